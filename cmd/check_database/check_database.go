@@ -66,6 +66,8 @@ func main() {
 		checkMongoDB(ctx, db)
 	case database.DatabaseTypeSQLite:
 		checkSQLite(ctx, db)
+	case database.DatabaseTypePostgres:
+		checkPostgres(ctx, db)
 	}
 
 	fmt.Printf("\n✅ Database connection check completed!\n")
@@ -96,3 +98,20 @@ func checkSQLite(ctx context.Context, db database.Database) {
 	}
 }
 
+func checkPostgres(ctx context.Context, db database.Database) {
+	// PostgreSQL 特定檢查
+	fmt.Printf("\n📚 PostgreSQL-specific checks:\n")
+	fmt.Printf("   Database type: PostgreSQL\n")
+
+	// 檢查資料表
+	collections := []string{"users", "channels", "programs", "counters", "migrations"}
+	for _, collName := range collections {
+		coll := db.Collection(collName)
+		count, err := coll.CountDocuments(ctx, database.Filter{})
+		if err != nil {
+			fmt.Printf("   ⚠️  %s: error checking (%v)\n", collName, err)
+		} else {
+			fmt.Printf("   ✅ %s: %d records\n", collName, count)
+		}
+	}
+}
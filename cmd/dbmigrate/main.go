@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/higgstv/higgstv-go/internal/config"
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/migration"
+)
+
+const usage = `用法: dbmigrate [選項] <指令>
+
+指令:
+  status       列出所有遷移與執行狀態
+  up [N]       依序執行未執行的遷移（省略 N 時全部執行）
+  down N       還原最後 N 個已執行的遷移
+  create NAME  在 -dir 中為 SQLite 與 PostgreSQL 建立新遷移的 up、down SQL 檔案
+  unlock       釋放遷移鎖（遷移程序異常中止、遷移鎖未釋放時使用）
+
+資料庫連線使用伺服器的配置（config.yaml 或 HIGGSTV_ 環境變數）。
+
+選項:
+`
+
+func main() {
+	dir := flag.String("dir", "internal/migration/sql", "create 建立 SQL 檔案的目錄")
+	timeout := flag.Duration("timeout", 10*time.Minute, "執行逾時")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// create 只建立檔案，不需要連線資料庫
+	if args[0] == "create" {
+		if len(args) != 2 {
+			fmt.Println("❌ 用法: dbmigrate create NAME")
+			os.Exit(2)
+		}
+		files, err := migration.Create(*dir, args[1])
+		if err != nil {
+			fmt.Printf("❌ 建立遷移失敗: %v\n", err)
+			os.Exit(1)
+		}
+		for _, file := range files {
+			fmt.Printf("✅ %s\n", file)
+		}
+		fmt.Println("ℹ️  MongoDB 或資料遷移請在 internal/migration/migration.go 的 migrations 中以相同 ID 加上 Up、Down")
+		return
+	}
+
+	// 載入配置
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Printf("❌ 載入配置失敗: %v\n", err)
+		os.Exit(1)
+	}
+
+	dbType, err := database.ParseDatabaseType(cfg.Database.Type)
+	if err != nil {
+		fmt.Printf("❌ 無效的資料庫類型: %v\n", err)
+		os.Exit(1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	db, err := database.NewDatabase(ctx, database.DatabaseConfig{
+		Type:     dbType,
+		URI:      cfg.Database.URI,
+		Database: cfg.Database.Database,
+	})
+	if err != nil {
+		fmt.Printf("❌ 資料庫連線失敗: %v\n", err)
+		os.Exit(1)
+	}
+	defer func() {
+		_ = db.Close(context.Background())
+	}()
+
+	if err := run(ctx, migration.NewMigrator(db), args); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		_ = db.Close(context.Background())
+		os.Exit(1)
+	}
+}
+
+// run 執行指令
+func run(ctx context.Context, migrator *migration.Migrator, args []string) error {
+	switch args[0] {
+	case "status":
+		return printStatus(ctx, migrator)
+	case "up":
+		n := 0
+		if len(args) > 1 {
+			var err error
+			if n, err = parseCount(args[1]); err != nil {
+				return err
+			}
+		}
+		done, err := migrator.Up(ctx, n)
+		for _, m := range done {
+			fmt.Printf("✅ up   %s  %s\n", m.ID, m.Description)
+		}
+		if err != nil {
+			return err
+		}
+		if len(done) == 0 {
+			fmt.Println("ℹ️  沒有未執行的遷移")
+		}
+		return nil
+	case "down":
+		if len(args) != 2 {
+			return fmt.Errorf("用法: dbmigrate down N")
+		}
+		n, err := parseCount(args[1])
+		if err != nil {
+			return err
+		}
+		done, err := migrator.Down(ctx, n)
+		for _, m := range done {
+			fmt.Printf("✅ down %s  %s\n", m.ID, m.Description)
+		}
+		if err != nil {
+			return err
+		}
+		if len(done) == 0 {
+			fmt.Println("ℹ️  沒有已執行的遷移")
+		}
+		return nil
+	case "unlock":
+		if err := migrator.Unlock(ctx); err != nil {
+			return err
+		}
+		fmt.Println("✅ 已釋放遷移鎖")
+		return nil
+	default:
+		return fmt.Errorf("未知的指令: %s（可用指令: status、up、down、create、unlock）", args[0])
+	}
+}
+
+// printStatus 列出所有遷移與執行狀態
+func printStatus(ctx context.Context, migrator *migration.Migrator) error {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, status := range statuses {
+		executedAt := "-"
+		if status.ExecutedAt != nil {
+			executedAt = status.ExecutedAt.Local().Format("2006-01-02 15:04:05")
+		}
+		fmt.Printf("%-8s %-19s %s  %s\n", status.State, executedAt, status.ID, status.Description)
+	}
+
+	lock, err := migrator.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	if lock != nil {
+		fmt.Printf("⚠️  遷移鎖由 %s 於 %s 取得（程序已中止時可執行 dbmigrate unlock）\n",
+			lock.Owner, lock.LockedAt.Local().Format("2006-01-02 15:04:05"))
+	}
+	return nil
+}
+
+// parseCount 解析遷移數量
+func parseCount(value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("遷移數量必須是正整數: %s", value)
+	}
+	return n, nil
+}
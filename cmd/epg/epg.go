@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/higgstv/higgstv-go/internal/config"
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/epg"
+	"github.com/higgstv/higgstv-go/internal/repository"
+	"github.com/higgstv/higgstv-go/internal/service"
+)
+
+// maxSlotsPerChannel 每個頻道輸出的時段上限
+const maxSlotsPerChannel = 5000
+
+func main() {
+	outDir := flag.String("out", "./epg", "輸出目錄")
+	hours := flag.Int("hours", 24, "節目表涵蓋的小時數（自現在起）")
+	perChannel := flag.Bool("per-channel", true, "是否同時輸出每個頻道各自的檔案（channels/<id>.xml / .json）")
+	user := flag.String("user", "", "只輸出特定使用者的頻道（username）")
+	q := flag.String("q", "", "以名稱模糊搜尋")
+	hasContents := flag.Bool("has-contents", true, "只輸出有節目的頻道")
+	ignoreTypes := flag.String("ignore-types", "", "要排除的頻道類型（以逗號分隔）")
+	flag.Parse()
+
+	if *hours <= 0 {
+		fmt.Println("❌ -hours 必須大於 0")
+		os.Exit(1)
+	}
+
+	// 載入配置
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Printf("❌ 載入配置失敗: %v\n", err)
+		os.Exit(1)
+	}
+
+	dbType, err := database.ParseDatabaseType(cfg.Database.Type)
+	if err != nil {
+		fmt.Printf("❌ 無效的資料庫類型: %v\n", err)
+		os.Exit(1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	db, err := database.NewDatabase(ctx, database.DatabaseConfig{
+		Type:     dbType,
+		URI:      cfg.Database.URI,
+		Database: cfg.Database.Database,
+	})
+	if err != nil {
+		fmt.Printf("❌ 資料庫連線失敗: %v\n", err)
+		os.Exit(1)
+	}
+	defer func() {
+		_ = db.Close(context.Background())
+	}()
+
+	query := service.ChannelQuery{
+		User:        *user,
+		Q:           *q,
+		HasContents: *hasContents,
+		Sort:        "name",
+	}
+	if *ignoreTypes != "" {
+		query.IgnoreTypes = strings.Split(*ignoreTypes, ",")
+	}
+
+	from := time.Now().UTC().Truncate(time.Minute)
+	to := from.Add(time.Duration(*hours) * time.Hour)
+
+	epgService := service.NewEPGService(repository.NewChannelRepository(db), repository.NewUserRepository(db))
+	guide, err := epgService.Guide(ctx, query, from, to, maxSlotsPerChannel)
+	if err != nil {
+		fmt.Printf("❌ 建立節目表失敗: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := writeGuide(*outDir, "guide", guide); err != nil {
+		fmt.Printf("❌ 寫入節目表失敗: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *perChannel {
+		channelDir := filepath.Join(*outDir, "channels")
+		for _, channel := range guide.Channels {
+			single := &epg.Guide{
+				Generated: guide.Generated,
+				From:      guide.From,
+				To:        guide.To,
+				Channels:  []epg.Channel{channel},
+			}
+			if err := writeGuide(channelDir, channel.ID, single); err != nil {
+				fmt.Printf("❌ 寫入頻道 %s 節目表失敗: %v\n", channel.ID, err)
+				os.Exit(1)
+			}
+		}
+	}
+
+	fmt.Printf("✅ 已輸出 %d 個頻道的節目表到 %s（%s ~ %s）\n",
+		len(guide.Channels), *outDir, from.Format(time.RFC3339), to.Format(time.RFC3339))
+}
+
+// writeGuide 輸出 <name>.xml（XMLTV）與 <name>.json
+func writeGuide(dir, name string, guide *epg.Guide) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	if err := writeFile(filepath.Join(dir, name+".xml"), guide, epg.WriteXMLTV); err != nil {
+		return err
+	}
+	return writeFile(filepath.Join(dir, name+".json"), guide, epg.WriteJSON)
+}
+
+// writeFile 先寫入暫存檔再改名，避免靜態伺服器讀到寫到一半的檔案
+func writeFile(path string, guide *epg.Guide, write func(io.Writer, *epg.Guide) error) error {
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if err := write(f, guide); err != nil {
+		_ = f.Close()
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -16,6 +16,7 @@ import (
 
 	"github.com/higgstv/higgstv-go/internal/config"
 	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/migration"
 	"github.com/higgstv/higgstv-go/internal/models"
 	"github.com/higgstv/higgstv-go/internal/repository"
 )
@@ -117,6 +118,12 @@ func main() {
 	defer func() {
 		_ = sqliteDB.Close(context.Background())
 	}()
+
+	// 建立資料表（與 dbmigrate up 相同）
+	if err := migration.RunMigrations(context.Background(), sqliteDB); err != nil {
+		fmt.Printf("❌ SQLite 資料表建立失敗: %v\n", err)
+		os.Exit(1)
+	}
 	
 	// 暫時禁用外鍵約束以允許遷移（遷移完成後會重新啟用）
 	db := sqliteDB.GetDB()
@@ -583,6 +590,11 @@ func migrateChannels(ctx context.Context, mongoDB *mongo.Database, sqliteDB data
 		originalContents := channel.Contents
 		channel.Contents = nil
 		
+		// 尚未執行頻道可見度遷移的 MongoDB 資料預設為公開
+		if channel.Visibility == "" {
+			channel.Visibility = models.ChannelVisibilityPublic
+		}
+		
 		if err := channelRepo.Create(ctx, &channel); err != nil {
 			if !isDuplicateError(err) {
 				stats.Errors = append(stats.Errors, fmt.Sprintf("頻道 %s: %v", channel.ID, err))
@@ -38,8 +38,14 @@ import (
 	"github.com/higgstv/higgstv-go/internal/api/middleware"
 	"github.com/higgstv/higgstv-go/internal/config"
 	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/jobs"
 	"github.com/higgstv/higgstv-go/internal/migration"
+	"github.com/higgstv/higgstv-go/internal/playlist"
+	"github.com/higgstv/higgstv-go/internal/repository"
+	"github.com/higgstv/higgstv-go/internal/service"
+	"github.com/higgstv/higgstv-go/pkg/availability"
 	"github.com/higgstv/higgstv-go/pkg/logger"
+	"github.com/higgstv/higgstv-go/pkg/metadata"
 	"github.com/higgstv/higgstv-go/pkg/metrics"
 	"github.com/higgstv/higgstv-go/pkg/session"
 
@@ -114,6 +120,28 @@ func main() {
 		zap.String("uri", cfg.Database.URI),
 	)
 
+	// 伺服器端 Session 儲存（可列出與撤銷登入裝置）
+	session.SetStore(repository.NewSessionStore(db))
+	// 個人 API Token 驗證（Authorization: Bearer）
+	session.SetTokenAuthenticator(service.NewAPITokenService(repository.NewAPITokenRepository(db), repository.NewUserRepository(db)))
+
+	// 資料庫遷移（預設由 cmd/dbmigrate 執行，設定 database.auto_migrate 時在啟動時執行）
+	if cfg.Database.AutoMigrate {
+		if err := migration.RunMigrationsWithTimeout(db); err != nil {
+			log.Fatalf("Failed to run migrations: %v", err)
+		}
+		logger.Logger.Info("Database migrations completed")
+	} else {
+		pending, err := migration.NewMigrator(db).Pending(ctx)
+		if err != nil {
+			log.Fatalf("Failed to check migrations: %v", err)
+		}
+		if len(pending) > 0 {
+			log.Fatalf("Database has %d pending migrations (first: %s); run `go run ./cmd/dbmigrate up` or set database.auto_migrate",
+				len(pending), pending[0].ID)
+		}
+	}
+
 	// 確保資料庫索引已建立
 	if err := database.EnsureIndexesWithTimeout(db); err != nil {
 		logger.Logger.Warn("Failed to ensure database indexes", zap.Error(err))
@@ -122,14 +150,52 @@ func main() {
 		logger.Logger.Info("Database indexes ensured")
 	}
 
-	// 執行資料庫遷移
-	if err := migration.RunMigrationsWithTimeout(db); err != nil {
-		logger.Logger.Warn("Failed to run migrations", zap.Error(err))
-		// 不中斷啟動，但記錄警告
-	} else {
-		logger.Logger.Info("Database migrations completed")
+	// 影片資訊查詢（新增節目時補齊名稱與時長，並在背景補齊既有節目）
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	if cfg.Metadata.Enabled {
+		youtubeAPIKey := cfg.Metadata.YouTubeAPIKey
+		if youtubeAPIKey == "" {
+			youtubeAPIKey = cfg.Import.YouTubeAPIKey
+		}
+		metadata.Init(metadata.Config{
+			BaseURL:       cfg.Metadata.BaseURL,
+			Timeout:       cfg.Metadata.Timeout,
+			CacheTTL:      cfg.Metadata.CacheTTL,
+			RatePerHost:   cfg.Metadata.RatePerHost,
+			YouTubeAPIKey: youtubeAPIKey,
+		})
+		go jobs.RunMetadataBackfill(jobsCtx, db, cfg.Metadata.BackfillInterval, cfg.Metadata.BackfillBatch)
+		logger.Logger.Info("Metadata enrichment enabled",
+			zap.Duration("backfill_interval", cfg.Metadata.BackfillInterval),
+		)
+	}
+
+	// 節目可播放狀態檢查
+	if cfg.Availability.Enabled {
+		checker := availability.NewChecker(availability.Config{
+			BaseURL:      cfg.Availability.BaseURL,
+			Timeout:      cfg.Availability.Timeout,
+			RatePerHost:  cfg.Availability.RatePerHost,
+			AllowPrivate: cfg.Availability.AllowPrivate,
+		})
+		go jobs.RunAvailabilityCheck(jobsCtx, db, checker, cfg.Availability.Interval, cfg.Availability.RecheckAfter, cfg.Availability.Batch)
+		logger.Logger.Info("Availability check enabled",
+			zap.Duration("interval", cfg.Availability.Interval),
+		)
 	}
 
+	// 永久刪除垃圾桶中過期的頻道
+	go jobs.RunTrashPurge(jobsCtx, db, cfg.Trash.PurgeInterval, cfg.Trash.Retention, cfg.Trash.PurgeBatch)
+
+	// 線上播放清單匯入
+	playlist.InitResolver(playlist.ResolverConfig{
+		BaseURL:  cfg.Import.BaseURL,
+		APIKey:   cfg.Import.YouTubeAPIKey,
+		Timeout:  cfg.Import.Timeout,
+		MaxItems: cfg.Import.MaxItems,
+	})
+
 	// 設定 Gin
 	if cfg.Server.Env == "production" {
 		gin.SetMode(gin.ReleaseMode)
@@ -190,6 +256,7 @@ func main() {
 	<-quit
 
 	fmt.Println("Shutting down server...")
+	stopJobs()
 
 	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
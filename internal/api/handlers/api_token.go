@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/higgstv/higgstv-go/internal/api/response"
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/repository"
+	"github.com/higgstv/higgstv-go/internal/service"
+	"github.com/higgstv/higgstv-go/pkg/session"
+)
+
+// CreateAPITokenRequest 建立 API Token 請求
+type CreateAPITokenRequest struct {
+	Name      string     `json:"name" example:"bookmarklet"`                // Token 名稱
+	Scopes    []string   `json:"scopes" example:"pickprog"`                 // 權限範圍（all 或 pickprog，未提供時為 all）
+	ExpiresAt *time.Time `json:"expires_at" example:"2026-12-31T00:00:00Z"` // 到期時間（選填）
+}
+
+// CreateAPIToken 建立個人 API Token
+// @Summary      建立 API Token
+// @Description  建立個人 API Token，可用 Authorization: Bearer 取代 Cookie 呼叫需登入的 API；Token 只會在建立時回傳一次（需登入）
+// @Tags         API Token
+// @Accept       json
+// @Produce      json
+// @Security     ApiAuth
+// @Param        request body CreateAPITokenRequest true "建立 API Token 請求"
+// @Success      200 {object} map[string]interface{} "成功回應（含 token 與 Token 資訊）"
+// @Failure      200 {object} map[string]interface{} "參數錯誤" example({"state":1,"code":0})
+// @Router       /apis/tokens [post]
+func CreateAPIToken(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req CreateAPITokenRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.Error(c, response.ErrorRequiredField)
+			return
+		}
+		scopes := req.Scopes
+		if len(scopes) == 0 {
+			scopes = []string{session.ScopeAll}
+		}
+
+		tokenService := service.NewAPITokenService(repository.NewAPITokenRepository(db), repository.NewUserRepository(db))
+		apiToken, token, err := tokenService.CreateToken(c.Request.Context(), session.GetUserID(c), req.Name, scopes, req.ExpiresAt)
+		if err != nil {
+			switch err.Error() {
+			case "invalid name", "invalid scope", "invalid expiry":
+				response.Error(c, response.ErrorRequiredField)
+			default:
+				response.Error(c, response.ErrorServerError)
+			}
+			return
+		}
+
+		response.Success(c, gin.H{"token": token, "api_token": apiToken})
+	}
+}
+
+// ListAPITokens 列出個人 API Token
+// @Summary      列出 API Token
+// @Description  列出目前使用者的 API Token（不含 Token 本身，只含開頭 prefix 與最後使用時間）（需登入）
+// @Tags         API Token
+// @Produce      json
+// @Security     ApiAuth
+// @Success      200 {object} map[string]interface{} "成功回應（含 API Token 列表）"
+// @Failure      200 {object} map[string]interface{} "未登入" example({"state":1,"code":1})
+// @Router       /apis/tokens [get]
+func ListAPITokens(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenService := service.NewAPITokenService(repository.NewAPITokenRepository(db), repository.NewUserRepository(db))
+		tokens, err := tokenService.ListTokens(c.Request.Context(), session.GetUserID(c))
+		if err != nil {
+			response.Error(c, response.ErrorServerError)
+			return
+		}
+
+		response.Success(c, gin.H{"api_tokens": tokens})
+	}
+}
+
+// RevokeAPIToken 撤銷個人 API Token
+// @Summary      撤銷 API Token
+// @Description  撤銷目前使用者的 API Token，撤銷後立即失效（需登入）
+// @Tags         API Token
+// @Produce      json
+// @Security     ApiAuth
+// @Param        id path string true "API Token ID"
+// @Success      200 {object} map[string]interface{} "成功回應" example({"state":0})
+// @Failure      200 {object} map[string]interface{} "API Token 不存在" example({"state":1,"code":0})
+// @Router       /apis/tokens/{id}/revoke [post]
+func RevokeAPIToken(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenService := service.NewAPITokenService(repository.NewAPITokenRepository(db), repository.NewUserRepository(db))
+		if err := tokenService.RevokeToken(c.Request.Context(), session.GetUserID(c), c.Param("id")); err != nil {
+			if err.Error() == "token not found" {
+				response.Error(c, response.ErrorRequiredField)
+				return
+			}
+			response.Error(c, response.ErrorServerError)
+			return
+		}
+
+		response.Success(c, nil)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/higgstv/higgstv-go/internal/api/response"
 	"github.com/higgstv/higgstv-go/internal/config"
 	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/models"
 	"github.com/higgstv/higgstv-go/internal/repository"
 	"github.com/higgstv/higgstv-go/internal/service"
 	"github.com/higgstv/higgstv-go/pkg/logger"
@@ -88,7 +89,8 @@ func SignOut() gin.HandlerFunc {
 
 // SignUpRequest 註冊請求
 type SignUpRequest struct {
-	InvitationCode string `json:"invitation_code" binding:"required" example:"sixpens"` // 邀請碼
+	InvitationCode string `json:"invitation_code" example:"sixpens"` // 邀請碼（由管理員建立，使用 share_token 註冊時可省略）
+	ShareToken     string `json:"share_token"` // 頻道分享邀請 Token（透過邀請連結註冊時提供，註冊後自動接受邀請）
 	Username       string `json:"username" binding:"required" example:"testuser"` // 使用者名稱
 	Email          string `json:"email" binding:"required,email" example:"test@example.com"` // 電子郵件
 	Password       string `json:"password" binding:"required" example:"password123"` // 密碼
@@ -96,7 +98,7 @@ type SignUpRequest struct {
 
 // SignUp 註冊
 // @Summary      註冊
-// @Description  新使用者註冊，需要有效的邀請碼
+// @Description  新使用者註冊，需要有效的邀請碼，或透過頻道分享邀請連結註冊（share_token，Email 需與邀請相同，註冊後自動接受邀請）
 // @Tags         認證
 // @Accept       json
 // @Produce      json
@@ -112,13 +114,30 @@ func SignUp(db database.Database) gin.HandlerFunc {
 			return
 		}
 
+		if req.InvitationCode == "" && req.ShareToken == "" {
+			response.Error(c, response.ErrorRequiredField)
+			return
+		}
+
 		userRepo := repository.NewUserRepository(db)
 		channelRepo := repository.NewChannelRepository(db)
-		authService := service.NewAuthService(userRepo)
-		channelService := service.NewChannelService(channelRepo, userRepo)
-
-		// 註冊使用者
-		user, err := authService.SignUp(c.Request.Context(), req.InvitationCode, req.Username, req.Email, req.Password)
+		shareRepo := repository.NewChannelShareRepository(db)
+		authService := service.NewAuthService(userRepo).
+			WithInvitations(repository.NewInvitationRepository(db)).
+			WithShares(shareRepo).
+			WithTransactions(db)
+		channelService := service.NewChannelService(channelRepo, userRepo).
+			WithHistory(repository.NewChannelRevisionRepository(db)).
+			WithTransactions(db)
+
+		// 註冊使用者（沒有邀請碼時以頻道分享邀請註冊）
+		var user *models.User
+		var err error
+		if req.InvitationCode != "" {
+			user, err = authService.SignUp(c.Request.Context(), req.InvitationCode, req.Username, req.Email, req.Password)
+		} else {
+			user, err = authService.SignUpWithShare(c.Request.Context(), req.ShareToken, req.Username, req.Email, req.Password)
+		}
 		if err != nil {
 			if err.Error() == "invalid invitation code" {
 				response.Error(c, response.ErrorAccessDenied)
@@ -162,6 +181,18 @@ func SignUp(db database.Database) gin.HandlerFunc {
 			return
 		}
 
+		// 透過邀請連結註冊時自動接受頻道分享邀請（失敗不影響註冊）
+		if req.ShareToken != "" {
+			shareService := service.NewShareService(shareRepo, channelRepo, userRepo).
+				WithHistory(repository.NewChannelRevisionRepository(db))
+			if _, err := shareService.Accept(c.Request.Context(), req.ShareToken, user.ID); err != nil && logger.Logger != nil {
+				logger.Logger.Warn("Failed to accept channel share at sign up",
+					zap.String("user_id", user.ID),
+					zap.Error(err),
+				)
+			}
+		}
+
 		// 設定 Session
 		if err := session.SetLoggedIn(c, user.ID, user.Username, user.Email, unclassifiedChannel.ID); err != nil {
 			response.Error(c, response.ErrorServerError)
@@ -180,7 +211,7 @@ type ChangePasswordRequest struct {
 
 // ChangePassword 變更密碼
 // @Summary      變更密碼
-// @Description  修改密碼（需登入）
+// @Description  修改密碼，成功後登出其他裝置（需登入）
 // @Tags         認證
 // @Accept       json
 // @Produce      json
@@ -217,6 +248,14 @@ func ChangePassword(db database.Database) gin.HandlerFunc {
 			return
 		}
 
+		// 變更密碼後登出其他裝置
+		if err := session.RevokeOthers(c); err != nil && logger.Logger != nil {
+			logger.Logger.Error("Failed to revoke sessions after password change",
+				zap.Error(err),
+				zap.String("username", username),
+			)
+		}
+
 		response.SuccessWithRet(c, true)
 	}
 }
@@ -291,7 +330,7 @@ type ResetPasswordRequest struct {
 
 // ResetPassword 重設密碼
 // @Summary      重設密碼
-// @Description  使用 access_key 重設密碼（不需要登入）
+// @Description  使用 access_key 重設密碼，成功後登出所有裝置（不需要登入）
 // @Tags         認證
 // @Accept       json
 // @Produce      json
@@ -317,6 +356,16 @@ func ResetPassword(db database.Database) gin.HandlerFunc {
 			return
 		}
 
+		// 重設密碼後登出所有裝置
+		if user, err := userRepo.FindByEmail(c.Request.Context(), req.Email); err == nil && user != nil {
+			if err := session.RevokeUser(c.Request.Context(), user.ID); err != nil && logger.Logger != nil {
+				logger.Logger.Error("Failed to revoke sessions after password reset",
+					zap.Error(err),
+					zap.String("user_id", user.ID),
+				)
+			}
+		}
+
 		response.SuccessWithRet(c, true)
 	}
 }
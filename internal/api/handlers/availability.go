@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/higgstv/higgstv-go/internal/api/response"
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/models"
+	"github.com/higgstv/higgstv-go/internal/repository"
+	"github.com/higgstv/higgstv-go/internal/service"
+	"github.com/higgstv/higgstv-go/pkg/session"
+)
+
+// GetChannelAvailability 取得頻道節目可播放狀態報表
+// @Summary      取得頻道節目可播放狀態報表
+// @Description  列出頻道各節目的可播放狀態（available、unavailable、unknown，尚未檢查時為空）與最後檢查時間（需登入，需有 write 權限）
+// @Tags         頻道
+// @Produce      json
+// @Security     ApiAuth
+// @Param        id path string true "頻道 ID"
+// @Param        availability query string false "只列出指定狀態的節目：available、unavailable 或 unknown"
+// @Success      200 {object} map[string]interface{} "成功回應（含各狀態的節目數與節目列表）"
+// @Failure      200 {object} map[string]interface{} "參數錯誤" example({"state":1,"code":0})
+// @Failure      200 {object} map[string]interface{} "權限不足或頻道不存在" example({"state":1,"code":2})
+// @Router       /apis/channel/{id}/availability [get]
+func GetChannelAvailability(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		channelID := c.Param("id")
+		if channelID == "" {
+			response.Error(c, response.ErrorRequiredField)
+			return
+		}
+
+		filter := models.Availability(c.Query("availability"))
+		switch filter {
+		case "", models.AvailabilityAvailable, models.AvailabilityUnavailable, models.AvailabilityUnknown:
+		default:
+			response.Error(c, response.ErrorRequiredField)
+			return
+		}
+
+		userID := session.GetUserID(c)
+		if userID == "" {
+			response.Error(c, response.ErrorRequireLogin)
+			return
+		}
+
+		channelRepo := repository.NewChannelRepository(db)
+		programRepo := repository.NewProgramRepository(db)
+
+		// 檢查權限
+		channelService := service.NewChannelService(channelRepo, nil)
+		canWrite, err := channelService.CanWrite(c.Request.Context(), channelID, userID)
+		if err != nil {
+			response.Error(c, response.ErrorServerError)
+			return
+		}
+		if !canWrite {
+			response.Error(c, response.ErrorAccessDenied)
+			return
+		}
+
+		availabilityService := service.NewAvailabilityService(channelRepo, programRepo, nil)
+		report, err := availabilityService.Report(c.Request.Context(), channelID, filter)
+		if err != nil {
+			if err.Error() == "channel not found" {
+				response.Error(c, response.ErrorAccessDenied)
+				return
+			}
+			response.Error(c, response.ErrorServerError)
+			return
+		}
+
+		response.Success(c, gin.H{"report": report})
+	}
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -58,10 +59,17 @@ func AddChannel(db database.Database) gin.HandlerFunc {
 
 		channelRepo := repository.NewChannelRepository(db)
 		userRepo := repository.NewUserRepository(db)
-		channelService := service.NewChannelService(channelRepo, userRepo)
+		channelService := service.NewChannelService(channelRepo, userRepo).
+			WithHistory(repository.NewChannelRevisionRepository(db)).
+			WithTags(repository.NewTagRepository(db)).
+			WithTransactions(db)
 
 		channel, err := channelService.AddChannel(c.Request.Context(), userID, username, req.Name, req.Tags)
 		if err != nil {
+			if err.Error() == "invalid tags" {
+				response.Error(c, response.ErrorRequiredField)
+				return
+			}
 			response.Error(c, response.ErrorServerError)
 			return
 		}
@@ -122,13 +130,14 @@ func GetOwnChannels(db database.Database) gin.HandlerFunc {
 
 // GetChannels 取得頻道列表（支援過濾）
 // @Summary      取得頻道列表
-// @Description  取得公開頻道列表，支援過濾和排序
+// @Description  取得公開頻道列表（不含 unlisted 與 private 頻道），支援過濾和排序
 // @Tags         頻道
 // @Produce      json
 // @Param        user query string false "只列出特定使用者的頻道（username）"
 // @Param        q query string false "以名稱模糊搜尋"
 // @Param        has_contents query string false "是否只顯示有節目的頻道（0/1）"
 // @Param        ignore_types query []string false "要排除的頻道類型陣列"
+// @Param        tag query string false "標籤 slug（含下層標籤）"
 // @Param        sort query string false "排序欄位（例如：last_modified, name）"
 // @Param        desc query string false "是否遞減排序（0/1）"
 // @Param        start query int false "分頁起始 index"
@@ -137,85 +146,18 @@ func GetOwnChannels(db database.Database) gin.HandlerFunc {
 // @Router       /apis/getchannels [get]
 func GetChannels(db database.Database) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		filter := database.Filter{}
-
-		// 處理 user 參數（透過 username 查詢使用者 ID）
-		if username := c.Query("user"); username != "" {
-			userRepo := repository.NewUserRepository(db)
-			user, err := userRepo.FindByUsername(c.Request.Context(), username)
-			if err == nil && user != nil {
-				filter["owners"] = user.ID
-			} else {
-				// 如果使用者不存在，回傳空結果
-				response.Success(c, gin.H{"channels": []models.Channel{}})
-				return
-			}
-		}
-
-		// 處理關鍵字搜尋（q 參數，文件規範使用 q 而非 name）
-		if q := c.Query("q"); q != "" {
-			filter["name"] = database.Filter{"$regex": q, "$options": "i"}
-		} else if name := c.Query("name"); name != "" {
-			// 向後相容：也支援 name 參數
-			filter["name"] = database.Filter{"$regex": name, "$options": "i"}
-		}
-
-		// 處理 has_contents 參數（是否只顯示有節目的頻道）
-		if hasContentsStr := c.Query("has_contents"); hasContentsStr != "" {
-			if hasContentsStr == "1" {
-				filter["contents.0"] = database.Filter{"$exists": true}
-			}
-		}
-
-		// 處理 ignore_types 參數（要排除的頻道類型）
-		ignoreTypes := c.QueryArray("ignore_types")
-		if len(ignoreTypes) > 0 {
-			filter["type"] = database.Filter{"$nin": ignoreTypes}
-		}
-
-		// 排序
-		sortField := c.DefaultQuery("sort", "last_modified")
-		descStr := c.DefaultQuery("desc", "0")
-		order := 1
-		if descStr == "1" {
-			order = -1
-		}
-
-		var sort database.Sort
-		if sortField == "name" {
-			sort = database.Sort{{Field: "name", Order: order}}
-		} else {
-			sort = database.Sort{{Field: "last_modified", Order: order}}
-		}
-
-		// 分頁（支援 start 和 skip，start 優先）
-		limit := int64(0)
-		skip := int64(0)
-		if limitStr := c.Query("limit"); limitStr != "" {
-			if l, err := strconv.ParseInt(limitStr, 10, 64); err == nil {
-				limit = l
-			}
-		}
-		// 優先使用 start 參數（文件規範）
-		if startStr := c.Query("start"); startStr != "" {
-			if s, err := strconv.ParseInt(startStr, 10, 64); err == nil {
-				skip = s
-			}
-		} else if skipStr := c.Query("skip"); skipStr != "" {
-			// 向後相容：也支援 skip 參數
-			if s, err := strconv.ParseInt(skipStr, 10, 64); err == nil {
-				skip = s
-			}
-		}
+		query := channelQueryFromRequest(c)
 
 		channelRepo := repository.NewChannelRepository(db)
-		channels, err := channelRepo.ListChannels(c.Request.Context(), filter, sort, limit, skip)
+		userRepo := repository.NewUserRepository(db)
+		channelService := service.NewChannelService(channelRepo, userRepo).WithTags(repository.NewTagRepository(db))
+		channels, err := channelService.QueryChannels(c.Request.Context(), query)
 		if err != nil {
 			// 記錄錯誤以便除錯
 			if logger.Logger != nil {
 				logger.Logger.Error("Failed to list channels",
 					zap.Error(err),
-					zap.Any("filter", filter),
+					zap.Any("query", query),
 				)
 			}
 			response.Error(c, response.ErrorServerError)
@@ -226,9 +168,47 @@ func GetChannels(db database.Database) gin.HandlerFunc {
 	}
 }
 
+// channelQueryFromRequest 解析 GetChannels 的過濾、排序與分頁參數
+func channelQueryFromRequest(c *gin.Context) service.ChannelQuery {
+	query := service.ChannelQuery{
+		User: c.Query("user"),
+		// 文件規範使用 q 而非 name
+		Q:           c.Query("q"),
+		HasContents: c.Query("has_contents") == "1",
+		IgnoreTypes: c.QueryArray("ignore_types"),
+		Tag:         c.Query("tag"),
+		Sort:        c.DefaultQuery("sort", "last_modified"),
+		Desc:        c.DefaultQuery("desc", "0") == "1",
+	}
+	if query.Q == "" {
+		// 向後相容：也支援 name 參數
+		query.Q = c.Query("name")
+	}
+
+	// 分頁（支援 start 和 skip，start 優先）
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.ParseInt(limitStr, 10, 64); err == nil {
+			query.Limit = l
+		}
+	}
+	// 優先使用 start 參數（文件規範）
+	if startStr := c.Query("start"); startStr != "" {
+		if s, err := strconv.ParseInt(startStr, 10, 64); err == nil {
+			query.Skip = s
+		}
+	} else if skipStr := c.Query("skip"); skipStr != "" {
+		// 向後相容：也支援 skip 參數
+		if s, err := strconv.ParseInt(skipStr, 10, 64); err == nil {
+			query.Skip = s
+		}
+	}
+
+	return query
+}
+
 // GetChannel 取得單一頻道
 // @Summary      取得單一頻道
-// @Description  根據頻道 ID 取得頻道詳細資訊
+// @Description  根據頻道 ID 取得頻道詳細資訊（公開與不公開列出的頻道所有人皆可讀取，私人頻道需登入且具有 read 權限）
 // @Tags         頻道
 // @Produce      json
 // @Param        id path string true "頻道 ID"
@@ -249,18 +229,24 @@ func GetChannel(db database.Database) gin.HandlerFunc {
 			response.Error(c, response.ErrorServerError)
 			return
 		}
-		if channel == nil {
+		if channel == nil || !channel.Readable(session.GetUserID(c)) {
 			response.Error(c, response.ErrorAccessDenied)
 			return
 		}
 
+		// 頻道設定隱藏無法播放的節目
+		if channel.HideUnavailable {
+			channel.HideUnavailableContents()
+		}
+
+		c.Header("ETag", revisionETag(channel.Revision))
 		response.Success(c, gin.H{"channel": channel})
 	}
 }
 
 // GetChannelInfo 取得頻道資訊（含擁有者資訊）
 // @Summary      取得頻道資訊（含擁有者）
-// @Description  取得頻道詳細資訊，包含擁有者基本資訊
+// @Description  取得頻道詳細資訊，包含擁有者基本資訊（私人頻道需具有 read 權限）
 // @Tags         頻道
 // @Produce      json
 // @Param        id path string true "頻道 ID"
@@ -283,7 +269,7 @@ func GetChannelInfo(db database.Database) gin.HandlerFunc {
 			response.Error(c, response.ErrorServerError)
 			return
 		}
-		if channel == nil {
+		if channel == nil || !channel.Readable(session.GetUserID(c)) {
 			response.Error(c, response.ErrorAccessDenied)
 			return
 		}
@@ -300,6 +286,7 @@ func GetChannelInfo(db database.Database) gin.HandlerFunc {
 			OwnersInfo: ownersInfo,
 		}
 
+		c.Header("ETag", revisionETag(channel.Revision))
 		response.Success(c, gin.H{"channel": result})
 	}
 }
@@ -310,19 +297,24 @@ type SaveChannelRequest struct {
 	Name  string `json:"name" binding:"required"`
 	Desc  string `json:"desc"`
 	Tags  []int  `json:"tags"`
+	HideUnavailable *bool `json:"hide_unavailable"` // 取得頻道時隱藏無法播放的節目（選填）
+	Visibility string `json:"visibility" example:"unlisted"` // 頻道可見度：public、unlisted 或 private（選填）
+	Revision *int64 `json:"revision"` // 預期的頻道修訂版本（選填，也可使用 If-Match 標頭）
 }
 
 // SaveChannel 儲存頻道
 // @Summary      儲存頻道
-// @Description  更新頻道名稱、描述和標籤（需登入，需有權限）
+// @Description  更新頻道名稱、描述、標籤，以及是否隱藏無法播放的節目、頻道可見度（public 會列在頻道列表、unlisted 只能透過 ID 讀取、private 需具有 read 權限）（需登入，需為頻道管理員）
 // @Tags         頻道
 // @Accept       json
 // @Produce      json
 // @Security     ApiAuth
+// @Param        If-Match header string false "預期的頻道修訂版本（GetChannel 回傳的 ETag）"
 // @Param        request body SaveChannelRequest true "儲存頻道請求"
 // @Success      200 {object} map[string]interface{} "成功回應" example({"state":0})
 // @Failure      200 {object} map[string]interface{} "缺少必填欄位" example({"state":1,"code":0})
 // @Failure      200 {object} map[string]interface{} "權限不足" example({"state":1,"code":2})
+// @Failure      200 {object} map[string]interface{} "頻道已被其他人修改" example({"state":1,"code":3})
 // @Router       /apis/savechannel [post]
 func SaveChannel(db database.Database) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -341,6 +333,12 @@ func SaveChannel(db database.Database) gin.HandlerFunc {
 			req.Tags = []int{}
 		}
 
+		visibility := models.ChannelVisibility(req.Visibility)
+		if visibility != "" && !visibility.Valid() {
+			response.Error(c, response.ErrorRequiredField)
+			return
+		}
+
 		userID := session.GetUserID(c)
 		if userID == "" {
 			response.Error(c, response.ErrorRequireLogin)
@@ -348,7 +346,7 @@ func SaveChannel(db database.Database) gin.HandlerFunc {
 		}
 
 		channelRepo := repository.NewChannelRepository(db)
-		channelService := service.NewChannelService(channelRepo, nil)
+		channelService := service.NewChannelService(channelRepo, nil).WithHistory(repository.NewChannelRevisionRepository(db))
 
 		// 檢查權限
 		isAdmin, err := channelService.IsAdmin(c.Request.Context(), req.ID, userID)
@@ -360,13 +358,29 @@ func SaveChannel(db database.Database) gin.HandlerFunc {
 			response.Error(c, response.ErrorAccessDenied)
 			return
 		}
+		revision, ok := requestRevision(c, req.Revision)
+		if !ok {
+			return
+		}
 
-		err = channelService.UpdateChannel(c.Request.Context(), req.ID, req.Name, req.Desc, req.Tags)
+		err = withRevision(c.Request.Context(), db, req.ID, revision, func(ctx context.Context) error {
+			return channelService.SaveChannel(ctx, req.ID, service.ChannelUpdate{
+				Name:            req.Name,
+				Desc:            req.Desc,
+				Tags:            req.Tags,
+				HideUnavailable: req.HideUnavailable,
+				Visibility:      visibility,
+			})
+		})
+		if revisionConflict(c, err) {
+			return
+		}
 		if err != nil {
 			response.Error(c, response.ErrorServerError)
 			return
 		}
 
+		setRevisionETag(c, db, req.ID)
 		response.Success(c, nil)
 	}
 }
@@ -412,7 +426,8 @@ func SetChannelOwner(db database.Database) gin.HandlerFunc {
 
 		channelRepo := repository.NewChannelRepository(db)
 		userRepo := repository.NewUserRepository(db)
-		channelService := service.NewChannelService(channelRepo, userRepo)
+		channelService := service.NewChannelService(channelRepo, userRepo).
+			WithHistory(repository.NewChannelRevisionRepository(db))
 
 		// 檢查權限
 		isAdmin, err := channelService.IsAdmin(c.Request.Context(), req.ID, userID)
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/higgstv/higgstv-go/internal/api/response"
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/repository"
+	"github.com/higgstv/higgstv-go/internal/service"
+	"github.com/higgstv/higgstv-go/pkg/session"
+)
+
+// ForkChannelRequest 複製頻道請求
+type ForkChannelRequest struct {
+	Name string `json:"name"` // 新頻道名稱（可選，預設沿用來源頻道名稱）
+}
+
+// ForkChannel 複製頻道
+// @Summary      複製頻道
+// @Description  將可讀取的頻道（含頻道資訊、標籤、節目與節目順序）複製為自己擁有的新頻道，節目會取得新的節目 ID；新頻道記錄來源 forked_from，來源頻道的 fork_count 遞增（需登入）
+// @Tags         頻道
+// @Accept       json
+// @Produce      json
+// @Security     ApiAuth
+// @Param        id path string true "來源頻道 ID"
+// @Param        request body ForkChannelRequest false "複製頻道請求"
+// @Success      200 {object} map[string]interface{} "成功回應（含新頻道）"
+// @Failure      200 {object} map[string]interface{} "未登入" example({"state":1,"code":1})
+// @Failure      200 {object} map[string]interface{} "權限不足或頻道不存在" example({"state":1,"code":2})
+// @Router       /apis/channel/{id}/fork [post]
+func ForkChannel(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req ForkChannelRequest
+		// 請求內容可省略
+		_ = c.ShouldBindJSON(&req)
+
+		userID := session.GetUserID(c)
+		username := session.GetUsername(c)
+		if userID == "" || username == "" {
+			response.Error(c, response.ErrorRequireLogin)
+			return
+		}
+
+		forkService := service.NewForkService(
+			repository.NewChannelRepository(db),
+			repository.NewProgramRepository(db),
+			repository.NewUserRepository(db),
+		).WithHistory(repository.NewChannelRevisionRepository(db)).
+			WithTransactions(db)
+
+		channel, err := forkService.ForkChannel(c.Request.Context(), c.Param("id"), userID, username, req.Name)
+		if err != nil {
+			if err.Error() == "channel not found" {
+				response.Error(c, response.ErrorAccessDenied)
+				return
+			}
+			response.Error(c, response.ErrorServerError)
+			return
+		}
+
+		response.Success(c, gin.H{"channel": channel})
+	}
+}
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/higgstv/higgstv-go/internal/api/response"
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/repository"
+	"github.com/higgstv/higgstv-go/internal/service"
+	"github.com/higgstv/higgstv-go/pkg/session"
+)
+
+// ChannelRevisionSummary 頻道歷史版本摘要（列表不含完整快照）
+type ChannelRevisionSummary struct {
+	ID           string    `json:"_id"`
+	Revision     int64     `json:"revision"`
+	Action       string    `json:"action"`
+	Created      time.Time `json:"created"`
+	Name         string    `json:"name"`
+	ProgramCount int       `json:"program_count"`
+}
+
+// RestoreChannelRevisionRequest 還原頻道歷史版本請求
+type RestoreChannelRevisionRequest struct {
+	Revision *int64 `json:"revision"` // 預期的頻道修訂版本（可選，亦可使用 If-Match 標頭）
+}
+
+// newHistoryService 建立頻道歷史版本服務
+func newHistoryService(db database.Database) *service.HistoryService {
+	return service.NewHistoryService(
+		repository.NewChannelRevisionRepository(db),
+		repository.NewChannelRepository(db),
+		repository.NewProgramRepository(db),
+	).WithTransactions(db)
+}
+
+// ListChannelRevisions 列出頻道歷史版本
+// @Summary      列出頻道歷史版本
+// @Description  列出頻道每次修改後記錄的歷史版本摘要，依時間由新到舊（需登入，需具有 read 權限）
+// @Tags         頻道
+// @Produce      json
+// @Security     ApiAuth
+// @Param        id path string true "頻道 ID"
+// @Param        limit query int false "限制筆數"
+// @Param        skip query int false "略過筆數"
+// @Success      200 {object} map[string]interface{} "成功回應（含歷史版本列表）"
+// @Failure      200 {object} map[string]interface{} "權限不足或頻道不存在" example({"state":1,"code":2})
+// @Router       /apis/channel/{id}/revisions [get]
+func ListChannelRevisions(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var limit, skip int64
+		if l, err := strconv.ParseInt(c.Query("limit"), 10, 64); err == nil && l > 0 {
+			limit = l
+		}
+		if s, err := strconv.ParseInt(c.Query("skip"), 10, 64); err == nil && s > 0 {
+			skip = s
+		}
+
+		revisions, err := newHistoryService(db).ListRevisions(c.Request.Context(), c.Param("id"), session.GetUserID(c), limit, skip)
+		if err != nil {
+			respondHistoryError(c, err)
+			return
+		}
+
+		summaries := make([]ChannelRevisionSummary, 0, len(revisions))
+		for _, revision := range revisions {
+			summaries = append(summaries, ChannelRevisionSummary{
+				ID:           revision.ID,
+				Revision:     revision.Revision,
+				Action:       revision.Action,
+				Created:      revision.Created,
+				Name:         revision.Snapshot.Name,
+				ProgramCount: len(revision.Snapshot.Contents),
+			})
+		}
+
+		response.Success(c, gin.H{"revisions": summaries})
+	}
+}
+
+// DiffChannelRevisions 比較兩個頻道歷史版本
+// @Summary      比較頻道歷史版本
+// @Description  比較兩個歷史版本的頻道欄位、新增/刪除/修改的節目與節目順序（需登入，需具有 read 權限）
+// @Tags         頻道
+// @Produce      json
+// @Security     ApiAuth
+// @Param        id path string true "頻道 ID"
+// @Param        from query string true "較舊的歷史版本 ID"
+// @Param        to query string true "較新的歷史版本 ID"
+// @Success      200 {object} map[string]interface{} "成功回應（含差異）"
+// @Failure      200 {object} map[string]interface{} "缺少必填欄位或歷史版本不存在" example({"state":1,"code":0})
+// @Failure      200 {object} map[string]interface{} "權限不足或頻道不存在" example({"state":1,"code":2})
+// @Router       /apis/channel/{id}/revisions/diff [get]
+func DiffChannelRevisions(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		from, to := c.Query("from"), c.Query("to")
+		if from == "" || to == "" {
+			response.Error(c, response.ErrorRequiredField)
+			return
+		}
+
+		diff, err := newHistoryService(db).DiffRevisions(c.Request.Context(), c.Param("id"), session.GetUserID(c), from, to)
+		if err != nil {
+			respondHistoryError(c, err)
+			return
+		}
+
+		response.Success(c, gin.H{"diff": diff})
+	}
+}
+
+// RestoreChannelRevision 還原頻道歷史版本
+// @Summary      還原頻道歷史版本
+// @Description  將頻道資訊（含封面與播出排程）、節目與節目順序還原為指定的歷史版本，節目保留原本的節目 ID，還原後記錄一個新的歷史版本（需登入，需為頻道管理員）
+// @Tags         頻道
+// @Accept       json
+// @Produce      json
+// @Security     ApiAuth
+// @Param        id path string true "頻道 ID"
+// @Param        revision_id path string true "歷史版本 ID"
+// @Param        If-Match header string false "預期的頻道修訂版本（GetChannel 回傳的 ETag）"
+// @Param        request body RestoreChannelRevisionRequest false "還原頻道歷史版本請求"
+// @Success      200 {object} map[string]interface{} "成功回應" example({"state":0})
+// @Failure      200 {object} map[string]interface{} "歷史版本不存在" example({"state":1,"code":0})
+// @Failure      200 {object} map[string]interface{} "權限不足或頻道不存在" example({"state":1,"code":2})
+// @Failure      200 {object} map[string]interface{} "頻道已被其他人修改" example({"state":1,"code":3})
+// @Router       /apis/channel/{id}/revisions/{revision_id}/restore [post]
+func RestoreChannelRevision(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req RestoreChannelRevisionRequest
+		// 請求內容可省略
+		_ = c.ShouldBindJSON(&req)
+
+		channelID := c.Param("id")
+		userID := session.GetUserID(c)
+
+		channelService := service.NewChannelService(repository.NewChannelRepository(db), nil)
+		isAdmin, err := channelService.IsAdmin(c.Request.Context(), channelID, userID)
+		if err != nil {
+			response.Error(c, response.ErrorServerError)
+			return
+		}
+		if !isAdmin {
+			response.Error(c, response.ErrorAccessDenied)
+			return
+		}
+		revision, ok := requestRevision(c, req.Revision)
+		if !ok {
+			return
+		}
+
+		err = withRevision(c.Request.Context(), db, channelID, revision, func(ctx context.Context) error {
+			_, err := newHistoryService(db).RestoreRevision(ctx, channelID, userID, c.Param("revision_id"))
+			return err
+		})
+		if revisionConflict(c, err) {
+			return
+		}
+		if err != nil {
+			respondHistoryError(c, err)
+			return
+		}
+
+		setRevisionETag(c, db, channelID)
+		response.Success(c, nil)
+	}
+}
+
+// respondHistoryError 回應頻道歷史版本操作的錯誤
+func respondHistoryError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "revision not found":
+		response.Error(c, response.ErrorRequiredField)
+	case "channel not found", "permission denied":
+		response.Error(c, response.ErrorAccessDenied)
+	default:
+		response.Error(c, response.ErrorServerError)
+	}
+}
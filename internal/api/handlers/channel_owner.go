@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/higgstv/higgstv-go/internal/api/response"
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/repository"
+	"github.com/higgstv/higgstv-go/internal/service"
+	"github.com/higgstv/higgstv-go/pkg/session"
+)
+
+// ChannelOwnerRequest 移除／移轉頻道擁有者請求
+type ChannelOwnerRequest struct {
+	Email  string `json:"email" example:"user@example.com"` // 使用者 Email（優先使用）
+	UserID string `json:"user_id"`                          // 使用者 ID
+}
+
+// RemoveChannelOwner 移除頻道擁有者
+// @Summary      移除頻道擁有者
+// @Description  移除頻道的共用擁有者，或指定自己以離開頻道（需登入，需為頻道擁有者）；不能移除最後一位擁有者
+// @Tags         頻道
+// @Accept       json
+// @Produce      json
+// @Security     ApiAuth
+// @Param        id path string true "頻道 ID"
+// @Param        request body ChannelOwnerRequest true "移除頻道擁有者請求"
+// @Success      200 {object} map[string]interface{} "成功回應" example({"state":0})
+// @Failure      200 {object} map[string]interface{} "參數錯誤、不是擁有者或最後一位擁有者" example({"state":1,"code":0})
+// @Failure      200 {object} map[string]interface{} "權限不足" example({"state":1,"code":2})
+// @Router       /apis/channel/{id}/owner/remove [post]
+func RemoveChannelOwner(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		channelID := c.Param("id")
+		var req ChannelOwnerRequest
+		if err := c.ShouldBindJSON(&req); err != nil || channelID == "" {
+			response.Error(c, response.ErrorRequiredField)
+			return
+		}
+
+		userRepo := repository.NewUserRepository(db)
+		targetID, ok := resolveTargetUser(c, userRepo, req.Email, req.UserID)
+		if !ok {
+			return
+		}
+
+		channelService := service.NewChannelService(repository.NewChannelRepository(db), userRepo).
+			WithHistory(repository.NewChannelRevisionRepository(db))
+		err := channelService.RemoveOwner(c.Request.Context(), channelID, session.GetUserID(c), targetID)
+		respondOwnerError(c, err)
+	}
+}
+
+// TransferChannelOwnership 移轉頻道擁有權
+// @Summary      移轉頻道擁有權
+// @Description  將自己的頻道擁有權移轉給其他使用者，移轉後自己不再是擁有者（需登入，需為頻道擁有者）；未分類頻道不能移轉
+// @Tags         頻道
+// @Accept       json
+// @Produce      json
+// @Security     ApiAuth
+// @Param        id path string true "頻道 ID"
+// @Param        request body ChannelOwnerRequest true "移轉頻道擁有權請求"
+// @Success      200 {object} map[string]interface{} "成功回應" example({"state":0})
+// @Failure      200 {object} map[string]interface{} "參數錯誤或使用者不存在" example({"state":1,"code":0})
+// @Failure      200 {object} map[string]interface{} "權限不足" example({"state":1,"code":2})
+// @Router       /apis/channel/{id}/owner/transfer [post]
+func TransferChannelOwnership(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		channelID := c.Param("id")
+		var req ChannelOwnerRequest
+		if err := c.ShouldBindJSON(&req); err != nil || channelID == "" {
+			response.Error(c, response.ErrorRequiredField)
+			return
+		}
+
+		userRepo := repository.NewUserRepository(db)
+		targetID, ok := resolveTargetUser(c, userRepo, req.Email, req.UserID)
+		if !ok {
+			return
+		}
+
+		channelService := service.NewChannelService(repository.NewChannelRepository(db), userRepo).
+			WithHistory(repository.NewChannelRevisionRepository(db))
+		err := channelService.TransferOwnership(c.Request.Context(), channelID, session.GetUserID(c), targetID)
+		respondOwnerError(c, err)
+	}
+}
+
+// resolveTargetUser 解析請求指定的使用者 ID（優先使用 email），失敗時已回應錯誤
+func resolveTargetUser(c *gin.Context, userRepo database.UserRepository, email, userID string) (string, bool) {
+	if email != "" {
+		user, err := userRepo.FindByEmail(c.Request.Context(), email)
+		if err != nil {
+			response.Error(c, response.ErrorServerError)
+			return "", false
+		}
+		if user == nil {
+			response.Error(c, response.ErrorRequiredField)
+			return "", false
+		}
+		return user.ID, true
+	}
+	if userID == "" {
+		response.Error(c, response.ErrorRequiredField)
+		return "", false
+	}
+	return userID, true
+}
+
+// respondOwnerError 回應移除或移轉擁有者的結果
+func respondOwnerError(c *gin.Context, err error) {
+	if err == nil {
+		response.Success(c, nil)
+		return
+	}
+	switch err.Error() {
+	case "not an owner", "last owner", "user not found", "invalid owner", "unclassified channel cannot be transferred":
+		response.Error(c, response.ErrorRequiredField)
+	case "channel not found", "permission denied":
+		response.Error(c, response.ErrorAccessDenied)
+	default:
+		response.Error(c, response.ErrorServerError)
+	}
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/higgstv/higgstv-go/internal/api/response"
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/repository"
+	"github.com/higgstv/higgstv-go/internal/service"
+	"github.com/higgstv/higgstv-go/pkg/session"
+)
+
+// ChannelPermissionRequest 授予／撤銷頻道權限請求
+type ChannelPermissionRequest struct {
+	Email      string `json:"email" example:"user@example.com"`              // 使用者 Email（優先使用）
+	UserID     string `json:"user_id"`                                       // 使用者 ID
+	Permission string `json:"permission" binding:"required" example:"write"` // 權限：admin、read 或 write
+}
+
+// GrantChannelPermission 授予頻道權限
+// @Summary      授予頻道權限
+// @Description  授予使用者頻道的 admin、read 或 write 權限（需登入，需為頻道管理員）；write 可新增、編輯與排序節目，read 可讀取私人頻道
+// @Tags         頻道
+// @Accept       json
+// @Produce      json
+// @Security     ApiAuth
+// @Param        id path string true "頻道 ID"
+// @Param        request body ChannelPermissionRequest true "授予頻道權限請求"
+// @Success      200 {object} map[string]interface{} "成功回應" example({"state":0})
+// @Failure      200 {object} map[string]interface{} "參數錯誤或使用者不存在" example({"state":1,"code":0})
+// @Failure      200 {object} map[string]interface{} "權限不足" example({"state":1,"code":2})
+// @Router       /apis/channel/{id}/permission/grant [post]
+func GrantChannelPermission(db database.Database) gin.HandlerFunc {
+	return setChannelPermission(db, true)
+}
+
+// RevokeChannelPermission 撤銷頻道權限
+// @Summary      撤銷頻道權限
+// @Description  撤銷使用者頻道的 admin、read 或 write 權限（需登入，需為頻道管理員）；擁有者的權限不能撤銷
+// @Tags         頻道
+// @Accept       json
+// @Produce      json
+// @Security     ApiAuth
+// @Param        id path string true "頻道 ID"
+// @Param        request body ChannelPermissionRequest true "撤銷頻道權限請求"
+// @Success      200 {object} map[string]interface{} "成功回應" example({"state":0})
+// @Failure      200 {object} map[string]interface{} "參數錯誤或使用者不存在" example({"state":1,"code":0})
+// @Failure      200 {object} map[string]interface{} "權限不足" example({"state":1,"code":2})
+// @Router       /apis/channel/{id}/permission/revoke [post]
+func RevokeChannelPermission(db database.Database) gin.HandlerFunc {
+	return setChannelPermission(db, false)
+}
+
+// setChannelPermission 授予或撤銷頻道權限
+func setChannelPermission(db database.Database, granted bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		channelID := c.Param("id")
+		var req ChannelPermissionRequest
+		if err := c.ShouldBindJSON(&req); err != nil || channelID == "" {
+			response.Error(c, response.ErrorRequiredField)
+			return
+		}
+
+		userID := session.GetUserID(c)
+		if userID == "" {
+			response.Error(c, response.ErrorRequireLogin)
+			return
+		}
+
+		channelRepo := repository.NewChannelRepository(db)
+		userRepo := repository.NewUserRepository(db)
+		channelService := service.NewChannelService(channelRepo, userRepo).
+			WithHistory(repository.NewChannelRevisionRepository(db))
+
+		// 檢查權限
+		isAdmin, err := channelService.IsAdmin(c.Request.Context(), channelID, userID)
+		if err != nil {
+			response.Error(c, response.ErrorServerError)
+			return
+		}
+		if !isAdmin {
+			response.Error(c, response.ErrorAccessDenied)
+			return
+		}
+
+		// 優先使用 email 參數
+		targetID, ok := resolveTargetUser(c, userRepo, req.Email, req.UserID)
+		if !ok {
+			return
+		}
+
+		if err := channelService.SetPermission(c.Request.Context(), channelID, targetID, req.Permission, granted); err != nil {
+			switch err.Error() {
+			case "invalid permission", "user not found", "owner permission cannot be changed":
+				response.Error(c, response.ErrorRequiredField)
+			case "channel not found":
+				response.Error(c, response.ErrorAccessDenied)
+			default:
+				response.Error(c, response.ErrorServerError)
+			}
+			return
+		}
+
+		response.Success(c, nil)
+	}
+}
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/higgstv/higgstv-go/internal/api/response"
+	"github.com/higgstv/higgstv-go/internal/config"
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/repository"
+	"github.com/higgstv/higgstv-go/internal/service"
+	"github.com/higgstv/higgstv-go/pkg/logger"
+	"github.com/higgstv/higgstv-go/pkg/mail"
+	"github.com/higgstv/higgstv-go/pkg/session"
+)
+
+// ShareChannelRequest 頻道分享邀請請求
+type ShareChannelRequest struct {
+	Email string `json:"email" binding:"required" example:"friend@example.com"` // 受邀者 Email（可以尚未註冊）
+	Role  string `json:"role" binding:"required" example:"write"`              // 角色：owner、admin、write 或 read
+}
+
+// ChannelShareTokenRequest 接受／拒絕頻道分享邀請請求
+type ChannelShareTokenRequest struct {
+	Token string `json:"token" binding:"required"` // 邀請信中的 Token
+}
+
+// newShareService 建立頻道分享邀請服務
+func newShareService(db database.Database) *service.ShareService {
+	return service.NewShareService(
+		repository.NewChannelShareRepository(db),
+		repository.NewChannelRepository(db),
+		repository.NewUserRepository(db),
+	).WithHistory(repository.NewChannelRevisionRepository(db))
+}
+
+// ShareChannel 以 Email 邀請使用者共用頻道
+// @Summary      邀請共用頻道
+// @Description  寄送頻道分享邀請信，受邀者接受後才會成為擁有者或取得權限；尚未註冊的受邀者可透過邀請連結註冊並自動接受（需登入；owner 角色需為頻道擁有者，其他角色需為頻道管理員）
+// @Tags         頻道
+// @Accept       json
+// @Produce      json
+// @Security     ApiAuth
+// @Param        id path string true "頻道 ID"
+// @Param        request body ShareChannelRequest true "頻道分享邀請請求"
+// @Success      200 {object} map[string]interface{} "成功回應（含邀請資訊）"
+// @Failure      200 {object} map[string]interface{} "參數錯誤" example({"state":1,"code":0})
+// @Failure      200 {object} map[string]interface{} "權限不足" example({"state":1,"code":2})
+// @Router       /apis/channel/{id}/share [post]
+func ShareChannel(db database.Database, appConfig interface{}) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req ShareChannelRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.Error(c, response.ErrorRequiredField)
+			return
+		}
+
+		share, channel, token, err := newShareService(db).Invite(c.Request.Context(), c.Param("id"), session.GetUserID(c), req.Email, req.Role)
+		if err != nil {
+			respondShareError(c, err)
+			return
+		}
+
+		// 發送郵件（非同步，不阻塞回應）
+		inviter := session.GetUsername(c)
+		go func() {
+			cfg, ok := appConfig.(*config.Config)
+			if !ok || cfg == nil {
+				return
+			}
+			mailService := mail.NewService(mail.Config{
+				SMTPHost:     cfg.Mail.SMTPHost,
+				SMTPPort:     cfg.Mail.SMTPPort,
+				SMTPUser:     cfg.Mail.SMTPUser,
+				SMTPPassword: cfg.Mail.SMTPPassword,
+				From:         cfg.Mail.From,
+			})
+			if err := mailService.SendChannelShare(share.Email, inviter, channel.Name, share.Role, token, cfg.Mail.BaseURL); err != nil && logger.Logger != nil {
+				logger.Logger.Warn("Failed to send channel share invitation",
+					zap.String("share_id", share.ID),
+					zap.Error(err),
+				)
+			}
+		}()
+
+		response.Success(c, gin.H{"share": share})
+	}
+}
+
+// ListChannelShares 列出頻道的分享邀請
+// @Summary      列出頻道分享邀請
+// @Description  列出頻道的分享邀請與狀態（pending、accepted、declined、revoked）（需登入，需為頻道管理員）
+// @Tags         頻道
+// @Produce      json
+// @Security     ApiAuth
+// @Param        id path string true "頻道 ID"
+// @Success      200 {object} map[string]interface{} "成功回應（含邀請列表）"
+// @Failure      200 {object} map[string]interface{} "權限不足" example({"state":1,"code":2})
+// @Router       /apis/channel/{id}/shares [get]
+func ListChannelShares(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		shares, err := newShareService(db).ListShares(c.Request.Context(), c.Param("id"), session.GetUserID(c))
+		if err != nil {
+			respondShareError(c, err)
+			return
+		}
+
+		response.Success(c, gin.H{"shares": shares})
+	}
+}
+
+// RevokeChannelShare 撤銷頻道分享邀請
+// @Summary      撤銷頻道分享邀請
+// @Description  撤銷尚未回應的頻道分享邀請（需登入，需為頻道管理員）
+// @Tags         頻道
+// @Produce      json
+// @Security     ApiAuth
+// @Param        id path string true "頻道 ID"
+// @Param        share_id path string true "邀請 ID"
+// @Success      200 {object} map[string]interface{} "成功回應" example({"state":0})
+// @Failure      200 {object} map[string]interface{} "邀請不存在或已回應" example({"state":1,"code":0})
+// @Failure      200 {object} map[string]interface{} "權限不足" example({"state":1,"code":2})
+// @Router       /apis/channel/{id}/shares/{share_id}/revoke [post]
+func RevokeChannelShare(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		err := newShareService(db).RevokeShare(c.Request.Context(), c.Param("id"), c.Param("share_id"), session.GetUserID(c))
+		if err != nil {
+			respondShareError(c, err)
+			return
+		}
+
+		response.Success(c, nil)
+	}
+}
+
+// AcceptChannelShare 接受頻道分享邀請
+// @Summary      接受頻道分享邀請
+// @Description  接受頻道分享邀請，依邀請的角色成為擁有者或取得頻道權限（需登入，Email 需與邀請相同）
+// @Tags         頻道
+// @Accept       json
+// @Produce      json
+// @Security     ApiAuth
+// @Param        request body ChannelShareTokenRequest true "接受頻道分享邀請請求"
+// @Success      200 {object} map[string]interface{} "成功回應（含邀請資訊）"
+// @Failure      200 {object} map[string]interface{} "邀請無效、過期或 Email 不符" example({"state":1,"code":0})
+// @Router       /apis/shares/accept [post]
+func AcceptChannelShare(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req ChannelShareTokenRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.Error(c, response.ErrorRequiredField)
+			return
+		}
+
+		share, err := newShareService(db).Accept(c.Request.Context(), req.Token, session.GetUserID(c))
+		if err != nil {
+			respondShareError(c, err)
+			return
+		}
+
+		response.Success(c, gin.H{"share": share})
+	}
+}
+
+// DeclineChannelShare 拒絕頻道分享邀請
+// @Summary      拒絕頻道分享邀請
+// @Description  拒絕頻道分享邀請（需登入，Email 需與邀請相同）
+// @Tags         頻道
+// @Accept       json
+// @Produce      json
+// @Security     ApiAuth
+// @Param        request body ChannelShareTokenRequest true "拒絕頻道分享邀請請求"
+// @Success      200 {object} map[string]interface{} "成功回應" example({"state":0})
+// @Failure      200 {object} map[string]interface{} "邀請無效、過期或 Email 不符" example({"state":1,"code":0})
+// @Router       /apis/shares/decline [post]
+func DeclineChannelShare(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req ChannelShareTokenRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.Error(c, response.ErrorRequiredField)
+			return
+		}
+
+		if err := newShareService(db).Decline(c.Request.Context(), req.Token, session.GetUserID(c)); err != nil {
+			respondShareError(c, err)
+			return
+		}
+
+		response.Success(c, nil)
+	}
+}
+
+// respondShareError 回應頻道分享邀請的錯誤
+func respondShareError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "invalid role", "invalid email", "invitation not found", "invitation email mismatch", "user not found":
+		response.Error(c, response.ErrorRequiredField)
+	case "channel not found", "permission denied":
+		response.Error(c, response.ErrorAccessDenied)
+	default:
+		response.Error(c, response.ErrorServerError)
+	}
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/higgstv/higgstv-go/internal/api/response"
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/epg"
+	"github.com/higgstv/higgstv-go/internal/repository"
+	"github.com/higgstv/higgstv-go/internal/service"
+	"github.com/higgstv/higgstv-go/pkg/logger"
+	"github.com/higgstv/higgstv-go/pkg/session"
+)
+
+const (
+	// epgFormatXMLTV XMLTV 格式
+	epgFormatXMLTV = "xmltv"
+	// epgFormatJSON JSON 格式
+	epgFormatJSON = "json"
+	// epgDefaultChannels 未指定 limit 時節目表包含的頻道數
+	epgDefaultChannels = 50
+	// epgMaxChannels 單次查詢節目表包含的頻道數上限
+	epgMaxChannels = 200
+)
+
+// GetEPG 取得多個頻道的電子節目表
+// @Summary      取得電子節目表
+// @Description  依 GetChannels 的過濾條件輸出 XMLTV 或 JSON 節目表（預設為現在起 24 小時，最長 7 天；預設 50 個頻道，最多 200 個）
+// @Tags         播出
+// @Produce      xml
+// @Produce      json
+// @Param        format query string false "輸出格式：xmltv（預設）或 json"
+// @Param        from query string false "開始時間（RFC3339 或 Unix 秒數，預設為現在）"
+// @Param        to query string false "結束時間（RFC3339 或 Unix 秒數，預設為 from 後 24 小時）"
+// @Param        user query string false "只列出特定使用者的頻道（username）"
+// @Param        q query string false "以名稱模糊搜尋"
+// @Param        has_contents query string false "是否只顯示有節目的頻道（0/1）"
+// @Param        ignore_types query []string false "要排除的頻道類型陣列"
+// @Param        tag query string false "標籤 slug（含下層標籤）"
+// @Param        sort query string false "排序欄位（例如：last_modified, name）"
+// @Param        desc query string false "是否遞減排序（0/1）"
+// @Param        start query int false "分頁起始 index"
+// @Param        limit query int false "頻道數（預設 50，最多 200）"
+// @Success      200 {object} map[string]interface{} "成功回應"
+// @Failure      200 {object} map[string]interface{} "參數錯誤" example({"state":1,"code":0})
+// @Router       /apis/epg [get]
+func GetEPG(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		format := c.DefaultQuery("format", epgFormatXMLTV)
+		if format != epgFormatXMLTV && format != epgFormatJSON {
+			response.Error(c, response.ErrorRequiredField)
+			return
+		}
+		from, to, ok := parseScheduleWindow(c)
+		if !ok {
+			response.Error(c, response.ErrorRequiredField)
+			return
+		}
+
+		channelRepo := repository.NewChannelRepository(db)
+		userRepo := repository.NewUserRepository(db)
+		epgService := service.NewEPGService(channelRepo, userRepo).WithTags(repository.NewTagRepository(db))
+		query := channelQueryFromRequest(c)
+		if query.Limit <= 0 {
+			query.Limit = epgDefaultChannels
+		}
+		if query.Limit > epgMaxChannels {
+			query.Limit = epgMaxChannels
+		}
+		guide, err := epgService.Guide(c.Request.Context(), query, from, to, maxScheduleSlots)
+		if err != nil {
+			if logger.Logger != nil {
+				logger.Logger.Error("Failed to build EPG", zap.Error(err))
+			}
+			response.Error(c, response.ErrorServerError)
+			return
+		}
+
+		renderEPG(c, format, guide)
+	}
+}
+
+// GetChannelEPG 取得單一頻道的電子節目表
+// @Summary      取得頻道電子節目表
+// @Description  輸出單一頻道的 XMLTV 或 JSON 節目表（預設為現在起 24 小時，最長 7 天）
+// @Tags         播出
+// @Produce      xml
+// @Produce      json
+// @Param        id path string true "頻道 ID"
+// @Param        format query string false "輸出格式：xmltv（預設）或 json"
+// @Param        from query string false "開始時間（RFC3339 或 Unix 秒數，預設為現在）"
+// @Param        to query string false "結束時間（RFC3339 或 Unix 秒數，預設為 from 後 24 小時）"
+// @Success      200 {object} map[string]interface{} "成功回應"
+// @Failure      200 {object} map[string]interface{} "參數錯誤" example({"state":1,"code":0})
+// @Failure      200 {object} map[string]interface{} "頻道不存在" example({"state":1,"code":2})
+// @Router       /apis/channel/{id}/epg [get]
+func GetChannelEPG(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		channelID := c.Param("id")
+		format := c.DefaultQuery("format", epgFormatXMLTV)
+		if channelID == "" || (format != epgFormatXMLTV && format != epgFormatJSON) {
+			response.Error(c, response.ErrorRequiredField)
+			return
+		}
+		from, to, ok := parseScheduleWindow(c)
+		if !ok {
+			response.Error(c, response.ErrorRequiredField)
+			return
+		}
+
+		channelRepo := repository.NewChannelRepository(db)
+		// 私人頻道需具有 read 權限
+		channelService := service.NewChannelService(channelRepo, nil)
+		canRead, err := channelService.CanRead(c.Request.Context(), channelID, session.GetUserID(c))
+		if err != nil {
+			response.Error(c, response.ErrorServerError)
+			return
+		}
+		if !canRead {
+			response.Error(c, response.ErrorAccessDenied)
+			return
+		}
+
+		epgService := service.NewEPGService(channelRepo, nil)
+		guide, err := epgService.ChannelGuide(c.Request.Context(), channelID, from, to, maxScheduleSlots)
+		if err != nil {
+			if err.Error() == "channel not found" {
+				response.Error(c, response.ErrorAccessDenied)
+				return
+			}
+			response.Error(c, response.ErrorServerError)
+			return
+		}
+
+		renderEPG(c, format, guide)
+	}
+}
+
+// renderEPG 依格式輸出節目表（XMLTV 直接輸出文件，JSON 使用統一回應格式）
+func renderEPG(c *gin.Context, format string, guide *epg.Guide) {
+	if format == epgFormatJSON {
+		response.Success(c, gin.H{"guide": guide})
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := epg.WriteXMLTV(&buf, guide); err != nil {
+		response.Error(c, response.ErrorServerError)
+		return
+	}
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", buf.Bytes())
+}
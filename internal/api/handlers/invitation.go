@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/higgstv/higgstv-go/internal/api/response"
+	"github.com/higgstv/higgstv-go/internal/config"
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/repository"
+	"github.com/higgstv/higgstv-go/internal/service"
+	"github.com/higgstv/higgstv-go/pkg/session"
+)
+
+// isSiteAdmin 檢查目前登入的使用者是否為網站管理員（admin.users）
+func isSiteAdmin(c *gin.Context, appConfig interface{}) bool {
+	cfg, ok := appConfig.(*config.Config)
+	if !ok || cfg == nil {
+		return false
+	}
+	username := session.GetUsername(c)
+	if username == "" {
+		return false
+	}
+	for _, admin := range cfg.Admin.Users {
+		if admin == username {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateInvitationRequest 建立邀請碼請求
+type CreateInvitationRequest struct {
+	Code      string     `json:"code" example:"spring-2026"`                // 自訂邀請碼（選填，未提供時自動產生）
+	Note      string     `json:"note" example:"給讀書會成員"`                     // 備註
+	MaxUses   *int       `json:"max_uses" example:"1"`                      // 可使用次數（未提供時為 1，0 表示不限次數）
+	ExpiresAt *time.Time `json:"expires_at" example:"2026-12-31T00:00:00Z"` // 到期時間（選填）
+}
+
+// CreateInvitation 建立邀請碼
+// @Summary      建立邀請碼
+// @Description  建立註冊邀請碼，可設定可使用次數與到期時間（需登入，需為網站管理員）
+// @Tags         邀請碼
+// @Accept       json
+// @Produce      json
+// @Security     ApiAuth
+// @Param        request body CreateInvitationRequest true "建立邀請碼請求"
+// @Success      200 {object} map[string]interface{} "成功回應（含邀請碼）"
+// @Failure      200 {object} map[string]interface{} "參數錯誤或邀請碼已存在" example({"state":1,"code":0})
+// @Failure      200 {object} map[string]interface{} "權限不足" example({"state":1,"code":2})
+// @Router       /apis/admin/invitations [post]
+func CreateInvitation(db database.Database, appConfig interface{}) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !isSiteAdmin(c, appConfig) {
+			response.Error(c, response.ErrorAccessDenied)
+			return
+		}
+
+		var req CreateInvitationRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.Error(c, response.ErrorRequiredField)
+			return
+		}
+		maxUses := 1
+		if req.MaxUses != nil {
+			maxUses = *req.MaxUses
+		}
+
+		invitationService := service.NewInvitationService(repository.NewInvitationRepository(db))
+		invitation, err := invitationService.CreateInvitation(c.Request.Context(), session.GetUserID(c), req.Code, req.Note, maxUses, req.ExpiresAt)
+		if err != nil {
+			switch err.Error() {
+			case "invalid max uses", "invalid expiry", "invalid code", "invitation code already exists":
+				response.Error(c, response.ErrorRequiredField)
+			default:
+				response.Error(c, response.ErrorServerError)
+			}
+			return
+		}
+
+		response.Success(c, gin.H{"invitation": invitation})
+	}
+}
+
+// ListInvitations 列出邀請碼
+// @Summary      列出邀請碼
+// @Description  依建立時間由新到舊列出註冊邀請碼與使用次數（需登入，需為網站管理員）
+// @Tags         邀請碼
+// @Produce      json
+// @Security     ApiAuth
+// @Param        limit query int false "限制筆數"
+// @Param        skip query int false "略過筆數"
+// @Success      200 {object} map[string]interface{} "成功回應（含邀請碼列表）"
+// @Failure      200 {object} map[string]interface{} "權限不足" example({"state":1,"code":2})
+// @Router       /apis/admin/invitations [get]
+func ListInvitations(db database.Database, appConfig interface{}) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !isSiteAdmin(c, appConfig) {
+			response.Error(c, response.ErrorAccessDenied)
+			return
+		}
+
+		var limit, skip int64
+		if l, err := strconv.ParseInt(c.Query("limit"), 10, 64); err == nil && l > 0 {
+			limit = l
+		}
+		if s, err := strconv.ParseInt(c.Query("skip"), 10, 64); err == nil && s > 0 {
+			skip = s
+		}
+
+		invitationService := service.NewInvitationService(repository.NewInvitationRepository(db))
+		invitations, err := invitationService.ListInvitations(c.Request.Context(), limit, skip)
+		if err != nil {
+			response.Error(c, response.ErrorServerError)
+			return
+		}
+
+		response.Success(c, gin.H{"invitations": invitations})
+	}
+}
+
+// RevokeInvitation 撤銷邀請碼
+// @Summary      撤銷邀請碼
+// @Description  撤銷註冊邀請碼，撤銷後無法再用於註冊（需登入，需為網站管理員）
+// @Tags         邀請碼
+// @Produce      json
+// @Security     ApiAuth
+// @Param        id path string true "邀請碼 ID"
+// @Success      200 {object} map[string]interface{} "成功回應" example({"state":0})
+// @Failure      200 {object} map[string]interface{} "邀請碼不存在" example({"state":1,"code":0})
+// @Failure      200 {object} map[string]interface{} "權限不足" example({"state":1,"code":2})
+// @Router       /apis/admin/invitations/{id}/revoke [post]
+func RevokeInvitation(db database.Database, appConfig interface{}) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !isSiteAdmin(c, appConfig) {
+			response.Error(c, response.ErrorAccessDenied)
+			return
+		}
+
+		invitationService := service.NewInvitationService(repository.NewInvitationRepository(db))
+		if err := invitationService.RevokeInvitation(c.Request.Context(), c.Param("id")); err != nil {
+			if err.Error() == "invitation not found" {
+				response.Error(c, response.ErrorRequiredField)
+				return
+			}
+			response.Error(c, response.ErrorServerError)
+			return
+		}
+
+		response.Success(c, nil)
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/higgstv/higgstv-go/internal/api/response"
 	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/models"
 	"github.com/higgstv/higgstv-go/internal/repository"
 	"github.com/higgstv/higgstv-go/internal/service"
 	"github.com/higgstv/higgstv-go/pkg/session"
@@ -17,9 +18,11 @@ import (
 // PickProgramRequest Pick 節目請求（Query 參數）
 type PickProgramRequest struct {
 	Callback  string `form:"callback" example:"callback"` // JSONP callback 函數名
-	Name      string `form:"name" example:"影片名稱"` // 影片名稱
+	Name      string `form:"name" example:"影片名稱"` // 影片名稱（未提供時查詢影片資訊補齊）
 	YouTubeID string `form:"youtube_id" example:"dQw4w9WgXcQ"` // YouTube 影片 ID（文件規範）
-	URL       string `form:"url" example:"https://www.youtube.com/watch?v=dQw4w9WgXcQ"` // YouTube URL（向後相容）
+	Type      string `form:"type" example:"vimeo"` // 影片來源類型（選填，搭配 video_id）
+	VideoID   string `form:"video_id" example:"76979871"` // 影片 ID（選填，搭配 type）
+	URL       string `form:"url" example:"https://www.youtube.com/watch?v=dQw4w9WgXcQ"` // 影片 URL（自動判斷來源）
 	Desc      string `form:"desc" example:"影片描述"` // 描述（選填）
 	Duration  string `form:"duration" example:"300"` // 時長（秒，選填）
 	Tags      string `form:"tags" example:"1,2,3"` // 標籤（選填，逗號分隔）
@@ -27,14 +30,17 @@ type PickProgramRequest struct {
 
 // PickProgram Pick 節目（Bookmarklet API，支援 JSONP）
 // @Summary      Pick 節目（Bookmarklet）
-// @Description  透過 Bookmarklet 新增 YouTube 影片到未分類頻道（支援 JSONP），同時支援 youtube_id 和 url 參數
+// @Description  透過 Bookmarklet 新增影片到未分類頻道（支援 JSONP），同時支援 youtube_id、type + video_id 和 url 參數
+// @Description  url 可為 YouTube、Vimeo、Dailymotion 或直接連結的 MP4 / HLS 檔案
 // @Tags         節目
 // @Produce      json
 // @Security     ApiAuth
 // @Param        callback query string false "JSONP callback 函數名"
-// @Param        name query string true "影片名稱"
+// @Param        name query string false "影片名稱（未提供時查詢影片資訊補齊）"
 // @Param        youtube_id query string false "YouTube 影片 ID（文件規範）"
-// @Param        url query string false "YouTube URL（向後相容，與 youtube_id 二選一）"
+// @Param        type query string false "影片來源類型（youtube、vimeo、dailymotion、mp4、hls）"
+// @Param        video_id query string false "影片 ID（搭配 type）"
+// @Param        url query string false "影片 URL（與 youtube_id、video_id 擇一）"
 // @Param        desc query string false "描述"
 // @Param        duration query int false "時長（秒）"
 // @Param        tags query string false "標籤（逗號分隔的數字）"
@@ -58,12 +64,6 @@ func PickProgram(db database.Database) gin.HandlerFunc {
 			}
 		}
 
-		// 驗證必填欄位
-		if req.Name == "" {
-			response.JSONPError(c, req.Callback, response.ErrorRequiredField)
-			return
-		}
-
 		userID := session.GetUserID(c)
 		username := session.GetUsername(c)
 		unclassifiedChannelID := session.GetUnclassifiedChannel(c)
@@ -77,7 +77,9 @@ func PickProgram(db database.Database) gin.HandlerFunc {
 		if unclassifiedChannelID == "" {
 			channelRepo := repository.NewChannelRepository(db)
 			userRepo := repository.NewUserRepository(db)
-			channelService := service.NewChannelService(channelRepo, userRepo)
+			channelService := service.NewChannelService(channelRepo, userRepo).
+				WithHistory(repository.NewChannelRevisionRepository(db)).
+				WithTransactions(db)
 
 			unclassifiedChannel, err := channelService.CreateUnclassifiedChannel(c.Request.Context(), userID, username)
 			if err != nil {
@@ -93,15 +95,13 @@ func PickProgram(db database.Database) gin.HandlerFunc {
 			}
 		}
 
-		// 取得 YouTube ID（優先使用 youtube_id，否則從 url 提取）
-		var youtubeID string
-		if req.YouTubeID != "" {
-			youtubeID = req.YouTubeID
-		} else if req.URL != "" {
-			youtubeID = extractYouTubeID(req.URL)
-		}
-
-		if youtubeID == "" {
+		// 取得影片來源（優先使用 video_id / youtube_id，否則從 url 判斷）
+		programType, videoID, err := service.ResolveVideo(
+			models.ProgramType(req.Type),
+			firstNonEmpty(req.VideoID, req.YouTubeID),
+			req.URL,
+		)
+		if err != nil {
 			response.JSONPError(c, req.Callback, response.ErrorRequiredField)
 			return
 		}
@@ -129,19 +129,27 @@ func PickProgram(db database.Database) gin.HandlerFunc {
 		// 新增節目到未分類頻道
 		programRepo := repository.NewProgramRepository(db)
 		channelRepo := repository.NewChannelRepository(db)
-		programService := service.NewProgramService(programRepo, channelRepo)
+		programService := service.NewProgramService(programRepo, channelRepo).
+			WithHistory(repository.NewChannelRevisionRepository(db)).
+			WithTags(repository.NewTagRepository(db)).
+			WithTransactions(db)
 
 		program, err := programService.AddProgram(
 			c.Request.Context(),
 			unclassifiedChannelID,
 			req.Name,
-			youtubeID,
+			programType,
+			videoID,
 			req.Desc,
 			duration,
 			tags,
 			false, // updateCover 設為 false（pickprog 不需要更新封面）
 		)
 		if err != nil {
+			if err.Error() == "name and video id are required" || err.Error() == "invalid tags" {
+				response.JSONPError(c, req.Callback, response.ErrorRequiredField)
+				return
+			}
 			response.JSONPError(c, req.Callback, response.ErrorServerError)
 			return
 		}
@@ -154,51 +162,3 @@ func PickProgram(db database.Database) gin.HandlerFunc {
 		response.JSONPSuccess(c, req.Callback, respData)
 	}
 }
-
-// extractYouTubeID 從 URL 提取 YouTube ID
-func extractYouTubeID(url string) string {
-	if url == "" {
-		return ""
-	}
-
-	// 處理 youtu.be 短網址
-	if len(url) > 17 && url[:17] == "https://youtu.be/" {
-		id := url[17:]
-		// 移除可能的查詢參數
-		if idx := len(id); idx > 0 {
-			for i, c := range id {
-				if c == '?' || c == '&' {
-					return id[:i]
-				}
-			}
-			return id
-		}
-	}
-
-	// 處理標準 YouTube URL (watch?v=)
-	if len(url) > 32 && url[:32] == "https://www.youtube.com/watch?v=" {
-		id := url[32:]
-		// 移除可能的查詢參數
-		for i, c := range id {
-			if c == '&' {
-				return id[:i]
-			}
-		}
-		return id
-	}
-
-	// 處理 embed URL
-	if len(url) > 31 && url[:31] == "https://www.youtube.com/embed/" {
-		id := url[31:]
-		// 移除可能的查詢參數
-		for i, c := range id {
-			if c == '?' || c == '&' {
-				return id[:i]
-			}
-		}
-		return id
-	}
-
-	return ""
-}
-
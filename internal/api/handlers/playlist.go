@@ -0,0 +1,350 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/higgstv/higgstv-go/internal/api/response"
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/playlist"
+	"github.com/higgstv/higgstv-go/internal/repository"
+	"github.com/higgstv/higgstv-go/internal/service"
+	"github.com/higgstv/higgstv-go/pkg/logger"
+	"github.com/higgstv/higgstv-go/pkg/session"
+)
+
+// maxPlaylistFileSize 匯入播放清單檔案大小上限
+const maxPlaylistFileSize = 5 << 20
+
+// maxImportURLs 單次匯入的影片 URL 數上限
+const maxImportURLs = 500
+
+// ExportChannel 匯出頻道播放清單
+// @Summary      匯出頻道播放清單
+// @Description  依節目順序將頻道匯出為 M3U、XSPF 或 JSON 播放清單
+// @Tags         頻道
+// @Produce      plain
+// @Param        id path string true "頻道 ID"
+// @Param        format query string false "格式：m3u、xspf 或 json（預設）"
+// @Success      200 {string} string "播放清單檔案"
+// @Failure      200 {object} map[string]interface{} "格式錯誤" example({"state":1,"code":0})
+// @Failure      200 {object} map[string]interface{} "頻道不存在" example({"state":1,"code":2})
+// @Router       /apis/channel/{id}/export [get]
+func ExportChannel(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		channelID := c.Param("id")
+		format, err := playlist.ParseFormat(c.DefaultQuery("format", string(playlist.FormatJSON)))
+		if err != nil || channelID == "" {
+			response.Error(c, response.ErrorRequiredField)
+			return
+		}
+
+		channelRepo := repository.NewChannelRepository(db)
+		channel, err := channelRepo.FindByID(c.Request.Context(), channelID)
+		if err != nil {
+			response.Error(c, response.ErrorServerError)
+			return
+		}
+		if channel == nil || !channel.Readable(session.GetUserID(c)) {
+			response.Error(c, response.ErrorAccessDenied)
+			return
+		}
+
+		var buf bytes.Buffer
+		if err := playlist.Write(&buf, format, playlist.FromChannel(channel)); err != nil {
+			response.Error(c, response.ErrorServerError)
+			return
+		}
+
+		c.Header("Content-Disposition", `attachment; filename="channel-`+channel.ID+"."+string(format)+`"`)
+		c.Data(http.StatusOK, playlist.ContentType(format), buf.Bytes())
+	}
+}
+
+// ImportChannelRequest 匯入影片請求
+type ImportChannelRequest struct {
+	URL      string   `json:"url" example:"https://www.youtube.com/playlist?list=PLxxxxxxxx"` // 播放清單 URL（也可以是單一影片 URL）
+	URLs     []string `json:"urls"`                                                           // 影片 URL 列表（接在播放清單之後）
+	Revision *int64   `json:"revision"`                                                       // 預期的頻道修訂版本（選填，也可使用 If-Match 標頭）
+}
+
+// ImportChannel 從線上播放清單或影片 URL 列表匯入節目
+// @Summary      匯入播放清單
+// @Description  解析播放清單 URL（目前支援 YouTube）或影片 URL 列表，依序新增為頻道節目並回報每個項目的匯入結果（需登入，需有 write 權限）
+// @Description  頻道中已有的影片與重複的項目會略過（reason 為 duplicate），所有節目在同一個交易中新增
+// @Tags         頻道
+// @Accept       json
+// @Produce      json
+// @Security     ApiAuth
+// @Param        id path string true "頻道 ID"
+// @Param        If-Match header string false "預期的頻道修訂版本（GetChannel 回傳的 ETag）"
+// @Param        request body ImportChannelRequest true "播放清單 URL 或影片 URL 列表"
+// @Success      200 {object} map[string]interface{} "成功回應（含每個項目的 status 與 reason）"
+// @Failure      200 {object} map[string]interface{} "參數錯誤或播放清單不存在" example({"state":1,"code":0})
+// @Failure      200 {object} map[string]interface{} "權限不足" example({"state":1,"code":2})
+// @Failure      200 {object} map[string]interface{} "頻道已被其他人修改" example({"state":1,"code":3})
+// @Router       /apis/channel/{id}/import [post]
+func ImportChannel(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		channelID := c.Param("id")
+		if channelID == "" {
+			response.Error(c, response.ErrorRequiredField)
+			return
+		}
+
+		var req ImportChannelRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.Error(c, response.ErrorRequiredField)
+			return
+		}
+		req.URL = strings.TrimSpace(req.URL)
+		if (req.URL == "" && len(req.URLs) == 0) || len(req.URLs) > maxImportURLs {
+			response.Error(c, response.ErrorRequiredField)
+			return
+		}
+
+		userID := session.GetUserID(c)
+		if userID == "" {
+			response.Error(c, response.ErrorRequireLogin)
+			return
+		}
+
+		programRepo := repository.NewProgramRepository(db)
+		channelRepo := repository.NewChannelRepository(db)
+
+		// 檢查權限
+		channelService := service.NewChannelService(channelRepo, nil)
+		canWrite, err := channelService.CanWrite(c.Request.Context(), channelID, userID)
+		if err != nil {
+			response.Error(c, response.ErrorServerError)
+			return
+		}
+		if !canWrite {
+			response.Error(c, response.ErrorAccessDenied)
+			return
+		}
+		revision, ok := requestRevision(c, req.Revision)
+		if !ok {
+			return
+		}
+
+		entries := make([]playlist.Entry, 0, len(req.URLs)+1)
+		title := ""
+		if req.URL != "" {
+			resolved, err := playlist.DefaultResolver.Resolve(c.Request.Context(), req.URL)
+			switch {
+			case errors.Is(err, playlist.ErrNotPlaylist):
+				// 不是播放清單時當作單一影片匯入
+				entries = append(entries, playlist.Entry{URL: req.URL})
+			case errors.Is(err, playlist.ErrPlaylistNotFound):
+				response.Error(c, response.ErrorRequiredField)
+				return
+			case err != nil:
+				if logger.Logger != nil {
+					logger.Logger.Error("Failed to resolve playlist",
+						zap.Error(err),
+						zap.String("url", req.URL),
+					)
+				}
+				response.Error(c, response.ErrorServerError)
+				return
+			default:
+				title = resolved.Title
+				entries = append(entries, resolved.Entries...)
+			}
+		}
+		for _, rawURL := range req.URLs {
+			entries = append(entries, playlist.Entry{URL: strings.TrimSpace(rawURL)})
+		}
+
+		programService := service.NewProgramService(programRepo, channelRepo).WithHistory(repository.NewChannelRevisionRepository(db))
+		var results []service.ImportResult
+		err = withRevision(c.Request.Context(), db, channelID, revision, func(ctx context.Context) error {
+			results, err = programService.ImportVideos(ctx, channelID, entries)
+			return err
+		})
+		if revisionConflict(c, err) {
+			return
+		}
+		if err != nil {
+			if err.Error() == "channel not found" {
+				response.Error(c, response.ErrorAccessDenied)
+				return
+			}
+			if logger.Logger != nil {
+				logger.Logger.Error("Failed to import videos",
+					zap.Error(err),
+					zap.String("channel_id", channelID),
+					zap.Int("entries", len(entries)),
+				)
+			}
+			response.Error(c, response.ErrorServerError)
+			return
+		}
+
+		added := 0
+		for _, result := range results {
+			if result.Status == service.ImportStatusAdded {
+				added++
+			}
+		}
+
+		setRevisionETag(c, db, channelID)
+		response.Success(c, gin.H{
+			"title":   title,
+			"added":   added,
+			"skipped": len(results) - added,
+			"results": results,
+		})
+	}
+}
+
+// ImportChannelFile 從播放清單檔案匯入節目
+// @Summary      匯入播放清單檔案
+// @Description  解析 M3U、XSPF 或 JSON 播放清單並依序新增為頻道節目，回報每個項目的匯入結果（需登入，需有 write 權限）
+// @Description  檔案可使用 multipart 欄位 file 上傳，或直接放在請求內容；未指定 format 時依副檔名或內容判斷
+// @Tags         頻道
+// @Accept       multipart/form-data
+// @Accept       plain
+// @Produce      json
+// @Security     ApiAuth
+// @Param        id path string true "頻道 ID"
+// @Param        If-Match header string false "預期的頻道修訂版本（GetChannel 回傳的 ETag）"
+// @Param        format query string false "格式：m3u、xspf 或 json"
+// @Param        file formData file false "播放清單檔案"
+// @Success      200 {object} map[string]interface{} "成功回應（含每個項目的 status 與 reason）"
+// @Failure      200 {object} map[string]interface{} "格式錯誤" example({"state":1,"code":0})
+// @Failure      200 {object} map[string]interface{} "權限不足" example({"state":1,"code":2})
+// @Failure      200 {object} map[string]interface{} "頻道已被其他人修改" example({"state":1,"code":3})
+// @Router       /apis/channel/{id}/import/file [post]
+func ImportChannelFile(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		channelID := c.Param("id")
+		if channelID == "" {
+			response.Error(c, response.ErrorRequiredField)
+			return
+		}
+
+		userID := session.GetUserID(c)
+		if userID == "" {
+			response.Error(c, response.ErrorRequireLogin)
+			return
+		}
+
+		data, filename, err := readPlaylistUpload(c)
+		if err != nil || len(data) == 0 {
+			response.Error(c, response.ErrorRequiredField)
+			return
+		}
+
+		format, err := detectPlaylistFormat(c.Query("format"), filename, data)
+		if err != nil {
+			response.Error(c, response.ErrorRequiredField)
+			return
+		}
+		parsed, err := playlist.Parse(bytes.NewReader(data), format)
+		if err != nil {
+			response.Error(c, response.ErrorRequiredField)
+			return
+		}
+
+		programRepo := repository.NewProgramRepository(db)
+		channelRepo := repository.NewChannelRepository(db)
+
+		// 檢查權限
+		channelService := service.NewChannelService(channelRepo, nil)
+		canWrite, err := channelService.CanWrite(c.Request.Context(), channelID, userID)
+		if err != nil {
+			response.Error(c, response.ErrorServerError)
+			return
+		}
+		if !canWrite {
+			response.Error(c, response.ErrorAccessDenied)
+			return
+		}
+		revision, ok := requestRevision(c, nil)
+		if !ok {
+			return
+		}
+
+		programService := service.NewProgramService(programRepo, channelRepo).WithHistory(repository.NewChannelRevisionRepository(db))
+		var results []service.ImportResult
+		err = withRevision(c.Request.Context(), db, channelID, revision, func(ctx context.Context) error {
+			results, err = programService.ImportPlaylist(ctx, channelID, parsed.Entries)
+			return err
+		})
+		if revisionConflict(c, err) {
+			return
+		}
+		if err != nil {
+			if logger.Logger != nil {
+				logger.Logger.Error("Failed to import playlist",
+					zap.Error(err),
+					zap.String("channel_id", channelID),
+					zap.Int("imported", len(results)),
+				)
+			}
+			response.Error(c, response.ErrorServerError)
+			return
+		}
+
+		added := 0
+		for _, result := range results {
+			if result.Status == service.ImportStatusAdded {
+				added++
+			}
+		}
+
+		setRevisionETag(c, db, channelID)
+		response.Success(c, gin.H{
+			"format":  format,
+			"added":   added,
+			"skipped": len(results) - added,
+			"results": results,
+		})
+	}
+}
+
+// readPlaylistUpload 讀取上傳的播放清單（multipart 欄位 file 或請求內容）
+func readPlaylistUpload(c *gin.Context) ([]byte, string, error) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxPlaylistFileSize)
+
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			return nil, "", err
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			return nil, "", err
+		}
+		defer func() {
+			_ = file.Close()
+		}()
+		data, err := io.ReadAll(file)
+		return data, fileHeader.Filename, err
+	}
+
+	data, err := io.ReadAll(c.Request.Body)
+	return data, "", err
+}
+
+// detectPlaylistFormat 依序使用 format 參數、副檔名、內容判斷格式
+func detectPlaylistFormat(name, filename string, data []byte) (playlist.Format, error) {
+	if name != "" {
+		return playlist.ParseFormat(name)
+	}
+	if filename != "" {
+		if format, err := playlist.FormatFromFilename(filename); err == nil {
+			return format, nil
+		}
+	}
+	return playlist.Detect(data)
+}
@@ -1,11 +1,14 @@
 package handlers
 
 import (
+	"context"
+
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 
 	"github.com/higgstv/higgstv-go/internal/api/response"
 	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/models"
 	"github.com/higgstv/higgstv-go/internal/repository"
 	"github.com/higgstv/higgstv-go/internal/service"
 	"github.com/higgstv/higgstv-go/pkg/logger"
@@ -15,24 +18,30 @@ import (
 // AddProgramRequest 新增節目請求
 type AddProgramRequest struct {
 	Ch         string `json:"ch" binding:"required" example:"channel_id"` // 頻道 ID
-	Name       string `json:"name" binding:"required" example:"節目名稱"` // 節目名稱
-	YouTubeID  string `json:"youtube_id" binding:"required" example:"dQw4w9WgXcQ"` // YouTube 影片 ID
+	Name       string `json:"name" example:"節目名稱"` // 節目名稱（未提供時查詢影片資訊補齊）
+	YouTubeID  string `json:"youtube_id" example:"dQw4w9WgXcQ"` // YouTube 影片 ID（向後相容）
+	Type       models.ProgramType `json:"type" example:"youtube"` // 影片來源類型（選填，預設 youtube）
+	VideoID    string `json:"video_id" example:"dQw4w9WgXcQ"` // 影片 ID（與 youtube_id、url 擇一）
+	URL        string `json:"url" example:"https://vimeo.com/76979871"` // 影片 URL（自動判斷來源）
 	Desc       string `json:"desc" example:"節目描述"` // 節目描述
-	Duration   int    `json:"duration" example:"300"` // 時長（秒）
+	Duration   int    `json:"duration" example:"300"` // 時長（秒，未提供時查詢影片資訊補齊）
 	Tags       []int  `json:"tags"` // 標籤列表
 	UpdateCover bool  `json:"updateCover" example:"false"` // 是否更新頻道封面
+	Revision   *int64 `json:"revision"` // 預期的頻道修訂版本（選填，也可使用 If-Match 標頭）
 }
 
 // AddProgram 新增節目
 // @Summary      新增節目
-// @Description  在頻道中新增節目（需要登入且為頻道管理員）
+// @Description  在頻道中新增節目（需要登入且具有頻道的 write 權限），影片可用 youtube_id、type + video_id 或 url 指定
 // @Tags         節目
 // @Accept       json
 // @Produce      json
 // @Security     ApiAuth
+// @Param        If-Match header string false "預期的頻道修訂版本（GetChannel 回傳的 ETag）"
 // @Param        request body AddProgramRequest true "新增節目請求"
 // @Success      200 {object} map[string]interface{} "成功回應"
 // @Failure      200 {object} map[string]interface{} "需要登入或權限不足" example({"state":1,"code":2})
+// @Failure      200 {object} map[string]interface{} "頻道已被其他人修改" example({"state":1,"code":3})
 // @Router       /apis/addprog [post]
 func AddProgram(db database.Database) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -46,6 +55,12 @@ func AddProgram(db database.Database) gin.HandlerFunc {
 			req.Tags = []int{}
 		}
 
+		programType, videoID, err := service.ResolveVideo(req.Type, firstNonEmpty(req.VideoID, req.YouTubeID), req.URL)
+		if err != nil {
+			response.Error(c, response.ErrorRequiredField)
+			return
+		}
+
 		userID := session.GetUserID(c)
 		if userID == "" {
 			response.Error(c, response.ErrorRequireLogin)
@@ -54,35 +69,55 @@ func AddProgram(db database.Database) gin.HandlerFunc {
 
 		programRepo := repository.NewProgramRepository(db)
 		channelRepo := repository.NewChannelRepository(db)
-		programService := service.NewProgramService(programRepo, channelRepo)
+		programService := service.NewProgramService(programRepo, channelRepo).
+			WithHistory(repository.NewChannelRevisionRepository(db)).
+			WithTags(repository.NewTagRepository(db)).
+			WithTransactions(db)
 
 		// 檢查權限
 		channelService := service.NewChannelService(channelRepo, nil)
-		isAdmin, err := channelService.IsAdmin(c.Request.Context(), req.Ch, userID)
+		canWrite, err := channelService.CanWrite(c.Request.Context(), req.Ch, userID)
 		if err != nil {
 			response.Error(c, response.ErrorServerError)
 			return
 		}
-		if !isAdmin {
+		if !canWrite {
 			response.Error(c, response.ErrorAccessDenied)
 			return
 		}
+		revision, ok := requestRevision(c, req.Revision)
+		if !ok {
+			return
+		}
 
-		program, err := programService.AddProgram(
-			c.Request.Context(),
-			req.Ch,
-			req.Name,
-			req.YouTubeID,
-			req.Desc,
-			req.Duration,
-			req.Tags,
-			req.UpdateCover,
-		)
+		var program *models.Program
+		err = withRevision(c.Request.Context(), db, req.Ch, revision, func(ctx context.Context) error {
+			program, err = programService.AddProgram(
+				ctx,
+				req.Ch,
+				req.Name,
+				programType,
+				videoID,
+				req.Desc,
+				req.Duration,
+				req.Tags,
+				req.UpdateCover,
+			)
+			return err
+		})
+		if revisionConflict(c, err) {
+			return
+		}
 		if err != nil {
+			if err.Error() == "name and video id are required" || err.Error() == "invalid tags" {
+				response.Error(c, response.ErrorRequiredField)
+				return
+			}
 			response.Error(c, response.ErrorServerError)
 			return
 		}
 
+		setRevisionETag(c, db, req.Ch)
 		response.Success(c, gin.H{"program": program})
 	}
 }
@@ -92,24 +127,30 @@ type SaveProgramRequest struct {
 	Ch         string `json:"ch" binding:"required"`
 	ProgID     int    `json:"prog_id" binding:"required"`
 	Name       string `json:"name" binding:"required"`
-	YouTubeID  string `json:"youtube_id" binding:"required"`
+	YouTubeID  string `json:"youtube_id"`
+	Type       models.ProgramType `json:"type"`
+	VideoID    string `json:"video_id"`
+	URL        string `json:"url"`
 	Desc       string `json:"desc"`
 	Duration   *int   `json:"duration"`
 	Tags       []int  `json:"tags"`
 	UpdateCover bool  `json:"updateCover"`
+	Revision   *int64 `json:"revision"` // 預期的頻道修訂版本（選填，也可使用 If-Match 標頭）
 }
 
 // SaveProgram 儲存節目
 // @Summary      儲存節目
-// @Description  更新節目內容（需登入，需有權限）
+// @Description  更新節目內容（需登入，需有 write 權限），提供 youtube_id、type + video_id 或 url 時一併更新影片來源
 // @Tags         節目
 // @Accept       json
 // @Produce      json
 // @Security     ApiAuth
+// @Param        If-Match header string false "預期的頻道修訂版本（GetChannel 回傳的 ETag）"
 // @Param        request body SaveProgramRequest true "儲存節目請求"
 // @Success      200 {object} map[string]interface{} "成功回應"
 // @Failure      200 {object} map[string]interface{} "缺少必填欄位" example({"state":1,"code":0})
 // @Failure      200 {object} map[string]interface{} "權限不足" example({"state":1,"code":2})
+// @Failure      200 {object} map[string]interface{} "頻道已被其他人修改" example({"state":1,"code":3})
 // @Router       /apis/saveprog [post]
 func SaveProgram(db database.Database) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -119,6 +160,18 @@ func SaveProgram(db database.Database) gin.HandlerFunc {
 			return
 		}
 
+		// 未提供影片時保留原本的影片來源
+		var programType models.ProgramType
+		videoID := firstNonEmpty(req.VideoID, req.YouTubeID)
+		if videoID != "" || req.URL != "" {
+			var err error
+			programType, videoID, err = service.ResolveVideo(req.Type, videoID, req.URL)
+			if err != nil {
+				response.Error(c, response.ErrorRequiredField)
+				return
+			}
+		}
+
 		userID := session.GetUserID(c)
 		if userID == "" {
 			response.Error(c, response.ErrorRequireLogin)
@@ -127,36 +180,49 @@ func SaveProgram(db database.Database) gin.HandlerFunc {
 
 		programRepo := repository.NewProgramRepository(db)
 		channelRepo := repository.NewChannelRepository(db)
-		programService := service.NewProgramService(programRepo, channelRepo)
+		programService := service.NewProgramService(programRepo, channelRepo).WithHistory(repository.NewChannelRevisionRepository(db))
 
 		// 檢查權限
 		channelService := service.NewChannelService(channelRepo, nil)
-		isAdmin, err := channelService.IsAdmin(c.Request.Context(), req.Ch, userID)
+		canWrite, err := channelService.CanWrite(c.Request.Context(), req.Ch, userID)
 		if err != nil {
 			response.Error(c, response.ErrorServerError)
 			return
 		}
-		if !isAdmin {
+		if !canWrite {
 			response.Error(c, response.ErrorAccessDenied)
 			return
 		}
+		revision, ok := requestRevision(c, req.Revision)
+		if !ok {
+			return
+		}
 
-		program, err := programService.UpdateProgram(
-			c.Request.Context(),
-			req.Ch,
-			req.ProgID,
-			req.Name,
-			req.YouTubeID,
-			req.Desc,
-			req.Duration,
-			req.Tags,
-			req.UpdateCover,
-		)
+		var program *models.Program
+		err = withRevision(c.Request.Context(), db, req.Ch, revision, func(ctx context.Context) error {
+			program, err = programService.UpdateProgram(
+				ctx,
+				req.Ch,
+				req.ProgID,
+				req.Name,
+				programType,
+				videoID,
+				req.Desc,
+				req.Duration,
+				req.Tags,
+				req.UpdateCover,
+			)
+			return err
+		})
+		if revisionConflict(c, err) {
+			return
+		}
 		if err != nil {
 			response.Error(c, response.ErrorServerError)
 			return
 		}
 
+		setRevisionETag(c, db, req.Ch)
 		response.Success(c, gin.H{"program": program})
 	}
 }
@@ -165,19 +231,22 @@ func SaveProgram(db database.Database) gin.HandlerFunc {
 type DeleteProgramRequest struct {
 	Ch string `json:"ch" binding:"required"`
 	IDs []int  `json:"ids" binding:"required"`
+	Revision *int64 `json:"revision"` // 預期的頻道修訂版本（選填，也可使用 If-Match 標頭）
 }
 
 // DeleteProgram 刪除節目
 // @Summary      刪除節目
-// @Description  刪除節目（可一次刪除多個，需登入，需有權限）
+// @Description  刪除節目（可一次刪除多個，需登入，需有 write 權限）
 // @Tags         節目
 // @Accept       json
 // @Produce      json
 // @Security     ApiAuth
+// @Param        If-Match header string false "預期的頻道修訂版本（GetChannel 回傳的 ETag）"
 // @Param        request body DeleteProgramRequest true "刪除節目請求"
 // @Success      200 {object} map[string]interface{} "成功回應" example({"state":0})
 // @Failure      200 {object} map[string]interface{} "缺少必填欄位" example({"state":1,"code":0})
 // @Failure      200 {object} map[string]interface{} "權限不足" example({"state":1,"code":2})
+// @Failure      200 {object} map[string]interface{} "頻道已被其他人修改" example({"state":1,"code":3})
 // @Router       /apis/delprog [post]
 func DeleteProgram(db database.Database) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -195,21 +264,30 @@ func DeleteProgram(db database.Database) gin.HandlerFunc {
 
 		programRepo := repository.NewProgramRepository(db)
 		channelRepo := repository.NewChannelRepository(db)
-		programService := service.NewProgramService(programRepo, channelRepo)
+		programService := service.NewProgramService(programRepo, channelRepo).WithHistory(repository.NewChannelRevisionRepository(db))
 
 		// 檢查權限
 		channelService := service.NewChannelService(channelRepo, nil)
-		isAdmin, err := channelService.IsAdmin(c.Request.Context(), req.Ch, userID)
+		canWrite, err := channelService.CanWrite(c.Request.Context(), req.Ch, userID)
 		if err != nil {
 			response.Error(c, response.ErrorServerError)
 			return
 		}
-		if !isAdmin {
+		if !canWrite {
 			response.Error(c, response.ErrorAccessDenied)
 			return
 		}
+		revision, ok := requestRevision(c, req.Revision)
+		if !ok {
+			return
+		}
 
-		err = programService.DeletePrograms(c.Request.Context(), req.Ch, req.IDs)
+		err = withRevision(c.Request.Context(), db, req.Ch, revision, func(ctx context.Context) error {
+			return programService.DeletePrograms(ctx, req.Ch, req.IDs)
+		})
+		if revisionConflict(c, err) {
+			return
+		}
 		if err != nil {
 			// 記錄錯誤以便除錯
 			if logger.Logger != nil {
@@ -223,6 +301,7 @@ func DeleteProgram(db database.Database) gin.HandlerFunc {
 			return
 		}
 
+		setRevisionETag(c, db, req.Ch)
 		response.Success(c, nil)
 	}
 }
@@ -232,19 +311,23 @@ type MoveProgramRequest struct {
 	Ch     string `json:"ch" binding:"required"`
 	Target string `json:"target" binding:"required"`
 	IDs    []int  `json:"ids" binding:"required"`
+	Revision *int64 `json:"revision"` // 預期的來源頻道修訂版本（選填，也可使用 If-Match 標頭）
+	TargetRevision *int64 `json:"target_revision"` // 預期的目標頻道修訂版本（選填）
 }
 
 // MoveProgram 移動節目
 // @Summary      移動節目
-// @Description  將節目從一個頻道搬到另一個頻道（需登入，需有來源和目標頻道權限）
+// @Description  將節目從一個頻道搬到另一個頻道（需登入，需有來源和目標頻道的 write 權限）
 // @Tags         節目
 // @Accept       json
 // @Produce      json
 // @Security     ApiAuth
+// @Param        If-Match header string false "預期的頻道修訂版本（GetChannel 回傳的 ETag）"
 // @Param        request body MoveProgramRequest true "移動節目請求"
 // @Success      200 {object} map[string]interface{} "成功回應" example({"state":0})
 // @Failure      200 {object} map[string]interface{} "缺少必填欄位" example({"state":1,"code":0})
 // @Failure      200 {object} map[string]interface{} "權限不足" example({"state":1,"code":2})
+// @Failure      200 {object} map[string]interface{} "頻道已被其他人修改" example({"state":1,"code":3})
 // @Router       /apis/progmoveto [post]
 func MoveProgram(db database.Database) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -262,37 +345,51 @@ func MoveProgram(db database.Database) gin.HandlerFunc {
 
 		programRepo := repository.NewProgramRepository(db)
 		channelRepo := repository.NewChannelRepository(db)
-		programService := service.NewProgramService(programRepo, channelRepo)
+		programService := service.NewProgramService(programRepo, channelRepo).
+			WithHistory(repository.NewChannelRevisionRepository(db)).
+			WithTransactions(db)
 
 		// 檢查來源頻道權限
 		channelService := service.NewChannelService(channelRepo, nil)
-		isAdmin, err := channelService.IsAdmin(c.Request.Context(), req.Ch, userID)
+		canWrite, err := channelService.CanWrite(c.Request.Context(), req.Ch, userID)
 		if err != nil {
 			response.Error(c, response.ErrorServerError)
 			return
 		}
-		if !isAdmin {
+		if !canWrite {
 			response.Error(c, response.ErrorAccessDenied)
 			return
 		}
 
 		// 檢查目標頻道權限
-		isAdminTarget, err := channelService.IsAdmin(c.Request.Context(), req.Target, userID)
+		canWriteTarget, err := channelService.CanWrite(c.Request.Context(), req.Target, userID)
 		if err != nil {
 			response.Error(c, response.ErrorServerError)
 			return
 		}
-		if !isAdminTarget {
+		if !canWriteTarget {
 			response.Error(c, response.ErrorAccessDenied)
 			return
 		}
+		revision, ok := requestRevision(c, req.Revision)
+		if !ok {
+			return
+		}
 
-		err = programService.MoveProgram(c.Request.Context(), req.Ch, req.Target, req.IDs)
+		err = withRevision(c.Request.Context(), db, req.Ch, revision, func(ctx context.Context) error {
+			return withRevision(ctx, db, req.Target, req.TargetRevision, func(ctx context.Context) error {
+				return programService.MoveProgram(ctx, req.Ch, req.Target, req.IDs)
+			})
+		})
+		if revisionConflict(c, err) {
+			return
+		}
 		if err != nil {
 			response.Error(c, response.ErrorServerError)
 			return
 		}
 
+		setRevisionETag(c, db, req.Ch)
 		response.Success(c, nil)
 	}
 }
@@ -301,19 +398,22 @@ func MoveProgram(db database.Database) gin.HandlerFunc {
 type SaveProgramOrderRequest struct {
 	Ch    string `json:"ch" binding:"required"`
 	Order []int  `json:"order" binding:"required"`
+	Revision *int64 `json:"revision"` // 預期的頻道修訂版本（選填，也可使用 If-Match 標頭）
 }
 
 // SaveProgramOrder 儲存節目順序
 // @Summary      儲存節目順序
-// @Description  儲存節目在頻道中的自訂排序（需登入，需有權限）
+// @Description  儲存節目在頻道中的自訂排序（需登入，需有 write 權限）
 // @Tags         節目
 // @Accept       json
 // @Produce      json
 // @Security     ApiAuth
+// @Param        If-Match header string false "預期的頻道修訂版本（GetChannel 回傳的 ETag）"
 // @Param        request body SaveProgramOrderRequest true "儲存節目順序請求"
 // @Success      200 {object} map[string]interface{} "成功回應" example({"state":0})
 // @Failure      200 {object} map[string]interface{} "缺少必填欄位" example({"state":1,"code":0})
 // @Failure      200 {object} map[string]interface{} "權限不足" example({"state":1,"code":2})
+// @Failure      200 {object} map[string]interface{} "頻道已被其他人修改" example({"state":1,"code":3})
 // @Router       /apis/prog/saveorder [post]
 func SaveProgramOrder(db database.Database) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -331,27 +431,46 @@ func SaveProgramOrder(db database.Database) gin.HandlerFunc {
 
 		programRepo := repository.NewProgramRepository(db)
 		channelRepo := repository.NewChannelRepository(db)
-		programService := service.NewProgramService(programRepo, channelRepo)
+		programService := service.NewProgramService(programRepo, channelRepo).WithHistory(repository.NewChannelRevisionRepository(db))
 
 		// 檢查權限
 		channelService := service.NewChannelService(channelRepo, nil)
-		isAdmin, err := channelService.IsAdmin(c.Request.Context(), req.Ch, userID)
+		canWrite, err := channelService.CanWrite(c.Request.Context(), req.Ch, userID)
 		if err != nil {
 			response.Error(c, response.ErrorServerError)
 			return
 		}
-		if !isAdmin {
+		if !canWrite {
 			response.Error(c, response.ErrorAccessDenied)
 			return
 		}
+		revision, ok := requestRevision(c, req.Revision)
+		if !ok {
+			return
+		}
 
-		err = programService.SetOrder(c.Request.Context(), req.Ch, req.Order)
+		err = withRevision(c.Request.Context(), db, req.Ch, revision, func(ctx context.Context) error {
+			return programService.SetOrder(ctx, req.Ch, req.Order)
+		})
+		if revisionConflict(c, err) {
+			return
+		}
 		if err != nil {
 			response.Error(c, response.ErrorServerError)
 			return
 		}
 
+		setRevisionETag(c, db, req.Ch)
 		response.Success(c, nil)
 	}
 }
 
+// firstNonEmpty 回傳第一個非空字串
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
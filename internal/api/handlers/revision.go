@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/higgstv/higgstv-go/internal/api/response"
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/repository"
+	"github.com/higgstv/higgstv-go/internal/service"
+)
+
+// revisionETag 將頻道修訂版本轉換為 ETag
+func revisionETag(revision int64) string {
+	return `"` + strconv.FormatInt(revision, 10) + `"`
+}
+
+// requestRevision 取得請求指定的頻道修訂版本：revision 欄位優先，其次為 If-Match 標頭
+// 未指定或 If-Match 為 * 時回傳 nil；格式錯誤時回應 ErrorRequiredField 並回傳 false
+func requestRevision(c *gin.Context, revision *int64) (*int64, bool) {
+	if revision != nil {
+		return revision, true
+	}
+
+	ifMatch := strings.TrimSpace(c.GetHeader("If-Match"))
+	if ifMatch == "" || ifMatch == "*" {
+		return nil, true
+	}
+	ifMatch = strings.Trim(strings.TrimPrefix(ifMatch, "W/"), `"`)
+	parsed, err := strconv.ParseInt(ifMatch, 10, 64)
+	if err != nil {
+		response.Error(c, response.ErrorRequiredField)
+		return nil, false
+	}
+	return &parsed, true
+}
+
+// withRevision 在交易中確認頻道修訂版本與請求指定的版本（requestRevision 的結果，nil 表示不檢查）相同後執行 fn
+// 需在權限檢查之後呼叫，fn 內修改頻道時必須使用傳入的 ctx；版本不符時回傳的錯誤可用 revisionConflict 處理
+func withRevision(ctx context.Context, db database.Database, channelID string, revision *int64, fn func(ctx context.Context) error) error {
+	channelService := service.NewChannelService(repository.NewChannelRepository(db), nil).WithTransactions(db)
+	return channelService.WithRevision(ctx, channelID, revision, fn)
+}
+
+// revisionConflict 檢查是否為頻道修訂版本不符的錯誤，是的話回應 ErrorConflict 並回傳 true
+func revisionConflict(c *gin.Context, err error) bool {
+	if err == nil || err.Error() != "revision conflict" {
+		return false
+	}
+	response.Error(c, response.ErrorConflict)
+	return true
+}
+
+// setRevisionETag 以頻道修改後的修訂版本設定 ETag 標頭
+func setRevisionETag(c *gin.Context, db database.Database, channelID string) {
+	channelService := service.NewChannelService(repository.NewChannelRepository(db), nil)
+	revision, err := channelService.GetRevision(c.Request.Context(), channelID)
+	if err != nil {
+		return
+	}
+	c.Header("ETag", revisionETag(revision))
+}
@@ -0,0 +1,278 @@
+package handlers
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/higgstv/higgstv-go/internal/api/response"
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/models"
+	"github.com/higgstv/higgstv-go/internal/repository"
+	"github.com/higgstv/higgstv-go/internal/schedule"
+	"github.com/higgstv/higgstv-go/internal/service"
+	"github.com/higgstv/higgstv-go/pkg/session"
+)
+
+const (
+	// defaultScheduleWindow 未指定 to 時的查詢區間
+	defaultScheduleWindow = 24 * time.Hour
+	// maxScheduleWindow 單次查詢區間上限
+	maxScheduleWindow = 7 * 24 * time.Hour
+	// maxScheduleSlots 單次查詢回傳的時段上限
+	maxScheduleSlots = 1000
+)
+
+// GetChannelNow 取得頻道目前播出的節目
+// @Summary      取得頻道目前播出的節目
+// @Description  依頻道的播出起點與循環模式計算目前播出的節目與播放位置，所有觀眾在同一時間看到同一節目的同一秒
+// @Tags         播出
+// @Produce      json
+// @Param        id path string true "頻道 ID"
+// @Param        at query string false "查詢時間（RFC3339 或 Unix 秒數，預設為現在）"
+// @Success      200 {object} map[string]interface{} "成功回應，沒有節目播出時 airing 為 null"
+// @Failure      200 {object} map[string]interface{} "頻道不存在" example({"state":1,"code":2})
+// @Router       /apis/channel/{id}/now [get]
+func GetChannelNow(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		channelID := c.Param("id")
+		if channelID == "" {
+			response.Error(c, response.ErrorRequiredField)
+			return
+		}
+
+		at := time.Now()
+		if atStr := c.Query("at"); atStr != "" {
+			t, ok := parseScheduleTime(atStr)
+			if !ok {
+				response.Error(c, response.ErrorRequiredField)
+				return
+			}
+			at = t
+		}
+
+		channelRepo := repository.NewChannelRepository(db)
+		// 私人頻道需具有 read 權限
+		channelService := service.NewChannelService(channelRepo, nil)
+		canRead, err := channelService.CanRead(c.Request.Context(), channelID, session.GetUserID(c))
+		if err != nil {
+			response.Error(c, response.ErrorServerError)
+			return
+		}
+		if !canRead {
+			response.Error(c, response.ErrorAccessDenied)
+			return
+		}
+
+		scheduleService := service.NewScheduleService(channelRepo)
+		timeline, err := scheduleService.Timeline(c.Request.Context(), channelID)
+		if err != nil {
+			if err.Error() == "channel not found" {
+				response.Error(c, response.ErrorAccessDenied)
+				return
+			}
+			response.Error(c, response.ErrorServerError)
+			return
+		}
+
+		response.Success(c, gin.H{
+			"at":       at.UTC(),
+			"airing":   timeline.At(at),
+			"schedule": scheduleInfo(timeline),
+		})
+	}
+}
+
+// GetChannelSchedule 取得頻道節目表
+// @Summary      取得頻道節目表
+// @Description  列出指定時間區間內的播出時段（預設為現在起 24 小時，最長 7 天）
+// @Tags         播出
+// @Produce      json
+// @Param        id path string true "頻道 ID"
+// @Param        from query string false "開始時間（RFC3339 或 Unix 秒數，預設為現在）"
+// @Param        to query string false "結束時間（RFC3339 或 Unix 秒數，預設為 from 後 24 小時）"
+// @Success      200 {object} map[string]interface{} "成功回應"
+// @Failure      200 {object} map[string]interface{} "參數錯誤" example({"state":1,"code":0})
+// @Failure      200 {object} map[string]interface{} "頻道不存在" example({"state":1,"code":2})
+// @Router       /apis/channel/{id}/schedule [get]
+func GetChannelSchedule(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		channelID := c.Param("id")
+		if channelID == "" {
+			response.Error(c, response.ErrorRequiredField)
+			return
+		}
+
+		from, to, ok := parseScheduleWindow(c)
+		if !ok {
+			response.Error(c, response.ErrorRequiredField)
+			return
+		}
+
+		channelRepo := repository.NewChannelRepository(db)
+		// 私人頻道需具有 read 權限
+		channelService := service.NewChannelService(channelRepo, nil)
+		canRead, err := channelService.CanRead(c.Request.Context(), channelID, session.GetUserID(c))
+		if err != nil {
+			response.Error(c, response.ErrorServerError)
+			return
+		}
+		if !canRead {
+			response.Error(c, response.ErrorAccessDenied)
+			return
+		}
+
+		scheduleService := service.NewScheduleService(channelRepo)
+		timeline, err := scheduleService.Timeline(c.Request.Context(), channelID)
+		if err != nil {
+			if err.Error() == "channel not found" {
+				response.Error(c, response.ErrorAccessDenied)
+				return
+			}
+			response.Error(c, response.ErrorServerError)
+			return
+		}
+
+		response.Success(c, gin.H{
+			"from":     from.UTC(),
+			"to":       to.UTC(),
+			"slots":    timeline.Between(from, to, maxScheduleSlots),
+			"schedule": scheduleInfo(timeline),
+		})
+	}
+}
+
+// SaveChannelScheduleRequest 設定頻道播出請求
+type SaveChannelScheduleRequest struct {
+	Epoch    string              `json:"epoch" example:"2024-01-01T00:00:00Z"` // 播出起點（RFC3339 或 Unix 秒數，預設為現在）
+	Loop     models.ScheduleLoop `json:"loop" example:"repeat"`                // 循環模式：repeat 或 once
+	Revision *int64              `json:"revision"`                             // 預期的頻道修訂版本（選填，也可使用 If-Match 標頭）
+}
+
+// SaveChannelSchedule 設定頻道播出起點與循環模式
+// @Summary      設定頻道播出
+// @Description  設定頻道的播出起點與循環模式（需登入，需有權限）
+// @Tags         播出
+// @Accept       json
+// @Produce      json
+// @Security     ApiAuth
+// @Param        id path string true "頻道 ID"
+// @Param        If-Match header string false "預期的頻道修訂版本（GetChannel 回傳的 ETag）"
+// @Param        request body SaveChannelScheduleRequest true "設定頻道播出請求"
+// @Success      200 {object} map[string]interface{} "成功回應"
+// @Failure      200 {object} map[string]interface{} "參數錯誤" example({"state":1,"code":0})
+// @Failure      200 {object} map[string]interface{} "權限不足" example({"state":1,"code":2})
+// @Failure      200 {object} map[string]interface{} "頻道已被其他人修改" example({"state":1,"code":3})
+// @Router       /apis/channel/{id}/schedule [post]
+func SaveChannelSchedule(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		channelID := c.Param("id")
+		var req SaveChannelScheduleRequest
+		if err := c.ShouldBindJSON(&req); err != nil || channelID == "" {
+			response.Error(c, response.ErrorRequiredField)
+			return
+		}
+
+		var epoch time.Time
+		if req.Epoch != "" {
+			t, ok := parseScheduleTime(req.Epoch)
+			if !ok {
+				response.Error(c, response.ErrorRequiredField)
+				return
+			}
+			epoch = t
+		}
+
+		userID := session.GetUserID(c)
+		if userID == "" {
+			response.Error(c, response.ErrorRequireLogin)
+			return
+		}
+
+		channelRepo := repository.NewChannelRepository(db)
+
+		// 檢查權限
+		channelService := service.NewChannelService(channelRepo, nil)
+		isAdmin, err := channelService.IsAdmin(c.Request.Context(), channelID, userID)
+		if err != nil {
+			response.Error(c, response.ErrorServerError)
+			return
+		}
+		if !isAdmin {
+			response.Error(c, response.ErrorAccessDenied)
+			return
+		}
+		revision, ok := requestRevision(c, req.Revision)
+		if !ok {
+			return
+		}
+
+		scheduleService := service.NewScheduleService(channelRepo).WithHistory(repository.NewChannelRevisionRepository(db))
+		var channelSchedule *models.ChannelSchedule
+		err = withRevision(c.Request.Context(), db, channelID, revision, func(ctx context.Context) error {
+			channelSchedule, err = scheduleService.SetSchedule(ctx, channelID, epoch, req.Loop)
+			return err
+		})
+		if revisionConflict(c, err) {
+			return
+		}
+		if err != nil {
+			if err.Error() == "invalid loop" {
+				response.Error(c, response.ErrorRequiredField)
+				return
+			}
+			response.Error(c, response.ErrorServerError)
+			return
+		}
+
+		setRevisionETag(c, db, channelID)
+		response.Success(c, gin.H{"schedule": channelSchedule})
+	}
+}
+
+// scheduleInfo 時間軸摘要（播出起點、循環模式、一輪秒數、略過的節目）
+func scheduleInfo(timeline *schedule.Timeline) gin.H {
+	return gin.H{
+		"epoch":   timeline.Epoch.UTC(),
+		"loop":    timeline.Loop,
+		"cycle":   int(timeline.Cycle() / time.Second),
+		"skipped": timeline.Skipped,
+	}
+}
+
+// parseScheduleWindow 解析 from / to 查詢參數（預設為現在起 24 小時，最長 7 天）
+func parseScheduleWindow(c *gin.Context) (time.Time, time.Time, bool) {
+	from := time.Now()
+	if fromStr := c.Query("from"); fromStr != "" {
+		t, ok := parseScheduleTime(fromStr)
+		if !ok {
+			return time.Time{}, time.Time{}, false
+		}
+		from = t
+	}
+	to := from.Add(defaultScheduleWindow)
+	if toStr := c.Query("to"); toStr != "" {
+		t, ok := parseScheduleTime(toStr)
+		if !ok || !t.After(from) {
+			return time.Time{}, time.Time{}, false
+		}
+		to = t
+	}
+	if to.Sub(from) > maxScheduleWindow {
+		to = from.Add(maxScheduleWindow)
+	}
+	return from, to, true
+}
+
+// parseScheduleTime 解析時間參數（RFC3339 或 Unix 秒數）
+func parseScheduleTime(value string) (time.Time, bool) {
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0), true
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/higgstv/higgstv-go/internal/api/response"
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/repository"
+	"github.com/higgstv/higgstv-go/internal/service"
+	"github.com/higgstv/higgstv-go/pkg/session"
+)
+
+// Search 全文搜尋頻道與節目
+// @Summary      全文搜尋
+// @Description  搜尋頻道與節目的名稱與描述，結果依相關度排序並分頁；只包含公開頻道，登入後另包含自己可讀取的頻道
+// @Tags         頻道
+// @Produce      json
+// @Param        q query string true "搜尋字串（以空白分隔多個搜尋詞，需全部符合）"
+// @Param        type query string false "搜尋類型：all（預設）、channels 或 programs"
+// @Param        start query int false "分頁起始 index"
+// @Param        limit query int false "每種結果的筆數（預設 20，最多 100）"
+// @Success      200 {object} map[string]interface{} "成功回應（含 channels 與 programs）"
+// @Failure      200 {object} map[string]interface{} "缺少搜尋字串或搜尋類型錯誤" example({"state":1,"code":0})
+// @Router       /apis/search [get]
+func Search(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := service.SearchQuery{
+			Q:      c.Query("q"),
+			Type:   c.Query("type"),
+			UserID: session.GetUserID(c),
+		}
+		if l, err := strconv.ParseInt(c.Query("limit"), 10, 64); err == nil {
+			query.Limit = l
+		}
+		if s, err := strconv.ParseInt(c.Query("start"), 10, 64); err == nil {
+			query.Skip = s
+		}
+
+		searchService := service.NewSearchService(repository.NewSearchRepository(db))
+		result, err := searchService.Search(c.Request.Context(), query)
+		if err != nil {
+			switch err.Error() {
+			case "invalid query", "invalid search type":
+				response.Error(c, response.ErrorRequiredField)
+			default:
+				response.Error(c, response.ErrorServerError)
+			}
+			return
+		}
+
+		response.Success(c, gin.H{"channels": result.Channels, "programs": result.Programs})
+	}
+}
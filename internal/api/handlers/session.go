@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/higgstv/higgstv-go/internal/api/response"
+	"github.com/higgstv/higgstv-go/pkg/session"
+)
+
+// ListSessions 列出目前使用者的登入裝置
+// @Summary      列出登入裝置
+// @Description  列出目前使用者所有有效的 Session（含 User-Agent、IP 與最後使用時間，current 表示目前的 Session）（需登入）
+// @Tags         認證
+// @Produce      json
+// @Security     ApiAuth
+// @Success      200 {object} map[string]interface{} "成功回應（含 Session 列表）"
+// @Failure      200 {object} map[string]interface{} "未登入" example({"state":1,"code":1})
+// @Router       /apis/sessions [get]
+func ListSessions() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sessions, err := session.List(c)
+		if err != nil {
+			response.Error(c, response.ErrorServerError)
+			return
+		}
+
+		response.Success(c, gin.H{"sessions": sessions})
+	}
+}
+
+// RevokeSessionRequest 撤銷 Session 請求
+type RevokeSessionRequest struct {
+	ID  string `json:"id" example:"session_id"` // 要撤銷的 Session ID
+	All bool   `json:"all" example:"false"`     // 撤銷目前 Session 以外的所有 Session
+}
+
+// RevokeSessions 撤銷登入裝置
+// @Summary      撤銷登入裝置
+// @Description  撤銷指定的 Session，或以 all 撤銷目前 Session 以外的所有 Session（需登入）
+// @Tags         認證
+// @Accept       json
+// @Produce      json
+// @Security     ApiAuth
+// @Param        request body RevokeSessionRequest true "撤銷 Session 請求"
+// @Success      200 {object} map[string]interface{} "成功回應" example({"state":0})
+// @Failure      200 {object} map[string]interface{} "缺少欄位或 Session 不存在" example({"state":1,"code":0})
+// @Router       /apis/sessions/revoke [post]
+func RevokeSessions() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req RevokeSessionRequest
+		if err := c.ShouldBindJSON(&req); err != nil || (req.ID == "" && !req.All) {
+			response.Error(c, response.ErrorRequiredField)
+			return
+		}
+
+		if req.All {
+			if err := session.RevokeOthers(c); err != nil {
+				response.Error(c, response.ErrorServerError)
+				return
+			}
+			response.Success(c, nil)
+			return
+		}
+
+		if err := session.Revoke(c, req.ID); err != nil {
+			if errors.Is(err, session.ErrNotFound) {
+				response.Error(c, response.ErrorRequiredField)
+				return
+			}
+			response.Error(c, response.ErrorServerError)
+			return
+		}
+		response.Success(c, nil)
+	}
+}
@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/higgstv/higgstv-go/internal/api/response"
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/repository"
+	"github.com/higgstv/higgstv-go/internal/service"
+)
+
+// CreateTagRequest 建立標籤請求
+type CreateTagRequest struct {
+	ID     *int              `json:"id" example:"3"`                          // 標籤 ID（選填，未提供時自動產生；可填入客戶端既有使用的數字）
+	Slug   string            `json:"slug" binding:"required" example:"music"` // 標籤代稱（小寫英數字，以 - 分隔）
+	Labels map[string]string `json:"labels" example:"zh-TW:音樂,en:Music"`      // 各語系的顯示名稱
+	Parent *int              `json:"parent" example:"1"`                      // 上層標籤 ID（選填）
+}
+
+// UpdateTagRequest 更新標籤請求
+type UpdateTagRequest struct {
+	Slug   string            `json:"slug" example:"music"` // 標籤代稱（選填）
+	Labels map[string]string `json:"labels"`               // 各語系的顯示名稱（選填，提供時整個取代）
+	Parent *int              `json:"parent" example:"1"`   // 上層標籤 ID（選填，0 表示移除上層標籤）
+}
+
+// ListTags 列出標籤
+// @Summary      列出標籤
+// @Description  列出所有標籤的 ID、slug、各語系顯示名稱與上層標籤
+// @Tags         標籤
+// @Produce      json
+// @Success      200 {object} map[string]interface{} "成功回應（含標籤列表）"
+// @Router       /apis/tags [get]
+func ListTags(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tagService := service.NewTagService(repository.NewTagRepository(db))
+		tags, err := tagService.ListTags(c.Request.Context())
+		if err != nil {
+			response.Error(c, response.ErrorServerError)
+			return
+		}
+
+		response.Success(c, gin.H{"tags": tags})
+	}
+}
+
+// CreateTag 建立標籤
+// @Summary      建立標籤
+// @Description  建立標籤，登錄任何標籤後新增頻道與節目時只接受已登錄的標籤（需登入，需為網站管理員）
+// @Tags         標籤
+// @Accept       json
+// @Produce      json
+// @Security     ApiAuth
+// @Param        request body CreateTagRequest true "建立標籤請求"
+// @Success      200 {object} map[string]interface{} "成功回應（含標籤）"
+// @Failure      200 {object} map[string]interface{} "參數錯誤或標籤已存在" example({"state":1,"code":0})
+// @Failure      200 {object} map[string]interface{} "權限不足" example({"state":1,"code":2})
+// @Router       /apis/admin/tags [post]
+func CreateTag(db database.Database, appConfig interface{}) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !isSiteAdmin(c, appConfig) {
+			response.Error(c, response.ErrorAccessDenied)
+			return
+		}
+
+		var req CreateTagRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.Error(c, response.ErrorRequiredField)
+			return
+		}
+
+		tagService := service.NewTagService(repository.NewTagRepository(db))
+		tag, err := tagService.CreateTag(c.Request.Context(), req.ID, req.Slug, req.Labels, req.Parent)
+		if err != nil {
+			respondTagError(c, err)
+			return
+		}
+
+		response.Success(c, gin.H{"tag": tag})
+	}
+}
+
+// UpdateTag 更新標籤
+// @Summary      更新標籤
+// @Description  更新標籤的 slug、各語系顯示名稱或上層標籤（需登入，需為網站管理員）
+// @Tags         標籤
+// @Accept       json
+// @Produce      json
+// @Security     ApiAuth
+// @Param        id path int true "標籤 ID"
+// @Param        request body UpdateTagRequest true "更新標籤請求"
+// @Success      200 {object} map[string]interface{} "成功回應（含標籤）"
+// @Failure      200 {object} map[string]interface{} "參數錯誤或標籤不存在" example({"state":1,"code":0})
+// @Failure      200 {object} map[string]interface{} "權限不足" example({"state":1,"code":2})
+// @Router       /apis/admin/tags/{id} [post]
+func UpdateTag(db database.Database, appConfig interface{}) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !isSiteAdmin(c, appConfig) {
+			response.Error(c, response.ErrorAccessDenied)
+			return
+		}
+
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			response.Error(c, response.ErrorRequiredField)
+			return
+		}
+		var req UpdateTagRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.Error(c, response.ErrorRequiredField)
+			return
+		}
+
+		tagService := service.NewTagService(repository.NewTagRepository(db))
+		tag, err := tagService.UpdateTag(c.Request.Context(), id, service.TagUpdate{
+			Slug:   req.Slug,
+			Labels: req.Labels,
+			Parent: req.Parent,
+		})
+		if err != nil {
+			respondTagError(c, err)
+			return
+		}
+
+		response.Success(c, gin.H{"tag": tag})
+	}
+}
+
+// DeleteTag 刪除標籤
+// @Summary      刪除標籤
+// @Description  刪除沒有下層標籤的標籤，頻道與節目中的標籤 ID 保留不變（需登入，需為網站管理員）
+// @Tags         標籤
+// @Produce      json
+// @Security     ApiAuth
+// @Param        id path int true "標籤 ID"
+// @Success      200 {object} map[string]interface{} "成功回應" example({"state":0})
+// @Failure      200 {object} map[string]interface{} "標籤不存在或仍有下層標籤" example({"state":1,"code":0})
+// @Failure      200 {object} map[string]interface{} "權限不足" example({"state":1,"code":2})
+// @Router       /apis/admin/tags/{id}/delete [post]
+func DeleteTag(db database.Database, appConfig interface{}) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !isSiteAdmin(c, appConfig) {
+			response.Error(c, response.ErrorAccessDenied)
+			return
+		}
+
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			response.Error(c, response.ErrorRequiredField)
+			return
+		}
+
+		tagService := service.NewTagService(repository.NewTagRepository(db))
+		if err := tagService.DeleteTag(c.Request.Context(), id); err != nil {
+			respondTagError(c, err)
+			return
+		}
+
+		response.Success(c, nil)
+	}
+}
+
+// respondTagError 回應標籤管理的錯誤
+func respondTagError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "invalid slug", "invalid tag id", "tag already exists", "tag not found",
+		"parent tag not found", "invalid parent tag", "tag has children":
+		response.Error(c, response.ErrorRequiredField)
+	default:
+		response.Error(c, response.ErrorServerError)
+	}
+}
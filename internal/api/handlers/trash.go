@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/higgstv/higgstv-go/internal/api/response"
+	"github.com/higgstv/higgstv-go/internal/config"
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/repository"
+	"github.com/higgstv/higgstv-go/internal/service"
+	"github.com/higgstv/higgstv-go/pkg/session"
+)
+
+// DeleteChannel 刪除頻道（移到垃圾桶）
+// @Summary      刪除頻道
+// @Description  將頻道移到垃圾桶，保留期限內可以還原，之後永久刪除頻道與所有節目（需登入，需為頻道擁有者）；未分類頻道不能刪除
+// @Tags         頻道
+// @Produce      json
+// @Security     ApiAuth
+// @Param        id path string true "頻道 ID"
+// @Success      200 {object} map[string]interface{} "成功回應" example({"state":0})
+// @Failure      200 {object} map[string]interface{} "未分類頻道不能刪除" example({"state":1,"code":0})
+// @Failure      200 {object} map[string]interface{} "權限不足或頻道不存在" example({"state":1,"code":2})
+// @Router       /apis/channel/{id}/delete [post]
+func DeleteChannel(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		channelService := service.NewChannelService(repository.NewChannelRepository(db), repository.NewUserRepository(db)).
+			WithHistory(repository.NewChannelRevisionRepository(db))
+		err := channelService.DeleteChannel(c.Request.Context(), c.Param("id"), session.GetUserID(c))
+		respondTrashError(c, err)
+	}
+}
+
+// RestoreChannel 從垃圾桶還原頻道
+// @Summary      還原頻道
+// @Description  將垃圾桶中的頻道還原（需登入，需為頻道擁有者）
+// @Tags         頻道
+// @Produce      json
+// @Security     ApiAuth
+// @Param        id path string true "頻道 ID"
+// @Success      200 {object} map[string]interface{} "成功回應" example({"state":0})
+// @Failure      200 {object} map[string]interface{} "權限不足或頻道不在垃圾桶中" example({"state":1,"code":2})
+// @Router       /apis/channel/{id}/restore [post]
+func RestoreChannel(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		channelService := service.NewChannelService(repository.NewChannelRepository(db), repository.NewUserRepository(db)).
+			WithHistory(repository.NewChannelRevisionRepository(db))
+		err := channelService.RestoreChannel(c.Request.Context(), c.Param("id"), session.GetUserID(c))
+		respondTrashError(c, err)
+	}
+}
+
+// ListTrash 列出垃圾桶中的頻道
+// @Summary      列出垃圾桶
+// @Description  列出目前使用者擁有且已刪除的頻道，含刪除時間（deleted_at）與預計永久刪除的時間（purge_at）（需登入）
+// @Tags         頻道
+// @Produce      json
+// @Security     ApiAuth
+// @Success      200 {object} map[string]interface{} "成功回應（含頻道列表）"
+// @Failure      200 {object} map[string]interface{} "未登入" example({"state":1,"code":1})
+// @Router       /apis/trash [get]
+func ListTrash(db database.Database, appConfig interface{}) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		retention := service.DefaultTrashRetention
+		if cfg, ok := appConfig.(*config.Config); ok && cfg != nil && cfg.Trash.Retention > 0 {
+			retention = cfg.Trash.Retention
+		}
+
+		channelService := service.NewChannelService(repository.NewChannelRepository(db), repository.NewUserRepository(db))
+		channels, err := channelService.ListTrash(c.Request.Context(), session.GetUserID(c), retention)
+		if err != nil {
+			response.Error(c, response.ErrorServerError)
+			return
+		}
+
+		response.Success(c, gin.H{"channels": channels})
+	}
+}
+
+// respondTrashError 回應刪除或還原頻道的結果
+func respondTrashError(c *gin.Context, err error) {
+	if err == nil {
+		response.Success(c, nil)
+		return
+	}
+	switch err.Error() {
+	case "unclassified channel cannot be deleted":
+		response.Error(c, response.ErrorRequiredField)
+	case "channel not found", "permission denied":
+		response.Error(c, response.ErrorAccessDenied)
+	default:
+		response.Error(c, response.ErrorServerError)
+	}
+}
@@ -7,15 +7,26 @@ import (
 )
 
 // RequireAuth 需要登入的中介層
-func RequireAuth() gin.HandlerFunc {
+// 接受 Cookie 或 Authorization: Bearer API Token；API Token 需具有指定的權限範圍（未指定時為 all）
+// 沒有使用 RequireAuth 的路由只接受具有 all 權限範圍的 Token 身分
+func RequireAuth(scopes ...string) gin.HandlerFunc {
+	scope := session.ScopeAll
+	if len(scopes) > 0 {
+		scope = scopes[0]
+	}
 	return func(c *gin.Context) {
+		session.SetScope(c, scope)
 		if !session.IsLoggedIn(c) {
 			// 未登入時返回錯誤響應
 			response.Error(c, response.ErrorRequireLogin)
 			c.Abort()
 			return
 		}
+		if !session.HasScope(c, scope) {
+			response.Error(c, response.ErrorAccessDenied)
+			c.Abort()
+			return
+		}
 		c.Next()
 	}
 }
-
@@ -19,6 +19,7 @@ const (
 	ErrorRequiredField = 0
 	ErrorRequireLogin  = 1
 	ErrorAccessDenied  = 2
+	ErrorConflict      = 3 // 資料已被其他人修改（修訂版本不符）
 )
 
 // Response 統一 API 回應格式
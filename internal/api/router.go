@@ -6,6 +6,7 @@ import (
 	"github.com/higgstv/higgstv-go/internal/api/handlers"
 	"github.com/higgstv/higgstv-go/internal/api/middleware"
 	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/pkg/session"
 )
 
 // SetupRoutes 設定路由
@@ -19,6 +20,24 @@ func SetupRoutes(router *gin.Engine, db database.Database, config interface{}) {
 	router.POST("/apis/change_password", middleware.RequireAuth(), handlers.ChangePassword(db))
 	router.POST("/apis/forget_password", handlers.ForgetPassword(db, config))
 	router.POST("/apis/reset_password", handlers.ResetPassword(db))
+	router.GET("/apis/sessions", middleware.RequireAuth(), handlers.ListSessions())
+	router.POST("/apis/sessions/revoke", middleware.RequireAuth(), handlers.RevokeSessions())
+
+	// 個人 API Token
+	router.POST("/apis/tokens", middleware.RequireAuth(), handlers.CreateAPIToken(db))
+	router.GET("/apis/tokens", middleware.RequireAuth(), handlers.ListAPITokens(db))
+	router.POST("/apis/tokens/:id/revoke", middleware.RequireAuth(), handlers.RevokeAPIToken(db))
+
+	// 邀請碼管理 API（網站管理員）
+	router.POST("/apis/admin/invitations", middleware.RequireAuth(), handlers.CreateInvitation(db, config))
+	router.GET("/apis/admin/invitations", middleware.RequireAuth(), handlers.ListInvitations(db, config))
+	router.POST("/apis/admin/invitations/:id/revoke", middleware.RequireAuth(), handlers.RevokeInvitation(db, config))
+
+	// 標籤 API（管理需為網站管理員）
+	router.GET("/apis/tags", handlers.ListTags(db))
+	router.POST("/apis/admin/tags", middleware.RequireAuth(), handlers.CreateTag(db, config))
+	router.POST("/apis/admin/tags/:id", middleware.RequireAuth(), handlers.UpdateTag(db, config))
+	router.POST("/apis/admin/tags/:id/delete", middleware.RequireAuth(), handlers.DeleteTag(db, config))
 
 	// 頻道相關 API
 	router.POST("/apis/addchannel", middleware.RequireAuth(), handlers.AddChannel(db))
@@ -28,6 +47,36 @@ func SetupRoutes(router *gin.Engine, db database.Database, config interface{}) {
 	router.GET("/apis/getchannelinfo/:id", middleware.RequireAuth(), handlers.GetChannelInfo(db))
 	router.POST("/apis/savechannel", middleware.RequireAuth(), handlers.SaveChannel(db))
 	router.POST("/apis/setchannelowner", middleware.RequireAuth(), handlers.SetChannelOwner(db))
+	router.POST("/apis/channel/:id/permission/grant", middleware.RequireAuth(), handlers.GrantChannelPermission(db))
+	router.POST("/apis/channel/:id/permission/revoke", middleware.RequireAuth(), handlers.RevokeChannelPermission(db))
+	router.POST("/apis/channel/:id/owner/remove", middleware.RequireAuth(), handlers.RemoveChannelOwner(db))
+	router.POST("/apis/channel/:id/owner/transfer", middleware.RequireAuth(), handlers.TransferChannelOwnership(db))
+	router.POST("/apis/channel/:id/share", middleware.RequireAuth(), handlers.ShareChannel(db, config))
+	router.GET("/apis/channel/:id/shares", middleware.RequireAuth(), handlers.ListChannelShares(db))
+	router.POST("/apis/channel/:id/shares/:share_id/revoke", middleware.RequireAuth(), handlers.RevokeChannelShare(db))
+	router.POST("/apis/shares/accept", middleware.RequireAuth(), handlers.AcceptChannelShare(db))
+	router.POST("/apis/shares/decline", middleware.RequireAuth(), handlers.DeclineChannelShare(db))
+	router.GET("/apis/channel/:id/export", handlers.ExportChannel(db))
+	router.POST("/apis/channel/:id/fork", middleware.RequireAuth(), handlers.ForkChannel(db))
+	router.POST("/apis/channel/:id/import", middleware.RequireAuth(), handlers.ImportChannel(db))
+	router.POST("/apis/channel/:id/import/file", middleware.RequireAuth(), handlers.ImportChannelFile(db))
+	router.GET("/apis/channel/:id/availability", middleware.RequireAuth(), handlers.GetChannelAvailability(db))
+	router.POST("/apis/channel/:id/delete", middleware.RequireAuth(), handlers.DeleteChannel(db))
+	router.POST("/apis/channel/:id/restore", middleware.RequireAuth(), handlers.RestoreChannel(db))
+	router.GET("/apis/trash", middleware.RequireAuth(), handlers.ListTrash(db, config))
+	router.GET("/apis/channel/:id/revisions", middleware.RequireAuth(), handlers.ListChannelRevisions(db))
+	router.GET("/apis/channel/:id/revisions/diff", middleware.RequireAuth(), handlers.DiffChannelRevisions(db))
+	router.POST("/apis/channel/:id/revisions/:revision_id/restore", middleware.RequireAuth(), handlers.RestoreChannelRevision(db))
+
+	// 線性播出相關 API
+	router.GET("/apis/channel/:id/now", handlers.GetChannelNow(db))
+	router.GET("/apis/channel/:id/schedule", handlers.GetChannelSchedule(db))
+	router.POST("/apis/channel/:id/schedule", middleware.RequireAuth(), handlers.SaveChannelSchedule(db))
+	router.GET("/apis/channel/:id/epg", handlers.GetChannelEPG(db))
+	router.GET("/apis/epg", handlers.GetEPG(db))
+
+	// 全文搜尋
+	router.GET("/apis/search", handlers.Search(db))
 
 	// 節目相關 API
 	router.POST("/apis/addprog", middleware.RequireAuth(), handlers.AddProgram(db))
@@ -37,6 +86,6 @@ func SetupRoutes(router *gin.Engine, db database.Database, config interface{}) {
 	router.POST("/apis/prog/saveorder", middleware.RequireAuth(), handlers.SaveProgramOrder(db))
 
 	// Pick API (Bookmarklet)
-	router.GET("/apis/pickprog", middleware.RequireAuth(), handlers.PickProgram(db))
+	router.GET("/apis/pickprog", middleware.RequireAuth(session.ScopePickProg), handlers.PickProgram(db))
 }
 
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	"github.com/spf13/viper"
 )
 
@@ -10,6 +12,11 @@ type Config struct {
 	Database DatabaseConfig `mapstructure:"database"`
 	Session  SessionConfig  `mapstructure:"session"`
 	Mail     MailConfig     `mapstructure:"mail"`
+	Metadata MetadataConfig `mapstructure:"metadata"`
+	Availability AvailabilityConfig `mapstructure:"availability"`
+	Import ImportConfig `mapstructure:"import"`
+	Admin AdminConfig `mapstructure:"admin"`
+	Trash TrashConfig `mapstructure:"trash"`
 }
 
 // ServerConfig 伺服器配置
@@ -20,9 +27,10 @@ type ServerConfig struct {
 
 // DatabaseConfig 資料庫配置
 type DatabaseConfig struct {
-	Type     string `mapstructure:"type"`     // mongodb 或 sqlite
-	URI      string `mapstructure:"uri"`       // MongoDB URI 或 SQLite 檔案路徑
-	Database string `mapstructure:"database"` // 資料庫名稱（MongoDB）或資料庫檔案名稱（SQLite）
+	Type        string `mapstructure:"type"`         // mongodb 或 sqlite
+	URI         string `mapstructure:"uri"`          // MongoDB URI 或 SQLite 檔案路徑
+	Database    string `mapstructure:"database"`     // 資料庫名稱（MongoDB）或資料庫檔案名稱（SQLite）
+	AutoMigrate bool   `mapstructure:"auto_migrate"` // 伺服器啟動時執行未執行的遷移（預設由 cmd/dbmigrate 執行）
 }
 
 // SessionConfig Session 配置
@@ -40,6 +48,50 @@ type MailConfig struct {
 	BaseURL      string `mapstructure:"base_url"`
 }
 
+// MetadataConfig 影片資訊查詢配置
+type MetadataConfig struct {
+	Enabled          bool          `mapstructure:"enabled"`
+	BaseURL          string        `mapstructure:"base_url"`          // 設定時改用 <base_url>/oembed 查詢（例如本機的 oEmbed 代理）
+	Timeout          time.Duration `mapstructure:"timeout"`
+	CacheTTL         time.Duration `mapstructure:"cache_ttl"`
+	RatePerHost      float64       `mapstructure:"rate_per_host"`     // 每個主機每秒請求數
+	BackfillInterval time.Duration `mapstructure:"backfill_interval"` // 0 表示不執行背景補齊
+	BackfillBatch    int           `mapstructure:"backfill_batch"`    // 每次補齊的節目數上限
+	YouTubeAPIKey    string        `mapstructure:"youtube_api_key"`   // 設定時 YouTube 改用 Data API 查詢（oEmbed 不提供時長），未設定時使用 import.youtube_api_key
+}
+
+// AvailabilityConfig 節目可播放狀態檢查配置
+type AvailabilityConfig struct {
+	Enabled      bool          `mapstructure:"enabled"`
+	BaseURL      string        `mapstructure:"base_url"`      // 設定時 oEmbed 檢查改用 <base_url>/oembed
+	Timeout      time.Duration `mapstructure:"timeout"`
+	RatePerHost  float64       `mapstructure:"rate_per_host"` // 每個主機每秒請求數
+	Interval     time.Duration `mapstructure:"interval"`      // 背景檢查間隔
+	RecheckAfter time.Duration `mapstructure:"recheck_after"` // 同一節目重新檢查的間隔
+	Batch        int           `mapstructure:"batch"`         // 每次檢查的節目數上限
+	AllowPrivate bool          `mapstructure:"allow_private"` // 允許檢查本機與內部網路位址的影片檔
+}
+
+// ImportConfig 線上播放清單匯入配置
+type ImportConfig struct {
+	BaseURL       string        `mapstructure:"base_url"`        // 設定時改用 <base_url> 取得 YouTube 播放清單（測試或自架代理）
+	YouTubeAPIKey string        `mapstructure:"youtube_api_key"` // 設定時改用 YouTube Data API 取得完整清單（未設定時使用 RSS，只包含前 15 部影片）
+	Timeout       time.Duration `mapstructure:"timeout"`
+	MaxItems      int           `mapstructure:"max_items"` // 單一播放清單最多匯入的影片數
+}
+
+// AdminConfig 網站管理員配置
+type AdminConfig struct {
+	Users []string `mapstructure:"users"` // 可管理邀請碼等全站設定的使用者名稱
+}
+
+// TrashConfig 頻道垃圾桶配置
+type TrashConfig struct {
+	Retention     time.Duration `mapstructure:"retention"`      // 刪除的頻道保留多久後永久刪除
+	PurgeInterval time.Duration `mapstructure:"purge_interval"` // 背景清除間隔（0 表示不執行）
+	PurgeBatch    int           `mapstructure:"purge_batch"`    // 每次清除的頻道數上限
+}
+
 // Load 載入配置
 func Load() (*Config, error) {
 	viper.SetConfigName("config")
@@ -57,7 +109,25 @@ func Load() (*Config, error) {
 	viper.SetDefault("database.type", "sqlite")
 	viper.SetDefault("database.uri", "file:./data/higgstv.db?cache=shared&mode=rwc")
 	viper.SetDefault("database.database", "higgstv")
+	viper.SetDefault("database.auto_migrate", false)
 	viper.SetDefault("session.secret", "change-me-in-production")
+	viper.SetDefault("metadata.enabled", false)
+	viper.SetDefault("metadata.timeout", "10s")
+	viper.SetDefault("metadata.cache_ttl", "24h")
+	viper.SetDefault("metadata.rate_per_host", 2)
+	viper.SetDefault("metadata.backfill_interval", "1h")
+	viper.SetDefault("metadata.backfill_batch", 100)
+	viper.SetDefault("availability.enabled", false)
+	viper.SetDefault("availability.timeout", "10s")
+	viper.SetDefault("availability.rate_per_host", 1)
+	viper.SetDefault("availability.interval", "1h")
+	viper.SetDefault("availability.recheck_after", "168h")
+	viper.SetDefault("availability.batch", 200)
+	viper.SetDefault("import.timeout", "10s")
+	viper.SetDefault("import.max_items", 200)
+	viper.SetDefault("trash.retention", "720h")
+	viper.SetDefault("trash.purge_interval", "1h")
+	viper.SetDefault("trash.purge_batch", 100)
 
 	if err := viper.ReadInConfig(); err != nil {
 		// 如果找不到配置檔，使用環境變數和預設值
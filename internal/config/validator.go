@@ -14,8 +14,8 @@ func (c *Config) Validate() error {
 		c.Database.Type = "sqlite" // 預設為 SQLite
 	}
 
-	if c.Database.Type != "mongodb" && c.Database.Type != "sqlite" {
-		return fmt.Errorf("database.type must be 'mongodb' or 'sqlite'")
+	if c.Database.Type != "mongodb" && c.Database.Type != "sqlite" && c.Database.Type != "postgres" {
+		return fmt.Errorf("database.type must be 'mongodb', 'sqlite' or 'postgres'")
 	}
 
 	if c.Database.URI == "" {
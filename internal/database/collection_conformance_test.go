@@ -0,0 +1,549 @@
+package database_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/higgstv/higgstv-go/internal/config"
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/migration"
+	"github.com/higgstv/higgstv-go/internal/models"
+)
+
+// 通用 Collection 介面的共用測試，預期結果以 MongoDB 的行為為準
+// 記憶體資料庫與 SQLite 一定會執行；設定的資料庫類型為 mongodb 時（HIGGSTV_DATABASE_TYPE）一併執行
+
+// collectionBackend 測試用的資料庫後端
+type collectionBackend struct {
+	name string
+	open func(t *testing.T) database.Database
+}
+
+// collectionBackends 列出要測試的資料庫後端
+func collectionBackends(t *testing.T) []collectionBackend {
+	backends := []collectionBackend{
+		{name: "memory", open: func(t *testing.T) database.Database {
+			return openCollectionDB(t, database.DatabaseConfig{Type: database.DatabaseTypeMemory})
+		}},
+		{name: "sqlite", open: func(t *testing.T) database.Database {
+			return openCollectionDB(t, database.DatabaseConfig{
+				Type: database.DatabaseTypeSQLite,
+				URI:  "file::memory:?cache=private",
+			})
+		}},
+	}
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	dbType, err := database.ParseDatabaseType(cfg.Database.Type)
+	if err != nil || dbType != database.DatabaseTypeMongoDB {
+		return backends
+	}
+
+	return append(backends, collectionBackend{name: string(dbType), open: func(t *testing.T) database.Database {
+		// 每個測試使用獨立的資料庫，結束後刪除
+		name := cfg.Database.Database + "_collection_" + strings.NewReplacer("/", "_", "Test", "").Replace(t.Name())
+		if len(name) > 60 {
+			name = name[len(name)-60:]
+		}
+		db := openCollectionDB(t, database.DatabaseConfig{Type: dbType, URI: cfg.Database.URI, Database: name})
+		mongoDB := db.(*database.MongoDBDatabase).GetDatabase()
+		require.NoError(t, mongoDB.Drop(context.Background()))
+		t.Cleanup(func() { _ = mongoDB.Drop(context.Background()) })
+		return db
+	}})
+}
+
+// openCollectionDB 建立資料庫連線與資料表，測試結束時關閉
+func openCollectionDB(t *testing.T, cfg database.DatabaseConfig) database.Database {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	db, err := database.NewDatabase(ctx, cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close(context.Background()) })
+	require.NoError(t, migration.RunMigrations(ctx, db))
+	return db
+}
+
+// runCollectionConformance 對每個資料庫後端執行 fn（每次使用新的資料庫）
+func runCollectionConformance(t *testing.T, fn func(t *testing.T, db database.Database)) {
+	for _, backend := range collectionBackends(t) {
+		backend := backend
+		t.Run(backend.name, func(t *testing.T) {
+			fn(t, backend.open(t))
+		})
+	}
+}
+
+// collectionTime 測試用的固定時間
+func collectionTime(hour int) time.Time {
+	return time.Date(2024, 1, 1, hour, 0, 0, 0, time.UTC)
+}
+
+// insertCollectionUser 以通用 Collection 介面新增使用者
+func insertCollectionUser(t *testing.T, db database.Database, id string, hour int, ownChannels ...string) {
+	user := models.User{
+		ID:           id,
+		Username:     id,
+		Email:        strings.ToUpper(id[:1]) + id[1:] + "@example.com",
+		Password:     "hashed-" + id,
+		OwnChannels:  append([]string{}, ownChannels...),
+		Created:      collectionTime(hour),
+		LastModified: collectionTime(hour),
+	}
+	require.NoError(t, db.Collection("users").InsertOne(context.Background(), user))
+}
+
+// findUserIDs 查詢使用者並回傳 ID（依 username 排序）
+func findUserIDs(t *testing.T, db database.Database, filter database.Filter) []string {
+	var users []models.User
+	err := db.Collection("users").Find(context.Background(), filter, database.Sort{{Field: "username", Order: 1}}, 0, 0, &users)
+	require.NoError(t, err)
+	ids := []string{}
+	for _, user := range users {
+		ids = append(ids, user.ID)
+	}
+	return ids
+}
+
+// findChannelIDs 查詢頻道並回傳 ID（依 name 排序）
+func findChannelIDs(t *testing.T, db database.Database, filter database.Filter) []string {
+	var channels []models.Channel
+	err := db.Collection("channels").Find(context.Background(), filter, database.Sort{{Field: "name", Order: 1}}, 0, 0, &channels)
+	require.NoError(t, err)
+	ids := []string{}
+	for _, channel := range channels {
+		ids = append(ids, channel.ID)
+	}
+	return ids
+}
+
+// programIDs 取得節目 ID
+func programIDs(programs []models.Program) []int {
+	ids := []int{}
+	for _, program := range programs {
+		ids = append(ids, program.ID)
+	}
+	return ids
+}
+
+func TestCollectionConformance_Users(t *testing.T) {
+	runCollectionConformance(t, func(t *testing.T, db database.Database) {
+		ctx := context.Background()
+		users := db.Collection("users")
+		insertCollectionUser(t, db, "alice", 1, "ch1", "ch2")
+		insertCollectionUser(t, db, "bob", 2, "ch2")
+		insertCollectionUser(t, db, "carol", 3)
+		require.NoError(t, users.UpdateOne(ctx, database.Filter{"_id": "alice"}, database.Update{
+			Set: map[string]interface{}{"access_key": "key-alice"},
+		}))
+
+		t.Run("FindOne", func(t *testing.T) {
+			var user models.User
+			require.NoError(t, users.FindOne(ctx, database.Filter{"username": "bob"}, &user))
+			assert.Equal(t, "bob", user.ID)
+			assert.Equal(t, []string{"ch2"}, user.OwnChannels)
+			assert.True(t, user.Created.Equal(collectionTime(2)))
+			assert.Nil(t, user.AccessKey)
+
+			err := users.FindOne(ctx, database.Filter{"username": "nobody"}, &user)
+			assert.True(t, database.IsNotFound(err))
+		})
+
+		t.Run("Filters", func(t *testing.T) {
+			cases := []struct {
+				name   string
+				filter database.Filter
+				want   []string
+			}{
+				{"empty", database.Filter{}, []string{"alice", "bob", "carol"}},
+				{"$in", database.Filter{"username": database.Filter{"$in": []string{"alice", "carol"}}}, []string{"alice", "carol"}},
+				{"$nin", database.Filter{"username": database.Filter{"$nin": []string{"alice"}}}, []string{"bob", "carol"}},
+				{"$ne", database.Filter{"username": database.Filter{"$ne": "bob"}}, []string{"alice", "carol"}},
+				{"$regex", database.Filter{"email": database.Filter{"$regex": "^b", "$options": "i"}}, []string{"bob"}},
+				{"$regex case sensitive", database.Filter{"email": database.Filter{"$regex": "^b"}}, []string{}},
+				{"$exists", database.Filter{"access_key": database.Filter{"$exists": true}}, []string{"alice"}},
+				{"$exists false", database.Filter{"access_key": database.Filter{"$exists": false}}, []string{"bob", "carol"}},
+				{"null", database.Filter{"access_key": nil}, []string{"bob", "carol"}},
+				{"$or", database.Filter{"$or": []database.Filter{
+					{"username": "alice"},
+					{"email": "Carol@example.com"},
+				}}, []string{"alice", "carol"}},
+				{"$gte", database.Filter{"created": database.Filter{"$gte": collectionTime(2)}}, []string{"bob", "carol"}},
+				{"$lt", database.Filter{"created": database.Filter{"$lt": collectionTime(2)}}, []string{"alice"}},
+				{"array element", database.Filter{"own_channels": "ch2"}, []string{"alice", "bob"}},
+				{"array $in", database.Filter{"own_channels": database.Filter{"$in": []string{"ch1", "ch9"}}}, []string{"alice"}},
+				{"array $ne", database.Filter{"own_channels": database.Filter{"$ne": "ch1"}}, []string{"bob", "carol"}},
+				{"array $nin", database.Filter{"own_channels": database.Filter{"$nin": []string{"ch2"}}}, []string{"carol"}},
+				{"array index", database.Filter{"own_channels.1": database.Filter{"$exists": true}}, []string{"alice"}},
+				{"array index missing", database.Filter{"own_channels.0": database.Filter{"$exists": false}}, []string{"carol"}},
+			}
+			for _, tc := range cases {
+				assert.Equal(t, tc.want, findUserIDs(t, db, tc.filter), tc.name)
+			}
+		})
+
+		t.Run("SortLimitSkip", func(t *testing.T) {
+			var found []models.User
+			require.NoError(t, users.Find(ctx, database.Filter{}, database.Sort{{Field: "created", Order: -1}}, 1, 1, &found))
+			require.Len(t, found, 1)
+			assert.Equal(t, "bob", found[0].ID)
+
+			require.NoError(t, users.Find(ctx, database.Filter{}, database.Sort{{Field: "created", Order: -1}}, 0, 2, &found))
+			require.Len(t, found, 1)
+			assert.Equal(t, "alice", found[0].ID)
+		})
+
+		t.Run("CountDocuments", func(t *testing.T) {
+			count, err := users.CountDocuments(ctx, database.Filter{"own_channels": "ch2"})
+			require.NoError(t, err)
+			assert.Equal(t, int64(2), count)
+		})
+
+		t.Run("ArrayUpdates", func(t *testing.T) {
+			require.NoError(t, users.UpdateOne(ctx, database.Filter{"_id": "carol"}, database.Update{
+				Set:      map[string]interface{}{"unclassified_channel": "ch3"},
+				AddToSet: map[string]interface{}{"own_channels": database.Filter{"$each": []string{"ch3", "ch4", "ch3"}}},
+			}))
+			require.NoError(t, users.UpdateOne(ctx, database.Filter{"_id": "carol"}, database.Update{
+				AddToSet: map[string]interface{}{"own_channels": "ch4"},
+				Push:     map[string]interface{}{},
+			}))
+			require.NoError(t, users.UpdateOne(ctx, database.Filter{"_id": "carol"}, database.Update{
+				Push: map[string]interface{}{"own_channels": "ch5"},
+			}))
+			require.NoError(t, users.UpdateOne(ctx, database.Filter{"_id": "carol"}, database.Update{
+				Pull: map[string]interface{}{"own_channels": "ch4"},
+			}))
+
+			var user models.User
+			require.NoError(t, users.FindOne(ctx, database.Filter{"_id": "carol"}, &user))
+			assert.Equal(t, []string{"ch3", "ch5"}, user.OwnChannels)
+			require.NotNil(t, user.UnclassifiedChannel)
+			assert.Equal(t, "ch3", *user.UnclassifiedChannel)
+
+			require.NoError(t, users.UpdateOne(ctx, database.Filter{"_id": "carol"}, database.Update{
+				Pull: map[string]interface{}{"own_channels": database.Filter{"$in": []string{"ch3", "ch5"}}},
+				Set:  map[string]interface{}{"unclassified_channel": nil},
+			}))
+			var cleared models.User
+			require.NoError(t, users.FindOne(ctx, database.Filter{"_id": "carol"}, &cleared))
+			assert.Empty(t, cleared.OwnChannels)
+			assert.Nil(t, cleared.UnclassifiedChannel)
+		})
+
+		t.Run("UpdateOneMatched", func(t *testing.T) {
+			matched, err := users.UpdateOneMatched(ctx, database.Filter{"_id": "nobody"}, database.Update{
+				Set: map[string]interface{}{"password": "x"},
+			})
+			require.NoError(t, err)
+			assert.False(t, matched)
+
+			matched, err = users.UpdateOneMatched(ctx, database.Filter{"_id": "bob"}, database.Update{
+				Set: map[string]interface{}{"password": "changed"},
+			})
+			require.NoError(t, err)
+			assert.True(t, matched)
+		})
+
+		t.Run("Delete", func(t *testing.T) {
+			require.NoError(t, users.DeleteOne(ctx, database.Filter{"username": database.Filter{"$in": []string{"alice", "bob"}}}))
+			assert.Len(t, findUserIDs(t, db, database.Filter{}), 2)
+
+			require.NoError(t, users.DeleteMany(ctx, database.Filter{"created": database.Filter{"$gte": collectionTime(1)}}))
+			assert.Empty(t, findUserIDs(t, db, database.Filter{}))
+		})
+	})
+}
+
+func TestCollectionConformance_Channels(t *testing.T) {
+	runCollectionConformance(t, func(t *testing.T, db database.Database) {
+		ctx := context.Background()
+		channels := db.Collection("channels")
+		insertCollectionUser(t, db, "u1", 1)
+		insertCollectionUser(t, db, "u2", 2)
+
+		require.NoError(t, channels.InsertOne(ctx, models.Channel{
+			ID:    "c1",
+			Type:  models.ChannelTypeDefault,
+			Name:  "Alpha",
+			Tags:  []int{1, 2},
+			Cover: &models.ChannelCover{Default: "cover.png"},
+			Contents: []models.Program{
+				{ID: 1, Name: "Intro", Type: models.ProgramTypeYouTube, YouTubeID: "a", Tags: []int{1}},
+				{ID: 2, Name: "Outro", Type: models.ProgramTypeYouTube, YouTubeID: "b", Tags: []int{}},
+			},
+			ContentsOrder: []int{2, 1},
+			Owners:        []string{"u1"},
+			Permission: []models.ChannelPermission{
+				{UserID: "u1", Admin: true, Read: true, Write: true},
+				{UserID: "u2", Read: true},
+			},
+			Visibility:   models.ChannelVisibilityPublic,
+			Created:      collectionTime(1),
+			LastModified: collectionTime(1),
+		}))
+		require.NoError(t, channels.InsertOne(ctx, models.Channel{
+			ID:            "c2",
+			Type:          models.ChannelTypeDefault,
+			Name:          "Beta",
+			Tags:          []int{3},
+			Contents:      []models.Program{},
+			ContentsOrder: []int{},
+			Owners:        []string{"u2"},
+			Permission:    []models.ChannelPermission{},
+			Visibility:    models.ChannelVisibilityPrivate,
+			Created:       collectionTime(2),
+			LastModified:  collectionTime(2),
+		}))
+
+		t.Run("FindOne", func(t *testing.T) {
+			var channel models.Channel
+			require.NoError(t, channels.FindOne(ctx, database.Filter{"_id": "c1"}, &channel))
+			assert.Equal(t, "Alpha", channel.Name)
+			assert.Equal(t, []int{1, 2}, channel.Tags)
+			require.NotNil(t, channel.Cover)
+			assert.Equal(t, "cover.png", channel.Cover.Default)
+			assert.Equal(t, []int{1, 2}, programIDs(channel.Contents))
+			assert.Equal(t, []int{1}, channel.Contents[0].Tags)
+			assert.Equal(t, []int{2, 1}, channel.ContentsOrder)
+			assert.Equal(t, []string{"u1"}, channel.Owners)
+			assert.Equal(t, []models.ChannelPermission{
+				{UserID: "u1", Admin: true, Read: true, Write: true},
+				{UserID: "u2", Read: true},
+			}, channel.Permission)
+			assert.Nil(t, channel.Schedule)
+			assert.False(t, channel.HideUnavailable)
+		})
+
+		t.Run("Filters", func(t *testing.T) {
+			cases := []struct {
+				name   string
+				filter database.Filter
+				want   []string
+			}{
+				{"has contents", database.Filter{"contents.0": database.Filter{"$exists": true}}, []string{"c1"}},
+				{"no contents", database.Filter{"contents.0": database.Filter{"$exists": false}}, []string{"c2"}},
+				{"program id", database.Filter{"contents._id": 2}, []string{"c1"}},
+				{"program name $regex", database.Filter{"contents.name": database.Filter{"$regex": "^out", "$options": "i"}}, []string{"c1"}},
+				{"program tags", database.Filter{"contents.tags": 1}, []string{"c1"}},
+				{"permission user", database.Filter{"permission.user_id": "u2"}, []string{"c1"}},
+				{"permission user $ne", database.Filter{"permission.user_id": database.Filter{"$ne": "u2"}}, []string{"c2"}},
+				{"owners", database.Filter{"owners": "u2"}, []string{"c2"}},
+				{"tags $in", database.Filter{"tags": database.Filter{"$in": []int{2, 3}}}, []string{"c1", "c2"}},
+				{"tags $nin", database.Filter{"tags": database.Filter{"$nin": []int{3}}}, []string{"c1"}},
+				{"nested field", database.Filter{"cover.default": "cover.png"}, []string{"c1"}},
+				{"nested document $exists", database.Filter{"cover": database.Filter{"$exists": false}}, []string{"c2"}},
+				{"deleted_at null", database.Filter{"deleted_at": nil, "visibility": "private"}, []string{"c2"}},
+				{"$or", database.Filter{"$or": []database.Filter{
+					{"owners": "u1"},
+					{"tags": 3},
+				}, "type": database.Filter{"$nin": []string{"unclassified"}}}, []string{"c1", "c2"}},
+			}
+			for _, tc := range cases {
+				assert.Equal(t, tc.want, findChannelIDs(t, db, tc.filter), tc.name)
+			}
+		})
+
+		t.Run("PositionalSet", func(t *testing.T) {
+			require.NoError(t, channels.UpdateOne(ctx, database.Filter{"_id": "c1", "contents._id": 1}, database.Update{
+				Set: map[string]interface{}{
+					"contents.$.name":     "Opening",
+					"contents.$.duration": 90,
+					"hide_unavailable":    true,
+				},
+			}))
+
+			var channel models.Channel
+			require.NoError(t, channels.FindOne(ctx, database.Filter{"_id": "c1"}, &channel))
+			assert.Equal(t, "Opening", channel.Contents[0].Name)
+			assert.Equal(t, 90, channel.Contents[0].Duration)
+			assert.Equal(t, "Outro", channel.Contents[1].Name)
+			assert.True(t, channel.HideUnavailable)
+		})
+
+		t.Run("PushPull", func(t *testing.T) {
+			require.NoError(t, channels.UpdateOne(ctx, database.Filter{"_id": "c1"}, database.Update{
+				Push: map[string]interface{}{
+					"contents":       models.Program{ID: 3, Name: "Extra", Type: models.ProgramTypeYouTube, YouTubeID: "c", Tags: []int{}},
+					"contents_order": 3,
+				},
+				AddToSet: map[string]interface{}{"tags": database.Filter{"$each": []int{2, 4}}},
+				Inc:      map[string]interface{}{"revision": 1},
+			}))
+
+			var channel models.Channel
+			require.NoError(t, channels.FindOne(ctx, database.Filter{"_id": "c1"}, &channel))
+			assert.Equal(t, []int{1, 2, 3}, programIDs(channel.Contents))
+			assert.Equal(t, []int{2, 1, 3}, channel.ContentsOrder)
+			assert.Equal(t, []int{1, 2, 4}, channel.Tags)
+			assert.Equal(t, int64(1), channel.Revision)
+
+			require.NoError(t, channels.UpdateOne(ctx, database.Filter{"_id": "c1"}, database.Update{
+				Pull: map[string]interface{}{
+					"contents":       database.Filter{"_id": database.Filter{"$in": []int{1}}},
+					"contents_order": 1,
+					"permission":     database.Filter{"user_id": "u2"},
+				},
+			}))
+			require.NoError(t, channels.FindOne(ctx, database.Filter{"_id": "c1"}, &channel))
+			assert.Equal(t, []int{2, 3}, programIDs(channel.Contents))
+			assert.Equal(t, []int{2, 3}, channel.ContentsOrder)
+			require.Len(t, channel.Permission, 1)
+			assert.Equal(t, "u1", channel.Permission[0].UserID)
+		})
+
+		t.Run("SetDocuments", func(t *testing.T) {
+			epoch := collectionTime(5)
+			require.NoError(t, channels.UpdateOne(ctx, database.Filter{"_id": "c2"}, database.Update{
+				Set: map[string]interface{}{
+					"cover":      models.ChannelCover{Default: "beta.png"},
+					"schedule":   models.ChannelSchedule{Epoch: epoch, Loop: models.ScheduleLoopRepeat},
+					"owners":     []string{"u1", "u2"},
+					"deleted_at": epoch,
+				},
+			}))
+
+			var channel models.Channel
+			require.NoError(t, channels.FindOne(ctx, database.Filter{"_id": "c2"}, &channel))
+			require.NotNil(t, channel.Cover)
+			assert.Equal(t, "beta.png", channel.Cover.Default)
+			require.NotNil(t, channel.Schedule)
+			assert.True(t, channel.Schedule.Epoch.Equal(epoch))
+			assert.Equal(t, models.ScheduleLoopRepeat, channel.Schedule.Loop)
+			assert.Equal(t, []string{"u1", "u2"}, channel.Owners)
+			require.NotNil(t, channel.DeletedAt)
+			assert.True(t, channel.DeletedAt.Equal(epoch))
+
+			count, err := channels.CountDocuments(ctx, database.Filter{"owners": "u1", "deleted_at": nil})
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), count)
+		})
+
+		t.Run("Delete", func(t *testing.T) {
+			require.NoError(t, channels.DeleteOne(ctx, database.Filter{"_id": "c2"}))
+			assert.Equal(t, []string{"c1"}, findChannelIDs(t, db, database.Filter{}))
+		})
+	})
+}
+
+func TestCollectionConformance_Counters(t *testing.T) {
+	runCollectionConformance(t, func(t *testing.T, db database.Database) {
+		ctx := context.Background()
+		counters := db.Collection("counters")
+		inc := database.Update{Inc: map[string]interface{}{"seq": 1}}
+
+		var counter struct {
+			Seq int `bson:"seq"`
+		}
+		require.NoError(t, counters.FindOneAndUpdate(ctx, database.Filter{"_id": "program_id"}, inc, true, &counter))
+		assert.Equal(t, 1, counter.Seq)
+		require.NoError(t, counters.FindOneAndUpdate(ctx, database.Filter{"_id": "program_id"}, inc, true, &counter))
+		assert.Equal(t, 2, counter.Seq)
+		require.NoError(t, counters.FindOneAndUpdate(ctx, database.Filter{"_id": "program_id"}, inc, false, &counter))
+		assert.Equal(t, 2, counter.Seq)
+		require.NoError(t, counters.FindOne(ctx, database.Filter{"_id": "program_id"}, &counter))
+		assert.Equal(t, 3, counter.Seq)
+
+		// upsert 且回傳更新前的文件時，沒有文件可以回傳
+		err := counters.FindOneAndUpdate(ctx, database.Filter{"_id": "tag_id"}, inc, false, &counter)
+		assert.True(t, database.IsNotFound(err))
+		require.NoError(t, counters.FindOne(ctx, database.Filter{"_id": "tag_id"}, &counter))
+		assert.Equal(t, 1, counter.Seq)
+
+		require.NoError(t, counters.DeleteOne(ctx, database.Filter{"_id": "tag_id"}))
+		require.NoError(t, counters.InsertOne(ctx, map[string]interface{}{"_id": "tag_id", "seq": 10}))
+		require.NoError(t, counters.FindOne(ctx, database.Filter{"_id": "tag_id"}, &counter))
+		assert.Equal(t, 10, counter.Seq)
+	})
+}
+
+func TestCollectionConformance_OtherCollections(t *testing.T) {
+	runCollectionConformance(t, func(t *testing.T, db database.Database) {
+		ctx := context.Background()
+
+		t.Run("Invitations", func(t *testing.T) {
+			invitations := db.Collection("invitations")
+			// 移除遷移建立的舊版邀請碼
+			require.NoError(t, invitations.DeleteMany(ctx, database.Filter{}))
+			expires := collectionTime(10)
+			require.NoError(t, invitations.InsertOne(ctx, models.Invitation{
+				ID: "i1", Code: "CODE1", MaxUses: 1, ExpiresAt: &expires, Created: collectionTime(1), LastModified: collectionTime(1),
+			}))
+			require.NoError(t, invitations.InsertOne(ctx, models.Invitation{
+				ID: "i2", Code: "CODE2", Revoked: true, Created: collectionTime(2), LastModified: collectionTime(2),
+			}))
+
+			var found []models.Invitation
+			require.NoError(t, invitations.Find(ctx, database.Filter{"revoked": false}, nil, 0, 0, &found))
+			require.Len(t, found, 1)
+			assert.Equal(t, "i1", found[0].ID)
+			require.NotNil(t, found[0].ExpiresAt)
+			assert.True(t, found[0].ExpiresAt.Equal(expires))
+
+			require.NoError(t, invitations.Find(ctx, database.Filter{"expires_at": database.Filter{"$exists": false}}, nil, 0, 0, &found))
+			require.Len(t, found, 1)
+			assert.Equal(t, "i2", found[0].ID)
+			assert.True(t, found[0].Revoked)
+		})
+
+		t.Run("Tags", func(t *testing.T) {
+			tags := db.Collection("tags")
+			parent := 1
+			require.NoError(t, tags.InsertOne(ctx, models.Tag{
+				ID: 1, Slug: "music", Labels: map[string]string{"zh-TW": "音樂", "en": "Music"}, Created: collectionTime(1), LastModified: collectionTime(1),
+			}))
+			require.NoError(t, tags.InsertOne(ctx, models.Tag{
+				ID: 2, Slug: "jazz", Labels: map[string]string{}, Parent: &parent, Created: collectionTime(2), LastModified: collectionTime(2),
+			}))
+
+			var tag models.Tag
+			require.NoError(t, tags.FindOne(ctx, database.Filter{"slug": "music"}, &tag))
+			assert.Equal(t, map[string]string{"zh-TW": "音樂", "en": "Music"}, tag.Labels)
+			assert.Nil(t, tag.Parent)
+
+			require.NoError(t, tags.UpdateOne(ctx, database.Filter{"_id": 2}, database.Update{
+				Set: map[string]interface{}{"labels": map[string]string{"en": "Jazz"}},
+			}))
+			var child models.Tag
+			require.NoError(t, tags.FindOne(ctx, database.Filter{"parent": 1}, &child))
+			assert.Equal(t, "jazz", child.Slug)
+			assert.Equal(t, map[string]string{"en": "Jazz"}, child.Labels)
+		})
+
+		t.Run("APITokens", func(t *testing.T) {
+			tokens := db.Collection("api_tokens")
+			require.NoError(t, tokens.InsertOne(ctx, models.APIToken{
+				ID: "t1", UserID: "u1", Name: "cli", TokenHash: "hash", Scopes: []string{"read", "write"}, Created: collectionTime(1),
+			}))
+
+			var token models.APIToken
+			require.NoError(t, tokens.FindOne(ctx, database.Filter{"token_hash": "hash"}, &token))
+			assert.Equal(t, []string{"read", "write"}, token.Scopes)
+			assert.Nil(t, token.LastUsed)
+		})
+
+		t.Run("Migrations", func(t *testing.T) {
+			migrations := db.Collection("migrations")
+			require.NoError(t, migrations.InsertOne(ctx, map[string]interface{}{
+				"_id": "001_initial", "description": "initial", "executed_at": collectionTime(1),
+			}))
+
+			var executed []struct {
+				ID          string    `bson:"_id"`
+				Description string    `bson:"description"`
+				ExecutedAt  time.Time `bson:"executed_at"`
+			}
+			require.NoError(t, migrations.Find(ctx, database.Filter{"_id": "001_initial"}, database.Sort{{Field: "_id", Order: 1}}, 0, 0, &executed))
+			require.Len(t, executed, 1)
+			assert.Equal(t, "001_initial", executed[0].ID)
+			assert.True(t, executed[0].ExecutedAt.Equal(collectionTime(1)))
+		})
+	})
+}
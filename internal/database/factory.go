@@ -12,6 +12,10 @@ func NewDatabase(ctx context.Context, config DatabaseConfig) (Database, error) {
 		return NewMongoDBDatabase(ctx, config)
 	case DatabaseTypeSQLite:
 		return NewSQLiteDatabase(ctx, config)
+	case DatabaseTypePostgres:
+		return NewPostgresDatabase(ctx, config)
+	case DatabaseTypeMemory:
+		return NewMemoryDatabase(ctx, config)
 	default:
 		return nil, fmt.Errorf("unsupported database type: %s", config.Type)
 	}
@@ -24,6 +28,10 @@ func ParseDatabaseType(s string) (DatabaseType, error) {
 		return DatabaseTypeMongoDB, nil
 	case "sqlite", "sqlite3":
 		return DatabaseTypeSQLite, nil
+	case "postgres", "postgresql":
+		return DatabaseTypePostgres, nil
+	case "memory":
+		return DatabaseTypeMemory, nil
 	default:
 		return "", fmt.Errorf("unknown database type: %s", s)
 	}
@@ -97,6 +97,113 @@ func EnsureIndexes(ctx context.Context, db Database) error {
 		}
 	}
 
+	// invitations code 索引（SQLite 使用 UNIQUE 欄位）
+	if db.Type() == DatabaseTypeMongoDB {
+		if err := db.Collection("invitations").CreateIndex(ctx, map[string]interface{}{
+			"code": 1,
+		}, IndexOptions{
+			Unique: true,
+			Name:   "code_1",
+		}); err != nil {
+			_ = err
+		}
+	}
+
+	// sessions user_id 索引（SQLite 於建立資料表時建立）
+	if db.Type() == DatabaseTypeMongoDB {
+		if err := db.Collection("sessions").CreateIndex(ctx, map[string]interface{}{
+			"user_id": 1,
+		}, IndexOptions{
+			Name: "user_id_1",
+		}); err != nil {
+			_ = err
+		}
+	}
+
+	// api_tokens token_hash 與 user_id 索引（SQLite 於建立資料表時建立）
+	if db.Type() == DatabaseTypeMongoDB {
+		if err := db.Collection("api_tokens").CreateIndex(ctx, map[string]interface{}{
+			"token_hash": 1,
+		}, IndexOptions{
+			Unique: true,
+			Name:   "token_hash_1",
+		}); err != nil {
+			_ = err
+		}
+		if err := db.Collection("api_tokens").CreateIndex(ctx, map[string]interface{}{
+			"user_id": 1,
+		}, IndexOptions{
+			Name: "user_id_1",
+		}); err != nil {
+			_ = err
+		}
+	}
+
+	// channel_shares token_hash、channel_id 與 email 索引（SQLite 於建立資料表時建立）
+	if db.Type() == DatabaseTypeMongoDB {
+		if err := db.Collection("channel_shares").CreateIndex(ctx, map[string]interface{}{
+			"token_hash": 1,
+		}, IndexOptions{
+			Unique: true,
+			Name:   "token_hash_1",
+		}); err != nil {
+			_ = err
+		}
+		if err := db.Collection("channel_shares").CreateIndex(ctx, map[string]interface{}{
+			"channel_id": 1,
+		}, IndexOptions{
+			Name: "channel_id_1",
+		}); err != nil {
+			_ = err
+		}
+		if err := db.Collection("channel_shares").CreateIndex(ctx, map[string]interface{}{
+			"email": 1,
+		}, IndexOptions{
+			Name: "email_1",
+		}); err != nil {
+			_ = err
+		}
+	}
+
+	// channel_revisions channel_id 索引（SQLite 於建立資料表時建立）
+	if db.Type() == DatabaseTypeMongoDB {
+		if err := db.Collection("channel_revisions").CreateIndex(ctx, map[string]interface{}{
+			"channel_id": 1,
+		}, IndexOptions{
+			Name: "channel_id_1",
+		}); err != nil {
+			_ = err
+		}
+	}
+
+	// tags slug 索引（SQLite 使用 UNIQUE 欄位）
+	if db.Type() == DatabaseTypeMongoDB {
+		if err := db.Collection("tags").CreateIndex(ctx, map[string]interface{}{
+			"slug": 1,
+		}, IndexOptions{
+			Unique: true,
+			Name:   "slug_1",
+		}); err != nil {
+			_ = err
+		}
+	}
+
+	// channels 全文索引（頻道與節目的名稱、描述，SQLite 使用 FTS5）
+	if db.Type() == DatabaseTypeMongoDB {
+		if err := db.Collection("channels").CreateIndex(ctx, map[string]interface{}{
+			"name":          "text",
+			"desc":          "text",
+			"contents.name": "text",
+			"contents.desc": "text",
+		}, IndexOptions{
+			Name:            "search_text",
+			Weights:         map[string]int{"name": 10, "desc": 2, "contents.name": 5, "contents.desc": 1},
+			DefaultLanguage: "none",
+		}); err != nil {
+			_ = err
+		}
+	}
+
 	return nil
 }
 
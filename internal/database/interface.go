@@ -2,6 +2,8 @@ package database
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/higgstv/higgstv-go/internal/models"
 )
@@ -10,8 +12,10 @@ import (
 type DatabaseType string
 
 const (
-	DatabaseTypeMongoDB DatabaseType = "mongodb"
-	DatabaseTypeSQLite  DatabaseType = "sqlite"
+	DatabaseTypeMongoDB  DatabaseType = "mongodb"
+	DatabaseTypeSQLite   DatabaseType = "sqlite"
+	DatabaseTypePostgres DatabaseType = "postgres"
+	DatabaseTypeMemory   DatabaseType = "memory" // 僅保存在記憶體中（供測試使用）
 )
 
 // Filter 查詢過濾器（通用於 MongoDB 和 SQLite）
@@ -32,6 +36,7 @@ type Update struct {
 	AddToSet map[string]interface{}  // $addToSet 操作（僅 MongoDB，SQLite 需要手動處理）
 	Pull     map[string]interface{}  // $pull 操作（僅 MongoDB，SQLite 需要手動處理）
 	Push     map[string]interface{}  // $push 操作（僅 MongoDB，SQLite 需要手動處理）
+	Inc      map[string]interface{}  // $inc 操作（僅 MongoDB，SQLite 需要手動處理）
 }
 
 // Database 資料庫抽象介面
@@ -50,6 +55,15 @@ type Database interface {
 
 	// BeginTx 開始交易（如果支援）
 	BeginTx(ctx context.Context) (Tx, error)
+
+	// WithTx 在交易中執行 fn（fn 回傳錯誤時回滾）
+	// fn 內的 Repository 與 Collection 操作必須使用傳入的 ctx 才會包含在交易中，巢狀呼叫沿用外層交易
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// Transactor 可在交易中執行多個 Repository 操作（Database 皆實作，供 Service 使用）
+type Transactor interface {
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
 }
 
 // Collection 集合/表操作介面
@@ -66,9 +80,15 @@ type Collection interface {
 	// UpdateOne 更新單筆文件
 	UpdateOne(ctx context.Context, filter Filter, update Update) error
 
+	// UpdateOneMatched 更新單筆文件，並回傳是否有符合條件的文件（用於條件式更新）
+	UpdateOneMatched(ctx context.Context, filter Filter, update Update) (bool, error)
+
 	// DeleteOne 刪除單筆文件
 	DeleteOne(ctx context.Context, filter Filter) error
 
+	// DeleteMany 刪除多筆文件
+	DeleteMany(ctx context.Context, filter Filter) error
+
 	// CountDocuments 計算文件數量
 	CountDocuments(ctx context.Context, filter Filter) (int64, error)
 
@@ -87,6 +107,10 @@ type IndexOptions struct {
 	Unique bool
 	Name   string
 	Sparse bool // 僅 MongoDB，SQLite 不支援
+
+	// 僅 MongoDB 全文索引（keys 的值為 "text"）
+	Weights         map[string]int
+	DefaultLanguage string
 }
 
 // IndexInfo 索引資訊
@@ -122,6 +146,7 @@ type DatabaseConfig struct {
 type UserRepository interface {
 	FindByUsername(ctx context.Context, username string) (*models.User, error)
 	FindByEmail(ctx context.Context, email string) (*models.User, error)
+	FindByID(ctx context.Context, id string) (*models.User, error)
 	Exists(ctx context.Context, username, email string) (bool, error)
 	Create(ctx context.Context, user *models.User) error
 	UpdatePassword(ctx context.Context, userID, hashedPassword string) error
@@ -138,19 +163,99 @@ type ChannelRepository interface {
 	Create(ctx context.Context, channel *models.Channel) error
 	Update(ctx context.Context, id string, update map[string]interface{}) error
 	ListChannels(ctx context.Context, filter Filter, sort Sort, limit, skip int64) ([]models.Channel, error)
+	// LoadContents 為 ListChannels 列出的頻道批次載入節目與節目順序（列表已包含節目的後端不做任何事）
+	LoadContents(ctx context.Context, channels []models.Channel) error
 	IsAdmin(ctx context.Context, channelID, userID string) (bool, error)
+	GetPermission(ctx context.Context, channelID, userID string) (models.ChannelPermission, error)
+	SetPermission(ctx context.Context, channelID string, permission models.ChannelPermission) error
 	AddOwners(ctx context.Context, channelID string, userIDs []string) error
+	SoftDelete(ctx context.Context, id string, deletedAt time.Time) error
+	Restore(ctx context.Context, id string) error
+	FindDeleted(ctx context.Context, id string) (*models.Channel, error)
+	ListDeleted(ctx context.Context, userID string) ([]models.Channel, error)
+	ListExpired(ctx context.Context, before time.Time, limit int64) ([]string, error)
+	Purge(ctx context.Context, id string) error
+	RemoveOwner(ctx context.Context, channelID, userID string) error
+	TransferOwnership(ctx context.Context, channelID, fromUserID, toUserID string) error
+	GetRevision(ctx context.Context, channelID string) (int64, error)
+	ClaimRevision(ctx context.Context, channelID string, revision int64) error
+	IncrementForkCount(ctx context.Context, channelID string) error
 }
 
 // ProgramRepository 節目 Repository 介面（抽象層）
 type ProgramRepository interface {
 	GetNextProgramID(ctx context.Context) (int, error)
 	AddProgram(ctx context.Context, channelID string, program *models.Program) error
+	AddPrograms(ctx context.Context, channelID string, programs []*models.Program) error
+	// RestorePrograms 以節目原本的 ID 與建立時間新增節目（還原歷史版本使用，節目 ID 不能已被其他節目使用）
+	RestorePrograms(ctx context.Context, channelID string, programs []*models.Program) error
 	UpdateProgram(ctx context.Context, channelID string, programID int, update map[string]interface{}) error
+	// UpdateProgramStatus 更新背景工作維護的節目欄位（可播放狀態、影片資訊），不修改 last_modified 與頻道的修訂版本
+	UpdateProgramStatus(ctx context.Context, channelID string, programID int, update map[string]interface{}) error
 	DeletePrograms(ctx context.Context, channelID string, programIDs []int) error
 	SetOrder(ctx context.Context, channelID string, order []int) error
 }
 
+// TagRepository 標籤 Repository 介面（抽象層）
+type TagRepository interface {
+	Create(ctx context.Context, tag *models.Tag) error
+	FindByID(ctx context.Context, id int) (*models.Tag, error)
+	FindBySlug(ctx context.Context, slug string) (*models.Tag, error)
+	List(ctx context.Context) ([]models.Tag, error)
+	Update(ctx context.Context, tag *models.Tag) error
+	Delete(ctx context.Context, id int) error
+}
+
+// InvitationRepository 註冊邀請碼 Repository 介面（抽象層）
+type InvitationRepository interface {
+	Create(ctx context.Context, invitation *models.Invitation) error
+	FindByCode(ctx context.Context, code string) (*models.Invitation, error)
+	List(ctx context.Context, limit, skip int64) ([]models.Invitation, error)
+	Revoke(ctx context.Context, id string) error
+	Redeem(ctx context.Context, code string, now time.Time) (*models.Invitation, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// APITokenRepository 個人 API Token Repository 介面（抽象層）
+type APITokenRepository interface {
+	Create(ctx context.Context, token *models.APIToken) error
+	FindByHash(ctx context.Context, tokenHash string) (*models.APIToken, error)
+	ListByUser(ctx context.Context, userID string) ([]models.APIToken, error)
+	Delete(ctx context.Context, id, userID string) error
+	Touch(ctx context.Context, id string, lastUsed time.Time) error
+}
+
+// ChannelShareRepository 頻道分享邀請 Repository 介面（抽象層）
+type ChannelShareRepository interface {
+	Create(ctx context.Context, share *models.ChannelShare) error
+	FindByID(ctx context.Context, id string) (*models.ChannelShare, error)
+	FindByHash(ctx context.Context, tokenHash string) (*models.ChannelShare, error)
+	ListByChannel(ctx context.Context, channelID string) ([]models.ChannelShare, error)
+	ListPendingByEmail(ctx context.Context, email string, now time.Time) ([]models.ChannelShare, error)
+	UpdateStatus(ctx context.Context, id, fromStatus, toStatus, userID string) (bool, error)
+}
+
+// ChannelRevisionRepository 頻道歷史版本 Repository 介面（抽象層）
+type ChannelRevisionRepository interface {
+	Create(ctx context.Context, revision *models.ChannelRevision) error
+	FindByID(ctx context.Context, id string) (*models.ChannelRevision, error)
+	ListByChannel(ctx context.Context, channelID string, limit, skip int64) ([]models.ChannelRevision, error)
+}
+
+// SearchQuery 全文搜尋條件
+type SearchQuery struct {
+	Terms  []string // 搜尋詞（需全部符合）
+	UserID string   // 搜尋者（可搜尋到自己可讀取的非公開頻道，未登入為空字串）
+	Limit  int64
+	Skip   int64
+}
+
+// SearchRepository 全文搜尋 Repository 介面（抽象層）
+type SearchRepository interface {
+	SearchChannels(ctx context.Context, query SearchQuery) ([]models.Channel, error)
+	SearchPrograms(ctx context.Context, query SearchQuery) ([]models.ProgramSearchResult, error)
+}
+
 // ErrNoDocuments 找不到文件的錯誤（對應 MongoDB 的 ErrNoDocuments）
 var ErrNoDocuments = &NotFoundError{Message: "no documents found"}
 
@@ -172,3 +277,26 @@ func IsNotFound(err error) bool {
 	return ok
 }
 
+// ErrLastOwner 移除擁有者後頻道將沒有任何擁有者的錯誤
+var ErrLastOwner = errors.New("channel must have at least one owner")
+
+// ErrRevisionMismatch 頻道修訂版本與請求指定的版本不符（已被其他人修改）的錯誤
+var ErrRevisionMismatch = errors.New("channel revision mismatch")
+
+// claimedRevisionKey context 中標示頻道修訂版本已在目前交易中遞增的 key
+type claimedRevisionKey struct {
+	channelID string
+}
+
+// WithClaimedRevision 標示頻道修訂版本已在目前交易中比對並遞增（同一個交易中的後續修改不再遞增）
+func WithClaimedRevision(ctx context.Context, channelID string) context.Context {
+	return context.WithValue(ctx, claimedRevisionKey{channelID: channelID}, true)
+}
+
+// RevisionIncrement 取得修改頻道時修訂版本的遞增量（已在目前交易中遞增時為 0）
+func RevisionIncrement(ctx context.Context, channelID string) int64 {
+	if ctx.Value(claimedRevisionKey{channelID: channelID}) != nil {
+		return 0
+	}
+	return 1
+}
@@ -0,0 +1,1075 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/higgstv/higgstv-go/internal/models"
+)
+
+// MemoryDatabase 記憶體資料庫實作（資料只保存在程序中，關閉後即消失，主要供測試使用）
+// 使用者、頻道（含節目）與邀請碼由 Repository 直接存取 MemoryStore，
+// 其餘集合（migrations、counters 等）以 MemoryCollection 保存 BSON 文件
+type MemoryDatabase struct {
+	store *MemoryStore
+
+	mu          sync.Mutex
+	collections map[string]*MemoryCollection
+
+	txMu sync.Mutex // 同一時間只執行一個 WithTx
+}
+
+// memoryTxKey context 中標示已在 WithTx 中的 key
+type memoryTxKey struct {
+	db *MemoryDatabase
+}
+
+// MemoryStore 記憶體資料庫中由 Repository 直接存取的資料（讀寫前需取得鎖，存取時需自行複製資料）
+type MemoryStore struct {
+	sync.Mutex
+	Users       map[string]*models.User
+	Channels    map[string]*models.Channel // 節目內嵌在頻道的 Contents 中（與 MongoDB 相同）
+	Invitations map[string]*models.Invitation
+}
+
+// GetStore 取得記憶體資料（供 Repository 使用）
+func (d *MemoryDatabase) GetStore() *MemoryStore {
+	return d.store
+}
+
+// NewMemoryDatabase 建立記憶體資料庫（URI 與 Database 設定不使用）
+func NewMemoryDatabase(ctx context.Context, config DatabaseConfig) (*MemoryDatabase, error) {
+	return &MemoryDatabase{
+		store: &MemoryStore{
+			Users:       make(map[string]*models.User),
+			Channels:    make(map[string]*models.Channel),
+			Invitations: make(map[string]*models.Invitation),
+		},
+		collections: make(map[string]*MemoryCollection),
+	}, nil
+}
+
+// Type 回傳資料庫類型
+func (d *MemoryDatabase) Type() DatabaseType {
+	return DatabaseTypeMemory
+}
+
+// Collection 取得集合操作介面（同名集合共用相同的資料）
+func (d *MemoryDatabase) Collection(name string) Collection {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	collection, ok := d.collections[name]
+	if !ok {
+		collection = &MemoryCollection{name: name}
+		d.collections[name] = collection
+	}
+	return collection
+}
+
+// Close 關閉資料庫（no-op）
+func (d *MemoryDatabase) Close(ctx context.Context) error {
+	return nil
+}
+
+// Ping 測試連線（記憶體資料庫永遠可用）
+func (d *MemoryDatabase) Ping(ctx context.Context) error {
+	return nil
+}
+
+// BeginTx 開始交易（no-op，記憶體資料庫的每個 Repository 操作在持有鎖時完成）
+func (d *MemoryDatabase) BeginTx(ctx context.Context) (Tx, error) {
+	return &MemoryTx{}, nil
+}
+
+// WithTx 在交易中執行 fn（執行前保存所有資料，fn 回傳錯誤時還原；巢狀呼叫沿用外層交易）
+// 還原的資料經過 BSON 轉換，時間只保留到毫秒（與 MongoDB 相同）
+func (d *MemoryDatabase) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if ctx.Value(memoryTxKey{db: d}) != nil {
+		return fn(ctx)
+	}
+
+	d.txMu.Lock()
+	defer d.txMu.Unlock()
+
+	snapshot, err := d.snapshot()
+	if err != nil {
+		return err
+	}
+	if err := fn(context.WithValue(ctx, memoryTxKey{db: d}, true)); err != nil {
+		if restoreErr := d.restore(snapshot); restoreErr != nil {
+			return fmt.Errorf("%w (rollback failed: %v)", err, restoreErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// memorySnapshot WithTx 開始時的資料（以 BSON 保存）
+type memorySnapshot struct {
+	users       map[string]bson.M
+	channels    map[string]bson.M
+	invitations map[string]bson.M
+	collections map[string][]bson.M
+}
+
+// snapshot 保存所有資料
+func (d *MemoryDatabase) snapshot() (*memorySnapshot, error) {
+	snapshot := &memorySnapshot{
+		users:       map[string]bson.M{},
+		channels:    map[string]bson.M{},
+		invitations: map[string]bson.M{},
+		collections: map[string][]bson.M{},
+	}
+
+	d.store.Lock()
+	err := func() error {
+		for id, user := range d.store.Users {
+			doc, err := encodeDocument(user)
+			if err != nil {
+				return err
+			}
+			snapshot.users[id] = doc
+		}
+		for id, channel := range d.store.Channels {
+			doc, err := encodeDocument(channel)
+			if err != nil {
+				return err
+			}
+			snapshot.channels[id] = doc
+		}
+		for id, invitation := range d.store.Invitations {
+			doc, err := encodeDocument(invitation)
+			if err != nil {
+				return err
+			}
+			snapshot.invitations[id] = doc
+		}
+		return nil
+	}()
+	d.store.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for name, collection := range d.collections {
+		collection.mu.Lock()
+		docs := make([]bson.M, 0, len(collection.documents))
+		for _, doc := range collection.documents {
+			copied, err := encodeDocument(doc)
+			if err != nil {
+				collection.mu.Unlock()
+				return nil, err
+			}
+			docs = append(docs, copied)
+		}
+		collection.mu.Unlock()
+		snapshot.collections[name] = docs
+	}
+	return snapshot, nil
+}
+
+// restore 還原 snapshot 保存的資料
+func (d *MemoryDatabase) restore(snapshot *memorySnapshot) error {
+	users := make(map[string]*models.User, len(snapshot.users))
+	for id, doc := range snapshot.users {
+		user := &models.User{}
+		if err := decodeDocument(doc, user); err != nil {
+			return err
+		}
+		users[id] = user
+	}
+	channels := make(map[string]*models.Channel, len(snapshot.channels))
+	for id, doc := range snapshot.channels {
+		channel := &models.Channel{}
+		if err := decodeDocument(doc, channel); err != nil {
+			return err
+		}
+		channels[id] = channel
+	}
+	invitations := make(map[string]*models.Invitation, len(snapshot.invitations))
+	for id, doc := range snapshot.invitations {
+		invitation := &models.Invitation{}
+		if err := decodeDocument(doc, invitation); err != nil {
+			return err
+		}
+		invitations[id] = invitation
+	}
+
+	d.store.Lock()
+	d.store.Users = users
+	d.store.Channels = channels
+	d.store.Invitations = invitations
+	d.store.Unlock()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for name, collection := range d.collections {
+		collection.mu.Lock()
+		collection.documents = snapshot.collections[name]
+		collection.mu.Unlock()
+	}
+	return nil
+}
+
+// MemoryTx 記憶體資料庫交易實作（no-op）
+type MemoryTx struct{}
+
+// Commit 提交交易（no-op）
+func (t *MemoryTx) Commit(ctx context.Context) error {
+	return nil
+}
+
+// Rollback 回滾交易（no-op）
+func (t *MemoryTx) Rollback(ctx context.Context) error {
+	return nil
+}
+
+// MemoryCollection 記憶體集合實作（文件以 BSON 形式保存，過濾條件支援常用的 MongoDB 運算子）
+type MemoryCollection struct {
+	mu        sync.Mutex
+	name      string
+	documents []bson.M
+	indexes   []IndexInfo
+}
+
+// FindOne 查詢單筆文件
+func (c *MemoryCollection) FindOne(ctx context.Context, filter Filter, result interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, doc := range c.documents {
+		if matchDocument(doc, filter) {
+			return decodeDocument(doc, result)
+		}
+	}
+	return ErrNoDocuments
+}
+
+// Find 查詢多筆文件
+func (c *MemoryCollection) Find(ctx context.Context, filter Filter, sort Sort, limit, skip int64, results interface{}) error {
+	resultsValue := reflect.ValueOf(results)
+	if resultsValue.Kind() != reflect.Ptr || resultsValue.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("results must be a pointer to a slice")
+	}
+
+	c.mu.Lock()
+	matched := []bson.M{}
+	for _, doc := range c.documents {
+		if matchDocument(doc, filter) {
+			matched = append(matched, doc)
+		}
+	}
+	c.mu.Unlock()
+
+	sortDocuments(matched, sort)
+	if skip > 0 {
+		if skip >= int64(len(matched)) {
+			matched = nil
+		} else {
+			matched = matched[skip:]
+		}
+	}
+	if limit > 0 && limit < int64(len(matched)) {
+		matched = matched[:limit]
+	}
+
+	sliceValue := resultsValue.Elem()
+	sliceValue.Set(reflect.MakeSlice(sliceValue.Type(), 0, len(matched)))
+	for _, doc := range matched {
+		elem := reflect.New(sliceValue.Type().Elem())
+		if err := decodeDocument(doc, elem.Interface()); err != nil {
+			return err
+		}
+		sliceValue.Set(reflect.Append(sliceValue, elem.Elem()))
+	}
+	return nil
+}
+
+// InsertOne 新增單筆文件（_id 重複時回傳錯誤）
+func (c *MemoryCollection) InsertOne(ctx context.Context, document interface{}) error {
+	doc, err := encodeDocument(document)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if id, ok := doc["_id"]; ok {
+		for _, existing := range c.documents {
+			if valuesEqual(existing["_id"], id) {
+				return fmt.Errorf("duplicate key in collection %s: _id %v", c.name, id)
+			}
+		}
+	}
+	c.documents = append(c.documents, doc)
+	return nil
+}
+
+// UpdateOne 更新單筆文件
+func (c *MemoryCollection) UpdateOne(ctx context.Context, filter Filter, update Update) error {
+	_, err := c.UpdateOneMatched(ctx, filter, update)
+	return err
+}
+
+// UpdateOneMatched 更新單筆文件，並回傳是否有符合條件的文件
+func (c *MemoryCollection) UpdateOneMatched(ctx context.Context, filter Filter, update Update) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, doc := range c.documents {
+		if matchDocument(doc, filter) {
+			return true, applyUpdate(doc, filter, update)
+		}
+	}
+	return false, nil
+}
+
+// DeleteOne 刪除單筆文件
+func (c *MemoryCollection) DeleteOne(ctx context.Context, filter Filter) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, doc := range c.documents {
+		if matchDocument(doc, filter) {
+			c.documents = append(c.documents[:i], c.documents[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+// DeleteMany 刪除多筆文件
+func (c *MemoryCollection) DeleteMany(ctx context.Context, filter Filter) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	kept := c.documents[:0]
+	for _, doc := range c.documents {
+		if !matchDocument(doc, filter) {
+			kept = append(kept, doc)
+		}
+	}
+	c.documents = kept
+	return nil
+}
+
+// CountDocuments 計算文件數量
+func (c *MemoryCollection) CountDocuments(ctx context.Context, filter Filter) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var count int64
+	for _, doc := range c.documents {
+		if matchDocument(doc, filter) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// FindOneAndUpdate 查詢並更新單筆文件（與 MongoDB 實作相同，找不到時以過濾條件中的欄位新增文件）
+func (c *MemoryCollection) FindOneAndUpdate(ctx context.Context, filter Filter, update Update, returnAfter bool, result interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, doc := range c.documents {
+		if !matchDocument(doc, filter) {
+			continue
+		}
+		before, err := encodeDocument(doc)
+		if err != nil {
+			return err
+		}
+		if err := applyUpdate(doc, filter, update); err != nil {
+			return err
+		}
+		if returnAfter {
+			return decodeDocument(doc, result)
+		}
+		return decodeDocument(before, result)
+	}
+
+	// upsert：只使用等於條件的欄位建立新文件
+	doc := bson.M{}
+	for key, value := range filter {
+		if _, isOperator := value.(Filter); !isOperator && !strings.HasPrefix(key, "$") {
+			doc[key] = value
+		}
+	}
+	if err := applyUpdate(doc, filter, update); err != nil {
+		return err
+	}
+	doc, err := encodeDocument(doc)
+	if err != nil {
+		return err
+	}
+	c.documents = append(c.documents, doc)
+	if !returnAfter {
+		return ErrNoDocuments
+	}
+	return decodeDocument(doc, result)
+}
+
+// CreateIndex 建立索引（只記錄索引資訊，不檢查唯一性）
+func (c *MemoryCollection) CreateIndex(ctx context.Context, keys map[string]interface{}, options IndexOptions) error {
+	if len(keys) == 0 {
+		return fmt.Errorf("index keys cannot be empty")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, index := range c.indexes {
+		if options.Name != "" && index.Name == options.Name {
+			return nil
+		}
+	}
+	c.indexes = append(c.indexes, IndexInfo{
+		Name:   options.Name,
+		Keys:   keys,
+		Unique: options.Unique,
+	})
+	return nil
+}
+
+// ListIndexes 列出索引
+func (c *MemoryCollection) ListIndexes(ctx context.Context) ([]IndexInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	indexes := make([]IndexInfo, len(c.indexes))
+	copy(indexes, c.indexes)
+	return indexes, nil
+}
+
+// FilterDocuments 依過濾條件與排序規則篩選 items（規則與 MemoryCollection 相同，供記憶體 Repository 查詢使用）
+func FilterDocuments[T any](items []T, filter Filter, rules Sort) ([]T, error) {
+	type entry struct {
+		doc  bson.M
+		item T
+	}
+
+	entries := []entry{}
+	for _, item := range items {
+		doc, err := encodeDocument(item)
+		if err != nil {
+			return nil, err
+		}
+		if matchDocument(doc, filter) {
+			entries = append(entries, entry{doc: doc, item: item})
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return lessDocument(entries[i].doc, entries[j].doc, rules)
+	})
+
+	result := make([]T, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, entry.item)
+	}
+	return result, nil
+}
+
+// encodeDocument 將文件轉換為 bson.M（同時複製一份，避免與呼叫端共用資料）
+func encodeDocument(document interface{}) (bson.M, error) {
+	data, err := bson.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+	var doc bson.M
+	if err := bson.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// decodeDocument 將 bson.M 解碼到 result
+func decodeDocument(doc bson.M, result interface{}) error {
+	data, err := bson.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return bson.Unmarshal(data, result)
+}
+
+// applyUpdate 套用更新操作（filter 用於 $ 位置運算子，例如 contents.$.name）
+func applyUpdate(doc bson.M, filter Filter, update Update) error {
+	for key, value := range update.Set {
+		encoded, err := encodeValue(value)
+		if err != nil {
+			return err
+		}
+		if err := setField(doc, filter, key, encoded); err != nil {
+			return err
+		}
+	}
+	for key, value := range update.Inc {
+		delta, ok := toFloat(value)
+		if !ok {
+			return fmt.Errorf("cannot $inc %s by non-numeric value %v", key, value)
+		}
+		existing, _ := lookupField(doc, key)
+		current, _ := toFloat(existing)
+		var result interface{} = int64(current + delta)
+		if _, ok := existing.(float64); ok {
+			result = current + delta
+		}
+		if err := setField(doc, filter, key, result); err != nil {
+			return err
+		}
+	}
+	for key, value := range update.AddToSet {
+		if err := pushField(doc, key, value, true); err != nil {
+			return err
+		}
+	}
+	for key, value := range update.Push {
+		if err := pushField(doc, key, value, false); err != nil {
+			return err
+		}
+	}
+	for key, condition := range update.Pull {
+		if err := pullField(doc, key, condition); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setField 以點號路徑設定欄位（不存在的子文件會自動建立，$ 為第一個符合過濾條件的陣列元素）
+func setField(doc bson.M, filter Filter, path string, value interface{}) error {
+	if arrayPath, rest, ok := strings.Cut(path, ".$"); ok && (rest == "" || strings.HasPrefix(rest, ".")) {
+		array, _ := lookupField(doc, arrayPath)
+		items, ok := array.(bson.A)
+		if !ok {
+			return fmt.Errorf("positional update needs array field %s", arrayPath)
+		}
+		index := positionalIndex(items, filter, arrayPath)
+		if index < 0 {
+			return fmt.Errorf("positional update of %s did not find a matching element", arrayPath)
+		}
+		if rest == "" {
+			items[index] = value
+			return nil
+		}
+		element, ok := items[index].(bson.M)
+		if !ok {
+			return fmt.Errorf("positional update needs document elements in %s", arrayPath)
+		}
+		return setField(element, nil, strings.TrimPrefix(rest, "."), value)
+	}
+
+	parts := strings.Split(path, ".")
+	current := doc
+	for _, part := range parts[:len(parts)-1] {
+		sub, ok := current[part].(bson.M)
+		if !ok {
+			if current[part] != nil {
+				return fmt.Errorf("cannot set %s: %s is not a document", path, part)
+			}
+			sub = bson.M{}
+			current[part] = sub
+		}
+		current = sub
+	}
+	current[parts[len(parts)-1]] = value
+	return nil
+}
+
+// positionalIndex 取得第一個符合過濾條件（filter 中以 arrayPath 開頭的欄位）的陣列元素位置
+func positionalIndex(items bson.A, filter Filter, arrayPath string) int {
+	conditions := Filter{}
+	var scalar interface{}
+	hasScalar := false
+	for key, condition := range filter {
+		if key == arrayPath {
+			scalar, hasScalar = condition, true
+		} else if sub, ok := strings.CutPrefix(key, arrayPath+"."); ok {
+			if _, err := parseIndex(strings.Split(sub, ".")[0]); err != nil {
+				conditions[sub] = condition
+			}
+		}
+	}
+	if len(conditions) == 0 && !hasScalar {
+		return -1
+	}
+
+	for i, item := range items {
+		if hasScalar && !matchCondition(item, true, scalar) {
+			continue
+		}
+		if len(conditions) > 0 {
+			element, ok := item.(bson.M)
+			if !ok || !matchDocument(element, conditions) {
+				continue
+			}
+		}
+		return i
+	}
+	return -1
+}
+
+// pushField 新增陣列元素（$push 與 $addToSet，支援 $each；unique 時略過已存在的值）
+func pushField(doc bson.M, path string, value interface{}, unique bool) error {
+	items := []interface{}{value}
+	if operators, ok := asFilter(value); ok {
+		if each, ok := operators["$each"]; ok {
+			list := reflect.ValueOf(each)
+			if list.Kind() != reflect.Slice {
+				return fmt.Errorf("$each needs an array")
+			}
+			items = make([]interface{}, list.Len())
+			for i := range items {
+				items[i] = list.Index(i).Interface()
+			}
+		}
+	}
+
+	existing, exists := lookupField(doc, path)
+	array, ok := existing.(bson.A)
+	if exists && existing != nil && !ok {
+		return fmt.Errorf("cannot push to non-array field %s", path)
+	}
+	for _, item := range items {
+		encoded, err := encodeValue(item)
+		if err != nil {
+			return err
+		}
+		if unique && slices.ContainsFunc(array, func(v interface{}) bool { return valuesEqual(v, encoded) }) {
+			continue
+		}
+		array = append(array, encoded)
+	}
+	if array == nil {
+		array = bson.A{}
+	}
+	return setField(doc, nil, path, array)
+}
+
+// pullField 移除符合條件的陣列元素（子文件元素以過濾條件比對）
+func pullField(doc bson.M, path string, condition interface{}) error {
+	existing, exists := lookupField(doc, path)
+	if !exists || existing == nil {
+		return nil
+	}
+	array, ok := existing.(bson.A)
+	if !ok {
+		return fmt.Errorf("cannot pull from non-array field %s", path)
+	}
+
+	encoded, err := encodeValue(condition)
+	if err != nil {
+		return err
+	}
+	filter, isFilter := asFilter(encoded)
+	kept := bson.A{}
+	for _, item := range array {
+		element, isDocument := item.(bson.M)
+		var matched bool
+		if isFilter && isDocument && !isOperatorFilter(filter) {
+			matched = matchDocument(element, filter)
+		} else {
+			matched = matchCondition(item, true, encoded)
+		}
+		if !matched {
+			kept = append(kept, item)
+		}
+	}
+	return setField(doc, nil, path, kept)
+}
+
+// encodeValue 將單一欄位值轉換為 bson.M 中保存的形式
+func encodeValue(value interface{}) (interface{}, error) {
+	doc, err := encodeDocument(bson.M{"v": value})
+	if err != nil {
+		return nil, err
+	}
+	return doc["v"], nil
+}
+
+// matchDocument 檢查文件是否符合過濾條件（支援 $or、$and、$eq、$in、$nin、$ne、$exists、$regex 與大小比較）
+func matchDocument(doc bson.M, filter Filter) bool {
+	for key, condition := range filter {
+		if key == "$or" {
+			if !matchAny(doc, condition) {
+				return false
+			}
+			continue
+		}
+		if key == "$and" {
+			if !matchAll(doc, condition) {
+				return false
+			}
+			continue
+		}
+		value, exists := lookupField(doc, key)
+		if !matchCondition(value, exists, condition) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchAny 檢查文件是否符合 $or 中任一條件
+func matchAny(doc bson.M, conditions interface{}) bool {
+	switch list := conditions.(type) {
+	case []Filter:
+		for _, filter := range list {
+			if matchDocument(doc, filter) {
+				return true
+			}
+		}
+	case []map[string]interface{}:
+		for _, filter := range list {
+			if matchDocument(doc, Filter(filter)) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, item := range list {
+			if filter, ok := asFilter(item); ok && matchDocument(doc, filter) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchAll 檢查文件是否符合 $and 中所有條件
+func matchAll(doc bson.M, conditions interface{}) bool {
+	list := reflect.ValueOf(conditions)
+	if list.Kind() != reflect.Slice || list.Len() == 0 {
+		return false
+	}
+	for i := 0; i < list.Len(); i++ {
+		filter, ok := asFilter(list.Index(i).Interface())
+		if !ok || !matchDocument(doc, filter) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchCondition 檢查欄位值是否符合條件（條件為運算子時逐一比對，否則比對相等）
+func matchCondition(value interface{}, exists bool, condition interface{}) bool {
+	operators, ok := asFilter(condition)
+	if !ok || !isOperatorFilter(operators) {
+		return matchEqual(value, exists, condition)
+	}
+
+	for op, operand := range operators {
+		switch op {
+		case "$eq":
+			if !matchEqual(value, exists, operand) {
+				return false
+			}
+		case "$ne":
+			if matchEqual(value, exists, operand) {
+				return false
+			}
+		case "$in":
+			if !matchIn(value, exists, operand) {
+				return false
+			}
+		case "$nin":
+			if matchIn(value, exists, operand) {
+				return false
+			}
+		case "$exists":
+			want, _ := operand.(bool)
+			if exists != want {
+				return false
+			}
+		case "$regex":
+			pattern, err := regexPattern(operand, operators["$options"])
+			if err != nil {
+				return false
+			}
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return false
+			}
+			if !matchElement(value, func(item interface{}) bool {
+				str, ok := item.(string)
+				return ok && re.MatchString(str)
+			}) {
+				return false
+			}
+		case "$options":
+			// 與 $regex 一起處理
+		case "$gt", "$gte", "$lt", "$lte":
+			if !exists || !matchElement(value, func(item interface{}) bool {
+				cmp, ok := compareValues(item, operand)
+				return ok && !((op == "$gt" && cmp <= 0) || (op == "$gte" && cmp < 0) ||
+					(op == "$lt" && cmp >= 0) || (op == "$lte" && cmp > 0))
+			}) {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// matchElement 檢查值（陣列時為任一元素）是否符合 fn
+func matchElement(value interface{}, fn func(item interface{}) bool) bool {
+	if array, ok := value.(bson.A); ok {
+		for _, item := range array {
+			if fn(item) {
+				return true
+			}
+		}
+		return false
+	}
+	return fn(value)
+}
+
+// matchEqual 比對相等（與 MongoDB 相同：nil 也符合不存在的欄位，陣列欄位只要有任一元素相等即符合）
+func matchEqual(value interface{}, exists bool, expected interface{}) bool {
+	if expected == nil {
+		return !exists || value == nil
+	}
+	if !exists {
+		return false
+	}
+	if array, ok := value.(bson.A); ok {
+		for _, item := range array {
+			if valuesEqual(item, expected) {
+				return true
+			}
+		}
+	}
+	return valuesEqual(value, expected)
+}
+
+// matchIn 檢查欄位值是否等於 operand 中任一值
+func matchIn(value interface{}, exists bool, operand interface{}) bool {
+	list := reflect.ValueOf(operand)
+	if list.Kind() != reflect.Slice {
+		return false
+	}
+	for i := 0; i < list.Len(); i++ {
+		if matchEqual(value, exists, list.Index(i).Interface()) {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupField 以點號路徑取得欄位值（數字路徑表示陣列索引，其他路徑在陣列中時比對任一元素）
+func lookupField(doc bson.M, path string) (interface{}, bool) {
+	var current interface{} = doc
+	for _, part := range strings.Split(path, ".") {
+		switch node := current.(type) {
+		case bson.M:
+			value, ok := node[part]
+			if !ok {
+				return nil, false
+			}
+			current = value
+		case bson.D:
+			value, ok := node.Map()[part]
+			if !ok {
+				return nil, false
+			}
+			current = value
+		case bson.A:
+			if index, err := parseIndex(part); err == nil {
+				if index >= len(node) {
+					return nil, false
+				}
+				current = node[index]
+				continue
+			}
+			values := bson.A{}
+			for _, item := range node {
+				if sub, ok := item.(bson.M); ok {
+					if value, ok := sub[part]; ok {
+						// 與 MongoDB 相同，子文件中的陣列展開後比對
+						if array, ok := value.(bson.A); ok {
+							values = append(values, array...)
+						} else {
+							values = append(values, value)
+						}
+					}
+				}
+			}
+			if len(values) == 0 {
+				return nil, false
+			}
+			current = values
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// parseIndex 解析陣列索引路徑
+func parseIndex(part string) (int, error) {
+	var index int
+	if _, err := fmt.Sscanf(part, "%d", &index); err != nil || fmt.Sprint(index) != part {
+		return 0, fmt.Errorf("not an index: %s", part)
+	}
+	return index, nil
+}
+
+// asFilter 將 Filter 或 map 轉換為 Filter
+func asFilter(value interface{}) (Filter, bool) {
+	switch v := value.(type) {
+	case Filter:
+		return v, true
+	case map[string]interface{}:
+		return Filter(v), true
+	case bson.M:
+		return Filter(v), true
+	}
+	return nil, false
+}
+
+// isOperatorFilter 檢查條件是否全部由運算子組成
+func isOperatorFilter(filter Filter) bool {
+	if len(filter) == 0 {
+		return false
+	}
+	for key := range filter {
+		if !strings.HasPrefix(key, "$") {
+			return false
+		}
+	}
+	return true
+}
+
+// valuesEqual 比較兩個值是否相等（數字與時間依數值比較）
+func valuesEqual(a, b interface{}) bool {
+	if cmp, ok := compareValues(a, b); ok {
+		return cmp == 0
+	}
+	encodedA, errA := encodeValue(a)
+	encodedB, errB := encodeValue(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+	return reflect.DeepEqual(encodedA, encodedB)
+}
+
+// compareValues 比較兩個數字、字串或時間（類型不同時 ok 為 false）
+func compareValues(a, b interface{}) (int, bool) {
+	if x, ok := toFloat(a); ok {
+		y, ok := toFloat(b)
+		if !ok {
+			return 0, false
+		}
+		return compareOrdered(x, y), true
+	}
+	if x, ok := a.(string); ok {
+		y, ok := b.(string)
+		if !ok {
+			return 0, false
+		}
+		return strings.Compare(x, y), true
+	}
+	if x, ok := toTime(a); ok {
+		y, ok := toTime(b)
+		if !ok {
+			return 0, false
+		}
+		return compareOrdered(x.UnixMilli(), y.UnixMilli()), true
+	}
+	if x, ok := a.(bool); ok {
+		y, ok := b.(bool)
+		if !ok || x != y {
+			return 1, ok
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// compareOrdered 比較兩個可排序的值
+func compareOrdered[T int64 | float64](x, y T) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+// toFloat 將數字轉換為 float64
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// toTime 將時間轉換為 time.Time
+func toTime(value interface{}) (time.Time, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, true
+	case *time.Time:
+		if v != nil {
+			return *v, true
+		}
+	case primitive.DateTime:
+		return v.Time(), true
+	}
+	return time.Time{}, false
+}
+
+// sortDocuments 依排序規則排序文件
+func sortDocuments(docs []bson.M, rules Sort) {
+	if len(rules) == 0 {
+		return
+	}
+	sort.SliceStable(docs, func(i, j int) bool {
+		return lessDocument(docs[i], docs[j], rules)
+	})
+}
+
+// lessDocument 依排序規則比較兩份文件（欄位不存在的文件排在最前面，與 MongoDB 相同）
+func lessDocument(a, b bson.M, rules Sort) bool {
+	for _, rule := range rules {
+		valueA, okA := lookupField(a, rule.Field)
+		valueB, okB := lookupField(b, rule.Field)
+		cmp := 0
+		switch {
+		case !okA && okB:
+			cmp = -1
+		case okA && !okB:
+			cmp = 1
+		case okA && okB:
+			cmp, _ = compareValues(valueA, valueB)
+		}
+		if cmp != 0 {
+			return (cmp < 0) == (rule.Order >= 0)
+		}
+	}
+	return false
+}
@@ -2,6 +2,7 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -79,6 +80,34 @@ func (d *MongoDBDatabase) BeginTx(ctx context.Context) (Tx, error) {
 	return &MongoDBTx{}, nil
 }
 
+// WithTx 在交易中執行 fn（fn 內的操作必須使用傳入的 ctx 才會包含在 session 的交易中）
+// ctx 已在 session 中時沿用該交易；單機 MongoDB 不支援交易，此時直接執行 fn
+func (d *MongoDBDatabase) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if mongo.SessionFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+
+	session, err := d.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessCtx)
+	})
+	if isTransactionNotSupported(err) {
+		return fn(ctx)
+	}
+	return err
+}
+
+// isTransactionNotSupported 檢查是否為不支援交易的錯誤（IllegalOperation：非 replica set）
+func isTransactionNotSupported(err error) bool {
+	var cmdErr mongo.CommandError
+	return errors.As(err, &cmdErr) && cmdErr.Code == 20
+}
+
 // MongoDBTx MongoDB 交易實作（簡化版本）
 type MongoDBTx struct{}
 
@@ -149,6 +178,18 @@ func (c *MongoDBCollection) UpdateOne(ctx context.Context, filter Filter, update
 	return err
 }
 
+// UpdateOneMatched 更新單筆文件，並回傳是否有符合條件的文件
+func (c *MongoDBCollection) UpdateOneMatched(ctx context.Context, filter Filter, update Update) (bool, error) {
+	bsonFilter := convertFilterToBSON(filter)
+	bsonUpdate := convertUpdateToBSON(update)
+
+	result, err := c.collection.UpdateOne(ctx, bsonFilter, bsonUpdate)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
 // DeleteOne 刪除單筆文件
 func (c *MongoDBCollection) DeleteOne(ctx context.Context, filter Filter) error {
 	bsonFilter := convertFilterToBSON(filter)
@@ -156,6 +197,13 @@ func (c *MongoDBCollection) DeleteOne(ctx context.Context, filter Filter) error
 	return err
 }
 
+// DeleteMany 刪除多筆文件
+func (c *MongoDBCollection) DeleteMany(ctx context.Context, filter Filter) error {
+	bsonFilter := convertFilterToBSON(filter)
+	_, err := c.collection.DeleteMany(ctx, bsonFilter)
+	return err
+}
+
 // CountDocuments 計算文件數量
 func (c *MongoDBCollection) CountDocuments(ctx context.Context, filter Filter) (int64, error) {
 	bsonFilter := convertFilterToBSON(filter)
@@ -199,6 +247,16 @@ func (c *MongoDBCollection) CreateIndex(ctx context.Context, keys map[string]int
 	if indexOpts.Sparse {
 		opts.SetSparse(true)
 	}
+	if len(indexOpts.Weights) > 0 {
+		weights := bson.M{}
+		for k, v := range indexOpts.Weights {
+			weights[k] = v
+		}
+		opts.SetWeights(weights)
+	}
+	if indexOpts.DefaultLanguage != "" {
+		opts.SetDefaultLanguage(indexOpts.DefaultLanguage)
+	}
 
 	indexModel := mongo.IndexModel{
 		Keys:    bsonKeys,
@@ -283,6 +341,9 @@ func convertUpdateToBSON(update Update) bson.M {
 	if len(update.Push) > 0 {
 		result["$push"] = update.Push
 	}
+	if len(update.Inc) > 0 {
+		result["$inc"] = update.Inc
+	}
 	return result
 }
 
@@ -0,0 +1,103 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+)
+
+// sqlTxKey context 中保存交易的 key（以連線區分，不同資料庫的交易互不影響）
+type sqlTxKey struct {
+	db *sql.DB
+}
+
+// SQLConn 會使用 context 中交易的 SQL 連線（SQLite 與 PostgreSQL Repository 使用）
+// 在 WithTx 中執行時，所有查詢與 BeginTx 都使用外層的交易
+type SQLConn struct {
+	db *sql.DB
+}
+
+// NewSQLConn 建立 SQLConn
+func NewSQLConn(db *sql.DB) *SQLConn {
+	return &SQLConn{db: db}
+}
+
+// tx 取得 context 中的交易
+func (c *SQLConn) tx(ctx context.Context) *sql.Tx {
+	tx, _ := ctx.Value(sqlTxKey{db: c.db}).(*sql.Tx)
+	return tx
+}
+
+// ExecContext 執行 SQL
+func (c *SQLConn) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if tx := c.tx(ctx); tx != nil {
+		return tx.ExecContext(ctx, query, args...)
+	}
+	return c.db.ExecContext(ctx, query, args...)
+}
+
+// QueryContext 查詢多筆資料
+func (c *SQLConn) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if tx := c.tx(ctx); tx != nil {
+		return tx.QueryContext(ctx, query, args...)
+	}
+	return c.db.QueryContext(ctx, query, args...)
+}
+
+// QueryRowContext 查詢單筆資料
+func (c *SQLConn) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	if tx := c.tx(ctx); tx != nil {
+		return tx.QueryRowContext(ctx, query, args...)
+	}
+	return c.db.QueryRowContext(ctx, query, args...)
+}
+
+// BeginTx 開始交易（context 中已有交易時沿用該交易，Commit 與 Rollback 由外層處理）
+func (c *SQLConn) BeginTx(ctx context.Context, opts *sql.TxOptions) (*SQLTx, error) {
+	if tx := c.tx(ctx); tx != nil {
+		return &SQLTx{Tx: tx, nested: true}, nil
+	}
+	tx, err := c.db.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &SQLTx{Tx: tx}, nil
+}
+
+// WithTx 在交易中執行 fn（fn 回傳錯誤時回滾，巢狀呼叫沿用外層交易）
+func (c *SQLConn) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if c.tx(ctx) != nil {
+		return fn(ctx)
+	}
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(context.WithValue(ctx, sqlTxKey{db: c.db}, tx)); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// SQLTx SQLConn 開始的交易（沿用外層交易時 Commit 與 Rollback 不執行任何操作）
+type SQLTx struct {
+	*sql.Tx
+	nested bool
+}
+
+// Commit 提交交易
+func (t *SQLTx) Commit() error {
+	if t.nested {
+		return nil
+	}
+	return t.Tx.Commit()
+}
+
+// Rollback 回滾交易（沿用外層交易時由外層的 WithTx 依 fn 回傳的錯誤回滾）
+func (t *SQLTx) Rollback() error {
+	if t.nested {
+		return nil
+	}
+	return t.Tx.Rollback()
+}
@@ -0,0 +1,1375 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/mattn/go-sqlite3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/higgstv/higgstv-go/internal/models"
+)
+
+// 將 MongoDB 風格的 Filter、Sort、Update 轉換為 SQLite 的 SQL
+// 每個集合對應一個資料表：巢狀欄位展開為欄位（cover.default 對應 cover_default），
+// 陣列欄位對應子表（channels.owners 對應 channel_owners，channels.contents 對應 programs）。
+// 與 MongoDB 的差異：子表以主鍵去除重複，因此 $push 不會新增已存在的值；null 與不存在的欄位視為相同；
+// 取代或移除 contents 中的節目時，外鍵會一併刪除 contents_order 中的節目 ID。
+
+// sqliteDriverName 註冊 REGEXP 函式的 SQLite driver 名稱（$regex 使用）
+const sqliteDriverName = "sqlite3_higgstv"
+
+// sqliteRegexps 已編譯的正規表示式
+var sqliteRegexps sync.Map
+
+func init() {
+	sql.Register(sqliteDriverName, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			return conn.RegisterFunc("regexp", sqliteRegexp, true)
+		},
+	})
+}
+
+// sqliteRegexp 實作 REGEXP 運算子（X REGEXP Y 會呼叫 regexp(Y, X)，非字串的值不符合）
+func sqliteRegexp(pattern string, value interface{}) (bool, error) {
+	var str string
+	switch v := value.(type) {
+	case string:
+		str = v
+	case []byte:
+		if v == nil { // NULL
+			return false, nil
+		}
+		str = string(v)
+	default:
+		return false, nil
+	}
+
+	if re, ok := sqliteRegexps.Load(pattern); ok {
+		return re.(*regexp.Regexp).MatchString(str), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return false, err
+	}
+	sqliteRegexps.Store(pattern, re)
+	return re.MatchString(str), nil
+}
+
+// sqliteColumnKind 欄位的儲存方式
+type sqliteColumnKind int
+
+const (
+	sqliteScalar sqliteColumnKind = iota // 直接儲存
+	sqliteBool                           // 以 INTEGER 0/1 儲存的布林值
+	sqliteWords                          // 以空白分隔儲存的字串陣列
+	sqliteJSON                           // 以 JSON 儲存的子文件
+)
+
+// sqliteColumn 文件欄位與資料表欄位的對應
+type sqliteColumn struct {
+	field    string // 文件欄位（巢狀欄位以 . 分隔）
+	column   string
+	kind     sqliteColumnKind
+	jsonType reflect.Type // sqliteJSON 解碼時使用的型別
+}
+
+// sqliteArray 儲存在子表中的陣列欄位
+type sqliteArray struct {
+	field  string
+	parent string       // 子表參照上層文件主鍵的欄位
+	table  string       // 純量陣列的子表
+	value  string       // 純量陣列的值欄位
+	order  string       // 保存元素位置的欄位（空字串時依新增順序）
+	doc    *sqliteTable // 子文件陣列的對應（table 為空時使用）
+}
+
+// sqliteTable 集合與資料表的對應
+type sqliteTable struct {
+	name    string
+	key     string // 主鍵欄位（對應 _id，子表以此欄位參照）
+	autoKey bool   // 主鍵為 INTEGER PRIMARY KEY，未指定 _id 時由 SQLite 產生
+	columns []sqliteColumn
+	arrays  []sqliteArray
+}
+
+// sqliteColumns 建立直接儲存的欄位（_id 對應 id，巢狀欄位的 . 改為 _）
+func sqliteColumns(fields ...string) []sqliteColumn {
+	columns := make([]sqliteColumn, 0, len(fields))
+	for _, field := range fields {
+		column := strings.ReplaceAll(field, ".", "_")
+		if field == "_id" {
+			column = "id"
+		}
+		columns = append(columns, sqliteColumn{field: field, column: column})
+	}
+	return columns
+}
+
+// sqliteBoolColumns 建立布林欄位
+func sqliteBoolColumns(fields ...string) []sqliteColumn {
+	columns := sqliteColumns(fields...)
+	for i := range columns {
+		columns[i].kind = sqliteBool
+	}
+	return columns
+}
+
+// sqliteProgram 節目（頻道的 contents 與 programs 資料表共用）
+func sqliteProgram(extra ...string) *sqliteTable {
+	return &sqliteTable{
+		name:    "programs",
+		key:     "id",
+		autoKey: true,
+		columns: sqliteColumns(append([]string{"_id", "name", "desc", "duration", "type", "youtube_id", "video_id",
+			"metadata_at", "availability", "last_checked", "created", "last_modified"}, extra...)...),
+		arrays: []sqliteArray{{field: "tags", parent: "program_id", table: "program_tags", value: "tag"}},
+	}
+}
+
+// sqliteTables 集合名稱與資料表的對應
+var sqliteTables = map[string]*sqliteTable{
+	"users": {
+		name: "users",
+		key:  "id",
+		columns: sqliteColumns("_id", "username", "email", "password", "access_key", "unclassified_channel",
+			"invitation_id", "created", "last_modified"),
+		arrays: []sqliteArray{
+			{field: "own_channels", parent: "user_id", table: "user_channels", value: "channel_id"},
+		},
+	},
+	"channels": {
+		name: "channels",
+		key:  "id",
+		columns: append(sqliteColumns("_id", "type", "name", "desc", "contents_seq", "cover.default", "schedule.epoch",
+			"schedule.loop", "visibility", "revision", "forked_from", "fork_count", "deleted_at", "created", "last_modified"),
+			sqliteBoolColumns("hide_unavailable")...),
+		arrays: []sqliteArray{
+			{field: "tags", parent: "channel_id", table: "channel_tags", value: "tag"},
+			{field: "owners", parent: "channel_id", table: "channel_owners", value: "user_id"},
+			{field: "permission", parent: "channel_id", doc: &sqliteTable{
+				name:    "channel_permissions",
+				columns: append(sqliteColumns("user_id"), sqliteBoolColumns("admin", "read", "write")...),
+			}},
+			// 節目需先於節目順序新增（channel_program_order 參照 programs）
+			{field: "contents", parent: "channel_id", doc: sqliteProgram()},
+			{field: "contents_order", parent: "channel_id", table: "channel_program_order", value: "program_id", order: "order_index"},
+		},
+	},
+	"programs": sqliteProgram("channel_id"),
+	"counters": {
+		name:    "counters",
+		key:     "id",
+		columns: sqliteColumns("_id", "seq"),
+	},
+	"invitations": {
+		name: "invitations",
+		key:  "id",
+		columns: append(sqliteColumns("_id", "code", "note", "created_by", "max_uses", "uses", "expires_at",
+			"created", "last_modified"), sqliteBoolColumns("revoked")...),
+	},
+	"sessions": {
+		name:    "sessions",
+		key:     "id",
+		columns: sqliteColumns("_id", "user_id", "user_agent", "ip", "created", "last_seen", "expires_at"),
+	},
+	"api_tokens": {
+		name: "api_tokens",
+		key:  "id",
+		columns: append(sqliteColumns("_id", "user_id", "name", "prefix", "token_hash", "expires_at", "last_used", "created"),
+			sqliteColumn{field: "scopes", column: "scopes", kind: sqliteWords}),
+	},
+	"channel_shares": {
+		name: "channel_shares",
+		key:  "id",
+		columns: sqliteColumns("_id", "channel_id", "email", "role", "token_hash", "invited_by", "status", "user_id",
+			"expires_at", "created", "last_modified"),
+	},
+	"tags": {
+		name:    "tags",
+		key:     "id",
+		autoKey: true,
+		columns: append(sqliteColumns("_id", "slug", "parent", "created", "last_modified"),
+			sqliteColumn{field: "labels", column: "labels", kind: sqliteJSON, jsonType: reflect.TypeOf(map[string]string{})}),
+	},
+	"channel_revisions": {
+		name: "channel_revisions",
+		key:  "id",
+		columns: append(sqliteColumns("_id", "channel_id", "revision", "action", "created"),
+			sqliteColumn{field: "snapshot", column: "snapshot", kind: sqliteJSON, jsonType: reflect.TypeOf(models.ChannelSnapshot{})}),
+	},
+	"migrations": {
+		name:    "migrations",
+		key:     "id",
+		columns: sqliteColumns("_id", "description", "checksum", "executed_at"),
+	},
+}
+
+// SQLiteWhere 將過濾條件轉換為 collection 對應資料表的 WHERE 條件（欄位以資料表名稱限定，供 SQLite Repository 組合查詢）
+func SQLiteWhere(collection string, filter Filter) (string, []interface{}, error) {
+	t, err := sqliteTableFor(collection)
+	if err != nil {
+		return "", nil, err
+	}
+	q := &sqliteQuery{}
+	where, err := t.where(q, quoteIdent(t.name), filter)
+	return where, q.args, err
+}
+
+// SQLiteOrderBy 將排序規則轉換為 ORDER BY 子句（沒有排序規則時回傳空字串）
+func SQLiteOrderBy(collection string, sort Sort) (string, error) {
+	t, err := sqliteTableFor(collection)
+	if err != nil {
+		return "", err
+	}
+	return t.orderBy(quoteIdent(t.name), sort)
+}
+
+// sqliteTableFor 取得集合對應的資料表
+func sqliteTableFor(collection string) (*sqliteTable, error) {
+	t, ok := sqliteTables[collection]
+	if !ok {
+		return nil, fmt.Errorf("unknown SQLite collection: %s", collection)
+	}
+	return t, nil
+}
+
+// sqliteExecer *sql.DB 與 *sql.Tx 共用的操作
+type sqliteExecer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// sqliteQuery 組合 SQL 時依序累積的參數
+type sqliteQuery struct {
+	args    []interface{}
+	aliases int
+}
+
+// arg 加入參數並回傳佔位符號
+func (q *sqliteQuery) arg(value interface{}) string {
+	q.args = append(q.args, sqliteArg(value))
+	return "?"
+}
+
+// alias 產生子查詢的資料表別名
+func (q *sqliteQuery) alias() string {
+	q.aliases++
+	return fmt.Sprintf("sub%d", q.aliases)
+}
+
+// column 取得文件欄位對應的資料表欄位
+func (t *sqliteTable) column(field string) (*sqliteColumn, bool) {
+	for i := range t.columns {
+		if t.columns[i].field == field {
+			return &t.columns[i], true
+		}
+	}
+	return nil, false
+}
+
+// nested 取得 prefix 之下的巢狀欄位（例如 cover 之下的 cover.default）
+func (t *sqliteTable) nested(prefix string) []*sqliteColumn {
+	var columns []*sqliteColumn
+	for i := range t.columns {
+		if strings.HasPrefix(t.columns[i].field, prefix+".") {
+			columns = append(columns, &t.columns[i])
+		}
+	}
+	return columns
+}
+
+// array 取得路徑所在的陣列欄位（rest 為陣列之後的路徑）
+func (t *sqliteTable) array(path string) (*sqliteArray, string, bool) {
+	for i := range t.arrays {
+		field := t.arrays[i].field
+		if path == field {
+			return &t.arrays[i], "", true
+		}
+		if rest, ok := strings.CutPrefix(path, field+"."); ok {
+			return &t.arrays[i], rest, true
+		}
+	}
+	return nil, "", false
+}
+
+// name 取得陣列欄位的子表名稱
+func (a *sqliteArray) name() string {
+	if a.doc != nil {
+		return a.doc.name
+	}
+	return a.table
+}
+
+// where 將過濾條件轉換為 WHERE 條件（alias 為資料表在查詢中的名稱）
+func (t *sqliteTable) where(q *sqliteQuery, alias string, filter Filter) (string, error) {
+	if len(filter) == 0 {
+		return "1", nil
+	}
+
+	parts := make([]string, 0, len(filter))
+	for _, key := range slices.Sorted(maps.Keys(filter)) {
+		var part string
+		var err error
+		switch key {
+		case "$or", "$and":
+			part, err = t.whereList(q, alias, key, filter[key])
+		default:
+			part, err = t.whereField(q, alias, key, filter[key])
+		}
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " AND "), nil
+}
+
+// whereList 處理 $or 與 $and
+func (t *sqliteTable) whereList(q *sqliteQuery, alias, op string, value interface{}) (string, error) {
+	list, ok := sqliteList(value)
+	if !ok || len(list) == 0 {
+		return "", fmt.Errorf("%s must be a nonempty array", op)
+	}
+
+	parts := make([]string, 0, len(list))
+	for _, item := range list {
+		filter, ok := asFilter(item)
+		if !ok {
+			return "", fmt.Errorf("%s entries must be documents", op)
+		}
+		part, err := t.where(q, alias, filter)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, "("+part+")")
+	}
+
+	separator := " OR "
+	if op == "$and" {
+		separator = " AND "
+	}
+	return "(" + strings.Join(parts, separator) + ")", nil
+}
+
+// whereField 單一欄位的條件
+func (t *sqliteTable) whereField(q *sqliteQuery, alias, key string, operand interface{}) (string, error) {
+	if strings.HasPrefix(key, "$") {
+		return "", fmt.Errorf("unsupported query operator for SQLite: %s", key)
+	}
+	if column, ok := t.column(key); ok {
+		if column.kind == sqliteWords || column.kind == sqliteJSON {
+			return sqliteWhereNull(alias+"."+quoteIdent(column.column), key, operand)
+		}
+		return sqliteWhereValue(q, alias+"."+quoteIdent(column.column), operand)
+	}
+	if array, rest, ok := t.array(key); ok {
+		return array.where(q, alias, t.key, rest, operand)
+	}
+	if columns := t.nested(key); len(columns) > 0 {
+		return sqliteWhereNested(alias, key, columns, operand)
+	}
+	return "", fmt.Errorf("unknown field %s in SQLite table %s", key, t.name)
+}
+
+// where 陣列欄位的條件（rest 為陣列之後的路徑，數字表示陣列索引）
+func (a *sqliteArray) where(q *sqliteQuery, alias, parentKey, rest string, operand interface{}) (string, error) {
+	first, _, _ := strings.Cut(rest, ".")
+	if _, err := parseIndex(first); err == nil {
+		// 只支援 field.N 的 $exists（陣列至少有 N+1 個元素）
+		exists, ok := sqliteExistsOperand(operand)
+		if !ok || first != rest {
+			return "", fmt.Errorf("SQLite only supports $exists on array index %s.%s", a.field, rest)
+		}
+		sub := q.alias()
+		comparison := "<="
+		if exists {
+			comparison = ">"
+		}
+		return fmt.Sprintf("(SELECT COUNT(*) FROM %s AS %s WHERE %s.%s = %s.%s) %s %s",
+			quoteIdent(a.name()), sub, sub, quoteIdent(a.parent), alias, quoteIdent(parentKey), comparison, first), nil
+	}
+
+	if rest != "" {
+		if a.doc == nil {
+			return "", fmt.Errorf("unknown field %s.%s in SQLite table %s", a.field, rest, a.name())
+		}
+		return a.whereElements(q, alias, parentKey, operand, func(sub string, condition Filter) (string, error) {
+			return a.doc.whereField(q, sub, rest, condition)
+		})
+	}
+
+	// 陣列欄位本身：陣列一律存在
+	if exists, ok := sqliteExistsOperand(operand); ok {
+		return sqliteBoolSQL(exists), nil
+	}
+	if a.doc != nil {
+		return "", fmt.Errorf("SQLite cannot compare document array %s, query its fields instead", a.field)
+	}
+	return a.whereElements(q, alias, parentKey, operand, func(sub string, condition Filter) (string, error) {
+		return sqliteWhereValue(q, sub+"."+quoteIdent(a.value), condition)
+	})
+}
+
+// whereElements 陣列元素的條件（與 MongoDB 相同，每個運算子各自比對任一元素，$ne、$nin 表示沒有任何元素符合）
+func (a *sqliteArray) whereElements(q *sqliteQuery, alias, parentKey string, operand interface{}, cond func(sub string, condition Filter) (string, error)) (string, error) {
+	operators, ok := asFilter(operand)
+	if !ok || !isOperatorFilter(operators) {
+		operators = Filter{"$eq": operand}
+	}
+
+	parts := make([]string, 0, len(operators))
+	for _, op := range slices.Sorted(maps.Keys(operators)) {
+		value := operators[op]
+		condition := Filter{op: value}
+		negate := false
+		switch op {
+		case "$options":
+			continue // 與 $regex 一起處理
+		case "$regex":
+			if options, ok := operators["$options"]; ok {
+				condition["$options"] = options
+			}
+		case "$ne":
+			condition, negate = Filter{"$eq": value}, true
+		case "$nin":
+			condition, negate = Filter{"$in": value}, true
+		case "$exists":
+			if exists, _ := value.(bool); !exists {
+				condition, negate = Filter{"$exists": true}, true
+			}
+		}
+
+		sub := q.alias()
+		where, err := cond(sub, condition)
+		if err != nil {
+			return "", err
+		}
+		part := fmt.Sprintf("EXISTS (SELECT 1 FROM %s AS %s WHERE %s.%s = %s.%s AND %s)",
+			quoteIdent(a.name()), sub, sub, quoteIdent(a.parent), alias, quoteIdent(parentKey), where)
+		if negate {
+			part = "NOT " + part
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " AND "), nil
+}
+
+// sqliteWhereValue 單一值的條件（支援 $eq、$ne、$in、$nin、$exists、$regex 與大小比較）
+func sqliteWhereValue(q *sqliteQuery, expr string, operand interface{}) (string, error) {
+	operators, ok := asFilter(operand)
+	if !ok || !isOperatorFilter(operators) {
+		return sqliteEqual(q, expr, operand)
+	}
+
+	parts := make([]string, 0, len(operators))
+	for _, op := range slices.Sorted(maps.Keys(operators)) {
+		value := operators[op]
+		var part string
+		var err error
+		switch op {
+		case "$eq":
+			part, err = sqliteEqual(q, expr, value)
+		case "$ne":
+			// 與 MongoDB 相同，不存在的欄位也符合 $ne
+			if value == nil {
+				part = expr + " IS NOT NULL"
+			} else {
+				part = fmt.Sprintf("(%s IS NULL OR %s <> %s)", expr, expr, q.arg(value))
+			}
+		case "$in", "$nin":
+			part, err = sqliteIn(q, expr, value, op == "$nin")
+		case "$exists":
+			exists, ok := value.(bool)
+			if !ok {
+				return "", fmt.Errorf("$exists needs a boolean")
+			}
+			part = expr + " IS NOT NULL"
+			if !exists {
+				part = expr + " IS NULL"
+			}
+		case "$regex":
+			var pattern string
+			pattern, err = regexPattern(value, operators["$options"])
+			part = fmt.Sprintf("%s REGEXP %s", expr, q.arg(pattern))
+		case "$options":
+			continue // 與 $regex 一起處理
+		case "$gt", "$gte", "$lt", "$lte":
+			comparison := map[string]string{"$gt": ">", "$gte": ">=", "$lt": "<", "$lte": "<="}[op]
+			part = fmt.Sprintf("%s %s %s", expr, comparison, q.arg(value))
+		default:
+			return "", fmt.Errorf("unsupported query operator for SQLite: %s", op)
+		}
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " AND "), nil
+}
+
+// sqliteEqual 相等條件（nil 表示欄位為 NULL）
+func sqliteEqual(q *sqliteQuery, expr string, value interface{}) (string, error) {
+	if value == nil {
+		return expr + " IS NULL", nil
+	}
+	if _, ok := sqliteList(value); ok {
+		return "", fmt.Errorf("SQLite cannot compare %s with an array", expr)
+	}
+	if _, ok := asDocument(value); ok {
+		return "", fmt.Errorf("SQLite cannot compare %s with a document", expr)
+	}
+	return fmt.Sprintf("%s = %s", expr, q.arg(value)), nil
+}
+
+// sqliteIn $in 與 $nin 條件（nil 表示欄位為 NULL，與 MongoDB 相同，$nin 也符合不存在的欄位）
+func sqliteIn(q *sqliteQuery, expr string, operand interface{}, negate bool) (string, error) {
+	list, ok := sqliteList(operand)
+	if !ok {
+		return "", fmt.Errorf("$in and $nin need an array")
+	}
+
+	hasNil := false
+	placeholders := []string{}
+	for _, value := range list {
+		if value == nil {
+			hasNil = true
+			continue
+		}
+		placeholders = append(placeholders, q.arg(value))
+	}
+
+	if !negate {
+		parts := []string{}
+		if len(placeholders) > 0 {
+			parts = append(parts, fmt.Sprintf("%s IN (%s)", expr, strings.Join(placeholders, ", ")))
+		}
+		if hasNil {
+			parts = append(parts, expr+" IS NULL")
+		}
+		if len(parts) == 0 {
+			return "0", nil
+		}
+		return "(" + strings.Join(parts, " OR ") + ")", nil
+	}
+
+	switch {
+	case len(placeholders) == 0 && hasNil:
+		return expr + " IS NOT NULL", nil
+	case len(placeholders) == 0:
+		return "1", nil
+	case hasNil:
+		return fmt.Sprintf("(%s IS NOT NULL AND %s NOT IN (%s))", expr, expr, strings.Join(placeholders, ", ")), nil
+	}
+	return fmt.Sprintf("(%s IS NULL OR %s NOT IN (%s))", expr, expr, strings.Join(placeholders, ", ")), nil
+}
+
+// sqliteRegexPattern 將 $regex 與 $options 轉換為 Go 的正規表示式（支援 i、m、s）
+func regexPattern(value, options interface{}) (string, error) {
+	var pattern, flags string
+	switch v := value.(type) {
+	case string:
+		pattern = v
+	case primitive.Regex:
+		pattern, flags = v.Pattern, v.Options
+	default:
+		return "", fmt.Errorf("$regex needs a string")
+	}
+	if options != nil {
+		str, ok := options.(string)
+		if !ok {
+			return "", fmt.Errorf("$options needs a string")
+		}
+		flags += str
+	}
+
+	prefix := ""
+	for _, flag := range flags {
+		if !strings.ContainsRune("ims", flag) {
+			return "", fmt.Errorf("unsupported $regex option for SQLite: %c", flag)
+		}
+		if !strings.ContainsRune(prefix, flag) {
+			prefix += string(flag)
+		}
+	}
+	if prefix != "" {
+		pattern = "(?" + prefix + ")" + pattern
+	}
+	if _, err := regexp.Compile(pattern); err != nil {
+		return "", fmt.Errorf("invalid $regex: %w", err)
+	}
+	return pattern, nil
+}
+
+// sqliteWhereNull 只能比對是否為 NULL 的欄位條件（JSON 與字串陣列欄位）
+func sqliteWhereNull(expr, field string, operand interface{}) (string, error) {
+	if operand == nil {
+		return expr + " IS NULL", nil
+	}
+	if exists, ok := sqliteExistsOperand(operand); ok {
+		if exists {
+			return expr + " IS NOT NULL", nil
+		}
+		return expr + " IS NULL", nil
+	}
+	return "", fmt.Errorf("SQLite only supports null and $exists conditions on %s", field)
+}
+
+// sqliteWhereNested 巢狀文件本身的條件（只支援 null 與 $exists）
+func sqliteWhereNested(alias, field string, columns []*sqliteColumn, operand interface{}) (string, error) {
+	exists, ok := sqliteExistsOperand(operand)
+	if operand == nil {
+		exists, ok = false, true
+	}
+	if !ok {
+		return "", fmt.Errorf("SQLite only supports null and $exists conditions on %s", field)
+	}
+
+	parts := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if exists {
+			parts = append(parts, alias+"."+quoteIdent(column.column)+" IS NOT NULL")
+		} else {
+			parts = append(parts, alias+"."+quoteIdent(column.column)+" IS NULL")
+		}
+	}
+	if exists {
+		return "(" + strings.Join(parts, " OR ") + ")", nil
+	}
+	return "(" + strings.Join(parts, " AND ") + ")", nil
+}
+
+// sqliteExistsOperand 取得只有 $exists 的條件值
+func sqliteExistsOperand(operand interface{}) (bool, bool) {
+	operators, ok := asFilter(operand)
+	if !ok || len(operators) != 1 {
+		return false, false
+	}
+	exists, ok := operators["$exists"].(bool)
+	return exists, ok
+}
+
+// sqliteBoolSQL 將布林值轉換為 SQL 條件
+func sqliteBoolSQL(value bool) string {
+	if value {
+		return "1"
+	}
+	return "0"
+}
+
+// orderBy 將排序規則轉換為 ORDER BY 子句（只能依欄位排序）
+func (t *sqliteTable) orderBy(alias string, sort Sort) (string, error) {
+	if len(sort) == 0 {
+		return "", nil
+	}
+	parts := make([]string, 0, len(sort))
+	for _, s := range sort {
+		column, ok := t.column(s.Field)
+		if !ok {
+			return "", fmt.Errorf("SQLite cannot sort %s by %s", t.name, s.Field)
+		}
+		order := "ASC"
+		if s.Order < 0 {
+			order = "DESC"
+		}
+		parts = append(parts, fmt.Sprintf("%s.%s %s", alias, quoteIdent(column.column), order))
+	}
+	return " ORDER BY " + strings.Join(parts, ", "), nil
+}
+
+// sqliteLimit LIMIT 與 OFFSET 子句
+func sqliteLimit(limit, skip int64) string {
+	clause := ""
+	if limit > 0 {
+		clause = fmt.Sprintf(" LIMIT %d", limit)
+	} else if skip > 0 {
+		clause = " LIMIT -1"
+	}
+	if skip > 0 {
+		clause += fmt.Sprintf(" OFFSET %d", skip)
+	}
+	return clause
+}
+
+// find 查詢符合過濾條件的文件
+func (t *sqliteTable) find(ctx context.Context, db sqliteExecer, filter Filter, sort Sort, limit, skip int64) ([]bson.M, error) {
+	q := &sqliteQuery{}
+	alias := quoteIdent(t.name)
+	where, err := t.where(q, alias, filter)
+	if err != nil {
+		return nil, err
+	}
+	orderBy, err := t.orderBy(alias, sort)
+	if err != nil {
+		return nil, err
+	}
+	return t.load(ctx, db, "WHERE "+where+orderBy+sqliteLimit(limit, skip), q.args)
+}
+
+// first 取得第一筆符合條件的文件的 rowid 與主鍵
+func (t *sqliteTable) first(ctx context.Context, db sqliteExecer, where string, args []interface{}) (int64, interface{}, bool, error) {
+	alias := quoteIdent(t.name)
+	key := "NULL"
+	if t.key != "" {
+		key = alias + "." + quoteIdent(t.key)
+	}
+
+	rows, err := db.QueryContext(ctx, fmt.Sprintf("SELECT %s.rowid, %s FROM %s WHERE %s ORDER BY %s.rowid LIMIT 1",
+		alias, key, alias, where, alias), args...)
+	if err != nil {
+		return 0, nil, false, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	if !rows.Next() {
+		return 0, nil, false, rows.Err()
+	}
+	var rowid int64
+	var keyValue interface{}
+	if err := rows.Scan(&rowid, &keyValue); err != nil {
+		return 0, nil, false, err
+	}
+	return rowid, sqliteText(keyValue), true, nil
+}
+
+// firstMatch 取得第一筆符合過濾條件的文件的 rowid 與主鍵
+func (t *sqliteTable) firstMatch(ctx context.Context, db sqliteExecer, filter Filter) (int64, interface{}, bool, error) {
+	q := &sqliteQuery{}
+	where, err := t.where(q, quoteIdent(t.name), filter)
+	if err != nil {
+		return 0, nil, false, err
+	}
+	return t.first(ctx, db, where, q.args)
+}
+
+// loadRow 讀取 rowid 對應的文件
+func (t *sqliteTable) loadRow(ctx context.Context, db sqliteExecer, rowid int64) (bson.M, error) {
+	docs, err := t.load(ctx, db, fmt.Sprintf("WHERE %s.rowid = ?", quoteIdent(t.name)), []interface{}{rowid})
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 {
+		return nil, ErrNoDocuments
+	}
+	return docs[0], nil
+}
+
+// load 讀取文件並載入陣列欄位（clause 為 FROM 之後的 SQL，資料表以名稱作為別名）
+func (t *sqliteTable) load(ctx context.Context, db sqliteExecer, clause string, args []interface{}) ([]bson.M, error) {
+	alias := quoteIdent(t.name)
+	selects := []string{"NULL"}
+	if t.key != "" {
+		selects[0] = alias + "." + quoteIdent(t.key)
+	}
+	for _, column := range t.columns {
+		selects = append(selects, alias+"."+quoteIdent(column.column))
+	}
+
+	rows, err := db.QueryContext(ctx, fmt.Sprintf("SELECT %s FROM %s %s", strings.Join(selects, ", "), alias, clause), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var docs []bson.M
+	var keys []interface{}
+	for rows.Next() {
+		values := make([]interface{}, len(selects))
+		pointers := make([]interface{}, len(selects))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+
+		doc := bson.M{}
+		for i := range t.columns {
+			value, err := t.columns[i].decode(values[i+1])
+			if err != nil {
+				return nil, err
+			}
+			if value != nil {
+				setPath(doc, t.columns[i].field, value)
+			}
+		}
+		docs = append(docs, doc)
+		keys = append(keys, sqliteText(values[0]))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	// 先關閉查詢結果再載入陣列（SQLite 只使用單一連線）
+	_ = rows.Close()
+
+	for i, doc := range docs {
+		for j := range t.arrays {
+			items, err := t.arrays[j].load(ctx, db, keys[i])
+			if err != nil {
+				return nil, err
+			}
+			doc[t.arrays[j].field] = items
+		}
+	}
+	return docs, nil
+}
+
+// load 讀取陣列欄位
+func (a *sqliteArray) load(ctx context.Context, db sqliteExecer, parentKey interface{}) (bson.A, error) {
+	table := quoteIdent(a.name())
+	order := table + ".rowid"
+	if a.order != "" {
+		order = table + "." + quoteIdent(a.order)
+	}
+	clause := fmt.Sprintf("WHERE %s.%s = ? ORDER BY %s", table, quoteIdent(a.parent), order)
+
+	items := bson.A{}
+	if a.doc != nil {
+		docs, err := a.doc.load(ctx, db, clause, []interface{}{parentKey})
+		if err != nil {
+			return nil, err
+		}
+		for _, doc := range docs {
+			items = append(items, doc)
+		}
+		return items, nil
+	}
+
+	rows, err := db.QueryContext(ctx, fmt.Sprintf("SELECT %s.%s FROM %s %s", table, quoteIdent(a.value), table, clause), parentKey)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+	for rows.Next() {
+		var value interface{}
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		items = append(items, sqliteText(value))
+	}
+	return items, rows.Err()
+}
+
+// decode 將資料表欄位值轉換為文件中的值
+func (c *sqliteColumn) decode(value interface{}) (interface{}, error) {
+	value = sqliteText(value)
+	if value == nil {
+		return nil, nil
+	}
+
+	switch c.kind {
+	case sqliteBool:
+		n, ok := value.(int64)
+		return ok && n != 0, nil
+	case sqliteWords:
+		str, _ := value.(string)
+		words := bson.A{}
+		for _, word := range strings.Fields(str) {
+			words = append(words, word)
+		}
+		return words, nil
+	case sqliteJSON:
+		str, _ := value.(string)
+		target := reflect.New(c.jsonType)
+		if err := json.Unmarshal([]byte(str), target.Interface()); err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", c.field, err)
+		}
+		return encodeValue(target.Elem().Interface())
+	}
+	return value, nil
+}
+
+// encode 將文件中的值轉換為資料表欄位值
+func (c *sqliteColumn) encode(value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	switch c.kind {
+	case sqliteWords:
+		list, ok := sqliteList(value)
+		if !ok {
+			return nil, fmt.Errorf("%s must be an array", c.field)
+		}
+		words := make([]string, 0, len(list))
+		for _, item := range list {
+			words = append(words, fmt.Sprint(item))
+		}
+		return strings.Join(words, " "), nil
+	case sqliteJSON:
+		doc, ok := asDocument(value)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a document", c.field)
+		}
+		target := reflect.New(c.jsonType)
+		if err := decodeDocument(doc, target.Interface()); err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(target.Elem().Interface())
+		if err != nil {
+			return nil, err
+		}
+		return string(data), nil
+	}
+
+	if _, ok := sqliteList(value); ok {
+		return nil, fmt.Errorf("%s cannot store an array in SQLite", c.field)
+	}
+	if _, ok := asDocument(value); ok {
+		return nil, fmt.Errorf("%s cannot store a document in SQLite", c.field)
+	}
+	return sqliteArg(value), nil
+}
+
+// checkFields 確認文件的欄位都有對應的資料表欄位（避免資料在寫入時遺失）
+func (t *sqliteTable) checkFields(doc bson.M) error {
+	for key, value := range doc {
+		if _, ok := t.column(key); ok {
+			continue
+		}
+		if _, rest, ok := t.array(key); ok && rest == "" {
+			continue
+		}
+		if len(t.nested(key)) > 0 {
+			if value == nil {
+				continue
+			}
+			sub, ok := asDocument(value)
+			if !ok {
+				return fmt.Errorf("field %s must be a document", key)
+			}
+			for subKey := range sub {
+				if _, ok := t.column(key + "." + subKey); !ok {
+					return fmt.Errorf("field %s.%s is not stored in SQLite table %s", key, subKey, t.name)
+				}
+			}
+			continue
+		}
+		return fmt.Errorf("field %s is not stored in SQLite table %s", key, t.name)
+	}
+	return nil
+}
+
+// insert 新增文件（parent 不為空時為子文件，parentKey 為上層文件的主鍵），回傳 rowid 與主鍵
+func (t *sqliteTable) insert(ctx context.Context, db sqliteExecer, doc bson.M, parent string, parentKey interface{}) (int64, interface{}, error) {
+	if err := t.checkFields(doc); err != nil {
+		return 0, nil, err
+	}
+	if _, ok := doc["_id"]; !ok && t.key != "" && !t.autoKey {
+		// 與 MongoDB 相同，未指定 _id 時自動產生
+		doc["_id"] = primitive.NewObjectID().Hex()
+	}
+
+	q := &sqliteQuery{}
+	columns := []string{}
+	placeholders := []string{}
+	if parent != "" {
+		columns = append(columns, quoteIdent(parent))
+		placeholders = append(placeholders, q.arg(parentKey))
+	}
+	for i := range t.columns {
+		value, ok := lookupField(doc, t.columns[i].field)
+		if !ok || value == nil {
+			continue
+		}
+		encoded, err := t.columns[i].encode(value)
+		if err != nil {
+			return 0, nil, err
+		}
+		columns = append(columns, quoteIdent(t.columns[i].column))
+		placeholders = append(placeholders, q.arg(encoded))
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s DEFAULT VALUES", quoteIdent(t.name))
+	if len(columns) > 0 {
+		query = fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+			quoteIdent(t.name), strings.Join(columns, ", "), strings.Join(placeholders, ", "))
+	}
+	result, err := db.ExecContext(ctx, query, q.args...)
+	if err != nil {
+		return 0, nil, err
+	}
+	rowid, err := result.LastInsertId()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var key interface{}
+	if t.key != "" {
+		key = sqliteArg(doc["_id"])
+		if key == nil {
+			key = rowid // INTEGER PRIMARY KEY 即為 rowid
+		}
+	}
+
+	for i := range t.arrays {
+		value := doc[t.arrays[i].field]
+		if value == nil {
+			continue
+		}
+		items, ok := sqliteList(value)
+		if !ok {
+			return 0, nil, fmt.Errorf("field %s must be an array", t.arrays[i].field)
+		}
+		if err := t.arrays[i].insert(ctx, db, key, items); err != nil {
+			return 0, nil, err
+		}
+	}
+	return rowid, key, nil
+}
+
+// insert 新增陣列元素（純量陣列已存在的值會略過）
+func (a *sqliteArray) insert(ctx context.Context, db sqliteExecer, parentKey interface{}, items []interface{}) error {
+	if a.doc != nil {
+		for _, item := range items {
+			doc, ok := asDocument(item)
+			if !ok {
+				return fmt.Errorf("elements of %s must be documents", a.field)
+			}
+			if _, _, err := a.doc.insert(ctx, db, doc, a.parent, parentKey); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	table := quoteIdent(a.name())
+	next := 0
+	if a.order != "" && len(items) > 0 {
+		rows, err := db.QueryContext(ctx, fmt.Sprintf("SELECT COALESCE(MAX(%s), -1) + 1 FROM %s WHERE %s = ?",
+			quoteIdent(a.order), table, quoteIdent(a.parent)), parentKey)
+		if err != nil {
+			return err
+		}
+		if rows.Next() {
+			err = rows.Scan(&next)
+		}
+		_ = rows.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, item := range items {
+		if _, ok := asDocument(item); ok {
+			return fmt.Errorf("elements of %s must not be documents", a.field)
+		}
+		var err error
+		if a.order == "" {
+			_, err = db.ExecContext(ctx, fmt.Sprintf("INSERT OR IGNORE INTO %s (%s, %s) VALUES (?, ?)",
+				table, quoteIdent(a.parent), quoteIdent(a.value)), parentKey, sqliteArg(item))
+		} else {
+			_, err = db.ExecContext(ctx, fmt.Sprintf("INSERT OR IGNORE INTO %s (%s, %s, %s) VALUES (?, ?, ?)",
+				table, quoteIdent(a.parent), quoteIdent(a.value), quoteIdent(a.order)), parentKey, sqliteArg(item), next)
+			next++
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// replace 以 items 取代陣列的所有元素
+func (a *sqliteArray) replace(ctx context.Context, db sqliteExecer, parentKey interface{}, items []interface{}) error {
+	_, err := db.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE %s = ?", quoteIdent(a.name()), quoteIdent(a.parent)), parentKey)
+	if err != nil {
+		return err
+	}
+	return a.insert(ctx, db, parentKey, items)
+}
+
+// update 套用更新操作到 rowid 對應的文件（key 為文件主鍵，filter 用於 $ 位置運算子）
+func (t *sqliteTable) update(ctx context.Context, db sqliteExecer, rowid int64, key interface{}, filter Filter, update Update) error {
+	q := &sqliteQuery{}
+	sets := []string{}
+
+	for _, field := range slices.Sorted(maps.Keys(update.Set)) {
+		value, err := encodeValue(update.Set[field])
+		if err != nil {
+			return err
+		}
+		if field == "_id" {
+			return fmt.Errorf("cannot update _id in SQLite table %s", t.name)
+		}
+
+		if arrayField, rest, ok := strings.Cut(field, ".$."); ok {
+			if err := t.updateElement(ctx, db, key, filter, arrayField, rest, value); err != nil {
+				return err
+			}
+			continue
+		}
+		if column, ok := t.column(field); ok {
+			encoded, err := column.encode(value)
+			if err != nil {
+				return err
+			}
+			sets = append(sets, fmt.Sprintf("%s = %s", quoteIdent(column.column), q.arg(encoded)))
+			continue
+		}
+		if array, rest, ok := t.array(field); ok && rest == "" {
+			items, ok := sqliteList(value)
+			if !ok && value != nil {
+				return fmt.Errorf("field %s must be an array", field)
+			}
+			if err := array.replace(ctx, db, key, items); err != nil {
+				return err
+			}
+			continue
+		}
+		if columns := t.nested(field); len(columns) > 0 {
+			// 與 MongoDB 相同，整個巢狀文件被取代（沒有指定的欄位設為 NULL）
+			sub, ok := asDocument(value)
+			if !ok && value != nil {
+				return fmt.Errorf("field %s must be a document", field)
+			}
+			for _, column := range columns {
+				encoded, err := column.encode(sub[strings.TrimPrefix(column.field, field+".")])
+				if err != nil {
+					return err
+				}
+				sets = append(sets, fmt.Sprintf("%s = %s", quoteIdent(column.column), q.arg(encoded)))
+			}
+			continue
+		}
+		return fmt.Errorf("unknown field %s in SQLite table %s", field, t.name)
+	}
+
+	for _, field := range slices.Sorted(maps.Keys(update.Inc)) {
+		column, ok := t.column(field)
+		if !ok || column.kind != sqliteScalar {
+			return fmt.Errorf("cannot $inc field %s in SQLite table %s", field, t.name)
+		}
+		delta := update.Inc[field]
+		if _, ok := toFloat(delta); !ok {
+			return fmt.Errorf("cannot $inc %s by non-numeric value %v", field, delta)
+		}
+		name := quoteIdent(column.column)
+		sets = append(sets, fmt.Sprintf("%s = COALESCE(%s, 0) + %s", name, name, q.arg(delta)))
+	}
+
+	if len(sets) > 0 {
+		query := fmt.Sprintf("UPDATE %s SET %s WHERE rowid = ?", quoteIdent(t.name), strings.Join(sets, ", "))
+		if _, err := db.ExecContext(ctx, query, append(q.args, rowid)...); err != nil {
+			return err
+		}
+	}
+
+	for _, field := range slices.Sorted(maps.Keys(update.AddToSet)) {
+		if err := t.push(ctx, db, key, field, update.AddToSet[field], true); err != nil {
+			return err
+		}
+	}
+	for _, field := range slices.Sorted(maps.Keys(update.Push)) {
+		if err := t.push(ctx, db, key, field, update.Push[field], false); err != nil {
+			return err
+		}
+	}
+	for _, field := range slices.Sorted(maps.Keys(update.Pull)) {
+		if err := t.pull(ctx, db, key, field, update.Pull[field]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateElement 更新子文件陣列中第一個符合過濾條件的元素（$ 位置運算子，例如 contents.$.name）
+func (t *sqliteTable) updateElement(ctx context.Context, db sqliteExecer, key interface{}, filter Filter, field, rest string, value interface{}) error {
+	array, arrayRest, ok := t.array(field)
+	if !ok || arrayRest != "" || array.doc == nil {
+		return fmt.Errorf("SQLite cannot apply positional update to %s", field)
+	}
+
+	condition := Filter{}
+	for filterKey, filterValue := range filter {
+		sub, ok := strings.CutPrefix(filterKey, field+".")
+		if !ok {
+			continue
+		}
+		if first, _, _ := strings.Cut(sub, "."); first != "" {
+			if _, err := parseIndex(first); err == nil {
+				continue
+			}
+		}
+		condition[sub] = filterValue
+	}
+	if len(condition) == 0 {
+		return fmt.Errorf("positional update of %s needs a query on %s", field, field)
+	}
+
+	q := &sqliteQuery{}
+	alias := quoteIdent(array.doc.name)
+	where := fmt.Sprintf("%s.%s = %s", alias, quoteIdent(array.parent), q.arg(key))
+	elementWhere, err := array.doc.where(q, alias, condition)
+	if err != nil {
+		return err
+	}
+	rowid, elementKey, found, err := array.doc.first(ctx, db, where+" AND "+elementWhere, q.args)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("positional update of %s did not find a matching element", field)
+	}
+	return array.doc.update(ctx, db, rowid, elementKey, nil, Update{Set: map[string]interface{}{rest: value}})
+}
+
+// push 新增陣列元素（$push 與 $addToSet，支援 $each）
+func (t *sqliteTable) push(ctx context.Context, db sqliteExecer, key interface{}, field string, value interface{}, unique bool) error {
+	array, rest, ok := t.array(field)
+	if !ok || rest != "" {
+		return fmt.Errorf("SQLite cannot push to %s in table %s", field, t.name)
+	}
+	if unique && array.doc != nil {
+		return fmt.Errorf("SQLite does not support $addToSet on document array %s", field)
+	}
+
+	items := []interface{}{value}
+	if operators, ok := asFilter(value); ok {
+		if each, ok := operators["$each"]; ok {
+			if items, ok = sqliteList(each); !ok {
+				return fmt.Errorf("$each needs an array")
+			}
+		}
+	}
+	encoded := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		value, err := encodeValue(item)
+		if err != nil {
+			return err
+		}
+		encoded = append(encoded, value)
+	}
+	return array.insert(ctx, db, key, encoded)
+}
+
+// pull 移除符合條件的陣列元素（子文件陣列的條件為子文件的過濾條件）
+func (t *sqliteTable) pull(ctx context.Context, db sqliteExecer, key interface{}, field string, operand interface{}) error {
+	array, rest, ok := t.array(field)
+	if !ok || rest != "" {
+		return fmt.Errorf("SQLite cannot pull from %s in table %s", field, t.name)
+	}
+
+	q := &sqliteQuery{}
+	table := quoteIdent(array.name())
+	where := fmt.Sprintf("%s.%s = %s", table, quoteIdent(array.parent), q.arg(key))
+	var condition string
+	var err error
+	if array.doc != nil {
+		filter, ok := asFilter(operand)
+		if !ok || isOperatorFilter(filter) {
+			return fmt.Errorf("$pull from %s needs a document condition", field)
+		}
+		condition, err = array.doc.where(q, table, filter)
+	} else {
+		condition, err = sqliteWhereValue(q, table+"."+quoteIdent(array.value), operand)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE %s AND %s", table, where, condition), q.args...)
+	return err
+}
+
+// upsertDocument 以過濾條件中比較相等的欄位建立新文件（與 MongoDB 的 upsert 相同）
+func upsertDocument(filter Filter) (bson.M, error) {
+	doc := bson.M{}
+	for key, value := range filter {
+		if strings.HasPrefix(key, "$") {
+			continue
+		}
+		if operators, ok := asFilter(value); ok && isOperatorFilter(operators) {
+			continue
+		}
+		setPath(doc, key, value)
+	}
+	return encodeDocument(doc)
+}
+
+// setPath 以點號路徑設定文件欄位
+func setPath(doc bson.M, path string, value interface{}) {
+	parts := strings.Split(path, ".")
+	for _, part := range parts[:len(parts)-1] {
+		sub, ok := doc[part].(bson.M)
+		if !ok {
+			sub = bson.M{}
+			doc[part] = sub
+		}
+		doc = sub
+	}
+	doc[parts[len(parts)-1]] = value
+}
+
+// asDocument 將子文件轉換為 bson.M
+func asDocument(value interface{}) (bson.M, bool) {
+	switch v := value.(type) {
+	case bson.M:
+		return v, true
+	case map[string]interface{}:
+		return bson.M(v), true
+	case Filter:
+		return bson.M(v), true
+	case bson.D:
+		return v.Map(), true
+	}
+	return nil, false
+}
+
+// sqliteList 將陣列轉換為 []interface{}（[]byte 不視為陣列）
+func sqliteList(value interface{}) ([]interface{}, bool) {
+	if value == nil {
+		return nil, false
+	}
+	if _, ok := value.([]byte); ok {
+		return nil, false
+	}
+	list := reflect.ValueOf(value)
+	if list.Kind() != reflect.Slice && list.Kind() != reflect.Array {
+		return nil, false
+	}
+	items := make([]interface{}, list.Len())
+	for i := range items {
+		items[i] = list.Index(i).Interface()
+	}
+	return items, true
+}
+
+// sqliteArg 將值轉換為 SQLite driver 可使用的參數
+func sqliteArg(value interface{}) interface{} {
+	switch v := value.(type) {
+	case primitive.DateTime:
+		return v.Time()
+	case int32:
+		return int64(v)
+	}
+	return value
+}
+
+// sqliteText 將 driver 回傳的 []byte 轉換為字串
+func sqliteText(value interface{}) interface{} {
+	if b, ok := value.([]byte); ok {
+		return string(b)
+	}
+	return value
+}
+
+// quoteIdent 以雙引號標示 SQL 識別字
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
@@ -0,0 +1,115 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// FullTextSearch 是否可使用 FTS5 全文搜尋（需以 sqlite_fts5 build tag 編譯）
+func (d *SQLiteDatabase) FullTextSearch() bool {
+	return d.fullTextSearch
+}
+
+// EnsureSearchIndex 建立頻道與節目的 FTS5 全文搜尋索引
+// 未啟用 FTS5 模組時略過（搜尋改用 LIKE 比對）
+func (d *SQLiteDatabase) EnsureSearchIndex(ctx context.Context) error {
+	// channels 的主鍵為文字，rowid 可能在 VACUUM 後改變，因此另存 channel_id；
+	// programs 使用 INTEGER PRIMARY KEY，直接以節目 ID 作為 rowid
+	tables := []struct {
+		name   string
+		create string
+		fill   string
+	}{
+		{
+			name:   "channels_fts",
+			create: `CREATE VIRTUAL TABLE channels_fts USING fts5(channel_id UNINDEXED, name, desc, tokenize = 'trigram')`,
+			fill:   `INSERT INTO channels_fts (channel_id, name, desc) SELECT id, name, desc FROM channels`,
+		},
+		{
+			name:   "programs_fts",
+			create: `CREATE VIRTUAL TABLE programs_fts USING fts5(name, desc, tokenize = 'trigram')`,
+			fill:   `INSERT INTO programs_fts (rowid, name, desc) SELECT id, name, desc FROM programs`,
+		},
+	}
+
+	for _, table := range tables {
+		exists, err := d.hasTable(ctx, table.name)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		if _, err := d.conn.ExecContext(ctx, table.create); err != nil {
+			if strings.Contains(err.Error(), "no such module") {
+				return nil
+			}
+			return fmt.Errorf("failed to create %s: %w", table.name, err)
+		}
+		// 既有資料庫建立索引時補上現有資料
+		if _, err := d.conn.ExecContext(ctx, table.fill); err != nil {
+			return fmt.Errorf("failed to fill %s: %w", table.name, err)
+		}
+	}
+
+	triggers := []string{
+		`CREATE TRIGGER IF NOT EXISTS channels_fts_insert AFTER INSERT ON channels BEGIN
+			INSERT INTO channels_fts (channel_id, name, desc) VALUES (new.id, new.name, new.desc);
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS channels_fts_update AFTER UPDATE OF name, desc ON channels BEGIN
+			DELETE FROM channels_fts WHERE channel_id = old.id;
+			INSERT INTO channels_fts (channel_id, name, desc) VALUES (new.id, new.name, new.desc);
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS channels_fts_delete AFTER DELETE ON channels BEGIN
+			DELETE FROM channels_fts WHERE channel_id = old.id;
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS programs_fts_insert AFTER INSERT ON programs BEGIN
+			INSERT INTO programs_fts (rowid, name, desc) VALUES (new.id, new.name, new.desc);
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS programs_fts_update AFTER UPDATE OF name, desc ON programs BEGIN
+			DELETE FROM programs_fts WHERE rowid = old.id;
+			INSERT INTO programs_fts (rowid, name, desc) VALUES (new.id, new.name, new.desc);
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS programs_fts_delete AFTER DELETE ON programs BEGIN
+			DELETE FROM programs_fts WHERE rowid = old.id;
+		END`,
+	}
+	for _, trigger := range triggers {
+		if _, err := d.conn.ExecContext(ctx, trigger); err != nil {
+			return fmt.Errorf("failed to create search trigger: %w", err)
+		}
+	}
+
+	d.fullTextSearch = true
+	return nil
+}
+
+// DropSearchIndex 刪除 FTS5 全文搜尋索引與同步的 trigger（搜尋改用 LIKE 比對）
+func (d *SQLiteDatabase) DropSearchIndex(ctx context.Context) error {
+	statements := []string{
+		`DROP TRIGGER IF EXISTS channels_fts_insert`,
+		`DROP TRIGGER IF EXISTS channels_fts_update`,
+		`DROP TRIGGER IF EXISTS channels_fts_delete`,
+		`DROP TRIGGER IF EXISTS programs_fts_insert`,
+		`DROP TRIGGER IF EXISTS programs_fts_update`,
+		`DROP TRIGGER IF EXISTS programs_fts_delete`,
+		`DROP TABLE IF EXISTS channels_fts`,
+		`DROP TABLE IF EXISTS programs_fts`,
+	}
+	for _, statement := range statements {
+		if _, err := d.conn.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+
+	d.fullTextSearch = false
+	return nil
+}
+
+// hasTable 檢查資料表是否存在
+func (d *SQLiteDatabase) hasTable(ctx context.Context, name string) (bool, error) {
+	var count int
+	err := d.conn.QueryRowContext(ctx, `SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`, name).Scan(&count)
+	return count > 0, err
+}
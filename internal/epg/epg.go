@@ -0,0 +1,182 @@
+package epg
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/higgstv/higgstv-go/internal/models"
+	"github.com/higgstv/higgstv-go/internal/schedule"
+)
+
+// GeneratorName XMLTV generator-info-name
+const GeneratorName = "HiggsTV"
+
+// xmltvTimeLayout XMLTV 時間格式
+const xmltvTimeLayout = "20060102150405 -0700"
+
+// Guide 電子節目表
+type Guide struct {
+	Generated time.Time `json:"generated"`
+	From      time.Time `json:"from"`
+	To        time.Time `json:"to"`
+	Channels  []Channel `json:"channels"`
+}
+
+// Channel 節目表中的頻道
+type Channel struct {
+	ID         string      `json:"id"`
+	Name       string      `json:"name"`
+	Desc       string      `json:"desc"`
+	Icon       string      `json:"icon,omitempty"`
+	Programmes []Programme `json:"programmes"`
+}
+
+// Programme 節目表中的一個播出時段
+type Programme struct {
+	ProgramID int       `json:"program_id"`
+	Title     string    `json:"title"`
+	Desc      string    `json:"desc"`
+	Start     time.Time `json:"start"`
+	Stop      time.Time `json:"stop"`
+	Duration  int       `json:"duration"` // 秒
+	Tags      []int     `json:"tags"`
+}
+
+// Build 建立 [from, to) 區間的節目表，頻道需已載入節目（contents）
+// maxSlots 為每個頻道的時段上限（<= 0 表示不限制）
+func Build(channels []models.Channel, from, to time.Time, maxSlots int) *Guide {
+	guide := &Guide{
+		Generated: time.Now().UTC(),
+		From:      from.UTC(),
+		To:        to.UTC(),
+		Channels:  make([]Channel, 0, len(channels)),
+	}
+
+	for i := range channels {
+		channel := &channels[i]
+		entry := Channel{
+			ID:         channel.ID,
+			Name:       channel.Name,
+			Desc:       channel.Desc,
+			Programmes: []Programme{},
+		}
+		if channel.Cover != nil {
+			entry.Icon = channel.Cover.Default
+		}
+
+		for _, slot := range schedule.Build(channel).Between(from, to, maxSlots) {
+			tags := slot.Program.Tags
+			if tags == nil {
+				tags = []int{}
+			}
+			entry.Programmes = append(entry.Programmes, Programme{
+				ProgramID: slot.Program.ID,
+				Title:     slot.Program.Name,
+				Desc:      slot.Program.Desc,
+				Start:     slot.Start.UTC(),
+				Stop:      slot.End.UTC(),
+				Duration:  slot.Program.Duration,
+				Tags:      tags,
+			})
+		}
+
+		guide.Channels = append(guide.Channels, entry)
+	}
+
+	return guide
+}
+
+// WriteJSON 輸出 JSON 節目表
+func WriteJSON(w io.Writer, guide *Guide) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(guide)
+}
+
+// xmltvDocument XMLTV 根節點
+type xmltvDocument struct {
+	XMLName       xml.Name         `xml:"tv"`
+	GeneratorName string           `xml:"generator-info-name,attr"`
+	Date          string           `xml:"date,attr"`
+	Channels      []xmltvChannel   `xml:"channel"`
+	Programmes    []xmltvProgramme `xml:"programme"`
+}
+
+type xmltvChannel struct {
+	ID          string     `xml:"id,attr"`
+	DisplayName string     `xml:"display-name"`
+	Icon        *xmltvIcon `xml:"icon,omitempty"`
+}
+
+type xmltvIcon struct {
+	Src string `xml:"src,attr"`
+}
+
+type xmltvProgramme struct {
+	Start      string       `xml:"start,attr"`
+	Stop       string       `xml:"stop,attr"`
+	Channel    string       `xml:"channel,attr"`
+	Title      string       `xml:"title"`
+	Desc       string       `xml:"desc,omitempty"`
+	Categories []string     `xml:"category"`
+	Length     *xmltvLength `xml:"length,omitempty"`
+}
+
+type xmltvLength struct {
+	Units string `xml:"units,attr"`
+	Value int    `xml:",chardata"`
+}
+
+// WriteXMLTV 輸出 XMLTV 節目表
+func WriteXMLTV(w io.Writer, guide *Guide) error {
+	doc := xmltvDocument{
+		GeneratorName: GeneratorName,
+		Date:          guide.Generated.Format(xmltvTimeLayout),
+		Channels:      make([]xmltvChannel, 0, len(guide.Channels)),
+		Programmes:    []xmltvProgramme{},
+	}
+
+	for _, channel := range guide.Channels {
+		entry := xmltvChannel{
+			ID:          channel.ID,
+			DisplayName: channel.Name,
+		}
+		if channel.Icon != "" {
+			entry.Icon = &xmltvIcon{Src: channel.Icon}
+		}
+		doc.Channels = append(doc.Channels, entry)
+
+		for _, programme := range channel.Programmes {
+			categories := make([]string, 0, len(programme.Tags))
+			for _, tag := range programme.Tags {
+				categories = append(categories, strconv.Itoa(tag))
+			}
+			doc.Programmes = append(doc.Programmes, xmltvProgramme{
+				Start:      programme.Start.Format(xmltvTimeLayout),
+				Stop:       programme.Stop.Format(xmltvTimeLayout),
+				Channel:    channel.ID,
+				Title:      programme.Title,
+				Desc:       programme.Desc,
+				Categories: categories,
+				Length:     &xmltvLength{Units: "seconds", Value: programme.Duration},
+			})
+		}
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, `<!DOCTYPE tv SYSTEM "xmltv.dtd">`+"\n"); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,59 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/repository"
+	"github.com/higgstv/higgstv-go/internal/service"
+	"github.com/higgstv/higgstv-go/pkg/availability"
+	"github.com/higgstv/higgstv-go/pkg/logger"
+)
+
+// RunAvailabilityCheck 定期檢查節目影片是否仍可播放，直到 ctx 結束
+// 每次最多檢查 batch 個超過 recheckAfter 未檢查的節目，啟動時立即執行一次
+func RunAvailabilityCheck(ctx context.Context, db database.Database, checker availability.Checker, interval, recheckAfter time.Duration, batch int) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		checkAvailability(ctx, db, checker, recheckAfter, batch)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkAvailability 執行一次可播放狀態檢查
+func checkAvailability(ctx context.Context, db database.Database, checker availability.Checker, recheckAfter time.Duration, batch int) {
+	availabilityService := service.NewAvailabilityService(
+		repository.NewChannelRepository(db),
+		repository.NewProgramRepository(db),
+		checker,
+	)
+
+	checked, err := availabilityService.CheckAll(ctx, recheckAfter, batch)
+	if logger.Logger == nil {
+		return
+	}
+	if err != nil && ctx.Err() == nil {
+		logger.Logger.Warn("Availability check failed",
+			zap.Int("checked", checked),
+			zap.Error(err),
+		)
+		return
+	}
+	if checked > 0 {
+		logger.Logger.Info("Availability check completed", zap.Int("checked", checked))
+	}
+}
@@ -0,0 +1,57 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/repository"
+	"github.com/higgstv/higgstv-go/internal/service"
+	"github.com/higgstv/higgstv-go/pkg/logger"
+)
+
+// RunMetadataBackfill 定期為既有節目補齊影片資訊，直到 ctx 結束
+// 每次最多查詢 batch 個節目，啟動時立即執行一次
+func RunMetadataBackfill(ctx context.Context, db database.Database, interval time.Duration, batch int) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		backfillMetadata(ctx, db, batch)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// backfillMetadata 執行一次影片資訊補齊
+func backfillMetadata(ctx context.Context, db database.Database, batch int) {
+	programService := service.NewProgramService(
+		repository.NewProgramRepository(db),
+		repository.NewChannelRepository(db),
+	)
+
+	processed, err := programService.BackfillMetadata(ctx, batch)
+	if logger.Logger == nil {
+		return
+	}
+	if err != nil && ctx.Err() == nil {
+		logger.Logger.Warn("Metadata backfill failed",
+			zap.Int("processed", processed),
+			zap.Error(err),
+		)
+		return
+	}
+	if processed > 0 {
+		logger.Logger.Info("Metadata backfill completed", zap.Int("processed", processed))
+	}
+}
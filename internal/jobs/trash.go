@@ -0,0 +1,60 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/repository"
+	"github.com/higgstv/higgstv-go/internal/service"
+	"github.com/higgstv/higgstv-go/pkg/logger"
+)
+
+// RunTrashPurge 定期永久刪除在垃圾桶超過 retention 的頻道，直到 ctx 結束
+// 每次最多刪除 batch 個頻道，啟動時立即執行一次
+func RunTrashPurge(ctx context.Context, db database.Database, interval, retention time.Duration, batch int) {
+	if interval <= 0 {
+		return
+	}
+	if retention <= 0 {
+		retention = service.DefaultTrashRetention
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purgeTrash(ctx, db, retention, batch)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purgeTrash 執行一次垃圾桶清除
+func purgeTrash(ctx context.Context, db database.Database, retention time.Duration, batch int) {
+	channelService := service.NewChannelService(
+		repository.NewChannelRepository(db),
+		repository.NewUserRepository(db),
+	)
+
+	purged, err := channelService.PurgeExpired(ctx, time.Now().Add(-retention), batch)
+	if logger.Logger == nil {
+		return
+	}
+	if err != nil && ctx.Err() == nil {
+		logger.Logger.Warn("Trash purge failed",
+			zap.Int("purged", purged),
+			zap.Error(err),
+		)
+		return
+	}
+	if purged > 0 {
+		logger.Logger.Info("Trash purge completed", zap.Int("purged", purged))
+	}
+}
@@ -0,0 +1,55 @@
+package migration
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Create 在 dir（internal/migration/sql）中為每個 SQL 資料庫後端建立新遷移的 up、down SQL 檔案，回傳建立的檔案
+// 遷移序號為現有遷移（含 dir 中尚未編譯進程式的檔案）的最大序號加一；MongoDB 與資料遷移請在 migrations 中以相同 ID 加上 Go 步驟
+func Create(dir, name string) ([]string, error) {
+	name = strings.ToLower(strings.NewReplacer("-", "_", " ", "_").Replace(strings.TrimSpace(name)))
+	if !validID("0_" + name) {
+		return nil, fmt.Errorf("invalid migration name %q (use letters, digits and underscores)", name)
+	}
+
+	defs, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+	added, err := loadMigrations(os.DirFS(dir), nil)
+	if err != nil {
+		return nil, err
+	}
+	next := 1
+	for _, def := range append(defs, added...) {
+		number, _, _ := strings.Cut(def.ID, "_")
+		if n, err := strconv.Atoi(number); err == nil && n >= next {
+			next = n + 1
+		}
+	}
+	id := fmt.Sprintf("%03d_%s", next, name)
+
+	files := []string{}
+	for _, backend := range sqlBackends {
+		backendDir := filepath.Join(dir, string(backend))
+		if err := os.MkdirAll(backendDir, 0o755); err != nil {
+			return files, err
+		}
+		contents := map[string]string{
+			"up":   fmt.Sprintf("-- %s\n-- %s 向上遷移\n", strings.ReplaceAll(name, "_", " "), backend),
+			"down": fmt.Sprintf("-- 還原 %s\n-- %s 向下遷移（還原 %s.up.sql 的變更）\n", id, backend, id),
+		}
+		for _, direction := range []string{"up", "down"} {
+			file := filepath.Join(backendDir, id+"."+direction+".sql")
+			if err := os.WriteFile(file, []byte(contents[direction]), 0o644); err != nil {
+				return files, err
+			}
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
@@ -2,141 +2,351 @@ package migration
 
 import (
 	"context"
+	"embed"
+	"errors"
 	"fmt"
+	"io/fs"
+	"path"
+	"sort"
 	"strings"
 	"time"
 
-	"go.uber.org/zap"
+	"go.mongodb.org/mongo-driver/bson"
 
 	"github.com/higgstv/higgstv-go/internal/database"
-	"github.com/higgstv/higgstv-go/pkg/logger"
+	"github.com/higgstv/higgstv-go/internal/models"
+	"github.com/higgstv/higgstv-go/internal/repository"
+	"github.com/higgstv/higgstv-go/pkg/uuidutil"
 )
 
 // Migration 遷移結構
+// SQL 由 sql/<資料庫類型>/<ID>.up.sql、<ID>.down.sql 載入；Up、Down 為所有資料庫後端共用的 Go 步驟
+// 向上遷移先執行 SQL 再執行 Up，向下遷移先執行 Down 再執行 SQL
 type Migration struct {
 	ID          string
 	Description string
 	Up          func(ctx context.Context, db database.Database) error
 	Down        func(ctx context.Context, db database.Database) error
+	NoTx        bool // 不在交易中執行（步驟中有允許失敗的操作時使用，例如 PostgreSQL 交易中的錯誤會中止整個交易）
+	SQL         map[database.DatabaseType]SQL
 }
 
+// SQL 單一資料庫後端的遷移 SQL
+type SQL struct {
+	Up   string
+	Down string
+}
+
+// sqlFiles 遷移 SQL（sql/<資料庫類型>/<ID>.up.sql、<ID>.down.sql）
+//
+//go:embed sql
+var sqlFiles embed.FS
+
+// sqlBackends 使用 SQL 遷移的資料庫類型
+var sqlBackends = []database.DatabaseType{database.DatabaseTypeSQLite, database.DatabaseTypePostgres}
+
 var migrations = []Migration{
 	{
 		ID:          "001_initial_schema",
 		Description: "建立初始資料庫結構和索引",
-		Up: func(ctx context.Context, db database.Database) error {
-			// 這個遷移已經在 database/indexes_unified.go 中處理
+		// SQLite 與 PostgreSQL 的資料表由 SQL 建立，MongoDB 的索引由 database.EnsureIndexes 建立
+	},
+	{
+		ID:          "002_legacy_invitation_code",
+		Description: "將原本寫死的邀請碼 sixpens 建立為不限次數的邀請碼（可由管理員撤銷）",
+		// 舊版 SQLite 資料庫在此時還沒有 invitations 表，改由 005_post_baseline_tables 建立表後再建立邀請碼
+		Up: createLegacyInvitation,
+		Down: func(ctx context.Context, db database.Database) error {
+			ok, err := hasInvitationTable(ctx, db)
+			if err != nil || !ok {
+				return err
+			}
+			invitationRepo := repository.NewInvitationRepository(db)
+			existing, err := invitationRepo.FindByCode(ctx, legacyInvitationCode)
+			if err != nil || existing == nil {
+				return err
+			}
+			return invitationRepo.Delete(ctx, existing.ID)
+		},
+	},
+	{
+		ID:          "003_sqlite_legacy_columns",
+		Description: "為舊版 SQLite 資料庫補上後續新增的欄位（原本由啟動時的 ensureSchema 補上）",
+		Up:          addLegacySQLiteColumns,
+		Down: func(ctx context.Context, db database.Database) error {
+			// 欄位屬於初始結構，由 001_initial_schema 的向下遷移一併刪除
 			return nil
 		},
+	},
+	{
+		ID:          "004_search_index",
+		Description: "建立全文搜尋索引（SQLite 為 FTS5，PostgreSQL 為 pg_trgm 索引，無法建立時略過）",
+		Up:          createSearchIndex,
+		Down:        dropSearchIndex,
+		NoTx:        true,
+	},
+	{
+		ID:          "005_post_baseline_tables",
+		Description: "為舊版 SQLite 資料庫建立初始結構之後新增的表與索引（PostgreSQL 的 001_initial_schema 已包含）",
+		Up:          createLegacyInvitation,
+	},
+	{
+		ID:          "006_channel_visibility",
+		Description: "為加入可見度之前建立的頻道設定可見度 public",
+		Up:          migrateChannelVisibility,
 		Down: func(ctx context.Context, db database.Database) error {
-			// 不實作向下遷移
+			// 無法分辨原本沒有可見度的頻道，保留 visibility
 			return nil
 		},
 	},
 }
 
-// RunMigrations 執行所有未執行的遷移
-func RunMigrations(ctx context.Context, db database.Database) error {
-	migrationsColl := db.Collection("migrations")
-
-	// 取得已執行的遷移
-	executed, err := getExecutedMigrations(ctx, migrationsColl)
-	if err != nil {
-		return fmt.Errorf("failed to get executed migrations: %w", err)
+// migrateChannelVisibility 將沒有可見度的既有頻道設為公開頻道
+func migrateChannelVisibility(ctx context.Context, db database.Database) error {
+	public := string(models.ChannelVisibilityPublic)
+	switch d := db.(type) {
+	case *database.SQLiteDatabase:
+		_, err := d.GetConn().ExecContext(ctx,
+			"UPDATE channels SET visibility = ? WHERE visibility IS NULL OR visibility = ''", public)
+		return err
+	case *database.PostgresDatabase:
+		_, err := d.GetConn().ExecContext(ctx,
+			"UPDATE channels SET visibility = $1 WHERE visibility IS NULL OR visibility = ''", public)
+		return err
+	case *database.MongoDBDatabase:
+		_, err := d.GetDatabase().Collection("channels").UpdateMany(ctx,
+			bson.M{"visibility": bson.M{"$in": bson.A{nil, ""}}}, // null 也符合沒有 visibility 欄位的文件
+			bson.M{"$set": bson.M{"visibility": public}},
+		)
+		return err
 	}
+	return nil
+}
 
-	executedMap := make(map[string]bool)
-	for _, id := range executed {
-		executedMap[id] = true
-	}
+// legacySQLiteColumns 初始結構之後才新增、舊版 SQLite 資料庫可能缺少的欄位
+var legacySQLiteColumns = []struct {
+	table      string
+	column     string
+	definition string
+}{
+	{"channels", "schedule_epoch", "DATETIME"},
+	{"channels", "schedule_loop", "TEXT"},
+	{"programs", "video_id", "TEXT"},
+	{"programs", "metadata_at", "DATETIME"},
+	{"programs", "availability", "TEXT"},
+	{"programs", "last_checked", "DATETIME"},
+	{"channels", "hide_unavailable", "INTEGER NOT NULL DEFAULT 0"},
+	{"users", "invitation_id", "TEXT"},
+	{"channels", "visibility", "TEXT NOT NULL DEFAULT 'public'"},
+	{"channels", "revision", "INTEGER NOT NULL DEFAULT 0"},
+	{"channels", "deleted_at", "DATETIME"},
+	{"channels", "forked_from", "TEXT"},
+	{"channels", "fork_count", "INTEGER NOT NULL DEFAULT 0"},
+}
 
-	// 執行未執行的遷移
-	for _, migration := range migrations {
-		if executedMap[migration.ID] {
-			if logger.Logger != nil {
-				logger.Logger.Info("Migration already executed",
-					zap.String("id", migration.ID),
-					zap.String("description", migration.Description),
-				)
-			}
+// addLegacySQLiteColumns 若 SQLite 表中缺少後續新增的欄位則新增（CREATE TABLE IF NOT EXISTS 不會修改既有的表）
+func addLegacySQLiteColumns(ctx context.Context, db database.Database) error {
+	d, ok := db.(*database.SQLiteDatabase)
+	if !ok {
+		return nil
+	}
+	for _, col := range legacySQLiteColumns {
+		exists, err := sqliteHasColumn(ctx, d.GetConn(), col.table, col.column)
+		if err != nil {
+			return err
+		}
+		if exists {
 			continue
 		}
-
-		if logger.Logger != nil {
-			logger.Logger.Info("Running migration",
-				zap.String("id", migration.ID),
-				zap.String("description", migration.Description),
-			)
+		if _, err := d.GetConn().ExecContext(ctx,
+			fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", col.table, col.column, col.definition)); err != nil {
+			return fmt.Errorf("failed to add column %s.%s: %w", col.table, col.column, err)
 		}
+	}
+	// 依賴新增欄位的索引
+	_, err := d.GetConn().ExecContext(ctx,
+		"CREATE INDEX IF NOT EXISTS idx_channels_deleted_at ON channels(deleted_at) WHERE deleted_at IS NOT NULL")
+	return err
+}
+
+// sqliteHasColumn 檢查 SQLite 表中是否有指定欄位
+func sqliteHasColumn(ctx context.Context, conn *database.SQLConn, table, column string) (bool, error) {
+	var count int
+	err := conn.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?", table, column).Scan(&count)
+	return count > 0, err
+}
+
+// createSearchIndex 建立全文搜尋索引（MongoDB 的 text 索引由 database.EnsureIndexes 建立）
+func createSearchIndex(ctx context.Context, db database.Database) error {
+	switch d := db.(type) {
+	case *database.SQLiteDatabase:
+		return d.EnsureSearchIndex(ctx)
+	case *database.PostgresDatabase:
+		d.EnsureSearchIndex(ctx)
+	}
+	return nil
+}
+
+// dropSearchIndex 刪除全文搜尋索引
+func dropSearchIndex(ctx context.Context, db database.Database) error {
+	switch d := db.(type) {
+	case *database.SQLiteDatabase:
+		return d.DropSearchIndex(ctx)
+	case *database.PostgresDatabase:
+		return d.DropSearchIndex(ctx)
+	}
+	return nil
+}
+
+// legacyInvitationCode 原本寫死在註冊流程中的邀請碼
+const legacyInvitationCode = "sixpens"
+
+// createLegacyInvitation 建立不限次數的舊版邀請碼（已存在或還沒有 invitations 表時略過）
+func createLegacyInvitation(ctx context.Context, db database.Database) error {
+	ok, err := hasInvitationTable(ctx, db)
+	if err != nil || !ok {
+		return err
+	}
+	invitationRepo := repository.NewInvitationRepository(db)
+	existing, err := invitationRepo.FindByCode(ctx, legacyInvitationCode)
+	if err != nil || existing != nil {
+		return err
+	}
+	return invitationRepo.Create(ctx, &models.Invitation{
+		ID:   uuidutil.NewBase64UUID(),
+		Code: legacyInvitationCode,
+		Note: "legacy invitation code",
+	})
+}
+
+// hasInvitationTable 檢查 invitations 表是否存在（只有 SQLite 會缺少）
+func hasInvitationTable(ctx context.Context, db database.Database) (bool, error) {
+	d, ok := db.(*database.SQLiteDatabase)
+	if !ok {
+		return true, nil
+	}
+	var count int
+	err := d.GetConn().QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'invitations'").Scan(&count)
+	return count > 0, err
+}
+
+// Migrations 回傳所有遷移（依 ID 排序，含 sql 目錄中的 SQL）
+func Migrations() ([]Migration, error) {
+	files, err := fs.Sub(sqlFiles, "sql")
+	if err != nil {
+		return nil, err
+	}
+	return loadMigrations(files, migrations)
+}
 
-		// 執行遷移
-		if err := migration.Up(ctx, db); err != nil {
-			return fmt.Errorf("migration %s failed: %w", migration.ID, err)
+// loadMigrations 將 files 中 <資料庫類型>/<ID>.up.sql、<ID>.down.sql 併入 Go 定義的遷移（只有 SQL 的遷移以檔名作為 ID）
+func loadMigrations(files fs.FS, defined []Migration) ([]Migration, error) {
+	byID := make(map[string]*Migration, len(defined))
+	result := make([]*Migration, 0, len(defined))
+	for i := range defined {
+		m := defined[i]
+		if _, ok := byID[m.ID]; ok {
+			return nil, fmt.Errorf("duplicate migration %s", m.ID)
 		}
+		m.SQL = map[database.DatabaseType]SQL{}
+		byID[m.ID] = &m
+		result = append(result, &m)
+	}
 
-		// 記錄遷移
-		migrationDoc := map[string]interface{}{
-			"_id":         migration.ID,
-			"description": migration.Description,
-			"executed_at": time.Now(),
+	for _, backend := range sqlBackends {
+		entries, err := fs.ReadDir(files, string(backend))
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, err
 		}
-		if err := migrationsColl.InsertOne(ctx, migrationDoc); err != nil {
-			// 如果是重複鍵錯誤，忽略（可能是在測試環境中重複執行）
-			if !isDuplicateMigrationError(err) {
-				return fmt.Errorf("failed to record migration %s: %w", migration.ID, err)
+		for _, entry := range entries {
+			id, direction, ok := parseSQLFileName(entry.Name())
+			if !ok {
+				return nil, fmt.Errorf("invalid migration file name %s/%s (expected <id>.up.sql or <id>.down.sql)", backend, entry.Name())
 			}
-			// 記錄已存在的遷移，繼續執行
-			if logger.Logger != nil {
-				logger.Logger.Info("Migration record already exists",
-					zap.String("id", migration.ID),
-				)
+			content, err := fs.ReadFile(files, path.Join(string(backend), entry.Name()))
+			if err != nil {
+				return nil, err
 			}
-		}
 
-		if logger.Logger != nil {
-			logger.Logger.Info("Migration completed",
-				zap.String("id", migration.ID),
-			)
+			m, ok := byID[id]
+			if !ok {
+				m = &Migration{ID: id, SQL: map[database.DatabaseType]SQL{}}
+				byID[id] = m
+				result = append(result, m)
+			}
+			step := m.SQL[backend]
+			if direction == "up" {
+				step.Up = string(content)
+				if m.Description == "" {
+					m.Description = sqlDescription(step.Up)
+				}
+			} else {
+				step.Down = string(content)
+			}
+			m.SQL[backend] = step
 		}
 	}
 
-	return nil
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	list := make([]Migration, len(result))
+	for i, m := range result {
+		for backend, step := range m.SQL {
+			if step.Up == "" || step.Down == "" {
+				return nil, fmt.Errorf("migration %s for %s must have both up and down SQL", m.ID, backend)
+			}
+		}
+		list[i] = *m
+	}
+	return list, nil
 }
 
-// isDuplicateMigrationError 檢查是否為重複遷移記錄錯誤
-func isDuplicateMigrationError(err error) bool {
-	if err == nil {
-		return false
-	}
-	errStr := err.Error()
-	// MongoDB E11000 錯誤
-	if strings.Contains(errStr, "E11000") || strings.Contains(errStr, "duplicate key") {
-		return true
+// sqlDescription 以 SQL 第一行註解作為遷移描述
+func sqlDescription(content string) string {
+	line, _, _ := strings.Cut(content, "\n")
+	if description, ok := strings.CutPrefix(strings.TrimSpace(line), "--"); ok {
+		return strings.TrimSpace(description)
 	}
-	// SQLite UNIQUE constraint 錯誤
-	if strings.Contains(errStr, "UNIQUE constraint") {
-		return true
+	return ""
+}
+
+// parseSQLFileName 解析遷移 SQL 檔名（<id>.up.sql 或 <id>.down.sql，id 以序號開頭）
+func parseSQLFileName(name string) (id, direction string, ok bool) {
+	for _, direction := range []string{"up", "down"} {
+		if id, found := strings.CutSuffix(name, "."+direction+".sql"); found {
+			return id, direction, validID(id)
+		}
 	}
-	return false
+	return "", "", false
 }
 
-// getExecutedMigrations 取得已執行的遷移 ID 列表
-func getExecutedMigrations(ctx context.Context, coll database.Collection) ([]string, error) {
-	var results []struct {
-		ID string
+// validID 檢查遷移 ID（序號_名稱，例如 007_add_channel_cover）
+func validID(id string) bool {
+	number, name, found := strings.Cut(id, "_")
+	if !found || number == "" || name == "" {
+		return false
 	}
-	
-	err := coll.Find(ctx, database.Filter{}, nil, 0, 0, &results)
-	if err != nil {
-		return nil, err
+	for _, r := range number {
+		if r < '0' || r > '9' {
+			return false
+		}
 	}
-
-	ids := make([]string, len(results))
-	for i, r := range results {
-		ids[i] = r.ID
+	for _, r := range name {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '_' {
+			return false
+		}
 	}
+	return true
+}
 
-	return ids, nil
+// RunMigrations 執行所有未執行的遷移
+func RunMigrations(ctx context.Context, db database.Database) error {
+	_, err := NewMigrator(db).Up(ctx, 0)
+	return err
 }
 
 // RunMigrationsWithTimeout 執行遷移（帶超時）
@@ -145,4 +355,3 @@ func RunMigrationsWithTimeout(db database.Database) error {
 	defer cancel()
 	return RunMigrations(ctx, db)
 }
-
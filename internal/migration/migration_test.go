@@ -0,0 +1,469 @@
+package migration
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/models"
+	"github.com/higgstv/higgstv-go/internal/repository"
+)
+
+// openTestDB 建立尚未執行遷移的資料庫，測試結束時關閉
+func openTestDB(t *testing.T, dbType database.DatabaseType) database.Database {
+	cfg := database.DatabaseConfig{Type: dbType}
+	if dbType == database.DatabaseTypeSQLite {
+		cfg.URI = "file::memory:?cache=private"
+	}
+	db, err := database.NewDatabase(context.Background(), cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close(context.Background()) })
+	return db
+}
+
+// runBackends 對記憶體資料庫與 SQLite 執行 fn
+func runBackends(t *testing.T, fn func(t *testing.T, db database.Database)) {
+	for _, dbType := range []database.DatabaseType{database.DatabaseTypeMemory, database.DatabaseTypeSQLite} {
+		t.Run(string(dbType), func(t *testing.T) {
+			fn(t, openTestDB(t, dbType))
+		})
+	}
+}
+
+// sqliteTableExists 檢查 SQLite 資料表是否存在
+func sqliteTableExists(t *testing.T, db database.Database, table string) bool {
+	var count int
+	require.NoError(t, db.(*database.SQLiteDatabase).GetDB().QueryRow(
+		`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`, table).Scan(&count))
+	return count > 0
+}
+
+// states 取得每個遷移的狀態
+func states(t *testing.T, migrator *Migrator) map[string]string {
+	statuses, err := migrator.Status(context.Background())
+	require.NoError(t, err)
+	result := map[string]string{}
+	for _, status := range statuses {
+		result[status.ID] = status.State
+	}
+	return result
+}
+
+func TestMigrations(t *testing.T) {
+	defs, err := Migrations()
+	require.NoError(t, err)
+	require.NotEmpty(t, defs)
+	assert.Equal(t, "001_initial_schema", defs[0].ID)
+
+	for i, def := range defs {
+		assert.True(t, validID(def.ID), def.ID)
+		assert.NotEmpty(t, def.Description, def.ID)
+		if i > 0 {
+			assert.Less(t, defs[i-1].ID, def.ID)
+		}
+	}
+	for _, backend := range sqlBackends {
+		step, ok := defs[0].SQL[backend]
+		require.True(t, ok, backend)
+		assert.Contains(t, step.Up, "CREATE TABLE IF NOT EXISTS channels")
+		assert.Contains(t, step.Down, "DROP TABLE IF EXISTS channels")
+	}
+}
+
+func TestMigrator_UpDown(t *testing.T) {
+	ctx := context.Background()
+
+	runBackends(t, func(t *testing.T, db database.Database) {
+		migrator := NewMigrator(db)
+		defs, err := Migrations()
+		require.NoError(t, err)
+
+		pending, err := migrator.Pending(ctx)
+		require.NoError(t, err)
+		assert.Len(t, pending, len(defs))
+
+		done, err := migrator.Up(ctx, 0)
+		require.NoError(t, err)
+		assert.Len(t, done, len(defs))
+		for id, state := range states(t, migrator) {
+			assert.Equal(t, StateApplied, state, id)
+		}
+
+		invitation, err := repository.NewInvitationRepository(db).FindByCode(ctx, legacyInvitationCode)
+		require.NoError(t, err)
+		assert.NotNil(t, invitation)
+		if db.Type() == database.DatabaseTypeSQLite {
+			assert.True(t, sqliteTableExists(t, db, "channels"))
+		}
+
+		// 再次執行不會重複執行
+		done, err = migrator.Up(ctx, 0)
+		require.NoError(t, err)
+		assert.Empty(t, done)
+
+		// 還原到只剩 001_initial_schema
+		done, err = migrator.Down(ctx, len(defs)-1)
+		require.NoError(t, err)
+		require.Len(t, done, len(defs)-1)
+		assert.Equal(t, defs[len(defs)-1].ID, done[0].ID)
+		if db.Type() == database.DatabaseTypeSQLite {
+			// invitations 表由 005_post_baseline_tables 建立，已一併刪除
+			assert.False(t, sqliteTableExists(t, db, "invitations"))
+		} else {
+			invitation, err = repository.NewInvitationRepository(db).FindByCode(ctx, legacyInvitationCode)
+			require.NoError(t, err)
+			assert.Nil(t, invitation)
+		}
+
+		done, err = migrator.Down(ctx, 5)
+		require.NoError(t, err)
+		require.Len(t, done, 1)
+		assert.Equal(t, "001_initial_schema", done[0].ID)
+		if db.Type() == database.DatabaseTypeSQLite {
+			assert.False(t, sqliteTableExists(t, db, "channels"))
+			assert.True(t, sqliteTableExists(t, db, "migrations"))
+		}
+		for id, state := range states(t, migrator) {
+			assert.Equal(t, StatePending, state, id)
+		}
+
+		// 只執行前兩個遷移
+		done, err = migrator.Up(ctx, 2)
+		require.NoError(t, err)
+		require.Len(t, done, 2)
+		pending, err = migrator.Pending(ctx)
+		require.NoError(t, err)
+		assert.Len(t, pending, len(defs)-2)
+
+		_, err = migrator.Down(ctx, 0)
+		assert.Error(t, err)
+	})
+}
+
+func TestMigrator_Checksum(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t, database.DatabaseTypeSQLite)
+
+	defs := []Migration{{
+		ID:          "001_widgets",
+		Description: "widgets",
+		SQL: map[database.DatabaseType]SQL{database.DatabaseTypeSQLite: {
+			Up:   "CREATE TABLE widgets (id INTEGER PRIMARY KEY)",
+			Down: "DROP TABLE widgets",
+		}},
+	}}
+	_, err := NewMigrator(db).WithMigrations(defs).Up(ctx, 0)
+	require.NoError(t, err)
+
+	t.Run("內容修改後拒絕執行", func(t *testing.T) {
+		changed := []Migration{defs[0], {ID: "002_gadgets", Description: "gadgets"}}
+		changed[0].SQL = map[database.DatabaseType]SQL{database.DatabaseTypeSQLite: {
+			Up:   "CREATE TABLE widgets (id INTEGER PRIMARY KEY, name TEXT)",
+			Down: "DROP TABLE widgets",
+		}}
+		migrator := NewMigrator(db).WithMigrations(changed)
+		assert.Equal(t, StateChanged, states(t, migrator)["001_widgets"])
+
+		_, err := migrator.Up(ctx, 0)
+		assert.ErrorIs(t, err, ErrChecksumMismatch)
+		assert.Equal(t, StatePending, states(t, migrator)["002_gadgets"])
+	})
+
+	t.Run("舊版記錄補上 checksum", func(t *testing.T) {
+		_, err := db.(*database.SQLiteDatabase).GetDB().Exec(`UPDATE migrations SET checksum = '' WHERE id = '001_widgets'`)
+		require.NoError(t, err)
+		migrator := NewMigrator(db).WithMigrations(defs)
+		assert.Equal(t, StateNoSum, states(t, migrator)["001_widgets"])
+
+		_, err = migrator.Up(ctx, 0)
+		require.NoError(t, err)
+		assert.Equal(t, StateApplied, states(t, migrator)["001_widgets"])
+	})
+
+	t.Run("程式中沒有定義的遷移", func(t *testing.T) {
+		migrator := NewMigrator(db).WithMigrations([]Migration{})
+		assert.Equal(t, StateUnknown, states(t, migrator)["001_widgets"])
+
+		_, err := migrator.Up(ctx, 0)
+		assert.ErrorIs(t, err, ErrUnknownMigration)
+	})
+}
+
+func TestMigrator_Lock(t *testing.T) {
+	ctx := context.Background()
+
+	runBackends(t, func(t *testing.T, db database.Database) {
+		other := NewMigrator(db)
+		other.owner = "other-host:1"
+		require.NoError(t, other.store.init(ctx))
+		require.NoError(t, other.store.lock(ctx, other.owner, time.Now()))
+
+		migrator := NewMigrator(db)
+		lock, err := migrator.Lock(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, lock)
+		assert.Equal(t, "other-host:1", lock.Owner)
+
+		_, err = migrator.Up(ctx, 0)
+		assert.ErrorIs(t, err, ErrLocked)
+		_, err = migrator.Down(ctx, 1)
+		assert.ErrorIs(t, err, ErrLocked)
+
+		require.NoError(t, migrator.Unlock(ctx))
+		_, err = migrator.Up(ctx, 0)
+		require.NoError(t, err)
+
+		// 執行完成後釋放遷移鎖
+		lock, err = migrator.Lock(ctx)
+		require.NoError(t, err)
+		assert.Nil(t, lock)
+	})
+}
+
+func TestMigrator_FailureRollsBack(t *testing.T) {
+	ctx := context.Background()
+
+	runBackends(t, func(t *testing.T, db database.Database) {
+		defs := []Migration{{
+			ID:          "001_broken",
+			Description: "broken",
+			SQL: map[database.DatabaseType]SQL{database.DatabaseTypeSQLite: {
+				Up:   "CREATE TABLE widgets (id INTEGER PRIMARY KEY); INSERT INTO missing_table VALUES (1);",
+				Down: "DROP TABLE widgets",
+			}},
+			Up: func(ctx context.Context, db database.Database) error {
+				if err := repository.NewInvitationRepository(db).Create(ctx, &models.Invitation{ID: "i1", Code: "broken"}); err != nil {
+					return err
+				}
+				return assert.AnError
+			},
+		}}
+		migrator := NewMigrator(db).WithMigrations(defs)
+
+		_, err := migrator.Up(ctx, 0)
+		require.Error(t, err)
+		assert.Equal(t, StatePending, states(t, migrator)["001_broken"])
+		if db.Type() == database.DatabaseTypeSQLite {
+			assert.False(t, sqliteTableExists(t, db, "widgets"))
+		} else {
+			invitation, err := repository.NewInvitationRepository(db).FindByCode(ctx, "broken")
+			require.NoError(t, err)
+			assert.Nil(t, invitation)
+		}
+
+		lock, err := migrator.Lock(ctx)
+		require.NoError(t, err)
+		assert.Nil(t, lock)
+	})
+}
+
+func TestMigrator_LegacySQLiteDatabase(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t, database.DatabaseTypeSQLite)
+	sqlDB := db.(*database.SQLiteDatabase).GetDB()
+
+	// 舊版（遷移框架之前）ensureSchema 建立的資料庫結構：缺少後續新增的表與欄位，migrations 沒有 checksum 欄位
+	// 001_initial_schema 已記錄為已執行，因此之後新增的結構必須由其他遷移建立
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS users (
+			id TEXT PRIMARY KEY,
+			username TEXT UNIQUE NOT NULL,
+			email TEXT UNIQUE NOT NULL,
+			password TEXT NOT NULL,
+			access_key TEXT,
+			unclassified_channel TEXT,
+			created DATETIME NOT NULL,
+			last_modified DATETIME NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS user_channels (
+			user_id TEXT NOT NULL,
+			channel_id TEXT NOT NULL,
+			PRIMARY KEY (user_id, channel_id),
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		)`,
+		`CREATE TABLE IF NOT EXISTS channels (
+			id TEXT PRIMARY KEY,
+			type TEXT NOT NULL,
+			name TEXT NOT NULL,
+			desc TEXT,
+			contents_seq TEXT,
+			cover_default TEXT,
+			created DATETIME NOT NULL,
+			last_modified DATETIME NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS channel_tags (
+			channel_id TEXT NOT NULL,
+			tag INTEGER NOT NULL,
+			PRIMARY KEY (channel_id, tag),
+			FOREIGN KEY (channel_id) REFERENCES channels(id) ON DELETE CASCADE
+		)`,
+		`CREATE TABLE IF NOT EXISTS channel_owners (
+			channel_id TEXT NOT NULL,
+			user_id TEXT NOT NULL,
+			PRIMARY KEY (channel_id, user_id),
+			FOREIGN KEY (channel_id) REFERENCES channels(id) ON DELETE CASCADE,
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		)`,
+		`CREATE TABLE IF NOT EXISTS channel_permissions (
+			channel_id TEXT NOT NULL,
+			user_id TEXT NOT NULL,
+			admin INTEGER NOT NULL DEFAULT 0,
+			read INTEGER NOT NULL DEFAULT 0,
+			write INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (channel_id, user_id),
+			FOREIGN KEY (channel_id) REFERENCES channels(id) ON DELETE CASCADE,
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		)`,
+		`CREATE TABLE IF NOT EXISTS programs (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			channel_id TEXT NOT NULL,
+			name TEXT NOT NULL,
+			desc TEXT,
+			duration INTEGER,
+			type TEXT NOT NULL,
+			youtube_id TEXT,
+			created DATETIME NOT NULL,
+			last_modified DATETIME NOT NULL,
+			FOREIGN KEY (channel_id) REFERENCES channels(id) ON DELETE CASCADE
+		)`,
+		`CREATE TABLE IF NOT EXISTS program_tags (
+			program_id INTEGER NOT NULL,
+			tag INTEGER NOT NULL,
+			PRIMARY KEY (program_id, tag),
+			FOREIGN KEY (program_id) REFERENCES programs(id) ON DELETE CASCADE
+		)`,
+		`CREATE TABLE IF NOT EXISTS channel_program_order (
+			channel_id TEXT NOT NULL,
+			program_id INTEGER NOT NULL,
+			order_index INTEGER NOT NULL,
+			PRIMARY KEY (channel_id, program_id),
+			FOREIGN KEY (channel_id) REFERENCES channels(id) ON DELETE CASCADE,
+			FOREIGN KEY (program_id) REFERENCES programs(id) ON DELETE CASCADE
+		)`,
+		`CREATE TABLE IF NOT EXISTS counters (
+			id TEXT PRIMARY KEY,
+			seq INTEGER NOT NULL DEFAULT 0
+		)`,
+		`CREATE TABLE IF NOT EXISTS migrations (
+			id TEXT PRIMARY KEY,
+			description TEXT,
+			executed_at DATETIME NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_users_username ON users(username)`,
+		`CREATE INDEX IF NOT EXISTS idx_users_email ON users(email)`,
+		`CREATE INDEX IF NOT EXISTS idx_users_access_key ON users(access_key) WHERE access_key IS NOT NULL`,
+		`CREATE INDEX IF NOT EXISTS idx_channels_owners ON channel_owners(channel_id, user_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_channels_last_modified ON channels(last_modified DESC)`,
+		`CREATE INDEX IF NOT EXISTS idx_channels_name ON channels(name)`,
+		`CREATE INDEX IF NOT EXISTS idx_programs_channel_id ON programs(channel_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_program_tags_program_id ON program_tags(program_id)`,
+		`INSERT INTO users (id, username, email, password, created, last_modified) VALUES ('u-1', 'old', 'old@example.com', 'x', '2024-01-01 00:00:00', '2024-01-01 00:00:00')`,
+		`INSERT INTO channels (id, type, name, desc, created, last_modified) VALUES ('ch-1', 'default', 'Old', '', '2024-01-01 00:00:00', '2024-01-01 00:00:00')`,
+		`INSERT INTO migrations (id, description, executed_at) VALUES ('001_initial_schema', '', '2024-01-01 00:00:00')`,
+	}
+	for _, statement := range statements {
+		_, err := sqlDB.Exec(statement)
+		require.NoError(t, err)
+	}
+
+	migrator := NewMigrator(db)
+	assert.Equal(t, StateNoSum, states(t, migrator)["001_initial_schema"])
+	_, err := migrator.Up(ctx, 0)
+	require.NoError(t, err)
+
+	channel, err := repository.NewChannelRepository(db).FindByID(ctx, "ch-1")
+	require.NoError(t, err)
+	require.NotNil(t, channel)
+	assert.Equal(t, "Old", channel.Name)
+	assert.Equal(t, 0, channel.ForkCount)
+	assert.EqualValues(t, "public", channel.Visibility)
+	for _, table := range []string{"invitations", "sessions", "api_tokens", "channel_shares", "tags", "channel_revisions"} {
+		assert.True(t, sqliteTableExists(t, db, table), table)
+	}
+	invitation, err := repository.NewInvitationRepository(db).FindByCode(ctx, legacyInvitationCode)
+	require.NoError(t, err)
+	assert.NotNil(t, invitation)
+	user, err := repository.NewUserRepository(db).FindByID(ctx, "u-1")
+	require.NoError(t, err)
+	require.NotNil(t, user)
+	assert.Empty(t, user.InvitationID)
+	for id, state := range states(t, migrator) {
+		assert.Equal(t, StateApplied, state, id)
+	}
+}
+
+func TestMigrateChannelVisibility(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t, database.DatabaseTypeSQLite)
+	require.NoError(t, RunMigrations(ctx, db))
+	sqlDB := db.(*database.SQLiteDatabase).GetDB()
+	for _, statement := range []string{
+		`INSERT INTO channels (id, type, name, desc, visibility, created, last_modified) VALUES ('ch-1', 'default', 'Old', '', '', '2024-01-01 00:00:00', '2024-01-01 00:00:00')`,
+		`INSERT INTO channels (id, type, name, desc, visibility, created, last_modified) VALUES ('ch-2', 'default', 'Private', '', 'private', '2024-01-01 00:00:00', '2024-01-01 00:00:00')`,
+	} {
+		_, err := sqlDB.Exec(statement)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, migrateChannelVisibility(ctx, db))
+
+	channelRepo := repository.NewChannelRepository(db)
+	for id, visibility := range map[string]models.ChannelVisibility{
+		"ch-1": models.ChannelVisibilityPublic,
+		"ch-2": models.ChannelVisibilityPrivate,
+	} {
+		channel, err := channelRepo.FindByID(ctx, id)
+		require.NoError(t, err)
+		require.NotNil(t, channel)
+		assert.Equal(t, visibility, channel.Visibility, id)
+	}
+}
+
+func TestCreate(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "sql")
+
+	files, err := Create(dir, "Add Channel-Cover")
+	require.NoError(t, err)
+	require.Len(t, files, 4)
+
+	defs, err := Migrations()
+	require.NoError(t, err)
+	id := filepath.Base(files[0][:len(files[0])-len(".up.sql")])
+	assert.Regexp(t, `^\d{3}_add_channel_cover$`, id)
+	assert.Greater(t, id, defs[len(defs)-1].ID)
+	for _, backend := range sqlBackends {
+		for _, direction := range []string{"up", "down"} {
+			assert.FileExists(t, filepath.Join(dir, string(backend), id+"."+direction+".sql"))
+		}
+	}
+
+	// 下一個遷移使用下一個序號
+	more, err := Create(dir, "second")
+	require.NoError(t, err)
+	assert.Greater(t, filepath.Base(more[0]), filepath.Base(files[0]))
+
+	_, err = Create(dir, "bad/name")
+	assert.Error(t, err)
+
+	// 新檔案可以直接載入並執行（只有註解的 SQL）
+	created, err := loadMigrations(os.DirFS(dir), nil)
+	require.NoError(t, err)
+	require.Len(t, created, 2)
+	assert.Equal(t, "add channel cover", created[0].Description)
+
+	db := openTestDB(t, database.DatabaseTypeSQLite)
+	migrator := NewMigrator(db).WithMigrations(append(defs, created...))
+	_, err = migrator.Up(ctx, 0)
+	require.NoError(t, err)
+	_, err = migrator.Down(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, StatePending, states(t, migrator)[id])
+	assert.True(t, sqliteTableExists(t, db, "channels"))
+}
@@ -0,0 +1,339 @@
+package migration
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/pkg/logger"
+)
+
+var (
+	// ErrLocked 其他程序正在執行遷移
+	ErrLocked = errors.New("migrations are locked")
+	// ErrChecksumMismatch 已執行的遷移內容與目前的定義不同
+	ErrChecksumMismatch = errors.New("migration checksum mismatch")
+	// ErrUnknownMigration 資料庫記錄了程式中沒有定義的遷移（程式版本比資料庫舊）
+	ErrUnknownMigration = errors.New("unknown migration")
+)
+
+// 遷移狀態
+const (
+	StatePending = "pending" // 尚未執行
+	StateApplied = "applied" // 已執行
+	StateNoSum   = "no-sum"  // 已執行，但記錄中沒有 checksum（舊版記錄，下次 up 時補上）
+	StateChanged = "changed" // 已執行，但內容已被修改
+	StateUnknown = "unknown" // 已執行，但程式中沒有定義
+)
+
+// Status 遷移狀態
+type Status struct {
+	ID          string
+	Description string
+	State       string
+	ExecutedAt  *time.Time
+}
+
+// Migrator 執行資料庫遷移
+// 已執行的遷移記錄在 migrations 中（含 checksum），執行期間以 migration_lock 避免多個程序同時遷移
+type Migrator struct {
+	db         database.Database
+	store      store
+	migrations []Migration
+	owner      string
+}
+
+// NewMigrator 建立 Migrator（使用所有已定義的遷移）
+func NewMigrator(db database.Database) *Migrator {
+	host, _ := os.Hostname()
+	return &Migrator{
+		db:    db,
+		store: newStore(db),
+		owner: fmt.Sprintf("%s:%d", host, os.Getpid()),
+	}
+}
+
+// WithMigrations 改用指定的遷移（測試使用）
+func (m *Migrator) WithMigrations(migrations []Migration) *Migrator {
+	m.migrations = migrations
+	return m
+}
+
+// definitions 取得遷移定義
+func (m *Migrator) definitions() ([]Migration, error) {
+	if m.migrations != nil {
+		return m.migrations, nil
+	}
+	return Migrations()
+}
+
+// Checksum 遷移在指定資料庫類型的 checksum（SQL 內容的 SHA-256；Go 步驟無法計算，只比對 SQL）
+func (mig Migration) Checksum(dbType database.DatabaseType) string {
+	step := mig.SQL[dbType]
+	sum := sha256.Sum256([]byte(step.Up + "\x00" + step.Down))
+	return hex.EncodeToString(sum[:])
+}
+
+// Status 列出所有遷移與程式中沒有定義的已執行遷移
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	defs, err := m.definitions()
+	if err != nil {
+		return nil, err
+	}
+	if err := m.store.init(ctx); err != nil {
+		return nil, err
+	}
+	records, err := m.store.applied(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get executed migrations: %w", err)
+	}
+
+	applied := make(map[string]record, len(records))
+	for _, rec := range records {
+		applied[rec.ID] = rec
+	}
+
+	statuses := make([]Status, 0, len(defs))
+	for _, def := range defs {
+		status := Status{ID: def.ID, Description: def.Description, State: StatePending}
+		if rec, ok := applied[def.ID]; ok {
+			executedAt := rec.ExecutedAt
+			status.ExecutedAt = &executedAt
+			switch rec.Checksum {
+			case "":
+				status.State = StateNoSum
+			case def.Checksum(m.db.Type()):
+				status.State = StateApplied
+			default:
+				status.State = StateChanged
+			}
+			delete(applied, def.ID)
+		}
+		statuses = append(statuses, status)
+	}
+	for _, rec := range applied {
+		executedAt := rec.ExecutedAt
+		statuses = append(statuses, Status{ID: rec.ID, Description: rec.Description, State: StateUnknown, ExecutedAt: &executedAt})
+	}
+
+	sort.SliceStable(statuses, func(i, j int) bool { return statuses[i].ID < statuses[j].ID })
+	return statuses, nil
+}
+
+// Pending 列出尚未執行的遷移
+func (m *Migrator) Pending(ctx context.Context) ([]Status, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pending := []Status{}
+	for _, status := range statuses {
+		if status.State == StatePending {
+			pending = append(pending, status)
+		}
+	}
+	return pending, nil
+}
+
+// Lock 取得目前的遷移鎖（沒有執行中的遷移時回傳 nil）
+func (m *Migrator) Lock(ctx context.Context) (*Lock, error) {
+	if err := m.store.init(ctx); err != nil {
+		return nil, err
+	}
+	return m.store.currentLock(ctx)
+}
+
+// Unlock 強制釋放遷移鎖（遷移程序異常中止、遷移鎖未釋放時使用）
+func (m *Migrator) Unlock(ctx context.Context) error {
+	if err := m.store.init(ctx); err != nil {
+		return err
+	}
+	return m.store.unlock(ctx, "")
+}
+
+// Up 依序執行未執行的遷移（n 小於等於 0 時全部執行），回傳已執行的遷移
+// 已執行遷移的 checksum 與目前定義不同時不執行任何遷移
+func (m *Migrator) Up(ctx context.Context, n int) ([]Migration, error) {
+	var done []Migration
+	err := m.locked(ctx, func(defs []Migration, applied map[string]record) error {
+		for _, def := range defs {
+			if n > 0 && len(done) >= n {
+				break
+			}
+			if _, ok := applied[def.ID]; ok {
+				continue
+			}
+
+			m.log("Running migration", def)
+			if err := m.apply(ctx, def, true); err != nil {
+				return fmt.Errorf("migration %s failed: %w", def.ID, err)
+			}
+			m.log("Migration completed", def)
+			done = append(done, def)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down 依 ID 由大到小還原最後 n 個已執行的遷移，回傳已還原的遷移
+func (m *Migrator) Down(ctx context.Context, n int) ([]Migration, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("number of migrations to revert must be positive")
+	}
+
+	var done []Migration
+	err := m.locked(ctx, func(defs []Migration, applied map[string]record) error {
+		for i := len(defs) - 1; i >= 0 && len(done) < n; i-- {
+			def := defs[i]
+			if _, ok := applied[def.ID]; !ok {
+				continue
+			}
+
+			m.log("Reverting migration", def)
+			if err := m.apply(ctx, def, false); err != nil {
+				return fmt.Errorf("revert migration %s failed: %w", def.ID, err)
+			}
+			m.log("Migration reverted", def)
+			done = append(done, def)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// locked 取得遷移鎖並檢查已執行的遷移後執行 fn
+// 已執行的遷移必須都有定義且 checksum 相符；沒有 checksum 的舊版記錄補上目前的 checksum
+func (m *Migrator) locked(ctx context.Context, fn func(defs []Migration, applied map[string]record) error) error {
+	defs, err := m.definitions()
+	if err != nil {
+		return err
+	}
+	if err := m.store.init(ctx); err != nil {
+		return err
+	}
+	if err := m.store.lock(ctx, m.owner, time.Now()); err != nil {
+		return err
+	}
+	defer func() {
+		// 遷移失敗時 ctx 可能已逾時，仍須釋放遷移鎖
+		_ = m.store.unlock(context.WithoutCancel(ctx), m.owner)
+	}()
+
+	records, err := m.store.applied(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get executed migrations: %w", err)
+	}
+
+	byID := make(map[string]Migration, len(defs))
+	for _, def := range defs {
+		byID[def.ID] = def
+	}
+	applied := make(map[string]record, len(records))
+	for _, rec := range records {
+		def, ok := byID[rec.ID]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownMigration, rec.ID)
+		}
+		checksum := def.Checksum(m.db.Type())
+		switch rec.Checksum {
+		case checksum:
+		case "":
+			if err := m.store.setChecksum(ctx, rec.ID, checksum); err != nil {
+				return fmt.Errorf("failed to record checksum of %s: %w", rec.ID, err)
+			}
+		default:
+			return fmt.Errorf("%w: %s", ErrChecksumMismatch, rec.ID)
+		}
+		applied[rec.ID] = rec
+	}
+
+	return fn(defs, applied)
+}
+
+// apply 執行（up 為 true）或還原單一遷移，並更新遷移記錄
+// 除了 NoTx 的遷移之外，遷移步驟與記錄在同一個交易中完成
+func (m *Migrator) apply(ctx context.Context, def Migration, up bool) error {
+	dbType := m.db.Type()
+	step := m.stepFunc(def, up)
+	run := func(ctx context.Context) error {
+		if err := step(ctx); err != nil {
+			return err
+		}
+		if !up {
+			return m.store.delete(ctx, def.ID)
+		}
+		return m.store.insert(ctx, record{
+			ID:          def.ID,
+			Description: def.Description,
+			Checksum:    def.Checksum(dbType),
+			ExecutedAt:  time.Now(),
+		})
+	}
+
+	if def.NoTx {
+		return run(ctx)
+	}
+	return m.db.WithTx(ctx, run)
+}
+
+// stepFunc 遷移步驟：向上遷移先執行 SQL 再執行 Up，向下遷移先執行 Down 再執行 SQL
+func (m *Migrator) stepFunc(def Migration, up bool) func(ctx context.Context) error {
+	sqlStep, hasSQL := def.SQL[m.db.Type()]
+	return func(ctx context.Context) error {
+		if up {
+			if hasSQL {
+				if err := m.execSQL(ctx, sqlStep.Up); err != nil {
+					return err
+				}
+			}
+			if def.Up != nil {
+				return def.Up(ctx, m.db)
+			}
+			return nil
+		}
+
+		if def.Down != nil {
+			if err := def.Down(ctx, m.db); err != nil {
+				return err
+			}
+		}
+		if hasSQL {
+			return m.execSQL(ctx, sqlStep.Down)
+		}
+		return nil
+	}
+}
+
+// execSQL 執行遷移 SQL（可包含多個以分號分隔的敘述）
+func (m *Migrator) execSQL(ctx context.Context, query string) error {
+	var conn *database.SQLConn
+	switch d := m.db.(type) {
+	case *database.SQLiteDatabase:
+		conn = d.GetConn()
+	case *database.PostgresDatabase:
+		conn = d.GetConn()
+	default:
+		return fmt.Errorf("SQL migrations are not supported for %s", m.db.Type())
+	}
+	_, err := conn.ExecContext(ctx, query)
+	return err
+}
+
+// log 記錄遷移進度
+func (m *Migrator) log(message string, def Migration) {
+	if logger.Logger != nil {
+		logger.Logger.Info(message,
+			zap.String("id", def.ID),
+			zap.String("description", def.Description),
+		)
+	}
+}
@@ -0,0 +1,191 @@
+-- 初始資料庫結構（既有資料庫可能已由舊版的 ensureSchema 建立，因此使用 IF NOT EXISTS）
+
+-- users 表
+CREATE TABLE IF NOT EXISTS users (
+	id TEXT PRIMARY KEY,
+	username TEXT UNIQUE NOT NULL,
+	email TEXT UNIQUE NOT NULL,
+	password TEXT NOT NULL,
+	access_key TEXT,
+	unclassified_channel TEXT,
+	invitation_id TEXT,
+	created TIMESTAMPTZ NOT NULL,
+	last_modified TIMESTAMPTZ NOT NULL
+);
+
+-- user_channels 表（使用者擁有的頻道）
+CREATE TABLE IF NOT EXISTS user_channels (
+	user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	channel_id TEXT NOT NULL,
+	PRIMARY KEY (user_id, channel_id)
+);
+
+-- channels 表
+CREATE TABLE IF NOT EXISTS channels (
+	id TEXT PRIMARY KEY,
+	type TEXT NOT NULL,
+	name TEXT NOT NULL,
+	"desc" TEXT,
+	contents_seq TEXT,
+	cover_default TEXT,
+	schedule_epoch TIMESTAMPTZ,
+	schedule_loop TEXT,
+	hide_unavailable BOOLEAN NOT NULL DEFAULT FALSE,
+	visibility TEXT NOT NULL DEFAULT 'public',
+	revision BIGINT NOT NULL DEFAULT 0,
+	forked_from TEXT,
+	fork_count INTEGER NOT NULL DEFAULT 0,
+	deleted_at TIMESTAMPTZ,
+	created TIMESTAMPTZ NOT NULL,
+	last_modified TIMESTAMPTZ NOT NULL
+);
+
+-- channel_tags 表
+CREATE TABLE IF NOT EXISTS channel_tags (
+	channel_id TEXT NOT NULL REFERENCES channels(id) ON DELETE CASCADE,
+	tag INTEGER NOT NULL,
+	PRIMARY KEY (channel_id, tag)
+);
+
+-- channel_owners 表
+CREATE TABLE IF NOT EXISTS channel_owners (
+	channel_id TEXT NOT NULL REFERENCES channels(id) ON DELETE CASCADE,
+	user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	PRIMARY KEY (channel_id, user_id)
+);
+
+-- channel_permissions 表
+CREATE TABLE IF NOT EXISTS channel_permissions (
+	channel_id TEXT NOT NULL REFERENCES channels(id) ON DELETE CASCADE,
+	user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	admin BOOLEAN NOT NULL DEFAULT FALSE,
+	read BOOLEAN NOT NULL DEFAULT FALSE,
+	write BOOLEAN NOT NULL DEFAULT FALSE,
+	PRIMARY KEY (channel_id, user_id)
+);
+
+-- programs 表（節目 ID 由 counters 表產生）
+CREATE TABLE IF NOT EXISTS programs (
+	id INTEGER PRIMARY KEY,
+	channel_id TEXT NOT NULL REFERENCES channels(id) ON DELETE CASCADE,
+	name TEXT NOT NULL,
+	"desc" TEXT,
+	duration INTEGER,
+	type TEXT NOT NULL,
+	youtube_id TEXT,
+	video_id TEXT,
+	metadata_at TIMESTAMPTZ,
+	availability TEXT,
+	last_checked TIMESTAMPTZ,
+	created TIMESTAMPTZ NOT NULL,
+	last_modified TIMESTAMPTZ NOT NULL
+);
+
+-- program_tags 表
+CREATE TABLE IF NOT EXISTS program_tags (
+	program_id INTEGER NOT NULL REFERENCES programs(id) ON DELETE CASCADE,
+	tag INTEGER NOT NULL,
+	PRIMARY KEY (program_id, tag)
+);
+
+-- channel_program_order 表（節目順序）
+CREATE TABLE IF NOT EXISTS channel_program_order (
+	channel_id TEXT NOT NULL REFERENCES channels(id) ON DELETE CASCADE,
+	program_id INTEGER NOT NULL REFERENCES programs(id) ON DELETE CASCADE,
+	order_index INTEGER NOT NULL,
+	PRIMARY KEY (channel_id, program_id)
+);
+
+-- counters 表
+CREATE TABLE IF NOT EXISTS counters (
+	id TEXT PRIMARY KEY,
+	seq INTEGER NOT NULL DEFAULT 0
+);
+
+-- invitations 表（註冊邀請碼）
+CREATE TABLE IF NOT EXISTS invitations (
+	id TEXT PRIMARY KEY,
+	code TEXT UNIQUE NOT NULL,
+	note TEXT NOT NULL DEFAULT '',
+	created_by TEXT NOT NULL DEFAULT '',
+	max_uses INTEGER NOT NULL DEFAULT 0,
+	uses INTEGER NOT NULL DEFAULT 0,
+	expires_at TIMESTAMPTZ,
+	revoked BOOLEAN NOT NULL DEFAULT FALSE,
+	created TIMESTAMPTZ NOT NULL,
+	last_modified TIMESTAMPTZ NOT NULL
+);
+
+-- sessions 表（伺服器端 Session）
+CREATE TABLE IF NOT EXISTS sessions (
+	id TEXT PRIMARY KEY,
+	user_id TEXT NOT NULL,
+	user_agent TEXT NOT NULL DEFAULT '',
+	ip TEXT NOT NULL DEFAULT '',
+	created TIMESTAMPTZ NOT NULL,
+	last_seen TIMESTAMPTZ NOT NULL,
+	expires_at TIMESTAMPTZ NOT NULL
+);
+
+-- api_tokens 表（個人 API Token）
+CREATE TABLE IF NOT EXISTS api_tokens (
+	id TEXT PRIMARY KEY,
+	user_id TEXT NOT NULL,
+	name TEXT NOT NULL DEFAULT '',
+	prefix TEXT NOT NULL DEFAULT '',
+	token_hash TEXT UNIQUE NOT NULL,
+	scopes TEXT NOT NULL DEFAULT '',
+	expires_at TIMESTAMPTZ,
+	last_used TIMESTAMPTZ,
+	created TIMESTAMPTZ NOT NULL
+);
+
+-- channel_shares 表（頻道分享邀請）
+CREATE TABLE IF NOT EXISTS channel_shares (
+	id TEXT PRIMARY KEY,
+	channel_id TEXT NOT NULL,
+	email TEXT NOT NULL,
+	role TEXT NOT NULL,
+	token_hash TEXT UNIQUE NOT NULL,
+	invited_by TEXT NOT NULL DEFAULT '',
+	status TEXT NOT NULL,
+	user_id TEXT NOT NULL DEFAULT '',
+	expires_at TIMESTAMPTZ NOT NULL,
+	created TIMESTAMPTZ NOT NULL,
+	last_modified TIMESTAMPTZ NOT NULL
+);
+
+-- tags 表（標籤定義）
+CREATE TABLE IF NOT EXISTS tags (
+	id INTEGER PRIMARY KEY,
+	slug TEXT UNIQUE NOT NULL,
+	labels JSONB NOT NULL DEFAULT '{}',
+	parent INTEGER,
+	created TIMESTAMPTZ NOT NULL,
+	last_modified TIMESTAMPTZ NOT NULL
+);
+
+-- channel_revisions 表（頻道歷史版本）
+CREATE TABLE IF NOT EXISTS channel_revisions (
+	id TEXT PRIMARY KEY,
+	channel_id TEXT NOT NULL,
+	revision BIGINT NOT NULL,
+	action TEXT NOT NULL,
+	snapshot JSONB NOT NULL,
+	created TIMESTAMPTZ NOT NULL
+);
+
+-- 索引
+CREATE INDEX IF NOT EXISTS idx_users_access_key ON users(access_key) WHERE access_key IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_user_channels_channel_id ON user_channels(channel_id);
+CREATE INDEX IF NOT EXISTS idx_channel_owners_user_id ON channel_owners(user_id);
+CREATE INDEX IF NOT EXISTS idx_channels_last_modified ON channels(last_modified DESC);
+CREATE INDEX IF NOT EXISTS idx_channels_name ON channels(name);
+CREATE INDEX IF NOT EXISTS idx_programs_channel_id ON programs(channel_id);
+CREATE INDEX IF NOT EXISTS idx_channel_program_order_program_id ON channel_program_order(program_id);
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions(user_id);
+CREATE INDEX IF NOT EXISTS idx_api_tokens_user_id ON api_tokens(user_id);
+CREATE INDEX IF NOT EXISTS idx_channels_deleted_at ON channels(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_channel_shares_channel_id ON channel_shares(channel_id);
+CREATE INDEX IF NOT EXISTS idx_channel_shares_email ON channel_shares(email);
+CREATE INDEX IF NOT EXISTS idx_channel_revisions_channel_id ON channel_revisions(channel_id, created);
@@ -0,0 +1,11 @@
+-- 刪除初始資料庫結構（依外鍵相依順序）
+DROP TABLE IF EXISTS counters;
+DROP TABLE IF EXISTS channel_program_order;
+DROP TABLE IF EXISTS program_tags;
+DROP TABLE IF EXISTS programs;
+DROP TABLE IF EXISTS channel_permissions;
+DROP TABLE IF EXISTS channel_owners;
+DROP TABLE IF EXISTS channel_tags;
+DROP TABLE IF EXISTS channels;
+DROP TABLE IF EXISTS user_channels;
+DROP TABLE IF EXISTS users;
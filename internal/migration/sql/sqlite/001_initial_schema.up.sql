@@ -0,0 +1,111 @@
+-- 初始資料庫結構（與舊版 ensureSchema 建立的結構相同，既有資料庫可能已建立，因此使用 IF NOT EXISTS）
+-- 之後新增的欄位由 003_sqlite_legacy_columns 補上，新增的表由 005_post_baseline_tables 建立
+
+-- users 表
+CREATE TABLE IF NOT EXISTS users (
+	id TEXT PRIMARY KEY,
+	username TEXT UNIQUE NOT NULL,
+	email TEXT UNIQUE NOT NULL,
+	password TEXT NOT NULL,
+	access_key TEXT,
+	unclassified_channel TEXT,
+	created DATETIME NOT NULL,
+	last_modified DATETIME NOT NULL
+);
+
+-- user_channels 表（使用者擁有的頻道）
+CREATE TABLE IF NOT EXISTS user_channels (
+	user_id TEXT NOT NULL,
+	channel_id TEXT NOT NULL,
+	PRIMARY KEY (user_id, channel_id),
+	FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- channels 表
+CREATE TABLE IF NOT EXISTS channels (
+	id TEXT PRIMARY KEY,
+	type TEXT NOT NULL,
+	name TEXT NOT NULL,
+	desc TEXT,
+	contents_seq TEXT,
+	cover_default TEXT,
+	created DATETIME NOT NULL,
+	last_modified DATETIME NOT NULL
+);
+
+-- channel_tags 表
+CREATE TABLE IF NOT EXISTS channel_tags (
+	channel_id TEXT NOT NULL,
+	tag INTEGER NOT NULL,
+	PRIMARY KEY (channel_id, tag),
+	FOREIGN KEY (channel_id) REFERENCES channels(id) ON DELETE CASCADE
+);
+
+-- channel_owners 表
+CREATE TABLE IF NOT EXISTS channel_owners (
+	channel_id TEXT NOT NULL,
+	user_id TEXT NOT NULL,
+	PRIMARY KEY (channel_id, user_id),
+	FOREIGN KEY (channel_id) REFERENCES channels(id) ON DELETE CASCADE,
+	FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- channel_permissions 表
+CREATE TABLE IF NOT EXISTS channel_permissions (
+	channel_id TEXT NOT NULL,
+	user_id TEXT NOT NULL,
+	admin INTEGER NOT NULL DEFAULT 0,
+	read INTEGER NOT NULL DEFAULT 0,
+	write INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY (channel_id, user_id),
+	FOREIGN KEY (channel_id) REFERENCES channels(id) ON DELETE CASCADE,
+	FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- programs 表
+CREATE TABLE IF NOT EXISTS programs (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	channel_id TEXT NOT NULL,
+	name TEXT NOT NULL,
+	desc TEXT,
+	duration INTEGER,
+	type TEXT NOT NULL,
+	youtube_id TEXT,
+	created DATETIME NOT NULL,
+	last_modified DATETIME NOT NULL,
+	FOREIGN KEY (channel_id) REFERENCES channels(id) ON DELETE CASCADE
+);
+
+-- program_tags 表
+CREATE TABLE IF NOT EXISTS program_tags (
+	program_id INTEGER NOT NULL,
+	tag INTEGER NOT NULL,
+	PRIMARY KEY (program_id, tag),
+	FOREIGN KEY (program_id) REFERENCES programs(id) ON DELETE CASCADE
+);
+
+-- channel_program_order 表（節目順序）
+CREATE TABLE IF NOT EXISTS channel_program_order (
+	channel_id TEXT NOT NULL,
+	program_id INTEGER NOT NULL,
+	order_index INTEGER NOT NULL,
+	PRIMARY KEY (channel_id, program_id),
+	FOREIGN KEY (channel_id) REFERENCES channels(id) ON DELETE CASCADE,
+	FOREIGN KEY (program_id) REFERENCES programs(id) ON DELETE CASCADE
+);
+
+-- counters 表
+CREATE TABLE IF NOT EXISTS counters (
+	id TEXT PRIMARY KEY,
+	seq INTEGER NOT NULL DEFAULT 0
+);
+
+-- 索引
+CREATE INDEX IF NOT EXISTS idx_users_username ON users(username);
+CREATE INDEX IF NOT EXISTS idx_users_email ON users(email);
+CREATE INDEX IF NOT EXISTS idx_users_access_key ON users(access_key) WHERE access_key IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_channels_owners ON channel_owners(channel_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_channels_last_modified ON channels(last_modified DESC);
+CREATE INDEX IF NOT EXISTS idx_channels_name ON channels(name);
+CREATE INDEX IF NOT EXISTS idx_programs_channel_id ON programs(channel_id);
+CREATE INDEX IF NOT EXISTS idx_program_tags_program_id ON program_tags(program_id);
@@ -0,0 +1,7 @@
+-- 刪除初始結構之後新增的表
+DROP TABLE IF EXISTS channel_revisions;
+DROP TABLE IF EXISTS tags;
+DROP TABLE IF EXISTS channel_shares;
+DROP TABLE IF EXISTS api_tokens;
+DROP TABLE IF EXISTS sessions;
+DROP TABLE IF EXISTS invitations;
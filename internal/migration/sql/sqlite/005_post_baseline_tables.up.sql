@@ -0,0 +1,81 @@
+-- 建立初始結構之後新增的表與索引（既有資料庫可能已由舊版的 ensureSchema 建立，因此使用 IF NOT EXISTS）
+
+-- invitations 表（註冊邀請碼）
+CREATE TABLE IF NOT EXISTS invitations (
+	id TEXT PRIMARY KEY,
+	code TEXT UNIQUE NOT NULL,
+	note TEXT NOT NULL DEFAULT '',
+	created_by TEXT NOT NULL DEFAULT '',
+	max_uses INTEGER NOT NULL DEFAULT 0,
+	uses INTEGER NOT NULL DEFAULT 0,
+	expires_at DATETIME,
+	revoked INTEGER NOT NULL DEFAULT 0,
+	created DATETIME NOT NULL,
+	last_modified DATETIME NOT NULL
+);
+
+-- sessions 表（伺服器端 Session）
+CREATE TABLE IF NOT EXISTS sessions (
+	id TEXT PRIMARY KEY,
+	user_id TEXT NOT NULL,
+	user_agent TEXT NOT NULL DEFAULT '',
+	ip TEXT NOT NULL DEFAULT '',
+	created DATETIME NOT NULL,
+	last_seen DATETIME NOT NULL,
+	expires_at DATETIME NOT NULL
+);
+
+-- api_tokens 表（個人 API Token）
+CREATE TABLE IF NOT EXISTS api_tokens (
+	id TEXT PRIMARY KEY,
+	user_id TEXT NOT NULL,
+	name TEXT NOT NULL DEFAULT '',
+	prefix TEXT NOT NULL DEFAULT '',
+	token_hash TEXT UNIQUE NOT NULL,
+	scopes TEXT NOT NULL DEFAULT '',
+	expires_at DATETIME,
+	last_used DATETIME,
+	created DATETIME NOT NULL
+);
+
+-- channel_shares 表（頻道分享邀請）
+CREATE TABLE IF NOT EXISTS channel_shares (
+	id TEXT PRIMARY KEY,
+	channel_id TEXT NOT NULL,
+	email TEXT NOT NULL,
+	role TEXT NOT NULL,
+	token_hash TEXT UNIQUE NOT NULL,
+	invited_by TEXT NOT NULL DEFAULT '',
+	status TEXT NOT NULL,
+	user_id TEXT NOT NULL DEFAULT '',
+	expires_at DATETIME NOT NULL,
+	created DATETIME NOT NULL,
+	last_modified DATETIME NOT NULL
+);
+
+-- tags 表（標籤定義，labels 為 JSON）
+CREATE TABLE IF NOT EXISTS tags (
+	id INTEGER PRIMARY KEY,
+	slug TEXT UNIQUE NOT NULL,
+	labels TEXT NOT NULL DEFAULT '{}',
+	parent INTEGER,
+	created DATETIME NOT NULL,
+	last_modified DATETIME NOT NULL
+);
+
+-- channel_revisions 表（頻道歷史版本，snapshot 為 JSON）
+CREATE TABLE IF NOT EXISTS channel_revisions (
+	id TEXT PRIMARY KEY,
+	channel_id TEXT NOT NULL,
+	revision INTEGER NOT NULL,
+	action TEXT NOT NULL,
+	snapshot TEXT NOT NULL,
+	created DATETIME NOT NULL
+);
+
+-- 索引
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions(user_id);
+CREATE INDEX IF NOT EXISTS idx_api_tokens_user_id ON api_tokens(user_id);
+CREATE INDEX IF NOT EXISTS idx_channel_shares_channel_id ON channel_shares(channel_id);
+CREATE INDEX IF NOT EXISTS idx_channel_shares_email ON channel_shares(email);
+CREATE INDEX IF NOT EXISTS idx_channel_revisions_channel_id ON channel_revisions(channel_id, created);
@@ -0,0 +1,268 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+)
+
+// record 已執行的遷移記錄
+type record struct {
+	ID          string    `bson:"_id"`
+	Description string    `bson:"description"`
+	Checksum    string    `bson:"checksum"`
+	ExecutedAt  time.Time `bson:"executed_at"`
+}
+
+// Lock 遷移鎖（執行中的遷移程序）
+type Lock struct {
+	Owner    string    `bson:"owner"`
+	LockedAt time.Time `bson:"locked_at"`
+}
+
+// store 遷移記錄與遷移鎖的儲存方式（依資料庫類型實作）
+type store interface {
+	// init 建立遷移記錄與遷移鎖的資料表（舊版的 migrations 表補上 checksum 欄位）
+	init(ctx context.Context) error
+	applied(ctx context.Context) ([]record, error)
+	insert(ctx context.Context, rec record) error
+	setChecksum(ctx context.Context, id, checksum string) error
+	delete(ctx context.Context, id string) error
+	// lock 取得遷移鎖，已被其他程序取得時回傳 ErrLocked
+	lock(ctx context.Context, owner string, now time.Time) error
+	// unlock 釋放遷移鎖（owner 為空字串時不論擁有者都釋放）
+	unlock(ctx context.Context, owner string) error
+	// currentLock 取得目前的遷移鎖（沒有時回傳 nil）
+	currentLock(ctx context.Context) (*Lock, error)
+}
+
+// newStore 依資料庫類型建立 store（SQLite、PostgreSQL 使用 SQL，MongoDB 與記憶體資料庫使用 Collection）
+func newStore(db database.Database) store {
+	switch d := db.(type) {
+	case *database.SQLiteDatabase:
+		return &sqlStore{conn: d.GetConn()}
+	case *database.PostgresDatabase:
+		return &sqlStore{conn: d.GetConn(), postgres: true}
+	default:
+		return &collectionStore{db: db}
+	}
+}
+
+// sqlStore SQLite 與 PostgreSQL 的遷移記錄（migrations 表）與遷移鎖（migration_lock 表，只有一列）
+type sqlStore struct {
+	conn     *database.SQLConn
+	postgres bool
+}
+
+// bind 將 ? 轉換為 PostgreSQL 的 $n 參數
+func (s *sqlStore) bind(query string) string {
+	if !s.postgres {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			fmt.Fprintf(&b, "$%d", n)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func (s *sqlStore) init(ctx context.Context) error {
+	timestamp := "DATETIME"
+	if s.postgres {
+		timestamp = "TIMESTAMPTZ"
+	}
+	tables := []string{
+		`CREATE TABLE IF NOT EXISTS migrations (
+			id TEXT PRIMARY KEY,
+			description TEXT,
+			checksum TEXT NOT NULL DEFAULT '',
+			executed_at ` + timestamp + ` NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS migration_lock (
+			id INTEGER PRIMARY KEY,
+			owner TEXT NOT NULL,
+			locked_at ` + timestamp + ` NOT NULL
+		)`,
+	}
+	for _, table := range tables {
+		if _, err := s.conn.ExecContext(ctx, table); err != nil {
+			return fmt.Errorf("failed to create migration table: %w", err)
+		}
+	}
+
+	// 舊版建立的 migrations 表沒有 checksum 欄位
+	if s.postgres {
+		_, err := s.conn.ExecContext(ctx, `ALTER TABLE migrations ADD COLUMN IF NOT EXISTS checksum TEXT NOT NULL DEFAULT ''`)
+		return err
+	}
+	exists, err := sqliteHasColumn(ctx, s.conn, "migrations", "checksum")
+	if err != nil || exists {
+		return err
+	}
+	_, err = s.conn.ExecContext(ctx, `ALTER TABLE migrations ADD COLUMN checksum TEXT NOT NULL DEFAULT ''`)
+	return err
+}
+
+func (s *sqlStore) applied(ctx context.Context) ([]record, error) {
+	rows, err := s.conn.QueryContext(ctx,
+		`SELECT id, COALESCE(description, ''), checksum, executed_at FROM migrations ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	records := []record{}
+	for rows.Next() {
+		var rec record
+		if err := rows.Scan(&rec.ID, &rec.Description, &rec.Checksum, &rec.ExecutedAt); err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+	}
+	return records, rows.Err()
+}
+
+func (s *sqlStore) insert(ctx context.Context, rec record) error {
+	_, err := s.conn.ExecContext(ctx,
+		s.bind(`INSERT INTO migrations (id, description, checksum, executed_at) VALUES (?, ?, ?, ?)`),
+		rec.ID, rec.Description, rec.Checksum, rec.ExecutedAt)
+	return err
+}
+
+func (s *sqlStore) setChecksum(ctx context.Context, id, checksum string) error {
+	_, err := s.conn.ExecContext(ctx, s.bind(`UPDATE migrations SET checksum = ? WHERE id = ?`), checksum, id)
+	return err
+}
+
+func (s *sqlStore) delete(ctx context.Context, id string) error {
+	_, err := s.conn.ExecContext(ctx, s.bind(`DELETE FROM migrations WHERE id = ?`), id)
+	return err
+}
+
+func (s *sqlStore) lock(ctx context.Context, owner string, now time.Time) error {
+	_, err := s.conn.ExecContext(ctx,
+		s.bind(`INSERT INTO migration_lock (id, owner, locked_at) VALUES (1, ?, ?)`), owner, now)
+	if err == nil {
+		return nil
+	}
+	// 主鍵重複表示已有其他程序取得遷移鎖
+	current, lockErr := s.currentLock(ctx)
+	if lockErr != nil || current == nil {
+		return err
+	}
+	return lockedError(current)
+}
+
+func (s *sqlStore) unlock(ctx context.Context, owner string) error {
+	if owner == "" {
+		_, err := s.conn.ExecContext(ctx, `DELETE FROM migration_lock`)
+		return err
+	}
+	_, err := s.conn.ExecContext(ctx, s.bind(`DELETE FROM migration_lock WHERE owner = ?`), owner)
+	return err
+}
+
+func (s *sqlStore) currentLock(ctx context.Context) (*Lock, error) {
+	var lock Lock
+	err := s.conn.QueryRowContext(ctx, `SELECT owner, locked_at FROM migration_lock WHERE id = 1`).Scan(&lock.Owner, &lock.LockedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &lock, nil
+}
+
+// collectionStore MongoDB 與記憶體資料庫的遷移記錄（migrations collection）與遷移鎖（migration_lock collection）
+type collectionStore struct {
+	db database.Database
+}
+
+// lockID 遷移鎖文件的 _id
+const lockID = "lock"
+
+func (s *collectionStore) init(ctx context.Context) error {
+	return nil
+}
+
+func (s *collectionStore) applied(ctx context.Context) ([]record, error) {
+	var records []record
+	err := s.db.Collection("migrations").Find(ctx, database.Filter{},
+		database.Sort{{Field: "_id", Order: 1}}, 0, 0, &records)
+	return records, err
+}
+
+func (s *collectionStore) insert(ctx context.Context, rec record) error {
+	return s.db.Collection("migrations").InsertOne(ctx, map[string]interface{}{
+		"_id":         rec.ID,
+		"description": rec.Description,
+		"checksum":    rec.Checksum,
+		"executed_at": rec.ExecutedAt,
+	})
+}
+
+func (s *collectionStore) setChecksum(ctx context.Context, id, checksum string) error {
+	return s.db.Collection("migrations").UpdateOne(ctx, database.Filter{"_id": id}, database.Update{
+		Set: map[string]interface{}{"checksum": checksum},
+	})
+}
+
+func (s *collectionStore) delete(ctx context.Context, id string) error {
+	return s.db.Collection("migrations").DeleteOne(ctx, database.Filter{"_id": id})
+}
+
+func (s *collectionStore) lock(ctx context.Context, owner string, now time.Time) error {
+	err := s.db.Collection("migration_lock").InsertOne(ctx, map[string]interface{}{
+		"_id":       lockID,
+		"owner":     owner,
+		"locked_at": now,
+	})
+	if err == nil {
+		return nil
+	}
+	// _id 重複表示已有其他程序取得遷移鎖
+	current, lockErr := s.currentLock(ctx)
+	if lockErr != nil || current == nil {
+		return err
+	}
+	return lockedError(current)
+}
+
+func (s *collectionStore) unlock(ctx context.Context, owner string) error {
+	filter := database.Filter{"_id": lockID}
+	if owner != "" {
+		filter["owner"] = owner
+	}
+	return s.db.Collection("migration_lock").DeleteOne(ctx, filter)
+}
+
+func (s *collectionStore) currentLock(ctx context.Context) (*Lock, error) {
+	var lock Lock
+	err := s.db.Collection("migration_lock").FindOne(ctx, database.Filter{"_id": lockID}, &lock)
+	if database.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &lock, nil
+}
+
+// lockedError 遷移鎖已被取得的錯誤
+func lockedError(lock *Lock) error {
+	return fmt.Errorf("%w by %s since %s", ErrLocked, lock.Owner, lock.LockedAt.Format(time.RFC3339))
+}
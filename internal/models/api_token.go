@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+)
+
+// APIToken 個人 API Token（只保存雜湊值，Token 本身僅在建立時回傳一次）
+type APIToken struct {
+	ID        string     `bson:"_id" json:"_id"`
+	UserID    string     `bson:"user_id" json:"-"`
+	Name      string     `bson:"name" json:"name"`
+	Prefix    string     `bson:"prefix" json:"prefix"` // Token 開頭，方便使用者辨識
+	TokenHash string     `bson:"token_hash" json:"-"`  // Token 的 SHA-256 雜湊值（hex）
+	Scopes    []string   `bson:"scopes" json:"scopes"`
+	ExpiresAt *time.Time `bson:"expires_at,omitempty" json:"expires_at,omitempty"`
+	LastUsed  *time.Time `bson:"last_used,omitempty" json:"last_used,omitempty"`
+	Created   time.Time  `bson:"created" json:"created"`
+}
+
+// Usable 檢查 Token 在指定時間是否仍可使用（未過期）
+func (t *APIToken) Usable(now time.Time) bool {
+	return t.ExpiresAt == nil || now.Before(*t.ExpiresAt)
+}
@@ -18,11 +18,44 @@ const (
 	ChannelTypeUnclassified ChannelType = "unclassified"
 )
 
+// ChannelVisibility 頻道可見度
+type ChannelVisibility string
+
+const (
+	// ChannelVisibilityPublic 公開頻道，會出現在頻道列表
+	ChannelVisibilityPublic ChannelVisibility = "public"
+	// ChannelVisibilityUnlisted 不公開列出的頻道，知道頻道 ID 即可讀取
+	ChannelVisibilityUnlisted ChannelVisibility = "unlisted"
+	// ChannelVisibilityPrivate 私人頻道，只有擁有者與具有 read 權限的使用者可以讀取
+	ChannelVisibilityPrivate ChannelVisibility = "private"
+)
+
+// Valid 檢查頻道可見度是否有效
+func (v ChannelVisibility) Valid() bool {
+	return v == ChannelVisibilityPublic || v == ChannelVisibilityUnlisted || v == ChannelVisibilityPrivate
+}
+
 // ChannelCover 頻道封面
 type ChannelCover struct {
 	Default string `bson:"default" json:"default"`
 }
 
+// ScheduleLoop 線性播出的循環模式
+type ScheduleLoop string
+
+const (
+	// ScheduleLoopRepeat 播完最後一個節目後從頭循環
+	ScheduleLoopRepeat ScheduleLoop = "repeat"
+	// ScheduleLoopOnce 只播一輪，播完後停播
+	ScheduleLoopOnce ScheduleLoop = "once"
+)
+
+// ChannelSchedule 頻道線性播出設定（以 Epoch 為第一個節目的開播時間）
+type ChannelSchedule struct {
+	Epoch time.Time    `bson:"epoch" json:"epoch"`
+	Loop  ScheduleLoop `bson:"loop" json:"loop"`
+}
+
 // ChannelPermission 頻道權限
 type ChannelPermission struct {
 	UserID string `bson:"user_id" json:"user_id"`
@@ -31,6 +64,16 @@ type ChannelPermission struct {
 	Write  bool   `bson:"write" json:"write"`
 }
 
+// CanRead 是否可以讀取頻道（admin 與 write 皆包含 read）
+func (p ChannelPermission) CanRead() bool {
+	return p.Admin || p.Read || p.Write
+}
+
+// CanWrite 是否可以新增、編輯與排序節目（admin 包含 write）
+func (p ChannelPermission) CanWrite() bool {
+	return p.Admin || p.Write
+}
+
 // Channel 頻道模型
 type Channel struct {
 	ID            string             `bson:"_id" json:"_id"` // 讀取時使用自訂解碼，寫入時直接使用字串
@@ -44,6 +87,13 @@ type Channel struct {
 	ContentsOrder []int              `bson:"contents_order" json:"contents_order"`
 	Owners        []string           `bson:"owners" json:"owners"`
 	Permission    []ChannelPermission `bson:"permission" json:"permission"`
+	Schedule      *ChannelSchedule   `bson:"schedule,omitempty" json:"schedule,omitempty"`
+	HideUnavailable bool             `bson:"hide_unavailable,omitempty" json:"hide_unavailable"` // 取得頻道時隱藏無法播放的節目
+	Visibility    ChannelVisibility  `bson:"visibility" json:"visibility"` // public、unlisted 或 private
+	DeletedAt     *time.Time         `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"` // 移到垃圾桶的時間（保留期限過後永久刪除）
+	Revision      int64              `bson:"revision" json:"revision"` // 修訂版本，每次修改頻道或節目時遞增（作為 ETag）
+	ForkedFrom    string             `bson:"forked_from,omitempty" json:"forked_from,omitempty"` // 複製來源頻道 ID
+	ForkCount     int                `bson:"fork_count" json:"fork_count"` // 被複製的次數
 	Created       time.Time          `bson:"created" json:"created"`
 	LastModified  time.Time          `bson:"last_modified" json:"last_modified"`
 }
@@ -63,6 +113,13 @@ func (c *Channel) UnmarshalBSON(data []byte) error {
 		ContentsOrder []int                `bson:"contents_order"`
 		Owners        []string             `bson:"owners"`
 		Permission    []ChannelPermission  `bson:"permission"`
+		Schedule      *ChannelSchedule     `bson:"schedule,omitempty"`
+		HideUnavailable bool               `bson:"hide_unavailable,omitempty"`
+		Visibility    ChannelVisibility    `bson:"visibility"`
+		DeletedAt     *time.Time           `bson:"deleted_at,omitempty"`
+		Revision      int64                `bson:"revision"`
+		ForkedFrom    string               `bson:"forked_from,omitempty"`
+		ForkCount     int                  `bson:"fork_count"`
 		Created       time.Time            `bson:"created"`
 		LastModified  time.Time            `bson:"last_modified"`
 	}{}
@@ -83,6 +140,17 @@ func (c *Channel) UnmarshalBSON(data []byte) error {
 	c.ContentsOrder = aux.ContentsOrder
 	c.Owners = aux.Owners
 	c.Permission = aux.Permission
+	c.Schedule = aux.Schedule
+	c.HideUnavailable = aux.HideUnavailable
+	c.Visibility = aux.Visibility
+	if c.Visibility == "" {
+		// 加入可見度之前建立的頻道沒有 visibility 欄位，視為公開頻道
+		c.Visibility = ChannelVisibilityPublic
+	}
+	c.DeletedAt = aux.DeletedAt
+	c.Revision = aux.Revision
+	c.ForkedFrom = aux.ForkedFrom
+	c.ForkCount = aux.ForkCount
 	c.Created = aux.Created
 	c.LastModified = aux.LastModified
 	
@@ -121,6 +189,67 @@ func (c *Channel) UnmarshalBSON(data []byte) error {
 	return nil
 }
 
+// OrderedContents 依 contents_order 排列的節目
+func (c *Channel) OrderedContents() []Program {
+	return OrderPrograms(c.Contents, c.ContentsOrder)
+}
+
+// IsOwner 檢查使用者是否為頻道擁有者
+func (c *Channel) IsOwner(userID string) bool {
+	for _, owner := range c.Owners {
+		if owner == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// PermissionFor 取得使用者對頻道的權限（擁有者具有所有權限）
+func (c *Channel) PermissionFor(userID string) ChannelPermission {
+	if userID == "" {
+		return ChannelPermission{}
+	}
+	if c.IsOwner(userID) {
+		return ChannelPermission{UserID: userID, Admin: true, Read: true, Write: true}
+	}
+	for _, permission := range c.Permission {
+		if permission.UserID == userID {
+			return permission
+		}
+	}
+	return ChannelPermission{UserID: userID}
+}
+
+// Readable 檢查使用者是否可以讀取頻道（公開與不公開列出的頻道所有人皆可讀取）
+func (c *Channel) Readable(userID string) bool {
+	return c.Visibility != ChannelVisibilityPrivate || c.PermissionFor(userID).CanRead()
+}
+
+// HideUnavailableContents 移除已確認無法播放的節目（同時從 contents_order 移除）
+func (c *Channel) HideUnavailableContents() {
+	hidden := make(map[int]bool)
+	contents := make([]Program, 0, len(c.Contents))
+	for _, program := range c.Contents {
+		if program.IsUnavailable() {
+			hidden[program.ID] = true
+			continue
+		}
+		contents = append(contents, program)
+	}
+	if len(hidden) == 0 {
+		return
+	}
+
+	order := make([]int, 0, len(c.ContentsOrder))
+	for _, id := range c.ContentsOrder {
+		if !hidden[id] {
+			order = append(order, id)
+		}
+	}
+	c.Contents = contents
+	c.ContentsOrder = order
+}
+
 // ChannelWithOwnersInfo 頻道資訊（含擁有者資訊，用於 getchannelinfo API）
 type ChannelWithOwnersInfo struct {
 	Channel
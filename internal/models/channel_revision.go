@@ -0,0 +1,72 @@
+package models
+
+import (
+	"time"
+)
+
+// 頻道歷史版本的修改動作
+const (
+	RevisionActionCreate         = "create"
+	RevisionActionUpdate         = "update"
+	RevisionActionAddProgram     = "add_program"
+	RevisionActionUpdateProgram  = "update_program"
+	RevisionActionDeletePrograms = "delete_programs"
+	RevisionActionMovePrograms   = "move_programs"
+	RevisionActionSaveOrder      = "save_order"
+	RevisionActionImport         = "import"
+	RevisionActionRestore        = "restore"
+	RevisionActionFork           = "fork"
+	RevisionActionSchedule       = "set_schedule"
+	RevisionActionOwners         = "update_owners"
+	RevisionActionPermission     = "set_permission"
+	RevisionActionTrash          = "trash"
+	RevisionActionUntrash        = "untrash"
+)
+
+// ChannelSnapshot 頻道快照（頻道資訊、播出設定、節目與節目順序）
+type ChannelSnapshot struct {
+	Name            string            `bson:"name" json:"name"`
+	Desc            string            `bson:"desc" json:"desc"`
+	Tags            []int             `bson:"tags" json:"tags"`
+	Cover           *ChannelCover     `bson:"cover,omitempty" json:"cover,omitempty"`
+	HideUnavailable bool              `bson:"hide_unavailable" json:"hide_unavailable"`
+	Visibility      ChannelVisibility `bson:"visibility" json:"visibility"`
+	Schedule        *ChannelSchedule  `bson:"schedule,omitempty" json:"schedule,omitempty"`
+	Contents        []Program         `bson:"contents" json:"contents"`
+	ContentsOrder   []int             `bson:"contents_order" json:"contents_order"`
+}
+
+// NewChannelSnapshot 建立頻道目前狀態的快照
+func NewChannelSnapshot(channel *Channel) ChannelSnapshot {
+	snapshot := ChannelSnapshot{
+		Name:            channel.Name,
+		Desc:            channel.Desc,
+		Tags:            channel.Tags,
+		Cover:           channel.Cover,
+		HideUnavailable: channel.HideUnavailable,
+		Visibility:      channel.Visibility,
+		Schedule:        channel.Schedule,
+		Contents:        channel.Contents,
+		ContentsOrder:   channel.ContentsOrder,
+	}
+	if snapshot.Tags == nil {
+		snapshot.Tags = []int{}
+	}
+	if snapshot.Contents == nil {
+		snapshot.Contents = []Program{}
+	}
+	if snapshot.ContentsOrder == nil {
+		snapshot.ContentsOrder = []int{}
+	}
+	return snapshot
+}
+
+// ChannelRevision 頻道歷史版本（每次修改頻道或節目後記錄，只新增不修改）
+type ChannelRevision struct {
+	ID        string          `bson:"_id" json:"_id"`
+	ChannelID string          `bson:"channel_id" json:"channel_id"`
+	Revision  int64           `bson:"revision" json:"revision"` // 記錄時頻道的修訂版本
+	Action    string          `bson:"action" json:"action"`
+	Snapshot  ChannelSnapshot `bson:"snapshot" json:"snapshot"`
+	Created   time.Time       `bson:"created" json:"created"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+)
+
+// 頻道分享邀請的角色
+const (
+	ShareRoleOwner = "owner"
+	ShareRoleAdmin = "admin"
+	ShareRoleWrite = "write"
+	ShareRoleRead  = "read"
+)
+
+// ShareRoles 所有有效的頻道分享邀請角色
+var ShareRoles = []string{ShareRoleOwner, ShareRoleAdmin, ShareRoleWrite, ShareRoleRead}
+
+// 頻道分享邀請的狀態
+const (
+	ShareStatusPending  = "pending"
+	ShareStatusAccepted = "accepted"
+	ShareStatusDeclined = "declined"
+	ShareStatusRevoked  = "revoked"
+)
+
+// ChannelShare 頻道分享邀請（以 Email 寄送，受邀者接受後才成為擁有者或取得權限）
+type ChannelShare struct {
+	ID           string    `bson:"_id" json:"_id"`
+	ChannelID    string    `bson:"channel_id" json:"channel_id"`
+	Email        string    `bson:"email" json:"email"`
+	Role         string    `bson:"role" json:"role"`
+	TokenHash    string    `bson:"token_hash" json:"-"`
+	InvitedBy    string    `bson:"invited_by" json:"invited_by"`     // 邀請者的使用者 ID
+	Status       string    `bson:"status" json:"status"`             // pending、accepted、declined 或 revoked
+	UserID       string    `bson:"user_id" json:"user_id,omitempty"` // 接受或拒絕的使用者 ID
+	ExpiresAt    time.Time `bson:"expires_at" json:"expires_at"`
+	Created      time.Time `bson:"created" json:"created"`
+	LastModified time.Time `bson:"last_modified" json:"last_modified"`
+}
+
+// Pending 檢查邀請在指定時間是否仍可接受或拒絕（尚未回應且未過期）
+func (s *ChannelShare) Pending(now time.Time) bool {
+	return s.Status == ShareStatusPending && now.Before(s.ExpiresAt)
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+)
+
+// Invitation 註冊邀請碼
+type Invitation struct {
+	ID           string     `bson:"_id" json:"_id"`
+	Code         string     `bson:"code" json:"code"`
+	Note         string     `bson:"note" json:"note"`
+	CreatedBy    string     `bson:"created_by" json:"created_by"` // 建立者的使用者 ID（系統建立時為空）
+	MaxUses      int        `bson:"max_uses" json:"max_uses"`     // 可使用次數，0 表示不限次數
+	Uses         int        `bson:"uses" json:"uses"`
+	ExpiresAt    *time.Time `bson:"expires_at,omitempty" json:"expires_at,omitempty"`
+	Revoked      bool       `bson:"revoked" json:"revoked"`
+	Created      time.Time  `bson:"created" json:"created"`
+	LastModified time.Time  `bson:"last_modified" json:"last_modified"`
+}
+
+// Usable 檢查邀請碼在指定時間是否仍可使用（未撤銷、未過期且未用完）
+func (i *Invitation) Usable(now time.Time) bool {
+	if i.Revoked {
+		return false
+	}
+	if i.ExpiresAt != nil && !now.Before(*i.ExpiresAt) {
+		return false
+	}
+	return i.MaxUses == 0 || i.Uses < i.MaxUses
+}
@@ -8,6 +8,26 @@ type ProgramType string
 const (
 	// ProgramTypeYouTube YouTube 節目類型
 	ProgramTypeYouTube ProgramType = "youtube"
+	// ProgramTypeVimeo Vimeo 節目類型
+	ProgramTypeVimeo ProgramType = "vimeo"
+	// ProgramTypeDailymotion Dailymotion 節目類型
+	ProgramTypeDailymotion ProgramType = "dailymotion"
+	// ProgramTypeMP4 直接連結的 MP4 影片
+	ProgramTypeMP4 ProgramType = "mp4"
+	// ProgramTypeHLS 直接連結的 HLS 串流
+	ProgramTypeHLS ProgramType = "hls"
+)
+
+// Availability 節目影片的可播放狀態
+type Availability string
+
+const (
+	// AvailabilityAvailable 影片可以播放
+	AvailabilityAvailable Availability = "available"
+	// AvailabilityUnavailable 影片已移除或設為私人
+	AvailabilityUnavailable Availability = "unavailable"
+	// AvailabilityUnknown 無法判斷（來源不支援檢查）
+	AvailabilityUnknown Availability = "unknown"
 )
 
 // Program 節目模型
@@ -18,8 +38,58 @@ type Program struct {
 	Duration    int        `bson:"duration" json:"duration"` // 秒
 	Type        ProgramType `bson:"type" json:"type"`
 	YouTubeID   string     `bson:"youtube_id" json:"youtube_id"`
+	VideoID     string     `bson:"video_id,omitempty" json:"video_id"` // 各來源的影片 ID（YouTube 節目與 youtube_id 相同）
 	Tags        []int      `bson:"tags" json:"tags"`
 	Created     time.Time  `bson:"created" json:"created"`
 	LastModified time.Time `bson:"last_modified" json:"last_modified"`
+	MetadataAt  *time.Time `bson:"metadata_at,omitempty" json:"metadata_at,omitempty"` // 最後一次查詢影片資訊的時間
+	Availability Availability `bson:"availability,omitempty" json:"availability,omitempty"` // 尚未檢查時為空
+	LastChecked *time.Time `bson:"last_checked,omitempty" json:"last_checked,omitempty"` // 最後一次檢查可播放狀態的時間
+}
+
+// MediaID 取得影片 ID（舊資料沒有 video_id 時使用 youtube_id）
+func (p *Program) MediaID() string {
+	if p.VideoID != "" {
+		return p.VideoID
+	}
+	return p.YouTubeID
 }
 
+// MediaType 取得節目類型（舊資料沒有 type 時視為 YouTube）
+func (p *Program) MediaType() ProgramType {
+	if p.Type == "" {
+		return ProgramTypeYouTube
+	}
+	return p.Type
+}
+
+// IsUnavailable 影片是否已確認無法播放
+func (p *Program) IsUnavailable() bool {
+	return p.Availability == AvailabilityUnavailable
+}
+
+// OrderPrograms 依 order 排列節目，未列在順序中的節目依原順序接在後面
+func OrderPrograms(contents []Program, order []int) []Program {
+	byID := make(map[int]Program, len(contents))
+	for _, program := range contents {
+		byID[program.ID] = program
+	}
+
+	result := make([]Program, 0, len(contents))
+	used := make(map[int]bool, len(contents))
+	for _, id := range order {
+		program, ok := byID[id]
+		if !ok || used[id] {
+			continue
+		}
+		result = append(result, program)
+		used[id] = true
+	}
+	for _, program := range contents {
+		if !used[program.ID] {
+			result = append(result, program)
+			used[program.ID] = true
+		}
+	}
+	return result
+}
@@ -0,0 +1,8 @@
+package models
+
+// ProgramSearchResult 節目搜尋結果（含所屬頻道）
+type ProgramSearchResult struct {
+	Program     Program `bson:"program" json:"program"`
+	ChannelID   string  `bson:"channel_id" json:"channel_id"`
+	ChannelName string  `bson:"channel_name" json:"channel_name"`
+}
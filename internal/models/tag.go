@@ -0,0 +1,15 @@
+package models
+
+import (
+	"time"
+)
+
+// Tag 標籤（Channel.Tags 與 Program.Tags 使用的 ID）
+type Tag struct {
+	ID           int               `bson:"_id" json:"_id"`
+	Slug         string            `bson:"slug" json:"slug"`
+	Labels       map[string]string `bson:"labels" json:"labels"`                     // 各語系的顯示名稱（例如 zh-TW、en）
+	Parent       *int              `bson:"parent,omitempty" json:"parent,omitempty"` // 上層標籤 ID
+	Created      time.Time         `bson:"created" json:"created"`
+	LastModified time.Time         `bson:"last_modified" json:"last_modified"`
+}
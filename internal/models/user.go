@@ -13,6 +13,7 @@ type User struct {
 	OwnChannels         []string  `bson:"own_channels" json:"own_channels"`
 	UnclassifiedChannel *string  `bson:"unclassified_channel,omitempty" json:"unclassified_channel,omitempty"`
 	AccessKey           *string   `bson:"access_key,omitempty" json:"-"`
+	InvitationID        *string   `bson:"invitation_id,omitempty" json:"invitation_id,omitempty"` // 註冊時使用的邀請碼
 	Created             time.Time `bson:"created" json:"created"`
 	LastModified        time.Time `bson:"last_modified" json:"last_modified"`
 }
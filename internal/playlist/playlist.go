@@ -0,0 +1,298 @@
+package playlist
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/higgstv/higgstv-go/internal/models"
+	"github.com/higgstv/higgstv-go/pkg/provider"
+)
+
+// Format 播放清單格式
+type Format string
+
+const (
+	// FormatM3U Extended M3U
+	FormatM3U Format = "m3u"
+	// FormatXSPF XML Shareable Playlist Format
+	FormatXSPF Format = "xspf"
+	// FormatJSON HiggsTV JSON 播放清單
+	FormatJSON Format = "json"
+)
+
+// ErrUnsupportedFormat 不支援的播放清單格式
+var ErrUnsupportedFormat = errors.New("unsupported playlist format")
+
+// Playlist 播放清單
+type Playlist struct {
+	Title   string  `json:"title"`
+	Desc    string  `json:"desc,omitempty"`
+	Image   string  `json:"image,omitempty"`
+	Entries []Entry `json:"entries"`
+}
+
+// Entry 播放清單項目
+type Entry struct {
+	Title     string `json:"title"`
+	URL       string `json:"url"`
+	Type      string `json:"type,omitempty"`
+	VideoID   string `json:"video_id,omitempty"`
+	YouTubeID string `json:"youtube_id,omitempty"`
+	Desc      string `json:"desc,omitempty"`
+	Duration  int    `json:"duration"` // 秒，0 表示未知
+	Tags      []int  `json:"tags,omitempty"`
+}
+
+// ParseFormat 解析格式名稱（接受 m3u8 作為 m3u 的別名）
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "m3u", "m3u8":
+		return FormatM3U, nil
+	case "xspf":
+		return FormatXSPF, nil
+	case "json":
+		return FormatJSON, nil
+	}
+	return "", ErrUnsupportedFormat
+}
+
+// FormatFromFilename 依副檔名判斷格式
+func FormatFromFilename(filename string) (Format, error) {
+	return ParseFormat(strings.TrimPrefix(path.Ext(filename), "."))
+}
+
+// Detect 依內容判斷格式
+func Detect(data []byte) (Format, error) {
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")))
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("#EXTM3U")):
+		return FormatM3U, nil
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		return FormatXSPF, nil
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		return FormatJSON, nil
+	}
+	return "", ErrUnsupportedFormat
+}
+
+// ContentType 格式對應的 Content-Type
+func ContentType(format Format) string {
+	switch format {
+	case FormatM3U:
+		return "audio/x-mpegurl; charset=utf-8"
+	case FormatXSPF:
+		return "application/xspf+xml; charset=utf-8"
+	default:
+		return "application/json; charset=utf-8"
+	}
+}
+
+// FromChannel 依 contents_order 將頻道節目轉為播放清單
+func FromChannel(channel *models.Channel) *Playlist {
+	playlist := &Playlist{
+		Title:   channel.Name,
+		Desc:    channel.Desc,
+		Entries: []Entry{},
+	}
+	if channel.Cover != nil {
+		playlist.Image = channel.Cover.Default
+	}
+	for _, program := range channel.OrderedContents() {
+		entry := Entry{
+			Title:     program.Name,
+			Type:      string(program.MediaType()),
+			VideoID:   program.MediaID(),
+			YouTubeID: program.YouTubeID,
+			Desc:      program.Desc,
+			Duration:  program.Duration,
+			Tags:      program.Tags,
+		}
+		if p, ok := provider.Get(entry.Type); ok {
+			entry.URL = p.CanonicalURL(entry.VideoID)
+		}
+		playlist.Entries = append(playlist.Entries, entry)
+	}
+	return playlist
+}
+
+// Write 以指定格式輸出播放清單
+func Write(w io.Writer, format Format, playlist *Playlist) error {
+	switch format {
+	case FormatM3U:
+		return writeM3U(w, playlist)
+	case FormatXSPF:
+		return writeXSPF(w, playlist)
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(playlist)
+	}
+	return ErrUnsupportedFormat
+}
+
+// Parse 以指定格式解析播放清單
+func Parse(r io.Reader, format Format) (*Playlist, error) {
+	switch format {
+	case FormatM3U:
+		return parseM3U(r)
+	case FormatXSPF:
+		return parseXSPF(r)
+	case FormatJSON:
+		var playlist Playlist
+		if err := json.NewDecoder(r).Decode(&playlist); err != nil {
+			return nil, fmt.Errorf("invalid json playlist: %w", err)
+		}
+		return &playlist, nil
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+// writeM3U 輸出 Extended M3U
+func writeM3U(w io.Writer, playlist *Playlist) error {
+	var buf bytes.Buffer
+	buf.WriteString("#EXTM3U\n")
+	if playlist.Title != "" {
+		buf.WriteString("#PLAYLIST:" + oneLine(playlist.Title) + "\n")
+	}
+	for _, entry := range playlist.Entries {
+		duration := entry.Duration
+		if duration <= 0 {
+			duration = -1
+		}
+		fmt.Fprintf(&buf, "#EXTINF:%d,%s\n%s\n", duration, oneLine(entry.Title), entry.URL)
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// parseM3U 解析 M3U / Extended M3U
+func parseM3U(r io.Reader) (*Playlist, error) {
+	playlist := &Playlist{Entries: []Entry{}}
+	var pending *Entry
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\ufeff"))
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "#EXTINF:"):
+			entry := Entry{}
+			info := strings.TrimPrefix(line, "#EXTINF:")
+			if idx := strings.Index(info, ","); idx >= 0 {
+				entry.Title = strings.TrimSpace(info[idx+1:])
+				info = info[:idx]
+			}
+			// 時長後面可能接屬性（例如 tvg-id="..."），只取第一個欄位
+			if fields := strings.Fields(info); len(fields) > 0 {
+				if d, err := strconv.Atoi(fields[0]); err == nil && d > 0 {
+					entry.Duration = d
+				}
+			}
+			pending = &entry
+		case strings.HasPrefix(line, "#PLAYLIST:"):
+			playlist.Title = strings.TrimSpace(strings.TrimPrefix(line, "#PLAYLIST:"))
+		case strings.HasPrefix(line, "#"):
+			continue
+		default:
+			entry := Entry{}
+			if pending != nil {
+				entry = *pending
+				pending = nil
+			}
+			entry.URL = line
+			playlist.Entries = append(playlist.Entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return playlist, nil
+}
+
+// xspfPlaylist XSPF 根節點
+type xspfPlaylist struct {
+	XMLName    xml.Name    `xml:"playlist"`
+	Version    string      `xml:"version,attr"`
+	Xmlns      string      `xml:"xmlns,attr"`
+	Title      string      `xml:"title,omitempty"`
+	Annotation string      `xml:"annotation,omitempty"`
+	Image      string      `xml:"image,omitempty"`
+	Tracks     []xspfTrack `xml:"trackList>track"`
+}
+
+type xspfTrack struct {
+	Location   string `xml:"location"`
+	Title      string `xml:"title,omitempty"`
+	Annotation string `xml:"annotation,omitempty"`
+	Duration   int    `xml:"duration,omitempty"` // 毫秒
+}
+
+// writeXSPF 輸出 XSPF
+func writeXSPF(w io.Writer, playlist *Playlist) error {
+	doc := xspfPlaylist{
+		Version:    "1",
+		Xmlns:      "http://xspf.org/ns/0/",
+		Title:      playlist.Title,
+		Annotation: playlist.Desc,
+		Image:      playlist.Image,
+		Tracks:     make([]xspfTrack, 0, len(playlist.Entries)),
+	}
+	for _, entry := range playlist.Entries {
+		doc.Tracks = append(doc.Tracks, xspfTrack{
+			Location:   entry.URL,
+			Title:      entry.Title,
+			Annotation: entry.Desc,
+			Duration:   entry.Duration * 1000,
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// parseXSPF 解析 XSPF
+func parseXSPF(r io.Reader) (*Playlist, error) {
+	var doc xspfPlaylist
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid xspf playlist: %w", err)
+	}
+
+	playlist := &Playlist{
+		Title:   doc.Title,
+		Desc:    doc.Annotation,
+		Image:   doc.Image,
+		Entries: make([]Entry, 0, len(doc.Tracks)),
+	}
+	for _, track := range doc.Tracks {
+		playlist.Entries = append(playlist.Entries, Entry{
+			Title:    strings.TrimSpace(track.Title),
+			URL:      strings.TrimSpace(track.Location),
+			Desc:     strings.TrimSpace(track.Annotation),
+			Duration: track.Duration / 1000,
+		})
+	}
+	return playlist, nil
+}
+
+// oneLine 移除換行（M3U 以行為單位）
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,248 @@
+package playlist
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/higgstv/higgstv-go/pkg/provider"
+)
+
+// ErrNotPlaylist URL 不是可解析的線上播放清單
+var ErrNotPlaylist = errors.New("not a playlist url")
+
+// ErrPlaylistNotFound 播放清單不存在或未公開
+var ErrPlaylistNotFound = errors.New("playlist not found")
+
+// Resolver 線上播放清單解析介面
+type Resolver interface {
+	Resolve(ctx context.Context, rawURL string) (*Playlist, error)
+}
+
+// DefaultResolver 預設的播放清單解析器
+var DefaultResolver Resolver = NewYouTubeResolver(ResolverConfig{})
+
+// InitResolver 以設定建立預設的播放清單解析器
+func InitResolver(config ResolverConfig) {
+	DefaultResolver = NewYouTubeResolver(config)
+}
+
+// ResolverConfig 播放清單解析設定
+type ResolverConfig struct {
+	// BaseURL 設定時改用 <BaseURL>/feeds/videos.xml 與 <BaseURL>/youtube/v3/playlistItems（測試或自架代理）
+	BaseURL string
+	// APIKey YouTube Data API 金鑰，設定時改用 API 分頁取得完整清單（RSS 只包含前 15 部影片）
+	APIKey string
+	// Timeout 單次請求逾時
+	Timeout time.Duration
+	// MaxItems 單一播放清單最多取得的影片數，0 表示使用預設值
+	MaxItems int
+	// Client 自訂 HTTP Client（選填）
+	Client *http.Client
+}
+
+// defaultMaxItems 單一播放清單預設最多取得的影片數
+const defaultMaxItems = 200
+
+// youtubePageSize YouTube Data API 每頁最多的項目數
+const youtubePageSize = 50
+
+var youtubePlaylistIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{2,64}$`)
+
+// YouTubeResolver 解析 YouTube 播放清單
+type YouTubeResolver struct {
+	feedURL  string
+	apiURL   string
+	apiKey   string
+	maxItems int
+	client   *http.Client
+}
+
+// NewYouTubeResolver 建立 YouTube 播放清單解析器
+func NewYouTubeResolver(config ResolverConfig) *YouTubeResolver {
+	feedURL := "https://www.youtube.com/feeds/videos.xml"
+	apiURL := "https://www.googleapis.com/youtube/v3/playlistItems"
+	if config.BaseURL != "" {
+		base := strings.TrimRight(config.BaseURL, "/")
+		feedURL = base + "/feeds/videos.xml"
+		apiURL = base + "/youtube/v3/playlistItems"
+	}
+	maxItems := config.MaxItems
+	if maxItems <= 0 {
+		maxItems = defaultMaxItems
+	}
+	client := config.Client
+	if client == nil {
+		timeout := config.Timeout
+		if timeout <= 0 {
+			timeout = 10 * time.Second
+		}
+		client = &http.Client{Timeout: timeout}
+	}
+
+	return &YouTubeResolver{
+		feedURL:  feedURL,
+		apiURL:   apiURL,
+		apiKey:   config.APIKey,
+		maxItems: maxItems,
+		client:   client,
+	}
+}
+
+// YouTubePlaylistID 從 URL 取出 YouTube 播放清單 ID（/playlist?list=、/watch?v=...&list= 或 youtu.be/...?list=）
+func YouTubePlaylistID(rawURL string) (string, bool) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", false
+	}
+	switch strings.ToLower(strings.TrimPrefix(u.Hostname(), "www.")) {
+	case "youtube.com", "m.youtube.com", "music.youtube.com", "youtu.be":
+	default:
+		return "", false
+	}
+	id := u.Query().Get("list")
+	if !youtubePlaylistIDPattern.MatchString(id) {
+		return "", false
+	}
+	return id, true
+}
+
+// Resolve 取得播放清單的影片（依播放清單順序）
+func (r *YouTubeResolver) Resolve(ctx context.Context, rawURL string) (*Playlist, error) {
+	id, ok := YouTubePlaylistID(rawURL)
+	if !ok {
+		return nil, ErrNotPlaylist
+	}
+	if r.apiKey != "" {
+		return r.resolveAPI(ctx, id)
+	}
+	return r.resolveFeed(ctx, id)
+}
+
+type youtubeFeed struct {
+	Title   string `xml:"title"`
+	Entries []struct {
+		VideoID string `xml:"http://www.youtube.com/xml/schemas/2015 videoId"`
+		Title   string `xml:"title"`
+	} `xml:"entry"`
+}
+
+// resolveFeed 透過公開的 RSS 取得播放清單
+func (r *YouTubeResolver) resolveFeed(ctx context.Context, id string) (*Playlist, error) {
+	body, err := r.get(ctx, r.feedURL, url.Values{"playlist_id": {id}})
+	if err != nil {
+		return nil, err
+	}
+
+	var feed youtubeFeed
+	if err := xml.Unmarshal(body, &feed); err != nil {
+		return nil, fmt.Errorf("invalid playlist feed: %w", err)
+	}
+
+	result := &Playlist{Title: feed.Title, Entries: []Entry{}}
+	for _, entry := range feed.Entries {
+		if len(result.Entries) >= r.maxItems {
+			break
+		}
+		result.add(entry.VideoID, entry.Title, "")
+	}
+	return result, nil
+}
+
+type youtubePlaylistItems struct {
+	NextPageToken string `json:"nextPageToken"`
+	Items         []struct {
+		Snippet struct {
+			Title       string `json:"title"`
+			Description string `json:"description"`
+			ResourceID  struct {
+				VideoID string `json:"videoId"`
+			} `json:"resourceId"`
+		} `json:"snippet"`
+	} `json:"items"`
+}
+
+// resolveAPI 透過 YouTube Data API 分頁取得播放清單
+func (r *YouTubeResolver) resolveAPI(ctx context.Context, id string) (*Playlist, error) {
+	result := &Playlist{Entries: []Entry{}}
+	pageToken := ""
+	for {
+		query := url.Values{
+			"part":       {"snippet"},
+			"playlistId": {id},
+			"maxResults": {fmt.Sprint(youtubePageSize)},
+			"key":        {r.apiKey},
+		}
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+		body, err := r.get(ctx, r.apiURL, query)
+		if err != nil {
+			return nil, err
+		}
+
+		var page youtubePlaylistItems
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("invalid playlist response: %w", err)
+		}
+		for _, item := range page.Items {
+			if len(result.Entries) >= r.maxItems {
+				return result, nil
+			}
+			result.add(item.Snippet.ResourceID.VideoID, item.Snippet.Title, item.Snippet.Description)
+		}
+
+		if page.NextPageToken == "" || page.NextPageToken == pageToken {
+			return result, nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+// add 新增 YouTube 影片項目（略過沒有影片 ID 的項目）
+func (p *Playlist) add(videoID, title, desc string) {
+	if videoID == "" {
+		return
+	}
+	entry := Entry{
+		Title:   title,
+		Type:    provider.TypeYouTube,
+		VideoID: videoID,
+		Desc:    desc,
+	}
+	if youtube, ok := provider.Get(provider.TypeYouTube); ok {
+		entry.URL = youtube.CanonicalURL(videoID)
+	}
+	p.Entries = append(p.Entries, entry)
+}
+
+// get 發送 GET 請求並讀取回應內容
+func (r *YouTubeResolver) get(ctx context.Context, endpoint string, query url.Values) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	switch {
+	case resp.StatusCode == http.StatusBadRequest, resp.StatusCode == http.StatusForbidden, resp.StatusCode == http.StatusNotFound:
+		return nil, ErrPlaylistNotFound
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("playlist request failed: %s", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+}
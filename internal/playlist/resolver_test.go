@@ -0,0 +1,69 @@
+package playlist
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestYouTubePlaylistID(t *testing.T) {
+	tests := []struct {
+		url string
+		id  string
+		ok  bool
+	}{
+		{"https://www.youtube.com/playlist?list=PLabc_123-x", "PLabc_123-x", true},
+		{"https://m.youtube.com/watch?v=dQw4w9WgXcQ&list=PLabc", "PLabc", true},
+		{"https://youtu.be/dQw4w9WgXcQ?list=PLabc", "PLabc", true},
+		{"https://www.youtube.com/watch?v=dQw4w9WgXcQ", "", false},
+		{"https://example.com/playlist?list=PLabc", "", false},
+		{"https://www.youtube.com/playlist?list=bad%20id", "", false},
+	}
+	for _, tt := range tests {
+		id, ok := YouTubePlaylistID(tt.url)
+		assert.Equal(t, tt.ok, ok, tt.url)
+		assert.Equal(t, tt.id, id, tt.url)
+	}
+}
+
+func TestYouTubeResolverAPIPagination(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if r.URL.Path != "/youtube/v3/playlistItems" || query.Get("key") != "secret" || query.Get("playlistId") != "PLabc" {
+			http.NotFound(w, r)
+			return
+		}
+		switch query.Get("pageToken") {
+		case "":
+			_, _ = w.Write([]byte(`{"nextPageToken":"p2","items":[
+				{"snippet":{"title":"One","resourceId":{"videoId":"dQw4w9WgXcQ"}}},
+				{"snippet":{"title":"Deleted video","resourceId":{}}}
+			]}`))
+		case "p2":
+			_, _ = w.Write([]byte(`{"items":[
+				{"snippet":{"title":"Two","description":"second","resourceId":{"videoId":"9bZkp7q19f0"}}},
+				{"snippet":{"title":"Three","resourceId":{"videoId":"kJQP7kiw5Fk"}}}
+			]}`))
+		}
+	}))
+	defer server.Close()
+
+	resolver := NewYouTubeResolver(ResolverConfig{BaseURL: server.URL, APIKey: "secret", MaxItems: 2})
+	result, err := resolver.Resolve(context.Background(), "https://www.youtube.com/playlist?list=PLabc")
+	require.NoError(t, err)
+	require.Len(t, result.Entries, 2)
+	assert.Equal(t, "dQw4w9WgXcQ", result.Entries[0].VideoID)
+	assert.Equal(t, "https://www.youtube.com/watch?v=dQw4w9WgXcQ", result.Entries[0].URL)
+	assert.Equal(t, "Two", result.Entries[1].Title)
+	assert.Equal(t, "second", result.Entries[1].Desc)
+
+	_, err = resolver.Resolve(context.Background(), "https://www.youtube.com/playlist?list=PLmissing")
+	assert.ErrorIs(t, err, ErrPlaylistNotFound)
+
+	_, err = resolver.Resolve(context.Background(), "https://vimeo.com/76979871")
+	assert.ErrorIs(t, err, ErrNotPlaylist)
+}
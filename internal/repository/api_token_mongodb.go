@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/models"
+)
+
+// MongoDBAPITokenRepository MongoDB 個人 API Token Repository
+type MongoDBAPITokenRepository struct {
+	collection database.Collection
+}
+
+// NewMongoDBAPITokenRepository 建立 MongoDB 個人 API Token Repository
+func NewMongoDBAPITokenRepository(db database.Database) *MongoDBAPITokenRepository {
+	return &MongoDBAPITokenRepository{
+		collection: db.Collection("api_tokens"),
+	}
+}
+
+// Create 建立 API Token
+func (r *MongoDBAPITokenRepository) Create(ctx context.Context, token *models.APIToken) error {
+	token.Created = time.Now()
+	return r.collection.InsertOne(ctx, token)
+}
+
+// FindByHash 依 Token 雜湊值查詢
+func (r *MongoDBAPITokenRepository) FindByHash(ctx context.Context, tokenHash string) (*models.APIToken, error) {
+	var token models.APIToken
+	err := r.collection.FindOne(ctx, database.Filter{"token_hash": tokenHash}, &token)
+	if database.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// ListByUser 列出使用者的 API Token（依建立時間由新到舊）
+func (r *MongoDBAPITokenRepository) ListByUser(ctx context.Context, userID string) ([]models.APIToken, error) {
+	tokens := []models.APIToken{}
+	sort := database.Sort{{Field: "created", Order: -1}}
+	if err := r.collection.Find(ctx, database.Filter{"user_id": userID}, sort, 0, 0, &tokens); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// Delete 刪除使用者的 API Token
+func (r *MongoDBAPITokenRepository) Delete(ctx context.Context, id, userID string) error {
+	filter := database.Filter{"_id": id, "user_id": userID}
+	var token models.APIToken
+	if err := r.collection.FindOne(ctx, filter, &token); err != nil {
+		return err
+	}
+	return r.collection.DeleteOne(ctx, filter)
+}
+
+// Touch 更新最後使用時間
+func (r *MongoDBAPITokenRepository) Touch(ctx context.Context, id string, lastUsed time.Time) error {
+	return r.collection.UpdateOne(ctx, database.Filter{"_id": id}, database.Update{
+		Set: map[string]interface{}{"last_used": lastUsed},
+	})
+}
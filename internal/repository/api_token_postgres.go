@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/models"
+)
+
+// PostgresAPITokenRepository PostgreSQL 個人 API Token Repository
+// scopes 與 SQLite 相同，以空白分隔的字串儲存
+type PostgresAPITokenRepository struct {
+	db database.Database
+}
+
+// NewPostgresAPITokenRepository 建立 PostgreSQL 個人 API Token Repository
+func NewPostgresAPITokenRepository(db database.Database) *PostgresAPITokenRepository {
+	return &PostgresAPITokenRepository{db: db}
+}
+
+// getDB 取得 SQL 資料庫連線（使用 context 中的交易）
+func (r *PostgresAPITokenRepository) getDB() *database.SQLConn {
+	return r.db.(*database.PostgresDatabase).GetConn()
+}
+
+// Create 建立 API Token
+func (r *PostgresAPITokenRepository) Create(ctx context.Context, token *models.APIToken) error {
+	token.Created = time.Now()
+	query := `INSERT INTO api_tokens (` + apiTokenSelectColumns + `) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+	_, err := r.getDB().ExecContext(ctx, query,
+		token.ID,
+		token.UserID,
+		token.Name,
+		token.Prefix,
+		token.TokenHash,
+		strings.Join(token.Scopes, " "),
+		token.ExpiresAt,
+		token.LastUsed,
+		token.Created,
+	)
+	return err
+}
+
+// FindByHash 依 Token 雜湊值查詢
+func (r *PostgresAPITokenRepository) FindByHash(ctx context.Context, tokenHash string) (*models.APIToken, error) {
+	row := r.getDB().QueryRowContext(ctx, `SELECT `+apiTokenSelectColumns+` FROM api_tokens WHERE token_hash = $1`, tokenHash)
+	token, err := scanAPIToken(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return token, err
+}
+
+// ListByUser 列出使用者的 API Token（依建立時間由新到舊）
+func (r *PostgresAPITokenRepository) ListByUser(ctx context.Context, userID string) ([]models.APIToken, error) {
+	rows, err := r.getDB().QueryContext(ctx,
+		`SELECT `+apiTokenSelectColumns+` FROM api_tokens WHERE user_id = $1 ORDER BY created DESC`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	tokens := []models.APIToken{}
+	for rows.Next() {
+		token, err := scanAPIToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, *token)
+	}
+	return tokens, rows.Err()
+}
+
+// Delete 刪除使用者的 API Token
+func (r *PostgresAPITokenRepository) Delete(ctx context.Context, id, userID string) error {
+	result, err := r.getDB().ExecContext(ctx, `DELETE FROM api_tokens WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return database.ErrNoDocuments
+	}
+	return nil
+}
+
+// Touch 更新最後使用時間
+func (r *PostgresAPITokenRepository) Touch(ctx context.Context, id string, lastUsed time.Time) error {
+	_, err := r.getDB().ExecContext(ctx, `UPDATE api_tokens SET last_used = $1 WHERE id = $2`, lastUsed, id)
+	return err
+}
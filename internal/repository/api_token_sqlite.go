@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/models"
+)
+
+// SQLiteAPITokenRepository SQLite 個人 API Token Repository
+// scopes 以空白分隔的字串儲存
+type SQLiteAPITokenRepository struct {
+	db database.Database
+}
+
+// NewSQLiteAPITokenRepository 建立 SQLite 個人 API Token Repository
+func NewSQLiteAPITokenRepository(db database.Database) *SQLiteAPITokenRepository {
+	return &SQLiteAPITokenRepository{db: db}
+}
+
+// getDB 取得 SQL 資料庫連線（使用 context 中的交易）
+func (r *SQLiteAPITokenRepository) getDB() *database.SQLConn {
+	return r.db.(*database.SQLiteDatabase).GetConn()
+}
+
+const apiTokenSelectColumns = `id, user_id, name, prefix, token_hash, scopes, expires_at, last_used, created`
+
+// scanAPIToken 讀取一筆 API Token
+func scanAPIToken(row rowScanner) (*models.APIToken, error) {
+	var token models.APIToken
+	var scopes string
+	var expiresAt, lastUsed sql.NullTime
+	if err := row.Scan(
+		&token.ID,
+		&token.UserID,
+		&token.Name,
+		&token.Prefix,
+		&token.TokenHash,
+		&scopes,
+		&expiresAt,
+		&lastUsed,
+		&token.Created,
+	); err != nil {
+		return nil, err
+	}
+	token.Scopes = strings.Fields(scopes)
+	if expiresAt.Valid {
+		token.ExpiresAt = &expiresAt.Time
+	}
+	if lastUsed.Valid {
+		token.LastUsed = &lastUsed.Time
+	}
+	return &token, nil
+}
+
+// Create 建立 API Token
+func (r *SQLiteAPITokenRepository) Create(ctx context.Context, token *models.APIToken) error {
+	token.Created = time.Now()
+	query := `INSERT INTO api_tokens (` + apiTokenSelectColumns + `) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.getDB().ExecContext(ctx, query,
+		token.ID,
+		token.UserID,
+		token.Name,
+		token.Prefix,
+		token.TokenHash,
+		strings.Join(token.Scopes, " "),
+		token.ExpiresAt,
+		token.LastUsed,
+		token.Created,
+	)
+	return err
+}
+
+// FindByHash 依 Token 雜湊值查詢
+func (r *SQLiteAPITokenRepository) FindByHash(ctx context.Context, tokenHash string) (*models.APIToken, error) {
+	row := r.getDB().QueryRowContext(ctx, `SELECT `+apiTokenSelectColumns+` FROM api_tokens WHERE token_hash = ?`, tokenHash)
+	token, err := scanAPIToken(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return token, err
+}
+
+// ListByUser 列出使用者的 API Token（依建立時間由新到舊）
+func (r *SQLiteAPITokenRepository) ListByUser(ctx context.Context, userID string) ([]models.APIToken, error) {
+	rows, err := r.getDB().QueryContext(ctx,
+		`SELECT `+apiTokenSelectColumns+` FROM api_tokens WHERE user_id = ? ORDER BY created DESC`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	tokens := []models.APIToken{}
+	for rows.Next() {
+		token, err := scanAPIToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, *token)
+	}
+	return tokens, rows.Err()
+}
+
+// Delete 刪除使用者的 API Token
+func (r *SQLiteAPITokenRepository) Delete(ctx context.Context, id, userID string) error {
+	result, err := r.getDB().ExecContext(ctx, `DELETE FROM api_tokens WHERE id = ? AND user_id = ?`, id, userID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return database.ErrNoDocuments
+	}
+	return nil
+}
+
+// Touch 更新最後使用時間
+func (r *SQLiteAPITokenRepository) Touch(ctx context.Context, id string, lastUsed time.Time) error {
+	_, err := r.getDB().ExecContext(ctx, `UPDATE api_tokens SET last_used = ? WHERE id = ?`, lastUsed, id)
+	return err
+}
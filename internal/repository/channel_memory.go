@@ -0,0 +1,469 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/models"
+)
+
+// MemoryChannelRepository 記憶體頻道 Repository
+type MemoryChannelRepository struct {
+	db database.Database
+}
+
+// NewMemoryChannelRepository 建立記憶體頻道 Repository
+func NewMemoryChannelRepository(db database.Database) *MemoryChannelRepository {
+	return &MemoryChannelRepository{db: db}
+}
+
+// getStore 取得記憶體資料
+func (r *MemoryChannelRepository) getStore() *database.MemoryStore {
+	return r.db.(*database.MemoryDatabase).GetStore()
+}
+
+// FindByID 依 ID 查詢頻道（不含垃圾桶中的頻道）
+func (r *MemoryChannelRepository) FindByID(ctx context.Context, id string) (*models.Channel, error) {
+	return r.find(id, false), nil
+}
+
+// FindDeleted 依 ID 查詢垃圾桶中的頻道
+func (r *MemoryChannelRepository) FindDeleted(ctx context.Context, id string) (*models.Channel, error) {
+	return r.find(id, true), nil
+}
+
+// find 依 ID 與是否在垃圾桶中查詢頻道（找不到時回傳 nil）
+func (r *MemoryChannelRepository) find(id string, deleted bool) *models.Channel {
+	store := r.getStore()
+	store.Lock()
+	defer store.Unlock()
+
+	channel, ok := store.Channels[id]
+	if !ok || (channel.DeletedAt != nil) != deleted {
+		return nil
+	}
+	return cloneChannel(channel)
+}
+
+// Create 建立頻道（ID 重複時回傳錯誤）
+func (r *MemoryChannelRepository) Create(ctx context.Context, channel *models.Channel) error {
+	now := time.Now()
+	channel.Created = now
+	channel.LastModified = now
+
+	store := r.getStore()
+	store.Lock()
+	defer store.Unlock()
+
+	if _, ok := store.Channels[channel.ID]; ok {
+		return fmt.Errorf("duplicate channel: %s", channel.ID)
+	}
+	store.Channels[channel.ID] = cloneChannel(channel)
+	return nil
+}
+
+// modify 修改頻道（頻道不存在時回傳 ErrNoDocuments）
+func (r *MemoryChannelRepository) modify(id string, fn func(store *database.MemoryStore, channel *models.Channel) error) error {
+	return modifyMemoryChannel(r.getStore(), id, fn)
+}
+
+// modifyMemoryChannel 在持有鎖時修改頻道（頻道不存在時回傳 ErrNoDocuments）
+func modifyMemoryChannel(store *database.MemoryStore, id string, fn func(store *database.MemoryStore, channel *models.Channel) error) error {
+	store.Lock()
+	defer store.Unlock()
+
+	channel, ok := store.Channels[id]
+	if !ok {
+		return database.ErrNoDocuments
+	}
+	return fn(store, channel)
+}
+
+// touchMemoryChannel 更新頻道的 last_modified 並遞增修訂版本（已在目前交易中遞增時不再遞增）
+func touchMemoryChannel(ctx context.Context, channel *models.Channel) {
+	channel.LastModified = time.Now()
+	channel.Revision += database.RevisionIncrement(ctx, channel.ID)
+}
+
+// ignoreNotFound 忽略 ErrNoDocuments（對應 SQL UPDATE 沒有符合的資料列時不回傳錯誤）
+func ignoreNotFound(err error) error {
+	if database.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// Update 更新頻道
+func (r *MemoryChannelRepository) Update(ctx context.Context, id string, update map[string]interface{}) error {
+	if len(update) == 0 {
+		return nil
+	}
+
+	return ignoreNotFound(r.modify(id, func(_ *database.MemoryStore, channel *models.Channel) error {
+		// 先套用到複本，任一欄位失敗時不修改頻道
+		updated := cloneChannel(channel)
+		for key, value := range update {
+			if err := setChannelField(updated, key, value); err != nil {
+				return err
+			}
+		}
+		touchMemoryChannel(ctx, updated)
+		*channel = *updated
+		return nil
+	}))
+}
+
+// setChannelField 設定頻道欄位（key 為 MongoDB 欄位名稱）
+func setChannelField(channel *models.Channel, key string, value interface{}) error {
+	switch key {
+	case "name":
+		return assignValue(&channel.Name, value)
+	case "desc":
+		return assignValue(&channel.Desc, value)
+	case "type":
+		return assignValue(&channel.Type, value)
+	case "tags":
+		if err := assignValue(&channel.Tags, value); err != nil {
+			return err
+		}
+		channel.Tags = append([]int(nil), channel.Tags...)
+	case "visibility":
+		return assignValue(&channel.Visibility, value)
+	case "hide_unavailable":
+		return assignValue(&channel.HideUnavailable, value)
+	case "contents_seq":
+		return assignValue(&channel.ContentsSeq, value)
+	case "forked_from":
+		return assignValue(&channel.ForkedFrom, value)
+	case "last_modified":
+		return assignValue(&channel.LastModified, value)
+	case "cover.default":
+		var cover string
+		if err := assignValue(&cover, value); err != nil {
+			return err
+		}
+		channel.Cover = &models.ChannelCover{Default: cover}
+	case "cover":
+		cover, ok := value.(*models.ChannelCover)
+		if !ok && value != nil {
+			return fmt.Errorf("invalid value for channel cover: %v", value)
+		}
+		if cover != nil {
+			clone := *cover
+			cover = &clone
+		}
+		channel.Cover = cover
+	case "schedule":
+		schedule, ok := value.(*models.ChannelSchedule)
+		if !ok && value != nil {
+			return fmt.Errorf("invalid value for channel schedule: %v", value)
+		}
+		if schedule != nil {
+			clone := *schedule
+			schedule = &clone
+		}
+		channel.Schedule = schedule
+	default:
+		return fmt.Errorf("unsupported channel field: %s", key)
+	}
+	return nil
+}
+
+// assignValue 將 value 指定給 target（允許底層類型相同的具名類型，例如 string 與 ChannelVisibility）
+func assignValue(target interface{}, value interface{}) error {
+	targetValue := reflect.ValueOf(target).Elem()
+	if value == nil {
+		targetValue.Set(reflect.Zero(targetValue.Type()))
+		return nil
+	}
+	v := reflect.ValueOf(value)
+	if !v.Type().ConvertibleTo(targetValue.Type()) || v.Kind() != targetValue.Kind() {
+		return fmt.Errorf("cannot assign %T to %s", value, targetValue.Type())
+	}
+	targetValue.Set(v.Convert(targetValue.Type()))
+	return nil
+}
+
+// ListChannels 列出頻道（支援過濾和排序，過濾條件與 MongoDB 相同）
+func (r *MemoryChannelRepository) ListChannels(ctx context.Context, filter database.Filter, sort database.Sort, limit, skip int64) ([]models.Channel, error) {
+	// 不含垃圾桶中的頻道（複製一份，避免修改呼叫端的過濾條件）
+	active := database.Filter{"deleted_at": nil}
+	for key, value := range filter {
+		active[key] = value
+	}
+
+	channels, err := database.FilterDocuments(r.snapshot(), active, sort)
+	if err != nil {
+		return nil, err
+	}
+	return paginate(channels, limit, skip), nil
+}
+
+// snapshot 複製所有頻道
+func (r *MemoryChannelRepository) snapshot() []models.Channel {
+	store := r.getStore()
+	store.Lock()
+	defer store.Unlock()
+
+	channels := make([]models.Channel, 0, len(store.Channels))
+	for _, channel := range store.Channels {
+		channels = append(channels, *cloneChannel(channel))
+	}
+	// map 沒有固定順序，先依 ID 排序讓未指定排序時的結果穩定
+	sort.Slice(channels, func(i, j int) bool { return channels[i].ID < channels[j].ID })
+	return channels
+}
+
+// paginate 套用 skip 與 limit（limit 為 0 表示不限制）
+func paginate[T any](items []T, limit, skip int64) []T {
+	if skip > 0 {
+		if skip >= int64(len(items)) {
+			return items[:0]
+		}
+		items = items[skip:]
+	}
+	if limit > 0 && limit < int64(len(items)) {
+		items = items[:limit]
+	}
+	return items
+}
+
+// LoadContents 批次載入節目與節目順序（ListChannels 已包含節目，不需載入）
+func (r *MemoryChannelRepository) LoadContents(ctx context.Context, channels []models.Channel) error {
+	return nil
+}
+
+// IsAdmin 檢查使用者是否為頻道管理員
+func (r *MemoryChannelRepository) IsAdmin(ctx context.Context, channelID, userID string) (bool, error) {
+	channel := r.find(channelID, false)
+	if channel == nil {
+		return false, nil
+	}
+	return channel.PermissionFor(userID).Admin, nil
+}
+
+// GetPermission 取得使用者對頻道的權限（擁有者具有所有權限）
+func (r *MemoryChannelRepository) GetPermission(ctx context.Context, channelID, userID string) (models.ChannelPermission, error) {
+	channel := r.find(channelID, false)
+	if channel == nil {
+		return models.ChannelPermission{UserID: userID}, nil
+	}
+	return channel.PermissionFor(userID), nil
+}
+
+// SetPermission 設定使用者對頻道的權限（所有權限皆為 false 時移除）
+func (r *MemoryChannelRepository) SetPermission(ctx context.Context, channelID string, permission models.ChannelPermission) error {
+	return ignoreNotFound(r.modify(channelID, func(_ *database.MemoryStore, channel *models.Channel) error {
+		permissions := []models.ChannelPermission{}
+		for _, existing := range channel.Permission {
+			if existing.UserID != permission.UserID {
+				permissions = append(permissions, existing)
+			}
+		}
+		if permission.Admin || permission.Read || permission.Write {
+			permissions = append(permissions, permission)
+		}
+		channel.Permission = permissions
+		touchMemoryChannel(ctx, channel)
+		return nil
+	}))
+}
+
+// AddOwners 新增擁有者（同時更新使用者的 own_channels）
+func (r *MemoryChannelRepository) AddOwners(ctx context.Context, channelID string, userIDs []string) error {
+	return ignoreNotFound(r.modify(channelID, func(store *database.MemoryStore, channel *models.Channel) error {
+		for _, userID := range userIDs {
+			addMemoryOwner(store, channel, userID)
+		}
+		touchMemoryChannel(ctx, channel)
+		return nil
+	}))
+}
+
+// RemoveOwner 移除擁有者（同時更新使用者的 own_channels），不能移除最後一位擁有者
+func (r *MemoryChannelRepository) RemoveOwner(ctx context.Context, channelID, userID string) error {
+	return r.modify(channelID, func(store *database.MemoryStore, channel *models.Channel) error {
+		if err := removeMemoryOwner(store, channel, userID); err != nil {
+			return err
+		}
+		touchMemoryChannel(ctx, channel)
+		return nil
+	})
+}
+
+// TransferOwnership 將擁有權從 fromUserID 移轉給 toUserID
+func (r *MemoryChannelRepository) TransferOwnership(ctx context.Context, channelID, fromUserID, toUserID string) error {
+	return r.modify(channelID, func(store *database.MemoryStore, channel *models.Channel) error {
+		if !channel.IsOwner(fromUserID) {
+			return database.ErrNoDocuments
+		}
+		// 先加入新的擁有者，移除原擁有者時才不會變成沒有擁有者
+		addMemoryOwner(store, channel, toUserID)
+		if err := removeMemoryOwner(store, channel, fromUserID); err != nil {
+			return err
+		}
+		touchMemoryChannel(ctx, channel)
+		return nil
+	})
+}
+
+// addMemoryOwner 新增擁有者與使用者的 own_channels
+func addMemoryOwner(store *database.MemoryStore, channel *models.Channel, userID string) {
+	channel.Owners = addString(channel.Owners, userID)
+	if user, ok := store.Users[userID]; ok {
+		user.OwnChannels = addString(user.OwnChannels, channel.ID)
+	}
+}
+
+// removeMemoryOwner 移除擁有者與使用者的 own_channels（不是擁有者時回傳 ErrNoDocuments）
+func removeMemoryOwner(store *database.MemoryStore, channel *models.Channel, userID string) error {
+	if !channel.IsOwner(userID) {
+		return database.ErrNoDocuments
+	}
+	if len(channel.Owners) <= 1 {
+		return database.ErrLastOwner
+	}
+	channel.Owners = removeString(channel.Owners, userID)
+	if user, ok := store.Users[userID]; ok {
+		user.OwnChannels = removeString(user.OwnChannels, channel.ID)
+	}
+	return nil
+}
+
+// GetRevision 取得頻道目前的修訂版本
+func (r *MemoryChannelRepository) GetRevision(ctx context.Context, channelID string) (int64, error) {
+	var revision int64
+	err := r.modify(channelID, func(_ *database.MemoryStore, channel *models.Channel) error {
+		revision = channel.Revision
+		return nil
+	})
+	return revision, err
+}
+
+// ClaimRevision 比對並遞增頻道修訂版本（不符或頻道在垃圾桶中時回傳 ErrRevisionMismatch），確保同時修改時只有一方成功
+// 需與修改頻道的操作在同一個交易中呼叫，並以 database.WithClaimedRevision 標示後續修改不再遞增
+func (r *MemoryChannelRepository) ClaimRevision(ctx context.Context, channelID string, revision int64) error {
+	err := r.modify(channelID, func(_ *database.MemoryStore, channel *models.Channel) error {
+		if channel.Revision != revision || channel.DeletedAt != nil {
+			return database.ErrRevisionMismatch
+		}
+		channel.Revision++
+		return nil
+	})
+	if database.IsNotFound(err) {
+		return database.ErrRevisionMismatch
+	}
+	return err
+}
+
+// IncrementForkCount 遞增頻道被複製的次數（不視為修改頻道，不遞增修訂版本）
+func (r *MemoryChannelRepository) IncrementForkCount(ctx context.Context, channelID string) error {
+	return ignoreNotFound(r.modify(channelID, func(_ *database.MemoryStore, channel *models.Channel) error {
+		channel.ForkCount++
+		return nil
+	}))
+}
+
+// SoftDelete 將頻道移到垃圾桶
+func (r *MemoryChannelRepository) SoftDelete(ctx context.Context, id string, deletedAt time.Time) error {
+	return r.modify(id, func(_ *database.MemoryStore, channel *models.Channel) error {
+		if channel.DeletedAt != nil {
+			return database.ErrNoDocuments
+		}
+		deletedAt := deletedAt.UTC()
+		channel.DeletedAt = &deletedAt
+		channel.Revision += database.RevisionIncrement(ctx, id)
+		return nil
+	})
+}
+
+// Restore 將頻道從垃圾桶還原
+func (r *MemoryChannelRepository) Restore(ctx context.Context, id string) error {
+	return r.modify(id, func(_ *database.MemoryStore, channel *models.Channel) error {
+		if channel.DeletedAt == nil {
+			return database.ErrNoDocuments
+		}
+		channel.DeletedAt = nil
+		touchMemoryChannel(ctx, channel)
+		return nil
+	})
+}
+
+// ListDeleted 列出使用者擁有且在垃圾桶中的頻道（依刪除時間由新到舊）
+func (r *MemoryChannelRepository) ListDeleted(ctx context.Context, userID string) ([]models.Channel, error) {
+	return database.FilterDocuments(r.snapshot(), database.Filter{
+		"owners":     userID,
+		"deleted_at": database.Filter{"$ne": nil},
+	}, database.Sort{{Field: "deleted_at", Order: -1}})
+}
+
+// ListExpired 列出在 before 之前移到垃圾桶的頻道 ID
+func (r *MemoryChannelRepository) ListExpired(ctx context.Context, before time.Time, limit int64) ([]string, error) {
+	channels, err := database.FilterDocuments(r.snapshot(), database.Filter{
+		"deleted_at": database.Filter{"$ne": nil, "$lte": before},
+	}, database.Sort{{Field: "deleted_at", Order: 1}})
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []string{}
+	for _, channel := range paginate(channels, limit, 0) {
+		ids = append(ids, channel.ID)
+	}
+	return ids, nil
+}
+
+// Purge 永久刪除頻道（節目內嵌在頻道中一併刪除），並從擁有者的 own_channels 移除
+func (r *MemoryChannelRepository) Purge(ctx context.Context, id string) error {
+	return ignoreNotFound(r.modify(id, func(store *database.MemoryStore, channel *models.Channel) error {
+		for _, owner := range channel.Owners {
+			if user, ok := store.Users[owner]; ok {
+				user.OwnChannels = removeString(user.OwnChannels, id)
+			}
+		}
+		delete(store.Channels, id)
+		return nil
+	}))
+}
+
+// cloneChannel 複製頻道（含節目，避免呼叫端修改記憶體中的資料）
+func cloneChannel(channel *models.Channel) *models.Channel {
+	clone := *channel
+	clone.Tags = cloneInts(channel.Tags)
+	clone.Owners = cloneStrings(channel.Owners)
+	clone.ContentsOrder = cloneInts(channel.ContentsOrder)
+	if channel.Permission != nil {
+		clone.Permission = append([]models.ChannelPermission{}, channel.Permission...)
+	}
+	if channel.Cover != nil {
+		cover := *channel.Cover
+		clone.Cover = &cover
+	}
+	if channel.Schedule != nil {
+		schedule := *channel.Schedule
+		clone.Schedule = &schedule
+	}
+	if channel.DeletedAt != nil {
+		deletedAt := *channel.DeletedAt
+		clone.DeletedAt = &deletedAt
+	}
+	if channel.Contents != nil {
+		clone.Contents = make([]models.Program, len(channel.Contents))
+		for i := range channel.Contents {
+			clone.Contents[i] = *cloneProgram(&channel.Contents[i])
+		}
+	}
+	return &clone
+}
+
+// cloneInts 複製整數切片（nil 仍為 nil）
+func cloneInts(values []int) []int {
+	if values == nil {
+		return nil
+	}
+	return append([]int{}, values...)
+}
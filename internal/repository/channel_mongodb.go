@@ -10,20 +10,38 @@ import (
 
 // MongoDBChannelRepository MongoDB 頻道 Repository
 type MongoDBChannelRepository struct {
+	db         database.Database
 	collection database.Collection
+	users      database.Collection
+	shares     database.Collection
+	revisions  database.Collection
 }
 
 // NewMongoDBChannelRepository 建立 MongoDB 頻道 Repository
 func NewMongoDBChannelRepository(db database.Database) *MongoDBChannelRepository {
 	return &MongoDBChannelRepository{
+		db:         db,
 		collection: db.Collection("channels"),
+		users:      db.Collection("users"),
+		shares:     db.Collection("channel_shares"),
+		revisions:  db.Collection("channel_revisions"),
 	}
 }
 
-// FindByID 依 ID 查詢頻道
+// FindByID 依 ID 查詢頻道（不含垃圾桶中的頻道）
 func (r *MongoDBChannelRepository) FindByID(ctx context.Context, id string) (*models.Channel, error) {
+	return r.find(ctx, database.Filter{"_id": id, "deleted_at": nil})
+}
+
+// FindDeleted 依 ID 查詢垃圾桶中的頻道
+func (r *MongoDBChannelRepository) FindDeleted(ctx context.Context, id string) (*models.Channel, error) {
+	return r.find(ctx, database.Filter{"_id": id, "deleted_at": database.Filter{"$ne": nil}})
+}
+
+// find 查詢單一頻道（找不到時回傳 nil）
+func (r *MongoDBChannelRepository) find(ctx context.Context, filter database.Filter) (*models.Channel, error) {
 	var channel models.Channel
-	err := r.collection.FindOne(ctx, database.Filter{"_id": id}, &channel)
+	err := r.collection.FindOne(ctx, filter, &channel)
 	if database.IsNotFound(err) {
 		return nil, nil
 	}
@@ -45,21 +63,34 @@ func (r *MongoDBChannelRepository) Update(ctx context.Context, id string, update
 	update["last_modified"] = time.Now()
 	return r.collection.UpdateOne(ctx, database.Filter{"_id": id}, database.Update{
 		Set: update,
+		Inc: revisionInc(ctx, id),
 	})
 }
 
 // ListChannels 列出頻道（支援過濾和排序）
 func (r *MongoDBChannelRepository) ListChannels(ctx context.Context, filter database.Filter, sort database.Sort, limit, skip int64) ([]models.Channel, error) {
+	// 不含垃圾桶中的頻道（複製一份，避免修改呼叫端的過濾條件）
+	active := database.Filter{"deleted_at": nil}
+	for key, value := range filter {
+		active[key] = value
+	}
+
 	var channels []models.Channel
-	err := r.collection.Find(ctx, filter, sort, limit, skip, &channels)
+	err := r.collection.Find(ctx, active, sort, limit, skip, &channels)
 	return channels, err
 }
 
+// LoadContents 批次載入節目與節目順序（節目內嵌於頻道文件，ListChannels 已包含）
+func (r *MongoDBChannelRepository) LoadContents(ctx context.Context, channels []models.Channel) error {
+	return nil
+}
+
 // IsAdmin 檢查使用者是否為頻道管理員
 func (r *MongoDBChannelRepository) IsAdmin(ctx context.Context, channelID, userID string) (bool, error) {
 	var channel models.Channel
 	err := r.collection.FindOne(ctx, database.Filter{
-		"_id": channelID,
+		"_id":        channelID,
+		"deleted_at": nil,
 		"$or": []database.Filter{
 			{"owners": userID},
 			{"permission.user_id": userID, "permission.admin": true},
@@ -74,15 +105,280 @@ func (r *MongoDBChannelRepository) IsAdmin(ctx context.Context, channelID, userI
 	return true, nil
 }
 
-// AddOwners 新增擁有者
+// GetPermission 取得使用者對頻道的權限（擁有者具有所有權限）
+func (r *MongoDBChannelRepository) GetPermission(ctx context.Context, channelID, userID string) (models.ChannelPermission, error) {
+	var channel models.Channel
+	err := r.collection.FindOne(ctx, database.Filter{"_id": channelID, "deleted_at": nil}, &channel)
+	if database.IsNotFound(err) {
+		return models.ChannelPermission{UserID: userID}, nil
+	}
+	if err != nil {
+		return models.ChannelPermission{UserID: userID}, err
+	}
+	return channel.PermissionFor(userID), nil
+}
+
+// SetPermission 設定使用者對頻道的權限（所有權限皆為 false 時移除）
+func (r *MongoDBChannelRepository) SetPermission(ctx context.Context, channelID string, permission models.ChannelPermission) error {
+	filter := database.Filter{"_id": channelID}
+	// 同一個欄位不能同時 $pull 與 $push，先移除舊的權限再加入新的
+	if err := r.collection.UpdateOne(ctx, filter, database.Update{
+		Pull: map[string]interface{}{
+			"permission": map[string]interface{}{"user_id": permission.UserID},
+		},
+		Set: map[string]interface{}{
+			"last_modified": time.Now(),
+		},
+		Inc: revisionInc(ctx, channelID),
+	}); err != nil {
+		return err
+	}
+	if !permission.Admin && !permission.Read && !permission.Write {
+		return nil
+	}
+	return r.collection.UpdateOne(ctx, filter, database.Update{
+		Push: map[string]interface{}{
+			"permission": permission,
+		},
+	})
+}
+
+// AddOwners 新增擁有者（同時更新使用者的 own_channels）
 func (r *MongoDBChannelRepository) AddOwners(ctx context.Context, channelID string, userIDs []string) error {
-	return r.collection.UpdateOne(ctx, database.Filter{"_id": channelID}, database.Update{
+	return r.db.WithTx(ctx, func(ctx context.Context) error {
+		if err := r.collection.UpdateOne(ctx, database.Filter{"_id": channelID}, database.Update{
+			AddToSet: map[string]interface{}{
+				"owners": map[string]interface{}{"$each": userIDs},
+			},
+			Set: map[string]interface{}{
+				"last_modified": time.Now(),
+			},
+			Inc: revisionInc(ctx, channelID),
+		}); err != nil {
+			return err
+		}
+		for _, userID := range userIDs {
+			if err := r.addOwnChannel(ctx, userID, channelID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// RemoveOwner 移除擁有者（同時更新使用者的 own_channels），不能移除最後一位擁有者
+func (r *MongoDBChannelRepository) RemoveOwner(ctx context.Context, channelID, userID string) error {
+	return r.db.WithTx(ctx, func(ctx context.Context) error {
+		return r.removeOwner(ctx, channelID, userID)
+	})
+}
+
+// TransferOwnership 將擁有權從 fromUserID 移轉給 toUserID
+func (r *MongoDBChannelRepository) TransferOwnership(ctx context.Context, channelID, fromUserID, toUserID string) error {
+	return r.db.WithTx(ctx, func(ctx context.Context) error {
+		// 先確認原擁有者，避免把擁有權加給新的使用者後才發現無法移轉
+		count, err := r.collection.CountDocuments(ctx, database.Filter{"_id": channelID, "owners": fromUserID})
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return database.ErrNoDocuments
+		}
+
+		// 同一個欄位不能同時 $addToSet 與 $pull，先加入新的擁有者再移除原擁有者
+		if err := r.collection.UpdateOne(ctx, database.Filter{"_id": channelID}, database.Update{
+			AddToSet: map[string]interface{}{
+				"owners": toUserID,
+			},
+		}); err != nil {
+			return err
+		}
+		if err := r.addOwnChannel(ctx, toUserID, channelID); err != nil {
+			return err
+		}
+		return r.removeOwner(ctx, channelID, fromUserID)
+	})
+}
+
+// removeOwner 移除擁有者與使用者的 own_channels（不是擁有者時回傳 ErrNoDocuments）
+func (r *MongoDBChannelRepository) removeOwner(ctx context.Context, channelID, userID string) error {
+	// owners.1 存在表示至少還有兩位擁有者
+	matched, err := r.collection.UpdateOneMatched(ctx, database.Filter{
+		"_id":      channelID,
+		"owners":   userID,
+		"owners.1": database.Filter{"$exists": true},
+	}, database.Update{
+		Pull: map[string]interface{}{
+			"owners": userID,
+		},
+		Set: map[string]interface{}{
+			"last_modified": time.Now(),
+		},
+		Inc: revisionInc(ctx, channelID),
+	})
+	if err != nil {
+		return err
+	}
+	if !matched {
+		count, err := r.collection.CountDocuments(ctx, database.Filter{"_id": channelID, "owners": userID})
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return database.ErrNoDocuments
+		}
+		return database.ErrLastOwner
+	}
+
+	return r.users.UpdateOne(ctx, database.Filter{"_id": userID}, database.Update{
+		Pull: map[string]interface{}{
+			"own_channels": channelID,
+		},
+	})
+}
+
+// addOwnChannel 將頻道加入使用者的 own_channels
+func (r *MongoDBChannelRepository) addOwnChannel(ctx context.Context, userID, channelID string) error {
+	return r.users.UpdateOne(ctx, database.Filter{"_id": userID}, database.Update{
 		AddToSet: map[string]interface{}{
-			"owners": map[string]interface{}{"$each": userIDs},
+			"own_channels": channelID,
 		},
+	})
+}
+
+// GetRevision 取得頻道目前的修訂版本
+func (r *MongoDBChannelRepository) GetRevision(ctx context.Context, channelID string) (int64, error) {
+	var channel struct {
+		Revision int64 `bson:"revision"`
+	}
+	if err := r.collection.FindOne(ctx, database.Filter{"_id": channelID}, &channel); err != nil {
+		return 0, err
+	}
+	return channel.Revision, nil
+}
+
+// ClaimRevision 比對並遞增頻道修訂版本（不符或頻道在垃圾桶中時回傳 ErrRevisionMismatch），確保同時修改時只有一方成功
+// 需與修改頻道的操作在同一個交易中呼叫，並以 database.WithClaimedRevision 標示後續修改不再遞增
+func (r *MongoDBChannelRepository) ClaimRevision(ctx context.Context, channelID string, revision int64) error {
+	filter := database.Filter{"_id": channelID, "revision": revision, "deleted_at": nil}
+	if revision == 0 {
+		// 加入修訂版本前建立的頻道沒有 revision 欄位
+		filter["revision"] = database.Filter{"$in": []interface{}{int64(0), nil}}
+	}
+	matched, err := r.collection.UpdateOneMatched(ctx, filter, database.Update{
+		Inc: revisionInc(ctx, channelID),
+	})
+	if err != nil {
+		return err
+	}
+	if !matched {
+		return database.ErrRevisionMismatch
+	}
+	return nil
+}
+
+// revisionInc 遞增頻道修訂版本的 $inc 操作（已在目前交易中遞增時不再遞增）
+func revisionInc(ctx context.Context, channelID string) map[string]interface{} {
+	return map[string]interface{}{"revision": database.RevisionIncrement(ctx, channelID)}
+}
+
+// IncrementForkCount 遞增頻道被複製的次數（不視為修改頻道，不遞增修訂版本）
+func (r *MongoDBChannelRepository) IncrementForkCount(ctx context.Context, channelID string) error {
+	return r.collection.UpdateOne(ctx, database.Filter{"_id": channelID}, database.Update{
+		Inc: map[string]interface{}{"fork_count": 1},
+	})
+}
+
+// SoftDelete 將頻道移到垃圾桶
+func (r *MongoDBChannelRepository) SoftDelete(ctx context.Context, id string, deletedAt time.Time) error {
+	matched, err := r.collection.UpdateOneMatched(ctx, database.Filter{"_id": id, "deleted_at": nil}, database.Update{
 		Set: map[string]interface{}{
+			"deleted_at": deletedAt,
+		},
+		Inc: revisionInc(ctx, id),
+	})
+	if err != nil {
+		return err
+	}
+	if !matched {
+		return database.ErrNoDocuments
+	}
+	return nil
+}
+
+// Restore 將頻道從垃圾桶還原
+func (r *MongoDBChannelRepository) Restore(ctx context.Context, id string) error {
+	matched, err := r.collection.UpdateOneMatched(ctx, database.Filter{"_id": id, "deleted_at": database.Filter{"$ne": nil}}, database.Update{
+		Set: map[string]interface{}{
+			"deleted_at":    nil,
 			"last_modified": time.Now(),
 		},
+		Inc: revisionInc(ctx, id),
 	})
+	if err != nil {
+		return err
+	}
+	if !matched {
+		return database.ErrNoDocuments
+	}
+	return nil
+}
+
+// ListDeleted 列出使用者擁有且在垃圾桶中的頻道（依刪除時間由新到舊）
+func (r *MongoDBChannelRepository) ListDeleted(ctx context.Context, userID string) ([]models.Channel, error) {
+	channels := []models.Channel{}
+	err := r.collection.Find(ctx, database.Filter{
+		"owners":     userID,
+		"deleted_at": database.Filter{"$ne": nil},
+	}, database.Sort{{Field: "deleted_at", Order: -1}}, 0, 0, &channels)
+	return channels, err
 }
 
+// ListExpired 列出在 before 之前移到垃圾桶的頻道 ID
+func (r *MongoDBChannelRepository) ListExpired(ctx context.Context, before time.Time, limit int64) ([]string, error) {
+	var channels []models.Channel
+	err := r.collection.Find(ctx, database.Filter{
+		"deleted_at": database.Filter{"$ne": nil, "$lte": before},
+	}, database.Sort{{Field: "deleted_at", Order: 1}}, limit, 0, &channels)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(channels))
+	for _, channel := range channels {
+		ids = append(ids, channel.ID)
+	}
+	return ids, nil
+}
+
+// Purge 永久刪除頻道（節目內嵌在頻道中一併刪除）與分享邀請，並從擁有者的 own_channels 移除（在同一個交易中完成）
+func (r *MongoDBChannelRepository) Purge(ctx context.Context, id string) error {
+	return r.db.WithTx(ctx, func(ctx context.Context) error {
+		var channel models.Channel
+		err := r.collection.FindOne(ctx, database.Filter{"_id": id}, &channel)
+		if database.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, owner := range channel.Owners {
+			if err := r.users.UpdateOne(ctx, database.Filter{"_id": owner}, database.Update{
+				Pull: map[string]interface{}{
+					"own_channels": id,
+				},
+			}); err != nil && !database.IsNotFound(err) {
+				return err
+			}
+		}
+
+		if err := r.shares.DeleteMany(ctx, database.Filter{"channel_id": id}); err != nil {
+			return err
+		}
+		if err := r.revisions.DeleteMany(ctx, database.Filter{"channel_id": id}); err != nil {
+			return err
+		}
+		return r.collection.DeleteOne(ctx, database.Filter{"_id": id})
+	})
+}
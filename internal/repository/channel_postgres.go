@@ -0,0 +1,837 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/models"
+)
+
+// PostgresChannelRepository PostgreSQL 頻道 Repository
+type PostgresChannelRepository struct {
+	db database.Database
+}
+
+// NewPostgresChannelRepository 建立 PostgreSQL 頻道 Repository
+func NewPostgresChannelRepository(db database.Database) *PostgresChannelRepository {
+	return &PostgresChannelRepository{db: db}
+}
+
+// getDB 取得 SQL 資料庫連線（使用 context 中的交易）
+func (r *PostgresChannelRepository) getDB() *database.SQLConn {
+	return r.db.(*database.PostgresDatabase).GetConn()
+}
+
+// channelPostgresSelectColumns channels 表查詢欄位（順序需與 scanChannel 一致）
+const channelPostgresSelectColumns = `channels.id, channels.type, channels.name, channels."desc", channels.contents_seq, channels.cover_default, channels.schedule_epoch, channels.schedule_loop, channels.hide_unavailable, channels.visibility, channels.deleted_at, channels.revision, channels.forked_from, channels.fork_count, channels.created, channels.last_modified`
+
+// postgresArgs 依序累積查詢參數並產生對應的 $n 佔位符
+type postgresArgs []interface{}
+
+// add 加入一個參數並回傳其佔位符
+func (a *postgresArgs) add(value interface{}) string {
+	*a = append(*a, value)
+	return fmt.Sprintf("$%d", len(*a))
+}
+
+// FindByID 依 ID 查詢頻道（不含垃圾桶中的頻道）
+func (r *PostgresChannelRepository) FindByID(ctx context.Context, id string) (*models.Channel, error) {
+	return r.find(ctx, id, "deleted_at IS NULL")
+}
+
+// FindDeleted 依 ID 查詢垃圾桶中的頻道
+func (r *PostgresChannelRepository) FindDeleted(ctx context.Context, id string) (*models.Channel, error) {
+	return r.find(ctx, id, "deleted_at IS NOT NULL")
+}
+
+// find 依 ID 與額外條件查詢頻道（含所有關聯資料）
+func (r *PostgresChannelRepository) find(ctx context.Context, id, condition string) (*models.Channel, error) {
+	query := fmt.Sprintf(`SELECT %s FROM channels WHERE id = $1 AND %s`, channelPostgresSelectColumns, condition)
+
+	channel, err := scanChannel(r.getDB().QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if channel.Tags, err = r.loadTags(ctx, id); err != nil {
+		return nil, err
+	}
+	if channel.Owners, err = r.loadOwners(ctx, id); err != nil {
+		return nil, err
+	}
+	if channel.Permission, err = r.loadPermissions(ctx, id); err != nil {
+		return nil, err
+	}
+	if channel.Contents, err = r.loadPrograms(ctx, id); err != nil {
+		return nil, err
+	}
+	if channel.ContentsOrder, err = r.loadContentsOrder(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return channel, nil
+}
+
+// Create 建立頻道
+func (r *PostgresChannelRepository) Create(ctx context.Context, channel *models.Channel) error {
+	now := time.Now()
+	channel.Created = now
+	channel.LastModified = now
+
+	tx, err := r.getDB().BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var coverDefault interface{}
+	if channel.Cover != nil {
+		coverDefault = channel.Cover.Default
+	}
+
+	var forkedFrom interface{}
+	if channel.ForkedFrom != "" {
+		forkedFrom = channel.ForkedFrom
+	}
+
+	var scheduleEpoch, scheduleLoop interface{}
+	if channel.Schedule != nil {
+		scheduleEpoch = channel.Schedule.Epoch
+		scheduleLoop = string(channel.Schedule.Loop)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO channels (id, type, name, "desc", contents_seq, cover_default, schedule_epoch, schedule_loop, hide_unavailable, visibility, deleted_at, revision, forked_from, fork_count, created, last_modified)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)`,
+		channel.ID,
+		channel.Type,
+		channel.Name,
+		channel.Desc,
+		channel.ContentsSeq,
+		coverDefault,
+		scheduleEpoch,
+		scheduleLoop,
+		channel.HideUnavailable,
+		channel.Visibility,
+		channel.DeletedAt,
+		channel.Revision,
+		forkedFrom,
+		channel.ForkCount,
+		channel.Created,
+		channel.LastModified,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := insertPostgresChannelTags(ctx, tx, channel.ID, channel.Tags); err != nil {
+		return err
+	}
+	for _, owner := range channel.Owners {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO channel_owners (channel_id, user_id) VALUES ($1, $2)`, channel.ID, owner); err != nil {
+			return err
+		}
+	}
+	for _, perm := range channel.Permission {
+		if err := insertPostgresPermission(ctx, tx, channel.ID, perm); err != nil {
+			return err
+		}
+	}
+
+	// 節目需透過 ProgramRepository 新增（需要先取得節目 ID），這裡只處理 contents_order
+	for i, programID := range channel.ContentsOrder {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO channel_program_order (channel_id, program_id, order_index) VALUES ($1, $2, $3)`,
+			channel.ID, programID, i); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Update 更新頻道
+func (r *PostgresChannelRepository) Update(ctx context.Context, id string, update map[string]interface{}) error {
+	if len(update) == 0 {
+		return nil
+	}
+
+	tx, err := r.getDB().BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	// 每次修改都遞增修訂版本
+	var args postgresArgs
+	setParts := []string{"last_modified = " + args.add(time.Now()), "revision = revision + " + args.add(database.RevisionIncrement(ctx, id))}
+
+	for key, value := range update {
+		switch key {
+		case "cover.default":
+			setParts = append(setParts, "cover_default = "+args.add(value))
+		case "cover":
+			// cover 只保存 cover_default 欄位，nil 表示清除
+			var coverDefault interface{}
+			if cover, ok := value.(*models.ChannelCover); ok && cover != nil {
+				coverDefault = cover.Default
+			}
+			setParts = append(setParts, "cover_default = "+args.add(coverDefault))
+		case "schedule":
+			// schedule 拆成 schedule_epoch / schedule_loop 兩個欄位，nil 表示清除
+			var epoch, loop interface{}
+			if schedule, ok := value.(*models.ChannelSchedule); ok && schedule != nil {
+				epoch = schedule.Epoch
+				loop = string(schedule.Loop)
+			}
+			setParts = append(setParts, "schedule_epoch = "+args.add(epoch), "schedule_loop = "+args.add(loop))
+		case "tags":
+			// 刪除舊的 tags 並插入新的
+			if _, err := tx.ExecContext(ctx, `DELETE FROM channel_tags WHERE channel_id = $1`, id); err != nil {
+				return err
+			}
+			if tags, ok := value.([]int); ok {
+				if err := insertPostgresChannelTags(ctx, tx, id, tags); err != nil {
+					return err
+				}
+			}
+		default:
+			setParts = append(setParts, pgx.Identifier{key}.Sanitize()+" = "+args.add(value))
+		}
+	}
+
+	query := fmt.Sprintf("UPDATE channels SET %s WHERE id = %s", strings.Join(setParts, ", "), args.add(id))
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ListChannels 列出頻道（支援過濾和排序）
+func (r *PostgresChannelRepository) ListChannels(ctx context.Context, filter database.Filter, sort database.Sort, limit, skip int64) ([]models.Channel, error) {
+	// 不含垃圾桶中的頻道
+	whereParts := []string{"deleted_at IS NULL"}
+	var args postgresArgs
+
+	for key, value := range filter {
+		operators, _ := value.(database.Filter)
+		if operators == nil {
+			if m, ok := value.(map[string]interface{}); ok {
+				operators = database.Filter(m)
+			}
+		}
+
+		switch key {
+		case "owners":
+			whereParts = append(whereParts, "EXISTS (SELECT 1 FROM channel_owners WHERE channel_id = channels.id AND user_id = "+args.add(value)+")")
+		case "name":
+			// 名稱模糊搜尋（不分大小寫的部分比對）
+			if regex, ok := operators["$regex"].(string); ok {
+				whereParts = append(whereParts, "name ILIKE "+args.add("%"+likeEscaper.Replace(regex)+"%"))
+			} else {
+				whereParts = append(whereParts, "name = "+args.add(value))
+			}
+		case "type", "visibility":
+			if ninValues, ok := operators["$nin"].([]string); ok {
+				whereParts = append(whereParts, "NOT ("+key+" = ANY("+args.add(ninValues)+"))")
+			} else {
+				whereParts = append(whereParts, key+" = "+args.add(value))
+			}
+		case "tags":
+			// 任一標籤符合
+			tags, _ := operators["$in"].([]int)
+			if len(tags) == 0 {
+				whereParts = append(whereParts, "FALSE")
+			} else {
+				whereParts = append(whereParts, "EXISTS (SELECT 1 FROM channel_tags WHERE channel_id = channels.id AND tag = ANY("+args.add(tags)+"))")
+			}
+		case "contents.0":
+			// 只列出有節目的頻道
+			if _, ok := operators["$exists"]; ok {
+				whereParts = append(whereParts, "EXISTS (SELECT 1 FROM channel_program_order WHERE channel_id = channels.id)")
+			}
+		default:
+			whereParts = append(whereParts, pgx.Identifier{key}.Sanitize()+" = "+args.add(value))
+		}
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM channels WHERE %s`, channelPostgresSelectColumns, strings.Join(whereParts, " AND "))
+
+	if len(sort) > 0 {
+		orderParts := make([]string, 0, len(sort))
+		for _, s := range sort {
+			order := "ASC"
+			if s.Order < 0 {
+				order = "DESC"
+			}
+			orderParts = append(orderParts, pgx.Identifier{s.Field}.Sanitize()+" "+order)
+		}
+		query += " ORDER BY " + strings.Join(orderParts, ", ")
+	}
+	if limit > 0 {
+		query += " LIMIT " + args.add(limit)
+	}
+	if skip > 0 {
+		query += " OFFSET " + args.add(skip)
+	}
+
+	return r.queryChannels(ctx, query, args...)
+}
+
+// queryChannels 查詢多個頻道（不載入關聯資料）
+func (r *PostgresChannelRepository) queryChannels(ctx context.Context, query string, args ...interface{}) ([]models.Channel, error) {
+	rows, err := r.getDB().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	channels := []models.Channel{}
+	for rows.Next() {
+		channel, err := scanChannel(rows)
+		if err != nil {
+			return nil, err
+		}
+		channels = append(channels, *channel)
+	}
+	return channels, rows.Err()
+}
+
+// LoadContents 批次載入 ListChannels 列出的頻道的節目與節目順序
+func (r *PostgresChannelRepository) LoadContents(ctx context.Context, channels []models.Channel) error {
+	if len(channels) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(channels))
+	index := make(map[string]int, len(channels))
+	for i := range channels {
+		ids[i] = channels[i].ID
+		index[channels[i].ID] = i
+	}
+
+	rows, err := r.getDB().QueryContext(ctx,
+		`SELECT `+programPostgresSelectColumns+`, programs.channel_id FROM programs WHERE channel_id = ANY($1) ORDER BY id`, ids)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var programIDs []int
+	for rows.Next() {
+		var channelID string
+		program, err := scanPostgresProgram(rows, &channelID)
+		if err != nil {
+			return err
+		}
+		i := index[channelID]
+		channels[i].Contents = append(channels[i].Contents, *program)
+		programIDs = append(programIDs, program.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	_ = rows.Close()
+
+	tagsMap, err := r.loadProgramTagsBatch(ctx, programIDs)
+	if err != nil {
+		return err
+	}
+	for i := range channels {
+		for j := range channels[i].Contents {
+			channels[i].Contents[j].Tags = tagsMap[channels[i].Contents[j].ID]
+		}
+	}
+
+	orderRows, err := r.getDB().QueryContext(ctx,
+		`SELECT channel_id, program_id FROM channel_program_order WHERE channel_id = ANY($1) ORDER BY channel_id, order_index`, ids)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = orderRows.Close()
+	}()
+	for orderRows.Next() {
+		var channelID string
+		var programID int
+		if err := orderRows.Scan(&channelID, &programID); err != nil {
+			return err
+		}
+		i := index[channelID]
+		channels[i].ContentsOrder = append(channels[i].ContentsOrder, programID)
+	}
+	return orderRows.Err()
+}
+
+// IsAdmin 檢查使用者是否為頻道管理員
+func (r *PostgresChannelRepository) IsAdmin(ctx context.Context, channelID, userID string) (bool, error) {
+	var isAdmin bool
+	err := r.getDB().QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM channels WHERE id = $1 AND deleted_at IS NULL)
+		AND (EXISTS (SELECT 1 FROM channel_owners WHERE channel_id = $1 AND user_id = $2)
+			OR EXISTS (SELECT 1 FROM channel_permissions WHERE channel_id = $1 AND user_id = $2 AND admin))`,
+		channelID, userID).Scan(&isAdmin)
+	return isAdmin, err
+}
+
+// GetPermission 取得使用者對頻道的權限（擁有者具有所有權限）
+func (r *PostgresChannelRepository) GetPermission(ctx context.Context, channelID, userID string) (models.ChannelPermission, error) {
+	permission := models.ChannelPermission{UserID: userID}
+
+	// 垃圾桶中的頻道沒有任何權限
+	var active, owner bool
+	err := r.getDB().QueryRowContext(ctx, `SELECT
+		EXISTS (SELECT 1 FROM channels WHERE id = $1 AND deleted_at IS NULL),
+		EXISTS (SELECT 1 FROM channel_owners WHERE channel_id = $1 AND user_id = $2)`,
+		channelID, userID).Scan(&active, &owner)
+	if err != nil || !active {
+		return permission, err
+	}
+	if owner {
+		permission.Admin, permission.Read, permission.Write = true, true, true
+		return permission, nil
+	}
+
+	err = r.getDB().QueryRowContext(ctx,
+		`SELECT admin, read, write FROM channel_permissions WHERE channel_id = $1 AND user_id = $2`,
+		channelID, userID).Scan(&permission.Admin, &permission.Read, &permission.Write)
+	if err == sql.ErrNoRows {
+		return permission, nil
+	}
+	return permission, err
+}
+
+// SetPermission 設定使用者對頻道的權限（所有權限皆為 false 時移除）
+func (r *PostgresChannelRepository) SetPermission(ctx context.Context, channelID string, permission models.ChannelPermission) error {
+	return r.modify(ctx, channelID, func(tx *database.SQLTx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM channel_permissions WHERE channel_id = $1 AND user_id = $2`, channelID, permission.UserID); err != nil {
+			return err
+		}
+		if permission.Admin || permission.Read || permission.Write {
+			return insertPostgresPermission(ctx, tx, channelID, permission)
+		}
+		return nil
+	})
+}
+
+// AddOwners 新增擁有者（同時更新使用者的 own_channels）
+func (r *PostgresChannelRepository) AddOwners(ctx context.Context, channelID string, userIDs []string) error {
+	return r.modify(ctx, channelID, func(tx *database.SQLTx) error {
+		for _, userID := range userIDs {
+			if err := addPostgresOwnerTx(ctx, tx, channelID, userID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// RemoveOwner 移除擁有者（同時更新使用者的 own_channels），不能移除最後一位擁有者
+func (r *PostgresChannelRepository) RemoveOwner(ctx context.Context, channelID, userID string) error {
+	return r.modify(ctx, channelID, func(tx *database.SQLTx) error {
+		return removePostgresOwnerTx(ctx, tx, channelID, userID)
+	})
+}
+
+// TransferOwnership 將擁有權從 fromUserID 移轉給 toUserID
+func (r *PostgresChannelRepository) TransferOwnership(ctx context.Context, channelID, fromUserID, toUserID string) error {
+	return r.modify(ctx, channelID, func(tx *database.SQLTx) error {
+		// 先加入新的擁有者，移除原擁有者時才不會變成沒有擁有者
+		if err := addPostgresOwnerTx(ctx, tx, channelID, toUserID); err != nil {
+			return err
+		}
+		return removePostgresOwnerTx(ctx, tx, channelID, fromUserID)
+	})
+}
+
+// modify 在交易中修改頻道的關聯資料，並更新 last_modified 與修訂版本
+func (r *PostgresChannelRepository) modify(ctx context.Context, channelID string, fn func(tx *database.SQLTx) error) error {
+	tx, err := r.getDB().BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE channels SET last_modified = $1, revision = revision + $2 WHERE id = $3`, time.Now(), database.RevisionIncrement(ctx, channelID), channelID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// addPostgresOwnerTx 在交易中新增擁有者與使用者的頻道關聯
+func addPostgresOwnerTx(ctx context.Context, tx *database.SQLTx, channelID, userID string) error {
+	if _, err := tx.ExecContext(ctx, `INSERT INTO channel_owners (channel_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, channelID, userID); err != nil {
+		return err
+	}
+	_, err := tx.ExecContext(ctx, `INSERT INTO user_channels (user_id, channel_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, userID, channelID)
+	return err
+}
+
+// removePostgresOwnerTx 在交易中移除擁有者與使用者的頻道關聯（不是擁有者時回傳 ErrNoDocuments）
+func removePostgresOwnerTx(ctx context.Context, tx *database.SQLTx, channelID, userID string) error {
+	var owners, isOwner int64
+	err := tx.QueryRowContext(ctx,
+		`SELECT COUNT(*), COUNT(*) FILTER (WHERE user_id = $1) FROM channel_owners WHERE channel_id = $2`,
+		userID, channelID).Scan(&owners, &isOwner)
+	if err != nil {
+		return err
+	}
+	if isOwner == 0 {
+		return database.ErrNoDocuments
+	}
+	if owners <= 1 {
+		return database.ErrLastOwner
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM channel_owners WHERE channel_id = $1 AND user_id = $2`, channelID, userID); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `DELETE FROM user_channels WHERE user_id = $1 AND channel_id = $2`, userID, channelID)
+	return err
+}
+
+// GetRevision 取得頻道目前的修訂版本
+func (r *PostgresChannelRepository) GetRevision(ctx context.Context, channelID string) (int64, error) {
+	var revision int64
+	err := r.getDB().QueryRowContext(ctx, `SELECT revision FROM channels WHERE id = $1`, channelID).Scan(&revision)
+	if err == sql.ErrNoRows {
+		return 0, database.ErrNoDocuments
+	}
+	return revision, err
+}
+
+// ClaimRevision 比對並遞增頻道修訂版本（不符或頻道在垃圾桶中時回傳 ErrRevisionMismatch），確保同時修改時只有一方成功
+// 需與修改頻道的操作在同一個交易中呼叫，並以 database.WithClaimedRevision 標示後續修改不再遞增
+func (r *PostgresChannelRepository) ClaimRevision(ctx context.Context, channelID string, revision int64) error {
+	result, err := r.getDB().ExecContext(ctx,
+		`UPDATE channels SET revision = revision + 1 WHERE id = $1 AND revision = $2 AND deleted_at IS NULL`, channelID, revision)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return database.ErrRevisionMismatch
+	}
+	return nil
+}
+
+// IncrementForkCount 遞增頻道被複製的次數（不視為修改頻道，不遞增修訂版本）
+func (r *PostgresChannelRepository) IncrementForkCount(ctx context.Context, channelID string) error {
+	_, err := r.getDB().ExecContext(ctx, `UPDATE channels SET fork_count = fork_count + 1 WHERE id = $1`, channelID)
+	return err
+}
+
+// SoftDelete 將頻道移到垃圾桶
+func (r *PostgresChannelRepository) SoftDelete(ctx context.Context, id string, deletedAt time.Time) error {
+	return r.execAffected(ctx, `UPDATE channels SET deleted_at = $1, revision = revision + $2 WHERE id = $3 AND deleted_at IS NULL`, deletedAt, database.RevisionIncrement(ctx, id), id)
+}
+
+// Restore 將頻道從垃圾桶還原
+func (r *PostgresChannelRepository) Restore(ctx context.Context, id string) error {
+	return r.execAffected(ctx, `UPDATE channels SET deleted_at = NULL, last_modified = $1, revision = revision + $2 WHERE id = $3 AND deleted_at IS NOT NULL`, time.Now(), database.RevisionIncrement(ctx, id), id)
+}
+
+// execAffected 執行更新，沒有符合條件的頻道時回傳 ErrNoDocuments
+func (r *PostgresChannelRepository) execAffected(ctx context.Context, query string, args ...interface{}) error {
+	result, err := r.getDB().ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return database.ErrNoDocuments
+	}
+	return nil
+}
+
+// ListDeleted 列出使用者擁有且在垃圾桶中的頻道（依刪除時間由新到舊）
+func (r *PostgresChannelRepository) ListDeleted(ctx context.Context, userID string) ([]models.Channel, error) {
+	channels, err := r.queryChannels(ctx, fmt.Sprintf(`SELECT %s FROM channels
+		WHERE deleted_at IS NOT NULL
+		AND EXISTS (SELECT 1 FROM channel_owners WHERE channel_id = channels.id AND user_id = $1)
+		ORDER BY deleted_at DESC`, channelPostgresSelectColumns), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range channels {
+		if channels[i].Owners, err = r.loadOwners(ctx, channels[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return channels, nil
+}
+
+// ListExpired 列出在 before 之前移到垃圾桶的頻道 ID
+func (r *PostgresChannelRepository) ListExpired(ctx context.Context, before time.Time, limit int64) ([]string, error) {
+	rows, err := r.getDB().QueryContext(ctx,
+		`SELECT id FROM channels WHERE deleted_at IS NOT NULL AND deleted_at <= $1 ORDER BY deleted_at LIMIT $2`,
+		before, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// Purge 永久刪除頻道，同時刪除節目、標籤、節目順序、擁有者、權限與分享邀請
+func (r *PostgresChannelRepository) Purge(ctx context.Context, id string) error {
+	tx, err := r.getDB().BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	// 節目標籤、節目順序、頻道標籤、擁有者與權限由外鍵串聯刪除
+	statements := []string{
+		`DELETE FROM programs WHERE channel_id = $1`,
+		`DELETE FROM user_channels WHERE channel_id = $1`,
+		`DELETE FROM channel_shares WHERE channel_id = $1`,
+		`DELETE FROM channel_revisions WHERE channel_id = $1`,
+		`DELETE FROM channels WHERE id = $1`,
+	}
+	for _, statement := range statements {
+		if _, err := tx.ExecContext(ctx, statement, id); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// loadTags 載入頻道的 tags
+func (r *PostgresChannelRepository) loadTags(ctx context.Context, channelID string) ([]int, error) {
+	return r.queryInts(ctx, `SELECT tag FROM channel_tags WHERE channel_id = $1 ORDER BY tag`, channelID)
+}
+
+// loadContentsOrder 載入頻道的 contents_order
+func (r *PostgresChannelRepository) loadContentsOrder(ctx context.Context, channelID string) ([]int, error) {
+	return r.queryInts(ctx, `SELECT program_id FROM channel_program_order WHERE channel_id = $1 ORDER BY order_index`, channelID)
+}
+
+// queryInts 查詢單一整數欄位的多筆資料
+func (r *PostgresChannelRepository) queryInts(ctx context.Context, query string, args ...interface{}) ([]int, error) {
+	rows, err := r.getDB().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var values []int
+	for rows.Next() {
+		var value int
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
+
+// loadOwners 載入頻道的 owners
+func (r *PostgresChannelRepository) loadOwners(ctx context.Context, channelID string) ([]string, error) {
+	rows, err := r.getDB().QueryContext(ctx,
+		`SELECT user_id FROM channel_owners WHERE channel_id = $1 ORDER BY user_id COLLATE "C"`, channelID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var owners []string
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		owners = append(owners, userID)
+	}
+	return owners, rows.Err()
+}
+
+// loadPermissions 載入頻道的 permissions
+func (r *PostgresChannelRepository) loadPermissions(ctx context.Context, channelID string) ([]models.ChannelPermission, error) {
+	rows, err := r.getDB().QueryContext(ctx,
+		`SELECT user_id, admin, read, write FROM channel_permissions WHERE channel_id = $1`, channelID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var permissions []models.ChannelPermission
+	for rows.Next() {
+		var perm models.ChannelPermission
+		if err := rows.Scan(&perm.UserID, &perm.Admin, &perm.Read, &perm.Write); err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, perm)
+	}
+	return permissions, rows.Err()
+}
+
+// programPostgresSelectColumns programs 表查詢欄位（順序需與 scanPostgresProgram 一致）
+const programPostgresSelectColumns = `programs.id, programs.name, programs."desc", programs.duration, programs.type, programs.youtube_id, programs.video_id, programs.metadata_at, programs.availability, programs.last_checked, programs.created, programs.last_modified`
+
+// scanPostgresProgram 掃描 programs 表的一列資料（extra 為查詢欄位之後的其他欄位）
+func scanPostgresProgram(row rowScanner, extra ...interface{}) (*models.Program, error) {
+	var program models.Program
+	var desc, youtubeID, videoID, availability sql.NullString
+	var duration sql.NullInt64
+	var metadataAt, lastChecked sql.NullTime
+	dest := []interface{}{
+		&program.ID,
+		&program.Name,
+		&desc,
+		&duration,
+		&program.Type,
+		&youtubeID,
+		&videoID,
+		&metadataAt,
+		&availability,
+		&lastChecked,
+		&program.Created,
+		&program.LastModified,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	program.Desc = desc.String
+	program.Duration = int(duration.Int64)
+	program.YouTubeID = youtubeID.String
+	program.VideoID = videoID.String
+	if metadataAt.Valid {
+		program.MetadataAt = &metadataAt.Time
+	}
+	program.Availability = models.Availability(availability.String)
+	if lastChecked.Valid {
+		program.LastChecked = &lastChecked.Time
+	}
+	return &program, nil
+}
+
+// loadPrograms 載入頻道的 programs（含 tags）
+func (r *PostgresChannelRepository) loadPrograms(ctx context.Context, channelID string) ([]models.Program, error) {
+	rows, err := r.getDB().QueryContext(ctx,
+		`SELECT `+programPostgresSelectColumns+` FROM programs WHERE channel_id = $1 ORDER BY id`, channelID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var programs []models.Program
+	var programIDs []int
+	for rows.Next() {
+		program, err := scanPostgresProgram(rows)
+		if err != nil {
+			return nil, err
+		}
+		programs = append(programs, *program)
+		programIDs = append(programIDs, program.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	tagsMap, err := r.loadProgramTagsBatch(ctx, programIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range programs {
+		programs[i].Tags = tagsMap[programs[i].ID]
+	}
+	return programs, nil
+}
+
+// loadProgramTagsBatch 批量載入 program tags
+func (r *PostgresChannelRepository) loadProgramTagsBatch(ctx context.Context, programIDs []int) (map[int][]int, error) {
+	tagsMap := make(map[int][]int)
+	if len(programIDs) == 0 {
+		return tagsMap, nil
+	}
+
+	rows, err := r.getDB().QueryContext(ctx,
+		`SELECT program_id, tag FROM program_tags WHERE program_id = ANY($1) ORDER BY program_id, tag`, programIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	for rows.Next() {
+		var programID, tag int
+		if err := rows.Scan(&programID, &tag); err != nil {
+			return nil, err
+		}
+		tagsMap[programID] = append(tagsMap[programID], tag)
+	}
+	return tagsMap, rows.Err()
+}
+
+// insertPostgresChannelTags 在交易中插入頻道 tags
+func insertPostgresChannelTags(ctx context.Context, tx *database.SQLTx, channelID string, tags []int) error {
+	for _, tag := range tags {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO channel_tags (channel_id, tag) VALUES ($1, $2) ON CONFLICT DO NOTHING`, channelID, tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// insertPostgresPermission 在交易中插入頻道權限
+func insertPostgresPermission(ctx context.Context, tx *database.SQLTx, channelID string, perm models.ChannelPermission) error {
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO channel_permissions (channel_id, user_id, admin, read, write) VALUES ($1, $2, $3, $4, $5)`,
+		channelID, perm.UserID, perm.Admin, perm.Read, perm.Write)
+	return err
+}
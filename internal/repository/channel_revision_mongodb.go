@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/models"
+)
+
+// MongoDBChannelRevisionRepository MongoDB 頻道歷史版本 Repository
+type MongoDBChannelRevisionRepository struct {
+	collection database.Collection
+}
+
+// NewMongoDBChannelRevisionRepository 建立 MongoDB 頻道歷史版本 Repository
+func NewMongoDBChannelRevisionRepository(db database.Database) *MongoDBChannelRevisionRepository {
+	return &MongoDBChannelRevisionRepository{
+		collection: db.Collection("channel_revisions"),
+	}
+}
+
+// Create 建立頻道歷史版本
+func (r *MongoDBChannelRevisionRepository) Create(ctx context.Context, revision *models.ChannelRevision) error {
+	revision.Created = time.Now()
+	return r.collection.InsertOne(ctx, revision)
+}
+
+// FindByID 依 ID 查詢（找不到時回傳 nil）
+func (r *MongoDBChannelRevisionRepository) FindByID(ctx context.Context, id string) (*models.ChannelRevision, error) {
+	var revision models.ChannelRevision
+	err := r.collection.FindOne(ctx, database.Filter{"_id": id}, &revision)
+	if database.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// ListByChannel 列出頻道的歷史版本（依建立時間由新到舊）
+func (r *MongoDBChannelRevisionRepository) ListByChannel(ctx context.Context, channelID string, limit, skip int64) ([]models.ChannelRevision, error) {
+	revisions := []models.ChannelRevision{}
+	sort := database.Sort{{Field: "created", Order: -1}, {Field: "revision", Order: -1}}
+	if err := r.collection.Find(ctx, database.Filter{"channel_id": channelID}, sort, limit, skip, &revisions); err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
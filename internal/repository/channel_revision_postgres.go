@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/models"
+)
+
+// PostgresChannelRevisionRepository PostgreSQL 頻道歷史版本 Repository（snapshot 以 JSONB 儲存）
+type PostgresChannelRevisionRepository struct {
+	db database.Database
+}
+
+// NewPostgresChannelRevisionRepository 建立 PostgreSQL 頻道歷史版本 Repository
+func NewPostgresChannelRevisionRepository(db database.Database) *PostgresChannelRevisionRepository {
+	return &PostgresChannelRevisionRepository{db: db}
+}
+
+// getDB 取得 SQL 資料庫連線（使用 context 中的交易）
+func (r *PostgresChannelRevisionRepository) getDB() *database.SQLConn {
+	return r.db.(*database.PostgresDatabase).GetConn()
+}
+
+// Create 建立頻道歷史版本
+func (r *PostgresChannelRevisionRepository) Create(ctx context.Context, revision *models.ChannelRevision) error {
+	revision.Created = time.Now()
+
+	snapshot, err := json.Marshal(revision.Snapshot)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO channel_revisions (` + channelRevisionSelectColumns + `) VALUES ($1, $2, $3, $4, $5, $6)`
+	_, err = r.getDB().ExecContext(ctx, query,
+		revision.ID,
+		revision.ChannelID,
+		revision.Revision,
+		revision.Action,
+		string(snapshot),
+		revision.Created,
+	)
+	return err
+}
+
+// FindByID 依 ID 查詢（找不到時回傳 nil）
+func (r *PostgresChannelRevisionRepository) FindByID(ctx context.Context, id string) (*models.ChannelRevision, error) {
+	row := r.getDB().QueryRowContext(ctx, `SELECT `+channelRevisionSelectColumns+` FROM channel_revisions WHERE id = $1`, id)
+	revision, err := scanChannelRevision(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return revision, err
+}
+
+// ListByChannel 列出頻道的歷史版本（依建立時間由新到舊）
+func (r *PostgresChannelRevisionRepository) ListByChannel(ctx context.Context, channelID string, limit, skip int64) ([]models.ChannelRevision, error) {
+	query := `SELECT ` + channelRevisionSelectColumns + ` FROM channel_revisions WHERE channel_id = $1 ORDER BY created DESC, revision DESC`
+	args := postgresArgs{channelID}
+	if limit > 0 {
+		query += ` LIMIT ` + args.add(limit) + ` OFFSET ` + args.add(skip)
+	}
+
+	rows, err := r.getDB().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	revisions := []models.ChannelRevision{}
+	for rows.Next() {
+		revision, err := scanChannelRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, *revision)
+	}
+	return revisions, rows.Err()
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/models"
+)
+
+// SQLiteChannelRevisionRepository SQLite 頻道歷史版本 Repository
+type SQLiteChannelRevisionRepository struct {
+	db database.Database
+}
+
+// NewSQLiteChannelRevisionRepository 建立 SQLite 頻道歷史版本 Repository
+func NewSQLiteChannelRevisionRepository(db database.Database) *SQLiteChannelRevisionRepository {
+	return &SQLiteChannelRevisionRepository{db: db}
+}
+
+// getDB 取得 SQL 資料庫連線（使用 context 中的交易）
+func (r *SQLiteChannelRevisionRepository) getDB() *database.SQLConn {
+	return r.db.(*database.SQLiteDatabase).GetConn()
+}
+
+const channelRevisionSelectColumns = `id, channel_id, revision, action, snapshot, created`
+
+// scanChannelRevision 讀取一筆頻道歷史版本（snapshot 以 JSON 儲存）
+func scanChannelRevision(row rowScanner) (*models.ChannelRevision, error) {
+	var revision models.ChannelRevision
+	var snapshot string
+	if err := row.Scan(
+		&revision.ID,
+		&revision.ChannelID,
+		&revision.Revision,
+		&revision.Action,
+		&snapshot,
+		&revision.Created,
+	); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(snapshot), &revision.Snapshot); err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// Create 建立頻道歷史版本
+func (r *SQLiteChannelRevisionRepository) Create(ctx context.Context, revision *models.ChannelRevision) error {
+	revision.Created = time.Now()
+
+	snapshot, err := json.Marshal(revision.Snapshot)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO channel_revisions (` + channelRevisionSelectColumns + `) VALUES (?, ?, ?, ?, ?, ?)`
+	_, err = r.getDB().ExecContext(ctx, query,
+		revision.ID,
+		revision.ChannelID,
+		revision.Revision,
+		revision.Action,
+		string(snapshot),
+		revision.Created,
+	)
+	return err
+}
+
+// FindByID 依 ID 查詢（找不到時回傳 nil）
+func (r *SQLiteChannelRevisionRepository) FindByID(ctx context.Context, id string) (*models.ChannelRevision, error) {
+	row := r.getDB().QueryRowContext(ctx, `SELECT `+channelRevisionSelectColumns+` FROM channel_revisions WHERE id = ?`, id)
+	revision, err := scanChannelRevision(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return revision, err
+}
+
+// ListByChannel 列出頻道的歷史版本（依建立時間由新到舊）
+func (r *SQLiteChannelRevisionRepository) ListByChannel(ctx context.Context, channelID string, limit, skip int64) ([]models.ChannelRevision, error) {
+	query := `SELECT ` + channelRevisionSelectColumns + ` FROM channel_revisions WHERE channel_id = ? ORDER BY created DESC, revision DESC`
+	args := []interface{}{channelID}
+	if limit > 0 {
+		query += ` LIMIT ? OFFSET ?`
+		args = append(args, limit, skip)
+	}
+
+	rows, err := r.getDB().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	revisions := []models.ChannelRevision{}
+	for rows.Next() {
+		revision, err := scanChannelRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, *revision)
+	}
+	return revisions, rows.Err()
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/models"
+)
+
+// MongoDBChannelShareRepository MongoDB 頻道分享邀請 Repository
+type MongoDBChannelShareRepository struct {
+	collection database.Collection
+}
+
+// NewMongoDBChannelShareRepository 建立 MongoDB 頻道分享邀請 Repository
+func NewMongoDBChannelShareRepository(db database.Database) *MongoDBChannelShareRepository {
+	return &MongoDBChannelShareRepository{
+		collection: db.Collection("channel_shares"),
+	}
+}
+
+// Create 建立頻道分享邀請
+func (r *MongoDBChannelShareRepository) Create(ctx context.Context, share *models.ChannelShare) error {
+	now := time.Now()
+	share.Created = now
+	share.LastModified = now
+	return r.collection.InsertOne(ctx, share)
+}
+
+// FindByID 依 ID 查詢
+func (r *MongoDBChannelShareRepository) FindByID(ctx context.Context, id string) (*models.ChannelShare, error) {
+	return r.findOne(ctx, database.Filter{"_id": id})
+}
+
+// FindByHash 依 Token 雜湊值查詢
+func (r *MongoDBChannelShareRepository) FindByHash(ctx context.Context, tokenHash string) (*models.ChannelShare, error) {
+	return r.findOne(ctx, database.Filter{"token_hash": tokenHash})
+}
+
+// findOne 查詢單筆頻道分享邀請（找不到時回傳 nil）
+func (r *MongoDBChannelShareRepository) findOne(ctx context.Context, filter database.Filter) (*models.ChannelShare, error) {
+	var share models.ChannelShare
+	err := r.collection.FindOne(ctx, filter, &share)
+	if database.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &share, nil
+}
+
+// ListByChannel 列出頻道的分享邀請（依建立時間由新到舊）
+func (r *MongoDBChannelShareRepository) ListByChannel(ctx context.Context, channelID string) ([]models.ChannelShare, error) {
+	shares := []models.ChannelShare{}
+	sort := database.Sort{{Field: "created", Order: -1}}
+	if err := r.collection.Find(ctx, database.Filter{"channel_id": channelID}, sort, 0, 0, &shares); err != nil {
+		return nil, err
+	}
+	return shares, nil
+}
+
+// ListPendingByEmail 列出寄給 email 且尚未回應、未過期的分享邀請
+func (r *MongoDBChannelShareRepository) ListPendingByEmail(ctx context.Context, email string, now time.Time) ([]models.ChannelShare, error) {
+	shares := []models.ChannelShare{}
+	sort := database.Sort{{Field: "created", Order: 1}}
+	if err := r.collection.Find(ctx, database.Filter{
+		"email":      email,
+		"status":     models.ShareStatusPending,
+		"expires_at": database.Filter{"$gt": now},
+	}, sort, 0, 0, &shares); err != nil {
+		return nil, err
+	}
+	return shares, nil
+}
+
+// UpdateStatus 將狀態為 fromStatus 的邀請更新為 toStatus，回傳是否有更新（用於避免重複接受）
+func (r *MongoDBChannelShareRepository) UpdateStatus(ctx context.Context, id, fromStatus, toStatus, userID string) (bool, error) {
+	return r.collection.UpdateOneMatched(ctx, database.Filter{"_id": id, "status": fromStatus}, database.Update{
+		Set: map[string]interface{}{
+			"status":        toStatus,
+			"user_id":       userID,
+			"last_modified": time.Now(),
+		},
+	})
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/models"
+)
+
+// PostgresChannelShareRepository PostgreSQL 頻道分享邀請 Repository
+type PostgresChannelShareRepository struct {
+	db database.Database
+}
+
+// NewPostgresChannelShareRepository 建立 PostgreSQL 頻道分享邀請 Repository
+func NewPostgresChannelShareRepository(db database.Database) *PostgresChannelShareRepository {
+	return &PostgresChannelShareRepository{db: db}
+}
+
+// getDB 取得 SQL 資料庫連線（使用 context 中的交易）
+func (r *PostgresChannelShareRepository) getDB() *database.SQLConn {
+	return r.db.(*database.PostgresDatabase).GetConn()
+}
+
+// Create 建立頻道分享邀請
+func (r *PostgresChannelShareRepository) Create(ctx context.Context, share *models.ChannelShare) error {
+	now := time.Now()
+	share.Created = now
+	share.LastModified = now
+
+	query := `INSERT INTO channel_shares (` + channelShareSelectColumns + `) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+	_, err := r.getDB().ExecContext(ctx, query,
+		share.ID,
+		share.ChannelID,
+		share.Email,
+		share.Role,
+		share.TokenHash,
+		share.InvitedBy,
+		share.Status,
+		share.UserID,
+		share.ExpiresAt,
+		share.Created,
+		share.LastModified,
+	)
+	return err
+}
+
+// FindByID 依 ID 查詢
+func (r *PostgresChannelShareRepository) FindByID(ctx context.Context, id string) (*models.ChannelShare, error) {
+	return r.findOne(ctx, `SELECT `+channelShareSelectColumns+` FROM channel_shares WHERE id = $1`, id)
+}
+
+// FindByHash 依 Token 雜湊值查詢
+func (r *PostgresChannelShareRepository) FindByHash(ctx context.Context, tokenHash string) (*models.ChannelShare, error) {
+	return r.findOne(ctx, `SELECT `+channelShareSelectColumns+` FROM channel_shares WHERE token_hash = $1`, tokenHash)
+}
+
+// findOne 查詢單筆頻道分享邀請（找不到時回傳 nil）
+func (r *PostgresChannelShareRepository) findOne(ctx context.Context, query string, args ...interface{}) (*models.ChannelShare, error) {
+	share, err := scanChannelShare(r.getDB().QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return share, err
+}
+
+// ListByChannel 列出頻道的分享邀請（依建立時間由新到舊）
+func (r *PostgresChannelShareRepository) ListByChannel(ctx context.Context, channelID string) ([]models.ChannelShare, error) {
+	return r.list(ctx, `SELECT `+channelShareSelectColumns+` FROM channel_shares WHERE channel_id = $1 ORDER BY created DESC`, channelID)
+}
+
+// ListPendingByEmail 列出寄給 email 且尚未回應、未過期的分享邀請
+func (r *PostgresChannelShareRepository) ListPendingByEmail(ctx context.Context, email string, now time.Time) ([]models.ChannelShare, error) {
+	return r.list(ctx, `SELECT `+channelShareSelectColumns+` FROM channel_shares
+		WHERE email = $1 AND status = $2 AND expires_at > $3 ORDER BY created`,
+		email, models.ShareStatusPending, now)
+}
+
+// list 查詢多筆頻道分享邀請
+func (r *PostgresChannelShareRepository) list(ctx context.Context, query string, args ...interface{}) ([]models.ChannelShare, error) {
+	rows, err := r.getDB().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	shares := []models.ChannelShare{}
+	for rows.Next() {
+		share, err := scanChannelShare(rows)
+		if err != nil {
+			return nil, err
+		}
+		shares = append(shares, *share)
+	}
+	return shares, rows.Err()
+}
+
+// UpdateStatus 將狀態為 fromStatus 的邀請更新為 toStatus，回傳是否有更新（用於避免重複接受）
+func (r *PostgresChannelShareRepository) UpdateStatus(ctx context.Context, id, fromStatus, toStatus, userID string) (bool, error) {
+	result, err := r.getDB().ExecContext(ctx,
+		`UPDATE channel_shares SET status = $1, user_id = $2, last_modified = $3 WHERE id = $4 AND status = $5`,
+		toStatus, userID, time.Now(), id, fromStatus)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
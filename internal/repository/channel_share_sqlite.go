@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/models"
+)
+
+// SQLiteChannelShareRepository SQLite 頻道分享邀請 Repository
+type SQLiteChannelShareRepository struct {
+	db database.Database
+}
+
+// NewSQLiteChannelShareRepository 建立 SQLite 頻道分享邀請 Repository
+func NewSQLiteChannelShareRepository(db database.Database) *SQLiteChannelShareRepository {
+	return &SQLiteChannelShareRepository{db: db}
+}
+
+// getDB 取得 SQL 資料庫連線（使用 context 中的交易）
+func (r *SQLiteChannelShareRepository) getDB() *database.SQLConn {
+	return r.db.(*database.SQLiteDatabase).GetConn()
+}
+
+const channelShareSelectColumns = `id, channel_id, email, role, token_hash, invited_by, status, user_id, expires_at, created, last_modified`
+
+// scanChannelShare 讀取一筆頻道分享邀請
+func scanChannelShare(row rowScanner) (*models.ChannelShare, error) {
+	var share models.ChannelShare
+	if err := row.Scan(
+		&share.ID,
+		&share.ChannelID,
+		&share.Email,
+		&share.Role,
+		&share.TokenHash,
+		&share.InvitedBy,
+		&share.Status,
+		&share.UserID,
+		&share.ExpiresAt,
+		&share.Created,
+		&share.LastModified,
+	); err != nil {
+		return nil, err
+	}
+	return &share, nil
+}
+
+// Create 建立頻道分享邀請（到期時間以 UTC 儲存，確保字串比較與時間先後一致）
+func (r *SQLiteChannelShareRepository) Create(ctx context.Context, share *models.ChannelShare) error {
+	now := time.Now()
+	share.Created = now
+	share.LastModified = now
+
+	query := `INSERT INTO channel_shares (` + channelShareSelectColumns + `) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.getDB().ExecContext(ctx, query,
+		share.ID,
+		share.ChannelID,
+		share.Email,
+		share.Role,
+		share.TokenHash,
+		share.InvitedBy,
+		share.Status,
+		share.UserID,
+		share.ExpiresAt.UTC(),
+		share.Created,
+		share.LastModified,
+	)
+	return err
+}
+
+// FindByID 依 ID 查詢
+func (r *SQLiteChannelShareRepository) FindByID(ctx context.Context, id string) (*models.ChannelShare, error) {
+	return r.findOne(ctx, `SELECT `+channelShareSelectColumns+` FROM channel_shares WHERE id = ?`, id)
+}
+
+// FindByHash 依 Token 雜湊值查詢
+func (r *SQLiteChannelShareRepository) FindByHash(ctx context.Context, tokenHash string) (*models.ChannelShare, error) {
+	return r.findOne(ctx, `SELECT `+channelShareSelectColumns+` FROM channel_shares WHERE token_hash = ?`, tokenHash)
+}
+
+// findOne 查詢單筆頻道分享邀請（找不到時回傳 nil）
+func (r *SQLiteChannelShareRepository) findOne(ctx context.Context, query string, args ...interface{}) (*models.ChannelShare, error) {
+	share, err := scanChannelShare(r.getDB().QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return share, err
+}
+
+// ListByChannel 列出頻道的分享邀請（依建立時間由新到舊）
+func (r *SQLiteChannelShareRepository) ListByChannel(ctx context.Context, channelID string) ([]models.ChannelShare, error) {
+	return r.list(ctx, `SELECT `+channelShareSelectColumns+` FROM channel_shares WHERE channel_id = ? ORDER BY created DESC`, channelID)
+}
+
+// ListPendingByEmail 列出寄給 email 且尚未回應、未過期的分享邀請
+func (r *SQLiteChannelShareRepository) ListPendingByEmail(ctx context.Context, email string, now time.Time) ([]models.ChannelShare, error) {
+	return r.list(ctx, `SELECT `+channelShareSelectColumns+` FROM channel_shares
+		WHERE email = ? AND status = ? AND expires_at > ? ORDER BY created`,
+		email, models.ShareStatusPending, now.UTC())
+}
+
+// list 查詢多筆頻道分享邀請
+func (r *SQLiteChannelShareRepository) list(ctx context.Context, query string, args ...interface{}) ([]models.ChannelShare, error) {
+	rows, err := r.getDB().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	shares := []models.ChannelShare{}
+	for rows.Next() {
+		share, err := scanChannelShare(rows)
+		if err != nil {
+			return nil, err
+		}
+		shares = append(shares, *share)
+	}
+	return shares, rows.Err()
+}
+
+// UpdateStatus 將狀態為 fromStatus 的邀請更新為 toStatus，回傳是否有更新（用於避免重複接受）
+func (r *SQLiteChannelShareRepository) UpdateStatus(ctx context.Context, id, fromStatus, toStatus, userID string) (bool, error) {
+	result, err := r.getDB().ExecContext(ctx,
+		`UPDATE channel_shares SET status = ?, user_id = ?, last_modified = ? WHERE id = ? AND status = ?`,
+		toStatus, userID, time.Now(), id, fromStatus)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
@@ -21,34 +21,30 @@ func NewSQLiteChannelRepository(db database.Database) *SQLiteChannelRepository {
 	return &SQLiteChannelRepository{db: db}
 }
 
-// getDB 取得底層 SQL 資料庫連線
-func (r *SQLiteChannelRepository) getDB() *sql.DB {
+// getDB 取得 SQL 資料庫連線（使用 context 中的交易）
+func (r *SQLiteChannelRepository) getDB() *database.SQLConn {
 	sqliteDB := r.db.(*database.SQLiteDatabase)
-	return sqliteDB.GetDB()
+	return sqliteDB.GetConn()
 }
 
-// FindByID 依 ID 查詢頻道
+// FindByID 依 ID 查詢頻道（不含垃圾桶中的頻道）
 func (r *SQLiteChannelRepository) FindByID(ctx context.Context, id string) (*models.Channel, error) {
+	return r.find(ctx, id, "deleted_at IS NULL")
+}
+
+// FindDeleted 依 ID 查詢垃圾桶中的頻道
+func (r *SQLiteChannelRepository) FindDeleted(ctx context.Context, id string) (*models.Channel, error) {
+	return r.find(ctx, id, "deleted_at IS NOT NULL")
+}
+
+// find 依 ID 與額外條件查詢頻道（含所有關聯資料）
+func (r *SQLiteChannelRepository) find(ctx context.Context, id, condition string) (*models.Channel, error) {
 	db := r.getDB()
 
 	// 查詢頻道基本資訊
-	query := `SELECT id, type, name, desc, CAST(contents_seq AS TEXT) as contents_seq, cover_default, created, last_modified 
-	          FROM channels WHERE id = ?`
+	query := fmt.Sprintf(`SELECT %s FROM channels WHERE id = ? AND %s`, channelSelectColumns, condition)
 
-	var channel models.Channel
-	var coverDefault sql.NullString
-	var contentsSeq sql.NullString
-
-	err := db.QueryRowContext(ctx, query, id).Scan(
-		&channel.ID,
-		&channel.Type,
-		&channel.Name,
-		&channel.Desc,
-		&contentsSeq,
-		&coverDefault,
-		&channel.Created,
-		&channel.LastModified,
-	)
+	channel, err := scanChannel(db.QueryRowContext(ctx, query, id))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -56,17 +52,6 @@ func (r *SQLiteChannelRepository) FindByID(ctx context.Context, id string) (*mod
 		return nil, err
 	}
 
-	if coverDefault.Valid {
-		channel.Cover = &models.ChannelCover{Default: coverDefault.String}
-	}
-
-	// 處理 contents_seq
-	if contentsSeq.Valid {
-		channel.ContentsSeq = contentsSeq.String
-	} else {
-		channel.ContentsSeq = ""
-	}
-
 	// 載入 tags
 	tags, err := r.loadTags(ctx, id)
 	if err != nil {
@@ -102,6 +87,72 @@ func (r *SQLiteChannelRepository) FindByID(ctx context.Context, id string) (*mod
 	}
 	channel.ContentsOrder = order
 
+	return channel, nil
+}
+
+// channelSelectColumns channels 表查詢欄位（順序需與 scanChannel 一致）
+const channelSelectColumns = `id, type, name, desc, CAST(contents_seq AS TEXT) as contents_seq, cover_default, schedule_epoch, schedule_loop, hide_unavailable, visibility, deleted_at, revision, forked_from, fork_count, created, last_modified`
+
+// rowScanner 抽象 *sql.Row 與 *sql.Rows 的 Scan
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanChannel 掃描 channels 表的一列資料
+func scanChannel(row rowScanner) (*models.Channel, error) {
+	var channel models.Channel
+	var coverDefault sql.NullString
+	var contentsSeq sql.NullString
+	var scheduleEpoch sql.NullTime
+	var scheduleLoop sql.NullString
+	var deletedAt sql.NullTime
+	var forkedFrom sql.NullString
+
+	if err := row.Scan(
+		&channel.ID,
+		&channel.Type,
+		&channel.Name,
+		&channel.Desc,
+		&contentsSeq,
+		&coverDefault,
+		&scheduleEpoch,
+		&scheduleLoop,
+		&channel.HideUnavailable,
+		&channel.Visibility,
+		&deletedAt,
+		&channel.Revision,
+		&forkedFrom,
+		&channel.ForkCount,
+		&channel.Created,
+		&channel.LastModified,
+	); err != nil {
+		return nil, err
+	}
+
+	if coverDefault.Valid {
+		channel.Cover = &models.ChannelCover{Default: coverDefault.String}
+	}
+
+	// 處理 contents_seq
+	if contentsSeq.Valid {
+		channel.ContentsSeq = contentsSeq.String
+	} else {
+		channel.ContentsSeq = ""
+	}
+
+	if deletedAt.Valid {
+		channel.DeletedAt = &deletedAt.Time
+	}
+	channel.ForkedFrom = forkedFrom.String
+
+	// 處理 schedule
+	if scheduleEpoch.Valid {
+		channel.Schedule = &models.ChannelSchedule{
+			Epoch: scheduleEpoch.Time,
+			Loop:  models.ScheduleLoop(scheduleLoop.String),
+		}
+	}
+
 	return &channel, nil
 }
 
@@ -127,8 +178,19 @@ func (r *SQLiteChannelRepository) Create(ctx context.Context, channel *models.Ch
 		coverDefault = channel.Cover.Default
 	}
 
-	query := `INSERT INTO channels (id, type, name, desc, contents_seq, cover_default, created, last_modified)
-	          VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	var forkedFrom interface{}
+	if channel.ForkedFrom != "" {
+		forkedFrom = channel.ForkedFrom
+	}
+
+	var scheduleEpoch, scheduleLoop interface{}
+	if channel.Schedule != nil {
+		scheduleEpoch = channel.Schedule.Epoch
+		scheduleLoop = string(channel.Schedule.Loop)
+	}
+
+	query := `INSERT INTO channels (id, type, name, desc, contents_seq, cover_default, schedule_epoch, schedule_loop, hide_unavailable, visibility, deleted_at, revision, forked_from, fork_count, created, last_modified)
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
 	_, err = tx.ExecContext(ctx, query,
 		channel.ID,
@@ -137,6 +199,14 @@ func (r *SQLiteChannelRepository) Create(ctx context.Context, channel *models.Ch
 		channel.Desc,
 		channel.ContentsSeq,
 		coverDefault,
+		scheduleEpoch,
+		scheduleLoop,
+		channel.HideUnavailable,
+		channel.Visibility,
+		channel.DeletedAt,
+		channel.Revision,
+		forkedFrom,
+		channel.ForkCount,
 		channel.Created,
 		channel.LastModified,
 	)
@@ -177,15 +247,36 @@ func (r *SQLiteChannelRepository) Create(ctx context.Context, channel *models.Ch
 func (r *SQLiteChannelRepository) Update(ctx context.Context, id string, update map[string]interface{}) error {
 	db := r.getDB()
 
-	// 建立 UPDATE 語句
-	setParts := []string{"last_modified = ?"}
-	args := []interface{}{time.Now()}
+	if len(update) == 0 {
+		return nil
+	}
+
+	// 建立 UPDATE 語句（每次修改都遞增修訂版本）
+	setParts := []string{"last_modified = ?", "revision = revision + ?"}
+	args := []interface{}{time.Now(), database.RevisionIncrement(ctx, id)}
 
 	for key, value := range update {
 		// 處理特殊欄位
 		if key == "cover.default" {
 			setParts = append(setParts, "cover_default = ?")
 			args = append(args, value)
+		} else if key == "cover" {
+			// cover 只保存 cover_default 欄位，nil 表示清除
+			var coverDefault interface{}
+			if cover, ok := value.(*models.ChannelCover); ok && cover != nil {
+				coverDefault = cover.Default
+			}
+			setParts = append(setParts, "cover_default = ?")
+			args = append(args, coverDefault)
+		} else if key == "schedule" {
+			// schedule 拆成 schedule_epoch / schedule_loop 兩個欄位，nil 表示清除
+			var epoch, loop interface{}
+			if schedule, ok := value.(*models.ChannelSchedule); ok && schedule != nil {
+				epoch = schedule.Epoch
+				loop = string(schedule.Loop)
+			}
+			setParts = append(setParts, "schedule_epoch = ?", "schedule_loop = ?")
+			args = append(args, epoch, loop)
 		} else if key == "tags" {
 			// 刪除舊的 tags 並插入新的
 			if _, err := db.ExecContext(ctx, "DELETE FROM channel_tags WHERE channel_id = ?", id); err != nil {
@@ -202,11 +293,6 @@ func (r *SQLiteChannelRepository) Update(ctx context.Context, id string, update
 		}
 	}
 
-	if len(setParts) == 1 {
-		// 只有 last_modified，不需要更新
-		return nil
-	}
-
 	query := fmt.Sprintf("UPDATE channels SET %s WHERE id = ?", strings.Join(setParts, ", "))
 	args = append(args, id)
 
@@ -218,110 +304,20 @@ func (r *SQLiteChannelRepository) Update(ctx context.Context, id string, update
 func (r *SQLiteChannelRepository) ListChannels(ctx context.Context, filter database.Filter, sort database.Sort, limit, skip int64) ([]models.Channel, error) {
 	db := r.getDB()
 
-	// 建立 WHERE 子句
-	whereParts := []string{}
-	args := []interface{}{}
-
-	// 處理過濾條件
+	// 過濾條件轉換為 SQL（不含垃圾桶中的頻道）
+	conditions := database.Filter{"deleted_at": nil}
 	for key, value := range filter {
-		switch key {
-		case "owners":
-			// 使用 EXISTS 子查詢
-			whereParts = append(whereParts, "EXISTS (SELECT 1 FROM channel_owners WHERE channel_id = channels.id AND user_id = ?)")
-			args = append(args, value)
-		case "name":
-			// 處理正則表達式（簡化為 LIKE）
-			if nameFilter, ok := value.(database.Filter); ok {
-				if regex, ok := nameFilter["$regex"].(string); ok {
-					whereParts = append(whereParts, "name LIKE ?")
-					args = append(args, "%"+regex+"%")
-				} else {
-					whereParts = append(whereParts, "name = ?")
-					args = append(args, value)
-				}
-			} else if nameFilter, ok := value.(map[string]interface{}); ok {
-				if regex, ok := nameFilter["$regex"].(string); ok {
-					whereParts = append(whereParts, "name LIKE ?")
-					args = append(args, "%"+regex+"%")
-				} else {
-					whereParts = append(whereParts, "name = ?")
-					args = append(args, value)
-				}
-			} else {
-				whereParts = append(whereParts, "name = ?")
-				args = append(args, value)
-			}
-		case "type":
-			// 處理 $nin 操作（not in）
-			if typeFilter, ok := value.(database.Filter); ok {
-				if ninValues, ok := typeFilter["$nin"].([]string); ok {
-					// 建立 NOT IN 子句
-					placeholders := make([]string, len(ninValues))
-					for i, v := range ninValues {
-						placeholders[i] = "?"
-						args = append(args, v)
-					}
-					whereParts = append(whereParts, fmt.Sprintf("type NOT IN (%s)", strings.Join(placeholders, ", ")))
-				} else {
-					// 預設為等於操作
-					whereParts = append(whereParts, "type = ?")
-					args = append(args, value)
-				}
-			} else if typeFilter, ok := value.(map[string]interface{}); ok {
-				if ninValues, ok := typeFilter["$nin"].([]string); ok {
-					// 建立 NOT IN 子句
-					placeholders := make([]string, len(ninValues))
-					for i, v := range ninValues {
-						placeholders[i] = "?"
-						args = append(args, v)
-					}
-					whereParts = append(whereParts, fmt.Sprintf("type NOT IN (%s)", strings.Join(placeholders, ", ")))
-				} else {
-					// 預設為等於操作
-					whereParts = append(whereParts, "type = ?")
-					args = append(args, value)
-				}
-			} else {
-				// 預設為等於操作
-				whereParts = append(whereParts, "type = ?")
-				args = append(args, value)
-			}
-		case "contents.0":
-			// 處理 has_contents 參數（檢查是否有節目）
-			if contentsFilter, ok := value.(database.Filter); ok {
-				if _, ok := contentsFilter["$exists"]; ok {
-					// 使用 EXISTS 子查詢檢查是否有節目
-					whereParts = append(whereParts, "EXISTS (SELECT 1 FROM channel_program_order WHERE channel_id = channels.id)")
-				}
-			} else if contentsFilter, ok := value.(map[string]interface{}); ok {
-				if _, ok := contentsFilter["$exists"]; ok {
-					// 使用 EXISTS 子查詢檢查是否有節目
-					whereParts = append(whereParts, "EXISTS (SELECT 1 FROM channel_program_order WHERE channel_id = channels.id)")
-				}
-			}
-		default:
-			whereParts = append(whereParts, fmt.Sprintf("%s = ?", key))
-			args = append(args, value)
-		}
+		conditions[key] = value
 	}
-
-	whereClause := ""
-	if len(whereParts) > 0 {
-		whereClause = "WHERE " + strings.Join(whereParts, " AND ")
+	where, args, err := database.SQLiteWhere("channels", conditions)
+	if err != nil {
+		return nil, err
 	}
+	whereClause := "WHERE " + where
 
-	// 建立 ORDER BY 子句
-	orderClause := ""
-	if len(sort) > 0 {
-		orderParts := []string{}
-		for _, s := range sort {
-			order := "ASC"
-			if s.Order < 0 {
-				order = "DESC"
-			}
-			orderParts = append(orderParts, fmt.Sprintf("%s %s", s.Field, order))
-		}
-		orderClause = "ORDER BY " + strings.Join(orderParts, ", ")
+	orderClause, err := database.SQLiteOrderBy("channels", sort)
+	if err != nil {
+		return nil, err
 	}
 
 	// 建立 LIMIT 和 OFFSET 子句
@@ -333,8 +329,7 @@ func (r *SQLiteChannelRepository) ListChannels(ctx context.Context, filter datab
 		}
 	}
 
-	query := fmt.Sprintf(`SELECT id, type, name, desc, CAST(contents_seq AS TEXT) as contents_seq, cover_default, created, last_modified 
-	                      FROM channels %s %s %s`, whereClause, orderClause, limitClause)
+	query := fmt.Sprintf(`SELECT %s FROM channels %s %s %s`, channelSelectColumns, whereClause, orderClause, limitClause)
 
 	rows, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -346,43 +341,92 @@ func (r *SQLiteChannelRepository) ListChannels(ctx context.Context, filter datab
 
 	var channels []models.Channel
 	for rows.Next() {
-		var channel models.Channel
-		var coverDefault sql.NullString
-		var contentsSeq sql.NullString
-
-		if err := rows.Scan(
-			&channel.ID,
-			&channel.Type,
-			&channel.Name,
-			&channel.Desc,
-			&contentsSeq,
-			&coverDefault,
-			&channel.Created,
-			&channel.LastModified,
-		); err != nil {
+		channel, err := scanChannel(rows)
+		if err != nil {
 			return nil, err
 		}
 
-		if coverDefault.Valid {
-			channel.Cover = &models.ChannelCover{Default: coverDefault.String}
-		}
-
-		// 處理 contents_seq
-		if contentsSeq.Valid {
-			channel.ContentsSeq = contentsSeq.String
-		} else {
-			channel.ContentsSeq = ""
-		}
-
 		// 載入關聯資料（可選，根據需求決定是否載入）
 		// 為了效能，這裡暫時不載入，需要時再載入
 
-		channels = append(channels, channel)
+		channels = append(channels, *channel)
 	}
 
 	return channels, rows.Err()
 }
 
+// LoadContents 批次載入 ListChannels 列出的頻道的節目與節目順序
+func (r *SQLiteChannelRepository) LoadContents(ctx context.Context, channels []models.Channel) error {
+	if len(channels) == 0 {
+		return nil
+	}
+
+	db := r.getDB()
+	placeholders := make([]string, len(channels))
+	args := make([]interface{}, len(channels))
+	index := make(map[string]int, len(channels))
+	for i := range channels {
+		placeholders[i] = "?"
+		args[i] = channels[i].ID
+		index[channels[i].ID] = i
+	}
+	in := strings.Join(placeholders, ",")
+
+	rows, err := db.QueryContext(ctx, fmt.Sprintf(
+		`SELECT %s, channel_id FROM programs WHERE channel_id IN (%s) ORDER BY id`, programSelectColumns, in), args...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var programIDs []int
+	for rows.Next() {
+		var channelID string
+		program, err := scanProgram(rows, &channelID)
+		if err != nil {
+			return err
+		}
+		i := index[channelID]
+		channels[i].Contents = append(channels[i].Contents, *program)
+		programIDs = append(programIDs, program.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	_ = rows.Close()
+
+	tagsMap, err := r.loadProgramTagsBatch(ctx, programIDs)
+	if err != nil {
+		return err
+	}
+	for i := range channels {
+		for j := range channels[i].Contents {
+			channels[i].Contents[j].Tags = tagsMap[channels[i].Contents[j].ID]
+		}
+	}
+
+	orderRows, err := db.QueryContext(ctx, fmt.Sprintf(
+		`SELECT channel_id, program_id FROM channel_program_order WHERE channel_id IN (%s) ORDER BY channel_id, order_index`, in), args...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = orderRows.Close()
+	}()
+	for orderRows.Next() {
+		var channelID string
+		var programID int
+		if err := orderRows.Scan(&channelID, &programID); err != nil {
+			return err
+		}
+		i := index[channelID]
+		channels[i].ContentsOrder = append(channels[i].ContentsOrder, programID)
+	}
+	return orderRows.Err()
+}
+
 // IsAdmin 檢查使用者是否為頻道管理員
 func (r *SQLiteChannelRepository) IsAdmin(ctx context.Context, channelID, userID string) (bool, error) {
 	db := r.getDB()
@@ -390,16 +434,81 @@ func (r *SQLiteChannelRepository) IsAdmin(ctx context.Context, channelID, userID
 		SELECT 1 FROM channel_owners WHERE channel_id = ? AND user_id = ?
 		UNION
 		SELECT 1 FROM channel_permissions WHERE channel_id = ? AND user_id = ? AND admin = 1
-	)`
+	) WHERE EXISTS (SELECT 1 FROM channels WHERE id = ? AND deleted_at IS NULL)`
 
 	var count int64
-	err := db.QueryRowContext(ctx, query, channelID, userID, channelID, userID).Scan(&count)
+	err := db.QueryRowContext(ctx, query, channelID, userID, channelID, userID, channelID).Scan(&count)
 	if err != nil {
 		return false, err
 	}
 	return count > 0, nil
 }
 
+// GetPermission 取得使用者對頻道的權限（擁有者具有所有權限）
+func (r *SQLiteChannelRepository) GetPermission(ctx context.Context, channelID, userID string) (models.ChannelPermission, error) {
+	db := r.getDB()
+	permission := models.ChannelPermission{UserID: userID}
+
+	// 垃圾桶中的頻道沒有任何權限
+	var active int64
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM channels WHERE id = ? AND deleted_at IS NULL`, channelID).Scan(&active); err != nil {
+		return permission, err
+	}
+	if active == 0 {
+		return permission, nil
+	}
+
+	var owners int64
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM channel_owners WHERE channel_id = ? AND user_id = ?`, channelID, userID).Scan(&owners); err != nil {
+		return permission, err
+	}
+	if owners > 0 {
+		permission.Admin, permission.Read, permission.Write = true, true, true
+		return permission, nil
+	}
+
+	var admin, read, write int
+	err := db.QueryRowContext(ctx, `SELECT admin, read, write FROM channel_permissions WHERE channel_id = ? AND user_id = ?`, channelID, userID).Scan(&admin, &read, &write)
+	if err == sql.ErrNoRows {
+		return permission, nil
+	}
+	if err != nil {
+		return permission, err
+	}
+	permission.Admin = admin != 0
+	permission.Read = read != 0
+	permission.Write = write != 0
+	return permission, nil
+}
+
+// SetPermission 設定使用者對頻道的權限（所有權限皆為 false 時移除）
+func (r *SQLiteChannelRepository) SetPermission(ctx context.Context, channelID string, permission models.ChannelPermission) error {
+	db := r.getDB()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM channel_permissions WHERE channel_id = ? AND user_id = ?`, channelID, permission.UserID); err != nil {
+		return err
+	}
+	if permission.Admin || permission.Read || permission.Write {
+		if err := r.insertPermissionsTx(ctx, tx, channelID, []models.ChannelPermission{permission}); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE channels SET last_modified = ?, revision = revision + ? WHERE id = ?", time.Now(), database.RevisionIncrement(ctx, channelID), channelID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
 // AddOwners 新增擁有者
 func (r *SQLiteChannelRepository) AddOwners(ctx context.Context, channelID string, userIDs []string) error {
 	db := r.getDB()
@@ -413,22 +522,254 @@ func (r *SQLiteChannelRepository) AddOwners(ctx context.Context, channelID strin
 		_ = tx.Rollback()
 	}()
 
-	// 插入擁有者
-	query := `INSERT OR IGNORE INTO channel_owners (channel_id, user_id) VALUES (?, ?)`
+	// 插入擁有者（同時更新使用者的 own_channels）
 	for _, userID := range userIDs {
-		if _, err := tx.ExecContext(ctx, query, channelID, userID); err != nil {
+		if err := addOwnerTx(ctx, tx, channelID, userID); err != nil {
 			return err
 		}
 	}
 
 	// 更新 last_modified
-	if _, err := tx.ExecContext(ctx, "UPDATE channels SET last_modified = ? WHERE id = ?", time.Now(), channelID); err != nil {
+	if _, err := tx.ExecContext(ctx, "UPDATE channels SET last_modified = ?, revision = revision + ? WHERE id = ?", time.Now(), database.RevisionIncrement(ctx, channelID), channelID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RemoveOwner 移除擁有者（同時更新使用者的 own_channels），不能移除最後一位擁有者
+func (r *SQLiteChannelRepository) RemoveOwner(ctx context.Context, channelID, userID string) error {
+	tx, err := r.getDB().BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err := removeOwnerTx(ctx, tx, channelID, userID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE channels SET last_modified = ?, revision = revision + ? WHERE id = ?", time.Now(), database.RevisionIncrement(ctx, channelID), channelID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// TransferOwnership 將擁有權從 fromUserID 移轉給 toUserID
+func (r *SQLiteChannelRepository) TransferOwnership(ctx context.Context, channelID, fromUserID, toUserID string) error {
+	tx, err := r.getDB().BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	// 先加入新的擁有者，移除原擁有者時才不會變成沒有擁有者
+	if err := addOwnerTx(ctx, tx, channelID, toUserID); err != nil {
+		return err
+	}
+	if err := removeOwnerTx(ctx, tx, channelID, fromUserID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE channels SET last_modified = ?, revision = revision + ? WHERE id = ?", time.Now(), database.RevisionIncrement(ctx, channelID), channelID); err != nil {
 		return err
 	}
 
 	return tx.Commit()
 }
 
+// addOwnerTx 在交易中新增擁有者與使用者的頻道關聯
+func addOwnerTx(ctx context.Context, tx *database.SQLTx, channelID, userID string) error {
+	if _, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO channel_owners (channel_id, user_id) VALUES (?, ?)`, channelID, userID); err != nil {
+		return err
+	}
+	_, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO user_channels (user_id, channel_id) VALUES (?, ?)`, userID, channelID)
+	return err
+}
+
+// removeOwnerTx 在交易中移除擁有者與使用者的頻道關聯（不是擁有者時回傳 ErrNoDocuments）
+func removeOwnerTx(ctx context.Context, tx *database.SQLTx, channelID, userID string) error {
+	var owners, isOwner int64
+	err := tx.QueryRowContext(ctx,
+		`SELECT COUNT(*), COALESCE(SUM(user_id = ?), 0) FROM channel_owners WHERE channel_id = ?`,
+		userID, channelID).Scan(&owners, &isOwner)
+	if err != nil {
+		return err
+	}
+	if isOwner == 0 {
+		return database.ErrNoDocuments
+	}
+	if owners <= 1 {
+		return database.ErrLastOwner
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM channel_owners WHERE channel_id = ? AND user_id = ?`, channelID, userID); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `DELETE FROM user_channels WHERE user_id = ? AND channel_id = ?`, userID, channelID)
+	return err
+}
+
+// GetRevision 取得頻道目前的修訂版本
+func (r *SQLiteChannelRepository) GetRevision(ctx context.Context, channelID string) (int64, error) {
+	var revision int64
+	err := r.getDB().QueryRowContext(ctx, `SELECT revision FROM channels WHERE id = ?`, channelID).Scan(&revision)
+	if err == sql.ErrNoRows {
+		return 0, database.ErrNoDocuments
+	}
+	return revision, err
+}
+
+// ClaimRevision 比對並遞增頻道修訂版本（不符或頻道在垃圾桶中時回傳 ErrRevisionMismatch），確保同時修改時只有一方成功
+// 需與修改頻道的操作在同一個交易中呼叫，並以 database.WithClaimedRevision 標示後續修改不再遞增
+func (r *SQLiteChannelRepository) ClaimRevision(ctx context.Context, channelID string, revision int64) error {
+	result, err := r.getDB().ExecContext(ctx,
+		`UPDATE channels SET revision = revision + 1 WHERE id = ? AND revision = ? AND deleted_at IS NULL`, channelID, revision)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return database.ErrRevisionMismatch
+	}
+	return nil
+}
+
+// IncrementForkCount 遞增頻道被複製的次數（不視為修改頻道，不遞增修訂版本）
+func (r *SQLiteChannelRepository) IncrementForkCount(ctx context.Context, channelID string) error {
+	_, err := r.getDB().ExecContext(ctx, `UPDATE channels SET fork_count = fork_count + 1 WHERE id = ?`, channelID)
+	return err
+}
+
+// SoftDelete 將頻道移到垃圾桶（時間以 UTC 儲存，確保字串比較與時間先後一致）
+func (r *SQLiteChannelRepository) SoftDelete(ctx context.Context, id string, deletedAt time.Time) error {
+	result, err := r.getDB().ExecContext(ctx, `UPDATE channels SET deleted_at = ?, revision = revision + ? WHERE id = ? AND deleted_at IS NULL`, deletedAt.UTC(), database.RevisionIncrement(ctx, id), id)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return database.ErrNoDocuments
+	}
+	return nil
+}
+
+// Restore 將頻道從垃圾桶還原
+func (r *SQLiteChannelRepository) Restore(ctx context.Context, id string) error {
+	result, err := r.getDB().ExecContext(ctx, `UPDATE channels SET deleted_at = NULL, last_modified = ?, revision = revision + ? WHERE id = ? AND deleted_at IS NOT NULL`, time.Now(), database.RevisionIncrement(ctx, id), id)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return database.ErrNoDocuments
+	}
+	return nil
+}
+
+// ListDeleted 列出使用者擁有且在垃圾桶中的頻道（依刪除時間由新到舊）
+func (r *SQLiteChannelRepository) ListDeleted(ctx context.Context, userID string) ([]models.Channel, error) {
+	query := fmt.Sprintf(`SELECT %s FROM channels
+		WHERE deleted_at IS NOT NULL
+		AND EXISTS (SELECT 1 FROM channel_owners WHERE channel_id = channels.id AND user_id = ?)
+		ORDER BY deleted_at DESC`, channelSelectColumns)
+
+	rows, err := r.getDB().QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	channels := []models.Channel{}
+	for rows.Next() {
+		channel, err := scanChannel(rows)
+		if err != nil {
+			return nil, err
+		}
+		channels = append(channels, *channel)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range channels {
+		owners, err := r.loadOwners(ctx, channels[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		channels[i].Owners = owners
+	}
+	return channels, nil
+}
+
+// ListExpired 列出在 before 之前移到垃圾桶的頻道 ID
+func (r *SQLiteChannelRepository) ListExpired(ctx context.Context, before time.Time, limit int64) ([]string, error) {
+	rows, err := r.getDB().QueryContext(ctx,
+		`SELECT id FROM channels WHERE deleted_at IS NOT NULL AND deleted_at <= ? ORDER BY deleted_at LIMIT ?`,
+		before.UTC(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// Purge 永久刪除頻道，同時刪除節目、標籤、節目順序、擁有者、權限與分享邀請
+func (r *SQLiteChannelRepository) Purge(ctx context.Context, id string) error {
+	tx, err := r.getDB().BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	statements := []string{
+		`DELETE FROM program_tags WHERE program_id IN (SELECT id FROM programs WHERE channel_id = ?)`,
+		`DELETE FROM channel_program_order WHERE channel_id = ?`,
+		`DELETE FROM programs WHERE channel_id = ?`,
+		`DELETE FROM channel_tags WHERE channel_id = ?`,
+		`DELETE FROM channel_permissions WHERE channel_id = ?`,
+		`DELETE FROM channel_owners WHERE channel_id = ?`,
+		`DELETE FROM user_channels WHERE channel_id = ?`,
+		`DELETE FROM channel_shares WHERE channel_id = ?`,
+		`DELETE FROM channel_revisions WHERE channel_id = ?`,
+		`DELETE FROM channels WHERE id = ?`,
+	}
+	for _, statement := range statements {
+		if _, err := tx.ExecContext(ctx, statement, id); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
 // 輔助方法：載入 tags
 func (r *SQLiteChannelRepository) loadTags(ctx context.Context, channelID string) ([]int, error) {
 	db := r.getDB()
@@ -511,8 +852,7 @@ func (r *SQLiteChannelRepository) loadPermissions(ctx context.Context, channelID
 // 輔助方法：載入 programs
 func (r *SQLiteChannelRepository) loadPrograms(ctx context.Context, channelID string) ([]models.Program, error) {
 	db := r.getDB()
-	query := `SELECT id, name, desc, duration, type, youtube_id, created, last_modified 
-	          FROM programs WHERE channel_id = ? ORDER BY id`
+	query := `SELECT ` + programSelectColumns + ` FROM programs WHERE channel_id = ? ORDER BY id`
 
 	rows, err := db.QueryContext(ctx, query, channelID)
 	if err != nil {
@@ -525,21 +865,11 @@ func (r *SQLiteChannelRepository) loadPrograms(ctx context.Context, channelID st
 	var programs []models.Program
 	var programIDs []int
 	for rows.Next() {
-		var program models.Program
-		if err := rows.Scan(
-			&program.ID,
-			&program.Name,
-			&program.Desc,
-			&program.Duration,
-			&program.Type,
-			&program.YouTubeID,
-			&program.Created,
-			&program.LastModified,
-		); err != nil {
+		program, err := scanProgram(rows)
+		if err != nil {
 			return nil, err
 		}
-
-		programs = append(programs, program)
+		programs = append(programs, *program)
 		programIDs = append(programIDs, program.ID)
 	}
 
@@ -562,6 +892,44 @@ func (r *SQLiteChannelRepository) loadPrograms(ctx context.Context, channelID st
 	return programs, nil
 }
 
+// programSelectColumns programs 表查詢欄位（順序需與 scanProgram 一致）
+const programSelectColumns = `id, name, desc, duration, type, youtube_id, video_id, metadata_at, availability, last_checked, created, last_modified`
+
+// scanProgram 掃描 programs 表的一列資料（extra 為查詢欄位之後的其他欄位）
+func scanProgram(row rowScanner, extra ...interface{}) (*models.Program, error) {
+	var program models.Program
+	var videoID sql.NullString
+	var metadataAt sql.NullTime
+	var availability sql.NullString
+	var lastChecked sql.NullTime
+	dest := []interface{}{
+		&program.ID,
+		&program.Name,
+		&program.Desc,
+		&program.Duration,
+		&program.Type,
+		&program.YouTubeID,
+		&videoID,
+		&metadataAt,
+		&availability,
+		&lastChecked,
+		&program.Created,
+		&program.LastModified,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	program.VideoID = videoID.String
+	if metadataAt.Valid {
+		program.MetadataAt = &metadataAt.Time
+	}
+	program.Availability = models.Availability(availability.String)
+	if lastChecked.Valid {
+		program.LastChecked = &lastChecked.Time
+	}
+	return &program, nil
+}
+
 // 輔助方法：批量載入 program tags
 func (r *SQLiteChannelRepository) loadProgramTagsBatch(ctx context.Context, programIDs []int) (map[int][]int, error) {
 	if len(programIDs) == 0 {
@@ -628,7 +996,7 @@ func (r *SQLiteChannelRepository) loadContentsOrder(ctx context.Context, channel
 }
 
 // 輔助方法：插入 tags（交易版本）
-func (r *SQLiteChannelRepository) insertTagsTx(ctx context.Context, tx *sql.Tx, channelID string, tags []int) error {
+func (r *SQLiteChannelRepository) insertTagsTx(ctx context.Context, tx *database.SQLTx, channelID string, tags []int) error {
 	if len(tags) == 0 {
 		return nil
 	}
@@ -654,7 +1022,7 @@ func (r *SQLiteChannelRepository) insertTags(ctx context.Context, channelID stri
 }
 
 // 輔助方法：插入 owners（交易版本）
-func (r *SQLiteChannelRepository) insertOwnersTx(ctx context.Context, tx *sql.Tx, channelID string, owners []string) error {
+func (r *SQLiteChannelRepository) insertOwnersTx(ctx context.Context, tx *database.SQLTx, channelID string, owners []string) error {
 	if len(owners) == 0 {
 		return nil
 	}
@@ -674,7 +1042,7 @@ func (r *SQLiteChannelRepository) insertOwnersTx(ctx context.Context, tx *sql.Tx
 }
 
 // 輔助方法：插入 permissions（交易版本）
-func (r *SQLiteChannelRepository) insertPermissionsTx(ctx context.Context, tx *sql.Tx, channelID string, permissions []models.ChannelPermission) error {
+func (r *SQLiteChannelRepository) insertPermissionsTx(ctx context.Context, tx *database.SQLTx, channelID string, permissions []models.ChannelPermission) error {
 	if len(permissions) == 0 {
 		return nil
 	}
@@ -706,7 +1074,7 @@ func (r *SQLiteChannelRepository) insertPermissionsTx(ctx context.Context, tx *s
 }
 
 // 輔助方法：插入 contents_order（交易版本）
-func (r *SQLiteChannelRepository) insertContentsOrderTx(ctx context.Context, tx *sql.Tx, channelID string, order []int) error {
+func (r *SQLiteChannelRepository) insertContentsOrderTx(ctx context.Context, tx *database.SQLTx, channelID string, order []int) error {
 	if len(order) == 0 {
 		return nil
 	}
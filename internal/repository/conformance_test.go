@@ -0,0 +1,891 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/higgstv/higgstv-go/internal/config"
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/migration"
+	"github.com/higgstv/higgstv-go/internal/models"
+	"github.com/higgstv/higgstv-go/internal/repository"
+	"github.com/higgstv/higgstv-go/pkg/session"
+)
+
+// 所有資料庫後端都必須通過的 Repository 共用測試
+// 記憶體資料庫與 SQLite 一定會執行；設定的資料庫類型為 mongodb 或 postgres 時（HIGGSTV_DATABASE_TYPE）一併執行
+
+// conformanceBackend 測試用的資料庫後端
+type conformanceBackend struct {
+	name string
+	open func(t *testing.T) database.Database
+}
+
+// conformanceBackends 列出要測試的資料庫後端
+func conformanceBackends(t *testing.T) []conformanceBackend {
+	backends := []conformanceBackend{
+		{name: "memory", open: func(t *testing.T) database.Database {
+			return openConformanceDB(t, database.DatabaseConfig{Type: database.DatabaseTypeMemory})
+		}},
+		{name: "sqlite", open: func(t *testing.T) database.Database {
+			return openConformanceDB(t, database.DatabaseConfig{
+				Type: database.DatabaseTypeSQLite,
+				URI:  "file::memory:?cache=private",
+			})
+		}},
+	}
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	dbType, err := database.ParseDatabaseType(cfg.Database.Type)
+	if err != nil || (dbType != database.DatabaseTypeMongoDB && dbType != database.DatabaseTypePostgres) {
+		return backends
+	}
+
+	return append(backends, conformanceBackend{name: string(dbType), open: func(t *testing.T) database.Database {
+		// 每個測試使用獨立的資料庫（PostgreSQL 為 schema），開始前與結束後刪除
+		name := strings.ToLower(cfg.Database.Database + "_conformance_" + sanitizeTestName(t.Name()))
+		dbCfg := database.DatabaseConfig{Type: dbType, URI: cfg.Database.URI, Database: name}
+		dropConformanceDB(t, dbCfg)
+		db := openConformanceDB(t, dbCfg)
+		t.Cleanup(func() { dropConformanceDB(t, dbCfg) })
+		return db
+	}})
+}
+
+// openConformanceDB 建立資料庫連線、執行遷移與建立索引，測試結束時關閉
+func openConformanceDB(t *testing.T, cfg database.DatabaseConfig) database.Database {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	db, err := database.NewDatabase(ctx, cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close(context.Background()) })
+
+	require.NoError(t, migration.RunMigrations(ctx, db))
+	require.NoError(t, database.EnsureIndexes(ctx, db))
+	return db
+}
+
+// dropConformanceDB 刪除測試用的資料庫（MongoDB 刪除資料庫，PostgreSQL 刪除 schema 與其中所有資料表）
+// 之後由 openConformanceDB 執行遷移重新建立，不需要維護資料表清單
+func dropConformanceDB(t *testing.T, cfg database.DatabaseConfig) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	db, err := database.NewDatabase(ctx, cfg)
+	require.NoError(t, err)
+	defer func() { _ = db.Close(context.Background()) }()
+
+	switch d := db.(type) {
+	case *database.MongoDBDatabase:
+		require.NoError(t, d.GetDatabase().Drop(ctx))
+	case *database.PostgresDatabase:
+		_, err := d.GetDB().ExecContext(ctx, "DROP SCHEMA IF EXISTS "+pgx.Identifier{cfg.Database}.Sanitize()+" CASCADE")
+		require.NoError(t, err)
+	}
+}
+
+// sanitizeTestName 清理測試名稱（作為資料庫名稱）
+func sanitizeTestName(name string) string {
+	name = strings.TrimPrefix(name, "Test")
+	name = strings.NewReplacer("/", "_", " ", "_", "-", "_").Replace(name)
+	if len(name) > 40 {
+		name = name[len(name)-40:]
+	}
+	return name
+}
+
+// runConformance 對每個資料庫後端執行 fn（每次使用新的資料庫）
+func runConformance(t *testing.T, fn func(t *testing.T, db database.Database)) {
+	for _, backend := range conformanceBackends(t) {
+		backend := backend
+		t.Run(backend.name, func(t *testing.T) {
+			fn(t, backend.open(t))
+		})
+	}
+}
+
+// createConformanceUser 建立測試使用者
+func createConformanceUser(t *testing.T, db database.Database, id string) *models.User {
+	user := &models.User{
+		ID:       id,
+		Username: id,
+		Email:    id + "@example.com",
+		Password: "hashed-" + id,
+	}
+	require.NoError(t, repository.NewUserRepository(db).Create(context.Background(), user))
+	return user
+}
+
+// createConformanceChannel 建立測試頻道（擁有者需先建立）
+func createConformanceChannel(t *testing.T, db database.Database, id, name string, owners ...string) *models.Channel {
+	channel := &models.Channel{
+		ID:         id,
+		Type:       models.ChannelTypeDefault,
+		Name:       name,
+		Desc:       "desc of " + name,
+		Tags:       []int{1, 2},
+		Owners:     owners,
+		Visibility: models.ChannelVisibilityPublic,
+	}
+	require.NoError(t, repository.NewChannelRepository(db).Create(context.Background(), channel))
+	return channel
+}
+
+// channelIDs 取得頻道 ID 列表
+func channelIDs(channels []models.Channel) []string {
+	ids := []string{}
+	for _, channel := range channels {
+		ids = append(ids, channel.ID)
+	}
+	return ids
+}
+
+func TestConformance_UserRepository(t *testing.T) {
+	ctx := context.Background()
+
+	runConformance(t, func(t *testing.T, db database.Database) {
+		userRepo := repository.NewUserRepository(db)
+
+		t.Run("找不到使用者回傳 nil", func(t *testing.T) {
+			user, err := userRepo.FindByUsername(ctx, "nobody")
+			assert.NoError(t, err)
+			assert.Nil(t, user)
+			user, err = userRepo.FindByEmail(ctx, "nobody@example.com")
+			assert.NoError(t, err)
+			assert.Nil(t, user)
+			user, err = userRepo.FindByID(ctx, "nobody")
+			assert.NoError(t, err)
+			assert.Nil(t, user)
+		})
+
+		createConformanceUser(t, db, "alice")
+		createConformanceUser(t, db, "bob")
+
+		t.Run("建立與查詢", func(t *testing.T) {
+			for _, find := range []func() (*models.User, error){
+				func() (*models.User, error) { return userRepo.FindByUsername(ctx, "alice") },
+				func() (*models.User, error) { return userRepo.FindByEmail(ctx, "alice@example.com") },
+				func() (*models.User, error) { return userRepo.FindByID(ctx, "alice") },
+			} {
+				user, err := find()
+				require.NoError(t, err)
+				require.NotNil(t, user)
+				assert.Equal(t, "alice", user.ID)
+				assert.Equal(t, "alice", user.Username)
+				assert.Equal(t, "alice@example.com", user.Email)
+				assert.Equal(t, "hashed-alice", user.Password)
+				assert.Empty(t, user.OwnChannels)
+				assert.Nil(t, user.UnclassifiedChannel)
+				assert.Nil(t, user.AccessKey)
+				assert.False(t, user.Created.IsZero())
+			}
+		})
+
+		t.Run("使用者名稱重複", func(t *testing.T) {
+			err := userRepo.Create(ctx, &models.User{ID: "alice2", Username: "alice", Email: "alice2@example.com"})
+			assert.Error(t, err)
+		})
+
+		t.Run("檢查是否存在", func(t *testing.T) {
+			exists, err := userRepo.Exists(ctx, "alice", "other@example.com")
+			assert.NoError(t, err)
+			assert.True(t, exists)
+			exists, err = userRepo.Exists(ctx, "other", "bob@example.com")
+			assert.NoError(t, err)
+			assert.True(t, exists)
+			exists, err = userRepo.Exists(ctx, "other", "other@example.com")
+			assert.NoError(t, err)
+			assert.False(t, exists)
+		})
+
+		t.Run("更新密碼", func(t *testing.T) {
+			require.NoError(t, userRepo.UpdatePassword(ctx, "bob", "new-hash"))
+			user, err := userRepo.FindByID(ctx, "bob")
+			require.NoError(t, err)
+			assert.Equal(t, "new-hash", user.Password)
+		})
+
+		t.Run("使用 access_key 重設密碼", func(t *testing.T) {
+			changed, err := userRepo.ChangePasswordWithAccessKey(ctx, "alice@example.com", "key", "reset-hash")
+			assert.NoError(t, err)
+			assert.False(t, changed, "尚未設定 access_key")
+
+			require.NoError(t, userRepo.SetAccessKey(ctx, "alice@example.com", "key"))
+			changed, err = userRepo.ChangePasswordWithAccessKey(ctx, "alice@example.com", "wrong", "reset-hash")
+			assert.NoError(t, err)
+			assert.False(t, changed)
+
+			changed, err = userRepo.ChangePasswordWithAccessKey(ctx, "alice@example.com", "key", "reset-hash")
+			assert.NoError(t, err)
+			assert.True(t, changed)
+
+			user, err := userRepo.FindByEmail(ctx, "alice@example.com")
+			require.NoError(t, err)
+			assert.Equal(t, "reset-hash", user.Password)
+			assert.Nil(t, user.AccessKey)
+
+			changed, err = userRepo.ChangePasswordWithAccessKey(ctx, "alice@example.com", "key", "again")
+			assert.NoError(t, err)
+			assert.False(t, changed, "access_key 只能使用一次")
+		})
+
+		t.Run("新增頻道與設定未分類頻道", func(t *testing.T) {
+			createConformanceChannel(t, db, "ch-1", "Channel 1", "alice")
+			createConformanceChannel(t, db, "ch-2", "Channel 2", "alice")
+			require.NoError(t, userRepo.AddChannel(ctx, "alice", "ch-1"))
+			require.NoError(t, userRepo.AddChannel(ctx, "alice", "ch-1"))
+			require.NoError(t, userRepo.AddChannel(ctx, "alice", "ch-2"))
+			require.NoError(t, userRepo.SetUnclassifiedChannel(ctx, "alice", "ch-2"))
+
+			user, err := userRepo.FindByUsername(ctx, "alice")
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []string{"ch-1", "ch-2"}, user.OwnChannels)
+			require.NotNil(t, user.UnclassifiedChannel)
+			assert.Equal(t, "ch-2", *user.UnclassifiedChannel)
+		})
+
+		t.Run("使用者不存在時新增頻道", func(t *testing.T) {
+			assert.True(t, database.IsNotFound(userRepo.AddChannel(ctx, "nobody", "ch-1")))
+			assert.True(t, database.IsNotFound(userRepo.SetUnclassifiedChannel(ctx, "nobody", "ch-1")))
+		})
+
+		t.Run("取得使用者基本資訊", func(t *testing.T) {
+			infos, err := userRepo.GetUsersBasicInfo(ctx, []string{"alice", "bob", "nobody"})
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []models.UserBasicInfo{
+				{ID: "alice", Username: "alice", Email: "alice@example.com"},
+				{ID: "bob", Username: "bob", Email: "bob@example.com"},
+			}, infos)
+
+			infos, err = userRepo.GetUsersBasicInfo(ctx, []string{})
+			assert.NoError(t, err)
+			assert.Empty(t, infos)
+		})
+	})
+}
+
+func TestConformance_ChannelRepository(t *testing.T) {
+	ctx := context.Background()
+
+	runConformance(t, func(t *testing.T, db database.Database) {
+		channelRepo := repository.NewChannelRepository(db)
+		userRepo := repository.NewUserRepository(db)
+		for _, id := range []string{"alice", "bob", "carol"} {
+			createConformanceUser(t, db, id)
+		}
+
+		t.Run("找不到頻道回傳 nil", func(t *testing.T) {
+			channel, err := channelRepo.FindByID(ctx, "missing")
+			assert.NoError(t, err)
+			assert.Nil(t, channel)
+			channel, err = channelRepo.FindDeleted(ctx, "missing")
+			assert.NoError(t, err)
+			assert.Nil(t, channel)
+		})
+
+		t.Run("建立與查詢", func(t *testing.T) {
+			createConformanceChannel(t, db, "ch-create", "Create", "alice")
+			channel, err := channelRepo.FindByID(ctx, "ch-create")
+			require.NoError(t, err)
+			require.NotNil(t, channel)
+			assert.Equal(t, "Create", channel.Name)
+			assert.Equal(t, "desc of Create", channel.Desc)
+			assert.Equal(t, models.ChannelTypeDefault, channel.Type)
+			assert.Equal(t, models.ChannelVisibilityPublic, channel.Visibility)
+			assert.ElementsMatch(t, []int{1, 2}, channel.Tags)
+			assert.Equal(t, []string{"alice"}, channel.Owners)
+			assert.Empty(t, channel.Contents)
+			assert.Equal(t, int64(0), channel.Revision)
+		})
+
+		t.Run("更新頻道遞增修訂版本", func(t *testing.T) {
+			createConformanceChannel(t, db, "ch-update", "Update", "alice")
+			require.NoError(t, channelRepo.Update(ctx, "ch-update", map[string]interface{}{
+				"name":          "Updated",
+				"desc":          "new desc",
+				"tags":          []int{3},
+				"visibility":    string(models.ChannelVisibilityUnlisted),
+				"cover.default": "https://example.com/cover.jpg",
+			}))
+
+			channel, err := channelRepo.FindByID(ctx, "ch-update")
+			require.NoError(t, err)
+			assert.Equal(t, "Updated", channel.Name)
+			assert.Equal(t, "new desc", channel.Desc)
+			assert.Equal(t, []int{3}, channel.Tags)
+			assert.Equal(t, models.ChannelVisibilityUnlisted, channel.Visibility)
+			require.NotNil(t, channel.Cover)
+			assert.Equal(t, "https://example.com/cover.jpg", channel.Cover.Default)
+			assert.Equal(t, int64(1), channel.Revision)
+		})
+
+		t.Run("列出頻道", func(t *testing.T) {
+			createConformanceChannel(t, db, "ch-list-a", "List Apple", "bob")
+			createConformanceChannel(t, db, "ch-list-b", "List Banana", "bob")
+			unclassified := createConformanceChannel(t, db, "ch-list-c", "List Cherry", "bob")
+			require.NoError(t, channelRepo.Update(ctx, unclassified.ID, map[string]interface{}{
+				"type": string(models.ChannelTypeUnclassified),
+			}))
+			require.NoError(t, channelRepo.Update(ctx, "ch-list-b", map[string]interface{}{"tags": []int{7}}))
+			createConformanceChannel(t, db, "ch-list-deleted", "List Deleted", "bob")
+			require.NoError(t, channelRepo.SoftDelete(ctx, "ch-list-deleted", time.Now()))
+
+			byName := database.Sort{{Field: "name", Order: 1}}
+			channels, err := channelRepo.ListChannels(ctx, database.Filter{"owners": "bob"}, byName, 0, 0)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"ch-list-a", "ch-list-b", "ch-list-c"}, channelIDs(channels))
+
+			channels, err = channelRepo.ListChannels(ctx, database.Filter{"owners": "bob"}, database.Sort{{Field: "name", Order: -1}}, 2, 1)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"ch-list-b", "ch-list-a"}, channelIDs(channels))
+
+			channels, err = channelRepo.ListChannels(ctx, database.Filter{
+				"owners": "bob",
+				"type":   database.Filter{"$nin": []string{string(models.ChannelTypeUnclassified)}},
+			}, byName, 0, 0)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"ch-list-a", "ch-list-b"}, channelIDs(channels))
+
+			channels, err = channelRepo.ListChannels(ctx, database.Filter{
+				"owners": "bob",
+				"name":   database.Filter{"$regex": "banana", "$options": "i"},
+			}, byName, 0, 0)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"ch-list-b"}, channelIDs(channels))
+
+			channels, err = channelRepo.ListChannels(ctx, database.Filter{
+				"owners": "bob",
+				"tags":   database.Filter{"$in": []int{7, 8}},
+			}, byName, 0, 0)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"ch-list-b"}, channelIDs(channels))
+
+			channels, err = channelRepo.ListChannels(ctx, database.Filter{"owners": "nobody"}, byName, 0, 0)
+			require.NoError(t, err)
+			assert.Empty(t, channels)
+		})
+
+		t.Run("權限", func(t *testing.T) {
+			createConformanceChannel(t, db, "ch-perm", "Permission", "alice")
+
+			permission, err := channelRepo.GetPermission(ctx, "ch-perm", "alice")
+			require.NoError(t, err)
+			assert.Equal(t, models.ChannelPermission{UserID: "alice", Admin: true, Read: true, Write: true}, permission)
+
+			permission, err = channelRepo.GetPermission(ctx, "ch-perm", "bob")
+			require.NoError(t, err)
+			assert.Equal(t, models.ChannelPermission{UserID: "bob"}, permission)
+
+			require.NoError(t, channelRepo.SetPermission(ctx, "ch-perm", models.ChannelPermission{UserID: "bob", Read: true}))
+			permission, err = channelRepo.GetPermission(ctx, "ch-perm", "bob")
+			require.NoError(t, err)
+			assert.Equal(t, models.ChannelPermission{UserID: "bob", Read: true}, permission)
+			isAdmin, err := channelRepo.IsAdmin(ctx, "ch-perm", "bob")
+			require.NoError(t, err)
+			assert.False(t, isAdmin)
+
+			require.NoError(t, channelRepo.SetPermission(ctx, "ch-perm", models.ChannelPermission{UserID: "bob", Admin: true}))
+			isAdmin, err = channelRepo.IsAdmin(ctx, "ch-perm", "bob")
+			require.NoError(t, err)
+			assert.True(t, isAdmin)
+			isAdmin, err = channelRepo.IsAdmin(ctx, "ch-perm", "alice")
+			require.NoError(t, err)
+			assert.True(t, isAdmin)
+
+			require.NoError(t, channelRepo.SetPermission(ctx, "ch-perm", models.ChannelPermission{UserID: "bob"}))
+			channel, err := channelRepo.FindByID(ctx, "ch-perm")
+			require.NoError(t, err)
+			assert.Empty(t, channel.Permission)
+			assert.Equal(t, int64(3), channel.Revision)
+		})
+
+		t.Run("擁有者", func(t *testing.T) {
+			createConformanceChannel(t, db, "ch-owner", "Owner", "alice")
+			require.NoError(t, userRepo.AddChannel(ctx, "alice", "ch-owner"))
+
+			require.NoError(t, channelRepo.AddOwners(ctx, "ch-owner", []string{"bob"}))
+			channel, err := channelRepo.FindByID(ctx, "ch-owner")
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []string{"alice", "bob"}, channel.Owners)
+			bob, err := userRepo.FindByID(ctx, "bob")
+			require.NoError(t, err)
+			assert.Contains(t, bob.OwnChannels, "ch-owner")
+
+			err = channelRepo.RemoveOwner(ctx, "ch-owner", "carol")
+			assert.True(t, database.IsNotFound(err), "不是擁有者：%v", err)
+
+			require.NoError(t, channelRepo.RemoveOwner(ctx, "ch-owner", "alice"))
+			alice, err := userRepo.FindByID(ctx, "alice")
+			require.NoError(t, err)
+			assert.NotContains(t, alice.OwnChannels, "ch-owner")
+
+			assert.Equal(t, database.ErrLastOwner, channelRepo.RemoveOwner(ctx, "ch-owner", "bob"))
+
+			err = channelRepo.TransferOwnership(ctx, "ch-owner", "carol", "alice")
+			assert.True(t, database.IsNotFound(err), "不是擁有者：%v", err)
+
+			require.NoError(t, channelRepo.TransferOwnership(ctx, "ch-owner", "bob", "carol"))
+			channel, err = channelRepo.FindByID(ctx, "ch-owner")
+			require.NoError(t, err)
+			assert.Equal(t, []string{"carol"}, channel.Owners)
+			carol, err := userRepo.FindByID(ctx, "carol")
+			require.NoError(t, err)
+			assert.Contains(t, carol.OwnChannels, "ch-owner")
+			bob, err = userRepo.FindByID(ctx, "bob")
+			require.NoError(t, err)
+			assert.NotContains(t, bob.OwnChannels, "ch-owner")
+		})
+
+		t.Run("修訂版本", func(t *testing.T) {
+			_, err := channelRepo.GetRevision(ctx, "missing")
+			assert.True(t, database.IsNotFound(err))
+
+			createConformanceChannel(t, db, "ch-revision", "Revision", "alice")
+			assert.Equal(t, database.ErrRevisionMismatch, channelRepo.ClaimRevision(ctx, "ch-revision", 5))
+			require.NoError(t, channelRepo.ClaimRevision(ctx, "ch-revision", 0))
+			assert.Equal(t, database.ErrRevisionMismatch, channelRepo.ClaimRevision(ctx, "ch-revision", 0))
+
+			require.NoError(t, channelRepo.IncrementForkCount(ctx, "ch-revision"))
+			revision, err := channelRepo.GetRevision(ctx, "ch-revision")
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), revision, "複製次數不遞增修訂版本")
+
+			channel, err := channelRepo.FindByID(ctx, "ch-revision")
+			require.NoError(t, err)
+			assert.Equal(t, 1, channel.ForkCount)
+
+			// 同一個交易中比對後的修改不再遞增修訂版本
+			require.NoError(t, db.WithTx(ctx, func(ctx context.Context) error {
+				if err := channelRepo.ClaimRevision(ctx, "ch-revision", 1); err != nil {
+					return err
+				}
+				return channelRepo.Update(database.WithClaimedRevision(ctx, "ch-revision"), "ch-revision", map[string]interface{}{"name": "Claimed"})
+			}))
+			revision, err = channelRepo.GetRevision(ctx, "ch-revision")
+			require.NoError(t, err)
+			assert.Equal(t, int64(2), revision)
+
+			// 垃圾桶中的頻道無法比對修訂版本
+			require.NoError(t, channelRepo.SoftDelete(ctx, "ch-revision", time.Now()))
+			revision, err = channelRepo.GetRevision(ctx, "ch-revision")
+			require.NoError(t, err)
+			assert.Equal(t, database.ErrRevisionMismatch, channelRepo.ClaimRevision(ctx, "ch-revision", revision))
+		})
+
+		t.Run("垃圾桶", func(t *testing.T) {
+			createConformanceChannel(t, db, "ch-trash", "Trash", "alice")
+			require.NoError(t, userRepo.AddChannel(ctx, "alice", "ch-trash"))
+
+			require.NoError(t, channelRepo.SoftDelete(ctx, "ch-trash", time.Now().Add(-time.Minute)))
+			assert.True(t, database.IsNotFound(channelRepo.SoftDelete(ctx, "ch-trash", time.Now())))
+
+			channel, err := channelRepo.FindByID(ctx, "ch-trash")
+			require.NoError(t, err)
+			assert.Nil(t, channel)
+			channel, err = channelRepo.FindDeleted(ctx, "ch-trash")
+			require.NoError(t, err)
+			require.NotNil(t, channel)
+			assert.NotNil(t, channel.DeletedAt)
+
+			deleted, err := channelRepo.ListDeleted(ctx, "alice")
+			require.NoError(t, err)
+			assert.Contains(t, channelIDs(deleted), "ch-trash")
+			deleted, err = channelRepo.ListDeleted(ctx, "bob")
+			require.NoError(t, err)
+			assert.NotContains(t, channelIDs(deleted), "ch-trash")
+
+			expired, err := channelRepo.ListExpired(ctx, time.Now().Add(-time.Hour), 10)
+			require.NoError(t, err)
+			assert.NotContains(t, expired, "ch-trash")
+			expired, err = channelRepo.ListExpired(ctx, time.Now(), 10)
+			require.NoError(t, err)
+			assert.Contains(t, expired, "ch-trash")
+
+			permission, err := channelRepo.GetPermission(ctx, "ch-trash", "alice")
+			require.NoError(t, err)
+			assert.False(t, permission.CanRead(), "垃圾桶中的頻道沒有任何權限")
+
+			require.NoError(t, channelRepo.Restore(ctx, "ch-trash"))
+			assert.True(t, database.IsNotFound(channelRepo.Restore(ctx, "ch-trash")))
+			channel, err = channelRepo.FindByID(ctx, "ch-trash")
+			require.NoError(t, err)
+			require.NotNil(t, channel)
+			assert.Nil(t, channel.DeletedAt)
+
+			require.NoError(t, channelRepo.SoftDelete(ctx, "ch-trash", time.Now()))
+			require.NoError(t, channelRepo.Purge(ctx, "ch-trash"))
+			channel, err = channelRepo.FindDeleted(ctx, "ch-trash")
+			require.NoError(t, err)
+			assert.Nil(t, channel)
+			alice, err := userRepo.FindByID(ctx, "alice")
+			require.NoError(t, err)
+			assert.NotContains(t, alice.OwnChannels, "ch-trash")
+		})
+	})
+}
+
+func TestConformance_ProgramRepository(t *testing.T) {
+	ctx := context.Background()
+
+	runConformance(t, func(t *testing.T, db database.Database) {
+		programRepo := repository.NewProgramRepository(db)
+		channelRepo := repository.NewChannelRepository(db)
+		createConformanceUser(t, db, "alice")
+		createConformanceChannel(t, db, "ch-programs", "Programs", "alice")
+
+		t.Run("節目 ID 遞增", func(t *testing.T) {
+			first, err := programRepo.GetNextProgramID(ctx)
+			require.NoError(t, err)
+			second, err := programRepo.GetNextProgramID(ctx)
+			require.NoError(t, err)
+			assert.Greater(t, second, first)
+		})
+
+		var programIDs []int
+		t.Run("新增節目", func(t *testing.T) {
+			program := &models.Program{
+				Name:      "First",
+				Desc:      "first program",
+				Duration:  60,
+				Type:      models.ProgramTypeYouTube,
+				YouTubeID: "abc",
+				VideoID:   "abc",
+				Tags:      []int{1},
+			}
+			require.NoError(t, programRepo.AddProgram(ctx, "ch-programs", program))
+			assert.NotZero(t, program.ID)
+
+			batch := []*models.Program{
+				{Name: "Second", Type: models.ProgramTypeYouTube, YouTubeID: "def", VideoID: "def"},
+				{Name: "Third", Type: models.ProgramTypeYouTube, YouTubeID: "ghi", VideoID: "ghi"},
+			}
+			require.NoError(t, programRepo.AddPrograms(ctx, "ch-programs", batch))
+			assert.Greater(t, batch[0].ID, program.ID)
+			assert.Greater(t, batch[1].ID, batch[0].ID)
+			programIDs = []int{program.ID, batch[0].ID, batch[1].ID}
+
+			channel, err := channelRepo.FindByID(ctx, "ch-programs")
+			require.NoError(t, err)
+			require.Len(t, channel.Contents, 3)
+			assert.Equal(t, "First", channel.Contents[0].Name)
+			assert.Equal(t, "first program", channel.Contents[0].Desc)
+			assert.Equal(t, 60, channel.Contents[0].Duration)
+			assert.Equal(t, "abc", channel.Contents[0].VideoID)
+			assert.Equal(t, []int{1}, channel.Contents[0].Tags)
+			assert.Equal(t, int64(2), channel.Revision)
+		})
+
+		t.Run("更新節目", func(t *testing.T) {
+			require.Len(t, programIDs, 3)
+			require.NoError(t, programRepo.UpdateProgram(ctx, "ch-programs", programIDs[1], map[string]interface{}{
+				"contents.$.name":     "Second Updated",
+				"contents.$.duration": 120,
+				"contents.$.tags":     []int{4, 5},
+			}))
+
+			channel, err := channelRepo.FindByID(ctx, "ch-programs")
+			require.NoError(t, err)
+			for _, program := range channel.Contents {
+				if program.ID == programIDs[1] {
+					assert.Equal(t, "Second Updated", program.Name)
+					assert.Equal(t, 120, program.Duration)
+					assert.ElementsMatch(t, []int{4, 5}, program.Tags)
+				} else {
+					assert.NotEqual(t, "Second Updated", program.Name)
+				}
+			}
+		})
+
+		t.Run("更新節目狀態不修改修訂版本", func(t *testing.T) {
+			require.Len(t, programIDs, 3)
+			before, err := channelRepo.FindByID(ctx, "ch-programs")
+			require.NoError(t, err)
+
+			checked := time.Now()
+			require.NoError(t, programRepo.UpdateProgramStatus(ctx, "ch-programs", programIDs[2], map[string]interface{}{
+				"contents.$.availability": models.AvailabilityUnavailable,
+				"contents.$.last_checked": checked,
+				"contents.$.duration":     90,
+			}))
+
+			channel, err := channelRepo.FindByID(ctx, "ch-programs")
+			require.NoError(t, err)
+			assert.Equal(t, before.Revision, channel.Revision)
+			assert.True(t, before.LastModified.Equal(channel.LastModified))
+			for i, program := range channel.Contents {
+				if program.ID != programIDs[2] {
+					continue
+				}
+				assert.Equal(t, models.AvailabilityUnavailable, program.Availability)
+				assert.Equal(t, 90, program.Duration)
+				require.NotNil(t, program.LastChecked)
+				assert.WithinDuration(t, checked, *program.LastChecked, time.Second)
+				assert.True(t, before.Contents[i].LastModified.Equal(program.LastModified))
+			}
+		})
+
+		t.Run("設定節目順序", func(t *testing.T) {
+			require.Len(t, programIDs, 3)
+			order := []int{programIDs[2], programIDs[0], programIDs[1]}
+			require.NoError(t, programRepo.SetOrder(ctx, "ch-programs", order))
+
+			channel, err := channelRepo.FindByID(ctx, "ch-programs")
+			require.NoError(t, err)
+			assert.Equal(t, order, channel.ContentsOrder)
+		})
+
+		t.Run("批次載入列表頻道的節目", func(t *testing.T) {
+			require.Len(t, programIDs, 3)
+			channels, err := channelRepo.ListChannels(ctx, database.Filter{}, nil, 0, 0)
+			require.NoError(t, err)
+			require.Len(t, channels, 1)
+			require.NoError(t, channelRepo.LoadContents(ctx, channels))
+
+			channel, err := channelRepo.FindByID(ctx, "ch-programs")
+			require.NoError(t, err)
+			assert.Equal(t, channel.ContentsOrder, channels[0].ContentsOrder)
+			require.Len(t, channels[0].Contents, len(channel.Contents))
+			for i, program := range channel.Contents {
+				assert.Equal(t, program.ID, channels[0].Contents[i].ID)
+				assert.Equal(t, program.Name, channels[0].Contents[i].Name)
+				assert.ElementsMatch(t, program.Tags, channels[0].Contents[i].Tags)
+			}
+		})
+
+		t.Run("刪除節目", func(t *testing.T) {
+			require.Len(t, programIDs, 3)
+			require.NoError(t, programRepo.DeletePrograms(ctx, "ch-programs", []int{programIDs[0]}))
+			require.NoError(t, programRepo.DeletePrograms(ctx, "ch-programs", []int{}))
+
+			channel, err := channelRepo.FindByID(ctx, "ch-programs")
+			require.NoError(t, err)
+			ids := []int{}
+			for _, program := range channel.Contents {
+				ids = append(ids, program.ID)
+			}
+			assert.ElementsMatch(t, programIDs[1:], ids)
+		})
+
+		t.Run("以原本的 ID 還原節目", func(t *testing.T) {
+			require.Len(t, programIDs, 3)
+			before, err := channelRepo.GetRevision(ctx, "ch-programs")
+			require.NoError(t, err)
+			created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+			require.NoError(t, programRepo.RestorePrograms(ctx, "ch-programs", []*models.Program{{
+				ID:           programIDs[0],
+				Name:         "First",
+				Type:         models.ProgramTypeYouTube,
+				YouTubeID:    "abc",
+				Tags:         []int{1},
+				Created:      created,
+				LastModified: created,
+			}}))
+
+			channel, err := channelRepo.FindByID(ctx, "ch-programs")
+			require.NoError(t, err)
+			assert.Greater(t, channel.Revision, before)
+			var restored *models.Program
+			for i := range channel.Contents {
+				if channel.Contents[i].ID == programIDs[0] {
+					restored = &channel.Contents[i]
+				}
+			}
+			require.NotNil(t, restored)
+			assert.Equal(t, "First", restored.Name)
+			assert.Equal(t, []int{1}, restored.Tags)
+			assert.True(t, created.Equal(restored.Created))
+		})
+	})
+}
+
+func TestConformance_WithTx(t *testing.T) {
+	ctx := context.Background()
+	errAbort := errors.New("abort")
+
+	runConformance(t, func(t *testing.T, db database.Database) {
+		userRepo := repository.NewUserRepository(db)
+		channelRepo := repository.NewChannelRepository(db)
+		programRepo := repository.NewProgramRepository(db)
+		createConformanceUser(t, db, "alice")
+
+		// createLinked 建立頻道、加入使用者的頻道列表並新增節目
+		createLinked := func(ctx context.Context, id string) error {
+			if err := channelRepo.Create(ctx, &models.Channel{
+				ID:     id,
+				Type:   models.ChannelTypeDefault,
+				Name:   id,
+				Owners: []string{"alice"},
+			}); err != nil {
+				return err
+			}
+			if err := userRepo.AddChannel(ctx, "alice", id); err != nil {
+				return err
+			}
+			return programRepo.AddProgram(ctx, id, &models.Program{Name: "program of " + id, Type: models.ProgramTypeYouTube})
+		}
+
+		// assertLinked 確認頻道與使用者的頻道列表是否存在
+		assertLinked := func(t *testing.T, id string, exists bool) {
+			channel, err := channelRepo.FindByID(ctx, id)
+			require.NoError(t, err)
+			user, err := userRepo.FindByID(ctx, "alice")
+			require.NoError(t, err)
+			require.NotNil(t, user)
+			if exists {
+				require.NotNil(t, channel)
+				assert.Len(t, channel.Contents, 1)
+				assert.Contains(t, user.OwnChannels, id)
+			} else {
+				assert.Nil(t, channel)
+				assert.NotContains(t, user.OwnChannels, id)
+			}
+		}
+
+		t.Run("成功時提交所有變更", func(t *testing.T) {
+			require.NoError(t, db.WithTx(ctx, func(ctx context.Context) error {
+				return createLinked(ctx, "ch-commit")
+			}))
+			assertLinked(t, "ch-commit", true)
+		})
+
+		t.Run("失敗時回滾所有變更", func(t *testing.T) {
+			err := db.WithTx(ctx, func(ctx context.Context) error {
+				if err := createLinked(ctx, "ch-rollback"); err != nil {
+					return err
+				}
+				return errAbort
+			})
+			assert.ErrorIs(t, err, errAbort)
+			assertLinked(t, "ch-rollback", false)
+			assertLinked(t, "ch-commit", true)
+		})
+
+		t.Run("巢狀呼叫沿用外層交易", func(t *testing.T) {
+			err := db.WithTx(ctx, func(ctx context.Context) error {
+				if err := db.WithTx(ctx, func(ctx context.Context) error {
+					return createLinked(ctx, "ch-nested")
+				}); err != nil {
+					return err
+				}
+				return errAbort
+			})
+			assert.ErrorIs(t, err, errAbort)
+			assertLinked(t, "ch-nested", false)
+		})
+	})
+}
+
+func TestConformance_AuxiliaryRepositories(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	runConformance(t, func(t *testing.T, db database.Database) {
+		createConformanceUser(t, db, "alice")
+		createConformanceChannel(t, db, "ch-aux", "Cooking Show", "alice")
+
+		t.Run("API Token", func(t *testing.T) {
+			tokenRepo := repository.NewAPITokenRepository(db)
+			require.NoError(t, tokenRepo.Create(ctx, &models.APIToken{
+				ID: "token-1", UserID: "alice", Name: "cli", Prefix: "htv_abc", TokenHash: "hash-1", Scopes: []string{"read"},
+			}))
+			token, err := tokenRepo.FindByHash(ctx, "hash-1")
+			require.NoError(t, err)
+			require.NotNil(t, token)
+			assert.Equal(t, "cli", token.Name)
+
+			require.NoError(t, tokenRepo.Touch(ctx, "token-1", now))
+			tokens, err := tokenRepo.ListByUser(ctx, "alice")
+			require.NoError(t, err)
+			require.Len(t, tokens, 1)
+			require.NotNil(t, tokens[0].LastUsed)
+
+			require.NoError(t, tokenRepo.Delete(ctx, "token-1", "alice"))
+			token, err = tokenRepo.FindByHash(ctx, "hash-1")
+			require.NoError(t, err)
+			assert.Nil(t, token)
+		})
+
+		t.Run("頻道分享邀請", func(t *testing.T) {
+			shareRepo := repository.NewChannelShareRepository(db)
+			require.NoError(t, shareRepo.Create(ctx, &models.ChannelShare{
+				ID: "share-1", ChannelID: "ch-aux", Email: "bob@example.com", Role: "read", TokenHash: "share-hash",
+				InvitedBy: "alice", Status: models.ShareStatusPending, ExpiresAt: now.Add(time.Hour),
+			}))
+			shares, err := shareRepo.ListPendingByEmail(ctx, "bob@example.com", now)
+			require.NoError(t, err)
+			require.Len(t, shares, 1)
+
+			updated, err := shareRepo.UpdateStatus(ctx, "share-1", models.ShareStatusPending, models.ShareStatusAccepted, "alice")
+			require.NoError(t, err)
+			assert.True(t, updated)
+			updated, err = shareRepo.UpdateStatus(ctx, "share-1", models.ShareStatusPending, models.ShareStatusAccepted, "alice")
+			require.NoError(t, err)
+			assert.False(t, updated)
+		})
+
+		t.Run("頻道歷史版本", func(t *testing.T) {
+			revisionRepo := repository.NewChannelRevisionRepository(db)
+			require.NoError(t, revisionRepo.Create(ctx, &models.ChannelRevision{
+				ID: "rev-1", ChannelID: "ch-aux", Revision: 1, Action: "update",
+				Snapshot: models.ChannelSnapshot{Name: "Cooking Show"},
+			}))
+			revisions, err := revisionRepo.ListByChannel(ctx, "ch-aux", 0, 0)
+			require.NoError(t, err)
+			require.Len(t, revisions, 1)
+			assert.Equal(t, "Cooking Show", revisions[0].Snapshot.Name)
+		})
+
+		t.Run("Session", func(t *testing.T) {
+			store := repository.NewSessionStore(db)
+			for _, id := range []string{"session-1", "session-2"} {
+				require.NoError(t, store.Create(ctx, &session.Record{
+					ID: id, UserID: "alice", Created: now, LastSeen: now, ExpiresAt: now.Add(time.Hour),
+				}))
+			}
+			require.NoError(t, store.DeleteByUser(ctx, "alice", "session-1"))
+			records, err := store.ListByUser(ctx, "alice", now)
+			require.NoError(t, err)
+			require.Len(t, records, 1)
+			assert.Equal(t, "session-1", records[0].ID)
+		})
+
+		t.Run("標籤", func(t *testing.T) {
+			tagRepo := repository.NewTagRepository(db)
+			require.NoError(t, tagRepo.Create(ctx, &models.Tag{ID: 1, Slug: "food", Labels: map[string]string{"en": "Food"}}))
+			tag, err := tagRepo.FindBySlug(ctx, "food")
+			require.NoError(t, err)
+			require.NotNil(t, tag)
+			assert.Equal(t, "Food", tag.Labels["en"])
+
+			require.NoError(t, tagRepo.Delete(ctx, 1))
+			tags, err := tagRepo.List(ctx)
+			require.NoError(t, err)
+			assert.Empty(t, tags)
+		})
+
+		t.Run("全文搜尋", func(t *testing.T) {
+			require.NoError(t, repository.NewProgramRepository(db).AddProgram(ctx, "ch-aux", &models.Program{
+				Name: "Pasta Night", Type: models.ProgramTypeYouTube, YouTubeID: "dQw4w9WgXcQ",
+			}))
+			searchRepo := repository.NewSearchRepository(db)
+
+			channels, err := searchRepo.SearchChannels(ctx, database.SearchQuery{Terms: []string{"cooking"}, Limit: 10})
+			require.NoError(t, err)
+			assert.Equal(t, []string{"ch-aux"}, channelIDs(channels))
+
+			results, err := searchRepo.SearchPrograms(ctx, database.SearchQuery{Terms: []string{"pasta"}, Limit: 10})
+			require.NoError(t, err)
+			require.Len(t, results, 1)
+			assert.Equal(t, "ch-aux", results[0].ChannelID)
+		})
+	})
+}
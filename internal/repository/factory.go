@@ -2,6 +2,7 @@ package repository
 
 import (
 	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/pkg/session"
 )
 
 // NewUserRepository 建立使用者 Repository（根據資料庫類型）
@@ -11,6 +12,10 @@ func NewUserRepository(db database.Database) database.UserRepository {
 		return NewMongoDBUserRepository(db)
 	case database.DatabaseTypeSQLite:
 		return NewSQLiteUserRepository(db)
+	case database.DatabaseTypePostgres:
+		return NewPostgresUserRepository(db)
+	case database.DatabaseTypeMemory:
+		return NewMemoryUserRepository(db)
 	default:
 		panic("unsupported database type")
 	}
@@ -23,6 +28,10 @@ func NewChannelRepository(db database.Database) database.ChannelRepository {
 		return NewMongoDBChannelRepository(db)
 	case database.DatabaseTypeSQLite:
 		return NewSQLiteChannelRepository(db)
+	case database.DatabaseTypePostgres:
+		return NewPostgresChannelRepository(db)
+	case database.DatabaseTypeMemory:
+		return NewMemoryChannelRepository(db)
 	default:
 		panic("unsupported database type")
 	}
@@ -35,8 +44,118 @@ func NewProgramRepository(db database.Database) database.ProgramRepository {
 		return NewMongoDBProgramRepository(db)
 	case database.DatabaseTypeSQLite:
 		return NewSQLiteProgramRepository(db)
+	case database.DatabaseTypePostgres:
+		return NewPostgresProgramRepository(db)
+	case database.DatabaseTypeMemory:
+		return NewMemoryProgramRepository(db)
 	default:
 		panic("unsupported database type")
 	}
 }
 
+// NewInvitationRepository 建立邀請碼 Repository（根據資料庫類型）
+func NewInvitationRepository(db database.Database) database.InvitationRepository {
+	switch db.Type() {
+	case database.DatabaseTypeMongoDB:
+		return NewMongoDBInvitationRepository(db)
+	case database.DatabaseTypeSQLite:
+		return NewSQLiteInvitationRepository(db)
+	case database.DatabaseTypePostgres:
+		return NewPostgresInvitationRepository(db)
+	case database.DatabaseTypeMemory:
+		return NewMemoryInvitationRepository(db)
+	default:
+		panic("unsupported database type")
+	}
+}
+
+// NewAPITokenRepository 建立個人 API Token Repository（根據資料庫類型）
+func NewAPITokenRepository(db database.Database) database.APITokenRepository {
+	switch db.Type() {
+	// 記憶體資料庫的集合與 MongoDB 的行為相同，共用以 Collection 實作的 Repository
+	case database.DatabaseTypeMongoDB, database.DatabaseTypeMemory:
+		return NewMongoDBAPITokenRepository(db)
+	case database.DatabaseTypeSQLite:
+		return NewSQLiteAPITokenRepository(db)
+	case database.DatabaseTypePostgres:
+		return NewPostgresAPITokenRepository(db)
+	default:
+		panic("unsupported database type")
+	}
+}
+
+// NewChannelShareRepository 建立頻道分享邀請 Repository（根據資料庫類型）
+func NewChannelShareRepository(db database.Database) database.ChannelShareRepository {
+	switch db.Type() {
+	// 記憶體資料庫的集合與 MongoDB 的行為相同，共用以 Collection 實作的 Repository
+	case database.DatabaseTypeMongoDB, database.DatabaseTypeMemory:
+		return NewMongoDBChannelShareRepository(db)
+	case database.DatabaseTypeSQLite:
+		return NewSQLiteChannelShareRepository(db)
+	case database.DatabaseTypePostgres:
+		return NewPostgresChannelShareRepository(db)
+	default:
+		panic("unsupported database type")
+	}
+}
+
+// NewChannelRevisionRepository 建立頻道歷史版本 Repository（根據資料庫類型）
+func NewChannelRevisionRepository(db database.Database) database.ChannelRevisionRepository {
+	switch db.Type() {
+	// 記憶體資料庫的集合與 MongoDB 的行為相同，共用以 Collection 實作的 Repository
+	case database.DatabaseTypeMongoDB, database.DatabaseTypeMemory:
+		return NewMongoDBChannelRevisionRepository(db)
+	case database.DatabaseTypeSQLite:
+		return NewSQLiteChannelRevisionRepository(db)
+	case database.DatabaseTypePostgres:
+		return NewPostgresChannelRevisionRepository(db)
+	default:
+		panic("unsupported database type")
+	}
+}
+
+// NewSessionStore 建立伺服器端 Session 儲存（根據資料庫類型）
+func NewSessionStore(db database.Database) session.Store {
+	switch db.Type() {
+	// 記憶體資料庫的集合與 MongoDB 的行為相同，共用以 Collection 實作的 Repository
+	case database.DatabaseTypeMongoDB, database.DatabaseTypeMemory:
+		return NewMongoDBSessionStore(db)
+	case database.DatabaseTypeSQLite:
+		return NewSQLiteSessionStore(db)
+	case database.DatabaseTypePostgres:
+		return NewPostgresSessionStore(db)
+	default:
+		panic("unsupported database type")
+	}
+}
+
+// NewTagRepository 建立標籤 Repository（根據資料庫類型）
+func NewTagRepository(db database.Database) database.TagRepository {
+	switch db.Type() {
+	// 記憶體資料庫的集合與 MongoDB 的行為相同，共用以 Collection 實作的 Repository
+	case database.DatabaseTypeMongoDB, database.DatabaseTypeMemory:
+		return NewMongoDBTagRepository(db)
+	case database.DatabaseTypeSQLite:
+		return NewSQLiteTagRepository(db)
+	case database.DatabaseTypePostgres:
+		return NewPostgresTagRepository(db)
+	default:
+		panic("unsupported database type")
+	}
+}
+
+// NewSearchRepository 建立全文搜尋 Repository（根據資料庫類型）
+func NewSearchRepository(db database.Database) database.SearchRepository {
+	switch db.Type() {
+	case database.DatabaseTypeMongoDB:
+		return NewMongoDBSearchRepository(db)
+	case database.DatabaseTypeSQLite:
+		return NewSQLiteSearchRepository(db)
+	case database.DatabaseTypePostgres:
+		return NewPostgresSearchRepository(db)
+	case database.DatabaseTypeMemory:
+		return NewMemorySearchRepository(db)
+	default:
+		panic("unsupported database type")
+	}
+}
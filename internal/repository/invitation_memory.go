@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/models"
+)
+
+// MemoryInvitationRepository 記憶體邀請碼 Repository
+type MemoryInvitationRepository struct {
+	db database.Database
+}
+
+// NewMemoryInvitationRepository 建立記憶體邀請碼 Repository
+func NewMemoryInvitationRepository(db database.Database) *MemoryInvitationRepository {
+	return &MemoryInvitationRepository{db: db}
+}
+
+// getStore 取得記憶體資料
+func (r *MemoryInvitationRepository) getStore() *database.MemoryStore {
+	return r.db.(*database.MemoryDatabase).GetStore()
+}
+
+// Create 建立邀請碼（ID 或邀請碼重複時回傳錯誤）
+func (r *MemoryInvitationRepository) Create(ctx context.Context, invitation *models.Invitation) error {
+	now := time.Now()
+	invitation.Created = now
+	invitation.LastModified = now
+
+	store := r.getStore()
+	store.Lock()
+	defer store.Unlock()
+
+	for _, existing := range store.Invitations {
+		if existing.ID == invitation.ID || existing.Code == invitation.Code {
+			return fmt.Errorf("duplicate invitation: %s", invitation.Code)
+		}
+	}
+	store.Invitations[invitation.ID] = cloneInvitation(invitation)
+	return nil
+}
+
+// FindByCode 依邀請碼查詢
+func (r *MemoryInvitationRepository) FindByCode(ctx context.Context, code string) (*models.Invitation, error) {
+	store := r.getStore()
+	store.Lock()
+	defer store.Unlock()
+
+	for _, invitation := range store.Invitations {
+		if invitation.Code == code {
+			return cloneInvitation(invitation), nil
+		}
+	}
+	return nil, nil
+}
+
+// List 列出邀請碼（依建立時間由新到舊）
+func (r *MemoryInvitationRepository) List(ctx context.Context, limit, skip int64) ([]models.Invitation, error) {
+	store := r.getStore()
+	store.Lock()
+	invitations := make([]models.Invitation, 0, len(store.Invitations))
+	for _, invitation := range store.Invitations {
+		invitations = append(invitations, *cloneInvitation(invitation))
+	}
+	store.Unlock()
+
+	sort.SliceStable(invitations, func(i, j int) bool {
+		return invitations[i].Created.After(invitations[j].Created)
+	})
+	return paginate(invitations, limit, skip), nil
+}
+
+// Revoke 撤銷邀請碼
+func (r *MemoryInvitationRepository) Revoke(ctx context.Context, id string) error {
+	store := r.getStore()
+	store.Lock()
+	defer store.Unlock()
+
+	invitation, ok := store.Invitations[id]
+	if !ok {
+		return database.ErrNoDocuments
+	}
+	invitation.Revoked = true
+	invitation.LastModified = time.Now()
+	return nil
+}
+
+// Delete 刪除邀請碼（已註冊使用者的 invitation_id 保持不變）
+func (r *MemoryInvitationRepository) Delete(ctx context.Context, id string) error {
+	store := r.getStore()
+	store.Lock()
+	defer store.Unlock()
+
+	if _, ok := store.Invitations[id]; !ok {
+		return database.ErrNoDocuments
+	}
+	delete(store.Invitations, id)
+	return nil
+}
+
+// Redeem 使用一次邀請碼（邀請碼無法使用時回傳 nil）
+func (r *MemoryInvitationRepository) Redeem(ctx context.Context, code string, now time.Time) (*models.Invitation, error) {
+	store := r.getStore()
+	store.Lock()
+	defer store.Unlock()
+
+	for _, invitation := range store.Invitations {
+		if invitation.Code != code {
+			continue
+		}
+		if !invitation.Usable(now) {
+			return nil, nil
+		}
+		invitation.Uses++
+		invitation.LastModified = now
+		return cloneInvitation(invitation), nil
+	}
+	return nil, nil
+}
+
+// cloneInvitation 複製邀請碼
+func cloneInvitation(invitation *models.Invitation) *models.Invitation {
+	clone := *invitation
+	clone.ExpiresAt = cloneTimePtr(invitation.ExpiresAt)
+	return &clone
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/models"
+)
+
+// MongoDBInvitationRepository MongoDB 邀請碼 Repository
+type MongoDBInvitationRepository struct {
+	collection database.Collection
+}
+
+// NewMongoDBInvitationRepository 建立 MongoDB 邀請碼 Repository
+func NewMongoDBInvitationRepository(db database.Database) *MongoDBInvitationRepository {
+	return &MongoDBInvitationRepository{
+		collection: db.Collection("invitations"),
+	}
+}
+
+// Create 建立邀請碼
+func (r *MongoDBInvitationRepository) Create(ctx context.Context, invitation *models.Invitation) error {
+	now := time.Now()
+	invitation.Created = now
+	invitation.LastModified = now
+	return r.collection.InsertOne(ctx, invitation)
+}
+
+// FindByCode 依邀請碼查詢
+func (r *MongoDBInvitationRepository) FindByCode(ctx context.Context, code string) (*models.Invitation, error) {
+	var invitation models.Invitation
+	err := r.collection.FindOne(ctx, database.Filter{"code": code}, &invitation)
+	if database.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// List 列出邀請碼（依建立時間由新到舊）
+func (r *MongoDBInvitationRepository) List(ctx context.Context, limit, skip int64) ([]models.Invitation, error) {
+	invitations := []models.Invitation{}
+	sort := database.Sort{{Field: "created", Order: -1}}
+	if err := r.collection.Find(ctx, database.Filter{}, sort, limit, skip, &invitations); err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+
+// Revoke 撤銷邀請碼
+func (r *MongoDBInvitationRepository) Revoke(ctx context.Context, id string) error {
+	matched, err := r.collection.UpdateOneMatched(ctx, database.Filter{"_id": id}, database.Update{
+		Set: map[string]interface{}{
+			"revoked":       true,
+			"last_modified": time.Now(),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if !matched {
+		return database.ErrNoDocuments
+	}
+	return nil
+}
+
+// Delete 刪除邀請碼（已註冊使用者的 invitation_id 保持不變）
+func (r *MongoDBInvitationRepository) Delete(ctx context.Context, id string) error {
+	count, err := r.collection.CountDocuments(ctx, database.Filter{"_id": id})
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return database.ErrNoDocuments
+	}
+	return r.collection.DeleteOne(ctx, database.Filter{"_id": id})
+}
+
+// Redeem 使用一次邀請碼（邀請碼無法使用時回傳 nil）
+// 以條件式更新確保同時註冊時不會超過可使用次數
+func (r *MongoDBInvitationRepository) Redeem(ctx context.Context, code string, now time.Time) (*models.Invitation, error) {
+	filter := database.Filter{
+		"code":    code,
+		"revoked": false,
+		"$and": []database.Filter{
+			{"$or": []database.Filter{
+				{"expires_at": nil},
+				{"expires_at": database.Filter{"$gt": now}},
+			}},
+			{"$or": []database.Filter{
+				{"max_uses": 0},
+				{"$expr": database.Filter{"$lt": []interface{}{"$uses", "$max_uses"}}},
+			}},
+		},
+	}
+	matched, err := r.collection.UpdateOneMatched(ctx, filter, database.Update{
+		Inc: map[string]interface{}{"uses": 1},
+		Set: map[string]interface{}{"last_modified": now},
+	})
+	if err != nil || !matched {
+		return nil, err
+	}
+	return r.FindByCode(ctx, code)
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/models"
+)
+
+// PostgresInvitationRepository PostgreSQL 邀請碼 Repository
+type PostgresInvitationRepository struct {
+	db database.Database
+}
+
+// NewPostgresInvitationRepository 建立 PostgreSQL 邀請碼 Repository
+func NewPostgresInvitationRepository(db database.Database) *PostgresInvitationRepository {
+	return &PostgresInvitationRepository{db: db}
+}
+
+// getDB 取得 SQL 資料庫連線（使用 context 中的交易）
+func (r *PostgresInvitationRepository) getDB() *database.SQLConn {
+	return r.db.(*database.PostgresDatabase).GetConn()
+}
+
+// Create 建立邀請碼
+func (r *PostgresInvitationRepository) Create(ctx context.Context, invitation *models.Invitation) error {
+	now := time.Now()
+	invitation.Created = now
+	invitation.LastModified = now
+
+	query := `INSERT INTO invitations (` + invitationSelectColumns + `)
+	          VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+	_, err := r.getDB().ExecContext(ctx, query,
+		invitation.ID,
+		invitation.Code,
+		invitation.Note,
+		invitation.CreatedBy,
+		invitation.MaxUses,
+		invitation.Uses,
+		invitation.ExpiresAt,
+		invitation.Revoked,
+		invitation.Created,
+		invitation.LastModified,
+	)
+	return err
+}
+
+// FindByCode 依邀請碼查詢
+func (r *PostgresInvitationRepository) FindByCode(ctx context.Context, code string) (*models.Invitation, error) {
+	row := r.getDB().QueryRowContext(ctx, `SELECT `+invitationSelectColumns+` FROM invitations WHERE code = $1`, code)
+	invitation, err := scanInvitation(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return invitation, err
+}
+
+// List 列出邀請碼（依建立時間由新到舊）
+func (r *PostgresInvitationRepository) List(ctx context.Context, limit, skip int64) ([]models.Invitation, error) {
+	query := `SELECT ` + invitationSelectColumns + ` FROM invitations ORDER BY created DESC`
+	var args postgresArgs
+	if limit > 0 {
+		query += ` LIMIT ` + args.add(limit)
+	}
+	if skip > 0 {
+		query += ` OFFSET ` + args.add(skip)
+	}
+
+	rows, err := r.getDB().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	invitations := []models.Invitation{}
+	for rows.Next() {
+		invitation, err := scanInvitation(rows)
+		if err != nil {
+			return nil, err
+		}
+		invitations = append(invitations, *invitation)
+	}
+	return invitations, rows.Err()
+}
+
+// Revoke 撤銷邀請碼
+func (r *PostgresInvitationRepository) Revoke(ctx context.Context, id string) error {
+	result, err := r.getDB().ExecContext(ctx, `UPDATE invitations SET revoked = TRUE, last_modified = $1 WHERE id = $2`, time.Now(), id)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return database.ErrNoDocuments
+	}
+	return nil
+}
+
+// Delete 刪除邀請碼（已註冊使用者的 invitation_id 保持不變）
+func (r *PostgresInvitationRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.getDB().ExecContext(ctx, `DELETE FROM invitations WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return database.ErrNoDocuments
+	}
+	return nil
+}
+
+// Redeem 使用一次邀請碼（邀請碼無法使用時回傳 nil）
+// 更新時比對原本的使用次數，確保同時註冊時不會超過可使用次數
+func (r *PostgresInvitationRepository) Redeem(ctx context.Context, code string, now time.Time) (*models.Invitation, error) {
+	invitation, err := r.FindByCode(ctx, code)
+	if err != nil || invitation == nil || !invitation.Usable(now) {
+		return nil, err
+	}
+
+	result, err := r.getDB().ExecContext(ctx,
+		`UPDATE invitations SET uses = uses + 1, last_modified = $1 WHERE id = $2 AND uses = $3 AND NOT revoked`,
+		now, invitation.ID, invitation.Uses,
+	)
+	if err != nil {
+		return nil, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		// 其他請求同時使用了邀請碼，重新檢查
+		return r.Redeem(ctx, code, now)
+	}
+
+	invitation.Uses++
+	invitation.LastModified = now
+	return invitation, nil
+}
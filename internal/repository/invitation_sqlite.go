@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/models"
+)
+
+// SQLiteInvitationRepository SQLite 邀請碼 Repository
+type SQLiteInvitationRepository struct {
+	db database.Database
+}
+
+// NewSQLiteInvitationRepository 建立 SQLite 邀請碼 Repository
+func NewSQLiteInvitationRepository(db database.Database) *SQLiteInvitationRepository {
+	return &SQLiteInvitationRepository{db: db}
+}
+
+// getDB 取得 SQL 資料庫連線（使用 context 中的交易）
+func (r *SQLiteInvitationRepository) getDB() *database.SQLConn {
+	return r.db.(*database.SQLiteDatabase).GetConn()
+}
+
+const invitationSelectColumns = `id, code, note, created_by, max_uses, uses, expires_at, revoked, created, last_modified`
+
+// scanInvitation 讀取一筆邀請碼
+func scanInvitation(row rowScanner) (*models.Invitation, error) {
+	var invitation models.Invitation
+	var expiresAt sql.NullTime
+	if err := row.Scan(
+		&invitation.ID,
+		&invitation.Code,
+		&invitation.Note,
+		&invitation.CreatedBy,
+		&invitation.MaxUses,
+		&invitation.Uses,
+		&expiresAt,
+		&invitation.Revoked,
+		&invitation.Created,
+		&invitation.LastModified,
+	); err != nil {
+		return nil, err
+	}
+	if expiresAt.Valid {
+		invitation.ExpiresAt = &expiresAt.Time
+	}
+	return &invitation, nil
+}
+
+// Create 建立邀請碼
+func (r *SQLiteInvitationRepository) Create(ctx context.Context, invitation *models.Invitation) error {
+	now := time.Now()
+	invitation.Created = now
+	invitation.LastModified = now
+
+	revoked := 0
+	if invitation.Revoked {
+		revoked = 1
+	}
+	query := `INSERT INTO invitations (` + invitationSelectColumns + `)
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.getDB().ExecContext(ctx, query,
+		invitation.ID,
+		invitation.Code,
+		invitation.Note,
+		invitation.CreatedBy,
+		invitation.MaxUses,
+		invitation.Uses,
+		invitation.ExpiresAt,
+		revoked,
+		invitation.Created,
+		invitation.LastModified,
+	)
+	return err
+}
+
+// FindByCode 依邀請碼查詢
+func (r *SQLiteInvitationRepository) FindByCode(ctx context.Context, code string) (*models.Invitation, error) {
+	row := r.getDB().QueryRowContext(ctx, `SELECT `+invitationSelectColumns+` FROM invitations WHERE code = ?`, code)
+	invitation, err := scanInvitation(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return invitation, err
+}
+
+// List 列出邀請碼（依建立時間由新到舊）
+func (r *SQLiteInvitationRepository) List(ctx context.Context, limit, skip int64) ([]models.Invitation, error) {
+	query := `SELECT ` + invitationSelectColumns + ` FROM invitations ORDER BY created DESC`
+	args := []interface{}{}
+	if limit > 0 {
+		query += ` LIMIT ? OFFSET ?`
+		args = append(args, limit, skip)
+	} else if skip > 0 {
+		query += ` LIMIT -1 OFFSET ?`
+		args = append(args, skip)
+	}
+
+	rows, err := r.getDB().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	invitations := []models.Invitation{}
+	for rows.Next() {
+		invitation, err := scanInvitation(rows)
+		if err != nil {
+			return nil, err
+		}
+		invitations = append(invitations, *invitation)
+	}
+	return invitations, rows.Err()
+}
+
+// Revoke 撤銷邀請碼
+func (r *SQLiteInvitationRepository) Revoke(ctx context.Context, id string) error {
+	result, err := r.getDB().ExecContext(ctx, `UPDATE invitations SET revoked = 1, last_modified = ? WHERE id = ?`, time.Now(), id)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return database.ErrNoDocuments
+	}
+	return nil
+}
+
+// Delete 刪除邀請碼（已註冊使用者的 invitation_id 保持不變）
+func (r *SQLiteInvitationRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.getDB().ExecContext(ctx, `DELETE FROM invitations WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return database.ErrNoDocuments
+	}
+	return nil
+}
+
+// Redeem 使用一次邀請碼（邀請碼無法使用時回傳 nil）
+// 更新時比對原本的使用次數，確保同時註冊時不會超過可使用次數
+func (r *SQLiteInvitationRepository) Redeem(ctx context.Context, code string, now time.Time) (*models.Invitation, error) {
+	invitation, err := r.FindByCode(ctx, code)
+	if err != nil || invitation == nil || !invitation.Usable(now) {
+		return nil, err
+	}
+
+	result, err := r.getDB().ExecContext(ctx,
+		`UPDATE invitations SET uses = uses + 1, last_modified = ? WHERE id = ? AND uses = ? AND revoked = 0`,
+		now, invitation.ID, invitation.Uses,
+	)
+	if err != nil {
+		return nil, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		// 其他請求同時使用了邀請碼，重新檢查
+		return r.Redeem(ctx, code, now)
+	}
+
+	invitation.Uses++
+	invitation.LastModified = now
+	return invitation, nil
+}
@@ -0,0 +1,259 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/models"
+)
+
+// MemoryProgramRepository 記憶體節目 Repository（節目內嵌在頻道中）
+type MemoryProgramRepository struct {
+	db           database.Database
+	countersColl database.Collection
+}
+
+// NewMemoryProgramRepository 建立記憶體節目 Repository
+func NewMemoryProgramRepository(db database.Database) *MemoryProgramRepository {
+	return &MemoryProgramRepository{
+		db:           db,
+		countersColl: db.Collection("counters"),
+	}
+}
+
+// getStore 取得記憶體資料
+func (r *MemoryProgramRepository) getStore() *database.MemoryStore {
+	return r.db.(*database.MemoryDatabase).GetStore()
+}
+
+// GetNextProgramID 取得下一個節目 ID（使用 counter collection）
+func (r *MemoryProgramRepository) GetNextProgramID(ctx context.Context) (int, error) {
+	var counter struct {
+		Seq int `bson:"seq"`
+	}
+	err := r.countersColl.FindOneAndUpdate(ctx, database.Filter{"_id": "program_id"}, database.Update{
+		Inc: map[string]interface{}{"seq": 1},
+	}, true, &counter)
+	if err != nil {
+		return 0, err
+	}
+	return counter.Seq, nil
+}
+
+// AddProgram 新增節目到頻道
+func (r *MemoryProgramRepository) AddProgram(ctx context.Context, channelID string, program *models.Program) error {
+	return r.AddPrograms(ctx, channelID, []*models.Program{program})
+}
+
+// AddPrograms 依序新增多個節目到頻道（頻道不存在時回傳 ErrNoDocuments，不會寫入任何節目）
+func (r *MemoryProgramRepository) AddPrograms(ctx context.Context, channelID string, programs []*models.Program) error {
+	if len(programs) == 0 {
+		return nil
+	}
+
+	// 先確認頻道存在，避免頻道不存在時仍取得節目 ID
+	if _, err := NewMemoryChannelRepository(r.db).GetRevision(ctx, channelID); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, program := range programs {
+		programID, err := r.GetNextProgramID(ctx)
+		if err != nil {
+			return err
+		}
+		program.ID = programID
+		program.Created = now
+		program.LastModified = now
+	}
+
+	return r.appendPrograms(ctx, channelID, programs)
+}
+
+// RestorePrograms 以節目原本的 ID 與建立時間新增多個節目到頻道（還原歷史版本使用）
+func (r *MemoryProgramRepository) RestorePrograms(ctx context.Context, channelID string, programs []*models.Program) error {
+	if len(programs) == 0 {
+		return nil
+	}
+	return r.appendPrograms(ctx, channelID, programs)
+}
+
+// appendPrograms 將節目加入頻道的 contents（頻道不存在時回傳 ErrNoDocuments）
+func (r *MemoryProgramRepository) appendPrograms(ctx context.Context, channelID string, programs []*models.Program) error {
+	return modifyMemoryChannel(r.getStore(), channelID, func(_ *database.MemoryStore, channel *models.Channel) error {
+		for _, program := range programs {
+			channel.Contents = append(channel.Contents, *cloneProgram(program))
+		}
+		touchMemoryChannel(ctx, channel)
+		return nil
+	})
+}
+
+// UpdateProgram 更新節目（節目不存在時不修改頻道）
+func (r *MemoryProgramRepository) UpdateProgram(ctx context.Context, channelID string, programID int, update map[string]interface{}) error {
+	return ignoreNotFound(modifyMemoryChannel(r.getStore(), channelID, func(_ *database.MemoryStore, channel *models.Channel) error {
+		for i := range channel.Contents {
+			if channel.Contents[i].ID != programID {
+				continue
+			}
+			// 先套用到複本，任一欄位失敗時不修改節目
+			updated := cloneProgram(&channel.Contents[i])
+			for key, value := range update {
+				if err := setProgramField(updated, strings.TrimPrefix(key, "contents.$."), value); err != nil {
+					return err
+				}
+			}
+			updated.LastModified = time.Now()
+			channel.Contents[i] = *updated
+			touchMemoryChannel(ctx, channel)
+			return nil
+		}
+		return nil
+	}))
+}
+
+// UpdateProgramStatus 更新背景工作維護的節目欄位（不修改節目與頻道的 last_modified 及頻道的修訂版本）
+func (r *MemoryProgramRepository) UpdateProgramStatus(ctx context.Context, channelID string, programID int, update map[string]interface{}) error {
+	return ignoreNotFound(modifyMemoryChannel(r.getStore(), channelID, func(_ *database.MemoryStore, channel *models.Channel) error {
+		for i := range channel.Contents {
+			if channel.Contents[i].ID != programID {
+				continue
+			}
+			updated := cloneProgram(&channel.Contents[i])
+			for key, value := range update {
+				if err := setProgramField(updated, strings.TrimPrefix(key, "contents.$."), value); err != nil {
+					return err
+				}
+			}
+			channel.Contents[i] = *updated
+			return nil
+		}
+		return nil
+	}))
+}
+
+// setProgramField 設定節目欄位（key 為 MongoDB 欄位名稱）
+func setProgramField(program *models.Program, key string, value interface{}) error {
+	switch key {
+	case "name":
+		return assignValue(&program.Name, value)
+	case "desc":
+		return assignValue(&program.Desc, value)
+	case "duration":
+		return assignValue(&program.Duration, value)
+	case "type":
+		return assignValue(&program.Type, value)
+	case "youtube_id":
+		return assignValue(&program.YouTubeID, value)
+	case "video_id":
+		return assignValue(&program.VideoID, value)
+	case "availability":
+		return assignValue(&program.Availability, value)
+	case "tags":
+		if err := assignValue(&program.Tags, value); err != nil {
+			return err
+		}
+		program.Tags = cloneInts(program.Tags)
+	case "metadata_at":
+		return assignTime(&program.MetadataAt, value)
+	case "last_checked":
+		return assignTime(&program.LastChecked, value)
+	case "last_modified":
+		return assignValue(&program.LastModified, value)
+	default:
+		return fmt.Errorf("unsupported program field: %s", key)
+	}
+	return nil
+}
+
+// assignTime 設定可為空的時間欄位
+func assignTime(target **time.Time, value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*target = nil
+	case time.Time:
+		*target = &v
+	case *time.Time:
+		*target = cloneTimePtr(v)
+	default:
+		return fmt.Errorf("cannot assign %T to time", value)
+	}
+	return nil
+}
+
+// DeletePrograms 刪除節目（同時從節目順序中移除，沒有刪除任何節目時回傳錯誤）
+func (r *MemoryProgramRepository) DeletePrograms(ctx context.Context, channelID string, programIDs []int) error {
+	if len(programIDs) == 0 {
+		return nil
+	}
+
+	remove := make(map[int]bool, len(programIDs))
+	for _, id := range programIDs {
+		remove[id] = true
+	}
+
+	err := modifyMemoryChannel(r.getStore(), channelID, func(_ *database.MemoryStore, channel *models.Channel) error {
+		contents := []models.Program{}
+		for _, program := range channel.Contents {
+			if !remove[program.ID] {
+				contents = append(contents, program)
+			}
+		}
+		if len(contents) == len(channel.Contents) {
+			return database.ErrNoDocuments
+		}
+
+		order := []int{}
+		for _, id := range channel.ContentsOrder {
+			if !remove[id] {
+				order = append(order, id)
+			}
+		}
+		channel.Contents = contents
+		channel.ContentsOrder = order
+		touchMemoryChannel(ctx, channel)
+		return nil
+	})
+	if database.IsNotFound(err) {
+		return fmt.Errorf("no programs deleted: channel_id=%s, program_ids=%v", channelID, programIDs)
+	}
+	return err
+}
+
+// SetOrder 設定節目順序（重複的節目 ID 只保留第一個）
+func (r *MemoryProgramRepository) SetOrder(ctx context.Context, channelID string, order []int) error {
+	return ignoreNotFound(modifyMemoryChannel(r.getStore(), channelID, func(_ *database.MemoryStore, channel *models.Channel) error {
+		seen := make(map[int]bool, len(order))
+		contentsOrder := []int{}
+		for _, id := range order {
+			if !seen[id] {
+				seen[id] = true
+				contentsOrder = append(contentsOrder, id)
+			}
+		}
+		channel.ContentsOrder = contentsOrder
+		touchMemoryChannel(ctx, channel)
+		return nil
+	}))
+}
+
+// cloneProgram 複製節目
+func cloneProgram(program *models.Program) *models.Program {
+	clone := *program
+	clone.Tags = cloneInts(program.Tags)
+	clone.MetadataAt = cloneTimePtr(program.MetadataAt)
+	clone.LastChecked = cloneTimePtr(program.LastChecked)
+	return &clone
+}
+
+// cloneTimePtr 複製時間指標
+func cloneTimePtr(value *time.Time) *time.Time {
+	if value == nil {
+		return nil
+	}
+	clone := *value
+	return &clone
+}
@@ -75,6 +75,48 @@ func (r *MongoDBProgramRepository) AddProgram(ctx context.Context, channelID str
 		Set: map[string]interface{}{
 			"last_modified": time.Now(),
 		},
+		Inc: revisionInc(ctx, channelID),
+	})
+}
+
+// AddPrograms 依序新增多個節目到頻道（以單一更新寫入，任一節目都不會單獨寫入）
+func (r *MongoDBProgramRepository) AddPrograms(ctx context.Context, channelID string, programs []*models.Program) error {
+	if len(programs) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	for _, program := range programs {
+		programID, err := r.GetNextProgramID(ctx)
+		if err != nil {
+			return err
+		}
+		program.ID = programID
+		program.Created = now
+		program.LastModified = now
+	}
+
+	return r.pushPrograms(ctx, channelID, programs)
+}
+
+// RestorePrograms 以節目原本的 ID 與建立時間新增多個節目到頻道（還原歷史版本使用）
+func (r *MongoDBProgramRepository) RestorePrograms(ctx context.Context, channelID string, programs []*models.Program) error {
+	if len(programs) == 0 {
+		return nil
+	}
+	return r.pushPrograms(ctx, channelID, programs)
+}
+
+// pushPrograms 將節目加入頻道的 contents，並更新頻道的 last_modified
+func (r *MongoDBProgramRepository) pushPrograms(ctx context.Context, channelID string, programs []*models.Program) error {
+	return r.collection.UpdateOne(ctx, database.Filter{"_id": channelID}, database.Update{
+		Push: map[string]interface{}{
+			"contents": map[string]interface{}{"$each": programs},
+		},
+		Set: map[string]interface{}{
+			"last_modified": time.Now(),
+		},
+		Inc: revisionInc(ctx, channelID),
 	})
 }
 
@@ -89,9 +131,21 @@ func (r *MongoDBProgramRepository) UpdateProgram(ctx context.Context, channelID
 		"contents._id": programID,
 	}, database.Update{
 		Set: update,
+		Inc: revisionInc(ctx, channelID),
 	})
 }
 
+// UpdateProgramStatus 更新背景工作維護的節目欄位（不修改節目與頻道的 last_modified 及頻道的修訂版本）
+func (r *MongoDBProgramRepository) UpdateProgramStatus(ctx context.Context, channelID string, programID int, update map[string]interface{}) error {
+	if len(update) == 0 {
+		return nil
+	}
+	return r.collection.UpdateOne(ctx, database.Filter{
+		"_id":          channelID,
+		"contents._id": programID,
+	}, database.Update{Set: update})
+}
+
 // DeletePrograms 刪除節目
 func (r *MongoDBProgramRepository) DeletePrograms(ctx context.Context, channelID string, programIDs []int) error {
 	return r.collection.UpdateOne(ctx, database.Filter{"_id": channelID}, database.Update{
@@ -103,6 +157,7 @@ func (r *MongoDBProgramRepository) DeletePrograms(ctx context.Context, channelID
 		Set: map[string]interface{}{
 			"last_modified": time.Now(),
 		},
+		Inc: revisionInc(ctx, channelID),
 	})
 }
 
@@ -113,6 +168,7 @@ func (r *MongoDBProgramRepository) SetOrder(ctx context.Context, channelID strin
 			"contents_order": order,
 			"last_modified":  time.Now(),
 		},
+		Inc: revisionInc(ctx, channelID),
 	})
 }
 
@@ -0,0 +1,227 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/models"
+)
+
+// PostgresProgramRepository PostgreSQL 節目 Repository
+type PostgresProgramRepository struct {
+	db database.Database
+}
+
+// NewPostgresProgramRepository 建立 PostgreSQL 節目 Repository
+func NewPostgresProgramRepository(db database.Database) *PostgresProgramRepository {
+	return &PostgresProgramRepository{db: db}
+}
+
+// getDB 取得 SQL 資料庫連線（使用 context 中的交易）
+func (r *PostgresProgramRepository) getDB() *database.SQLConn {
+	return r.db.(*database.PostgresDatabase).GetConn()
+}
+
+// nextProgramIDQuery 遞增並取得節目 ID 計數器（不存在時初始化為 1）
+const nextProgramIDQuery = `INSERT INTO counters (id, seq) VALUES ('program_id', 1)
+	ON CONFLICT (id) DO UPDATE SET seq = counters.seq + 1
+	RETURNING seq`
+
+// GetNextProgramID 取得下一個節目 ID（使用 counters 表）
+func (r *PostgresProgramRepository) GetNextProgramID(ctx context.Context) (int, error) {
+	var seq int
+	err := r.getDB().QueryRowContext(ctx, nextProgramIDQuery).Scan(&seq)
+	return seq, err
+}
+
+// AddProgram 新增節目到頻道
+func (r *PostgresProgramRepository) AddProgram(ctx context.Context, channelID string, program *models.Program) error {
+	return r.AddPrograms(ctx, channelID, []*models.Program{program})
+}
+
+// AddPrograms 在同一個交易中依序新增多個節目到頻道（任一節目失敗時全部回復）
+func (r *PostgresProgramRepository) AddPrograms(ctx context.Context, channelID string, programs []*models.Program) error {
+	return r.modify(ctx, channelID, func(tx *database.SQLTx) error {
+		for _, program := range programs {
+			if err := r.addProgramTx(ctx, tx, channelID, program); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// RestorePrograms 以節目原本的 ID 與建立時間新增多個節目到頻道（還原歷史版本使用）
+func (r *PostgresProgramRepository) RestorePrograms(ctx context.Context, channelID string, programs []*models.Program) error {
+	return r.modify(ctx, channelID, func(tx *database.SQLTx) error {
+		for _, program := range programs {
+			if err := insertPostgresProgram(ctx, tx, channelID, program); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// addProgramTx 在交易中新增單一節目（不更新頻道的 last_modified）
+func (r *PostgresProgramRepository) addProgramTx(ctx context.Context, tx *database.SQLTx, channelID string, program *models.Program) error {
+	if err := tx.QueryRowContext(ctx, nextProgramIDQuery).Scan(&program.ID); err != nil {
+		return err
+	}
+	program.Created = time.Now()
+	program.LastModified = program.Created
+
+	return insertPostgresProgram(ctx, tx, channelID, program)
+}
+
+// insertPostgresProgram 在交易中以節目的 ID 插入單一節目與 tags
+func insertPostgresProgram(ctx context.Context, tx *database.SQLTx, channelID string, program *models.Program) error {
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO programs (id, channel_id, name, "desc", duration, type, youtube_id, video_id, metadata_at, availability, last_checked, created, last_modified)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`,
+		program.ID,
+		channelID,
+		program.Name,
+		program.Desc,
+		program.Duration,
+		program.Type,
+		program.YouTubeID,
+		program.VideoID,
+		program.MetadataAt,
+		program.Availability,
+		program.LastChecked,
+		program.Created,
+		program.LastModified,
+	)
+	if err != nil {
+		return err
+	}
+
+	return insertPostgresProgramTags(ctx, tx, program.ID, program.Tags)
+}
+
+// UpdateProgram 更新節目
+func (r *PostgresProgramRepository) UpdateProgram(ctx context.Context, channelID string, programID int, update map[string]interface{}) error {
+	return r.modify(ctx, channelID, func(tx *database.SQLTx) error {
+		var args postgresArgs
+		setParts := []string{"last_modified = " + args.add(time.Now())}
+
+		for key, value := range update {
+			// contents.$ 前綴為 MongoDB 的陣列更新語法
+			key = strings.TrimPrefix(key, "contents.$.")
+
+			if key == "tags" {
+				if _, err := tx.ExecContext(ctx, `DELETE FROM program_tags WHERE program_id = $1`, programID); err != nil {
+					return err
+				}
+				if tags, ok := value.([]int); ok {
+					if err := insertPostgresProgramTags(ctx, tx, programID, tags); err != nil {
+						return err
+					}
+				}
+			} else {
+				setParts = append(setParts, pgx.Identifier{key}.Sanitize()+" = "+args.add(value))
+			}
+		}
+
+		if len(setParts) == 1 {
+			return nil
+		}
+		query := fmt.Sprintf("UPDATE programs SET %s WHERE id = %s AND channel_id = %s",
+			strings.Join(setParts, ", "), args.add(programID), args.add(channelID))
+		_, err := tx.ExecContext(ctx, query, args...)
+		return err
+	})
+}
+
+// UpdateProgramStatus 更新背景工作維護的節目欄位（不修改節目與頻道的 last_modified 及頻道的修訂版本）
+func (r *PostgresProgramRepository) UpdateProgramStatus(ctx context.Context, channelID string, programID int, update map[string]interface{}) error {
+	if len(update) == 0 {
+		return nil
+	}
+
+	var args postgresArgs
+	setParts := make([]string, 0, len(update))
+	for key, value := range update {
+		setParts = append(setParts, pgx.Identifier{strings.TrimPrefix(key, "contents.$.")}.Sanitize()+" = "+args.add(value))
+	}
+	query := fmt.Sprintf("UPDATE programs SET %s WHERE id = %s AND channel_id = %s",
+		strings.Join(setParts, ", "), args.add(programID), args.add(channelID))
+	_, err := r.getDB().ExecContext(ctx, query, args...)
+	return err
+}
+
+// DeletePrograms 刪除節目（節目的 tags 與順序由外鍵串聯刪除）
+func (r *PostgresProgramRepository) DeletePrograms(ctx context.Context, channelID string, programIDs []int) error {
+	if len(programIDs) == 0 {
+		return nil
+	}
+
+	return r.modify(ctx, channelID, func(tx *database.SQLTx) error {
+		result, err := tx.ExecContext(ctx, `DELETE FROM programs WHERE channel_id = $1 AND id = ANY($2)`, channelID, programIDs)
+		if err != nil {
+			return fmt.Errorf("failed to delete programs: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rowsAffected == 0 {
+			return fmt.Errorf("no programs deleted: channel_id=%s, program_ids=%v", channelID, programIDs)
+		}
+		return nil
+	})
+}
+
+// SetOrder 設定節目順序（重複的節目 ID 只保留第一個位置）
+func (r *PostgresProgramRepository) SetOrder(ctx context.Context, channelID string, order []int) error {
+	return r.modify(ctx, channelID, func(tx *database.SQLTx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM channel_program_order WHERE channel_id = $1`, channelID); err != nil {
+			return err
+		}
+		for i, programID := range order {
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO channel_program_order (channel_id, program_id, order_index) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`,
+				channelID, programID, i); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// modify 在交易中修改頻道的節目，並更新頻道的 last_modified 與修訂版本
+func (r *PostgresProgramRepository) modify(ctx context.Context, channelID string, fn func(tx *database.SQLTx) error) error {
+	tx, err := r.getDB().BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE channels SET last_modified = $1, revision = revision + $2 WHERE id = $3`, time.Now(), database.RevisionIncrement(ctx, channelID), channelID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// insertPostgresProgramTags 在交易中插入節目 tags
+func insertPostgresProgramTags(ctx context.Context, tx *database.SQLTx, programID int, tags []int) error {
+	for _, tag := range tags {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO program_tags (program_id, tag) VALUES ($1, $2) ON CONFLICT DO NOTHING`, programID, tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -21,14 +21,14 @@ func NewSQLiteProgramRepository(db database.Database) *SQLiteProgramRepository {
 	return &SQLiteProgramRepository{db: db}
 }
 
-// getDB 取得底層 SQL 資料庫連線
-func (r *SQLiteProgramRepository) getDB() *sql.DB {
+// getDB 取得 SQL 資料庫連線（使用 context 中的交易）
+func (r *SQLiteProgramRepository) getDB() *database.SQLConn {
 	sqliteDB := r.db.(*database.SQLiteDatabase)
-	return sqliteDB.GetDB()
+	return sqliteDB.GetConn()
 }
 
 // getNextProgramIDTx 在交易中取得下一個節目 ID（內部方法）
-func (r *SQLiteProgramRepository) getNextProgramIDTx(ctx context.Context, tx *sql.Tx) (int, error) {
+func (r *SQLiteProgramRepository) getNextProgramIDTx(ctx context.Context, tx *database.SQLTx) (int, error) {
 	// 更新計數器
 	query := `UPDATE counters SET seq = seq + 1 WHERE id = 'program_id'`
 	result, err := tx.ExecContext(ctx, query)
@@ -87,6 +87,22 @@ func (r *SQLiteProgramRepository) GetNextProgramID(ctx context.Context) (int, er
 
 // AddProgram 新增節目到頻道
 func (r *SQLiteProgramRepository) AddProgram(ctx context.Context, channelID string, program *models.Program) error {
+	return r.AddPrograms(ctx, channelID, []*models.Program{program})
+}
+
+// AddPrograms 在同一個交易中依序新增多個節目到頻道（任一節目失敗時全部回復）
+func (r *SQLiteProgramRepository) AddPrograms(ctx context.Context, channelID string, programs []*models.Program) error {
+	return r.insertPrograms(ctx, channelID, programs, r.addProgramTx)
+}
+
+// RestorePrograms 以節目原本的 ID 與建立時間新增多個節目到頻道（還原歷史版本使用）
+func (r *SQLiteProgramRepository) RestorePrograms(ctx context.Context, channelID string, programs []*models.Program) error {
+	return r.insertPrograms(ctx, channelID, programs, r.insertProgramTx)
+}
+
+// insertPrograms 在同一個交易中以 insert 依序新增多個節目（任一節目失敗時全部回復），並更新頻道的 last_modified
+func (r *SQLiteProgramRepository) insertPrograms(ctx context.Context, channelID string, programs []*models.Program,
+	insert func(ctx context.Context, tx *database.SQLTx, channelID string, program *models.Program) error) error {
 	db := r.getDB()
 
 	// 開始交易
@@ -98,6 +114,22 @@ func (r *SQLiteProgramRepository) AddProgram(ctx context.Context, channelID stri
 		_ = tx.Rollback()
 	}()
 
+	for _, program := range programs {
+		if err := insert(ctx, tx, channelID, program); err != nil {
+			return err
+		}
+	}
+
+	// 更新頻道的 last_modified
+	if _, err := tx.ExecContext(ctx, "UPDATE channels SET last_modified = ?, revision = revision + ? WHERE id = ?", time.Now(), database.RevisionIncrement(ctx, channelID), channelID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// addProgramTx 在交易中新增單一節目（不更新頻道的 last_modified）
+func (r *SQLiteProgramRepository) addProgramTx(ctx context.Context, tx *database.SQLTx, channelID string, program *models.Program) error {
 	// 取得下一個節目 ID（使用同一個交易）
 	programID, err := r.getNextProgramIDTx(ctx, tx)
 	if err != nil {
@@ -107,11 +139,15 @@ func (r *SQLiteProgramRepository) AddProgram(ctx context.Context, channelID stri
 	program.Created = time.Now()
 	program.LastModified = time.Now()
 
-	// 插入節目
-	query := `INSERT INTO programs (id, channel_id, name, desc, duration, type, youtube_id, created, last_modified)
-	          VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	return r.insertProgramTx(ctx, tx, channelID, program)
+}
+
+// insertProgramTx 在交易中以節目的 ID 插入單一節目與 tags
+func (r *SQLiteProgramRepository) insertProgramTx(ctx context.Context, tx *database.SQLTx, channelID string, program *models.Program) error {
+	query := `INSERT INTO programs (id, channel_id, name, desc, duration, type, youtube_id, video_id, metadata_at, availability, last_checked, created, last_modified)
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
-	_, err = tx.ExecContext(ctx, query,
+	_, err := tx.ExecContext(ctx, query,
 		program.ID,
 		channelID,
 		program.Name,
@@ -119,6 +155,10 @@ func (r *SQLiteProgramRepository) AddProgram(ctx context.Context, channelID stri
 		program.Duration,
 		program.Type,
 		program.YouTubeID,
+		program.VideoID,
+		program.MetadataAt,
+		program.Availability,
+		program.LastChecked,
 		program.Created,
 		program.LastModified,
 	)
@@ -133,12 +173,7 @@ func (r *SQLiteProgramRepository) AddProgram(ctx context.Context, channelID stri
 		}
 	}
 
-	// 更新頻道的 last_modified
-	if _, err := tx.ExecContext(ctx, "UPDATE channels SET last_modified = ? WHERE id = ?", time.Now(), channelID); err != nil {
-		return err
-	}
-
-	return tx.Commit()
+	return nil
 }
 
 // MigrateProgram 遷移節目（保留原有 ID，用於資料遷移）
@@ -177,8 +212,8 @@ func (r *SQLiteProgramRepository) MigrateProgram(ctx context.Context, channelID
 		return false, err
 	} else {
 		// 節目不存在，插入新節目（保留原有 ID）
-		query := `INSERT INTO programs (id, channel_id, name, desc, duration, type, youtube_id, created, last_modified)
-		          VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+		query := `INSERT INTO programs (id, channel_id, name, desc, duration, type, youtube_id, video_id, metadata_at, availability, last_checked, created, last_modified)
+		          VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
 		result, err := tx.ExecContext(ctx, query,
 			program.ID,
@@ -188,6 +223,10 @@ func (r *SQLiteProgramRepository) MigrateProgram(ctx context.Context, channelID
 			program.Duration,
 			program.Type,
 			program.YouTubeID,
+			program.VideoID,
+			program.MetadataAt,
+			program.Availability,
+			program.LastChecked,
 			program.Created,
 			program.LastModified,
 		)
@@ -211,7 +250,7 @@ func (r *SQLiteProgramRepository) MigrateProgram(ctx context.Context, channelID
 	}
 
 	// 更新頻道的 last_modified
-	if _, err := tx.ExecContext(ctx, "UPDATE channels SET last_modified = ? WHERE id = ?", time.Now(), channelID); err != nil {
+	if _, err := tx.ExecContext(ctx, "UPDATE channels SET last_modified = ?, revision = revision + ? WHERE id = ?", time.Now(), database.RevisionIncrement(ctx, channelID), channelID); err != nil {
 		return false, err
 	}
 
@@ -269,13 +308,31 @@ func (r *SQLiteProgramRepository) UpdateProgram(ctx context.Context, channelID s
 	}
 
 	// 更新頻道的 last_modified
-	if _, err := tx.ExecContext(ctx, "UPDATE channels SET last_modified = ? WHERE id = ?", time.Now(), channelID); err != nil {
+	if _, err := tx.ExecContext(ctx, "UPDATE channels SET last_modified = ?, revision = revision + ? WHERE id = ?", time.Now(), database.RevisionIncrement(ctx, channelID), channelID); err != nil {
 		return err
 	}
 
 	return tx.Commit()
 }
 
+// UpdateProgramStatus 更新背景工作維護的節目欄位（不修改節目與頻道的 last_modified 及頻道的修訂版本）
+func (r *SQLiteProgramRepository) UpdateProgramStatus(ctx context.Context, channelID string, programID int, update map[string]interface{}) error {
+	if len(update) == 0 {
+		return nil
+	}
+
+	setParts := make([]string, 0, len(update))
+	args := make([]interface{}, 0, len(update)+2)
+	for key, value := range update {
+		setParts = append(setParts, fmt.Sprintf("%s = ?", strings.TrimPrefix(key, "contents.$.")))
+		args = append(args, value)
+	}
+	query := fmt.Sprintf("UPDATE programs SET %s WHERE id = ? AND channel_id = ?", strings.Join(setParts, ", "))
+	args = append(args, programID, channelID)
+	_, err := r.getDB().ExecContext(ctx, query, args...)
+	return err
+}
+
 // DeletePrograms 刪除節目
 func (r *SQLiteProgramRepository) DeletePrograms(ctx context.Context, channelID string, programIDs []int) error {
 	if len(programIDs) == 0 {
@@ -323,7 +380,7 @@ func (r *SQLiteProgramRepository) DeletePrograms(ctx context.Context, channelID
 	}
 
 	// 更新頻道的 last_modified
-	if _, err := tx.ExecContext(ctx, "UPDATE channels SET last_modified = ? WHERE id = ?", time.Now(), channelID); err != nil {
+	if _, err := tx.ExecContext(ctx, "UPDATE channels SET last_modified = ?, revision = revision + ? WHERE id = ?", time.Now(), database.RevisionIncrement(ctx, channelID), channelID); err != nil {
 		return err
 	}
 
@@ -357,7 +414,7 @@ func (r *SQLiteProgramRepository) SetOrder(ctx context.Context, channelID string
 	}
 
 	// 更新頻道的 last_modified
-	if _, err := tx.ExecContext(ctx, "UPDATE channels SET last_modified = ? WHERE id = ?", time.Now(), channelID); err != nil {
+	if _, err := tx.ExecContext(ctx, "UPDATE channels SET last_modified = ?, revision = revision + ? WHERE id = ?", time.Now(), database.RevisionIncrement(ctx, channelID), channelID); err != nil {
 		return err
 	}
 
@@ -365,7 +422,7 @@ func (r *SQLiteProgramRepository) SetOrder(ctx context.Context, channelID string
 }
 
 // 輔助方法：插入 program tags（交易版本）
-func (r *SQLiteProgramRepository) insertProgramTagsTx(ctx context.Context, tx *sql.Tx, programID int, tags []int) error {
+func (r *SQLiteProgramRepository) insertProgramTagsTx(ctx context.Context, tx *database.SQLTx, programID int, tags []int) error {
 	if len(tags) == 0 {
 		return nil
 	}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/models"
+)
+
+// MemorySearchRepository 記憶體全文搜尋 Repository（不分大小寫的子字串比對，規則與 SQLite 未啟用 FTS5 時相同）
+type MemorySearchRepository struct {
+	channels *MemoryChannelRepository
+}
+
+// NewMemorySearchRepository 建立記憶體全文搜尋 Repository
+func NewMemorySearchRepository(db database.Database) *MemorySearchRepository {
+	return &MemorySearchRepository{channels: NewMemoryChannelRepository(db)}
+}
+
+// SearchChannels 搜尋頻道名稱與描述（依相關度排序，結果不含節目）
+func (r *MemorySearchRepository) SearchChannels(ctx context.Context, query database.SearchQuery) ([]models.Channel, error) {
+	type hit struct {
+		channel models.Channel
+		score   int
+	}
+
+	hits := []hit{}
+	for _, channel := range r.searchable(query.UserID) {
+		score, ok := matchScore(channel.Name, channel.Desc, query.Terms)
+		if !ok {
+			continue
+		}
+		channel.Contents = nil
+		channel.ContentsOrder = nil
+		hits = append(hits, hit{channel: channel, score: score})
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].score != hits[j].score {
+			return hits[i].score > hits[j].score
+		}
+		return hits[i].channel.LastModified.After(hits[j].channel.LastModified)
+	})
+
+	channels := make([]models.Channel, 0, len(hits))
+	for _, hit := range hits {
+		channels = append(channels, hit.channel)
+	}
+	return paginate(channels, query.Limit, query.Skip), nil
+}
+
+// SearchPrograms 搜尋節目名稱與描述（依相關度排序）
+func (r *MemorySearchRepository) SearchPrograms(ctx context.Context, query database.SearchQuery) ([]models.ProgramSearchResult, error) {
+	type hit struct {
+		result models.ProgramSearchResult
+		score  int
+	}
+
+	hits := []hit{}
+	for _, channel := range r.searchable(query.UserID) {
+		for _, program := range channel.Contents {
+			score, ok := matchScore(program.Name, program.Desc, query.Terms)
+			if !ok {
+				continue
+			}
+			hits = append(hits, hit{
+				result: models.ProgramSearchResult{Program: program, ChannelID: channel.ID, ChannelName: channel.Name},
+				score:  score,
+			})
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].score != hits[j].score {
+			return hits[i].score > hits[j].score
+		}
+		return hits[i].result.Program.ID < hits[j].result.Program.ID
+	})
+
+	results := make([]models.ProgramSearchResult, 0, len(hits))
+	for _, hit := range hits {
+		results = append(results, hit.result)
+	}
+	return paginate(results, query.Limit, query.Skip), nil
+}
+
+// searchable 搜尋者可搜尋到的頻道（公開頻道，或搜尋者可讀取的頻道；不含垃圾桶中的頻道）
+func (r *MemorySearchRepository) searchable(userID string) []models.Channel {
+	channels := []models.Channel{}
+	for _, channel := range r.channels.snapshot() {
+		if channel.DeletedAt != nil {
+			continue
+		}
+		if channel.Visibility == models.ChannelVisibilityPublic || channel.PermissionFor(userID).CanRead() {
+			channels = append(channels, channel)
+		}
+	}
+	return channels
+}
+
+// matchScore 檢查名稱或描述是否包含所有搜尋詞，並計算相關度（名稱符合的權重高於描述）
+func matchScore(name, desc string, terms []string) (int, bool) {
+	name = strings.ToLower(name)
+	desc = strings.ToLower(desc)
+	score := 0
+	for _, term := range terms {
+		term = strings.ToLower(term)
+		inName := strings.Contains(name, term)
+		inDesc := strings.Contains(desc, term)
+		if !inName && !inDesc {
+			return 0, false
+		}
+		if inName {
+			score += 10
+		}
+		if inDesc {
+			score++
+		}
+	}
+	return score, true
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/models"
+)
+
+// MongoDBSearchRepository MongoDB 全文搜尋 Repository（使用 channels 的 text 索引）
+// 需要依 textScore 排序與 aggregation，Collection 抽象不支援，因此直接使用底層連線
+type MongoDBSearchRepository struct {
+	channels *mongo.Collection
+}
+
+// NewMongoDBSearchRepository 建立 MongoDB 全文搜尋 Repository
+func NewMongoDBSearchRepository(db database.Database) *MongoDBSearchRepository {
+	return &MongoDBSearchRepository{
+		channels: db.(*database.MongoDBDatabase).GetDatabase().Collection("channels"),
+	}
+}
+
+// SearchChannels 搜尋頻道名稱與描述（依相關度排序）
+func (r *MongoDBSearchRepository) SearchChannels(ctx context.Context, query database.SearchQuery) ([]models.Channel, error) {
+	filter := searchFilter(query)
+	// text 索引包含節目欄位，另外確認頻道本身的名稱或描述符合
+	filter["$and"] = termConditions(query.Terms, "name", "desc")
+
+	opts := options.Find().
+		SetProjection(bson.M{"score": bson.M{"$meta": "textScore"}, "contents": 0, "contents_order": 0}).
+		SetSort(bson.D{{Key: "score", Value: bson.M{"$meta": "textScore"}}, {Key: "last_modified", Value: -1}}).
+		SetLimit(query.Limit).
+		SetSkip(query.Skip)
+
+	cursor, err := r.channels.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	channels := []models.Channel{}
+	if err := cursor.All(ctx, &channels); err != nil {
+		return nil, err
+	}
+	return channels, nil
+}
+
+// SearchPrograms 搜尋節目名稱與描述（依所屬頻道的相關度排序）
+func (r *MongoDBSearchRepository) SearchPrograms(ctx context.Context, query database.SearchQuery) ([]models.ProgramSearchResult, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: searchFilter(query)}},
+		{{Key: "$addFields", Value: bson.M{"score": bson.M{"$meta": "textScore"}}}},
+		{{Key: "$unwind", Value: "$contents"}},
+		{{Key: "$match", Value: bson.M{"$and": termConditions(query.Terms, "contents.name", "contents.desc")}}},
+		{{Key: "$sort", Value: bson.D{{Key: "score", Value: -1}, {Key: "contents._id", Value: 1}}}},
+		{{Key: "$skip", Value: query.Skip}},
+		{{Key: "$limit", Value: query.Limit}},
+		{{Key: "$project", Value: bson.M{"_id": 0, "program": "$contents", "channel_id": "$_id", "channel_name": "$name"}}},
+	}
+
+	cursor, err := r.channels.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	results := []models.ProgramSearchResult{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// searchFilter 全文搜尋與頻道可見度條件（公開頻道，或搜尋者可讀取的頻道）
+func searchFilter(query database.SearchQuery) bson.M {
+	phrases := make([]string, len(query.Terms))
+	for i, term := range query.Terms {
+		phrases[i] = `"` + strings.ReplaceAll(term, `"`, ``) + `"`
+	}
+
+	filter := bson.M{
+		"$text":      bson.M{"$search": strings.Join(phrases, " ")},
+		"deleted_at": nil,
+	}
+	if query.UserID == "" {
+		filter["visibility"] = string(models.ChannelVisibilityPublic)
+		return filter
+	}
+	filter["$or"] = bson.A{
+		bson.M{"visibility": string(models.ChannelVisibilityPublic)},
+		bson.M{"owners": query.UserID},
+		bson.M{"permission": bson.M{"$elemMatch": bson.M{
+			"user_id": query.UserID,
+			"$or":     bson.A{bson.M{"admin": true}, bson.M{"read": true}, bson.M{"write": true}},
+		}}},
+	}
+	return filter
+}
+
+// termConditions 每個搜尋詞都需出現在名稱或描述中（不分大小寫）
+func termConditions(terms []string, nameField, descField string) bson.A {
+	conditions := bson.A{}
+	for _, term := range terms {
+		pattern := bson.M{"$regex": regexp.QuoteMeta(term), "$options": "i"}
+		conditions = append(conditions, bson.M{"$or": bson.A{
+			bson.M{nameField: pattern},
+			bson.M{descField: pattern},
+		}})
+	}
+	return conditions
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/models"
+)
+
+// PostgresSearchRepository PostgreSQL 全文搜尋 Repository
+// 使用 ILIKE 比對名稱與描述（有 pg_trgm 時由 trigram 索引加速）
+type PostgresSearchRepository struct {
+	db database.Database
+}
+
+// NewPostgresSearchRepository 建立 PostgreSQL 全文搜尋 Repository
+func NewPostgresSearchRepository(db database.Database) *PostgresSearchRepository {
+	return &PostgresSearchRepository{db: db}
+}
+
+// getDB 取得 SQL 資料庫連線（使用 context 中的交易）
+func (r *PostgresSearchRepository) getDB() *database.SQLConn {
+	return r.db.(*database.PostgresDatabase).GetConn()
+}
+
+// SearchChannels 搜尋頻道名稱與描述（依相關度排序）
+func (r *PostgresSearchRepository) SearchChannels(ctx context.Context, query database.SearchQuery) ([]models.Channel, error) {
+	var args postgresArgs
+	visibility := postgresSearchVisibility(&args, "channels", query.UserID)
+	match, score := postgresLikeMatch(&args, "channels.name", `channels."desc"`, query.Terms)
+
+	statement := fmt.Sprintf(`SELECT %s FROM channels
+		WHERE channels.deleted_at IS NULL AND %s AND %s
+		ORDER BY %s DESC, channels.last_modified DESC LIMIT %s OFFSET %s`,
+		channelPostgresSelectColumns, visibility, match, score, args.add(query.Limit), args.add(query.Skip))
+
+	return NewPostgresChannelRepository(r.db).queryChannels(ctx, statement, args...)
+}
+
+// SearchPrograms 搜尋節目名稱與描述（依相關度排序）
+func (r *PostgresSearchRepository) SearchPrograms(ctx context.Context, query database.SearchQuery) ([]models.ProgramSearchResult, error) {
+	var args postgresArgs
+	visibility := postgresSearchVisibility(&args, "channels", query.UserID)
+	match, score := postgresLikeMatch(&args, "programs.name", `programs."desc"`, query.Terms)
+
+	statement := fmt.Sprintf(`SELECT %s, channels.id, channels.name
+		FROM programs
+		JOIN channels ON channels.id = programs.channel_id
+		WHERE channels.deleted_at IS NULL AND %s AND %s
+		ORDER BY %s DESC, programs.id LIMIT %s OFFSET %s`,
+		programPostgresSelectColumns, visibility, match, score, args.add(query.Limit), args.add(query.Skip))
+
+	rows, err := r.getDB().QueryContext(ctx, statement, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	results := []models.ProgramSearchResult{}
+	var ids []int
+	for rows.Next() {
+		var result models.ProgramSearchResult
+		program, err := scanPostgresProgram(rows, &result.ChannelID, &result.ChannelName)
+		if err != nil {
+			return nil, err
+		}
+		result.Program = *program
+		results = append(results, result)
+		ids = append(ids, program.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	tagsMap, err := NewPostgresChannelRepository(r.db).loadProgramTagsBatch(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range results {
+		results[i].Program.Tags = tagsMap[results[i].Program.ID]
+	}
+	return results, nil
+}
+
+// postgresSearchVisibility 搜尋結果的頻道可見度條件（公開頻道，或搜尋者可讀取的頻道）
+func postgresSearchVisibility(args *postgresArgs, table, userID string) string {
+	if userID == "" {
+		return fmt.Sprintf("%s.visibility = 'public'", table)
+	}
+	user := args.add(userID)
+	return fmt.Sprintf(`(%[1]s.visibility = 'public'
+		OR EXISTS (SELECT 1 FROM channel_owners WHERE channel_id = %[1]s.id AND user_id = %[2]s)
+		OR EXISTS (SELECT 1 FROM channel_permissions WHERE channel_id = %[1]s.id AND user_id = %[2]s AND (admin OR read OR write)))`, table, user)
+}
+
+// postgresLikeMatch 建立 ILIKE 比對條件與排序分數（名稱符合的權重高於描述）
+func postgresLikeMatch(args *postgresArgs, nameColumn, descColumn string, terms []string) (string, string) {
+	matches := make([]string, len(terms))
+	scores := make([]string, len(terms))
+	for i, term := range terms {
+		pattern := args.add("%" + likeEscaper.Replace(term) + "%")
+		matches[i] = fmt.Sprintf(`(%[1]s ILIKE %[3]s OR %[2]s ILIKE %[3]s)`, nameColumn, descColumn, pattern)
+		scores[i] = fmt.Sprintf(`COALESCE(%[1]s ILIKE %[3]s, FALSE)::int * 10 + COALESCE(%[2]s ILIKE %[3]s, FALSE)::int`, nameColumn, descColumn, pattern)
+	}
+	return strings.Join(matches, " AND "), "(" + strings.Join(scores, " + ") + ")"
+}
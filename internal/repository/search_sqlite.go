@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/models"
+)
+
+// SQLiteSearchRepository SQLite 全文搜尋 Repository（使用 FTS5，未啟用時改用 LIKE 比對）
+type SQLiteSearchRepository struct {
+	db database.Database
+}
+
+// NewSQLiteSearchRepository 建立 SQLite 全文搜尋 Repository
+func NewSQLiteSearchRepository(db database.Database) *SQLiteSearchRepository {
+	return &SQLiteSearchRepository{db: db}
+}
+
+// getDB 取得 SQL 資料庫連線（使用 context 中的交易）
+func (r *SQLiteSearchRepository) getDB() *database.SQLConn {
+	return r.db.(*database.SQLiteDatabase).GetConn()
+}
+
+// useFTS 是否使用 FTS5 搜尋（trigram 分詞每個搜尋詞至少需要 3 個字元）
+func (r *SQLiteSearchRepository) useFTS(terms []string) bool {
+	if !r.db.(*database.SQLiteDatabase).FullTextSearch() {
+		return false
+	}
+	for _, term := range terms {
+		if utf8.RuneCountInString(term) < 3 {
+			return false
+		}
+	}
+	return true
+}
+
+// SearchChannels 搜尋頻道名稱與描述（依相關度排序）
+func (r *SQLiteSearchRepository) SearchChannels(ctx context.Context, query database.SearchQuery) ([]models.Channel, error) {
+	visibility, visibilityArgs := searchVisibility("channels", query.UserID)
+
+	var statement string
+	var args []interface{}
+	if r.useFTS(query.Terms) {
+		statement = fmt.Sprintf(`SELECT %s FROM channels
+			JOIN (SELECT channel_id, bm25(channels_fts, 0, 10.0, 2.0) AS score FROM channels_fts WHERE channels_fts MATCH ?) AS hits
+			ON hits.channel_id = channels.id
+			WHERE channels.deleted_at IS NULL AND %s
+			ORDER BY hits.score, channels.last_modified DESC LIMIT ? OFFSET ?`, channelSelectColumns, visibility)
+		args = append(args, ftsMatch(query.Terms))
+		args = append(args, visibilityArgs...)
+	} else {
+		match, matchArgs, score, scoreArgs := likeMatch("channels.name", "channels.desc", query.Terms)
+		statement = fmt.Sprintf(`SELECT %s FROM channels
+			WHERE channels.deleted_at IS NULL AND %s AND %s
+			ORDER BY %s DESC, channels.last_modified DESC LIMIT ? OFFSET ?`, channelSelectColumns, visibility, match, score)
+		args = append(args, visibilityArgs...)
+		args = append(args, matchArgs...)
+		args = append(args, scoreArgs...)
+	}
+	args = append(args, query.Limit, query.Skip)
+
+	rows, err := r.getDB().QueryContext(ctx, statement, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	channels := []models.Channel{}
+	for rows.Next() {
+		channel, err := scanChannel(rows)
+		if err != nil {
+			return nil, err
+		}
+		channels = append(channels, *channel)
+	}
+	return channels, rows.Err()
+}
+
+// SearchPrograms 搜尋節目名稱與描述（依相關度排序）
+func (r *SQLiteSearchRepository) SearchPrograms(ctx context.Context, query database.SearchQuery) ([]models.ProgramSearchResult, error) {
+	visibility, visibilityArgs := searchVisibility("c", query.UserID)
+	const columns = `p.id, p.name, p.desc, p.duration, p.type, p.youtube_id, p.video_id, p.metadata_at, p.availability, p.last_checked, p.created, p.last_modified, c.id, c.name`
+
+	var statement string
+	var args []interface{}
+	if r.useFTS(query.Terms) {
+		statement = fmt.Sprintf(`SELECT %s
+			FROM (SELECT rowid AS program_id, bm25(programs_fts, 10.0, 2.0) AS score FROM programs_fts WHERE programs_fts MATCH ?) AS hits
+			JOIN programs p ON p.id = hits.program_id
+			JOIN channels c ON c.id = p.channel_id
+			WHERE c.deleted_at IS NULL AND %s
+			ORDER BY hits.score, p.id LIMIT ? OFFSET ?`, columns, visibility)
+		args = append(args, ftsMatch(query.Terms))
+		args = append(args, visibilityArgs...)
+	} else {
+		match, matchArgs, score, scoreArgs := likeMatch("p.name", "p.desc", query.Terms)
+		statement = fmt.Sprintf(`SELECT %s
+			FROM programs p
+			JOIN channels c ON c.id = p.channel_id
+			WHERE c.deleted_at IS NULL AND %s AND %s
+			ORDER BY %s DESC, p.id LIMIT ? OFFSET ?`, columns, visibility, match, score)
+		args = append(args, visibilityArgs...)
+		args = append(args, matchArgs...)
+		args = append(args, scoreArgs...)
+	}
+	args = append(args, query.Limit, query.Skip)
+
+	rows, err := r.getDB().QueryContext(ctx, statement, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	results := []models.ProgramSearchResult{}
+	var ids []int
+	for rows.Next() {
+		var result models.ProgramSearchResult
+		var desc, videoID, availability sql.NullString
+		var metadataAt, lastChecked sql.NullTime
+		if err := rows.Scan(
+			&result.Program.ID,
+			&result.Program.Name,
+			&desc,
+			&result.Program.Duration,
+			&result.Program.Type,
+			&result.Program.YouTubeID,
+			&videoID,
+			&metadataAt,
+			&availability,
+			&lastChecked,
+			&result.Program.Created,
+			&result.Program.LastModified,
+			&result.ChannelID,
+			&result.ChannelName,
+		); err != nil {
+			return nil, err
+		}
+		result.Program.Desc = desc.String
+		result.Program.VideoID = videoID.String
+		if metadataAt.Valid {
+			result.Program.MetadataAt = &metadataAt.Time
+		}
+		result.Program.Availability = models.Availability(availability.String)
+		if lastChecked.Valid {
+			result.Program.LastChecked = &lastChecked.Time
+		}
+		results = append(results, result)
+		ids = append(ids, result.Program.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	tagsMap, err := NewSQLiteChannelRepository(r.db).loadProgramTagsBatch(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range results {
+		results[i].Program.Tags = tagsMap[results[i].Program.ID]
+	}
+	return results, nil
+}
+
+// searchVisibility 搜尋結果的頻道可見度條件（公開頻道，或搜尋者可讀取的頻道）
+func searchVisibility(table, userID string) (string, []interface{}) {
+	if userID == "" {
+		return fmt.Sprintf("%s.visibility = 'public'", table), nil
+	}
+	return fmt.Sprintf(`(%[1]s.visibility = 'public'
+		OR EXISTS (SELECT 1 FROM channel_owners WHERE channel_id = %[1]s.id AND user_id = ?)
+		OR EXISTS (SELECT 1 FROM channel_permissions WHERE channel_id = %[1]s.id AND user_id = ? AND (admin = 1 OR read = 1 OR write = 1)))`, table), []interface{}{userID, userID}
+}
+
+// ftsMatch 將搜尋詞轉換為 FTS5 查詢（每個搜尋詞視為一個片語，需全部符合）
+func ftsMatch(terms []string) string {
+	phrases := make([]string, len(terms))
+	for i, term := range terms {
+		phrases[i] = `"` + strings.ReplaceAll(term, `"`, `""`) + `"`
+	}
+	return strings.Join(phrases, " ")
+}
+
+// likeMatch 建立 LIKE 比對條件與排序分數（名稱符合的權重高於描述）
+func likeMatch(nameColumn, descColumn string, terms []string) (string, []interface{}, string, []interface{}) {
+	matches := make([]string, len(terms))
+	scores := make([]string, len(terms))
+	var matchArgs, scoreArgs []interface{}
+	for i, term := range terms {
+		pattern := "%" + likeEscaper.Replace(term) + "%"
+		matches[i] = fmt.Sprintf(`(%s LIKE ? ESCAPE '\' OR %s LIKE ? ESCAPE '\')`, nameColumn, descColumn)
+		scores[i] = fmt.Sprintf(`(%s LIKE ? ESCAPE '\') * 10 + (%s LIKE ? ESCAPE '\')`, nameColumn, descColumn)
+		matchArgs = append(matchArgs, pattern, pattern)
+		scoreArgs = append(scoreArgs, pattern, pattern)
+	}
+	return strings.Join(matches, " AND "), matchArgs, "(" + strings.Join(scores, " + ") + ")", scoreArgs
+}
+
+// likeEscaper 跳脫 LIKE 的萬用字元
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/pkg/session"
+)
+
+// MongoDBSessionStore MongoDB 伺服器端 Session 儲存
+type MongoDBSessionStore struct {
+	collection database.Collection
+}
+
+// NewMongoDBSessionStore 建立 MongoDB 伺服器端 Session 儲存
+func NewMongoDBSessionStore(db database.Database) *MongoDBSessionStore {
+	return &MongoDBSessionStore{
+		collection: db.Collection("sessions"),
+	}
+}
+
+// Create 建立 Session
+func (r *MongoDBSessionStore) Create(ctx context.Context, record *session.Record) error {
+	return r.collection.InsertOne(ctx, record)
+}
+
+// Find 依 ID 查詢 Session
+func (r *MongoDBSessionStore) Find(ctx context.Context, id string) (*session.Record, error) {
+	var record session.Record
+	err := r.collection.FindOne(ctx, database.Filter{"_id": id}, &record)
+	if database.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// Touch 更新最後使用時間與到期時間
+func (r *MongoDBSessionStore) Touch(ctx context.Context, id string, lastSeen, expiresAt time.Time) error {
+	return r.collection.UpdateOne(ctx, database.Filter{"_id": id}, database.Update{
+		Set: map[string]interface{}{
+			"last_seen":  lastSeen,
+			"expires_at": expiresAt,
+		},
+	})
+}
+
+// ListByUser 列出使用者尚未過期的 Session（依最後使用時間由新到舊）
+func (r *MongoDBSessionStore) ListByUser(ctx context.Context, userID string, now time.Time) ([]session.Record, error) {
+	records := []session.Record{}
+	filter := database.Filter{
+		"user_id":    userID,
+		"expires_at": database.Filter{"$gt": now},
+	}
+	sort := database.Sort{{Field: "last_seen", Order: -1}}
+	if err := r.collection.Find(ctx, filter, sort, 0, 0, &records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// Delete 刪除 Session
+func (r *MongoDBSessionStore) Delete(ctx context.Context, id string) error {
+	return r.collection.DeleteOne(ctx, database.Filter{"_id": id})
+}
+
+// DeleteByUser 刪除使用者的所有 Session（exceptID 非空時保留該 Session）
+func (r *MongoDBSessionStore) DeleteByUser(ctx context.Context, userID, exceptID string) error {
+	filter := database.Filter{"user_id": userID}
+	if exceptID != "" {
+		filter["_id"] = database.Filter{"$ne": exceptID}
+	}
+	return r.collection.DeleteMany(ctx, filter)
+}
+
+// DeleteExpired 刪除已過期的 Session
+func (r *MongoDBSessionStore) DeleteExpired(ctx context.Context, now time.Time) error {
+	return r.collection.DeleteMany(ctx, database.Filter{
+		"expires_at": database.Filter{"$lte": now},
+	})
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/pkg/session"
+)
+
+// PostgresSessionStore PostgreSQL 伺服器端 Session 儲存
+type PostgresSessionStore struct {
+	db database.Database
+}
+
+// NewPostgresSessionStore 建立 PostgreSQL 伺服器端 Session 儲存
+func NewPostgresSessionStore(db database.Database) *PostgresSessionStore {
+	return &PostgresSessionStore{db: db}
+}
+
+// getDB 取得 SQL 資料庫連線（使用 context 中的交易）
+func (r *PostgresSessionStore) getDB() *database.SQLConn {
+	return r.db.(*database.PostgresDatabase).GetConn()
+}
+
+// Create 建立 Session
+func (r *PostgresSessionStore) Create(ctx context.Context, record *session.Record) error {
+	query := `INSERT INTO sessions (` + sessionSelectColumns + `) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	_, err := r.getDB().ExecContext(ctx, query,
+		record.ID,
+		record.UserID,
+		record.UserAgent,
+		record.IP,
+		record.Created,
+		record.LastSeen,
+		record.ExpiresAt,
+	)
+	return err
+}
+
+// Find 依 ID 查詢 Session
+func (r *PostgresSessionStore) Find(ctx context.Context, id string) (*session.Record, error) {
+	row := r.getDB().QueryRowContext(ctx, `SELECT `+sessionSelectColumns+` FROM sessions WHERE id = $1`, id)
+	record, err := scanSession(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return record, err
+}
+
+// Touch 更新最後使用時間與到期時間
+func (r *PostgresSessionStore) Touch(ctx context.Context, id string, lastSeen, expiresAt time.Time) error {
+	_, err := r.getDB().ExecContext(ctx, `UPDATE sessions SET last_seen = $1, expires_at = $2 WHERE id = $3`,
+		lastSeen, expiresAt, id)
+	return err
+}
+
+// ListByUser 列出使用者尚未過期的 Session（依最後使用時間由新到舊）
+func (r *PostgresSessionStore) ListByUser(ctx context.Context, userID string, now time.Time) ([]session.Record, error) {
+	rows, err := r.getDB().QueryContext(ctx,
+		`SELECT `+sessionSelectColumns+` FROM sessions WHERE user_id = $1 AND expires_at > $2 ORDER BY last_seen DESC`,
+		userID, now)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	records := []session.Record{}
+	for rows.Next() {
+		record, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, *record)
+	}
+	return records, rows.Err()
+}
+
+// Delete 刪除 Session
+func (r *PostgresSessionStore) Delete(ctx context.Context, id string) error {
+	_, err := r.getDB().ExecContext(ctx, `DELETE FROM sessions WHERE id = $1`, id)
+	return err
+}
+
+// DeleteByUser 刪除使用者的所有 Session（exceptID 非空時保留該 Session）
+func (r *PostgresSessionStore) DeleteByUser(ctx context.Context, userID, exceptID string) error {
+	_, err := r.getDB().ExecContext(ctx, `DELETE FROM sessions WHERE user_id = $1 AND id != $2`, userID, exceptID)
+	return err
+}
+
+// DeleteExpired 刪除已過期的 Session
+func (r *PostgresSessionStore) DeleteExpired(ctx context.Context, now time.Time) error {
+	_, err := r.getDB().ExecContext(ctx, `DELETE FROM sessions WHERE expires_at <= $1`, now)
+	return err
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/pkg/session"
+)
+
+// SQLiteSessionStore SQLite 伺服器端 Session 儲存
+// 時間一律以 UTC 儲存，確保字串比較與時間先後一致
+type SQLiteSessionStore struct {
+	db database.Database
+}
+
+// NewSQLiteSessionStore 建立 SQLite 伺服器端 Session 儲存
+func NewSQLiteSessionStore(db database.Database) *SQLiteSessionStore {
+	return &SQLiteSessionStore{db: db}
+}
+
+// getDB 取得 SQL 資料庫連線（使用 context 中的交易）
+func (r *SQLiteSessionStore) getDB() *database.SQLConn {
+	return r.db.(*database.SQLiteDatabase).GetConn()
+}
+
+const sessionSelectColumns = `id, user_id, user_agent, ip, created, last_seen, expires_at`
+
+// scanSession 讀取一筆 Session
+func scanSession(row rowScanner) (*session.Record, error) {
+	var record session.Record
+	if err := row.Scan(
+		&record.ID,
+		&record.UserID,
+		&record.UserAgent,
+		&record.IP,
+		&record.Created,
+		&record.LastSeen,
+		&record.ExpiresAt,
+	); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// Create 建立 Session
+func (r *SQLiteSessionStore) Create(ctx context.Context, record *session.Record) error {
+	query := `INSERT INTO sessions (` + sessionSelectColumns + `) VALUES (?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.getDB().ExecContext(ctx, query,
+		record.ID,
+		record.UserID,
+		record.UserAgent,
+		record.IP,
+		record.Created.UTC(),
+		record.LastSeen.UTC(),
+		record.ExpiresAt.UTC(),
+	)
+	return err
+}
+
+// Find 依 ID 查詢 Session
+func (r *SQLiteSessionStore) Find(ctx context.Context, id string) (*session.Record, error) {
+	row := r.getDB().QueryRowContext(ctx, `SELECT `+sessionSelectColumns+` FROM sessions WHERE id = ?`, id)
+	record, err := scanSession(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return record, err
+}
+
+// Touch 更新最後使用時間與到期時間
+func (r *SQLiteSessionStore) Touch(ctx context.Context, id string, lastSeen, expiresAt time.Time) error {
+	_, err := r.getDB().ExecContext(ctx, `UPDATE sessions SET last_seen = ?, expires_at = ? WHERE id = ?`,
+		lastSeen.UTC(), expiresAt.UTC(), id)
+	return err
+}
+
+// ListByUser 列出使用者尚未過期的 Session（依最後使用時間由新到舊）
+func (r *SQLiteSessionStore) ListByUser(ctx context.Context, userID string, now time.Time) ([]session.Record, error) {
+	rows, err := r.getDB().QueryContext(ctx,
+		`SELECT `+sessionSelectColumns+` FROM sessions WHERE user_id = ? AND expires_at > ? ORDER BY last_seen DESC`,
+		userID, now.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	records := []session.Record{}
+	for rows.Next() {
+		record, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, *record)
+	}
+	return records, rows.Err()
+}
+
+// Delete 刪除 Session
+func (r *SQLiteSessionStore) Delete(ctx context.Context, id string) error {
+	_, err := r.getDB().ExecContext(ctx, `DELETE FROM sessions WHERE id = ?`, id)
+	return err
+}
+
+// DeleteByUser 刪除使用者的所有 Session（exceptID 非空時保留該 Session）
+func (r *SQLiteSessionStore) DeleteByUser(ctx context.Context, userID, exceptID string) error {
+	_, err := r.getDB().ExecContext(ctx, `DELETE FROM sessions WHERE user_id = ? AND id != ?`, userID, exceptID)
+	return err
+}
+
+// DeleteExpired 刪除已過期的 Session
+func (r *SQLiteSessionStore) DeleteExpired(ctx context.Context, now time.Time) error {
+	_, err := r.getDB().ExecContext(ctx, `DELETE FROM sessions WHERE expires_at <= ?`, now.UTC())
+	return err
+}
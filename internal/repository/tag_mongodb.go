@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/models"
+)
+
+// MongoDBTagRepository MongoDB 標籤 Repository
+type MongoDBTagRepository struct {
+	collection database.Collection
+}
+
+// NewMongoDBTagRepository 建立 MongoDB 標籤 Repository
+func NewMongoDBTagRepository(db database.Database) *MongoDBTagRepository {
+	return &MongoDBTagRepository{
+		collection: db.Collection("tags"),
+	}
+}
+
+// Create 建立標籤
+func (r *MongoDBTagRepository) Create(ctx context.Context, tag *models.Tag) error {
+	now := time.Now()
+	tag.Created = now
+	tag.LastModified = now
+	return r.collection.InsertOne(ctx, tag)
+}
+
+// FindByID 依 ID 查詢（找不到時回傳 nil）
+func (r *MongoDBTagRepository) FindByID(ctx context.Context, id int) (*models.Tag, error) {
+	return r.findOne(ctx, database.Filter{"_id": id})
+}
+
+// FindBySlug 依 slug 查詢（找不到時回傳 nil）
+func (r *MongoDBTagRepository) FindBySlug(ctx context.Context, slug string) (*models.Tag, error) {
+	return r.findOne(ctx, database.Filter{"slug": slug})
+}
+
+// findOne 查詢單一標籤
+func (r *MongoDBTagRepository) findOne(ctx context.Context, filter database.Filter) (*models.Tag, error) {
+	var tag models.Tag
+	err := r.collection.FindOne(ctx, filter, &tag)
+	if database.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// List 列出所有標籤（依 ID 排序）
+func (r *MongoDBTagRepository) List(ctx context.Context) ([]models.Tag, error) {
+	tags := []models.Tag{}
+	sort := database.Sort{{Field: "_id", Order: 1}}
+	if err := r.collection.Find(ctx, database.Filter{}, sort, 0, 0, &tags); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// Update 更新標籤的 slug、顯示名稱與上層標籤
+func (r *MongoDBTagRepository) Update(ctx context.Context, tag *models.Tag) error {
+	tag.LastModified = time.Now()
+	matched, err := r.collection.UpdateOneMatched(ctx, database.Filter{"_id": tag.ID}, database.Update{
+		Set: map[string]interface{}{
+			"slug":          tag.Slug,
+			"labels":        tag.Labels,
+			"parent":        tag.Parent,
+			"last_modified": tag.LastModified,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if !matched {
+		return database.ErrNoDocuments
+	}
+	return nil
+}
+
+// Delete 刪除標籤
+func (r *MongoDBTagRepository) Delete(ctx context.Context, id int) error {
+	return r.collection.DeleteOne(ctx, database.Filter{"_id": id})
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/models"
+)
+
+// PostgresTagRepository PostgreSQL 標籤 Repository（labels 以 JSONB 儲存）
+type PostgresTagRepository struct {
+	db database.Database
+}
+
+// NewPostgresTagRepository 建立 PostgreSQL 標籤 Repository
+func NewPostgresTagRepository(db database.Database) *PostgresTagRepository {
+	return &PostgresTagRepository{db: db}
+}
+
+// getDB 取得 SQL 資料庫連線（使用 context 中的交易）
+func (r *PostgresTagRepository) getDB() *database.SQLConn {
+	return r.db.(*database.PostgresDatabase).GetConn()
+}
+
+// Create 建立標籤
+func (r *PostgresTagRepository) Create(ctx context.Context, tag *models.Tag) error {
+	now := time.Now()
+	tag.Created = now
+	tag.LastModified = now
+
+	labels, err := json.Marshal(tag.Labels)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO tags (` + tagSelectColumns + `) VALUES ($1, $2, $3, $4, $5, $6)`
+	_, err = r.getDB().ExecContext(ctx, query,
+		tag.ID,
+		tag.Slug,
+		string(labels),
+		tag.Parent,
+		tag.Created,
+		tag.LastModified,
+	)
+	return err
+}
+
+// FindByID 依 ID 查詢（找不到時回傳 nil）
+func (r *PostgresTagRepository) FindByID(ctx context.Context, id int) (*models.Tag, error) {
+	return r.findOne(ctx, `SELECT `+tagSelectColumns+` FROM tags WHERE id = $1`, id)
+}
+
+// FindBySlug 依 slug 查詢（找不到時回傳 nil）
+func (r *PostgresTagRepository) FindBySlug(ctx context.Context, slug string) (*models.Tag, error) {
+	return r.findOne(ctx, `SELECT `+tagSelectColumns+` FROM tags WHERE slug = $1`, slug)
+}
+
+// findOne 查詢單一標籤
+func (r *PostgresTagRepository) findOne(ctx context.Context, query string, arg interface{}) (*models.Tag, error) {
+	tag, err := scanTag(r.getDB().QueryRowContext(ctx, query, arg))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return tag, err
+}
+
+// List 列出所有標籤（依 ID 排序）
+func (r *PostgresTagRepository) List(ctx context.Context) ([]models.Tag, error) {
+	rows, err := r.getDB().QueryContext(ctx, `SELECT `+tagSelectColumns+` FROM tags ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	tags := []models.Tag{}
+	for rows.Next() {
+		tag, err := scanTag(rows)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, *tag)
+	}
+	return tags, rows.Err()
+}
+
+// Update 更新標籤的 slug、顯示名稱與上層標籤
+func (r *PostgresTagRepository) Update(ctx context.Context, tag *models.Tag) error {
+	tag.LastModified = time.Now()
+	labels, err := json.Marshal(tag.Labels)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.getDB().ExecContext(ctx,
+		`UPDATE tags SET slug = $1, labels = $2, parent = $3, last_modified = $4 WHERE id = $5`,
+		tag.Slug, string(labels), tag.Parent, tag.LastModified, tag.ID,
+	)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return database.ErrNoDocuments
+	}
+	return nil
+}
+
+// Delete 刪除標籤
+func (r *PostgresTagRepository) Delete(ctx context.Context, id int) error {
+	_, err := r.getDB().ExecContext(ctx, `DELETE FROM tags WHERE id = $1`, id)
+	return err
+}
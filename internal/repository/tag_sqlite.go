@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/models"
+)
+
+// SQLiteTagRepository SQLite 標籤 Repository
+type SQLiteTagRepository struct {
+	db database.Database
+}
+
+// NewSQLiteTagRepository 建立 SQLite 標籤 Repository
+func NewSQLiteTagRepository(db database.Database) *SQLiteTagRepository {
+	return &SQLiteTagRepository{db: db}
+}
+
+// getDB 取得 SQL 資料庫連線（使用 context 中的交易）
+func (r *SQLiteTagRepository) getDB() *database.SQLConn {
+	return r.db.(*database.SQLiteDatabase).GetConn()
+}
+
+const tagSelectColumns = `id, slug, labels, parent, created, last_modified`
+
+// scanTag 讀取一筆標籤（labels 以 JSON 儲存）
+func scanTag(row rowScanner) (*models.Tag, error) {
+	var tag models.Tag
+	var labels string
+	var parent sql.NullInt64
+	if err := row.Scan(
+		&tag.ID,
+		&tag.Slug,
+		&labels,
+		&parent,
+		&tag.Created,
+		&tag.LastModified,
+	); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(labels), &tag.Labels); err != nil {
+		return nil, err
+	}
+	if parent.Valid {
+		id := int(parent.Int64)
+		tag.Parent = &id
+	}
+	return &tag, nil
+}
+
+// Create 建立標籤
+func (r *SQLiteTagRepository) Create(ctx context.Context, tag *models.Tag) error {
+	now := time.Now()
+	tag.Created = now
+	tag.LastModified = now
+
+	labels, err := json.Marshal(tag.Labels)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO tags (` + tagSelectColumns + `) VALUES (?, ?, ?, ?, ?, ?)`
+	_, err = r.getDB().ExecContext(ctx, query,
+		tag.ID,
+		tag.Slug,
+		string(labels),
+		tag.Parent,
+		tag.Created,
+		tag.LastModified,
+	)
+	return err
+}
+
+// FindByID 依 ID 查詢（找不到時回傳 nil）
+func (r *SQLiteTagRepository) FindByID(ctx context.Context, id int) (*models.Tag, error) {
+	return r.findOne(ctx, `SELECT `+tagSelectColumns+` FROM tags WHERE id = ?`, id)
+}
+
+// FindBySlug 依 slug 查詢（找不到時回傳 nil）
+func (r *SQLiteTagRepository) FindBySlug(ctx context.Context, slug string) (*models.Tag, error) {
+	return r.findOne(ctx, `SELECT `+tagSelectColumns+` FROM tags WHERE slug = ?`, slug)
+}
+
+// findOne 查詢單一標籤
+func (r *SQLiteTagRepository) findOne(ctx context.Context, query string, arg interface{}) (*models.Tag, error) {
+	tag, err := scanTag(r.getDB().QueryRowContext(ctx, query, arg))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return tag, err
+}
+
+// List 列出所有標籤（依 ID 排序）
+func (r *SQLiteTagRepository) List(ctx context.Context) ([]models.Tag, error) {
+	rows, err := r.getDB().QueryContext(ctx, `SELECT `+tagSelectColumns+` FROM tags ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	tags := []models.Tag{}
+	for rows.Next() {
+		tag, err := scanTag(rows)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, *tag)
+	}
+	return tags, rows.Err()
+}
+
+// Update 更新標籤的 slug、顯示名稱與上層標籤
+func (r *SQLiteTagRepository) Update(ctx context.Context, tag *models.Tag) error {
+	tag.LastModified = time.Now()
+	labels, err := json.Marshal(tag.Labels)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.getDB().ExecContext(ctx,
+		`UPDATE tags SET slug = ?, labels = ?, parent = ?, last_modified = ? WHERE id = ?`,
+		tag.Slug, string(labels), tag.Parent, tag.LastModified, tag.ID,
+	)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return database.ErrNoDocuments
+	}
+	return nil
+}
+
+// Delete 刪除標籤
+func (r *SQLiteTagRepository) Delete(ctx context.Context, id int) error {
+	_, err := r.getDB().ExecContext(ctx, `DELETE FROM tags WHERE id = ?`, id)
+	return err
+}
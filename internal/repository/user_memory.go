@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/models"
+)
+
+// MemoryUserRepository 記憶體使用者 Repository
+type MemoryUserRepository struct {
+	db database.Database
+}
+
+// NewMemoryUserRepository 建立記憶體使用者 Repository
+func NewMemoryUserRepository(db database.Database) *MemoryUserRepository {
+	return &MemoryUserRepository{db: db}
+}
+
+// getStore 取得記憶體資料
+func (r *MemoryUserRepository) getStore() *database.MemoryStore {
+	return r.db.(*database.MemoryDatabase).GetStore()
+}
+
+// find 查詢第一位符合條件的使用者（找不到時回傳 nil）
+func (r *MemoryUserRepository) find(match func(user *models.User) bool) *models.User {
+	store := r.getStore()
+	store.Lock()
+	defer store.Unlock()
+
+	for _, user := range store.Users {
+		if match(user) {
+			return cloneUser(user)
+		}
+	}
+	return nil
+}
+
+// FindByUsername 依使用者名稱查詢
+func (r *MemoryUserRepository) FindByUsername(ctx context.Context, username string) (*models.User, error) {
+	return r.find(func(user *models.User) bool { return user.Username == username }), nil
+}
+
+// FindByEmail 依 Email 查詢
+func (r *MemoryUserRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	return r.find(func(user *models.User) bool { return user.Email == email }), nil
+}
+
+// FindByID 依 ID 查詢
+func (r *MemoryUserRepository) FindByID(ctx context.Context, id string) (*models.User, error) {
+	return r.find(func(user *models.User) bool { return user.ID == id }), nil
+}
+
+// Exists 檢查使用者是否存在
+func (r *MemoryUserRepository) Exists(ctx context.Context, username, email string) (bool, error) {
+	user := r.find(func(user *models.User) bool { return user.Username == username || user.Email == email })
+	return user != nil, nil
+}
+
+// Create 建立使用者（ID、使用者名稱或 Email 重複時回傳錯誤）
+func (r *MemoryUserRepository) Create(ctx context.Context, user *models.User) error {
+	now := time.Now()
+	user.Created = now
+	user.LastModified = now
+	if user.OwnChannels == nil {
+		user.OwnChannels = []string{}
+	}
+
+	store := r.getStore()
+	store.Lock()
+	defer store.Unlock()
+
+	for _, existing := range store.Users {
+		if existing.ID == user.ID || existing.Username == user.Username || existing.Email == user.Email {
+			return fmt.Errorf("duplicate user: %s", user.Username)
+		}
+	}
+	store.Users[user.ID] = cloneUser(user)
+	return nil
+}
+
+// update 修改符合條件的第一位使用者（回傳是否有符合條件的使用者）
+func (r *MemoryUserRepository) update(match func(user *models.User) bool, modify func(user *models.User)) bool {
+	store := r.getStore()
+	store.Lock()
+	defer store.Unlock()
+
+	for _, user := range store.Users {
+		if match(user) {
+			modify(user)
+			user.LastModified = time.Now()
+			return true
+		}
+	}
+	return false
+}
+
+// UpdatePassword 更新密碼
+func (r *MemoryUserRepository) UpdatePassword(ctx context.Context, userID, hashedPassword string) error {
+	r.update(func(user *models.User) bool { return user.ID == userID }, func(user *models.User) {
+		user.Password = hashedPassword
+	})
+	return nil
+}
+
+// SetAccessKey 設定 access_key
+func (r *MemoryUserRepository) SetAccessKey(ctx context.Context, email, accessKey string) error {
+	r.update(func(user *models.User) bool { return user.Email == email }, func(user *models.User) {
+		user.AccessKey = &accessKey
+	})
+	return nil
+}
+
+// ChangePasswordWithAccessKey 使用 access_key 重設密碼
+func (r *MemoryUserRepository) ChangePasswordWithAccessKey(ctx context.Context, email, accessKey, hashedPassword string) (bool, error) {
+	changed := r.update(func(user *models.User) bool {
+		return user.Email == email && user.AccessKey != nil && *user.AccessKey == accessKey
+	}, func(user *models.User) {
+		user.Password = hashedPassword
+		user.AccessKey = nil
+	})
+	return changed, nil
+}
+
+// AddChannel 新增頻道到使用者的 own_channels
+func (r *MemoryUserRepository) AddChannel(ctx context.Context, username, channelID string) error {
+	found := r.update(func(user *models.User) bool { return user.Username == username }, func(user *models.User) {
+		user.OwnChannels = addString(user.OwnChannels, channelID)
+	})
+	if !found {
+		return database.ErrNoDocuments
+	}
+	return nil
+}
+
+// SetUnclassifiedChannel 設定未分類頻道
+func (r *MemoryUserRepository) SetUnclassifiedChannel(ctx context.Context, username, channelID string) error {
+	found := r.update(func(user *models.User) bool { return user.Username == username }, func(user *models.User) {
+		user.UnclassifiedChannel = &channelID
+	})
+	if !found {
+		return database.ErrNoDocuments
+	}
+	return nil
+}
+
+// GetUsersBasicInfo 取得使用者基本資訊（用於 owners_info）
+func (r *MemoryUserRepository) GetUsersBasicInfo(ctx context.Context, userIDs []string) ([]models.UserBasicInfo, error) {
+	store := r.getStore()
+	store.Lock()
+	defer store.Unlock()
+
+	results := []models.UserBasicInfo{}
+	seen := make(map[string]bool)
+	for _, id := range userIDs {
+		user, ok := store.Users[id]
+		if !ok || seen[id] {
+			continue
+		}
+		seen[id] = true
+		results = append(results, models.UserBasicInfo{
+			ID:       user.ID,
+			Username: user.Username,
+			Email:    user.Email,
+		})
+	}
+	return results, nil
+}
+
+// cloneUser 複製使用者（避免呼叫端修改記憶體中的資料）
+func cloneUser(user *models.User) *models.User {
+	clone := *user
+	clone.OwnChannels = cloneStrings(user.OwnChannels)
+	clone.UnclassifiedChannel = cloneStringPtr(user.UnclassifiedChannel)
+	clone.AccessKey = cloneStringPtr(user.AccessKey)
+	clone.InvitationID = cloneStringPtr(user.InvitationID)
+	return &clone
+}
+
+// cloneStrings 複製字串切片（nil 仍為 nil）
+func cloneStrings(values []string) []string {
+	if values == nil {
+		return nil
+	}
+	return append([]string{}, values...)
+}
+
+// cloneStringPtr 複製字串指標
+func cloneStringPtr(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	clone := *value
+	return &clone
+}
+
+// addString 將 value 加入 values（已存在時不重複加入，對應 $addToSet）
+func addString(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+// removeString 從 values 移除 value（對應 $pull）
+func removeString(values []string, value string) []string {
+	result := values[:0]
+	for _, existing := range values {
+		if existing != value {
+			result = append(result, existing)
+		}
+	}
+	return result
+}
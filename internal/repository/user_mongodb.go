@@ -47,6 +47,19 @@ func (r *MongoDBUserRepository) FindByEmail(ctx context.Context, email string) (
 	return &user, nil
 }
 
+// FindByID 依 ID 查詢
+func (r *MongoDBUserRepository) FindByID(ctx context.Context, id string) (*models.User, error) {
+	var user models.User
+	err := r.collection.FindOne(ctx, database.Filter{"_id": id}, &user)
+	if database.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
 // Exists 檢查使用者是否存在
 func (r *MongoDBUserRepository) Exists(ctx context.Context, username, email string) (bool, error) {
 	// MongoDB 的 $or 查詢
@@ -124,8 +137,8 @@ func (r *MongoDBUserRepository) ChangePasswordWithAccessKey(ctx context.Context,
 func (r *MongoDBUserRepository) AddChannel(ctx context.Context, username, channelID string) error {
 	filter := database.Filter{"username": username}
 
-	// 使用 $addToSet 新增頻道 ID
-	err := r.collection.UpdateOne(ctx, filter, database.Update{
+	// 使用 $addToSet 新增頻道 ID（使用者不存在時回傳 ErrNoDocuments，與 SQLite 相同）
+	matched, err := r.collection.UpdateOneMatched(ctx, filter, database.Update{
 		AddToSet: map[string]interface{}{
 			"own_channels": channelID,
 		},
@@ -133,6 +146,9 @@ func (r *MongoDBUserRepository) AddChannel(ctx context.Context, username, channe
 			"last_modified": time.Now(),
 		},
 	})
+	if err == nil && !matched {
+		return database.ErrNoDocuments
+	}
 	if err != nil {
 		// 如果錯誤是因為 own_channels 為 null 或非陣列，先初始化再重試
 		errStr := err.Error()
@@ -161,14 +177,21 @@ func (r *MongoDBUserRepository) AddChannel(ctx context.Context, username, channe
 	return nil
 }
 
-// SetUnclassifiedChannel 設定未分類頻道
+// SetUnclassifiedChannel 設定未分類頻道（使用者不存在時回傳 ErrNoDocuments）
 func (r *MongoDBUserRepository) SetUnclassifiedChannel(ctx context.Context, username, channelID string) error {
-	return r.collection.UpdateOne(ctx, database.Filter{"username": username}, database.Update{
+	matched, err := r.collection.UpdateOneMatched(ctx, database.Filter{"username": username}, database.Update{
 		Set: map[string]interface{}{
 			"unclassified_channel": channelID,
 			"last_modified":         time.Now(),
 		},
 	})
+	if err != nil {
+		return err
+	}
+	if !matched {
+		return database.ErrNoDocuments
+	}
+	return nil
 }
 
 // GetUsersBasicInfo 取得使用者基本資訊（用於 owners_info）
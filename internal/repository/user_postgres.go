@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/models"
+)
+
+// PostgresUserRepository PostgreSQL 使用者 Repository
+type PostgresUserRepository struct {
+	db database.Database
+}
+
+// NewPostgresUserRepository 建立 PostgreSQL 使用者 Repository
+func NewPostgresUserRepository(db database.Database) *PostgresUserRepository {
+	return &PostgresUserRepository{db: db}
+}
+
+// getDB 取得 SQL 資料庫連線（使用 context 中的交易）
+func (r *PostgresUserRepository) getDB() *database.SQLConn {
+	return r.db.(*database.PostgresDatabase).GetConn()
+}
+
+// FindByUsername 依使用者名稱查詢
+func (r *PostgresUserRepository) FindByUsername(ctx context.Context, username string) (*models.User, error) {
+	return r.findOne(ctx, "username", username)
+}
+
+// FindByEmail 依 Email 查詢
+func (r *PostgresUserRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	return r.findOne(ctx, "email", email)
+}
+
+// FindByID 依 ID 查詢
+func (r *PostgresUserRepository) FindByID(ctx context.Context, id string) (*models.User, error) {
+	return r.findOne(ctx, "id", id)
+}
+
+// findOne 依欄位查詢單一使用者（含 own_channels，找不到時回傳 nil）
+func (r *PostgresUserRepository) findOne(ctx context.Context, column, value string) (*models.User, error) {
+	query := `SELECT id, username, email, password, access_key, unclassified_channel, invitation_id, created, last_modified
+	          FROM users WHERE ` + column + ` = $1`
+
+	var user models.User
+	var accessKey, unclassifiedChannel, invitationID sql.NullString
+	err := r.getDB().QueryRowContext(ctx, query, value).Scan(
+		&user.ID,
+		&user.Username,
+		&user.Email,
+		&user.Password,
+		&accessKey,
+		&unclassifiedChannel,
+		&invitationID,
+		&user.Created,
+		&user.LastModified,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if accessKey.Valid {
+		user.AccessKey = &accessKey.String
+	}
+	if unclassifiedChannel.Valid {
+		user.UnclassifiedChannel = &unclassifiedChannel.String
+	}
+	if invitationID.Valid {
+		user.InvitationID = &invitationID.String
+	}
+
+	// 載入 own_channels
+	channels, err := r.loadOwnChannels(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	user.OwnChannels = channels
+
+	return &user, nil
+}
+
+// Exists 檢查使用者是否存在
+func (r *PostgresUserRepository) Exists(ctx context.Context, username, email string) (bool, error) {
+	var exists bool
+	err := r.getDB().QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM users WHERE username = $1 OR email = $2)`, username, email).Scan(&exists)
+	return exists, err
+}
+
+// Create 建立使用者
+func (r *PostgresUserRepository) Create(ctx context.Context, user *models.User) error {
+	now := time.Now()
+	user.Created = now
+	user.LastModified = now
+
+	tx, err := r.getDB().BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO users (id, username, email, password, access_key, unclassified_channel, invitation_id, created, last_modified)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		user.ID,
+		user.Username,
+		user.Email,
+		user.Password,
+		user.AccessKey,
+		user.UnclassifiedChannel,
+		user.InvitationID,
+		user.Created,
+		user.LastModified,
+	)
+	if err != nil {
+		return err
+	}
+
+	// 插入 own_channels
+	for _, channelID := range user.OwnChannels {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO user_channels (user_id, channel_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+			user.ID, channelID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// UpdatePassword 更新密碼
+func (r *PostgresUserRepository) UpdatePassword(ctx context.Context, userID, hashedPassword string) error {
+	_, err := r.getDB().ExecContext(ctx,
+		`UPDATE users SET password = $1, last_modified = $2 WHERE id = $3`, hashedPassword, time.Now(), userID)
+	return err
+}
+
+// SetAccessKey 設定 access_key
+func (r *PostgresUserRepository) SetAccessKey(ctx context.Context, email, accessKey string) error {
+	_, err := r.getDB().ExecContext(ctx,
+		`UPDATE users SET access_key = $1, last_modified = $2 WHERE email = $3`, accessKey, time.Now(), email)
+	return err
+}
+
+// ChangePasswordWithAccessKey 使用 access_key 重設密碼
+func (r *PostgresUserRepository) ChangePasswordWithAccessKey(ctx context.Context, email, accessKey, hashedPassword string) (bool, error) {
+	result, err := r.getDB().ExecContext(ctx,
+		`UPDATE users SET password = $1, access_key = NULL, last_modified = $2 WHERE email = $3 AND access_key = $4`,
+		hashedPassword, time.Now(), email, accessKey)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// AddChannel 新增頻道到使用者的 own_channels
+func (r *PostgresUserRepository) AddChannel(ctx context.Context, username, channelID string) error {
+	db := r.getDB()
+
+	var userID string
+	err := db.QueryRowContext(ctx,
+		`UPDATE users SET last_modified = $1 WHERE username = $2 RETURNING id`, time.Now(), username).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return database.ErrNoDocuments
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx,
+		`INSERT INTO user_channels (user_id, channel_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, userID, channelID)
+	return err
+}
+
+// SetUnclassifiedChannel 設定未分類頻道
+func (r *PostgresUserRepository) SetUnclassifiedChannel(ctx context.Context, username, channelID string) error {
+	result, err := r.getDB().ExecContext(ctx,
+		`UPDATE users SET unclassified_channel = $1, last_modified = $2 WHERE username = $3`, channelID, time.Now(), username)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return database.ErrNoDocuments
+	}
+	return nil
+}
+
+// GetUsersBasicInfo 取得使用者基本資訊（用於 owners_info）
+func (r *PostgresUserRepository) GetUsersBasicInfo(ctx context.Context, userIDs []string) ([]models.UserBasicInfo, error) {
+	if len(userIDs) == 0 {
+		return []models.UserBasicInfo{}, nil
+	}
+
+	rows, err := r.getDB().QueryContext(ctx, `SELECT id, username, email FROM users WHERE id = ANY($1)`, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var results []models.UserBasicInfo
+	for rows.Next() {
+		var info models.UserBasicInfo
+		if err := rows.Scan(&info.ID, &info.Username, &info.Email); err != nil {
+			return nil, err
+		}
+		results = append(results, info)
+	}
+
+	return results, rows.Err()
+}
+
+// loadOwnChannels 載入使用者的 own_channels
+func (r *PostgresUserRepository) loadOwnChannels(ctx context.Context, userID string) ([]string, error) {
+	rows, err := r.getDB().QueryContext(ctx,
+		`SELECT channel_id FROM user_channels WHERE user_id = $1 ORDER BY channel_id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var channels []string
+	for rows.Next() {
+		var channelID string
+		if err := rows.Scan(&channelID); err != nil {
+			return nil, err
+		}
+		channels = append(channels, channelID)
+	}
+
+	return channels, rows.Err()
+}
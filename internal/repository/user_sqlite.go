@@ -19,23 +19,22 @@ func NewSQLiteUserRepository(db database.Database) *SQLiteUserRepository {
 	return &SQLiteUserRepository{db: db}
 }
 
-// getDB 取得底層 SQL 資料庫連線
-func (r *SQLiteUserRepository) getDB() *sql.DB {
-	// 透過反射或類型斷言取得底層 *sql.DB
-	// 這需要在 database 包中提供方法
+// getDB 取得 SQL 資料庫連線（使用 context 中的交易）
+func (r *SQLiteUserRepository) getDB() *database.SQLConn {
 	sqliteDB := r.db.(*database.SQLiteDatabase)
-	return sqliteDB.GetDB()
+	return sqliteDB.GetConn()
 }
 
 // FindByUsername 依使用者名稱查詢
 func (r *SQLiteUserRepository) FindByUsername(ctx context.Context, username string) (*models.User, error) {
 	db := r.getDB()
-	query := `SELECT id, username, email, password, access_key, unclassified_channel, created, last_modified 
+	query := `SELECT id, username, email, password, access_key, unclassified_channel, invitation_id, created, last_modified 
 	          FROM users WHERE username = ?`
 	
 	var user models.User
 	var accessKey sql.NullString
 	var unclassifiedChannel sql.NullString
+	var invitationID sql.NullString
 	
 	err := db.QueryRowContext(ctx, query, username).Scan(
 		&user.ID,
@@ -44,6 +43,7 @@ func (r *SQLiteUserRepository) FindByUsername(ctx context.Context, username stri
 		&user.Password,
 		&accessKey,
 		&unclassifiedChannel,
+		&invitationID,
 		&user.Created,
 		&user.LastModified,
 	)
@@ -60,6 +60,9 @@ func (r *SQLiteUserRepository) FindByUsername(ctx context.Context, username stri
 	if unclassifiedChannel.Valid {
 		user.UnclassifiedChannel = &unclassifiedChannel.String
 	}
+	if invitationID.Valid {
+		user.InvitationID = &invitationID.String
+	}
 
 	// 載入 own_channels
 	channels, err := r.loadOwnChannels(ctx, user.ID)
@@ -74,12 +77,13 @@ func (r *SQLiteUserRepository) FindByUsername(ctx context.Context, username stri
 // FindByEmail 依 Email 查詢
 func (r *SQLiteUserRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
 	db := r.getDB()
-	query := `SELECT id, username, email, password, access_key, unclassified_channel, created, last_modified 
+	query := `SELECT id, username, email, password, access_key, unclassified_channel, invitation_id, created, last_modified 
 	          FROM users WHERE email = ?`
 	
 	var user models.User
 	var accessKey sql.NullString
 	var unclassifiedChannel sql.NullString
+	var invitationID sql.NullString
 	
 	err := db.QueryRowContext(ctx, query, email).Scan(
 		&user.ID,
@@ -88,6 +92,7 @@ func (r *SQLiteUserRepository) FindByEmail(ctx context.Context, email string) (*
 		&user.Password,
 		&accessKey,
 		&unclassifiedChannel,
+		&invitationID,
 		&user.Created,
 		&user.LastModified,
 	)
@@ -104,6 +109,58 @@ func (r *SQLiteUserRepository) FindByEmail(ctx context.Context, email string) (*
 	if unclassifiedChannel.Valid {
 		user.UnclassifiedChannel = &unclassifiedChannel.String
 	}
+	if invitationID.Valid {
+		user.InvitationID = &invitationID.String
+	}
+
+	// 載入 own_channels
+	channels, err := r.loadOwnChannels(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	user.OwnChannels = channels
+
+	return &user, nil
+}
+
+// FindByID 依 ID 查詢
+func (r *SQLiteUserRepository) FindByID(ctx context.Context, id string) (*models.User, error) {
+	db := r.getDB()
+	query := `SELECT id, username, email, password, access_key, unclassified_channel, invitation_id, created, last_modified 
+	          FROM users WHERE id = ?`
+	
+	var user models.User
+	var accessKey sql.NullString
+	var unclassifiedChannel sql.NullString
+	var invitationID sql.NullString
+	
+	err := db.QueryRowContext(ctx, query, id).Scan(
+		&user.ID,
+		&user.Username,
+		&user.Email,
+		&user.Password,
+		&accessKey,
+		&unclassifiedChannel,
+		&invitationID,
+		&user.Created,
+		&user.LastModified,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if accessKey.Valid {
+		user.AccessKey = &accessKey.String
+	}
+	if unclassifiedChannel.Valid {
+		user.UnclassifiedChannel = &unclassifiedChannel.String
+	}
+	if invitationID.Valid {
+		user.InvitationID = &invitationID.String
+	}
 
 	// 載入 own_channels
 	channels, err := r.loadOwnChannels(ctx, user.ID)
@@ -135,8 +192,8 @@ func (r *SQLiteUserRepository) Create(ctx context.Context, user *models.User) er
 	user.Created = now
 	user.LastModified = now
 
-	query := `INSERT INTO users (id, username, email, password, access_key, unclassified_channel, created, last_modified)
-	          VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	query := `INSERT INTO users (id, username, email, password, access_key, unclassified_channel, invitation_id, created, last_modified)
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
 	
 	var accessKey interface{}
 	if user.AccessKey != nil {
@@ -146,6 +203,10 @@ func (r *SQLiteUserRepository) Create(ctx context.Context, user *models.User) er
 	if user.UnclassifiedChannel != nil {
 		unclassifiedChannel = *user.UnclassifiedChannel
 	}
+	var invitationID interface{}
+	if user.InvitationID != nil {
+		invitationID = *user.InvitationID
+	}
 
 	_, err := db.ExecContext(ctx, query,
 		user.ID,
@@ -154,6 +215,7 @@ func (r *SQLiteUserRepository) Create(ctx context.Context, user *models.User) er
 		user.Password,
 		accessKey,
 		unclassifiedChannel,
+		invitationID,
 		user.Created,
 		user.LastModified,
 	)
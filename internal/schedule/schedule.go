@@ -0,0 +1,208 @@
+package schedule
+
+import (
+	"time"
+
+	"github.com/higgstv/higgstv-go/internal/models"
+)
+
+// Slot 節目時段
+type Slot struct {
+	Program models.Program `json:"program"`
+	Start   time.Time      `json:"start"`
+	End     time.Time      `json:"end"`
+}
+
+// Airing 某個時間點正在播出的節目
+type Airing struct {
+	Slot
+	Offset    int   `json:"offset"`    // 已播出秒數
+	Remaining int   `json:"remaining"` // 剩餘秒數
+	Next      *Slot `json:"next,omitempty"`
+}
+
+// Timeline 頻道的線性播出時間軸
+type Timeline struct {
+	Epoch    time.Time
+	Loop     models.ScheduleLoop
+	Programs []models.Program // 可播出的節目（依播出順序）
+	Skipped  []int            // 因長度為 0 而略過的節目 ID
+
+	starts []time.Duration // 各節目在一輪中的開始位移
+	cycle  time.Duration   // 一輪的總長度
+}
+
+// Effective 取得頻道實際使用的播出設定（未設定時以頻道建立時間為起點並循環播出）
+func Effective(channel *models.Channel) models.ChannelSchedule {
+	effective := models.ChannelSchedule{
+		Epoch: channel.Created,
+		Loop:  models.ScheduleLoopRepeat,
+	}
+	if channel.Schedule != nil {
+		if !channel.Schedule.Epoch.IsZero() {
+			effective.Epoch = channel.Schedule.Epoch
+		}
+		if channel.Schedule.Loop == models.ScheduleLoopOnce {
+			effective.Loop = models.ScheduleLoopOnce
+		}
+	}
+	return effective
+}
+
+// Build 依頻道的節目順序建立時間軸
+func Build(channel *models.Channel) *Timeline {
+	return build(Effective(channel), channel.OrderedContents())
+}
+
+// Rebase 計算節目重新排序後的新起點，讓 at 時正在播出的節目在新順序下仍於同一秒播出
+// 只播一輪（once）的頻道起點是固定的播出時間，或 at 時沒有節目播出時，回傳原本的起點
+func Rebase(channel *models.Channel, order []int, at time.Time) time.Time {
+	current := Build(channel)
+	if current.Loop != models.ScheduleLoopRepeat {
+		return current.Epoch
+	}
+	airing := current.At(at)
+	if airing == nil {
+		return current.Epoch
+	}
+
+	next := build(Effective(channel), models.OrderPrograms(channel.Contents, order))
+	for i, program := range next.Programs {
+		if program.ID == airing.Program.ID {
+			return at.Add(-next.starts[i] - time.Duration(airing.Offset)*time.Second)
+		}
+	}
+	return current.Epoch
+}
+
+// Cycle 一輪的總長度
+func (t *Timeline) Cycle() time.Duration {
+	return t.cycle
+}
+
+// At 取得指定時間點正在播出的節目，沒有節目播出時回傳 nil
+func (t *Timeline) At(at time.Time) *Airing {
+	cycleIndex, index, ok := t.locate(at)
+	if !ok {
+		return nil
+	}
+
+	slot := t.slot(cycleIndex, index)
+	airing := &Airing{
+		Slot:      slot,
+		Offset:    int(at.Sub(slot.Start) / time.Second),
+		Remaining: int(slot.End.Sub(at) / time.Second),
+	}
+	if next, ok := t.nextSlot(cycleIndex, index); ok {
+		airing.Next = &next
+	}
+	return airing
+}
+
+// Between 列出與 [from, to) 有重疊的時段，最多 limit 筆（limit <= 0 表示不限制）
+func (t *Timeline) Between(from, to time.Time, limit int) []Slot {
+	slots := []Slot{}
+	if t.cycle <= 0 || !to.After(from) {
+		return slots
+	}
+
+	// from 早於開播時間時從第一個節目開始
+	cycleIndex, index := int64(0), 0
+	if from.After(t.Epoch) {
+		var ok bool
+		cycleIndex, index, ok = t.locate(from)
+		if !ok {
+			return slots
+		}
+	}
+
+	slot := t.slot(cycleIndex, index)
+	for slot.Start.Before(to) {
+		slots = append(slots, slot)
+		if limit > 0 && len(slots) >= limit {
+			break
+		}
+		next, ok := t.nextSlot(cycleIndex, index)
+		if !ok {
+			break
+		}
+		index++
+		if index == len(t.Programs) {
+			index = 0
+			cycleIndex++
+		}
+		slot = next
+	}
+	return slots
+}
+
+// locate 找出指定時間點所在的輪次與節目索引
+func (t *Timeline) locate(at time.Time) (int64, int, bool) {
+	if t.cycle <= 0 || at.Before(t.Epoch) {
+		return 0, 0, false
+	}
+
+	elapsed := at.Sub(t.Epoch)
+	cycleIndex := int64(elapsed / t.cycle)
+	if t.Loop == models.ScheduleLoopOnce && cycleIndex > 0 {
+		return 0, 0, false
+	}
+
+	position := elapsed % t.cycle
+	// 以二分搜尋找出最後一個開始位移 <= position 的節目
+	lo, hi := 0, len(t.starts)-1
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if t.starts[mid] <= position {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	return cycleIndex, lo, true
+}
+
+// slot 取得第 cycleIndex 輪第 index 個節目的時段
+func (t *Timeline) slot(cycleIndex int64, index int) Slot {
+	program := t.Programs[index]
+	start := t.Epoch.Add(time.Duration(cycleIndex)*t.cycle + t.starts[index])
+	return Slot{
+		Program: program,
+		Start:   start,
+		End:     start.Add(time.Duration(program.Duration) * time.Second),
+	}
+}
+
+// nextSlot 取得下一個時段，只播一輪且已是最後一個節目時回傳 false
+func (t *Timeline) nextSlot(cycleIndex int64, index int) (Slot, bool) {
+	index++
+	if index == len(t.Programs) {
+		if t.Loop == models.ScheduleLoopOnce {
+			return Slot{}, false
+		}
+		index = 0
+		cycleIndex++
+	}
+	return t.slot(cycleIndex, index), true
+}
+
+// build 建立時間軸，長度為 0 的節目無法排入播出時間，記錄在 Skipped
+func build(effective models.ChannelSchedule, programs []models.Program) *Timeline {
+	t := &Timeline{
+		Epoch:    effective.Epoch,
+		Loop:     effective.Loop,
+		Programs: []models.Program{},
+		Skipped:  []int{},
+		starts:   []time.Duration{},
+	}
+	for _, program := range programs {
+		if program.Duration <= 0 {
+			t.Skipped = append(t.Skipped, program.ID)
+			continue
+		}
+		t.Programs = append(t.Programs, program)
+		t.starts = append(t.starts, t.cycle)
+		t.cycle += time.Duration(program.Duration) * time.Second
+	}
+	return t
+}
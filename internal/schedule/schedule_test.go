@@ -0,0 +1,136 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/higgstv/higgstv-go/internal/models"
+)
+
+var testEpoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// newTestChannel 建立測試用頻道：節目 1(60s)、2(0s)、3(120s)，順序 3,1,2
+func newTestChannel(loop models.ScheduleLoop) *models.Channel {
+	return &models.Channel{
+		Created: testEpoch.Add(-time.Hour),
+		Contents: []models.Program{
+			{ID: 1, Name: "one", Duration: 60},
+			{ID: 2, Name: "zero", Duration: 0},
+			{ID: 3, Name: "three", Duration: 120},
+		},
+		ContentsOrder: []int{3, 1, 2},
+		Schedule:      &models.ChannelSchedule{Epoch: testEpoch, Loop: loop},
+	}
+}
+
+func TestBuild_SkipsZeroDuration(t *testing.T) {
+	timeline := Build(newTestChannel(models.ScheduleLoopRepeat))
+
+	require.Len(t, timeline.Programs, 2)
+	assert.Equal(t, 3, timeline.Programs[0].ID)
+	assert.Equal(t, 1, timeline.Programs[1].ID)
+	assert.Equal(t, []int{2}, timeline.Skipped)
+	assert.Equal(t, 180*time.Second, timeline.Cycle())
+}
+
+func TestEffective_Defaults(t *testing.T) {
+	channel := newTestChannel(models.ScheduleLoopRepeat)
+	channel.Schedule = nil
+
+	effective := Effective(channel)
+	assert.Equal(t, channel.Created, effective.Epoch)
+	assert.Equal(t, models.ScheduleLoopRepeat, effective.Loop)
+}
+
+func TestAt_Repeat(t *testing.T) {
+	timeline := Build(newTestChannel(models.ScheduleLoopRepeat))
+
+	// 開播前沒有節目
+	assert.Nil(t, timeline.At(testEpoch.Add(-time.Second)))
+
+	airing := timeline.At(testEpoch.Add(130 * time.Second))
+	require.NotNil(t, airing)
+	assert.Equal(t, 1, airing.Program.ID)
+	assert.Equal(t, 10, airing.Offset)
+	assert.Equal(t, 50, airing.Remaining)
+	require.NotNil(t, airing.Next)
+	assert.Equal(t, 3, airing.Next.Program.ID)
+	assert.Equal(t, testEpoch.Add(180*time.Second), airing.Next.Start)
+
+	// 第二輪
+	airing = timeline.At(testEpoch.Add(185 * time.Second))
+	require.NotNil(t, airing)
+	assert.Equal(t, 3, airing.Program.ID)
+	assert.Equal(t, 5, airing.Offset)
+}
+
+func TestAt_Once(t *testing.T) {
+	timeline := Build(newTestChannel(models.ScheduleLoopOnce))
+
+	airing := timeline.At(testEpoch.Add(130 * time.Second))
+	require.NotNil(t, airing)
+	assert.Nil(t, airing.Next)
+
+	assert.Nil(t, timeline.At(testEpoch.Add(180*time.Second)))
+}
+
+func TestAt_NoPlayablePrograms(t *testing.T) {
+	channel := newTestChannel(models.ScheduleLoopRepeat)
+	channel.Contents = []models.Program{{ID: 2, Duration: 0}}
+
+	assert.Nil(t, Build(channel).At(testEpoch.Add(time.Minute)))
+	assert.Empty(t, Build(channel).Between(testEpoch, testEpoch.Add(time.Hour), 0))
+}
+
+func TestBetween(t *testing.T) {
+	timeline := Build(newTestChannel(models.ScheduleLoopRepeat))
+
+	slots := timeline.Between(testEpoch.Add(150*time.Second), testEpoch.Add(400*time.Second), 0)
+	require.Len(t, slots, 4)
+	assert.Equal(t, 1, slots[0].Program.ID)
+	assert.Equal(t, testEpoch.Add(120*time.Second), slots[0].Start)
+	assert.Equal(t, 3, slots[1].Program.ID)
+	assert.Equal(t, 1, slots[2].Program.ID)
+	assert.Equal(t, 3, slots[3].Program.ID)
+	assert.Equal(t, testEpoch.Add(360*time.Second), slots[3].Start)
+
+	limited := timeline.Between(testEpoch, testEpoch.Add(time.Hour), 3)
+	assert.Len(t, limited, 3)
+
+	once := Build(newTestChannel(models.ScheduleLoopOnce))
+	assert.Len(t, once.Between(testEpoch.Add(-time.Hour), testEpoch.Add(time.Hour), 0), 2)
+}
+
+func TestRebase_KeepsCurrentProgram(t *testing.T) {
+	channel := newTestChannel(models.ScheduleLoopRepeat)
+	at := testEpoch.Add(130 * time.Second) // 節目 1 已播 10 秒
+
+	newOrder := []int{1, 2, 3}
+	epoch := Rebase(channel, newOrder, at)
+
+	channel.ContentsOrder = newOrder
+	channel.Schedule.Epoch = epoch
+	airing := Build(channel).At(at)
+	require.NotNil(t, airing)
+	assert.Equal(t, 1, airing.Program.ID)
+	assert.Equal(t, 10, airing.Offset)
+	require.NotNil(t, airing.Next)
+	assert.Equal(t, 3, airing.Next.Program.ID)
+}
+
+func TestRebase_NothingAiring(t *testing.T) {
+	channel := newTestChannel(models.ScheduleLoopOnce)
+	at := testEpoch.Add(time.Hour)
+
+	assert.Equal(t, testEpoch, Rebase(channel, []int{1, 3}, at))
+}
+
+func TestRebase_OnceKeepsEpoch(t *testing.T) {
+	channel := newTestChannel(models.ScheduleLoopOnce)
+	at := testEpoch.Add(130 * time.Second) // 節目 1 正在播出
+
+	assert.Equal(t, testEpoch, Rebase(channel, []int{1, 3}, at))
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/models"
+	"github.com/higgstv/higgstv-go/pkg/session"
+	"github.com/higgstv/higgstv-go/pkg/uuidutil"
+)
+
+// apiTokenPrefix API Token 的固定開頭（方便辨識與掃描外洩的 Token）
+const apiTokenPrefix = "hgtv_"
+
+// apiTokenDisplayLength 列表中顯示的 Token 開頭長度
+const apiTokenDisplayLength = len(apiTokenPrefix) + 6
+
+// apiTokenTouchInterval 更新最後使用時間的最短間隔
+const apiTokenTouchInterval = time.Minute
+
+// maxAPITokenNameLength Token 名稱長度上限
+const maxAPITokenNameLength = 100
+
+// APITokenService 個人 API Token 服務
+type APITokenService struct {
+	tokenRepo database.APITokenRepository
+	userRepo  database.UserRepository
+}
+
+// NewAPITokenService 建立個人 API Token 服務
+func NewAPITokenService(tokenRepo database.APITokenRepository, userRepo database.UserRepository) *APITokenService {
+	return &APITokenService{
+		tokenRepo: tokenRepo,
+		userRepo:  userRepo,
+	}
+}
+
+// CreateToken 建立 API Token，回傳 Token 記錄與 Token 本身（只會回傳這一次）
+func (s *APITokenService) CreateToken(ctx context.Context, userID, name string, scopes []string, expiresAt *time.Time) (*models.APIToken, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxAPITokenNameLength {
+		return nil, "", errors.New("invalid name")
+	}
+	if len(scopes) == 0 {
+		return nil, "", errors.New("invalid scope")
+	}
+	for _, scope := range scopes {
+		if !validScope(scope) {
+			return nil, "", errors.New("invalid scope")
+		}
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, "", errors.New("invalid expiry")
+	}
+
+	raw, err := generateAPIToken()
+	if err != nil {
+		return nil, "", err
+	}
+	token := &models.APIToken{
+		ID:        uuidutil.NewBase64UUID(),
+		UserID:    userID,
+		Name:      name,
+		Prefix:    raw[:apiTokenDisplayLength],
+		TokenHash: hashToken(raw),
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+	}
+	if err := s.tokenRepo.Create(ctx, token); err != nil {
+		return nil, "", err
+	}
+	return token, raw, nil
+}
+
+// ListTokens 列出使用者的 API Token
+func (s *APITokenService) ListTokens(ctx context.Context, userID string) ([]models.APIToken, error) {
+	return s.tokenRepo.ListByUser(ctx, userID)
+}
+
+// RevokeToken 撤銷使用者的 API Token
+func (s *APITokenService) RevokeToken(ctx context.Context, userID, id string) error {
+	err := s.tokenRepo.Delete(ctx, id, userID)
+	if database.IsNotFound(err) {
+		return errors.New("token not found")
+	}
+	return err
+}
+
+// Authenticate 驗證 API Token，回傳使用者身分（Token 無效、過期或使用者不存在時回傳 nil）
+func (s *APITokenService) Authenticate(ctx context.Context, raw string) (*session.Identity, error) {
+	if !strings.HasPrefix(raw, apiTokenPrefix) {
+		return nil, nil
+	}
+	token, err := s.tokenRepo.FindByHash(ctx, hashToken(raw))
+	if err != nil || token == nil {
+		return nil, err
+	}
+	now := time.Now()
+	if !token.Usable(now) {
+		return nil, nil
+	}
+
+	user, err := s.userRepo.FindByID(ctx, token.UserID)
+	if err != nil || user == nil {
+		return nil, err
+	}
+
+	if token.LastUsed == nil || now.Sub(*token.LastUsed) >= apiTokenTouchInterval {
+		_ = s.tokenRepo.Touch(ctx, token.ID, now)
+	}
+
+	identity := &session.Identity{
+		TokenID:  token.ID,
+		UserID:   user.ID,
+		Username: user.Username,
+		Email:    user.Email,
+		Scopes:   token.Scopes,
+	}
+	if user.UnclassifiedChannel != nil {
+		identity.UnclassifiedChannel = *user.UnclassifiedChannel
+	}
+	return identity, nil
+}
+
+// validScope 檢查權限範圍是否有效
+func validScope(scope string) bool {
+	for _, s := range session.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// generateAPIToken 產生隨機 API Token
+func generateAPIToken() (string, error) {
+	token, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+	return apiTokenPrefix + token, nil
+}
+
+// randomToken 產生 32 bytes 的隨機 Token（base64url 編碼）
+func randomToken() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+// hashToken 計算 Token 的雜湊值（Token 為高熵隨機值，使用 SHA-256 即可）
+func hashToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
@@ -5,6 +5,8 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"errors"
+	"strings"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
 
@@ -15,7 +17,10 @@ import (
 
 // AuthService 認證服務
 type AuthService struct {
-	userRepo database.UserRepository
+	userRepo       database.UserRepository
+	invitationRepo database.InvitationRepository
+	shareRepo      database.ChannelShareRepository
+	tx             database.Transactor
 }
 
 // NewAuthService 建立認證服務
@@ -25,6 +30,24 @@ func NewAuthService(userRepo database.UserRepository) *AuthService {
 	}
 }
 
+// WithInvitations 設定註冊時驗證邀請碼使用的 Repository（未設定時無法註冊）
+func (s *AuthService) WithInvitations(invitationRepo database.InvitationRepository) *AuthService {
+	s.invitationRepo = invitationRepo
+	return s
+}
+
+// WithShares 設定以頻道分享邀請註冊時使用的 Repository
+func (s *AuthService) WithShares(shareRepo database.ChannelShareRepository) *AuthService {
+	s.shareRepo = shareRepo
+	return s
+}
+
+// WithTransactions 設定交易（使用邀請碼與建立使用者在同一個交易中完成，nil 表示不使用交易）
+func (s *AuthService) WithTransactions(tx database.Transactor) *AuthService {
+	s.tx = tx
+	return s
+}
+
 // SignIn 登入
 func (s *AuthService) SignIn(ctx context.Context, username, password string) (*models.User, error) {
 	user, err := s.userRepo.FindByUsername(ctx, username)
@@ -45,10 +68,69 @@ func (s *AuthService) SignIn(ctx context.Context, username, password string) (*m
 // SignUp 註冊
 func (s *AuthService) SignUp(ctx context.Context, invitationCode, username, email, password string) (*models.User, error) {
 	// 驗證邀請碼
-	if invitationCode != "sixpens" {
+	if s.invitationRepo == nil {
 		return nil, errors.New("invalid invitation code")
 	}
+	invitation, err := s.invitationRepo.FindByCode(ctx, invitationCode)
+	if err != nil {
+		return nil, err
+	}
+	if invitation == nil || !invitation.Usable(time.Now()) {
+		return nil, errors.New("invalid invitation code")
+	}
+
+	user, err := s.newUser(ctx, username, email, password)
+	if err != nil {
+		return nil, err
+	}
+
+	// 使用邀請碼並建立使用者（建立失敗時不消耗邀請碼）
+	err = withTx(ctx, s.tx, func(ctx context.Context) error {
+		// 檢查後可能已被其他註冊用完
+		invitation, err := s.invitationRepo.Redeem(ctx, invitationCode, time.Now())
+		if err != nil {
+			return err
+		}
+		if invitation == nil {
+			return errors.New("invalid invitation code")
+		}
+
+		user.InvitationID = &invitation.ID
+		return s.userRepo.Create(ctx, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
 
+// SignUpWithShare 以頻道分享邀請取代邀請碼註冊（Email 必須與邀請相同，邀請由呼叫端在註冊後接受）
+func (s *AuthService) SignUpWithShare(ctx context.Context, shareToken, username, email, password string) (*models.User, error) {
+	if s.shareRepo == nil {
+		return nil, errors.New("invalid invitation code")
+	}
+	share, err := s.shareRepo.FindByHash(ctx, hashToken(shareToken))
+	if err != nil {
+		return nil, err
+	}
+	if share == nil || !share.Pending(time.Now()) || !strings.EqualFold(share.Email, email) {
+		return nil, errors.New("invalid invitation code")
+	}
+
+	user, err := s.newUser(ctx, username, email, password)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// newUser 檢查使用者是否存在並建立使用者資料（尚未寫入資料庫）
+func (s *AuthService) newUser(ctx context.Context, username, email, password string) (*models.User, error) {
 	// 檢查使用者是否存在
 	exists, err := s.userRepo.Exists(ctx, username, email)
 	if err != nil {
@@ -64,19 +146,12 @@ func (s *AuthService) SignUp(ctx context.Context, invitationCode, username, emai
 		return nil, err
 	}
 
-	// 建立使用者
-	user := &models.User{
+	return &models.User{
 		ID:       uuidutil.NewBase64UUID(),
 		Username: username,
 		Email:    email,
 		Password: string(hashedPassword),
-	}
-
-	if err := s.userRepo.Create(ctx, user); err != nil {
-		return nil, err
-	}
-
-	return user, nil
+	}, nil
 }
 
 // ChangePassword 變更密碼
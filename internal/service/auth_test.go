@@ -2,140 +2,40 @@ package service
 
 import (
 	"context"
-	"strings"
+	"errors"
 	"testing"
-	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
-	"go.mongodb.org/mongo-driver/bson"
 
-	"github.com/higgstv/higgstv-go/internal/config"
 	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/models"
 	"github.com/higgstv/higgstv-go/internal/repository"
 )
 
-// setupTestDB 建立測試用資料庫連線（支援 MongoDB 和 SQLite）
-// 每個測試使用獨立的資料庫連線，確保測試隔離
+// setupTestDB 建立測試用資料庫（使用記憶體資料庫，不需要 MongoDB 或 SQLite）
+// 每個測試使用獨立的資料庫，確保測試隔離
 func setupTestDB(t *testing.T) (database.Database, func()) {
-	cfg, err := config.Load()
-	if err != nil {
-		t.Fatalf("Failed to load config: %v", err)
-	}
-
-	// 解析資料庫類型
-	dbType, err := database.ParseDatabaseType(cfg.Database.Type)
-	if err != nil {
-		t.Fatalf("Invalid database type: %v", err)
-	}
-
-	// 建立測試資料庫連線（每個測試使用獨立的連線）
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	// 為每個測試建立獨立的資料庫名稱（使用測試名稱）
-	testName := sanitizeTestName(t.Name())
-	testDBName := cfg.Database.Database + "_test_" + testName
-	testURI := cfg.Database.URI
-	
-	if dbType == database.DatabaseTypeSQLite {
-		// SQLite 測試使用獨立的記憶體資料庫（每個測試一個）
-		testURI = "file::memory:?cache=private"
-		testDBName = "test_" + testName
-	}
-
-	db, err := database.NewDatabase(ctx, database.DatabaseConfig{
-		Type:     dbType,
-		URI:      testURI,
-		Database: testDBName,
+	db, err := database.NewDatabase(context.Background(), database.DatabaseConfig{
+		Type: database.DatabaseTypeMemory,
 	})
 	require.NoError(t, err)
 
-	// 清理資料庫（確保測試開始時是乾淨的）
-	_ = cleanupDatabase(ctx, db)
-
-	// 執行遷移和索引建立
-	_ = database.EnsureIndexesWithTimeout(db)
-	// 注意：這裡不執行 migration，因為 service 層測試不需要完整的遷移
-
 	cleanup := func() {
-		// 清理資料庫
-		cleanupCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-		_ = cleanupDatabase(cleanupCtx, db)
-		
-		// 關閉資料庫連線
 		_ = db.Close(context.Background())
 	}
 
 	return db, cleanup
 }
 
-// sanitizeTestName 清理測試名稱（移除不適合作為資料庫名稱的字元）
-func sanitizeTestName(name string) string {
-	name = strings.TrimPrefix(name, "Test")
-	name = strings.ReplaceAll(name, "/", "_")
-	name = strings.ReplaceAll(name, " ", "_")
-	name = strings.ReplaceAll(name, "-", "_")
-	
-	// 限制長度（避免資料庫名稱過長）
-	if len(name) > 50 {
-		name = name[:50]
-	}
-	
-	return name
-}
-
-// cleanupDatabase 清理資料庫（刪除所有資料）
-func cleanupDatabase(ctx context.Context, db database.Database) error {
-	switch db.Type() {
-	case database.DatabaseTypeMongoDB:
-		return cleanupMongoDB(ctx, db)
-	case database.DatabaseTypeSQLite:
-		return cleanupSQLite(ctx, db)
-	default:
-		return nil
-	}
-}
-
-// cleanupMongoDB 清理 MongoDB 資料庫
-func cleanupMongoDB(ctx context.Context, db database.Database) error {
-	mongoDB := db.(*database.MongoDBDatabase)
-	mongoDatabase := mongoDB.GetDatabase()
-	
-	collections, err := mongoDatabase.ListCollectionNames(ctx, bson.M{})
-	if err != nil {
-		return err
-	}
-	
-	for _, collName := range collections {
-		coll := mongoDatabase.Collection(collName)
-		_, _ = coll.DeleteMany(ctx, bson.M{})
-	}
-	
-	return nil
-}
-
-// cleanupSQLite 清理 SQLite 資料庫
-func cleanupSQLite(ctx context.Context, db database.Database) error {
-	sqliteDB := db.(*database.SQLiteDatabase)
-	sqlDB := sqliteDB.GetDB()
-	
-	_, _ = sqlDB.ExecContext(ctx, "PRAGMA foreign_keys = OFF")
-	
-	tables := []string{
-		"users", "channels", "programs", "counters", "migrations",
-		"user_channels", "channel_tags", "channel_owners", "channel_permissions",
-		"program_tags", "channel_program_order",
-	}
-	
-	for _, table := range tables {
-		_, _ = sqlDB.ExecContext(ctx, "DELETE FROM "+table)
-	}
-	
-	_, _ = sqlDB.ExecContext(ctx, "PRAGMA foreign_keys = ON")
-	
-	return nil
+// newTestInvitationRepo 建立邀請碼 Repository 並新增不限次數的測試邀請碼 sixpens
+func newTestInvitationRepo(t *testing.T, db database.Database) database.InvitationRepository {
+	invitationRepo := repository.NewInvitationRepository(db)
+	require.NoError(t, invitationRepo.Create(context.Background(), &models.Invitation{
+		ID:   "test-invitation",
+		Code: "sixpens",
+	}))
+	return invitationRepo
 }
 
 func TestAuthService_SignUp(t *testing.T) {
@@ -143,7 +43,7 @@ func TestAuthService_SignUp(t *testing.T) {
 	defer cleanup()
 
 	userRepo := repository.NewUserRepository(db)
-	authService := NewAuthService(userRepo)
+	authService := NewAuthService(userRepo).WithInvitations(newTestInvitationRepo(t, db))
 
 	ctx := context.Background()
 
@@ -173,12 +73,52 @@ func TestAuthService_SignUp(t *testing.T) {
 	})
 }
 
+// failingCreateUserRepo 建立使用者一律失敗的使用者 Repository（模擬檢查後才發生的重複註冊）
+type failingCreateUserRepo struct {
+	database.UserRepository
+}
+
+func (r failingCreateUserRepo) Create(ctx context.Context, user *models.User) error {
+	return errors.New("create user failed")
+}
+
+func TestAuthService_SignUpRollsBackRedeem(t *testing.T) {
+	db := setupSQLiteTestDB(t)
+
+	ctx := context.Background()
+	invitationRepo := repository.NewInvitationRepository(db)
+	require.NoError(t, invitationRepo.Create(ctx, &models.Invitation{
+		ID:      "single-use",
+		Code:    "once",
+		MaxUses: 1,
+	}))
+
+	authService := NewAuthService(failingCreateUserRepo{repository.NewUserRepository(db)}).
+		WithInvitations(invitationRepo).
+		WithTransactions(db)
+	_, err := authService.SignUp(ctx, "once", "testuser", "test@example.com", "password123")
+	require.EqualError(t, err, "create user failed")
+
+	// 建立使用者失敗時邀請碼不會被消耗
+	invitation, err := invitationRepo.FindByCode(ctx, "once")
+	require.NoError(t, err)
+	require.NotNil(t, invitation)
+	assert.Equal(t, 0, invitation.Uses)
+
+	user, err := NewAuthService(repository.NewUserRepository(db)).
+		WithInvitations(invitationRepo).
+		WithTransactions(db).
+		SignUp(ctx, "once", "testuser", "test@example.com", "password123")
+	require.NoError(t, err)
+	assert.Equal(t, "single-use", *user.InvitationID)
+}
+
 func TestAuthService_SignIn(t *testing.T) {
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
 
 	userRepo := repository.NewUserRepository(db)
-	authService := NewAuthService(userRepo)
+	authService := NewAuthService(userRepo).WithInvitations(newTestInvitationRepo(t, db))
 
 	ctx := context.Background()
 
@@ -205,4 +145,3 @@ func TestAuthService_SignIn(t *testing.T) {
 		assert.Equal(t, "invalid password", err.Error())
 	})
 }
-
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/models"
+	"github.com/higgstv/higgstv-go/pkg/availability"
+)
+
+// AvailabilityService 節目可播放狀態服務
+type AvailabilityService struct {
+	channelRepo database.ChannelRepository
+	programRepo database.ProgramRepository
+	checker     availability.Checker
+}
+
+// NewAvailabilityService 建立節目可播放狀態服務（只產生報表時 checker 可為 nil）
+func NewAvailabilityService(channelRepo database.ChannelRepository, programRepo database.ProgramRepository, checker availability.Checker) *AvailabilityService {
+	return &AvailabilityService{
+		channelRepo: channelRepo,
+		programRepo: programRepo,
+		checker:     checker,
+	}
+}
+
+// AvailabilityReport 頻道節目可播放狀態報表
+type AvailabilityReport struct {
+	ChannelID   string                `json:"channel_id"`
+	Total       int                   `json:"total"`
+	Available   int                   `json:"available"`
+	Unavailable int                   `json:"unavailable"`
+	Unknown     int                   `json:"unknown"`
+	Unchecked   int                   `json:"unchecked"`
+	Programs    []ProgramAvailability `json:"programs"`
+}
+
+// ProgramAvailability 單一節目的可播放狀態
+type ProgramAvailability struct {
+	ID           int                 `json:"_id"`
+	Name         string              `json:"name"`
+	Type         models.ProgramType  `json:"type"`
+	VideoID      string              `json:"video_id"`
+	Availability models.Availability `json:"availability"`
+	LastChecked  *time.Time          `json:"last_checked,omitempty"`
+}
+
+// Report 產生頻道節目可播放狀態報表（依節目順序，filter 非空時只列出該狀態的節目）
+func (s *AvailabilityService) Report(ctx context.Context, channelID string, filter models.Availability) (*AvailabilityReport, error) {
+	channel, err := s.channelRepo.FindByID(ctx, channelID)
+	if err != nil {
+		return nil, err
+	}
+	if channel == nil {
+		return nil, errors.New("channel not found")
+	}
+
+	report := &AvailabilityReport{
+		ChannelID: channel.ID,
+		Programs:  []ProgramAvailability{},
+	}
+	for _, program := range channel.OrderedContents() {
+		report.Total++
+		switch program.Availability {
+		case models.AvailabilityAvailable:
+			report.Available++
+		case models.AvailabilityUnavailable:
+			report.Unavailable++
+		case models.AvailabilityUnknown:
+			report.Unknown++
+		default:
+			report.Unchecked++
+		}
+
+		if filter != "" && program.Availability != filter {
+			continue
+		}
+		report.Programs = append(report.Programs, ProgramAvailability{
+			ID:           program.ID,
+			Name:         program.Name,
+			Type:         program.MediaType(),
+			VideoID:      program.MediaID(),
+			Availability: program.Availability,
+			LastChecked:  program.LastChecked,
+		})
+	}
+	return report, nil
+}
+
+// CheckAll 檢查所有頻道中超過 recheckAfter 未檢查的節目，回傳已檢查的節目數（limit 為 0 時不限制）
+func (s *AvailabilityService) CheckAll(ctx context.Context, recheckAfter time.Duration, limit int) (int, error) {
+	if s.checker == nil {
+		return 0, errors.New("availability checker is not configured")
+	}
+
+	checked := 0
+	threshold := time.Now().Add(-recheckAfter)
+	err := walkChannels(ctx, s.channelRepo, func(channel *models.Channel) (bool, error) {
+		for _, program := range channel.Contents {
+			if limit > 0 && checked >= limit {
+				return false, nil
+			}
+			if program.LastChecked != nil && program.LastChecked.After(threshold) {
+				continue
+			}
+
+			if err := s.checkProgram(ctx, channel.ID, program); err != nil {
+				return false, err
+			}
+			checked++
+		}
+		return true, nil
+	})
+	return checked, err
+}
+
+// checkProgram 檢查單一節目並寫回狀態
+// 暫時無法判斷時只更新檢查時間，保留原本的狀態
+func (s *AvailabilityService) checkProgram(ctx context.Context, channelID string, program models.Program) error {
+	status, err := s.checker.Check(ctx, string(program.MediaType()), program.MediaID())
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	update := map[string]interface{}{
+		"contents.$.last_checked": time.Now(),
+	}
+	if err == nil {
+		update["contents.$.availability"] = models.Availability(status)
+	}
+	return s.programRepo.UpdateProgramStatus(ctx, channelID, program.ID, update)
+}
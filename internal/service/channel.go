@@ -11,8 +11,11 @@ import (
 
 // ChannelService 頻道服務
 type ChannelService struct {
-	channelRepo database.ChannelRepository
-	userRepo    database.UserRepository
+	channelRepo  database.ChannelRepository
+	userRepo     database.UserRepository
+	revisionRepo database.ChannelRevisionRepository
+	tagRepo      database.TagRepository
+	tx           database.Transactor
 }
 
 // NewChannelService 建立頻道服務
@@ -23,6 +26,24 @@ func NewChannelService(channelRepo database.ChannelRepository, userRepo database
 	}
 }
 
+// WithHistory 設定頻道歷史版本 Repository（每次修改頻道後記錄頻道快照，nil 表示不記錄）
+func (s *ChannelService) WithHistory(revisionRepo database.ChannelRevisionRepository) *ChannelService {
+	s.revisionRepo = revisionRepo
+	return s
+}
+
+// WithTags 設定標籤 Repository（新增頻道時檢查標籤、以標籤 slug 過濾頻道列表，nil 表示不使用）
+func (s *ChannelService) WithTags(tagRepo database.TagRepository) *ChannelService {
+	s.tagRepo = tagRepo
+	return s
+}
+
+// WithTransactions 設定交易（建立頻道並加入使用者的 own_channels 等多個步驟在同一個交易中完成，nil 表示不使用交易）
+func (s *ChannelService) WithTransactions(tx database.Transactor) *ChannelService {
+	s.tx = tx
+	return s
+}
+
 // CreateDefaultChannel 建立預設頻道
 func (s *ChannelService) CreateDefaultChannel(ctx context.Context, userID, username string) (*models.Channel, error) {
 	channel := &models.Channel{
@@ -36,14 +57,22 @@ func (s *ChannelService) CreateDefaultChannel(ctx context.Context, userID, usern
 		ContentsOrder: []int{},
 		Owners:        []string{userID},
 		Permission:    []models.ChannelPermission{},
+		Visibility:    models.ChannelVisibilityPublic,
 	}
 
-	if err := s.channelRepo.Create(ctx, channel); err != nil {
-		return nil, err
-	}
+	err := withTx(ctx, s.tx, func(ctx context.Context) error {
+		if err := s.channelRepo.Create(ctx, channel); err != nil {
+			return err
+		}
 
-	// 新增頻道到使用者的 own_channels
-	if err := s.userRepo.AddChannel(ctx, username, channel.ID); err != nil {
+		// 新增頻道到使用者的 own_channels
+		if err := s.userRepo.AddChannel(ctx, username, channel.ID); err != nil {
+			return err
+		}
+
+		return recordRevision(ctx, s.revisionRepo, s.channelRepo, channel.ID, models.RevisionActionCreate)
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -63,19 +92,27 @@ func (s *ChannelService) CreateUnclassifiedChannel(ctx context.Context, userID,
 		ContentsOrder: []int{},
 		Owners:        []string{userID},
 		Permission:    []models.ChannelPermission{},
+		Visibility:    models.ChannelVisibilityPublic,
 	}
 
-	if err := s.channelRepo.Create(ctx, channel); err != nil {
-		return nil, err
-	}
+	err := withTx(ctx, s.tx, func(ctx context.Context) error {
+		if err := s.channelRepo.Create(ctx, channel); err != nil {
+			return err
+		}
 
-	// 新增頻道到使用者的 own_channels
-	if err := s.userRepo.AddChannel(ctx, username, channel.ID); err != nil {
-		return nil, err
-	}
+		// 新增頻道到使用者的 own_channels
+		if err := s.userRepo.AddChannel(ctx, username, channel.ID); err != nil {
+			return err
+		}
+
+		if err := recordRevision(ctx, s.revisionRepo, s.channelRepo, channel.ID, models.RevisionActionCreate); err != nil {
+			return err
+		}
 
-	// 設定使用者的 unclassified_channel
-	if err := s.userRepo.SetUnclassifiedChannel(ctx, username, channel.ID); err != nil {
+		// 設定使用者的 unclassified_channel
+		return s.userRepo.SetUnclassifiedChannel(ctx, username, channel.ID)
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -87,6 +124,9 @@ func (s *ChannelService) AddChannel(ctx context.Context, userID, username, name
 	if name == "" {
 		return nil, errors.New("name is required")
 	}
+	if err := validateTags(ctx, s.tagRepo, tags); err != nil {
+		return nil, err
+	}
 
 	channel := &models.Channel{
 		ID:            uuidutil.NewBase64UUID(),
@@ -99,14 +139,22 @@ func (s *ChannelService) AddChannel(ctx context.Context, userID, username, name
 		ContentsOrder: []int{},
 		Owners:        []string{userID},
 		Permission:    []models.ChannelPermission{},
+		Visibility:    models.ChannelVisibilityPublic,
 	}
 
-	if err := s.channelRepo.Create(ctx, channel); err != nil {
-		return nil, err
-	}
+	err := withTx(ctx, s.tx, func(ctx context.Context) error {
+		if err := s.channelRepo.Create(ctx, channel); err != nil {
+			return err
+		}
+
+		// 新增頻道到使用者的 own_channels
+		if err := s.userRepo.AddChannel(ctx, username, channel.ID); err != nil {
+			return err
+		}
 
-	// 新增頻道到使用者的 own_channels
-	if err := s.userRepo.AddChannel(ctx, username, channel.ID); err != nil {
+		return recordRevision(ctx, s.revisionRepo, s.channelRepo, channel.ID, models.RevisionActionCreate)
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -135,7 +183,93 @@ func (s *ChannelService) UpdateChannel(ctx context.Context, channelID string, na
 		return errors.New("no fields to update")
 	}
 
-	return s.channelRepo.Update(ctx, channelID, update)
+	return s.update(ctx, channelID, update)
+}
+
+// ChannelUpdate 儲存頻道的欄位（空字串與 nil 表示不修改）
+type ChannelUpdate struct {
+	Name            string
+	Desc            string
+	Tags            []int
+	HideUnavailable *bool
+	Visibility      models.ChannelVisibility
+}
+
+// SaveChannel 一次更新頻道的多個欄位（只記錄一個歷史版本）
+func (s *ChannelService) SaveChannel(ctx context.Context, channelID string, changes ChannelUpdate) error {
+	update := make(map[string]interface{})
+	if changes.Name != "" {
+		update["name"] = changes.Name
+	}
+	if changes.Desc != "" {
+		update["desc"] = changes.Desc
+	}
+	if changes.Tags != nil {
+		update["tags"] = changes.Tags
+	}
+	if changes.HideUnavailable != nil {
+		update["hide_unavailable"] = *changes.HideUnavailable
+	}
+	if changes.Visibility != "" {
+		if !changes.Visibility.Valid() {
+			return errors.New("invalid visibility")
+		}
+		update["visibility"] = changes.Visibility
+	}
+
+	if len(update) == 0 {
+		return errors.New("no fields to update")
+	}
+
+	return s.update(ctx, channelID, update)
+}
+
+// SetHideUnavailable 設定取得頻道時是否隱藏無法播放的節目
+func (s *ChannelService) SetHideUnavailable(ctx context.Context, channelID string, hide bool) error {
+	return s.update(ctx, channelID, map[string]interface{}{
+		"hide_unavailable": hide,
+	})
+}
+
+// SetVisibility 設定頻道可見度（public、unlisted 或 private）
+func (s *ChannelService) SetVisibility(ctx context.Context, channelID string, visibility models.ChannelVisibility) error {
+	if !visibility.Valid() {
+		return errors.New("invalid visibility")
+	}
+	return s.update(ctx, channelID, map[string]interface{}{
+		"visibility": visibility,
+	})
+}
+
+// update 更新頻道並記錄歷史版本
+func (s *ChannelService) update(ctx context.Context, channelID string, update map[string]interface{}) error {
+	if err := s.channelRepo.Update(ctx, channelID, update); err != nil {
+		return err
+	}
+	return recordRevision(ctx, s.revisionRepo, s.channelRepo, channelID, models.RevisionActionUpdate)
+}
+
+// GetRevision 取得頻道目前的修訂版本
+func (s *ChannelService) GetRevision(ctx context.Context, channelID string) (int64, error) {
+	return s.channelRepo.GetRevision(ctx, channelID)
+}
+
+// WithRevision 確認頻道修訂版本與請求指定的版本相同並遞增後執行 fn（revision 為 nil 時直接執行 fn），避免覆蓋其他人的修改
+// 比對與 fn 在同一個交易中執行，fn 內的修改不再遞增修訂版本，fn 回傳錯誤時修訂版本一併還原
+func (s *ChannelService) WithRevision(ctx context.Context, channelID string, revision *int64, fn func(ctx context.Context) error) error {
+	if revision == nil {
+		return fn(ctx)
+	}
+	return withTx(ctx, s.tx, func(ctx context.Context) error {
+		err := s.channelRepo.ClaimRevision(ctx, channelID, *revision)
+		if err == database.ErrRevisionMismatch {
+			return errors.New("revision conflict")
+		}
+		if err != nil {
+			return err
+		}
+		return fn(database.WithClaimedRevision(ctx, channelID))
+	})
 }
 
 // ListChannels 列出頻道
@@ -143,13 +277,248 @@ func (s *ChannelService) ListChannels(ctx context.Context, filter database.Filte
 	return s.channelRepo.ListChannels(ctx, filter, sort, limit, skip)
 }
 
+// ChannelQuery 公開頻道列表的查詢條件（對應 GetChannels 的參數）
+type ChannelQuery struct {
+	User        string   // 只列出特定使用者的頻道（username）
+	Q           string   // 以名稱模糊搜尋
+	HasContents bool     // 只列出有節目的頻道
+	IgnoreTypes []string // 要排除的頻道類型
+	Tag         string   // 標籤 slug（含下層標籤，需透過 tagRepo 解析）
+	Sort        string   // 排序欄位：last_modified 或 name
+	Desc        bool     // 是否遞減排序
+	Limit       int64
+	Skip        int64
+}
+
+// Filter 轉換為資料庫過濾條件與排序（User 與 Tag 需另外透過 userRepo、tagRepo 解析）
+func (q ChannelQuery) Filter() (database.Filter, database.Sort) {
+	filter := database.Filter{}
+
+	if q.Q != "" {
+		filter["name"] = database.Filter{"$regex": q.Q, "$options": "i"}
+	}
+	if q.HasContents {
+		filter["contents.0"] = database.Filter{"$exists": true}
+	}
+	if len(q.IgnoreTypes) > 0 {
+		filter["type"] = database.Filter{"$nin": q.IgnoreTypes}
+	}
+	// 只列出公開頻道（不公開列出與私人頻道需透過 ID 讀取）
+	filter["visibility"] = string(models.ChannelVisibilityPublic)
+
+	order := 1
+	if q.Desc {
+		order = -1
+	}
+	var sort database.Sort
+	if q.Sort == "name" {
+		sort = database.Sort{{Field: "name", Order: order}}
+	} else {
+		sort = database.Sort{{Field: "last_modified", Order: order}}
+	}
+
+	return filter, sort
+}
+
+// QueryChannels 依查詢條件列出頻道（使用者不存在時回傳空列表）
+func (s *ChannelService) QueryChannels(ctx context.Context, query ChannelQuery) ([]models.Channel, error) {
+	filter, sort := query.Filter()
+
+	if query.User != "" {
+		user, err := s.userRepo.FindByUsername(ctx, query.User)
+		if err != nil || user == nil {
+			return []models.Channel{}, nil
+		}
+		filter["owners"] = user.ID
+	}
+
+	if query.Tag != "" && s.tagRepo != nil {
+		ids, err := resolveTagSlug(ctx, s.tagRepo, query.Tag)
+		if err != nil {
+			return nil, err
+		}
+		if len(ids) == 0 {
+			return []models.Channel{}, nil
+		}
+		filter["tags"] = database.Filter{"$in": ids}
+	}
+
+	return s.channelRepo.ListChannels(ctx, filter, sort, query.Limit, query.Skip)
+}
+
 // IsAdmin 檢查是否為頻道管理員
 func (s *ChannelService) IsAdmin(ctx context.Context, channelID, userID string) (bool, error) {
 	return s.channelRepo.IsAdmin(ctx, channelID, userID)
 }
 
+// CanRead 檢查是否可以讀取頻道（頻道不存在時回傳 false）
+func (s *ChannelService) CanRead(ctx context.Context, channelID, userID string) (bool, error) {
+	channel, err := s.channelRepo.FindByID(ctx, channelID)
+	if err != nil || channel == nil {
+		return false, err
+	}
+	return channel.Readable(userID), nil
+}
+
+// CanWrite 檢查是否可以新增、編輯與排序頻道的節目（擁有者、admin 或 write 權限）
+func (s *ChannelService) CanWrite(ctx context.Context, channelID, userID string) (bool, error) {
+	permission, err := s.channelRepo.GetPermission(ctx, channelID, userID)
+	if err != nil {
+		return false, err
+	}
+	return permission.CanWrite(), nil
+}
+
+// 頻道權限名稱
+const (
+	PermissionAdmin = "admin"
+	PermissionRead  = "read"
+	PermissionWrite = "write"
+)
+
+// SetPermission 授予或撤銷使用者的單一頻道權限（擁有者的權限不能變更）
+func (s *ChannelService) SetPermission(ctx context.Context, channelID, userID, permission string, granted bool) error {
+	if permission != PermissionAdmin && permission != PermissionRead && permission != PermissionWrite {
+		return errors.New("invalid permission")
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return errors.New("user not found")
+	}
+
+	channel, err := s.channelRepo.FindByID(ctx, channelID)
+	if err != nil {
+		return err
+	}
+	if channel == nil {
+		return errors.New("channel not found")
+	}
+	if channel.IsOwner(userID) {
+		return errors.New("owner permission cannot be changed")
+	}
+
+	current := channel.PermissionFor(userID)
+	switch permission {
+	case PermissionAdmin:
+		current.Admin = granted
+	case PermissionRead:
+		current.Read = granted
+	case PermissionWrite:
+		current.Write = granted
+	}
+	if err := s.channelRepo.SetPermission(ctx, channelID, current); err != nil {
+		return err
+	}
+	return recordRevision(ctx, s.revisionRepo, s.channelRepo, channelID, models.RevisionActionPermission)
+}
+
 // AddOwners 新增擁有者
 func (s *ChannelService) AddOwners(ctx context.Context, channelID string, userIDs []string) error {
-	return s.channelRepo.AddOwners(ctx, channelID, userIDs)
+	if err := s.channelRepo.AddOwners(ctx, channelID, userIDs); err != nil {
+		return err
+	}
+	return recordRevision(ctx, s.revisionRepo, s.channelRepo, channelID, models.RevisionActionOwners)
 }
 
+// RemoveOwner 移除擁有者（擁有者可以移除其他擁有者或自己離開頻道），不能移除最後一位擁有者
+func (s *ChannelService) RemoveOwner(ctx context.Context, channelID, actorID, ownerID string) error {
+	channel, err := s.channelRepo.FindByID(ctx, channelID)
+	if err != nil {
+		return err
+	}
+	if channel == nil {
+		return errors.New("channel not found")
+	}
+	if !channel.IsOwner(actorID) {
+		return errors.New("permission denied")
+	}
+	if !channel.IsOwner(ownerID) {
+		return errors.New("not an owner")
+	}
+
+	if err := s.channelRepo.RemoveOwner(ctx, channelID, ownerID); err != nil {
+		return ownerError(err)
+	}
+	return recordRevision(ctx, s.revisionRepo, s.channelRepo, channelID, models.RevisionActionOwners)
+}
+
+// TransferOwnership 將自己的擁有權移轉給其他使用者（未分類頻道不能移轉）
+func (s *ChannelService) TransferOwnership(ctx context.Context, channelID, fromUserID, toUserID string) error {
+	channel, err := s.channelRepo.FindByID(ctx, channelID)
+	if err != nil {
+		return err
+	}
+	if channel == nil {
+		return errors.New("channel not found")
+	}
+	if !channel.IsOwner(fromUserID) {
+		return errors.New("permission denied")
+	}
+	if channel.Type == models.ChannelTypeUnclassified {
+		return errors.New("unclassified channel cannot be transferred")
+	}
+	if toUserID == fromUserID {
+		return errors.New("invalid owner")
+	}
+
+	user, err := s.userRepo.FindByID(ctx, toUserID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return errors.New("user not found")
+	}
+
+	if err := s.channelRepo.TransferOwnership(ctx, channelID, fromUserID, toUserID); err != nil {
+		return ownerError(err)
+	}
+	return recordRevision(ctx, s.revisionRepo, s.channelRepo, channelID, models.RevisionActionOwners)
+}
+
+// ownerError 轉換移除或移轉擁有者時 Repository 回傳的錯誤
+func ownerError(err error) error {
+	if err == database.ErrLastOwner {
+		return errors.New("last owner")
+	}
+	if database.IsNotFound(err) {
+		return errors.New("not an owner")
+	}
+	return err
+}
+
+// walkPageSize 逐一處理所有頻道時每次讀取的頻道數
+const walkPageSize = 100
+
+// walkChannels 依建立時間逐一讀取所有頻道（含節目內容），fn 回傳 false 時停止
+func walkChannels(ctx context.Context, channelRepo database.ChannelRepository, fn func(channel *models.Channel) (bool, error)) error {
+	sort := database.Sort{{Field: "created", Order: 1}}
+	for skip := int64(0); ; skip += walkPageSize {
+		channels, err := channelRepo.ListChannels(ctx, database.Filter{}, sort, walkPageSize, skip)
+		if err != nil {
+			return err
+		}
+
+		for _, summary := range channels {
+			// 列表不一定包含節目內容，重新查詢完整頻道
+			channel, err := channelRepo.FindByID(ctx, summary.ID)
+			if err != nil {
+				return err
+			}
+			if channel == nil {
+				continue
+			}
+			more, err := fn(channel)
+			if err != nil || !more {
+				return err
+			}
+		}
+
+		if len(channels) < walkPageSize {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/models"
+	"github.com/higgstv/higgstv-go/pkg/uuidutil"
+)
+
+// ForkService 頻道複製服務
+type ForkService struct {
+	channelRepo  database.ChannelRepository
+	programRepo  database.ProgramRepository
+	userRepo     database.UserRepository
+	revisionRepo database.ChannelRevisionRepository
+	tx           database.Transactor
+}
+
+// NewForkService 建立頻道複製服務
+func NewForkService(channelRepo database.ChannelRepository, programRepo database.ProgramRepository, userRepo database.UserRepository) *ForkService {
+	return &ForkService{
+		channelRepo: channelRepo,
+		programRepo: programRepo,
+		userRepo:    userRepo,
+	}
+}
+
+// WithHistory 設定頻道歷史版本 Repository（複製後記錄新頻道的快照，nil 表示不記錄）
+func (s *ForkService) WithHistory(revisionRepo database.ChannelRevisionRepository) *ForkService {
+	s.revisionRepo = revisionRepo
+	return s
+}
+
+// WithTransactions 設定交易（建立頻道、複製節目與更新來源頻道的複製次數在同一個交易中完成，nil 表示不使用交易）
+func (s *ForkService) WithTransactions(tx database.Transactor) *ForkService {
+	s.tx = tx
+	return s
+}
+
+// ForkChannel 複製使用者可以讀取的頻道為使用者擁有的新頻道
+// 複製頻道資訊、標籤、節目（取得新的節目 ID）與節目順序，不複製擁有者、權限與播出設定；name 為空時沿用來源頻道名稱
+func (s *ForkService) ForkChannel(ctx context.Context, sourceID, userID, username, name string) (*models.Channel, error) {
+	source, err := s.channelRepo.FindByID(ctx, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	if source == nil || !source.Readable(userID) {
+		return nil, errors.New("channel not found")
+	}
+
+	if name == "" {
+		name = source.Name
+	}
+	tags := append([]int{}, source.Tags...)
+	var cover *models.ChannelCover
+	if source.Cover != nil {
+		cover = &models.ChannelCover{Default: source.Cover.Default}
+	}
+
+	channel := &models.Channel{
+		ID:              uuidutil.NewBase64UUID(),
+		Type:            models.ChannelTypeDefault,
+		Name:            name,
+		Desc:            source.Desc,
+		Tags:            tags,
+		Cover:           cover,
+		ContentsSeq:     "",
+		Contents:        []models.Program{},
+		ContentsOrder:   []int{},
+		Owners:          []string{userID},
+		Permission:      []models.ChannelPermission{},
+		HideUnavailable: source.HideUnavailable,
+		Visibility:      source.Visibility,
+		ForkedFrom:      source.ID,
+	}
+	err = withTx(ctx, s.tx, func(ctx context.Context) error {
+		if err := s.channelRepo.Create(ctx, channel); err != nil {
+			return err
+		}
+
+		// 新增頻道到使用者的 own_channels
+		if err := s.userRepo.AddChannel(ctx, username, channel.ID); err != nil {
+			return err
+		}
+
+		if err := copyPrograms(ctx, s.programRepo, channel.ID, source.Contents, source.ContentsOrder); err != nil {
+			return err
+		}
+
+		if err := s.channelRepo.IncrementForkCount(ctx, source.ID); err != nil {
+			return err
+		}
+
+		return recordRevision(ctx, s.revisionRepo, s.channelRepo, channel.ID, models.RevisionActionFork)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.channelRepo.FindByID(ctx, channel.ID)
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/models"
+	"github.com/higgstv/higgstv-go/internal/repository"
+)
+
+func TestForkService_ForkChannelRollsBack(t *testing.T) {
+	db := setupSQLiteTestDB(t)
+
+	ctx := context.Background()
+	channelRepo := repository.NewChannelRepository(db)
+	programRepo := repository.NewProgramRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	revisionRepo := repository.NewChannelRevisionRepository(db)
+
+	require.NoError(t, userRepo.Create(ctx, &models.User{
+		ID:       "alice",
+		Username: "alice",
+		Email:    "alice@example.com",
+		Password: "hashed-alice",
+	}))
+	require.NoError(t, channelRepo.Create(ctx, &models.Channel{
+		ID:         "ch-source",
+		Type:       models.ChannelTypeDefault,
+		Name:       "Source",
+		Owners:     []string{"alice"},
+		Visibility: models.ChannelVisibilityPublic,
+	}))
+	_, err := NewProgramService(programRepo, channelRepo).
+		AddProgram(ctx, "ch-source", "First", models.ProgramTypeYouTube, "dQw4w9WgXcQ", "", 60, []int{}, false)
+	require.NoError(t, err)
+
+	forkService := NewForkService(channelRepo, failingAddProgramsRepo{programRepo}, userRepo).
+		WithHistory(revisionRepo).
+		WithTransactions(db)
+	_, err = forkService.ForkChannel(ctx, "ch-source", "alice", "alice", "Fork")
+	require.EqualError(t, err, "add programs failed")
+
+	// 複製失敗時不留下新頻道，使用者的 own_channels 與來源頻道的複製次數維持原樣
+	channels, err := channelRepo.ListChannels(ctx, database.Filter{}, nil, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, channels, 1)
+	assert.Equal(t, "ch-source", channels[0].ID)
+	assert.Equal(t, 0, channels[0].ForkCount)
+	user, err := userRepo.FindByID(ctx, "alice")
+	require.NoError(t, err)
+	assert.Empty(t, user.OwnChannels)
+}
@@ -0,0 +1,272 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/models"
+	"github.com/higgstv/higgstv-go/pkg/uuidutil"
+)
+
+// recordRevision 記錄頻道目前的快照（revisionRepo 為 nil 時不記錄，垃圾桶中的頻道同樣記錄）
+func recordRevision(ctx context.Context, revisionRepo database.ChannelRevisionRepository, channelRepo database.ChannelRepository, channelID, action string) error {
+	if revisionRepo == nil {
+		return nil
+	}
+	channel, err := channelRepo.FindByID(ctx, channelID)
+	if err == nil && channel == nil {
+		channel, err = channelRepo.FindDeleted(ctx, channelID)
+	}
+	if err != nil || channel == nil {
+		return err
+	}
+	return revisionRepo.Create(ctx, &models.ChannelRevision{
+		ID:        uuidutil.NewBase64UUID(),
+		ChannelID: channelID,
+		Revision:  channel.Revision,
+		Action:    action,
+		Snapshot:  models.NewChannelSnapshot(channel),
+	})
+}
+
+// HistoryService 頻道歷史版本服務
+type HistoryService struct {
+	revisionRepo database.ChannelRevisionRepository
+	channelRepo  database.ChannelRepository
+	programRepo  database.ProgramRepository
+	tx           database.Transactor
+}
+
+// NewHistoryService 建立頻道歷史版本服務
+func NewHistoryService(revisionRepo database.ChannelRevisionRepository, channelRepo database.ChannelRepository, programRepo database.ProgramRepository) *HistoryService {
+	return &HistoryService{
+		revisionRepo: revisionRepo,
+		channelRepo:  channelRepo,
+		programRepo:  programRepo,
+	}
+}
+
+// WithTransactions 設定交易（還原頻道資訊、節目與記錄歷史版本在同一個交易中完成，nil 表示不使用交易）
+func (s *HistoryService) WithTransactions(tx database.Transactor) *HistoryService {
+	s.tx = tx
+	return s
+}
+
+// ListRevisions 列出頻道的歷史版本（依建立時間由新到舊，需可讀取頻道）
+func (s *HistoryService) ListRevisions(ctx context.Context, channelID, userID string, limit, skip int64) ([]models.ChannelRevision, error) {
+	if _, err := s.readableChannel(ctx, channelID, userID); err != nil {
+		return nil, err
+	}
+	return s.revisionRepo.ListByChannel(ctx, channelID, limit, skip)
+}
+
+// FieldChange 頻道欄位的變更
+type FieldChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// ProgramChange 節目的變更（同一個節目 ID 的前後內容）
+type ProgramChange struct {
+	ID   int            `json:"_id"`
+	From models.Program `json:"from"`
+	To   models.Program `json:"to"`
+}
+
+// RevisionDiff 兩個頻道歷史版本的差異
+type RevisionDiff struct {
+	From         string                 `json:"from"`
+	To           string                 `json:"to"`
+	Fields       map[string]FieldChange `json:"fields"`
+	Added        []models.Program       `json:"added"`
+	Removed      []models.Program       `json:"removed"`
+	Changed      []ProgramChange        `json:"changed"`
+	OrderChanged bool                   `json:"order_changed"`
+	FromOrder    []int                  `json:"from_order"` // 實際播放順序
+	ToOrder      []int                  `json:"to_order"`
+}
+
+// DiffRevisions 比較頻道的兩個歷史版本（需可讀取頻道）
+func (s *HistoryService) DiffRevisions(ctx context.Context, channelID, userID, fromID, toID string) (*RevisionDiff, error) {
+	if _, err := s.readableChannel(ctx, channelID, userID); err != nil {
+		return nil, err
+	}
+	from, err := s.findRevision(ctx, channelID, fromID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.findRevision(ctx, channelID, toID)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := DiffSnapshots(from.Snapshot, to.Snapshot)
+	diff.From = from.ID
+	diff.To = to.ID
+	return diff, nil
+}
+
+// DiffSnapshots 比較兩個頻道快照
+func DiffSnapshots(from, to models.ChannelSnapshot) *RevisionDiff {
+	diff := &RevisionDiff{
+		Fields:  map[string]FieldChange{},
+		Added:   []models.Program{},
+		Removed: []models.Program{},
+		Changed: []ProgramChange{},
+	}
+
+	fields := []struct {
+		name     string
+		from, to interface{}
+	}{
+		{"name", from.Name, to.Name},
+		{"desc", from.Desc, to.Desc},
+		{"tags", from.Tags, to.Tags},
+		{"cover", from.Cover, to.Cover},
+		{"hide_unavailable", from.HideUnavailable, to.HideUnavailable},
+		{"visibility", from.Visibility, to.Visibility},
+		{"schedule", from.Schedule, to.Schedule},
+	}
+	for _, field := range fields {
+		if !reflect.DeepEqual(field.from, field.to) {
+			diff.Fields[field.name] = FieldChange{From: field.from, To: field.to}
+		}
+	}
+
+	before := make(map[int]models.Program, len(from.Contents))
+	for _, program := range from.Contents {
+		before[program.ID] = program
+	}
+	after := make(map[int]bool, len(to.Contents))
+	for _, program := range to.Contents {
+		after[program.ID] = true
+		old, ok := before[program.ID]
+		if !ok {
+			diff.Added = append(diff.Added, program)
+			continue
+		}
+		if programChanged(old, program) {
+			diff.Changed = append(diff.Changed, ProgramChange{ID: program.ID, From: old, To: program})
+		}
+	}
+	for _, program := range from.Contents {
+		if !after[program.ID] {
+			diff.Removed = append(diff.Removed, program)
+		}
+	}
+
+	diff.FromOrder = programIDs(models.OrderPrograms(from.Contents, from.ContentsOrder))
+	diff.ToOrder = programIDs(models.OrderPrograms(to.Contents, to.ContentsOrder))
+	diff.OrderChanged = !reflect.DeepEqual(diff.FromOrder, diff.ToOrder)
+	return diff
+}
+
+// RestoreRevision 將頻道還原為指定的歷史版本（需為頻道管理員）
+// 節目保留快照中的節目 ID，還原後記錄一個新的歷史版本；任一步驟失敗時不會留下部分還原的頻道
+func (s *HistoryService) RestoreRevision(ctx context.Context, channelID, userID, revisionID string) (*models.ChannelRevision, error) {
+	isAdmin, err := s.channelRepo.IsAdmin(ctx, channelID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin {
+		return nil, errors.New("permission denied")
+	}
+	revision, err := s.findRevision(ctx, channelID, revisionID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = withTx(ctx, s.tx, func(ctx context.Context) error {
+		channel, err := s.channelRepo.FindByID(ctx, channelID)
+		if err != nil {
+			return err
+		}
+		if channel == nil {
+			return errors.New("channel not found")
+		}
+
+		// 快照沒有封面或播出設定時清除頻道目前的設定
+		snapshot := revision.Snapshot
+		update := map[string]interface{}{
+			"name":             snapshot.Name,
+			"desc":             snapshot.Desc,
+			"tags":             snapshot.Tags,
+			"cover":            snapshot.Cover,
+			"hide_unavailable": snapshot.HideUnavailable,
+			"visibility":       snapshot.Visibility,
+			"schedule":         snapshot.Schedule,
+		}
+		if err := s.channelRepo.Update(ctx, channelID, update); err != nil {
+			return err
+		}
+
+		// 以快照的節目取代目前的節目（節目 ID 不會重複使用，快照中的 ID 只可能屬於這個頻道）
+		if len(channel.Contents) > 0 {
+			if err := s.programRepo.DeletePrograms(ctx, channelID, programIDs(channel.Contents)); err != nil {
+				return err
+			}
+		}
+		programs := make([]*models.Program, 0, len(snapshot.Contents))
+		for i := range snapshot.Contents {
+			program := snapshot.Contents[i]
+			programs = append(programs, &program)
+		}
+		if err := s.programRepo.RestorePrograms(ctx, channelID, programs); err != nil {
+			return err
+		}
+		if err := s.programRepo.SetOrder(ctx, channelID, snapshot.ContentsOrder); err != nil {
+			return err
+		}
+
+		return recordRevision(ctx, s.revisionRepo, s.channelRepo, channelID, models.RevisionActionRestore)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return revision, nil
+}
+
+// readableChannel 取得使用者可以讀取的頻道
+func (s *HistoryService) readableChannel(ctx context.Context, channelID, userID string) (*models.Channel, error) {
+	channel, err := s.channelRepo.FindByID(ctx, channelID)
+	if err != nil {
+		return nil, err
+	}
+	if channel == nil || !channel.Readable(userID) {
+		return nil, errors.New("permission denied")
+	}
+	return channel, nil
+}
+
+// findRevision 取得屬於頻道的歷史版本
+func (s *HistoryService) findRevision(ctx context.Context, channelID, revisionID string) (*models.ChannelRevision, error) {
+	revision, err := s.revisionRepo.FindByID(ctx, revisionID)
+	if err != nil {
+		return nil, err
+	}
+	if revision == nil || revision.ChannelID != channelID {
+		return nil, errors.New("revision not found")
+	}
+	return revision, nil
+}
+
+// programChanged 檢查節目內容是否有變更（不比較修改時間與可播放狀態的檢查時間）
+func programChanged(from, to models.Program) bool {
+	return from.Name != to.Name ||
+		from.Desc != to.Desc ||
+		from.Duration != to.Duration ||
+		from.MediaType() != to.MediaType() ||
+		from.MediaID() != to.MediaID() ||
+		!reflect.DeepEqual(from.Tags, to.Tags)
+}
+
+// programIDs 取得節目 ID 列表
+func programIDs(programs []models.Program) []int {
+	ids := make([]int, 0, len(programs))
+	for _, program := range programs {
+		ids = append(ids, program.ID)
+	}
+	return ids
+}
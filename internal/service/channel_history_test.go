@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/migration"
+	"github.com/higgstv/higgstv-go/internal/models"
+	"github.com/higgstv/higgstv-go/internal/repository"
+)
+
+// setupSQLiteTestDB 建立測試用的 SQLite 記憶體資料庫並執行遷移（記憶體資料庫沒有歷史版本 Repository）
+func setupSQLiteTestDB(t *testing.T) database.Database {
+	ctx := context.Background()
+	db, err := database.NewDatabase(ctx, database.DatabaseConfig{
+		Type: database.DatabaseTypeSQLite,
+		URI:  "file::memory:?cache=private",
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close(context.Background()) })
+
+	require.NoError(t, migration.RunMigrations(ctx, db))
+	return db
+}
+
+// failingAddProgramsRepo 新增節目一律失敗的節目 Repository
+type failingAddProgramsRepo struct {
+	database.ProgramRepository
+}
+
+func (r failingAddProgramsRepo) AddPrograms(ctx context.Context, channelID string, programs []*models.Program) error {
+	return errors.New("add programs failed")
+}
+
+func (r failingAddProgramsRepo) RestorePrograms(ctx context.Context, channelID string, programs []*models.Program) error {
+	return errors.New("add programs failed")
+}
+
+func TestHistoryService_RestoreRevision(t *testing.T) {
+	memoryDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	for name, db := range map[string]database.Database{"memory": memoryDB, "sqlite": setupSQLiteTestDB(t)} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			channelRepo := repository.NewChannelRepository(db)
+			programRepo := repository.NewProgramRepository(db)
+			revisionRepo := repository.NewChannelRevisionRepository(db)
+			programService := NewProgramService(programRepo, channelRepo)
+
+			require.NoError(t, repository.NewUserRepository(db).Create(ctx, &models.User{
+				ID:       "alice",
+				Username: "alice",
+				Email:    "alice@example.com",
+				Password: "hashed-alice",
+			}))
+			require.NoError(t, channelRepo.Create(ctx, &models.Channel{
+				ID:         "ch-restore",
+				Type:       models.ChannelTypeDefault,
+				Name:       "Original",
+				Owners:     []string{"alice"},
+				Visibility: models.ChannelVisibilityPublic,
+			}))
+			first, err := programService.AddProgram(ctx, "ch-restore", "First", models.ProgramTypeYouTube, "dQw4w9WgXcQ", "", 60, []int{}, false)
+			require.NoError(t, err)
+
+			// 沒有封面、有播出設定的歷史版本
+			channel, err := channelRepo.FindByID(ctx, "ch-restore")
+			require.NoError(t, err)
+			snapshot := models.NewChannelSnapshot(channel)
+			snapshot.Cover = nil
+			epoch := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+			snapshot.Schedule = &models.ChannelSchedule{Epoch: epoch, Loop: models.ScheduleLoopOnce}
+			require.NoError(t, revisionRepo.Create(ctx, &models.ChannelRevision{
+				ID:        "rev-saved",
+				ChannelID: "ch-restore",
+				Revision:  channel.Revision,
+				Action:    models.RevisionActionUpdate,
+				Snapshot:  snapshot,
+			}))
+
+			_, err = programService.AddProgram(ctx, "ch-restore", "Second", models.ProgramTypeYouTube, "9bZkp7q19f0", "", 60, []int{}, false)
+			require.NoError(t, err)
+
+			historyService := NewHistoryService(revisionRepo, channelRepo, programRepo).WithTransactions(db)
+			_, err = historyService.RestoreRevision(ctx, "ch-restore", "alice", "rev-saved")
+			require.NoError(t, err)
+
+			// 節目保留原本的 ID，封面清除，播出設定還原
+			restored, err := channelRepo.FindByID(ctx, "ch-restore")
+			require.NoError(t, err)
+			require.Len(t, restored.Contents, 1)
+			assert.Equal(t, first.ID, restored.Contents[0].ID)
+			assert.Equal(t, "First", restored.Contents[0].Name)
+			assert.Nil(t, restored.Cover)
+			require.NotNil(t, restored.Schedule)
+			assert.True(t, epoch.Equal(restored.Schedule.Epoch))
+			assert.Equal(t, models.ScheduleLoopOnce, restored.Schedule.Loop)
+		})
+	}
+}
+
+func TestHistoryService_RestoreRevisionRollsBack(t *testing.T) {
+	db := setupSQLiteTestDB(t)
+
+	ctx := context.Background()
+	channelRepo := repository.NewChannelRepository(db)
+	programRepo := repository.NewProgramRepository(db)
+	revisionRepo := repository.NewChannelRevisionRepository(db)
+	programService := NewProgramService(programRepo, channelRepo).WithHistory(revisionRepo)
+
+	require.NoError(t, repository.NewUserRepository(db).Create(ctx, &models.User{
+		ID:       "alice",
+		Username: "alice",
+		Email:    "alice@example.com",
+		Password: "hashed-alice",
+	}))
+	require.NoError(t, channelRepo.Create(ctx, &models.Channel{
+		ID:         "ch-restore",
+		Type:       models.ChannelTypeDefault,
+		Name:       "Original",
+		Owners:     []string{"alice"},
+		Visibility: models.ChannelVisibilityPublic,
+	}))
+	_, err := programService.AddProgram(ctx, "ch-restore", "First", models.ProgramTypeYouTube, "dQw4w9WgXcQ", "", 60, []int{}, false)
+	require.NoError(t, err)
+	revisions, err := revisionRepo.ListByChannel(ctx, "ch-restore", 0, 0)
+	require.NoError(t, err)
+	require.Len(t, revisions, 1)
+
+	_, err = programService.AddProgram(ctx, "ch-restore", "Second", models.ProgramTypeYouTube, "9bZkp7q19f0", "", 60, []int{}, false)
+	require.NoError(t, err)
+	require.NoError(t, channelRepo.Update(ctx, "ch-restore", map[string]interface{}{"name": "Renamed"}))
+	before, err := channelRepo.FindByID(ctx, "ch-restore")
+	require.NoError(t, err)
+
+	historyService := NewHistoryService(revisionRepo, channelRepo, failingAddProgramsRepo{programRepo}).WithTransactions(db)
+	_, err = historyService.RestoreRevision(ctx, "ch-restore", "alice", revisions[0].ID)
+	require.EqualError(t, err, "add programs failed")
+
+	// 還原失敗時頻道資訊、節目與歷史版本都維持原樣
+	after, err := channelRepo.FindByID(ctx, "ch-restore")
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", after.Name)
+	assert.Equal(t, before.Revision, after.Revision)
+	require.Len(t, after.Contents, 2)
+	assert.Equal(t, "First", after.Contents[0].Name)
+	assert.Equal(t, "Second", after.Contents[1].Name)
+	revisions, err = revisionRepo.ListByChannel(ctx, "ch-restore", 0, 0)
+	require.NoError(t, err)
+	assert.Len(t, revisions, 2)
+}
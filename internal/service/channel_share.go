@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/models"
+	"github.com/higgstv/higgstv-go/pkg/uuidutil"
+)
+
+// ShareInvitationTTL 頻道分享邀請的有效期限
+const ShareInvitationTTL = 14 * 24 * time.Hour
+
+// ShareService 頻道分享邀請服務
+type ShareService struct {
+	shareRepo    database.ChannelShareRepository
+	channelRepo  database.ChannelRepository
+	userRepo     database.UserRepository
+	revisionRepo database.ChannelRevisionRepository
+}
+
+// NewShareService 建立頻道分享邀請服務
+func NewShareService(shareRepo database.ChannelShareRepository, channelRepo database.ChannelRepository, userRepo database.UserRepository) *ShareService {
+	return &ShareService{
+		shareRepo:   shareRepo,
+		channelRepo: channelRepo,
+		userRepo:    userRepo,
+	}
+}
+
+// WithHistory 設定頻道歷史版本 Repository（接受邀請取得擁有權或權限後記錄頻道快照，nil 表示不記錄）
+func (s *ShareService) WithHistory(revisionRepo database.ChannelRevisionRepository) *ShareService {
+	s.revisionRepo = revisionRepo
+	return s
+}
+
+// Invite 建立頻道分享邀請，回傳邀請記錄、頻道與 Token（只會回傳這一次，用於寄送邀請信）
+// owner 角色只有擁有者可以邀請，其他角色需為頻道管理員
+func (s *ShareService) Invite(ctx context.Context, channelID, inviterID, email, role string) (*models.ChannelShare, *models.Channel, string, error) {
+	if !validShareRole(role) {
+		return nil, nil, "", errors.New("invalid role")
+	}
+	email, ok := normalizeEmail(email)
+	if !ok {
+		return nil, nil, "", errors.New("invalid email")
+	}
+
+	channel, err := s.channelRepo.FindByID(ctx, channelID)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	if channel == nil {
+		return nil, nil, "", errors.New("channel not found")
+	}
+	if role == models.ShareRoleOwner {
+		if !channel.IsOwner(inviterID) {
+			return nil, nil, "", errors.New("permission denied")
+		}
+	} else if !channel.PermissionFor(inviterID).Admin {
+		return nil, nil, "", errors.New("permission denied")
+	}
+
+	raw, err := randomToken()
+	if err != nil {
+		return nil, nil, "", err
+	}
+	share := &models.ChannelShare{
+		ID:        uuidutil.NewBase64UUID(),
+		ChannelID: channelID,
+		Email:     email,
+		Role:      role,
+		TokenHash: hashToken(raw),
+		InvitedBy: inviterID,
+		Status:    models.ShareStatusPending,
+		ExpiresAt: time.Now().Add(ShareInvitationTTL),
+	}
+	if err := s.shareRepo.Create(ctx, share); err != nil {
+		return nil, nil, "", err
+	}
+	return share, channel, raw, nil
+}
+
+// ListShares 列出頻道的分享邀請（需為頻道管理員）
+func (s *ShareService) ListShares(ctx context.Context, channelID, userID string) ([]models.ChannelShare, error) {
+	isAdmin, err := s.channelRepo.IsAdmin(ctx, channelID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin {
+		return nil, errors.New("permission denied")
+	}
+	return s.shareRepo.ListByChannel(ctx, channelID)
+}
+
+// RevokeShare 撤銷尚未回應的分享邀請（需為頻道管理員）
+func (s *ShareService) RevokeShare(ctx context.Context, channelID, shareID, userID string) error {
+	isAdmin, err := s.channelRepo.IsAdmin(ctx, channelID, userID)
+	if err != nil {
+		return err
+	}
+	if !isAdmin {
+		return errors.New("permission denied")
+	}
+
+	share, err := s.shareRepo.FindByID(ctx, shareID)
+	if err != nil {
+		return err
+	}
+	if share == nil || share.ChannelID != channelID {
+		return errors.New("invitation not found")
+	}
+	updated, err := s.shareRepo.UpdateStatus(ctx, share.ID, models.ShareStatusPending, models.ShareStatusRevoked, "")
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return errors.New("invitation not found")
+	}
+	return nil
+}
+
+// Accept 接受分享邀請，依角色成為擁有者或取得頻道權限（使用者的 Email 必須與邀請相同）
+func (s *ShareService) Accept(ctx context.Context, token, userID string) (*models.ChannelShare, error) {
+	share, channel, err := s.pendingShare(ctx, token, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 先將邀請標記為已接受，避免同一個邀請被重複使用
+	updated, err := s.shareRepo.UpdateStatus(ctx, share.ID, models.ShareStatusPending, models.ShareStatusAccepted, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, errors.New("invitation not found")
+	}
+
+	if err := s.grant(ctx, channel, userID, share.Role); err != nil {
+		// 授權失敗時還原邀請，讓受邀者可以重試
+		_, _ = s.shareRepo.UpdateStatus(ctx, share.ID, models.ShareStatusAccepted, models.ShareStatusPending, "")
+		return nil, err
+	}
+
+	share.Status = models.ShareStatusAccepted
+	share.UserID = userID
+	return share, nil
+}
+
+// Decline 拒絕分享邀請（使用者的 Email 必須與邀請相同）
+func (s *ShareService) Decline(ctx context.Context, token, userID string) error {
+	share, _, err := s.pendingShare(ctx, token, userID)
+	if err != nil {
+		return err
+	}
+
+	updated, err := s.shareRepo.UpdateStatus(ctx, share.ID, models.ShareStatusPending, models.ShareStatusDeclined, userID)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return errors.New("invitation not found")
+	}
+	return nil
+}
+
+// FindPending 依 Token 查詢尚未回應且未過期的分享邀請（找不到時回傳 nil）
+func (s *ShareService) FindPending(ctx context.Context, token string) (*models.ChannelShare, error) {
+	share, err := s.shareRepo.FindByHash(ctx, hashToken(token))
+	if err != nil || share == nil {
+		return nil, err
+	}
+	if !share.Pending(time.Now()) {
+		return nil, nil
+	}
+	return share, nil
+}
+
+// pendingShare 取得使用者可以回應的分享邀請與頻道
+func (s *ShareService) pendingShare(ctx context.Context, token, userID string) (*models.ChannelShare, *models.Channel, error) {
+	share, err := s.FindPending(ctx, token)
+	if err != nil {
+		return nil, nil, err
+	}
+	if share == nil {
+		return nil, nil, errors.New("invitation not found")
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user == nil {
+		return nil, nil, errors.New("user not found")
+	}
+	if !strings.EqualFold(user.Email, share.Email) {
+		return nil, nil, errors.New("invitation email mismatch")
+	}
+
+	// 頻道已刪除時邀請失效
+	channel, err := s.channelRepo.FindByID(ctx, share.ChannelID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if channel == nil {
+		return nil, nil, errors.New("invitation not found")
+	}
+	return share, channel, nil
+}
+
+// grant 依分享邀請的角色授予使用者頻道擁有權或權限（已有的權限保留）
+func (s *ShareService) grant(ctx context.Context, channel *models.Channel, userID, role string) error {
+	if role == models.ShareRoleOwner {
+		if err := s.channelRepo.AddOwners(ctx, channel.ID, []string{userID}); err != nil {
+			return err
+		}
+		return recordRevision(ctx, s.revisionRepo, s.channelRepo, channel.ID, models.RevisionActionOwners)
+	}
+	if channel.IsOwner(userID) {
+		return nil
+	}
+
+	permission := channel.PermissionFor(userID)
+	switch role {
+	case models.ShareRoleAdmin:
+		permission.Admin = true
+	case models.ShareRoleWrite:
+		permission.Write = true
+	case models.ShareRoleRead:
+		permission.Read = true
+	}
+	if err := s.channelRepo.SetPermission(ctx, channel.ID, permission); err != nil {
+		return err
+	}
+	return recordRevision(ctx, s.revisionRepo, s.channelRepo, channel.ID, models.RevisionActionPermission)
+}
+
+// validShareRole 檢查分享邀請角色是否有效
+func validShareRole(role string) bool {
+	for _, r := range models.ShareRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeEmail 檢查並正規化 Email（去除空白、轉為小寫）
+func normalizeEmail(email string) (string, bool) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != email {
+		return "", false
+	}
+	return email, true
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/epg"
+	"github.com/higgstv/higgstv-go/internal/models"
+)
+
+// EPGService 電子節目表服務
+type EPGService struct {
+	channelRepo database.ChannelRepository
+	userRepo    database.UserRepository
+	tagRepo     database.TagRepository
+}
+
+// NewEPGService 建立電子節目表服務
+func NewEPGService(channelRepo database.ChannelRepository, userRepo database.UserRepository) *EPGService {
+	return &EPGService{
+		channelRepo: channelRepo,
+		userRepo:    userRepo,
+	}
+}
+
+// WithTags 設定標籤 Repository（以標籤 slug 過濾頻道，nil 表示不使用）
+func (s *EPGService) WithTags(tagRepo database.TagRepository) *EPGService {
+	s.tagRepo = tagRepo
+	return s
+}
+
+// Guide 依查詢條件建立多個頻道的節目表（頻道數由 query.Limit 限制）
+func (s *EPGService) Guide(ctx context.Context, query ChannelQuery, from, to time.Time, maxSlots int) (*epg.Guide, error) {
+	channelService := NewChannelService(s.channelRepo, s.userRepo).WithTags(s.tagRepo)
+	channels, err := channelService.QueryChannels(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	// 頻道列表不一定包含節目，一次載入所有頻道的節目
+	if err := s.channelRepo.LoadContents(ctx, channels); err != nil {
+		return nil, err
+	}
+
+	return epg.Build(channels, from, to, maxSlots), nil
+}
+
+// ChannelGuide 建立單一頻道的節目表
+func (s *EPGService) ChannelGuide(ctx context.Context, channelID string, from, to time.Time, maxSlots int) (*epg.Guide, error) {
+	channel, err := s.channelRepo.FindByID(ctx, channelID)
+	if err != nil {
+		return nil, err
+	}
+	if channel == nil {
+		return nil, errors.New("channel not found")
+	}
+	return epg.Build([]models.Channel{*channel}, from, to, maxSlots), nil
+}
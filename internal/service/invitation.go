@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"regexp"
+	"time"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/models"
+	"github.com/higgstv/higgstv-go/pkg/uuidutil"
+)
+
+// invitationCodePattern 自訂邀請碼允許的格式
+var invitationCodePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{4,64}$`)
+
+// InvitationService 註冊邀請碼服務
+type InvitationService struct {
+	invitationRepo database.InvitationRepository
+}
+
+// NewInvitationService 建立註冊邀請碼服務
+func NewInvitationService(invitationRepo database.InvitationRepository) *InvitationService {
+	return &InvitationService{
+		invitationRepo: invitationRepo,
+	}
+}
+
+// CreateInvitation 建立邀請碼（code 為空時自動產生，maxUses 為 0 表示不限次數）
+func (s *InvitationService) CreateInvitation(ctx context.Context, createdBy, code, note string, maxUses int, expiresAt *time.Time) (*models.Invitation, error) {
+	if maxUses < 0 {
+		return nil, errors.New("invalid max uses")
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, errors.New("invalid expiry")
+	}
+
+	if code == "" {
+		generated, err := generateInvitationCode()
+		if err != nil {
+			return nil, err
+		}
+		code = generated
+	} else if !invitationCodePattern.MatchString(code) {
+		return nil, errors.New("invalid code")
+	}
+
+	existing, err := s.invitationRepo.FindByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, errors.New("invitation code already exists")
+	}
+
+	invitation := &models.Invitation{
+		ID:        uuidutil.NewBase64UUID(),
+		Code:      code,
+		Note:      note,
+		CreatedBy: createdBy,
+		MaxUses:   maxUses,
+		ExpiresAt: expiresAt,
+	}
+	if err := s.invitationRepo.Create(ctx, invitation); err != nil {
+		return nil, err
+	}
+	return invitation, nil
+}
+
+// ListInvitations 列出邀請碼
+func (s *InvitationService) ListInvitations(ctx context.Context, limit, skip int64) ([]models.Invitation, error) {
+	return s.invitationRepo.List(ctx, limit, skip)
+}
+
+// RevokeInvitation 撤銷邀請碼
+func (s *InvitationService) RevokeInvitation(ctx context.Context, id string) error {
+	err := s.invitationRepo.Revoke(ctx, id)
+	if database.IsNotFound(err) {
+		return errors.New("invitation not found")
+	}
+	return err
+}
+
+// generateInvitationCode 產生隨機邀請碼
+func generateInvitationCode() (string, error) {
+	bytes := make([]byte, 9)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
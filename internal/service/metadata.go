@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/higgstv/higgstv-go/internal/models"
+	"github.com/higgstv/higgstv-go/pkg/metadata"
+)
+
+// enrich 查詢影片資訊並補齊節目缺少的名稱、描述與時長（查詢失敗時保留原值）
+func (s *ProgramService) enrich(ctx context.Context, program *models.Program) {
+	if s.fetcher == nil {
+		return
+	}
+	info, err := s.fetcher.Fetch(ctx, string(program.MediaType()), program.MediaID())
+	if err != nil {
+		return
+	}
+
+	if program.Name == "" {
+		program.Name = info.Title
+	}
+	if program.Desc == "" {
+		program.Desc = info.Description
+	}
+	if program.Duration <= 0 && info.Duration > 0 {
+		program.Duration = info.Duration
+	}
+	// 仍沒有時長時不記錄查詢時間，讓背景補齊之後再查詢
+	if program.Duration > 0 {
+		now := time.Now()
+		program.MetadataAt = &now
+	}
+}
+
+// durationSource 可回報是否能查詢到影片時長的查詢器（例如 YouTube 需要 Data API 金鑰）
+type durationSource interface {
+	ProvidesDuration(providerType string) bool
+}
+
+// BackfillMetadata 為既有節目補齊時長（每個節目只查詢一次），回傳已查詢的節目數
+func (s *ProgramService) BackfillMetadata(ctx context.Context, limit int) (int, error) {
+	if s.fetcher == nil {
+		return 0, errors.New("metadata fetcher is not configured")
+	}
+
+	durations, _ := s.fetcher.(durationSource)
+	processed := 0
+	err := walkChannels(ctx, s.channelRepo, func(channel *models.Channel) (bool, error) {
+		for _, program := range channel.Contents {
+			if limit > 0 && processed >= limit {
+				return false, nil
+			}
+			if program.Duration > 0 || program.MetadataAt != nil {
+				continue
+			}
+			// 查不到時長的來源不重複查詢（設定 YouTube Data API 金鑰後才會補齊 YouTube 節目）
+			if durations != nil && !durations.ProvidesDuration(string(program.MediaType())) {
+				continue
+			}
+
+			updated, err := s.backfillProgram(ctx, channel.ID, program)
+			if errors.Is(err, metadata.ErrNotSupported) {
+				continue
+			}
+			if ctx.Err() != nil {
+				return false, ctx.Err()
+			}
+			if err != nil || updated {
+				processed++
+			}
+		}
+		return true, nil
+	})
+	return processed, err
+}
+
+// backfillProgram 查詢單一節目的影片資訊並寫回
+// 取得時長或影片不存在時記錄查詢時間，避免之後重複查詢；沒有時長時下次再查詢
+func (s *ProgramService) backfillProgram(ctx context.Context, channelID string, program models.Program) (bool, error) {
+	info, err := s.fetcher.Fetch(ctx, string(program.MediaType()), program.MediaID())
+	if err != nil && !errors.Is(err, metadata.ErrNotFound) {
+		return false, err
+	}
+
+	update := map[string]interface{}{}
+	if info == nil {
+		update["contents.$.metadata_at"] = time.Now()
+	} else {
+		if info.Duration > 0 {
+			update["contents.$.duration"] = info.Duration
+			update["contents.$.metadata_at"] = time.Now()
+		}
+		if program.Name == "" && info.Title != "" {
+			update["contents.$.name"] = info.Title
+		}
+		if program.Desc == "" && info.Description != "" {
+			update["contents.$.desc"] = info.Description
+		}
+	}
+	if len(update) == 0 {
+		return true, nil
+	}
+	if err := s.programRepo.UpdateProgramStatus(ctx, channelID, program.ID, update); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/higgstv/higgstv-go/internal/models"
+	"github.com/higgstv/higgstv-go/internal/playlist"
+)
+
+const (
+	// ImportStatusAdded 已新增
+	ImportStatusAdded = "added"
+	// ImportStatusSkipped 已略過
+	ImportStatusSkipped = "skipped"
+
+	// ImportReasonMissingURL 項目沒有 URL
+	ImportReasonMissingURL = "missing_url"
+	// ImportReasonUnsupportedURL URL 不屬於任何已註冊的影片來源
+	ImportReasonUnsupportedURL = "unsupported_url"
+	// ImportReasonInvalidVideo 影片類型或 ID 不合法
+	ImportReasonInvalidVideo = "invalid_video"
+	// ImportReasonDuplicate 影片已在頻道中或在同一批項目中重複
+	ImportReasonDuplicate = "duplicate"
+)
+
+// ImportResult 單一播放清單項目的匯入結果
+type ImportResult struct {
+	Index     int    `json:"index"`
+	Title     string `json:"title"`
+	URL       string `json:"url"`
+	Status    string `json:"status"`
+	Reason    string `json:"reason,omitempty"`
+	ProgramID int    `json:"program_id,omitempty"`
+}
+
+// ImportPlaylist 將播放清單項目依序新增為頻道節目，並回報每個項目的結果
+// 新增節目失敗時中止並回傳目前為止的結果
+func (s *ProgramService) ImportPlaylist(ctx context.Context, channelID string, entries []playlist.Entry) ([]ImportResult, error) {
+	results := make([]ImportResult, 0, len(entries))
+	added := 0
+	for i, entry := range entries {
+		result := ImportResult{
+			Index: i,
+			Title: entry.Title,
+			URL:   entry.URL,
+		}
+
+		videoID := entry.VideoID
+		if videoID == "" {
+			videoID = entry.YouTubeID
+		}
+		if videoID == "" && entry.URL == "" {
+			result.Status = ImportStatusSkipped
+			result.Reason = ImportReasonMissingURL
+			results = append(results, result)
+			continue
+		}
+
+		programType, resolvedID, err := ResolveVideo(models.ProgramType(entry.Type), videoID, entry.URL)
+		if err != nil {
+			result.Status = ImportStatusSkipped
+			result.Reason = ImportReasonInvalidVideo
+			if videoID == "" {
+				result.Reason = ImportReasonUnsupportedURL
+			}
+			results = append(results, result)
+			continue
+		}
+
+		name := entry.Title
+		if name == "" {
+			name = entry.URL
+		}
+		if name == "" {
+			name = resolvedID
+		}
+		tags := entry.Tags
+		if tags == nil {
+			tags = []int{}
+		}
+
+		program, err := s.addProgram(ctx, channelID, name, programType, resolvedID, entry.Desc, entry.Duration, tags, false)
+		if err != nil {
+			return results, err
+		}
+		result.Status = ImportStatusAdded
+		result.ProgramID = program.ID
+		results = append(results, result)
+		added++
+	}
+
+	// 整批匯入只記錄一個歷史版本
+	if added > 0 {
+		if err := recordRevision(ctx, s.revisionRepo, s.channelRepo, channelID, models.RevisionActionImport); err != nil {
+			return results, err
+		}
+	}
+	return results, nil
+}
+
+// ImportVideos 將影片項目依序新增為頻道節目，略過頻道中已有的影片與重複項目
+// 所有節目在同一個交易中新增，任一節目新增失敗時不會新增任何節目
+func (s *ProgramService) ImportVideos(ctx context.Context, channelID string, entries []playlist.Entry) ([]ImportResult, error) {
+	channel, err := s.channelRepo.FindByID(ctx, channelID)
+	if err != nil {
+		return nil, err
+	}
+	if channel == nil {
+		return nil, errors.New("channel not found")
+	}
+
+	seen := make(map[string]bool, len(channel.Contents)+len(entries))
+	for _, program := range channel.Contents {
+		seen[string(program.MediaType())+":"+program.MediaID()] = true
+	}
+
+	results := make([]ImportResult, 0, len(entries))
+	programs := make([]*models.Program, 0, len(entries))
+	added := make([]int, 0, len(entries))
+	for i, entry := range entries {
+		result := ImportResult{
+			Index: i,
+			Title: entry.Title,
+			URL:   entry.URL,
+		}
+
+		videoID := entry.VideoID
+		if videoID == "" {
+			videoID = entry.YouTubeID
+		}
+		if videoID == "" && entry.URL == "" {
+			result.Status = ImportStatusSkipped
+			result.Reason = ImportReasonMissingURL
+			results = append(results, result)
+			continue
+		}
+
+		programType, resolvedID, err := ResolveVideo(models.ProgramType(entry.Type), videoID, entry.URL)
+		if err != nil {
+			result.Status = ImportStatusSkipped
+			result.Reason = ImportReasonInvalidVideo
+			if videoID == "" {
+				result.Reason = ImportReasonUnsupportedURL
+			}
+			results = append(results, result)
+			continue
+		}
+
+		key := string(programType) + ":" + resolvedID
+		if seen[key] {
+			result.Status = ImportStatusSkipped
+			result.Reason = ImportReasonDuplicate
+			results = append(results, result)
+			continue
+		}
+		seen[key] = true
+
+		tags := entry.Tags
+		if tags == nil {
+			tags = []int{}
+		}
+		program := &models.Program{
+			Name:     entry.Title,
+			Desc:     entry.Desc,
+			Duration: entry.Duration,
+			Type:     programType,
+			VideoID:  resolvedID,
+			Tags:     tags,
+		}
+		// 缺少名稱或時長時查詢影片資訊補齊
+		if program.Name == "" || program.Duration <= 0 {
+			s.enrich(ctx, program)
+		}
+		if program.Name == "" {
+			program.Name = entry.URL
+		}
+		if program.Name == "" {
+			program.Name = resolvedID
+		}
+		// youtube_id 保留給既有的 YouTube 客戶端
+		if programType == models.ProgramTypeYouTube {
+			program.YouTubeID = resolvedID
+		}
+
+		result.Status = ImportStatusAdded
+		results = append(results, result)
+		programs = append(programs, program)
+		added = append(added, len(results)-1)
+	}
+
+	if len(programs) == 0 {
+		return results, nil
+	}
+	if err := s.programRepo.AddPrograms(ctx, channelID, programs); err != nil {
+		return nil, err
+	}
+	for i, index := range added {
+		results[index].ProgramID = programs[i].ID
+	}
+	if err := recordRevision(ctx, s.revisionRepo, s.channelRepo, channelID, models.RevisionActionImport); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
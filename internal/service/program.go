@@ -3,39 +3,136 @@ package service
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/higgstv/higgstv-go/internal/database"
 	"github.com/higgstv/higgstv-go/internal/models"
-	"github.com/higgstv/higgstv-go/pkg/youtube"
+	"github.com/higgstv/higgstv-go/internal/schedule"
+	"github.com/higgstv/higgstv-go/pkg/metadata"
+	"github.com/higgstv/higgstv-go/pkg/provider"
 )
 
 // ProgramService 節目服務
 type ProgramService struct {
 	programRepo  database.ProgramRepository
-	channelRepo database.ChannelRepository
+	channelRepo  database.ChannelRepository
+	fetcher      metadata.Fetcher
+	revisionRepo database.ChannelRevisionRepository
+	tagRepo      database.TagRepository
+	tx           database.Transactor
 }
 
-// NewProgramService 建立節目服務
+// NewProgramService 建立節目服務（使用 metadata.Default 查詢影片資訊）
 func NewProgramService(programRepo database.ProgramRepository, channelRepo database.ChannelRepository) *ProgramService {
 	return &ProgramService{
 		programRepo:  programRepo,
 		channelRepo: channelRepo,
+		fetcher:     metadata.Default,
 	}
 }
 
+// WithMetadata 設定影片資訊查詢器（新增節目缺少名稱或時長時使用，nil 表示停用）
+func (s *ProgramService) WithMetadata(fetcher metadata.Fetcher) *ProgramService {
+	s.fetcher = fetcher
+	return s
+}
+
+// WithHistory 設定頻道歷史版本 Repository（每次修改節目後記錄頻道快照，nil 表示不記錄）
+func (s *ProgramService) WithHistory(revisionRepo database.ChannelRevisionRepository) *ProgramService {
+	s.revisionRepo = revisionRepo
+	return s
+}
+
+// WithTags 設定標籤 Repository（新增節目時檢查標籤，nil 表示不檢查）
+func (s *ProgramService) WithTags(tagRepo database.TagRepository) *ProgramService {
+	s.tagRepo = tagRepo
+	return s
+}
+
+// WithTransactions 設定交易（新增節目與記錄歷史版本、移動節目時的刪除與新增在同一個交易中完成，nil 表示不使用交易）
+func (s *ProgramService) WithTransactions(tx database.Transactor) *ProgramService {
+	s.tx = tx
+	return s
+}
+
+// ResolveVideo 解析節目的影片來源與影片 ID
+// 有 rawURL 時依 URL 比對已註冊的來源；否則使用 programType（未指定時為 YouTube）與 videoID
+func ResolveVideo(programType models.ProgramType, videoID, rawURL string) (models.ProgramType, string, error) {
+	if rawURL != "" && videoID == "" {
+		p, id, ok := provider.Resolve(rawURL)
+		if !ok {
+			return "", "", errors.New("unsupported video url")
+		}
+		return models.ProgramType(p.Type()), id, nil
+	}
+
+	if videoID == "" {
+		return "", "", errors.New("video id is required")
+	}
+	if programType == "" {
+		programType = models.ProgramTypeYouTube
+	}
+	p, ok := provider.Get(string(programType))
+	if !ok {
+		return "", "", errors.New("unsupported program type")
+	}
+	if !p.ValidID(videoID) {
+		return "", "", errors.New("invalid video id")
+	}
+	return programType, videoID, nil
+}
+
 // AddProgram 新增節目
-func (s *ProgramService) AddProgram(ctx context.Context, channelID string, name, youtubeID, desc string, duration int, tags []int, updateCover bool) (*models.Program, error) {
-	if name == "" || youtubeID == "" {
-		return nil, errors.New("name and youtube_id are required")
+func (s *ProgramService) AddProgram(ctx context.Context, channelID string, name string, programType models.ProgramType, videoID, desc string, duration int, tags []int, updateCover bool) (*models.Program, error) {
+	if err := validateTags(ctx, s.tagRepo, tags); err != nil {
+		return nil, err
+	}
+	var program *models.Program
+	err := withTx(ctx, s.tx, func(ctx context.Context) error {
+		var err error
+		program, err = s.addProgram(ctx, channelID, name, programType, videoID, desc, duration, tags, updateCover)
+		if err != nil {
+			return err
+		}
+		return recordRevision(ctx, s.revisionRepo, s.channelRepo, channelID, models.RevisionActionAddProgram)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return program, nil
+}
+
+// addProgram 新增節目（不記錄歷史版本）
+func (s *ProgramService) addProgram(ctx context.Context, channelID string, name string, programType models.ProgramType, videoID, desc string, duration int, tags []int, updateCover bool) (*models.Program, error) {
+	if videoID == "" {
+		return nil, errors.New("name and video id are required")
+	}
+	if programType == "" {
+		programType = models.ProgramTypeYouTube
+	}
+	p, ok := provider.Get(string(programType))
+	if !ok {
+		return nil, errors.New("unsupported program type")
 	}
 
 	program := &models.Program{
-		Name:      name,
-		Desc:      desc,
-		Duration:  duration,
-		Type:      models.ProgramTypeYouTube,
-		YouTubeID: youtubeID,
-		Tags:      tags,
+		Name:     name,
+		Desc:     desc,
+		Duration: duration,
+		Type:     programType,
+		VideoID:  videoID,
+		Tags:     tags,
+	}
+	// 缺少名稱或時長時查詢影片資訊補齊
+	if name == "" || duration <= 0 {
+		s.enrich(ctx, program)
+	}
+	if program.Name == "" {
+		return nil, errors.New("name and video id are required")
+	}
+	// youtube_id 保留給既有的 YouTube 客戶端
+	if programType == models.ProgramTypeYouTube {
+		program.YouTubeID = videoID
 	}
 
 	if err := s.programRepo.AddProgram(ctx, channelID, program); err != nil {
@@ -44,29 +141,34 @@ func (s *ProgramService) AddProgram(ctx context.Context, channelID string, name,
 
 	// 如果需要更新頻道封面
 	if updateCover {
-		thumbnailURL := youtube.GetThumbnailURL(youtubeID)
-		update := map[string]interface{}{
-			"cover.default": thumbnailURL,
-		}
-		if err := s.channelRepo.Update(ctx, channelID, update); err != nil {
-			// 封面更新失敗不影響節目新增，只記錄錯誤
-			// 可以考慮加入 logger 記錄
-			_ = err // 忽略錯誤，繼續執行
-		}
+		s.updateCover(ctx, channelID, p, videoID)
 	}
 
 	return program, nil
 }
 
 // UpdateProgram 更新節目
-func (s *ProgramService) UpdateProgram(ctx context.Context, channelID string, programID int, name, youtubeID, desc string, duration *int, tags []int, updateCover bool) (*models.Program, error) {
+// videoID 非空時一併更新影片來源（programType 未指定時為 YouTube）
+func (s *ProgramService) UpdateProgram(ctx context.Context, channelID string, programID int, name string, programType models.ProgramType, videoID, desc string, duration *int, tags []int, updateCover bool) (*models.Program, error) {
 	update := make(map[string]interface{})
 
 	if name != "" {
 		update["contents.$.name"] = name
 	}
-	if youtubeID != "" {
-		update["contents.$.youtube_id"] = youtubeID
+	if videoID != "" {
+		if programType == "" {
+			programType = models.ProgramTypeYouTube
+		}
+		if _, ok := provider.Get(string(programType)); !ok {
+			return nil, errors.New("unsupported program type")
+		}
+		update["contents.$.type"] = programType
+		update["contents.$.video_id"] = videoID
+		if programType == models.ProgramTypeYouTube {
+			update["contents.$.youtube_id"] = videoID
+		} else {
+			update["contents.$.youtube_id"] = ""
+		}
 	}
 	if desc != "" {
 		update["contents.$.desc"] = desc
@@ -87,18 +189,6 @@ func (s *ProgramService) UpdateProgram(ctx context.Context, channelID string, pr
 		return nil, err
 	}
 
-	// 如果需要更新頻道封面
-	if updateCover {
-		thumbnailURL := youtube.GetThumbnailURL(youtubeID)
-		coverUpdate := map[string]interface{}{
-			"cover.default": thumbnailURL,
-		}
-		if err := s.channelRepo.Update(ctx, channelID, coverUpdate); err != nil {
-			// 封面更新失敗不影響節目更新，只記錄錯誤
-			_ = err // 忽略錯誤，繼續執行
-		}
-	}
-
 	// 重新查詢頻道以取得更新後的節目
 	channel, err := s.channelRepo.FindByID(ctx, channelID)
 	if err != nil {
@@ -111,6 +201,15 @@ func (s *ProgramService) UpdateProgram(ctx context.Context, channelID string, pr
 	// 找出更新後的節目
 	for _, program := range channel.Contents {
 		if program.ID == programID {
+			// 如果需要更新頻道封面
+			if updateCover {
+				if p, ok := provider.Get(string(program.MediaType())); ok {
+					s.updateCover(ctx, channelID, p, program.MediaID())
+				}
+			}
+			if err := recordRevision(ctx, s.revisionRepo, s.channelRepo, channelID, models.RevisionActionUpdateProgram); err != nil {
+				return nil, err
+			}
 			return &program, nil
 		}
 	}
@@ -118,12 +217,30 @@ func (s *ProgramService) UpdateProgram(ctx context.Context, channelID string, pr
 	return nil, errors.New("program not found")
 }
 
+// updateCover 以節目縮圖更新頻道封面（沒有縮圖的來源不更新）
+func (s *ProgramService) updateCover(ctx context.Context, channelID string, p provider.Provider, videoID string) {
+	thumbnailURL := p.ThumbnailURL(videoID)
+	if thumbnailURL == "" {
+		return
+	}
+	update := map[string]interface{}{
+		"cover.default": thumbnailURL,
+	}
+	if err := s.channelRepo.Update(ctx, channelID, update); err != nil {
+		// 封面更新失敗不影響節目新增或更新
+		_ = err // 忽略錯誤，繼續執行
+	}
+}
+
 // DeletePrograms 刪除節目
 func (s *ProgramService) DeletePrograms(ctx context.Context, channelID string, programIDs []int) error {
 	if len(programIDs) == 0 {
 		return errors.New("program IDs are required")
 	}
-	return s.programRepo.DeletePrograms(ctx, channelID, programIDs)
+	if err := s.programRepo.DeletePrograms(ctx, channelID, programIDs); err != nil {
+		return err
+	}
+	return recordRevision(ctx, s.revisionRepo, s.channelRepo, channelID, models.RevisionActionDeletePrograms)
 }
 
 // MoveProgram 移動節目到另一個頻道
@@ -164,23 +281,87 @@ func (s *ProgramService) MoveProgram(ctx context.Context, sourceChannelID, targe
 		// 不需要移動的節目會保留在原頻道中（DeletePrograms 只刪除指定的節目）
 	}
 
-	// 從來源頻道刪除
-	if err := s.programRepo.DeletePrograms(ctx, sourceChannelID, programIDs); err != nil {
-		return err
-	}
+	// 刪除與新增在同一個交易中完成，新增失敗時節目仍保留在來源頻道
+	return withTx(ctx, s.tx, func(ctx context.Context) error {
+		// 從來源頻道刪除
+		if err := s.programRepo.DeletePrograms(ctx, sourceChannelID, programIDs); err != nil {
+			return err
+		}
+
+		// 新增到目標頻道
+		for _, program := range programsToMove {
+			if err := s.programRepo.AddProgram(ctx, targetChannelID, &program); err != nil {
+				return err
+			}
+		}
 
-	// 新增到目標頻道
-	for _, program := range programsToMove {
-		if err := s.programRepo.AddProgram(ctx, targetChannelID, &program); err != nil {
+		// 兩個頻道都記錄歷史版本
+		if err := recordRevision(ctx, s.revisionRepo, s.channelRepo, sourceChannelID, models.RevisionActionMovePrograms); err != nil {
 			return err
 		}
+		return recordRevision(ctx, s.revisionRepo, s.channelRepo, targetChannelID, models.RevisionActionMovePrograms)
+	})
+}
+
+// withTx 在交易中執行 fn（tx 為 nil 時直接執行）
+func withTx(ctx context.Context, tx database.Transactor, fn func(ctx context.Context) error) error {
+	if tx == nil {
+		return fn(ctx)
 	}
+	return tx.WithTx(ctx, fn)
+}
 
-	return nil
+// copyPrograms 將節目複製到頻道（取得新的節目 ID），並將節目順序對應到新的節目 ID
+func copyPrograms(ctx context.Context, programRepo database.ProgramRepository, channelID string, contents []models.Program, order []int) error {
+	programs := make([]*models.Program, 0, len(contents))
+	for i := range contents {
+		program := contents[i]
+		programs = append(programs, &program)
+	}
+	if err := programRepo.AddPrograms(ctx, channelID, programs); err != nil {
+		return err
+	}
+
+	newIDs := make(map[int]int, len(programs))
+	for i, program := range programs {
+		newIDs[contents[i].ID] = program.ID
+	}
+	newOrder := make([]int, 0, len(order))
+	for _, id := range order {
+		if newID, ok := newIDs[id]; ok {
+			newOrder = append(newOrder, newID)
+		}
+	}
+	return programRepo.SetOrder(ctx, channelID, newOrder)
 }
 
 // SetOrder 設定節目順序
+// 重新排序時會同步調整播出起點，讓目前正在播出的節目不受影響
 func (s *ProgramService) SetOrder(ctx context.Context, channelID string, order []int) error {
-	return s.programRepo.SetOrder(ctx, channelID, order)
+	channel, err := s.channelRepo.FindByID(ctx, channelID)
+	if err != nil {
+		return err
+	}
+	if channel == nil {
+		return errors.New("channel not found")
+	}
+
+	if err := s.programRepo.SetOrder(ctx, channelID, order); err != nil {
+		return err
+	}
+
+	effective := schedule.Effective(channel)
+	epoch := schedule.Rebase(channel, order, time.Now())
+	if !epoch.Equal(effective.Epoch) {
+		if err := s.channelRepo.Update(ctx, channelID, map[string]interface{}{
+			"schedule": &models.ChannelSchedule{
+				Epoch: epoch.UTC(),
+				Loop:  effective.Loop,
+			},
+		}); err != nil {
+			return err
+		}
+	}
+	return recordRevision(ctx, s.revisionRepo, s.channelRepo, channelID, models.RevisionActionSaveOrder)
 }
 
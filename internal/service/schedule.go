@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/models"
+	"github.com/higgstv/higgstv-go/internal/schedule"
+)
+
+// ScheduleService 頻道線性播出服務
+type ScheduleService struct {
+	channelRepo  database.ChannelRepository
+	revisionRepo database.ChannelRevisionRepository
+}
+
+// NewScheduleService 建立頻道線性播出服務
+func NewScheduleService(channelRepo database.ChannelRepository) *ScheduleService {
+	return &ScheduleService{
+		channelRepo: channelRepo,
+	}
+}
+
+// WithHistory 設定歷史版本 Repository（設定播出起點與循環模式後記錄頻道快照，nil 表示不記錄）
+func (s *ScheduleService) WithHistory(revisionRepo database.ChannelRevisionRepository) *ScheduleService {
+	s.revisionRepo = revisionRepo
+	return s
+}
+
+// Timeline 取得頻道的播出時間軸
+func (s *ScheduleService) Timeline(ctx context.Context, channelID string) (*schedule.Timeline, error) {
+	channel, err := s.channelRepo.FindByID(ctx, channelID)
+	if err != nil {
+		return nil, err
+	}
+	if channel == nil {
+		return nil, errors.New("channel not found")
+	}
+	return schedule.Build(channel), nil
+}
+
+// SetSchedule 設定頻道的播出起點與循環模式
+func (s *ScheduleService) SetSchedule(ctx context.Context, channelID string, epoch time.Time, loop models.ScheduleLoop) (*models.ChannelSchedule, error) {
+	if loop == "" {
+		loop = models.ScheduleLoopRepeat
+	}
+	if loop != models.ScheduleLoopRepeat && loop != models.ScheduleLoopOnce {
+		return nil, errors.New("invalid loop")
+	}
+	if epoch.IsZero() {
+		epoch = time.Now()
+	}
+
+	channelSchedule := &models.ChannelSchedule{
+		Epoch: epoch.UTC(),
+		Loop:  loop,
+	}
+	if err := s.channelRepo.Update(ctx, channelID, map[string]interface{}{
+		"schedule": channelSchedule,
+	}); err != nil {
+		return nil, err
+	}
+	if err := recordRevision(ctx, s.revisionRepo, s.channelRepo, channelID, models.RevisionActionSchedule); err != nil {
+		return nil, err
+	}
+	return channelSchedule, nil
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/models"
+)
+
+// 搜尋類型
+const (
+	SearchTypeAll      = "all"
+	SearchTypeChannels = "channels"
+	SearchTypePrograms = "programs"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+	maxSearchTerms     = 10
+)
+
+// SearchQuery 搜尋條件
+type SearchQuery struct {
+	Q      string // 搜尋字串（以空白分隔多個搜尋詞，需全部符合）
+	Type   string // all、channels 或 programs
+	UserID string // 搜尋者（未登入為空字串）
+	Limit  int64  // 每種結果的筆數
+	Skip   int64
+}
+
+// SearchResult 搜尋結果（各自依相關度排序）
+type SearchResult struct {
+	Channels []models.Channel             `json:"channels"`
+	Programs []models.ProgramSearchResult `json:"programs"`
+}
+
+// SearchService 全文搜尋服務
+type SearchService struct {
+	searchRepo database.SearchRepository
+}
+
+// NewSearchService 建立全文搜尋服務
+func NewSearchService(searchRepo database.SearchRepository) *SearchService {
+	return &SearchService{
+		searchRepo: searchRepo,
+	}
+}
+
+// Search 搜尋頻道與節目的名稱與描述（只包含公開頻道與搜尋者可讀取的頻道）
+func (s *SearchService) Search(ctx context.Context, query SearchQuery) (*SearchResult, error) {
+	terms := strings.Fields(query.Q)
+	if len(terms) == 0 {
+		return nil, errors.New("invalid query")
+	}
+	if len(terms) > maxSearchTerms {
+		terms = terms[:maxSearchTerms]
+	}
+
+	if query.Type == "" {
+		query.Type = SearchTypeAll
+	}
+	if query.Type != SearchTypeAll && query.Type != SearchTypeChannels && query.Type != SearchTypePrograms {
+		return nil, errors.New("invalid search type")
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+	skip := query.Skip
+	if skip < 0 {
+		skip = 0
+	}
+	repoQuery := database.SearchQuery{
+		Terms:  terms,
+		UserID: query.UserID,
+		Limit:  limit,
+		Skip:   skip,
+	}
+
+	result := &SearchResult{
+		Channels: []models.Channel{},
+		Programs: []models.ProgramSearchResult{},
+	}
+	if query.Type != SearchTypePrograms {
+		channels, err := s.searchRepo.SearchChannels(ctx, repoQuery)
+		if err != nil {
+			return nil, err
+		}
+		result.Channels = channels
+	}
+	if query.Type != SearchTypeChannels {
+		programs, err := s.searchRepo.SearchPrograms(ctx, repoQuery)
+		if err != nil {
+			return nil, err
+		}
+		result.Programs = programs
+	}
+	return result, nil
+}
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"regexp"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/models"
+)
+
+// tagSlugPattern 標籤 slug 允許的格式（小寫英數字，以 - 分隔）
+var tagSlugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// TagService 標籤服務
+type TagService struct {
+	tagRepo database.TagRepository
+}
+
+// NewTagService 建立標籤服務
+func NewTagService(tagRepo database.TagRepository) *TagService {
+	return &TagService{
+		tagRepo: tagRepo,
+	}
+}
+
+// ListTags 列出所有標籤
+func (s *TagService) ListTags(ctx context.Context) ([]models.Tag, error) {
+	return s.tagRepo.List(ctx)
+}
+
+// CreateTag 建立標籤（id 為 nil 時使用目前最大的 ID 加 1，讓既有客戶端使用的數字可以直接登錄）
+func (s *TagService) CreateTag(ctx context.Context, id *int, slug string, labels map[string]string, parent *int) (*models.Tag, error) {
+	if !tagSlugPattern.MatchString(slug) {
+		return nil, errors.New("invalid slug")
+	}
+
+	tags, err := s.tagRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tag := &models.Tag{
+		Slug:   slug,
+		Labels: labels,
+		Parent: parent,
+	}
+	if id != nil {
+		if *id <= 0 {
+			return nil, errors.New("invalid tag id")
+		}
+		tag.ID = *id
+	} else {
+		tag.ID = 1
+		for _, existing := range tags {
+			if existing.ID >= tag.ID {
+				tag.ID = existing.ID + 1
+			}
+		}
+	}
+	if tag.Labels == nil {
+		tag.Labels = map[string]string{}
+	}
+
+	for _, existing := range tags {
+		if existing.ID == tag.ID || existing.Slug == tag.Slug {
+			return nil, errors.New("tag already exists")
+		}
+	}
+	if err := validateTagParent(tags, tag.ID, parent); err != nil {
+		return nil, err
+	}
+
+	if err := s.tagRepo.Create(ctx, tag); err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+// TagUpdate 更新標籤的欄位（空字串與 nil 表示不修改，Parent 為 0 表示移除上層標籤）
+type TagUpdate struct {
+	Slug   string
+	Labels map[string]string
+	Parent *int
+}
+
+// UpdateTag 更新標籤
+func (s *TagService) UpdateTag(ctx context.Context, id int, changes TagUpdate) (*models.Tag, error) {
+	tags, err := s.tagRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var tag *models.Tag
+	for i := range tags {
+		if tags[i].ID == id {
+			tag = &tags[i]
+		}
+	}
+	if tag == nil {
+		return nil, errors.New("tag not found")
+	}
+
+	if changes.Slug != "" && changes.Slug != tag.Slug {
+		if !tagSlugPattern.MatchString(changes.Slug) {
+			return nil, errors.New("invalid slug")
+		}
+		for _, existing := range tags {
+			if existing.Slug == changes.Slug {
+				return nil, errors.New("tag already exists")
+			}
+		}
+		tag.Slug = changes.Slug
+	}
+	if changes.Labels != nil {
+		tag.Labels = changes.Labels
+	}
+	if changes.Parent != nil {
+		parent := changes.Parent
+		if *parent == 0 {
+			parent = nil
+		}
+		if err := validateTagParent(tags, id, parent); err != nil {
+			return nil, err
+		}
+		tag.Parent = parent
+	}
+
+	if err := s.tagRepo.Update(ctx, tag); err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+// DeleteTag 刪除標籤（仍有下層標籤時不能刪除；頻道與節目中的標籤 ID 保留不變）
+func (s *TagService) DeleteTag(ctx context.Context, id int) error {
+	tags, err := s.tagRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, tag := range tags {
+		if tag.ID == id {
+			found = true
+		}
+		if tag.Parent != nil && *tag.Parent == id {
+			return errors.New("tag has children")
+		}
+	}
+	if !found {
+		return errors.New("tag not found")
+	}
+	return s.tagRepo.Delete(ctx, id)
+}
+
+// validateTagParent 檢查上層標籤存在且不會形成循環
+func validateTagParent(tags []models.Tag, id int, parent *int) error {
+	if parent == nil {
+		return nil
+	}
+	parents := make(map[int]*int, len(tags))
+	for _, tag := range tags {
+		parents[tag.ID] = tag.Parent
+	}
+	if _, ok := parents[*parent]; !ok {
+		return errors.New("parent tag not found")
+	}
+	// 往上走訪上層標籤，不能回到自己
+	for current := parent; current != nil; current = parents[*current] {
+		if *current == id {
+			return errors.New("invalid parent tag")
+		}
+	}
+	return nil
+}
+
+// validateTags 檢查標籤 ID 皆已登錄（tagRepo 為 nil 或尚未登錄任何標籤時不檢查，維持既有客戶端的行為）
+func validateTags(ctx context.Context, tagRepo database.TagRepository, ids []int) error {
+	if tagRepo == nil || len(ids) == 0 {
+		return nil
+	}
+	tags, err := tagRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+	registered := make(map[int]bool, len(tags))
+	for _, tag := range tags {
+		registered[tag.ID] = true
+	}
+	for _, id := range ids {
+		if !registered[id] {
+			return errors.New("invalid tags")
+		}
+	}
+	return nil
+}
+
+// resolveTagSlug 取得 slug 對應的標籤與所有下層標籤的 ID（找不到時回傳空列表）
+func resolveTagSlug(ctx context.Context, tagRepo database.TagRepository, slug string) ([]int, error) {
+	tags, err := tagRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	children := make(map[int][]int, len(tags))
+	root := -1
+	for _, tag := range tags {
+		if tag.Slug == slug {
+			root = tag.ID
+		}
+		if tag.Parent != nil {
+			children[*tag.Parent] = append(children[*tag.Parent], tag.ID)
+		}
+	}
+	if root < 0 {
+		return []int{}, nil
+	}
+
+	ids := []int{}
+	queue := []int{root}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		ids = append(ids, id)
+		queue = append(queue, children[id]...)
+	}
+	return ids, nil
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/models"
+)
+
+// DefaultTrashRetention 刪除的頻道預設在垃圾桶保留的時間
+const DefaultTrashRetention = 30 * 24 * time.Hour
+
+// TrashedChannel 垃圾桶中的頻道（含預計永久刪除的時間）
+type TrashedChannel struct {
+	models.Channel
+	PurgeAt time.Time `json:"purge_at"`
+}
+
+// DeleteChannel 將頻道移到垃圾桶（只有擁有者可以刪除，未分類頻道不能刪除）
+func (s *ChannelService) DeleteChannel(ctx context.Context, channelID, userID string) error {
+	channel, err := s.channelRepo.FindByID(ctx, channelID)
+	if err != nil {
+		return err
+	}
+	if channel == nil {
+		return errors.New("channel not found")
+	}
+	if !channel.IsOwner(userID) {
+		return errors.New("permission denied")
+	}
+	if channel.Type == models.ChannelTypeUnclassified {
+		return errors.New("unclassified channel cannot be deleted")
+	}
+
+	err = s.channelRepo.SoftDelete(ctx, channelID, time.Now())
+	if database.IsNotFound(err) {
+		return errors.New("channel not found")
+	}
+	if err != nil {
+		return err
+	}
+	return recordRevision(ctx, s.revisionRepo, s.channelRepo, channelID, models.RevisionActionTrash)
+}
+
+// RestoreChannel 將頻道從垃圾桶還原（只有擁有者可以還原）
+func (s *ChannelService) RestoreChannel(ctx context.Context, channelID, userID string) error {
+	channel, err := s.channelRepo.FindDeleted(ctx, channelID)
+	if err != nil {
+		return err
+	}
+	if channel == nil {
+		return errors.New("channel not found")
+	}
+	if !channel.IsOwner(userID) {
+		return errors.New("permission denied")
+	}
+
+	err = s.channelRepo.Restore(ctx, channelID)
+	if database.IsNotFound(err) {
+		return errors.New("channel not found")
+	}
+	if err != nil {
+		return err
+	}
+	return recordRevision(ctx, s.revisionRepo, s.channelRepo, channelID, models.RevisionActionUntrash)
+}
+
+// ListTrash 列出使用者垃圾桶中的頻道
+func (s *ChannelService) ListTrash(ctx context.Context, userID string, retention time.Duration) ([]TrashedChannel, error) {
+	if retention <= 0 {
+		retention = DefaultTrashRetention
+	}
+
+	channels, err := s.channelRepo.ListDeleted(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	trashed := make([]TrashedChannel, 0, len(channels))
+	for _, channel := range channels {
+		item := TrashedChannel{Channel: channel}
+		if channel.DeletedAt != nil {
+			item.PurgeAt = channel.DeletedAt.Add(retention)
+		}
+		trashed = append(trashed, item)
+	}
+	return trashed, nil
+}
+
+// PurgeExpired 永久刪除在 before 之前移到垃圾桶的頻道，回傳刪除的頻道數
+func (s *ChannelService) PurgeExpired(ctx context.Context, before time.Time, batch int) (int, error) {
+	ids, err := s.channelRepo.ListExpired(ctx, before, int64(batch))
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return purged, err
+		}
+		if err := s.channelRepo.Purge(ctx, id); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
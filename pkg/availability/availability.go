@@ -0,0 +1,179 @@
+package availability
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/higgstv/higgstv-go/pkg/metadata"
+	"github.com/higgstv/higgstv-go/pkg/provider"
+)
+
+// Status 影片可播放狀態
+type Status string
+
+const (
+	// StatusAvailable 影片可以播放
+	StatusAvailable Status = "available"
+	// StatusUnavailable 影片已移除或設為私人
+	StatusUnavailable Status = "unavailable"
+	// StatusUnknown 來源不支援檢查
+	StatusUnknown Status = "unknown"
+)
+
+// Checker 影片可播放狀態檢查介面
+// 回傳 error 代表暫時無法判斷（例如網路錯誤），呼叫端應保留原本的狀態
+type Checker interface {
+	Check(ctx context.Context, providerType, videoID string) (Status, error)
+}
+
+// OEmbedChecker 透過 oEmbed 檢查影片（oEmbed 對已移除或私人影片回傳 401、403、404）
+type OEmbedChecker struct {
+	Fetcher metadata.Fetcher
+}
+
+// Check 檢查影片可播放狀態
+func (c OEmbedChecker) Check(ctx context.Context, providerType, videoID string) (Status, error) {
+	_, err := c.Fetcher.Fetch(ctx, providerType, videoID)
+	switch {
+	case err == nil:
+		return StatusAvailable, nil
+	case errors.Is(err, metadata.ErrNotFound):
+		return StatusUnavailable, nil
+	case errors.Is(err, metadata.ErrNotSupported):
+		return StatusUnknown, nil
+	}
+	return "", err
+}
+
+// ErrPrivateAddress 影片檔 URL 解析後為本機或內部網路位址
+var ErrPrivateAddress = errors.New("video url resolves to a private address")
+
+// HTTPChecker 以 HEAD 請求檢查直接連結的影片檔（影片 ID 即為檔案 URL）
+// 影片檔 URL 由使用者提供，Client 應使用 NewHTTPClient 建立以拒絕內部網路位址
+type HTTPChecker struct {
+	Client  *http.Client
+	Limiter *metadata.HostLimiter // 每個主機的速率限制（選填）
+}
+
+// Check 檢查影片可播放狀態
+func (c HTTPChecker) Check(ctx context.Context, providerType, videoID string) (Status, error) {
+	target, err := url.Parse(videoID)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return StatusUnavailable, nil
+	}
+	if c.Limiter != nil {
+		if err := c.Limiter.Wait(ctx, target.Host); err != nil {
+			return "", err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, target.String(), nil)
+	if err != nil {
+		return StatusUnavailable, nil
+	}
+
+	resp, err := c.Client.Do(req)
+	if errors.Is(err, ErrPrivateAddress) {
+		return StatusUnavailable, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	_ = resp.Body.Close()
+
+	switch {
+	case resp.StatusCode < 400:
+		return StatusAvailable, nil
+	case resp.StatusCode == http.StatusNotFound, resp.StatusCode == http.StatusGone,
+		resp.StatusCode == http.StatusForbidden, resp.StatusCode == http.StatusUnauthorized:
+		return StatusUnavailable, nil
+	}
+	return "", fmt.Errorf("availability check failed: %s", resp.Status)
+}
+
+// Mux 依來源類型分派到對應的檢查器，沒有對應檢查器的來源回傳 StatusUnknown
+type Mux map[string]Checker
+
+// Check 檢查影片可播放狀態
+func (m Mux) Check(ctx context.Context, providerType, videoID string) (Status, error) {
+	checker, ok := m[providerType]
+	if !ok {
+		return StatusUnknown, nil
+	}
+	return checker.Check(ctx, providerType, videoID)
+}
+
+// NewHTTPClient 建立只連線到公開位址的 HTTP Client
+// 在 DNS 解析之後檢查實際連線的 IP，拒絕本機、鏈路本地與私有網路位址（重新導向也會檢查）
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || !publicIP(ip) {
+				return fmt.Errorf("%w: %s", ErrPrivateAddress, host)
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// 經由代理時實際連線的是代理伺服器，無法檢查目標位址
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+// publicIP 檢查是否為公開的 IP 位址
+func publicIP(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() && !ip.IsUnspecified() && !ip.IsMulticast()
+}
+
+// Config 預設檢查器設定
+type Config struct {
+	// BaseURL 設定時 oEmbed 查詢改用 <BaseURL>/oembed（同 metadata.Config）
+	BaseURL     string
+	Timeout     time.Duration
+	RatePerHost float64
+	// AllowPrivate 允許檢查本機與內部網路位址的影片檔（測試或內部部署使用）
+	AllowPrivate bool
+}
+
+// NewChecker 建立預設檢查器：YouTube、Vimeo、Dailymotion 使用 oEmbed（不快取），MP4、HLS 使用 HEAD 請求
+func NewChecker(config Config) Checker {
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	oembed := OEmbedChecker{Fetcher: metadata.NewOEmbedFetcher(metadata.Config{
+		BaseURL:     config.BaseURL,
+		Timeout:     timeout,
+		RatePerHost: config.RatePerHost,
+	})}
+	direct := HTTPChecker{
+		Client:  NewHTTPClient(timeout),
+		Limiter: metadata.NewHostLimiter(config.RatePerHost),
+	}
+	if config.AllowPrivate {
+		direct.Client = &http.Client{Timeout: timeout}
+	}
+
+	return Mux{
+		provider.TypeYouTube:     oembed,
+		provider.TypeVimeo:       oembed,
+		provider.TypeDailymotion: oembed,
+		provider.TypeMP4:         direct,
+		provider.TypeHLS:         direct,
+	}
+}
@@ -2,7 +2,9 @@ package mail
 
 import (
 	"fmt"
+	"html"
 	"net/smtp"
+	"net/url"
 )
 
 // Config 郵件配置
@@ -52,3 +54,17 @@ func (s *Service) SendPasswordReset(to, accessKey, baseURL string) error {
 	return s.Send(to, "Reset Password for Your HiggsTV Account", body)
 }
 
+// SendChannelShare 發送頻道分享邀請郵件
+func (s *Service) SendChannelShare(to, inviter, channelName, role, token, baseURL string) error {
+	acceptURL := fmt.Sprintf("%s/AcceptShare/%s?email=%s", baseURL, token, url.QueryEscape(to))
+	body := fmt.Sprintf(`
+		<p>Hi,</p>
+		<p>%s invited you to the HiggsTV channel "%s" as %s.
+		To accept or decline the invitation, please click the following link:</p>
+		<p><a href="%s">%s</a></p>
+		<p>If you don't have a HiggsTV account yet, you can sign up through the link with this email address.</p>
+		<p>Cheers,<br>HiggsTV</p>
+	`, html.EscapeString(inviter), html.EscapeString(channelName), role, acceptURL, acceptURL)
+
+	return s.Send(to, "You're Invited to a HiggsTV Channel", body)
+}
@@ -0,0 +1,319 @@
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/higgstv/higgstv-go/pkg/provider"
+)
+
+// ErrNotSupported 影片來源不支援查詢資訊
+var ErrNotSupported = errors.New("metadata not supported for this provider")
+
+// ErrNotFound 影片不存在或無法公開查詢（oEmbed 回傳 401、403、404）
+var ErrNotFound = errors.New("video not found")
+
+// Metadata 影片資訊
+type Metadata struct {
+	Title        string `json:"title"`
+	Description  string `json:"description,omitempty"`
+	Duration     int    `json:"duration,omitempty"` // 秒，0 表示未知
+	ThumbnailURL string `json:"thumbnail_url,omitempty"`
+	AuthorName   string `json:"author_name,omitempty"`
+}
+
+// Fetcher 影片資訊查詢介面
+type Fetcher interface {
+	Fetch(ctx context.Context, providerType, videoID string) (*Metadata, error)
+}
+
+// Default 預設的影片資訊查詢器（未啟用時為 nil）
+var Default Fetcher
+
+// Init 以設定建立預設的影片資訊查詢器
+func Init(config Config) {
+	Default = NewOEmbedFetcher(config)
+}
+
+// DefaultEndpoints 各來源預設的 oEmbed 端點
+var DefaultEndpoints = map[string]string{
+	provider.TypeYouTube:     "https://www.youtube.com/oembed",
+	provider.TypeVimeo:       "https://vimeo.com/api/oembed.json",
+	provider.TypeDailymotion: "https://www.dailymotion.com/services/oembed",
+}
+
+// Config oEmbed 查詢設定
+type Config struct {
+	// BaseURL 設定時所有來源都改用 <BaseURL>/oembed（測試或自架的 oEmbed 代理）
+	BaseURL string
+	// Endpoints 各來源的 oEmbed 端點，未設定時使用 DefaultEndpoints
+	Endpoints map[string]string
+	// Timeout 單次請求逾時
+	Timeout time.Duration
+	// CacheTTL 快取時間，0 表示不快取
+	CacheTTL time.Duration
+	// RatePerHost 每個主機每秒請求數，0 表示不限制
+	RatePerHost float64
+	// YouTubeAPIKey YouTube Data API 金鑰，設定時 YouTube 改用 API 查詢（oEmbed 不提供時長）
+	// BaseURL 設定時 API 改用 <BaseURL>/youtube/v3/videos
+	YouTubeAPIKey string
+	// Client 自訂 HTTP Client（選填）
+	Client *http.Client
+}
+
+// OEmbedFetcher 透過 oEmbed 查詢影片資訊（含快取與每個主機的速率限制）
+// 設定 YouTube Data API 金鑰時，YouTube 影片改用 API 查詢以取得時長
+type OEmbedFetcher struct {
+	baseURL       string
+	endpoints     map[string]string
+	youtubeAPIURL string
+	youtubeAPIKey string
+	client        *http.Client
+	cacheTTL      time.Duration
+	limiter       *HostLimiter
+
+	mu    sync.Mutex
+	cache map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	metadata *Metadata
+	expires  time.Time
+}
+
+// maxCacheEntries 快取筆數上限，超過時先清除過期項目
+const maxCacheEntries = 10000
+
+// NewOEmbedFetcher 建立 oEmbed 查詢器
+func NewOEmbedFetcher(config Config) *OEmbedFetcher {
+	endpoints := config.Endpoints
+	if endpoints == nil {
+		endpoints = DefaultEndpoints
+	}
+	client := config.Client
+	if client == nil {
+		timeout := config.Timeout
+		if timeout <= 0 {
+			timeout = 10 * time.Second
+		}
+		client = &http.Client{Timeout: timeout}
+	}
+
+	baseURL := strings.TrimRight(config.BaseURL, "/")
+	youtubeAPIURL := "https://www.googleapis.com/youtube/v3/videos"
+	if baseURL != "" {
+		youtubeAPIURL = baseURL + "/youtube/v3/videos"
+	}
+
+	return &OEmbedFetcher{
+		baseURL:       baseURL,
+		endpoints:     endpoints,
+		youtubeAPIURL: youtubeAPIURL,
+		youtubeAPIKey: config.YouTubeAPIKey,
+		client:        client,
+		cacheTTL:      config.CacheTTL,
+		limiter:       NewHostLimiter(config.RatePerHost),
+		cache:         make(map[string]cacheEntry),
+	}
+}
+
+// ProvidesDuration 回報是否能查詢到該來源的影片時長
+// YouTube 的 oEmbed 不提供時長，需要設定 YouTube Data API 金鑰
+func (f *OEmbedFetcher) ProvidesDuration(providerType string) bool {
+	switch providerType {
+	case provider.TypeYouTube:
+		return f.youtubeAPIKey != ""
+	case provider.TypeVimeo:
+		return f.endpoint(providerType) != ""
+	default:
+		return false
+	}
+}
+
+// Fetch 查詢影片資訊
+func (f *OEmbedFetcher) Fetch(ctx context.Context, providerType, videoID string) (*Metadata, error) {
+	p, ok := provider.Get(providerType)
+	if !ok {
+		return nil, ErrNotSupported
+	}
+	endpoint := f.endpoint(providerType)
+	if endpoint == "" {
+		return nil, ErrNotSupported
+	}
+
+	key := providerType + ":" + videoID
+	if metadata := f.cached(key); metadata != nil {
+		return metadata, nil
+	}
+
+	if providerType == provider.TypeYouTube && f.youtubeAPIKey != "" {
+		metadata, err := f.fetchYouTube(ctx, videoID)
+		if err != nil {
+			return nil, err
+		}
+		f.store(key, metadata)
+		return metadata, nil
+	}
+
+	requestURL, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	query := requestURL.Query()
+	query.Set("url", p.CanonicalURL(videoID))
+	query.Set("format", "json")
+	requestURL.RawQuery = query.Encode()
+
+	if err := f.limiter.Wait(ctx, requestURL.Host); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, fmt.Errorf("oembed request failed: %s", resp.Status)
+	}
+
+	var body struct {
+		Title        string `json:"title"`
+		Description  string `json:"description"`
+		Duration     int    `json:"duration"`
+		ThumbnailURL string `json:"thumbnail_url"`
+		AuthorName   string `json:"author_name"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body); err != nil {
+		return nil, fmt.Errorf("invalid oembed response: %w", err)
+	}
+
+	metadata := &Metadata{
+		Title:        strings.TrimSpace(body.Title),
+		Description:  strings.TrimSpace(body.Description),
+		Duration:     body.Duration,
+		ThumbnailURL: body.ThumbnailURL,
+		AuthorName:   body.AuthorName,
+	}
+	f.store(key, metadata)
+	return metadata, nil
+}
+
+// endpoint 取得來源的 oEmbed 端點
+func (f *OEmbedFetcher) endpoint(providerType string) string {
+	if _, ok := f.endpoints[providerType]; !ok {
+		return ""
+	}
+	if f.baseURL != "" {
+		return f.baseURL + "/oembed"
+	}
+	return f.endpoints[providerType]
+}
+
+// cached 取得未過期的快取
+func (f *OEmbedFetcher) cached(key string) *Metadata {
+	if f.cacheTTL <= 0 {
+		return nil
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	entry, ok := f.cache[key]
+	if !ok {
+		return nil
+	}
+	if time.Now().After(entry.expires) {
+		delete(f.cache, key)
+		return nil
+	}
+	return entry.metadata
+}
+
+// store 寫入快取
+func (f *OEmbedFetcher) store(key string, metadata *Metadata) {
+	if f.cacheTTL <= 0 {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := time.Now()
+	if len(f.cache) >= maxCacheEntries {
+		for k, entry := range f.cache {
+			if now.After(entry.expires) {
+				delete(f.cache, k)
+			}
+		}
+		// 仍然太多時整個清空
+		if len(f.cache) >= maxCacheEntries {
+			f.cache = make(map[string]cacheEntry)
+		}
+	}
+	f.cache[key] = cacheEntry{metadata: metadata, expires: now.Add(f.cacheTTL)}
+}
+
+// HostLimiter 每個主機的請求間隔限制
+type HostLimiter struct {
+	interval time.Duration
+	mu       sync.Mutex
+	next     map[string]time.Time
+}
+
+// NewHostLimiter 建立速率限制器（rate 為每秒請求數，0 表示不限制）
+func NewHostLimiter(rate float64) *HostLimiter {
+	l := &HostLimiter{next: make(map[string]time.Time)}
+	if rate > 0 {
+		l.interval = time.Duration(float64(time.Second) / rate)
+	}
+	return l
+}
+
+// Wait 等待直到可以對該主機發出請求
+func (l *HostLimiter) Wait(ctx context.Context, host string) error {
+	if l.interval <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	slot := l.next[host]
+	if slot.Before(now) {
+		slot = now
+	}
+	l.next[host] = slot.Add(l.interval)
+	l.mu.Unlock()
+
+	delay := time.Until(slot)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,75 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOEmbedFetcher_RateLimitPerHost(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"title":"Video","duration":10}`))
+	}))
+	defer server.Close()
+
+	// 不快取，每次都會發出請求；每秒 10 次代表請求間隔 100ms
+	fetcher := NewOEmbedFetcher(Config{BaseURL: server.URL, RatePerHost: 10})
+
+	start := time.Now()
+	for _, id := range []string{"1", "2", "3"} {
+		info, err := fetcher.Fetch(context.Background(), "vimeo", id)
+		require.NoError(t, err)
+		assert.Equal(t, "Video", info.Title)
+		assert.Equal(t, 10, info.Duration)
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+}
+
+func TestOEmbedFetcher_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("url") == "https://vimeo.com/1" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	fetcher := NewOEmbedFetcher(Config{BaseURL: server.URL})
+
+	_, err := fetcher.Fetch(context.Background(), "mp4", "https://example.com/a.mp4")
+	assert.True(t, errors.Is(err, ErrNotSupported))
+
+	_, err = fetcher.Fetch(context.Background(), "vimeo", "2")
+	assert.True(t, errors.Is(err, ErrNotFound))
+
+	_, err = fetcher.Fetch(context.Background(), "vimeo", "1")
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, ErrNotFound))
+}
+
+func TestParseISODuration(t *testing.T) {
+	for value, want := range map[string]int{
+		"PT3M33S":  213,
+		"PT1H":     3600,
+		"PT1H2M3S": 3723,
+		"P1DT2H":   93600,
+		"P0D":      0,
+		"PT45S":    45,
+		"PT10M":    600,
+	} {
+		got, err := parseISODuration(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, want, got, value)
+	}
+	for _, value := range []string{"", "P", "PT", "3M", "PT1.5S"} {
+		_, err := parseISODuration(value)
+		assert.Error(t, err, value)
+	}
+}
@@ -0,0 +1,112 @@
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// isoDurationPattern YouTube Data API 的 ISO 8601 時長（例如 PT1H2M3S、P1DT2H）
+var isoDurationPattern = regexp.MustCompile(`^P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// parseISODuration 解析 ISO 8601 時長為秒數
+func parseISODuration(value string) (int, error) {
+	match := isoDurationPattern.FindStringSubmatch(value)
+	if match == nil || value == "P" || value == "PT" {
+		return 0, fmt.Errorf("invalid duration: %q", value)
+	}
+	seconds := 0
+	for i, unit := range []int{86400, 3600, 60, 1} {
+		if match[i+1] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(match[i+1])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration: %q", value)
+		}
+		seconds += n * unit
+	}
+	return seconds, nil
+}
+
+// fetchYouTube 透過 YouTube Data API 查詢影片資訊（含時長）
+func (f *OEmbedFetcher) fetchYouTube(ctx context.Context, videoID string) (*Metadata, error) {
+	requestURL, err := url.Parse(f.youtubeAPIURL)
+	if err != nil {
+		return nil, err
+	}
+	query := requestURL.Query()
+	query.Set("part", "snippet,contentDetails")
+	query.Set("id", videoID)
+	query.Set("key", f.youtubeAPIKey)
+	requestURL.RawQuery = query.Encode()
+
+	if err := f.limiter.Wait(ctx, requestURL.Host); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	// 金鑰錯誤或配額用盡同樣回傳 403，不能視為影片不存在
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("youtube api request failed: %s", resp.Status)
+	}
+
+	var body struct {
+		Items []struct {
+			Snippet struct {
+				Title        string `json:"title"`
+				Description  string `json:"description"`
+				ChannelTitle string `json:"channelTitle"`
+				Thumbnails   map[string]struct {
+					URL string `json:"url"`
+				} `json:"thumbnails"`
+			} `json:"snippet"`
+			ContentDetails struct {
+				Duration string `json:"duration"`
+			} `json:"contentDetails"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body); err != nil {
+		return nil, fmt.Errorf("invalid youtube api response: %w", err)
+	}
+	// 影片不存在或為私人影片時 items 為空
+	if len(body.Items) == 0 {
+		return nil, ErrNotFound
+	}
+
+	item := body.Items[0]
+	// 直播中的影片沒有時長（P0D），視為未知
+	duration, _ := parseISODuration(item.ContentDetails.Duration)
+	metadata := &Metadata{
+		Title:       strings.TrimSpace(item.Snippet.Title),
+		Description: strings.TrimSpace(item.Snippet.Description),
+		Duration:    duration,
+		AuthorName:  item.Snippet.ChannelTitle,
+	}
+	for _, size := range []string{"high", "medium", "default"} {
+		if thumbnail, ok := item.Snippet.Thumbnails[size]; ok && thumbnail.URL != "" {
+			metadata.ThumbnailURL = thumbnail.URL
+			break
+		}
+	}
+	return metadata, nil
+}
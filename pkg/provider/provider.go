@@ -0,0 +1,122 @@
+package provider
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Provider 影片來源
+type Provider interface {
+	// Type 來源類型（對應 models.ProgramType）
+	Type() string
+	// ExtractID 從 URL 提取影片 ID，無法辨識時回傳 false
+	ExtractID(rawURL string) (string, bool)
+	// ValidID 檢查影片 ID 是否合法
+	ValidID(id string) bool
+	// CanonicalURL 影片的標準觀看 URL
+	CanonicalURL(id string) string
+	// ThumbnailURL 影片縮圖 URL，沒有縮圖時回傳空字串
+	ThumbnailURL(id string) string
+	// EmbedURL 影片嵌入播放 URL
+	EmbedURL(id string) string
+}
+
+// Registry 影片來源註冊表
+type Registry struct {
+	mu        sync.RWMutex
+	providers []Provider
+	byType    map[string]Provider
+}
+
+// NewRegistry 建立影片來源註冊表（URL 解析時依註冊順序比對）
+func NewRegistry(providers ...Provider) *Registry {
+	r := &Registry{byType: make(map[string]Provider)}
+	for _, p := range providers {
+		r.Register(p)
+	}
+	return r
+}
+
+// Register 註冊影片來源，相同類型會取代既有的來源
+func (r *Registry) Register(p Provider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.byType[p.Type()]; exists {
+		for i, existing := range r.providers {
+			if existing.Type() == p.Type() {
+				r.providers[i] = p
+			}
+		}
+	} else {
+		r.providers = append(r.providers, p)
+	}
+	r.byType[p.Type()] = p
+}
+
+// Get 依類型取得影片來源
+func (r *Registry) Get(providerType string) (Provider, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	p, ok := r.byType[providerType]
+	return p, ok
+}
+
+// Resolve 依 URL 找出影片來源與影片 ID
+func (r *Registry) Resolve(rawURL string) (Provider, string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, p := range r.providers {
+		if id, ok := p.ExtractID(rawURL); ok {
+			return p, id, true
+		}
+	}
+	return nil, "", false
+}
+
+// Types 已註冊的來源類型（依註冊順序）
+func (r *Registry) Types() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	types := make([]string, len(r.providers))
+	for i, p := range r.providers {
+		types[i] = p.Type()
+	}
+	return types
+}
+
+// Default 預設的影片來源註冊表
+var Default = NewRegistry(
+	YouTube{},
+	Vimeo{},
+	Dailymotion{},
+	DirectFile{ProviderType: TypeMP4, Extensions: []string{".mp4", ".m4v"}},
+	DirectFile{ProviderType: TypeHLS, Extensions: []string{".m3u8"}},
+)
+
+// Get 從預設註冊表依類型取得影片來源
+func Get(providerType string) (Provider, bool) {
+	return Default.Get(providerType)
+}
+
+// Resolve 從預設註冊表依 URL 找出影片來源與影片 ID
+func Resolve(rawURL string) (Provider, string, bool) {
+	return Default.Resolve(rawURL)
+}
+
+// parseURL 解析 URL（允許省略 scheme）並回傳去除 www. 的小寫主機名稱
+func parseURL(rawURL string) (*url.URL, string, bool) {
+	rawURL = strings.TrimSpace(rawURL)
+	if rawURL == "" {
+		return nil, "", false
+	}
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "https://" + rawURL
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return nil, "", false
+	}
+	return u, strings.TrimPrefix(strings.ToLower(u.Hostname()), "www."), true
+}
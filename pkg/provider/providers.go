@@ -0,0 +1,181 @@
+package provider
+
+import (
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/higgstv/higgstv-go/pkg/youtube"
+)
+
+// 內建的來源類型
+const (
+	TypeYouTube     = "youtube"
+	TypeVimeo       = "vimeo"
+	TypeDailymotion = "dailymotion"
+	TypeMP4         = "mp4"
+	TypeHLS         = "hls"
+)
+
+// YouTube YouTube 影片來源
+type YouTube struct{}
+
+// Type 來源類型
+func (YouTube) Type() string { return TypeYouTube }
+
+// ExtractID 從 URL 提取影片 ID
+func (YouTube) ExtractID(rawURL string) (string, bool) {
+	id := youtube.ExtractVideoID(rawURL)
+	return id, id != ""
+}
+
+// ValidID 檢查影片 ID 是否合法
+func (YouTube) ValidID(id string) bool { return youtube.IsValidVideoID(id) }
+
+// CanonicalURL 影片的標準觀看 URL
+func (YouTube) CanonicalURL(id string) string { return youtube.GetWatchURL(id) }
+
+// ThumbnailURL 影片縮圖 URL
+func (YouTube) ThumbnailURL(id string) string { return youtube.GetThumbnailURL(id) }
+
+// EmbedURL 影片嵌入播放 URL
+func (YouTube) EmbedURL(id string) string { return "https://www.youtube.com/embed/" + id }
+
+// vimeoIDPattern Vimeo 影片 ID（數字）
+var vimeoIDPattern = regexp.MustCompile(`^[0-9]{1,12}$`)
+
+// Vimeo Vimeo 影片來源
+type Vimeo struct{}
+
+// Type 來源類型
+func (Vimeo) Type() string { return TypeVimeo }
+
+// ExtractID 從 URL 提取影片 ID（支援 vimeo.com/<id>、vimeo.com/channels/<name>/<id>、player.vimeo.com/video/<id>）
+func (v Vimeo) ExtractID(rawURL string) (string, bool) {
+	u, host, ok := parseURL(rawURL)
+	if !ok || (host != "vimeo.com" && host != "player.vimeo.com") {
+		return "", false
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	// 影片 ID 為路徑中最後一個數字段
+	for i := len(parts) - 1; i >= 0; i-- {
+		if v.ValidID(parts[i]) {
+			return parts[i], true
+		}
+	}
+	return "", false
+}
+
+// ValidID 檢查影片 ID 是否合法
+func (Vimeo) ValidID(id string) bool { return vimeoIDPattern.MatchString(id) }
+
+// CanonicalURL 影片的標準觀看 URL
+func (Vimeo) CanonicalURL(id string) string { return "https://vimeo.com/" + id }
+
+// ThumbnailURL Vimeo 縮圖 URL 無法由影片 ID 推得（需透過 oEmbed 查詢），回傳空字串
+func (Vimeo) ThumbnailURL(id string) string { return "" }
+
+// EmbedURL 影片嵌入播放 URL
+func (Vimeo) EmbedURL(id string) string { return "https://player.vimeo.com/video/" + id }
+
+// dailymotionIDPattern Dailymotion 影片 ID
+var dailymotionIDPattern = regexp.MustCompile(`^[a-zA-Z0-9]{5,12}$`)
+
+// Dailymotion Dailymotion 影片來源
+type Dailymotion struct{}
+
+// Type 來源類型
+func (Dailymotion) Type() string { return TypeDailymotion }
+
+// ExtractID 從 URL 提取影片 ID（支援 dailymotion.com/video/<id>、dai.ly/<id>、dailymotion.com/embed/video/<id>）
+func (d Dailymotion) ExtractID(rawURL string) (string, bool) {
+	u, host, ok := parseURL(rawURL)
+	if !ok {
+		return "", false
+	}
+
+	var id string
+	switch host {
+	case "dai.ly":
+		id = strings.Trim(u.Path, "/")
+	case "dailymotion.com", "geo.dailymotion.com":
+		parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+		for i := 0; i < len(parts)-1; i++ {
+			if parts[i] == "video" {
+				id = parts[i+1]
+				break
+			}
+		}
+		if id == "" && u.Query().Get("video") != "" {
+			id = u.Query().Get("video")
+		}
+	}
+	// 網址中的 ID 後面可能接標題（例如 x8abc12_some-title）
+	if idx := strings.Index(id, "_"); idx >= 0 {
+		id = id[:idx]
+	}
+
+	if !d.ValidID(id) {
+		return "", false
+	}
+	return id, true
+}
+
+// ValidID 檢查影片 ID 是否合法
+func (Dailymotion) ValidID(id string) bool { return dailymotionIDPattern.MatchString(id) }
+
+// CanonicalURL 影片的標準觀看 URL
+func (Dailymotion) CanonicalURL(id string) string { return "https://www.dailymotion.com/video/" + id }
+
+// ThumbnailURL 影片縮圖 URL
+func (Dailymotion) ThumbnailURL(id string) string {
+	return "https://www.dailymotion.com/thumbnail/video/" + id
+}
+
+// EmbedURL 影片嵌入播放 URL
+func (Dailymotion) EmbedURL(id string) string {
+	return "https://www.dailymotion.com/embed/video/" + id
+}
+
+// DirectFile 直接連結的影片檔（MP4、HLS 等），影片 ID 即為檔案 URL
+type DirectFile struct {
+	ProviderType string
+	Extensions   []string // 小寫副檔名，例如 .mp4
+}
+
+// Type 來源類型
+func (d DirectFile) Type() string { return d.ProviderType }
+
+// ExtractID 從 URL 提取影片 ID（需為 http/https 且副檔名相符）
+func (d DirectFile) ExtractID(rawURL string) (string, bool) {
+	rawURL = strings.TrimSpace(rawURL)
+	if !d.ValidID(rawURL) {
+		return "", false
+	}
+	return rawURL, true
+}
+
+// ValidID 檢查影片 ID（檔案 URL）是否合法
+func (d DirectFile) ValidID(id string) bool {
+	u, err := url.Parse(id)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return false
+	}
+	ext := strings.ToLower(path.Ext(u.Path))
+	for _, allowed := range d.Extensions {
+		if ext == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// CanonicalURL 影片的標準觀看 URL
+func (DirectFile) CanonicalURL(id string) string { return id }
+
+// ThumbnailURL 直接連結的影片檔沒有縮圖
+func (DirectFile) ThumbnailURL(id string) string { return "" }
+
+// EmbedURL 影片嵌入播放 URL
+func (DirectFile) EmbedURL(id string) string { return id }
@@ -28,6 +28,14 @@ func GetSession(c *gin.Context) (*sessions.Session, error) {
 // SetLoggedIn 設定登入狀態
 func SetLoggedIn(c *gin.Context, userID, username, email, unclassifiedChannel string) error {
 	session, _ := GetSession(c)
+	previousID, _ := session.Values["sid"].(string)
+	id, err := startRecord(c, previousID, userID)
+	if err != nil {
+		return err
+	}
+	if id != "" {
+		session.Values["sid"] = id
+	}
 	session.Values["logged_in"] = true
 	session.Values["uid"] = userID
 	session.Values["username"] = username
@@ -35,21 +43,34 @@ func SetLoggedIn(c *gin.Context, userID, username, email, unclassifiedChannel st
 	if unclassifiedChannel != "" {
 		session.Values["unclassified_channel"] = unclassifiedChannel
 	}
+	c.Set(validKey, true)
 	return session.Save(c.Request, c.Writer)
 }
 
-// IsLoggedIn 檢查是否已登入
+// IsLoggedIn 檢查是否已登入（Cookie 或 API Token）
 func IsLoggedIn(c *gin.Context) bool {
+	if identity, ok := tokenIdentity(c); ok {
+		return identity != nil
+	}
 	session, err := GetSession(c)
 	if err != nil {
 		return false
 	}
 	loggedIn, ok := session.Values["logged_in"].(bool)
-	return ok && loggedIn
+	return ok && loggedIn && valid(c)
 }
 
-// GetUserID 取得使用者 ID
+// GetUserID 取得使用者 ID（API Token 需具有目前路由的權限範圍）
 func GetUserID(c *gin.Context) string {
+	if identity, ok := scopedIdentity(c); ok {
+		if identity == nil {
+			return ""
+		}
+		return identity.UserID
+	}
+	if !valid(c) {
+		return ""
+	}
 	session, _ := GetSession(c)
 	if uid, ok := session.Values["uid"].(string); ok {
 		return uid
@@ -59,6 +80,15 @@ func GetUserID(c *gin.Context) string {
 
 // GetUsername 取得使用者名稱
 func GetUsername(c *gin.Context) string {
+	if identity, ok := scopedIdentity(c); ok {
+		if identity == nil {
+			return ""
+		}
+		return identity.Username
+	}
+	if !valid(c) {
+		return ""
+	}
 	session, _ := GetSession(c)
 	if username, ok := session.Values["username"].(string); ok {
 		return username
@@ -68,6 +98,15 @@ func GetUsername(c *gin.Context) string {
 
 // GetEmail 取得 Email
 func GetEmail(c *gin.Context) string {
+	if identity, ok := scopedIdentity(c); ok {
+		if identity == nil {
+			return ""
+		}
+		return identity.Email
+	}
+	if !valid(c) {
+		return ""
+	}
 	session, _ := GetSession(c)
 	if email, ok := session.Values["email"].(string); ok {
 		return email
@@ -77,6 +116,15 @@ func GetEmail(c *gin.Context) string {
 
 // GetUnclassifiedChannel 取得未分類頻道 ID
 func GetUnclassifiedChannel(c *gin.Context) string {
+	if identity, ok := scopedIdentity(c); ok {
+		if identity == nil {
+			return ""
+		}
+		return identity.UnclassifiedChannel
+	}
+	if !valid(c) {
+		return ""
+	}
 	session, _ := GetSession(c)
 	if ch, ok := session.Values["unclassified_channel"].(string); ok {
 		return ch
@@ -84,16 +132,26 @@ func GetUnclassifiedChannel(c *gin.Context) string {
 	return ""
 }
 
-// SetUnclassifiedChannel 設定未分類頻道 ID
+// SetUnclassifiedChannel 設定未分類頻道 ID（API Token 請求不寫入 Cookie）
 func SetUnclassifiedChannel(c *gin.Context, channelID string) error {
+	if identity, ok := scopedIdentity(c); ok {
+		if identity != nil {
+			identity.UnclassifiedChannel = channelID
+		}
+		return nil
+	}
 	session, _ := GetSession(c)
 	session.Values["unclassified_channel"] = channelID
 	return session.Save(c.Request, c.Writer)
 }
 
-// Clear 清除 session
+// Clear 清除 session（同時撤銷伺服器端的 Session）
 func Clear(c *gin.Context) error {
 	session, _ := GetSession(c)
+	if id, ok := session.Values["sid"].(string); ok && id != "" && serverStore != nil {
+		_ = serverStore.Delete(c.Request.Context(), id)
+	}
+	c.Set(validKey, false)
 	session.Values = make(map[interface{}]interface{})
 	return session.Save(c.Request, c.Writer)
 }
@@ -0,0 +1,203 @@
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrNotFound Session 不存在或不屬於目前的使用者
+var ErrNotFound = errors.New("session not found")
+
+// Record 伺服器端 Session 記錄
+type Record struct {
+	ID        string    `bson:"_id" json:"id"`
+	UserID    string    `bson:"user_id" json:"-"`
+	UserAgent string    `bson:"user_agent" json:"user_agent"`
+	IP        string    `bson:"ip" json:"ip"`
+	Created   time.Time `bson:"created" json:"created"`
+	LastSeen  time.Time `bson:"last_seen" json:"last_seen"`
+	ExpiresAt time.Time `bson:"expires_at" json:"expires_at"`
+	Current   bool      `bson:"-" json:"current"` // 是否為目前請求使用的 Session（僅列表時設定）
+}
+
+// Store 伺服器端 Session 儲存介面
+type Store interface {
+	Create(ctx context.Context, record *Record) error
+	Find(ctx context.Context, id string) (*Record, error)
+	Touch(ctx context.Context, id string, lastSeen, expiresAt time.Time) error
+	ListByUser(ctx context.Context, userID string, now time.Time) ([]Record, error)
+	Delete(ctx context.Context, id string) error
+	DeleteByUser(ctx context.Context, userID, exceptID string) error
+	DeleteExpired(ctx context.Context, now time.Time) error
+}
+
+// serverStore 伺服器端 Session 儲存（未設定時只使用 Cookie）
+var serverStore Store
+
+// SetStore 設定伺服器端 Session 儲存
+func SetStore(s Store) {
+	serverStore = s
+}
+
+// maxAge Session 有效期間（與 Cookie 相同）
+const maxAge = 7 * 24 * time.Hour
+
+// touchInterval 更新最後使用時間的最短間隔（避免每個請求都寫入）
+const touchInterval = time.Minute
+
+// maxUserAgentLength 記錄的 User-Agent 長度上限
+const maxUserAgentLength = 512
+
+// validKey gin.Context 中快取 Session 驗證結果的鍵
+const validKey = "session.valid"
+
+// valid 檢查目前請求的 Session 是否仍有效（同一請求只查詢一次）
+func valid(c *gin.Context) bool {
+	if v, ok := c.Get(validKey); ok {
+		return v.(bool)
+	}
+	result := checkValid(c)
+	c.Set(validKey, result)
+	return result
+}
+
+// checkValid 檢查 Session 是否存在於伺服器端且未過期，並更新最後使用時間
+func checkValid(c *gin.Context) bool {
+	if serverStore == nil {
+		return true
+	}
+	session, err := GetSession(c)
+	if err != nil {
+		return false
+	}
+	id, _ := session.Values["sid"].(string)
+	if id == "" {
+		return false
+	}
+
+	ctx := c.Request.Context()
+	record, err := serverStore.Find(ctx, id)
+	if err != nil || record == nil {
+		return false
+	}
+	uid, _ := session.Values["uid"].(string)
+	now := time.Now()
+	if record.UserID != uid || !now.Before(record.ExpiresAt) {
+		return false
+	}
+
+	if now.Sub(record.LastSeen) >= touchInterval {
+		_ = serverStore.Touch(ctx, id, now, now.Add(maxAge))
+	}
+	return true
+}
+
+// startRecord 為新的登入建立伺服器端 Session，回傳 Session ID（未設定儲存時回傳空字串）
+func startRecord(c *gin.Context, previousID, userID string) (string, error) {
+	if serverStore == nil {
+		return "", nil
+	}
+	ctx := c.Request.Context()
+	// 登入時換發新的 Session ID
+	if previousID != "" {
+		_ = serverStore.Delete(ctx, previousID)
+	}
+
+	id, err := newID()
+	if err != nil {
+		return "", err
+	}
+	userAgent := c.Request.UserAgent()
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+	now := time.Now()
+	if err := serverStore.Create(ctx, &Record{
+		ID:        id,
+		UserID:    userID,
+		UserAgent: userAgent,
+		IP:        c.ClientIP(),
+		Created:   now,
+		LastSeen:  now,
+		ExpiresAt: now.Add(maxAge),
+	}); err != nil {
+		return "", err
+	}
+	_ = serverStore.DeleteExpired(ctx, now)
+	return id, nil
+}
+
+// ID 取得目前請求的 Session ID
+func ID(c *gin.Context) string {
+	if !valid(c) {
+		return ""
+	}
+	session, _ := GetSession(c)
+	id, _ := session.Values["sid"].(string)
+	return id
+}
+
+// List 列出目前使用者所有有效的 Session
+func List(c *gin.Context) ([]Record, error) {
+	userID := GetUserID(c)
+	if serverStore == nil || userID == "" {
+		return []Record{}, nil
+	}
+	records, err := serverStore.ListByUser(c.Request.Context(), userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	current := ID(c)
+	for i := range records {
+		records[i].Current = records[i].ID == current
+	}
+	return records, nil
+}
+
+// Revoke 撤銷目前使用者的指定 Session
+func Revoke(c *gin.Context, id string) error {
+	userID := GetUserID(c)
+	if serverStore == nil || userID == "" {
+		return ErrNotFound
+	}
+	ctx := c.Request.Context()
+	record, err := serverStore.Find(ctx, id)
+	if err != nil {
+		return err
+	}
+	if record == nil || record.UserID != userID {
+		return ErrNotFound
+	}
+	return serverStore.Delete(ctx, id)
+}
+
+// RevokeOthers 撤銷目前使用者除了目前 Session 以外的所有 Session
+func RevokeOthers(c *gin.Context) error {
+	userID := GetUserID(c)
+	if serverStore == nil || userID == "" {
+		return nil
+	}
+	return serverStore.DeleteByUser(c.Request.Context(), userID, ID(c))
+}
+
+// RevokeUser 撤銷指定使用者的所有 Session
+func RevokeUser(ctx context.Context, userID string) error {
+	if serverStore == nil {
+		return nil
+	}
+	return serverStore.DeleteByUser(ctx, userID, "")
+}
+
+// newID 產生隨機 Session ID
+func newID() (string, error) {
+	bytes := make([]byte, 24)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
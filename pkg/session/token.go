@@ -0,0 +1,124 @@
+package session
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// API Token 權限範圍
+const (
+	ScopeAll      = "all"      // 所有需登入的 API
+	ScopePickProg = "pickprog" // 僅限 /apis/pickprog（Bookmarklet）
+)
+
+// Scopes 所有可用的權限範圍
+var Scopes = []string{ScopeAll, ScopePickProg}
+
+// Identity 以 API Token 驗證的使用者身分
+type Identity struct {
+	TokenID             string
+	UserID              string
+	Username            string
+	Email               string
+	UnclassifiedChannel string
+	Scopes              []string
+}
+
+// HasScope 檢查身分是否具有指定的權限範圍（all 包含所有範圍）
+func (i *Identity) HasScope(scope string) bool {
+	for _, s := range i.Scopes {
+		if s == ScopeAll || s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// TokenAuthenticator API Token 驗證介面（Token 無效時回傳 nil）
+type TokenAuthenticator interface {
+	Authenticate(ctx context.Context, token string) (*Identity, error)
+}
+
+// tokenAuthenticator API Token 驗證（未設定時不接受 Bearer Token）
+var tokenAuthenticator TokenAuthenticator
+
+// SetTokenAuthenticator 設定 API Token 驗證
+func SetTokenAuthenticator(a TokenAuthenticator) {
+	tokenAuthenticator = a
+}
+
+// identityKey gin.Context 中快取 Token 驗證結果的鍵
+const identityKey = "session.identity"
+
+// bearerToken 取得 Authorization: Bearer 標頭中的 Token
+func bearerToken(c *gin.Context) string {
+	header := c.GetHeader("Authorization")
+	if len(header) < 7 || !strings.EqualFold(header[:7], "Bearer ") {
+		return ""
+	}
+	return strings.TrimSpace(header[7:])
+}
+
+// tokenIdentity 取得以 API Token 驗證的身分（同一請求只查詢一次）
+// 第二個回傳值表示請求是否使用 Bearer Token，使用時不再檢查 Cookie
+func tokenIdentity(c *gin.Context) (*Identity, bool) {
+	if v, ok := c.Get(identityKey); ok {
+		identity, _ := v.(*Identity)
+		return identity, true
+	}
+	token := bearerToken(c)
+	if token == "" {
+		return nil, false
+	}
+
+	var identity *Identity
+	if tokenAuthenticator != nil {
+		identity, _ = tokenAuthenticator.Authenticate(c.Request.Context(), token)
+	}
+	c.Set(identityKey, identity)
+	return identity, true
+}
+
+// scopeKey gin.Context 中記錄路由所需權限範圍的鍵
+const scopeKey = "session.scope"
+
+// SetScope 設定目前路由接受的 API Token 權限範圍（由 RequireAuth 設定，未設定時為 all）
+func SetScope(c *gin.Context, scope string) {
+	c.Set(scopeKey, scope)
+}
+
+// routeScope 取得目前路由接受的權限範圍
+func routeScope(c *gin.Context) string {
+	if scope, ok := c.Get(scopeKey); ok {
+		if s, _ := scope.(string); s != "" {
+			return s
+		}
+	}
+	return ScopeAll
+}
+
+// scopedIdentity 取得具有目前路由權限範圍的 Token 身分
+// 權限範圍不足的 Token（例如只限 pickprog）在其他路由上不代表任何使用者
+func scopedIdentity(c *gin.Context) (*Identity, bool) {
+	identity, ok := tokenIdentity(c)
+	if identity != nil && !identity.HasScope(routeScope(c)) {
+		return nil, ok
+	}
+	return identity, ok
+}
+
+// IsTokenAuth 檢查目前請求是否使用 API Token 驗證
+func IsTokenAuth(c *gin.Context) bool {
+	_, ok := tokenIdentity(c)
+	return ok
+}
+
+// HasScope 檢查目前請求是否具有指定的權限範圍（Cookie 登入具有所有範圍）
+func HasScope(c *gin.Context, scope string) bool {
+	if identity, ok := tokenIdentity(c); ok {
+		return identity != nil && identity.HasScope(scope)
+	}
+	return IsLoggedIn(c)
+}
@@ -1,9 +1,67 @@
 package youtube
 
-import "fmt"
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// videoIDPattern YouTube 影片 ID 格式（11 個字元）
+var videoIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
 
 // GetThumbnailURL 取得 YouTube 影片縮圖 URL
 func GetThumbnailURL(videoID string) string {
 	return fmt.Sprintf("https://img.youtube.com/vi/%s/maxresdefault.jpg", videoID)
 }
 
+// GetWatchURL 取得 YouTube 影片觀看 URL
+func GetWatchURL(videoID string) string {
+	return "https://www.youtube.com/watch?v=" + videoID
+}
+
+// IsValidVideoID 檢查是否為合法的 YouTube 影片 ID
+func IsValidVideoID(videoID string) bool {
+	return videoIDPattern.MatchString(videoID)
+}
+
+// ExtractVideoID 從 URL 提取 YouTube 影片 ID，無法辨識時回傳空字串
+// 支援 youtu.be 短網址、watch?v=、/embed/、/shorts/、/v/ 與 /live/ 格式
+func ExtractVideoID(rawURL string) string {
+	rawURL = strings.TrimSpace(rawURL)
+	if rawURL == "" {
+		return ""
+	}
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "https://" + rawURL
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	var id string
+	switch host {
+	case "youtu.be":
+		id = strings.Trim(u.Path, "/")
+	case "youtube.com", "m.youtube.com", "music.youtube.com", "youtube-nocookie.com":
+		if u.Path == "/watch" {
+			id = u.Query().Get("v")
+			break
+		}
+		parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+		if len(parts) == 2 {
+			switch parts[0] {
+			case "embed", "shorts", "v", "live":
+				id = parts[1]
+			}
+		}
+	}
+
+	if !IsValidVideoID(id) {
+		return ""
+	}
+	return id
+}
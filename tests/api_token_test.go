@@ -0,0 +1,130 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// doTokenRequest 輔助函數：以 Bearer Token 發送請求並解析 JSON 回應
+func doTokenRequest(t *testing.T, ctx *TestDBContext, method, path, token string) map[string]interface{} {
+	req, _ := http.NewRequest(method, path, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	ctx.Router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp
+}
+
+// createAPIToken 輔助函數：建立 API Token 並回傳 Token 與 ID
+func createAPIToken(t *testing.T, ctx *TestDBContext, cookie string, payload map[string]interface{}) (string, string) {
+	resp := doJSONRequest(t, ctx, "POST", "/apis/tokens", cookie, payload)
+	require.Equal(t, float64(0), resp["state"])
+	data := responseData(t, resp)
+	apiToken := data["api_token"].(map[string]interface{})
+	return data["token"].(string), apiToken["_id"].(string)
+}
+
+// TestAPITokenLifecycle 測試建立、使用、列出與撤銷 API Token
+func TestAPITokenLifecycle(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+
+	cookie := getAuthCookie(t, ctx, "tokenuser", "token@example.com", "testpass123")
+	token, tokenID := createAPIToken(t, ctx, cookie, map[string]interface{}{"name": "script"})
+
+	// 以 Token 取代 Cookie
+	resp := doTokenRequest(t, ctx, "GET", "/apis/getownchannels", token)
+	require.Equal(t, float64(0), resp["state"])
+	assert.NotEmpty(t, responseData(t, resp)["channels"])
+
+	resp = doJSONRequest(t, ctx, "GET", "/apis/tokens", cookie, nil)
+	require.Equal(t, float64(0), resp["state"])
+	tokens := responseData(t, resp)["api_tokens"].([]interface{})
+	require.Len(t, tokens, 1)
+	listed := tokens[0].(map[string]interface{})
+	assert.Equal(t, "script", listed["name"])
+	assert.Equal(t, []interface{}{"all"}, listed["scopes"])
+	assert.Contains(t, token, listed["prefix"].(string))
+	assert.NotContains(t, listed, "token_hash")
+	assert.NotNil(t, listed["last_used"])
+
+	// 其他使用者不能撤銷
+	other := getAuthCookie(t, ctx, "tokenother", "tokenother@example.com", "testpass123")
+	resp = doJSONRequest(t, ctx, "POST", "/apis/tokens/"+tokenID+"/revoke", other, nil)
+	assert.Equal(t, float64(0), resp["code"])
+
+	resp = doJSONRequest(t, ctx, "POST", "/apis/tokens/"+tokenID+"/revoke", cookie, nil)
+	require.Equal(t, float64(0), resp["state"])
+	resp = doTokenRequest(t, ctx, "GET", "/apis/getownchannels", token)
+	assert.Equal(t, float64(1), resp["code"])
+
+	// 錯誤的 Token 不會退回使用 Cookie
+	req, _ := http.NewRequest("GET", "/apis/getownchannels", nil)
+	req.Header.Set("Authorization", "Bearer hgtv_invalid")
+	req.Header.Set("Cookie", cookie)
+	w := httptest.NewRecorder()
+	ctx.Router.ServeHTTP(w, req)
+	assert.Contains(t, w.Body.String(), `"code":1`)
+}
+
+// TestAPITokenScopes 測試僅限 pickprog 的 Token 與過期的 Token
+func TestAPITokenScopes(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+
+	cookie := getAuthCookie(t, ctx, "scopeuser", "scope@example.com", "testpass123")
+	token, _ := createAPIToken(t, ctx, cookie, map[string]interface{}{
+		"name":   "bookmarklet",
+		"scopes": []string{"pickprog"},
+	})
+
+	req, _ := http.NewRequest("GET", "/apis/pickprog?callback=cb&name=Picked&youtube_id=dQw4w9WgXcQ", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	ctx.Router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"state":0`)
+	assert.Contains(t, w.Body.String(), "Picked")
+
+	// 其他 API 權限不足
+	resp := doTokenRequest(t, ctx, "GET", "/apis/getownchannels", token)
+	assert.Equal(t, float64(1), resp["state"])
+	assert.Equal(t, float64(2), resp["code"])
+	resp = doTokenRequest(t, ctx, "GET", "/apis/tokens", token)
+	assert.Equal(t, float64(2), resp["code"])
+
+	// 不需登入的 API 不會將權限範圍不足的 Token 視為使用者本人
+	channelID := createChannel(t, ctx, cookie, "Secret")
+	resp = doJSONRequest(t, ctx, "POST", "/apis/savechannel", cookie, map[string]interface{}{
+		"id": channelID, "name": "Secret", "visibility": "private",
+	})
+	require.Equal(t, float64(0), resp["state"])
+	resp = doJSONRequest(t, ctx, "GET", "/apis/getchannel/"+channelID, cookie, nil)
+	require.Equal(t, float64(0), resp["state"])
+	resp = doTokenRequest(t, ctx, "GET", "/apis/getchannel/"+channelID, token)
+	assert.Equal(t, float64(1), resp["state"])
+	assert.Equal(t, float64(2), resp["code"])
+
+	// 無效的權限範圍與到期時間
+	resp = doJSONRequest(t, ctx, "POST", "/apis/tokens", cookie, map[string]interface{}{
+		"name":   "bad",
+		"scopes": []string{"admin"},
+	})
+	assert.Equal(t, float64(0), resp["code"])
+	resp = doJSONRequest(t, ctx, "POST", "/apis/tokens", cookie, map[string]interface{}{
+		"name":       "bad",
+		"expires_at": time.Now().Add(-time.Hour).Format(time.RFC3339),
+	})
+	assert.Equal(t, float64(0), resp["code"])
+	resp = doJSONRequest(t, ctx, "POST", "/apis/tokens", cookie, map[string]interface{}{})
+	assert.Equal(t, float64(0), resp["code"])
+}
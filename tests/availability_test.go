@@ -0,0 +1,146 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/higgstv/higgstv-go/internal/repository"
+	"github.com/higgstv/higgstv-go/internal/service"
+	"github.com/higgstv/higgstv-go/pkg/availability"
+	"github.com/higgstv/higgstv-go/pkg/provider"
+)
+
+// TestAvailabilityCheck 測試檢查節目可播放狀態、報表與隱藏無法播放的節目
+func TestAvailabilityCheck(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+
+	// 本機 oEmbed 與影片檔服務：dQw4w9WgXcQ 與 ok.mp4 可播放，其餘回傳 404
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/oembed" && r.URL.Query().Get("url") == "https://www.youtube.com/watch?v=dQw4w9WgXcQ":
+			_, _ = w.Write([]byte(`{"title":"Never Gonna Give You Up"}`))
+		case r.URL.Path == "/videos/ok.mp4" && r.Method == http.MethodHead:
+			w.WriteHeader(http.StatusOK)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	cookie := getAuthCookie(t, ctx, "availuser", "avail@example.com", "testpass123")
+	channelID := createChannel(t, ctx, cookie, "Availability Channel")
+
+	availableID := addProgram(t, ctx, cookie, channelID, "Available", "dQw4w9WgXcQ", 60)
+	removedID := addProgram(t, ctx, cookie, channelID, "Removed", "9bZkp7q19f0", 60)
+	for _, name := range []string{"ok", "missing"} {
+		resp := doJSONRequest(t, ctx, "POST", "/apis/addprog", cookie, map[string]interface{}{
+			"ch":   channelID,
+			"name": name + ".mp4",
+			"url":  server.URL + "/videos/" + name + ".mp4",
+		})
+		require.Equal(t, float64(0), resp["state"])
+	}
+
+	// 尚未檢查
+	resp := doJSONRequest(t, ctx, "GET", "/apis/channel/"+channelID+"/availability", cookie, nil)
+	require.Equal(t, float64(0), resp["state"])
+	report := responseData(t, resp)["report"].(map[string]interface{})
+	assert.Equal(t, float64(4), report["total"])
+	assert.Equal(t, float64(4), report["unchecked"])
+
+	availabilityService := service.NewAvailabilityService(
+		repository.NewChannelRepository(ctx.DB),
+		repository.NewProgramRepository(ctx.DB),
+		availability.NewChecker(availability.Config{BaseURL: server.URL, Timeout: 5 * time.Second, AllowPrivate: true}),
+	)
+	checked, err := availabilityService.CheckAll(context.Background(), time.Hour, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 4, checked)
+
+	// 剛檢查過的節目不會重新檢查
+	checked, err = availabilityService.CheckAll(context.Background(), time.Hour, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 0, checked)
+
+	resp = doJSONRequest(t, ctx, "GET", "/apis/channel/"+channelID+"/availability", cookie, nil)
+	require.Equal(t, float64(0), resp["state"])
+	report = responseData(t, resp)["report"].(map[string]interface{})
+	assert.Equal(t, float64(2), report["available"])
+	assert.Equal(t, float64(2), report["unavailable"])
+	assert.Equal(t, float64(0), report["unchecked"])
+
+	resp = doJSONRequest(t, ctx, "GET", "/apis/channel/"+channelID+"/availability?availability=unavailable", cookie, nil)
+	report = responseData(t, resp)["report"].(map[string]interface{})
+	programs := report["programs"].([]interface{})
+	require.Len(t, programs, 2)
+	assert.Equal(t, float64(removedID), programs[0].(map[string]interface{})["_id"])
+	assert.Equal(t, "missing.mp4", programs[1].(map[string]interface{})["name"])
+	assert.NotEmpty(t, programs[0].(map[string]interface{})["last_checked"])
+
+	// 預設仍回傳所有節目
+	resp = doJSONRequest(t, ctx, "GET", "/apis/getchannel/"+channelID, "", nil)
+	channel := responseData(t, resp)["channel"].(map[string]interface{})
+	assert.Len(t, channel["contents"].([]interface{}), 4)
+
+	// 擁有者設定隱藏無法播放的節目
+	resp = doJSONRequest(t, ctx, "POST", "/apis/savechannel", cookie, map[string]interface{}{
+		"id":               channelID,
+		"name":             "Availability Channel",
+		"hide_unavailable": true,
+	})
+	require.Equal(t, float64(0), resp["state"])
+
+	resp = doJSONRequest(t, ctx, "GET", "/apis/getchannel/"+channelID, "", nil)
+	channel = responseData(t, resp)["channel"].(map[string]interface{})
+	assert.Equal(t, true, channel["hide_unavailable"])
+	contents := channel["contents"].([]interface{})
+	require.Len(t, contents, 2)
+	assert.Equal(t, float64(availableID), contents[0].(map[string]interface{})["_id"])
+	assert.Equal(t, "available", contents[0].(map[string]interface{})["availability"])
+	for _, id := range channel["contents_order"].([]interface{}) {
+		assert.NotEqual(t, float64(removedID), id)
+	}
+}
+
+// TestChannelAvailabilityRequiresAdmin 測試報表需要頻道權限
+// TestAvailabilityRejectsPrivateAddress 測試影片檔 URL 解析為本機位址時不會發出請求
+func TestAvailabilityRejectsPrivateAddress(t *testing.T) {
+	requested := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = true
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	checker := availability.NewChecker(availability.Config{Timeout: 5 * time.Second})
+	for _, videoURL := range []string{server.URL + "/videos/ok.mp4", "file:///etc/passwd"} {
+		status, err := checker.Check(context.Background(), provider.TypeMP4, videoURL)
+		require.NoError(t, err)
+		assert.Equal(t, availability.StatusUnavailable, status, videoURL)
+	}
+	assert.False(t, requested)
+}
+
+func TestChannelAvailabilityRequiresAdmin(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+
+	ownerCookie := getAuthCookie(t, ctx, "availowner", "availowner@example.com", "testpass123")
+	channelID := createChannel(t, ctx, ownerCookie, "Owner Channel")
+
+	otherCookie := getAuthCookie(t, ctx, "availother", "availother@example.com", "testpass123")
+	resp := doJSONRequest(t, ctx, "GET", "/apis/channel/"+channelID+"/availability", otherCookie, nil)
+	assert.Equal(t, float64(1), resp["state"])
+	assert.Equal(t, float64(2), resp["code"])
+
+	resp = doJSONRequest(t, ctx, "GET", "/apis/channel/"+channelID+"/availability?availability=bogus", ownerCookie, nil)
+	assert.Equal(t, float64(1), resp["state"])
+	assert.Equal(t, float64(0), resp["code"])
+}
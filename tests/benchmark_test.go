@@ -14,6 +14,7 @@ import (
 	"github.com/higgstv/higgstv-go/internal/api"
 	"github.com/higgstv/higgstv-go/internal/config"
 	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/migration"
 	"github.com/higgstv/higgstv-go/pkg/session"
 )
 
@@ -50,6 +51,10 @@ func initBenchmark() {
 		// 如果連線失敗，benchmark 測試會跳過
 		return
 	}
+	if err := migration.RunMigrationsWithTimeout(benchmarkDB); err != nil {
+		// 遷移失敗時 benchmark 測試同樣會跳過
+		return
+	}
 
 	session.Init(cfg.Session.Secret)
 	gin.SetMode(gin.TestMode)
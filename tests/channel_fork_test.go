@@ -0,0 +1,104 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestForkChannel 測試複製頻道
+func TestForkChannel(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+
+	owner := getAuthCookie(t, ctx, "forkowner", "forkowner@example.com", "testpass123")
+	forker := getAuthCookie(t, ctx, "forker", "forker@example.com", "testpass123")
+
+	sourceID := createChannel(t, ctx, owner, "Template")
+	first := addProgram(t, ctx, owner, sourceID, "First", "dQw4w9WgXcQ", 60)
+	second := addProgram(t, ctx, owner, sourceID, "Second", "9bZkp7q19f0", 90)
+	resp := doJSONRequest(t, ctx, "POST", "/apis/prog/saveorder", owner, map[string]interface{}{
+		"ch": sourceID, "order": []int{second, first},
+	})
+	require.Equal(t, float64(0), resp["state"])
+	resp = doJSONRequest(t, ctx, "POST", "/apis/savechannel", owner, map[string]interface{}{
+		"id": sourceID, "name": "Template", "desc": "A template", "tags": []int{3, 5},
+	})
+	require.Equal(t, float64(0), resp["state"])
+
+	resp = doJSONRequest(t, ctx, "POST", "/apis/channel/"+sourceID+"/fork", forker, map[string]interface{}{
+		"name": "My Copy",
+	})
+	require.Equal(t, float64(0), resp["state"])
+	fork := responseData(t, resp)["channel"].(map[string]interface{})
+	forkID := fork["_id"].(string)
+	assert.NotEqual(t, sourceID, forkID)
+	assert.Equal(t, "My Copy", fork["name"])
+	assert.Equal(t, "A template", fork["desc"])
+	assert.Equal(t, sourceID, fork["forked_from"])
+	assert.ElementsMatch(t, []interface{}{float64(3), float64(5)}, fork["tags"])
+
+	// 節目取得新的 ID，順序對應到新的 ID
+	contents := fork["contents"].([]interface{})
+	require.Len(t, contents, 2)
+	ids := map[string]float64{}
+	for _, item := range contents {
+		program := item.(map[string]interface{})
+		ids[program["name"].(string)] = program["_id"].(float64)
+		assert.NotEqual(t, float64(first), program["_id"])
+		assert.NotEqual(t, float64(second), program["_id"])
+	}
+	assert.Equal(t, []interface{}{ids["Second"], ids["First"]}, fork["contents_order"])
+
+	// 新頻道屬於複製者
+	resp = doJSONRequest(t, ctx, "GET", "/apis/getownchannels", forker, nil)
+	require.Equal(t, float64(0), resp["state"])
+	found := false
+	for _, item := range responseData(t, resp)["channels"].([]interface{}) {
+		if item.(map[string]interface{})["_id"] == forkID {
+			found = true
+		}
+	}
+	assert.True(t, found)
+
+	// 來源頻道的複製次數，修改複製的頻道不影響來源頻道
+	resp = doJSONRequest(t, ctx, "POST", "/apis/delprog", forker, map[string]interface{}{
+		"ch": forkID, "ids": []int{int(ids["First"])},
+	})
+	require.Equal(t, float64(0), resp["state"])
+	resp = doJSONRequest(t, ctx, "GET", "/apis/getchannel/"+sourceID, owner, nil)
+	require.Equal(t, float64(0), resp["state"])
+	source := responseData(t, resp)["channel"].(map[string]interface{})
+	assert.Equal(t, float64(1), source["fork_count"])
+	assert.Len(t, source["contents"], 2)
+
+	// 未指定名稱時沿用來源頻道名稱
+	resp = doJSONRequest(t, ctx, "POST", "/apis/channel/"+sourceID+"/fork", owner, nil)
+	require.Equal(t, float64(0), resp["state"])
+	assert.Equal(t, "Template", responseData(t, resp)["channel"].(map[string]interface{})["name"])
+}
+
+// TestForkPrivateChannel 測試不能複製無法讀取的頻道
+func TestForkPrivateChannel(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+
+	owner := getAuthCookie(t, ctx, "privforkowner", "privforkowner@example.com", "testpass123")
+	other := getAuthCookie(t, ctx, "privforker", "privforker@example.com", "testpass123")
+
+	channelID := createChannel(t, ctx, owner, "Secret")
+	resp := doJSONRequest(t, ctx, "POST", "/apis/savechannel", owner, map[string]interface{}{
+		"id": channelID, "name": "Secret", "visibility": "private",
+	})
+	require.Equal(t, float64(0), resp["state"])
+
+	resp = doJSONRequest(t, ctx, "POST", "/apis/channel/"+channelID+"/fork", other, nil)
+	assert.Equal(t, float64(2), resp["code"])
+	resp = doJSONRequest(t, ctx, "POST", "/apis/channel/missing/fork", other, nil)
+	assert.Equal(t, float64(2), resp["code"])
+
+	resp = doJSONRequest(t, ctx, "POST", "/apis/channel/"+channelID+"/fork", owner, nil)
+	require.Equal(t, float64(0), resp["state"])
+	assert.Equal(t, "private", responseData(t, resp)["channel"].(map[string]interface{})["visibility"])
+}
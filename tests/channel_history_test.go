@@ -0,0 +1,174 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// listRevisions 輔助函數：列出頻道歷史版本（由新到舊）
+func listRevisions(t *testing.T, ctx *TestDBContext, cookie, channelID string) []interface{} {
+	resp := doJSONRequest(t, ctx, "GET", "/apis/channel/"+channelID+"/revisions", cookie, nil)
+	require.Equal(t, float64(0), resp["state"])
+	return responseData(t, resp)["revisions"].([]interface{})
+}
+
+// TestChannelHistory 測試頻道修改記錄歷史版本、比較與還原
+func TestChannelHistory(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+
+	cookie := getAuthCookie(t, ctx, "historian", "historian@example.com", "testpass123")
+	channelID := createChannel(t, ctx, cookie, "History")
+	first := addProgram(t, ctx, cookie, channelID, "First", "dQw4w9WgXcQ", 60)
+	addProgram(t, ctx, cookie, channelID, "Second", "9bZkp7q19f0", 60)
+
+	revisions := listRevisions(t, ctx, cookie, channelID)
+	require.Len(t, revisions, 3)
+	latest := revisions[0].(map[string]interface{})
+	assert.Equal(t, "add_program", latest["action"])
+	assert.Equal(t, float64(2), latest["program_count"])
+	assert.Equal(t, "create", revisions[2].(map[string]interface{})["action"])
+	saved := latest["_id"].(string)
+
+	// 修改頻道名稱、刪除一個節目
+	resp := doJSONRequest(t, ctx, "POST", "/apis/savechannel", cookie, map[string]interface{}{
+		"id": channelID, "name": "Renamed", "visibility": "unlisted",
+	})
+	require.Equal(t, float64(0), resp["state"])
+	resp = doJSONRequest(t, ctx, "POST", "/apis/delprog", cookie, map[string]interface{}{
+		"ch": channelID, "ids": []int{first},
+	})
+	require.Equal(t, float64(0), resp["state"])
+
+	// 儲存頻道只記錄一個歷史版本
+	revisions = listRevisions(t, ctx, cookie, channelID)
+	require.Len(t, revisions, 5)
+	assert.Equal(t, "update", revisions[1].(map[string]interface{})["action"])
+	current := revisions[0].(map[string]interface{})["_id"].(string)
+
+	resp = doJSONRequest(t, ctx, "GET", "/apis/channel/"+channelID+"/revisions/diff?from="+saved+"&to="+current, cookie, nil)
+	require.Equal(t, float64(0), resp["state"])
+	diff := responseData(t, resp)["diff"].(map[string]interface{})
+	fields := diff["fields"].(map[string]interface{})
+	assert.Equal(t, "History", fields["name"].(map[string]interface{})["from"])
+	assert.Equal(t, "Renamed", fields["name"].(map[string]interface{})["to"])
+	assert.Contains(t, fields, "visibility")
+	removed := diff["removed"].([]interface{})
+	require.Len(t, removed, 1)
+	assert.Equal(t, float64(first), removed[0].(map[string]interface{})["_id"])
+	assert.Empty(t, diff["added"])
+	assert.Equal(t, true, diff["order_changed"])
+
+	// 還原到刪除節目之前
+	resp = doJSONRequest(t, ctx, "POST", "/apis/channel/"+channelID+"/revisions/"+saved+"/restore", cookie, nil)
+	require.Equal(t, float64(0), resp["state"])
+
+	resp = doJSONRequest(t, ctx, "GET", "/apis/getchannel/"+channelID, cookie, nil)
+	require.Equal(t, float64(0), resp["state"])
+	channel := responseData(t, resp)["channel"].(map[string]interface{})
+	assert.Equal(t, "History", channel["name"])
+	assert.Equal(t, "public", channel["visibility"])
+	contents := channel["contents"].([]interface{})
+	require.Len(t, contents, 2)
+	names := []interface{}{contents[0].(map[string]interface{})["name"], contents[1].(map[string]interface{})["name"]}
+	assert.ElementsMatch(t, []interface{}{"First", "Second"}, names)
+	ids := []interface{}{contents[0].(map[string]interface{})["_id"], contents[1].(map[string]interface{})["_id"]}
+	assert.Contains(t, ids, float64(first))
+
+	revisions = listRevisions(t, ctx, cookie, channelID)
+	assert.Equal(t, "restore", revisions[0].(map[string]interface{})["action"])
+
+	// 還原後的版本與儲存的版本相同（節目保留原本的 ID）
+	restored := revisions[0].(map[string]interface{})["_id"].(string)
+	resp = doJSONRequest(t, ctx, "GET", "/apis/channel/"+channelID+"/revisions/diff?from="+saved+"&to="+restored, cookie, nil)
+	require.Equal(t, float64(0), resp["state"])
+	diff = responseData(t, resp)["diff"].(map[string]interface{})
+	assert.Empty(t, diff["fields"])
+	assert.Empty(t, diff["added"])
+	assert.Empty(t, diff["removed"])
+	assert.Equal(t, false, diff["order_changed"])
+}
+
+// TestChannelHistoryPermission 測試歷史版本的讀取與還原權限
+func TestChannelHistoryPermission(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+
+	owner := getAuthCookie(t, ctx, "histowner", "histowner@example.com", "testpass123")
+	other := getAuthCookie(t, ctx, "histother", "histother@example.com", "testpass123")
+	channelID := createChannel(t, ctx, owner, "Guarded")
+
+	revisions := listRevisions(t, ctx, owner, channelID)
+	require.NotEmpty(t, revisions)
+	revisionID := revisions[0].(map[string]interface{})["_id"].(string)
+
+	// 公開頻道其他人可以讀取歷史版本，但不能還原
+	assert.NotEmpty(t, listRevisions(t, ctx, other, channelID))
+	resp := doJSONRequest(t, ctx, "POST", "/apis/channel/"+channelID+"/revisions/"+revisionID+"/restore", other, nil)
+	assert.Equal(t, float64(2), resp["code"])
+
+	// 私人頻道其他人不能讀取歷史版本
+	resp = doJSONRequest(t, ctx, "POST", "/apis/savechannel", owner, map[string]interface{}{
+		"id": channelID, "name": "Guarded", "visibility": "private",
+	})
+	require.Equal(t, float64(0), resp["state"])
+	resp = doJSONRequest(t, ctx, "GET", "/apis/channel/"+channelID+"/revisions", other, nil)
+	assert.Equal(t, float64(2), resp["code"])
+
+	// 不存在的歷史版本
+	resp = doJSONRequest(t, ctx, "GET", "/apis/channel/"+channelID+"/revisions/diff?from="+revisionID+"&to=missing", owner, nil)
+	assert.Equal(t, float64(0), resp["code"])
+	resp = doJSONRequest(t, ctx, "POST", "/apis/channel/"+channelID+"/revisions/missing/restore", owner, nil)
+	assert.Equal(t, float64(0), resp["code"])
+
+	// 使用過期的修訂版本還原會回報衝突
+	resp = doJSONRequest(t, ctx, "POST", "/apis/channel/"+channelID+"/revisions/"+revisionID+"/restore", owner, map[string]interface{}{
+		"revision": 0,
+	})
+	assert.Equal(t, float64(3), resp["code"])
+}
+
+// TestChannelHistoryRecordsEveryMutation 測試排程、擁有者、權限、垃圾桶與修改節目都記錄歷史版本
+func TestChannelHistoryRecordsEveryMutation(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+
+	getAuthCookie(t, ctx, "collaborator", "collaborator@example.com", "testpass123")
+	cookie := getAuthCookie(t, ctx, "chronicler", "chronicler@example.com", "testpass123")
+	channelID := createChannel(t, ctx, cookie, "Chronicle")
+	program := addProgram(t, ctx, cookie, channelID, "Program", "dQw4w9WgXcQ", 60)
+
+	requests := []struct {
+		path    string
+		payload map[string]interface{}
+	}{
+		{"/apis/saveprog", map[string]interface{}{"ch": channelID, "prog_id": program, "name": "Renamed"}},
+		{"/apis/channel/" + channelID + "/schedule", map[string]interface{}{"loop": "once"}},
+		{"/apis/channel/" + channelID + "/permission/grant", map[string]interface{}{"email": "collaborator@example.com", "permission": "read"}},
+		{"/apis/setchannelowner", map[string]interface{}{"id": channelID, "email": "collaborator@example.com"}},
+		{"/apis/channel/" + channelID + "/owner/remove", map[string]interface{}{"email": "collaborator@example.com"}},
+		{"/apis/channel/" + channelID + "/delete", nil},
+		{"/apis/channel/" + channelID + "/restore", nil},
+	}
+	for _, r := range requests {
+		resp := doJSONRequest(t, ctx, "POST", r.path, cookie, r.payload)
+		require.Equal(t, float64(0), resp["state"], r.path)
+	}
+
+	revisions := listRevisions(t, ctx, cookie, channelID)
+	actions := make([]interface{}, 0, len(revisions))
+	for _, revision := range revisions {
+		actions = append(actions, revision.(map[string]interface{})["action"])
+	}
+	assert.Equal(t, []interface{}{
+		"untrash", "trash", "update_owners", "update_owners", "set_permission", "set_schedule", "update_program", "add_program", "create",
+	}, actions)
+
+	// 每個歷史版本對應一個頻道修訂版本
+	resp := doJSONRequest(t, ctx, "GET", "/apis/getchannel/"+channelID, cookie, nil)
+	require.Equal(t, float64(0), resp["state"])
+	channel := responseData(t, resp)["channel"].(map[string]interface{})
+	assert.Equal(t, channel["revision"], revisions[0].(map[string]interface{})["revision"])
+}
@@ -0,0 +1,115 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/higgstv/higgstv-go/internal/repository"
+)
+
+// ownChannelsOf 輔助函數：取得使用者的 own_channels
+func ownChannelsOf(t *testing.T, ctx *TestDBContext, username string) []string {
+	user, err := repository.NewUserRepository(ctx.DB).FindByUsername(context.Background(), username)
+	require.NoError(t, err)
+	require.NotNil(t, user)
+	return user.OwnChannels
+}
+
+// TestRemoveChannelOwner 測試移除共用擁有者與離開頻道
+func TestRemoveChannelOwner(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+
+	owner := getAuthCookie(t, ctx, "ownera", "ownera@example.com", "testpass123")
+	coOwner := getAuthCookie(t, ctx, "ownerb", "ownerb@example.com", "testpass123")
+	outsider := getAuthCookie(t, ctx, "ownerc", "ownerc@example.com", "testpass123")
+	channelID := createChannel(t, ctx, owner, "Shared")
+
+	resp := doJSONRequest(t, ctx, "POST", "/apis/setchannelowner", owner, map[string]interface{}{
+		"id": channelID, "email": "ownerb@example.com",
+	})
+	require.Equal(t, float64(0), resp["state"])
+	assert.Contains(t, ownChannelsOf(t, ctx, "ownerb"), channelID)
+
+	// 非擁有者不能移除
+	resp = doJSONRequest(t, ctx, "POST", "/apis/channel/"+channelID+"/owner/remove", outsider, map[string]interface{}{
+		"email": "ownerb@example.com",
+	})
+	assert.Equal(t, float64(2), resp["code"])
+	resp = doJSONRequest(t, ctx, "POST", "/apis/channel/"+channelID+"/owner/remove", owner, map[string]interface{}{
+		"email": "ownerc@example.com",
+	})
+	assert.Equal(t, float64(0), resp["code"])
+
+	// 共用擁有者自己離開頻道
+	resp = doJSONRequest(t, ctx, "POST", "/apis/channel/"+channelID+"/owner/remove", coOwner, map[string]interface{}{
+		"email": "ownerb@example.com",
+	})
+	require.Equal(t, float64(0), resp["state"])
+	assert.NotContains(t, ownChannelsOf(t, ctx, "ownerb"), channelID)
+	assert.NotContains(t, ownChannelIDs(t, ctx, coOwner), channelID)
+	resp = doJSONRequest(t, ctx, "POST", "/apis/savechannel", coOwner, map[string]interface{}{
+		"id": channelID, "name": "Renamed",
+	})
+	assert.Equal(t, float64(2), resp["code"])
+
+	// 不能移除最後一位擁有者
+	resp = doJSONRequest(t, ctx, "POST", "/apis/channel/"+channelID+"/owner/remove", owner, map[string]interface{}{
+		"email": "ownera@example.com",
+	})
+	assert.Equal(t, float64(0), resp["code"])
+	assert.Contains(t, ownChannelIDs(t, ctx, owner), channelID)
+	assert.Contains(t, ownChannelsOf(t, ctx, "ownera"), channelID)
+}
+
+// TestTransferChannelOwnership 測試移轉頻道擁有權
+func TestTransferChannelOwnership(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+
+	owner := getAuthCookie(t, ctx, "transfera", "transfera@example.com", "testpass123")
+	receiver := getAuthCookie(t, ctx, "transferb", "transferb@example.com", "testpass123")
+	channelID := createChannel(t, ctx, owner, "Handover")
+
+	// 只有擁有者可以移轉
+	resp := doJSONRequest(t, ctx, "POST", "/apis/channel/"+channelID+"/owner/transfer", receiver, map[string]interface{}{
+		"email": "transferb@example.com",
+	})
+	assert.Equal(t, float64(2), resp["code"])
+	resp = doJSONRequest(t, ctx, "POST", "/apis/channel/"+channelID+"/owner/transfer", owner, map[string]interface{}{
+		"email": "nobody@example.com",
+	})
+	assert.Equal(t, float64(0), resp["code"])
+
+	resp = doJSONRequest(t, ctx, "POST", "/apis/channel/"+channelID+"/owner/transfer", owner, map[string]interface{}{
+		"email": "transferb@example.com",
+	})
+	require.Equal(t, float64(0), resp["state"])
+
+	assert.NotContains(t, ownChannelIDs(t, ctx, owner), channelID)
+	assert.NotContains(t, ownChannelsOf(t, ctx, "transfera"), channelID)
+	assert.Contains(t, ownChannelIDs(t, ctx, receiver), channelID)
+	assert.Contains(t, ownChannelsOf(t, ctx, "transferb"), channelID)
+
+	resp = doJSONRequest(t, ctx, "GET", "/apis/getchannel/"+channelID, receiver, nil)
+	require.Equal(t, float64(0), resp["state"])
+	owners := responseData(t, resp)["channel"].(map[string]interface{})["owners"].([]interface{})
+	assert.Len(t, owners, 1)
+
+	// 未分類頻道不能移轉
+	resp = doJSONRequest(t, ctx, "GET", "/apis/getownchannels", receiver, nil)
+	require.Equal(t, float64(0), resp["state"])
+	for _, item := range responseData(t, resp)["channels"].([]interface{}) {
+		channel := item.(map[string]interface{})
+		if channel["type"] != "unclassified" {
+			continue
+		}
+		resp = doJSONRequest(t, ctx, "POST", "/apis/channel/"+channel["_id"].(string)+"/owner/transfer", receiver, map[string]interface{}{
+			"email": "transfera@example.com",
+		})
+		assert.Equal(t, float64(0), resp["code"])
+	}
+}
@@ -0,0 +1,170 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setPermission 輔助函數：授予或撤銷頻道權限
+func setPermission(t *testing.T, ctx *TestDBContext, cookie, channelID, action, email, permission string) map[string]interface{} {
+	return doJSONRequest(t, ctx, "POST", "/apis/channel/"+channelID+"/permission/"+action, cookie, map[string]interface{}{
+		"email":      email,
+		"permission": permission,
+	})
+}
+
+// TestChannelWritePermission 測試 write 權限可以編輯節目但不能修改頻道或擁有者
+func TestChannelWritePermission(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+
+	owner := getAuthCookie(t, ctx, "permowner", "permowner@example.com", "testpass123")
+	writer := getAuthCookie(t, ctx, "permwriter", "permwriter@example.com", "testpass123")
+	channelID := createChannel(t, ctx, owner, "Shared Channel")
+
+	resp := doJSONRequest(t, ctx, "POST", "/apis/addprog", writer, map[string]interface{}{
+		"ch": channelID, "name": "Too Early", "youtube_id": "dQw4w9WgXcQ",
+	})
+	assert.Equal(t, float64(2), resp["code"])
+
+	resp = setPermission(t, ctx, owner, channelID, "grant", "permwriter@example.com", "write")
+	require.Equal(t, float64(0), resp["state"])
+
+	first := addProgram(t, ctx, writer, channelID, "First", "dQw4w9WgXcQ", 60)
+	second := addProgram(t, ctx, writer, channelID, "Second", "9bZkp7q19f0", 60)
+	resp = doJSONRequest(t, ctx, "POST", "/apis/prog/saveorder", writer, map[string]interface{}{
+		"ch":    channelID,
+		"order": []int{second, first},
+	})
+	assert.Equal(t, float64(0), resp["state"])
+
+	// 不能改名、不能新增擁有者、不能授予權限
+	resp = doJSONRequest(t, ctx, "POST", "/apis/savechannel", writer, map[string]interface{}{
+		"id": channelID, "name": "Renamed",
+	})
+	assert.Equal(t, float64(2), resp["code"])
+	resp = doJSONRequest(t, ctx, "POST", "/apis/setchannelowner", writer, map[string]interface{}{
+		"id": channelID, "email": "permwriter@example.com",
+	})
+	assert.Equal(t, float64(2), resp["code"])
+	resp = setPermission(t, ctx, writer, channelID, "grant", "permwriter@example.com", "admin")
+	assert.Equal(t, float64(2), resp["code"])
+
+	resp = doJSONRequest(t, ctx, "GET", "/apis/getchannelinfo/"+channelID, owner, nil)
+	require.Equal(t, float64(0), resp["state"])
+	channel := responseData(t, resp)["channel"].(map[string]interface{})
+	permissions := channel["permission"].([]interface{})
+	require.Len(t, permissions, 1)
+	assert.Equal(t, true, permissions[0].(map[string]interface{})["write"])
+	assert.Equal(t, false, permissions[0].(map[string]interface{})["admin"])
+
+	// 撤銷後無法再編輯
+	resp = setPermission(t, ctx, owner, channelID, "revoke", "permwriter@example.com", "write")
+	require.Equal(t, float64(0), resp["state"])
+	resp = doJSONRequest(t, ctx, "POST", "/apis/delprog", writer, map[string]interface{}{
+		"ch": channelID, "ids": []int{first},
+	})
+	assert.Equal(t, float64(2), resp["code"])
+
+	// 無效的權限與擁有者
+	resp = setPermission(t, ctx, owner, channelID, "grant", "permwriter@example.com", "delete")
+	assert.Equal(t, float64(0), resp["code"])
+	resp = setPermission(t, ctx, owner, channelID, "revoke", "permowner@example.com", "admin")
+	assert.Equal(t, float64(0), resp["code"])
+	resp = setPermission(t, ctx, owner, channelID, "grant", "nobody@example.com", "read")
+	assert.Equal(t, float64(0), resp["code"])
+}
+
+// TestPrivateChannelReadPermission 測試私人頻道需要 read 權限
+func TestPrivateChannelReadPermission(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+
+	owner := getAuthCookie(t, ctx, "privowner", "privowner@example.com", "testpass123")
+	reader := getAuthCookie(t, ctx, "privreader", "privreader@example.com", "testpass123")
+	channelID := createChannel(t, ctx, owner, "Private Channel")
+	addProgram(t, ctx, owner, channelID, "Secret", "dQw4w9WgXcQ", 60)
+
+	resp := doJSONRequest(t, ctx, "POST", "/apis/savechannel", owner, map[string]interface{}{
+		"id": channelID, "name": "Private Channel", "visibility": "private",
+	})
+	require.Equal(t, float64(0), resp["state"])
+
+	for _, cookie := range []string{"", reader} {
+		resp = doJSONRequest(t, ctx, "GET", "/apis/getchannel/"+channelID, cookie, nil)
+		assert.Equal(t, float64(2), resp["code"])
+		resp = doJSONRequest(t, ctx, "GET", "/apis/channel/"+channelID+"/now", cookie, nil)
+		assert.Equal(t, float64(2), resp["code"])
+	}
+	resp = doJSONRequest(t, ctx, "GET", "/apis/getchannelinfo/"+channelID, reader, nil)
+	assert.Equal(t, float64(2), resp["code"])
+
+	// 不會出現在公開列表
+	resp = doJSONRequest(t, ctx, "GET", "/apis/getchannels", "", nil)
+	require.Equal(t, float64(0), resp["state"])
+	for _, item := range responseData(t, resp)["channels"].([]interface{}) {
+		assert.NotEqual(t, channelID, item.(map[string]interface{})["_id"])
+	}
+
+	resp = doJSONRequest(t, ctx, "GET", "/apis/getchannel/"+channelID, owner, nil)
+	require.Equal(t, float64(0), resp["state"])
+	assert.Equal(t, "private", responseData(t, resp)["channel"].(map[string]interface{})["visibility"])
+
+	resp = setPermission(t, ctx, owner, channelID, "grant", "privreader@example.com", "read")
+	require.Equal(t, float64(0), resp["state"])
+	resp = doJSONRequest(t, ctx, "GET", "/apis/getchannel/"+channelID, reader, nil)
+	require.Equal(t, float64(0), resp["state"])
+	resp = doJSONRequest(t, ctx, "GET", "/apis/getchannelinfo/"+channelID, reader, nil)
+	require.Equal(t, float64(0), resp["state"])
+
+	// read 不包含 write
+	resp = doJSONRequest(t, ctx, "POST", "/apis/addprog", reader, map[string]interface{}{
+		"ch": channelID, "name": "Intruder", "youtube_id": "dQw4w9WgXcQ",
+	})
+	assert.Equal(t, float64(2), resp["code"])
+}
+
+// TestUnlistedChannelVisibility 測試不公開列出的頻道只能透過 ID 讀取
+func TestUnlistedChannelVisibility(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+
+	owner := getAuthCookie(t, ctx, "unlistedowner", "unlistedowner@example.com", "testpass123")
+	channelID := createChannel(t, ctx, owner, "Unlisted Channel")
+
+	// 新頻道預設為公開
+	listed := func() bool {
+		resp := doJSONRequest(t, ctx, "GET", "/apis/getchannels", "", nil)
+		require.Equal(t, float64(0), resp["state"])
+		for _, item := range responseData(t, resp)["channels"].([]interface{}) {
+			if item.(map[string]interface{})["_id"] == channelID {
+				return true
+			}
+		}
+		return false
+	}
+	assert.True(t, listed())
+
+	resp := doJSONRequest(t, ctx, "POST", "/apis/savechannel", owner, map[string]interface{}{
+		"id": channelID, "name": "Unlisted Channel", "visibility": "hidden",
+	})
+	assert.Equal(t, float64(0), resp["code"])
+
+	resp = doJSONRequest(t, ctx, "POST", "/apis/savechannel", owner, map[string]interface{}{
+		"id": channelID, "name": "Unlisted Channel", "visibility": "unlisted",
+	})
+	require.Equal(t, float64(0), resp["state"])
+	assert.False(t, listed())
+
+	resp = doJSONRequest(t, ctx, "GET", "/apis/getchannel/"+channelID, "", nil)
+	require.Equal(t, float64(0), resp["state"])
+	assert.Equal(t, "unlisted", responseData(t, resp)["channel"].(map[string]interface{})["visibility"])
+
+	resp = doJSONRequest(t, ctx, "POST", "/apis/savechannel", owner, map[string]interface{}{
+		"id": channelID, "name": "Unlisted Channel", "visibility": "public",
+	})
+	require.Equal(t, float64(0), resp["state"])
+	assert.True(t, listed())
+}
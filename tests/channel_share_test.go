@@ -0,0 +1,147 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/higgstv/higgstv-go/internal/repository"
+	"github.com/higgstv/higgstv-go/internal/service"
+)
+
+// inviteToChannel 輔助函數：透過服務層建立分享邀請並回傳 Token（API 只會把 Token 寄給受邀者）
+func inviteToChannel(t *testing.T, ctx *TestDBContext, channelID, inviter, email, role string) string {
+	user, err := repository.NewUserRepository(ctx.DB).FindByUsername(context.Background(), inviter)
+	require.NoError(t, err)
+	require.NotNil(t, user)
+
+	shareService := service.NewShareService(
+		repository.NewChannelShareRepository(ctx.DB),
+		repository.NewChannelRepository(ctx.DB),
+		repository.NewUserRepository(ctx.DB),
+	)
+	_, _, token, err := shareService.Invite(context.Background(), channelID, user.ID, email, role)
+	require.NoError(t, err)
+	return token
+}
+
+// TestChannelShareAcceptAndDecline 測試邀請、接受、拒絕與撤銷頻道分享邀請
+func TestChannelShareAcceptAndDecline(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+
+	owner := getAuthCookie(t, ctx, "shareowner", "shareowner@example.com", "testpass123")
+	writer := getAuthCookie(t, ctx, "sharewriter", "sharewriter@example.com", "testpass123")
+	reader := getAuthCookie(t, ctx, "sharereader", "sharereader@example.com", "testpass123")
+	channelID := createChannel(t, ctx, owner, "Invite Only")
+
+	resp := doJSONRequest(t, ctx, "POST", "/apis/channel/"+channelID+"/share", owner, map[string]interface{}{
+		"email": "ShareWriter@example.com", "role": "write",
+	})
+	require.Equal(t, float64(0), resp["state"])
+	share := responseData(t, resp)["share"].(map[string]interface{})
+	assert.Equal(t, "sharewriter@example.com", share["email"])
+	assert.Equal(t, "pending", share["status"])
+	assert.NotContains(t, share, "token_hash")
+
+	// 非管理員不能邀請，角色與 Email 必須有效
+	resp = doJSONRequest(t, ctx, "POST", "/apis/channel/"+channelID+"/share", writer, map[string]interface{}{
+		"email": "sharewriter@example.com", "role": "read",
+	})
+	assert.Equal(t, float64(2), resp["code"])
+	resp = doJSONRequest(t, ctx, "POST", "/apis/channel/"+channelID+"/share", owner, map[string]interface{}{
+		"email": "sharewriter@example.com", "role": "editor",
+	})
+	assert.Equal(t, float64(0), resp["code"])
+	resp = doJSONRequest(t, ctx, "POST", "/apis/channel/"+channelID+"/share", owner, map[string]interface{}{
+		"email": "not-an-email", "role": "read",
+	})
+	assert.Equal(t, float64(0), resp["code"])
+
+	// 尚未接受前沒有權限
+	token := inviteToChannel(t, ctx, channelID, "shareowner", "sharewriter@example.com", "write")
+	resp = doJSONRequest(t, ctx, "POST", "/apis/addprog", writer, map[string]interface{}{
+		"ch": channelID, "name": "Early", "youtube_id": "dQw4w9WgXcQ",
+	})
+	assert.Equal(t, float64(2), resp["code"])
+
+	// 其他使用者不能使用這個邀請
+	resp = doJSONRequest(t, ctx, "POST", "/apis/shares/accept", reader, map[string]interface{}{"token": token})
+	assert.Equal(t, float64(0), resp["code"])
+
+	resp = doJSONRequest(t, ctx, "POST", "/apis/shares/accept", writer, map[string]interface{}{"token": token})
+	require.Equal(t, float64(0), resp["state"])
+	assert.Equal(t, channelID, responseData(t, resp)["share"].(map[string]interface{})["channel_id"])
+	addProgram(t, ctx, writer, channelID, "Shared", "dQw4w9WgXcQ", 60)
+
+	// 邀請只能使用一次
+	resp = doJSONRequest(t, ctx, "POST", "/apis/shares/accept", writer, map[string]interface{}{"token": token})
+	assert.Equal(t, float64(0), resp["code"])
+
+	// 拒絕後不能再接受
+	declined := inviteToChannel(t, ctx, channelID, "shareowner", "sharereader@example.com", "read")
+	resp = doJSONRequest(t, ctx, "POST", "/apis/shares/decline", reader, map[string]interface{}{"token": declined})
+	require.Equal(t, float64(0), resp["state"])
+	resp = doJSONRequest(t, ctx, "POST", "/apis/shares/accept", reader, map[string]interface{}{"token": declined})
+	assert.Equal(t, float64(0), resp["code"])
+
+	// 撤銷後不能接受
+	revoked := inviteToChannel(t, ctx, channelID, "shareowner", "sharereader@example.com", "admin")
+	resp = doJSONRequest(t, ctx, "GET", "/apis/channel/"+channelID+"/shares", owner, nil)
+	require.Equal(t, float64(0), resp["state"])
+	shares := responseData(t, resp)["shares"].([]interface{})
+	require.Len(t, shares, 4)
+	latest := shares[0].(map[string]interface{})
+	assert.Equal(t, "admin", latest["role"])
+	resp = doJSONRequest(t, ctx, "POST", "/apis/channel/"+channelID+"/shares/"+latest["_id"].(string)+"/revoke", owner, nil)
+	require.Equal(t, float64(0), resp["state"])
+	resp = doJSONRequest(t, ctx, "POST", "/apis/shares/accept", reader, map[string]interface{}{"token": revoked})
+	assert.Equal(t, float64(0), resp["code"])
+
+	resp = doJSONRequest(t, ctx, "GET", "/apis/channel/"+channelID+"/shares", reader, nil)
+	assert.Equal(t, float64(2), resp["code"])
+
+	resp = doJSONRequest(t, ctx, "GET", "/apis/channel/"+channelID+"/shares", owner, nil)
+	require.Equal(t, float64(0), resp["state"])
+	statuses := map[string]int{}
+	for _, item := range responseData(t, resp)["shares"].([]interface{}) {
+		statuses[item.(map[string]interface{})["status"].(string)]++
+	}
+	assert.Equal(t, map[string]int{"pending": 1, "accepted": 1, "declined": 1, "revoked": 1}, statuses)
+}
+
+// TestChannelShareSignUp 測試透過邀請連結註冊並自動接受邀請
+func TestChannelShareSignUp(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+
+	owner := getAuthCookie(t, ctx, "sharesignupowner", "sharesignupowner@example.com", "testpass123")
+	channelID := createChannel(t, ctx, owner, "Co-owned")
+	token := inviteToChannel(t, ctx, channelID, "sharesignupowner", "newcomer@example.com", "owner")
+
+	// Email 與邀請不同時不能以邀請註冊
+	resp := doJSONRequest(t, ctx, "POST", "/apis/signup", "", map[string]interface{}{
+		"share_token": token, "username": "stranger", "email": "stranger@example.com", "password": "testpass123",
+	})
+	assert.Equal(t, float64(2), resp["code"])
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"share_token": token, "username": "newcomer", "email": "newcomer@example.com", "password": "testpass123",
+	})
+	w := doRawRequest(t, ctx, "POST", "/apis/signup", "", "application/json", body)
+	assert.Contains(t, w.Body.String(), `"ret":true`)
+	cookie := w.Header().Get("Set-Cookie")
+	require.NotEmpty(t, cookie)
+
+	assert.Contains(t, ownChannelIDs(t, ctx, cookie), channelID)
+	assert.Contains(t, ownChannelsOf(t, ctx, "newcomer"), channelID)
+
+	// 邀請已接受，不能再用來註冊
+	resp = doJSONRequest(t, ctx, "POST", "/apis/signup", "", map[string]interface{}{
+		"share_token": token, "username": "newcomer2", "email": "newcomer@example.com", "password": "testpass123",
+	})
+	assert.Equal(t, float64(2), resp["code"])
+}
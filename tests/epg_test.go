@@ -0,0 +1,102 @@
+package tests
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestChannelEPG_XMLTV 測試單一頻道 XMLTV 節目表
+func TestChannelEPG_XMLTV(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+
+	cookie := getAuthCookie(t, ctx, "epguser", "epg@example.com", "testpass123")
+	epoch := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	channelID, _ := setupScheduleChannel(t, ctx, cookie, epoch)
+
+	to := epoch.Add(3 * time.Minute)
+	req, _ := http.NewRequest("GET", fmt.Sprintf("/apis/channel/%s/epg?from=%d&to=%d", channelID, epoch.Unix(), to.Unix()), nil)
+	w := httptest.NewRecorder()
+	ctx.Router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/xml")
+
+	var doc struct {
+		Channels []struct {
+			ID          string `xml:"id,attr"`
+			DisplayName string `xml:"display-name"`
+			Icon        struct {
+				Src string `xml:"src,attr"`
+			} `xml:"icon"`
+		} `xml:"channel"`
+		Programmes []struct {
+			Start   string `xml:"start,attr"`
+			Stop    string `xml:"stop,attr"`
+			Channel string `xml:"channel,attr"`
+			Title   string `xml:"title"`
+		} `xml:"programme"`
+	}
+	require.NoError(t, xml.Unmarshal(w.Body.Bytes(), &doc))
+
+	require.Len(t, doc.Channels, 1)
+	assert.Equal(t, channelID, doc.Channels[0].ID)
+	assert.Equal(t, "Schedule Channel", doc.Channels[0].DisplayName)
+
+	require.Len(t, doc.Programmes, 2)
+	assert.Equal(t, "One", doc.Programmes[0].Title)
+	assert.Equal(t, "20240101000000 +0000", doc.Programmes[0].Start)
+	assert.Equal(t, "20240101000100 +0000", doc.Programmes[0].Stop)
+	assert.Equal(t, "Two", doc.Programmes[1].Title)
+	assert.Equal(t, channelID, doc.Programmes[1].Channel)
+
+	// 頻道不存在
+	resp := doJSONRequest(t, ctx, "GET", "/apis/channel/not-exist/epg", "", nil)
+	assert.Equal(t, float64(1), resp["state"])
+	assert.Equal(t, float64(2), resp["code"])
+}
+
+// TestEPG_JSON 測試多頻道 JSON 節目表與過濾條件
+func TestEPG_JSON(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+
+	cookie := getAuthCookie(t, ctx, "epguser", "epg@example.com", "testpass123")
+	epoch := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	channelID, ids := setupScheduleChannel(t, ctx, cookie, epoch)
+
+	to := epoch.Add(3 * time.Minute)
+	path := fmt.Sprintf("/apis/epg?format=json&has_contents=1&from=%d&to=%d", epoch.Unix(), to.Unix())
+	resp := doJSONRequest(t, ctx, "GET", path, "", nil)
+	require.Equal(t, float64(0), resp["state"])
+
+	guide := responseData(t, resp)["guide"].(map[string]interface{})
+	channels := guide["channels"].([]interface{})
+	require.Len(t, channels, 1)
+	channel := channels[0].(map[string]interface{})
+	assert.Equal(t, channelID, channel["id"])
+
+	programmes := channel["programmes"].([]interface{})
+	require.Len(t, programmes, 2)
+	first := programmes[0].(map[string]interface{})
+	assert.Equal(t, float64(ids[0]), first["program_id"])
+	assert.Equal(t, "One", first["title"])
+	assert.Equal(t, float64(60), first["duration"])
+
+	// 名稱過濾不到任何頻道
+	resp = doJSONRequest(t, ctx, "GET", "/apis/epg?format=json&q=nothing-matches", "", nil)
+	require.Equal(t, float64(0), resp["state"])
+	guide = responseData(t, resp)["guide"].(map[string]interface{})
+	assert.Empty(t, guide["channels"])
+
+	// 不支援的格式
+	resp = doJSONRequest(t, ctx, "GET", "/apis/epg?format=csv", "", nil)
+	assert.Equal(t, float64(1), resp["state"])
+	assert.Equal(t, float64(0), resp["code"])
+}
@@ -0,0 +1,154 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/higgstv/higgstv-go/internal/playlist"
+)
+
+const testPlaylistFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns:yt="http://www.youtube.com/xml/schemas/2015" xmlns="http://www.w3.org/2005/Atom">
+  <title>Test Playlist</title>
+  <entry>
+    <yt:videoId>dQw4w9WgXcQ</yt:videoId>
+    <title>Never Gonna Give You Up</title>
+  </entry>
+  <entry>
+    <yt:videoId>9bZkp7q19f0</yt:videoId>
+    <title>Gangnam Style</title>
+  </entry>
+  <entry>
+    <yt:videoId>kJQP7kiw5Fk</yt:videoId>
+    <title>Despacito</title>
+  </entry>
+  <entry>
+    <yt:videoId>9bZkp7q19f0</yt:videoId>
+    <title>Gangnam Style (again)</title>
+  </entry>
+</feed>`
+
+// startPlaylistServer 啟動本機的 YouTube 播放清單 RSS 服務（只有 PLtest 存在）
+func startPlaylistServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/feeds/videos.xml" || r.URL.Query().Get("playlist_id") != "PLtest" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/atom+xml")
+		_, _ = w.Write([]byte(testPlaylistFeed))
+	}))
+	t.Cleanup(server.Close)
+	playlist.InitResolver(playlist.ResolverConfig{BaseURL: server.URL})
+	return server
+}
+
+// TestImportYouTubePlaylist 測試匯入 YouTube 播放清單並略過重複的影片
+func TestImportYouTubePlaylist(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+	startPlaylistServer(t)
+
+	cookie := getAuthCookie(t, ctx, "importuser", "import@example.com", "testpass123")
+	channelID := createChannel(t, ctx, cookie, "Import Channel")
+	existingID := addProgram(t, ctx, cookie, channelID, "Already Here", "dQw4w9WgXcQ", 60)
+
+	resp := doJSONRequest(t, ctx, "POST", "/apis/channel/"+channelID+"/import", cookie, map[string]interface{}{
+		"url": "https://www.youtube.com/playlist?list=PLtest",
+		"urls": []string{
+			"https://vimeo.com/76979871",
+			"https://youtu.be/kJQP7kiw5Fk",
+			"https://example.com/page.html",
+		},
+	})
+	require.Equal(t, float64(0), resp["state"])
+	data := responseData(t, resp)
+	assert.Equal(t, "Test Playlist", data["title"])
+	assert.Equal(t, float64(3), data["added"])
+	assert.Equal(t, float64(4), data["skipped"])
+
+	results := data["results"].([]interface{})
+	require.Len(t, results, 7)
+	expected := []struct {
+		status string
+		reason string
+	}{
+		{"skipped", "duplicate"},
+		{"added", ""},
+		{"added", ""},
+		{"skipped", "duplicate"},
+		{"added", ""},
+		{"skipped", "duplicate"},
+		{"skipped", "unsupported_url"},
+	}
+	for i, want := range expected {
+		result := results[i].(map[string]interface{})
+		assert.Equal(t, want.status, result["status"], "result %d", i)
+		if want.reason != "" {
+			assert.Equal(t, want.reason, result["reason"], "result %d", i)
+		} else {
+			assert.NotZero(t, result["program_id"], "result %d", i)
+		}
+	}
+
+	resp = doJSONRequest(t, ctx, "GET", "/apis/getchannel/"+channelID, "", nil)
+	channel := responseData(t, resp)["channel"].(map[string]interface{})
+	contents := channel["contents"].([]interface{})
+	require.Len(t, contents, 4)
+	assert.Equal(t, float64(existingID), contents[0].(map[string]interface{})["_id"])
+	names := make([]string, 0, len(contents))
+	for _, content := range contents {
+		names = append(names, content.(map[string]interface{})["name"].(string))
+	}
+	assert.Equal(t, []string{"Already Here", "Gangnam Style", "Despacito", "https://vimeo.com/76979871"}, names)
+	assert.Equal(t, "vimeo", contents[3].(map[string]interface{})["type"])
+
+	// 再次匯入時全部略過
+	resp = doJSONRequest(t, ctx, "POST", "/apis/channel/"+channelID+"/import", cookie, map[string]interface{}{
+		"url": "https://www.youtube.com/watch?v=dQw4w9WgXcQ&list=PLtest",
+	})
+	require.Equal(t, float64(0), resp["state"])
+	data = responseData(t, resp)
+	assert.Equal(t, float64(0), data["added"])
+	assert.Equal(t, float64(4), data["skipped"])
+}
+
+// TestImportChannelErrors 測試匯入的權限檢查與錯誤處理
+func TestImportChannelErrors(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+	startPlaylistServer(t)
+
+	ownerCookie := getAuthCookie(t, ctx, "importowner", "importowner@example.com", "testpass123")
+	channelID := createChannel(t, ctx, ownerCookie, "Owner Channel")
+
+	otherCookie := getAuthCookie(t, ctx, "importother", "importother@example.com", "testpass123")
+	resp := doJSONRequest(t, ctx, "POST", "/apis/channel/"+channelID+"/import", otherCookie, map[string]interface{}{
+		"url": "https://www.youtube.com/playlist?list=PLtest",
+	})
+	assert.Equal(t, float64(1), resp["state"])
+	assert.Equal(t, float64(2), resp["code"])
+
+	// 未提供 URL
+	resp = doJSONRequest(t, ctx, "POST", "/apis/channel/"+channelID+"/import", ownerCookie, map[string]interface{}{})
+	assert.Equal(t, float64(1), resp["state"])
+	assert.Equal(t, float64(0), resp["code"])
+
+	// 播放清單不存在
+	resp = doJSONRequest(t, ctx, "POST", "/apis/channel/"+channelID+"/import", ownerCookie, map[string]interface{}{
+		"url": "https://www.youtube.com/playlist?list=PLmissing",
+	})
+	assert.Equal(t, float64(1), resp["state"])
+	assert.Equal(t, float64(0), resp["code"])
+
+	// 不是播放清單時當作單一影片匯入
+	resp = doJSONRequest(t, ctx, "POST", "/apis/channel/"+channelID+"/import", ownerCookie, map[string]interface{}{
+		"url": "https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+	})
+	require.Equal(t, float64(0), resp["state"])
+	assert.Equal(t, float64(1), responseData(t, resp)["added"])
+}
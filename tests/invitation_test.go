@@ -0,0 +1,126 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/higgstv/higgstv-go/internal/models"
+	"github.com/higgstv/higgstv-go/internal/repository"
+)
+
+// signUp 輔助函數：以指定邀請碼註冊
+func signUp(t *testing.T, ctx *TestDBContext, code, username string) map[string]interface{} {
+	return doJSONRequest(t, ctx, "POST", "/apis/signup", "", map[string]interface{}{
+		"invitation_code": code,
+		"username":        username,
+		"email":           username + "@example.com",
+		"password":        "testpass123",
+	})
+}
+
+// TestInvitationLifecycle 測試管理員建立、列出與撤銷邀請碼，以及註冊時記錄使用的邀請碼
+func TestInvitationLifecycle(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+	ctx.Config.Admin.Users = []string{"inviteadmin"}
+
+	adminCookie := getAuthCookie(t, ctx, "inviteadmin", "inviteadmin@example.com", "testpass123")
+
+	// 未指定次數時為單次使用
+	resp := doJSONRequest(t, ctx, "POST", "/apis/admin/invitations", adminCookie, map[string]interface{}{
+		"code": "spring-2026",
+		"note": "book club",
+	})
+	require.Equal(t, float64(0), resp["state"])
+	invitation := responseData(t, resp)["invitation"].(map[string]interface{})
+	assert.Equal(t, "spring-2026", invitation["code"])
+	assert.Equal(t, float64(1), invitation["max_uses"])
+
+	// 重複的邀請碼
+	resp = doJSONRequest(t, ctx, "POST", "/apis/admin/invitations", adminCookie, map[string]interface{}{
+		"code": "spring-2026",
+	})
+	assert.Equal(t, float64(1), resp["state"])
+	assert.Equal(t, float64(0), resp["code"])
+
+	resp = signUp(t, ctx, "spring-2026", "invitee")
+	require.Equal(t, float64(0), resp["state"])
+	assert.Equal(t, true, resp["ret"])
+
+	resp = signUp(t, ctx, "spring-2026", "invitee2")
+	assert.Equal(t, float64(1), resp["state"])
+	assert.Equal(t, float64(2), resp["code"])
+
+	user, err := repository.NewUserRepository(ctx.DB).FindByUsername(context.Background(), "invitee")
+	require.NoError(t, err)
+	require.NotNil(t, user.InvitationID)
+	assert.Equal(t, invitation["_id"], *user.InvitationID)
+
+	// 自動產生的多次使用邀請碼
+	resp = doJSONRequest(t, ctx, "POST", "/apis/admin/invitations", adminCookie, map[string]interface{}{
+		"max_uses":   2,
+		"expires_at": time.Now().Add(time.Hour).Format(time.RFC3339),
+	})
+	require.Equal(t, float64(0), resp["state"])
+	generated := responseData(t, resp)["invitation"].(map[string]interface{})
+	code := generated["code"].(string)
+	assert.NotEmpty(t, code)
+	for _, username := range []string{"multi1", "multi2"} {
+		resp = signUp(t, ctx, code, username)
+		require.Equal(t, float64(0), resp["state"], username)
+	}
+	resp = signUp(t, ctx, code, "multi3")
+	assert.Equal(t, float64(2), resp["code"])
+
+	resp = doJSONRequest(t, ctx, "GET", "/apis/admin/invitations", adminCookie, nil)
+	require.Equal(t, float64(0), resp["state"])
+	uses := map[string]float64{}
+	ids := map[string]string{}
+	for _, item := range responseData(t, resp)["invitations"].([]interface{}) {
+		invitation := item.(map[string]interface{})
+		uses[invitation["code"].(string)] = invitation["uses"].(float64)
+		ids[invitation["code"].(string)] = invitation["_id"].(string)
+	}
+	assert.Equal(t, float64(1), uses["spring-2026"])
+	assert.Equal(t, float64(2), uses[code])
+	// 原本的邀請碼由遷移建立（註冊 inviteadmin 時使用）
+	assert.Equal(t, float64(1), uses["sixpens"])
+
+	// 撤銷原本的邀請碼
+	resp = doJSONRequest(t, ctx, "POST", "/apis/admin/invitations/"+ids["sixpens"]+"/revoke", adminCookie, nil)
+	require.Equal(t, float64(0), resp["state"])
+	resp = signUp(t, ctx, "sixpens", "latecomer")
+	assert.Equal(t, float64(2), resp["code"])
+
+	resp = doJSONRequest(t, ctx, "POST", "/apis/admin/invitations/missing/revoke", adminCookie, nil)
+	assert.Equal(t, float64(0), resp["code"])
+}
+
+// TestInvitationRestrictions 測試過期邀請碼與非管理員的存取
+func TestInvitationRestrictions(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+
+	expired := time.Now().Add(-time.Minute)
+	require.NoError(t, repository.NewInvitationRepository(ctx.DB).Create(context.Background(), &models.Invitation{
+		ID:        "expired-invitation",
+		Code:      "expired-code",
+		ExpiresAt: &expired,
+	}))
+	resp := signUp(t, ctx, "expired-code", "tooLate")
+	assert.Equal(t, float64(1), resp["state"])
+	assert.Equal(t, float64(2), resp["code"])
+
+	cookie := getAuthCookie(t, ctx, "notadmin", "notadmin@example.com", "testpass123")
+	resp = doJSONRequest(t, ctx, "POST", "/apis/admin/invitations", cookie, map[string]interface{}{"code": "sneaky"})
+	assert.Equal(t, float64(2), resp["code"])
+	resp = doJSONRequest(t, ctx, "GET", "/apis/admin/invitations", cookie, nil)
+	assert.Equal(t, float64(2), resp["code"])
+
+	resp = doJSONRequest(t, ctx, "GET", "/apis/admin/invitations", "", nil)
+	assert.Equal(t, float64(1), resp["code"])
+}
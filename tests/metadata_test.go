@@ -0,0 +1,219 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/higgstv/higgstv-go/internal/repository"
+	"github.com/higgstv/higgstv-go/internal/service"
+	"github.com/higgstv/higgstv-go/pkg/metadata"
+)
+
+// startOEmbedServer 啟動本機 oEmbed 與 YouTube Data API 服務，回傳服務與請求次數
+func startOEmbedServer(t *testing.T) (*httptest.Server, *int32) {
+	var hits int32
+	responses := map[string]map[string]interface{}{
+		"https://vimeo.com/76979871": {
+			"title":       "Vimeo Title",
+			"description": "Vimeo description",
+			"duration":    62,
+		},
+		"https://www.youtube.com/watch?v=dQw4w9WgXcQ": {
+			"title":       "Never Gonna Give You Up",
+			"author_name": "Rick Astley",
+		},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		if r.URL.Path == "/youtube/v3/videos" {
+			items := []interface{}{}
+			if r.URL.Query().Get("key") == "test-key" && r.URL.Query().Get("id") == "dQw4w9WgXcQ" {
+				items = append(items, map[string]interface{}{
+					"snippet":        map[string]interface{}{"title": "Never Gonna Give You Up", "channelTitle": "Rick Astley"},
+					"contentDetails": map[string]interface{}{"duration": "PT3M33S"},
+				})
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": items})
+			return
+		}
+		if r.URL.Path != "/oembed" {
+			http.NotFound(w, r)
+			return
+		}
+		body, ok := responses[r.URL.Query().Get("url")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	t.Cleanup(server.Close)
+	return server, &hits
+}
+
+// enableMetadata 啟用指向本機 oEmbed 服務的影片資訊查詢（youtubeAPIKey 為空時 YouTube 使用 oEmbed）
+func enableMetadata(t *testing.T, baseURL, youtubeAPIKey string) {
+	metadata.Init(metadata.Config{
+		BaseURL:       baseURL,
+		Timeout:       5 * time.Second,
+		CacheTTL:      time.Hour,
+		YouTubeAPIKey: youtubeAPIKey,
+	})
+	t.Cleanup(func() {
+		metadata.Default = nil
+	})
+}
+
+// TestAddProgramWithMetadata 測試新增節目時補齊名稱與時長
+func TestAddProgramWithMetadata(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+
+	server, hits := startOEmbedServer(t)
+	enableMetadata(t, server.URL, "")
+
+	cookie := getAuthCookie(t, ctx, "metauser", "meta@example.com", "testpass123")
+	channelID := createChannel(t, ctx, cookie, "Metadata Channel")
+
+	// 名稱與時長都由 oEmbed 補齊
+	resp := doJSONRequest(t, ctx, "POST", "/apis/addprog", cookie, map[string]interface{}{
+		"ch":  channelID,
+		"url": "https://vimeo.com/76979871",
+	})
+	require.Equal(t, float64(0), resp["state"])
+	program := responseData(t, resp)["program"].(map[string]interface{})
+	assert.Equal(t, "Vimeo Title", program["name"])
+	assert.Equal(t, "Vimeo description", program["desc"])
+	assert.Equal(t, float64(62), program["duration"])
+	assert.NotEmpty(t, program["metadata_at"])
+
+	// 已提供的欄位不會被覆蓋；第二次查詢使用快取
+	resp = doJSONRequest(t, ctx, "POST", "/apis/addprog", cookie, map[string]interface{}{
+		"ch":   channelID,
+		"name": "My Name",
+		"url":  "https://vimeo.com/76979871",
+	})
+	require.Equal(t, float64(0), resp["state"])
+	program = responseData(t, resp)["program"].(map[string]interface{})
+	assert.Equal(t, "My Name", program["name"])
+	assert.Equal(t, float64(62), program["duration"])
+	assert.Equal(t, int32(1), atomic.LoadInt32(hits))
+
+	// oEmbed 沒有時長時保留 0，且不記錄查詢時間
+	resp = doJSONRequest(t, ctx, "POST", "/apis/addprog", cookie, map[string]interface{}{
+		"ch":         channelID,
+		"youtube_id": "dQw4w9WgXcQ",
+	})
+	require.Equal(t, float64(0), resp["state"])
+	program = responseData(t, resp)["program"].(map[string]interface{})
+	assert.Equal(t, "Never Gonna Give You Up", program["name"])
+	assert.Equal(t, float64(0), program["duration"])
+	assert.Empty(t, program["metadata_at"])
+
+	// 查不到影片且沒有名稱
+	resp = doJSONRequest(t, ctx, "POST", "/apis/addprog", cookie, map[string]interface{}{
+		"ch":         channelID,
+		"youtube_id": "9bZkp7q19f0",
+	})
+	assert.Equal(t, float64(1), resp["state"])
+	assert.Equal(t, float64(0), resp["code"])
+}
+
+// TestAddProgramWithoutNameMetadataDisabled 測試未啟用影片資訊查詢時名稱仍為必填
+func TestAddProgramWithoutNameMetadataDisabled(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+
+	cookie := getAuthCookie(t, ctx, "nometa", "nometa@example.com", "testpass123")
+	channelID := createChannel(t, ctx, cookie, "No Metadata Channel")
+
+	resp := doJSONRequest(t, ctx, "POST", "/apis/addprog", cookie, map[string]interface{}{
+		"ch":         channelID,
+		"youtube_id": "dQw4w9WgXcQ",
+	})
+	assert.Equal(t, float64(1), resp["state"])
+	assert.Equal(t, float64(0), resp["code"])
+}
+
+// TestBackfillMetadata 測試背景補齊既有節目的時長
+func TestBackfillMetadata(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+
+	cookie := getAuthCookie(t, ctx, "backfill", "backfill@example.com", "testpass123")
+	channelID := createChannel(t, ctx, cookie, "Backfill Channel")
+
+	// 未啟用時新增的節目沒有時長
+	resp := doJSONRequest(t, ctx, "POST", "/apis/addprog", cookie, map[string]interface{}{
+		"ch":   channelID,
+		"name": "Vimeo",
+		"url":  "https://vimeo.com/76979871",
+	})
+	require.Equal(t, float64(0), resp["state"])
+	vimeoID := int(responseData(t, resp)["program"].(map[string]interface{})["_id"].(float64))
+	missingID := addProgram(t, ctx, cookie, channelID, "Missing", "9bZkp7q19f0", 0)
+	youtubeID := addProgram(t, ctx, cookie, channelID, "YouTube", "dQw4w9WgXcQ", 0)
+	fileResp := doJSONRequest(t, ctx, "POST", "/apis/addprog", cookie, map[string]interface{}{
+		"ch":   channelID,
+		"name": "File",
+		"url":  "https://cdn.example.com/videos/clip.mp4",
+	})
+	require.Equal(t, float64(0), fileResp["state"])
+
+	server, hits := startOEmbedServer(t)
+	// 查詢器在建立服務時取得，切換設定後需重新建立
+	newProgramService := func() *service.ProgramService {
+		return service.NewProgramService(
+			repository.NewProgramRepository(ctx.DB),
+			repository.NewChannelRepository(ctx.DB),
+		)
+	}
+
+	// 未設定 YouTube Data API 金鑰時 oEmbed 查不到 YouTube 時長，只補齊 Vimeo
+	enableMetadata(t, server.URL, "")
+	processed, err := newProgramService().BackfillMetadata(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, processed) // MP4 不支援查詢，不計入
+	assert.Equal(t, int32(1), atomic.LoadInt32(hits))
+
+	// 設定金鑰後 YouTube 改用 Data API 補齊時長
+	enableMetadata(t, server.URL, "test-key")
+	programService := newProgramService()
+	processed, err = programService.BackfillMetadata(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, 2, processed)
+	assert.Equal(t, int32(3), atomic.LoadInt32(hits))
+
+	resp = doJSONRequest(t, ctx, "GET", "/apis/getchannel/"+channelID, "", nil)
+	channel := responseData(t, resp)["channel"].(map[string]interface{})
+	byID := map[int]map[string]interface{}{}
+	for _, item := range channel["contents"].([]interface{}) {
+		program := item.(map[string]interface{})
+		byID[int(program["_id"].(float64))] = program
+	}
+	assert.Equal(t, float64(62), byID[vimeoID]["duration"])
+	assert.Equal(t, "Vimeo", byID[vimeoID]["name"])
+	assert.NotEmpty(t, byID[vimeoID]["metadata_at"])
+	assert.Equal(t, float64(0), byID[missingID]["duration"])
+	assert.NotEmpty(t, byID[missingID]["metadata_at"])
+	assert.Equal(t, float64(213), byID[youtubeID]["duration"])
+	assert.Equal(t, "YouTube", byID[youtubeID]["name"])
+	assert.NotEmpty(t, byID[youtubeID]["metadata_at"])
+
+	// 已查詢過的節目不會重複查詢
+	processed, err = programService.BackfillMetadata(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, 0, processed)
+	assert.Equal(t, int32(3), atomic.LoadInt32(hits))
+}
@@ -0,0 +1,168 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// doRawRequest 輔助函數：送出原始內容請求並回傳 recorder
+func doRawRequest(t *testing.T, ctx *TestDBContext, method, path, cookie, contentType string, body []byte) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, path, bytes.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if cookie != "" {
+		req.Header.Set("Cookie", cookie)
+	}
+	w := httptest.NewRecorder()
+	ctx.Router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	return w
+}
+
+// TestExportChannel 測試依節目順序匯出播放清單
+func TestExportChannel(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+
+	cookie := getAuthCookie(t, ctx, "listuser", "list@example.com", "testpass123")
+	channelID := createChannel(t, ctx, cookie, "Export Channel")
+	first := addProgram(t, ctx, cookie, channelID, "First", "dQw4w9WgXcQ", 60)
+	second := addProgram(t, ctx, cookie, channelID, "Second", "9bZkp7q19f0", 0)
+
+	resp := doJSONRequest(t, ctx, "POST", "/apis/prog/saveorder", cookie, map[string]interface{}{
+		"ch":    channelID,
+		"order": []int{second, first},
+	})
+	require.Equal(t, float64(0), resp["state"])
+
+	// M3U
+	w := doRawRequest(t, ctx, "GET", "/apis/channel/"+channelID+"/export?format=m3u", "", "", nil)
+	assert.Contains(t, w.Header().Get("Content-Type"), "mpegurl")
+	assert.Equal(t, "#EXTM3U\n#PLAYLIST:Export Channel\n"+
+		"#EXTINF:-1,Second\nhttps://www.youtube.com/watch?v=9bZkp7q19f0\n"+
+		"#EXTINF:60,First\nhttps://www.youtube.com/watch?v=dQw4w9WgXcQ\n", w.Body.String())
+
+	// XSPF
+	w = doRawRequest(t, ctx, "GET", "/apis/channel/"+channelID+"/export?format=xspf", "", "", nil)
+	body := w.Body.String()
+	assert.Contains(t, body, `<playlist version="1" xmlns="http://xspf.org/ns/0/">`)
+	assert.Contains(t, body, "<duration>60000</duration>")
+	assert.Less(t, strings.Index(body, "Second"), strings.Index(body, "First"))
+
+	// JSON
+	w = doRawRequest(t, ctx, "GET", "/apis/channel/"+channelID+"/export?format=json", "", "", nil)
+	var exported map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &exported))
+	entries := exported["entries"].([]interface{})
+	require.Len(t, entries, 2)
+	assert.Equal(t, "9bZkp7q19f0", entries[0].(map[string]interface{})["youtube_id"])
+
+	// 不支援的格式
+	resp = doJSONRequest(t, ctx, "GET", "/apis/channel/"+channelID+"/export?format=pls", "", nil)
+	assert.Equal(t, float64(1), resp["state"])
+	assert.Equal(t, float64(0), resp["code"])
+}
+
+// TestImportChannelFile_M3U 測試匯入 M3U 並回報略過的項目
+func TestImportChannelFile_M3U(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+
+	cookie := getAuthCookie(t, ctx, "listuser", "list@example.com", "testpass123")
+	channelID := createChannel(t, ctx, cookie, "Import Channel")
+
+	m3u := "#EXTM3U\n" +
+		"#EXTINF:212,Never Gonna Give You Up\n" +
+		"https://youtu.be/dQw4w9WgXcQ\n" +
+		"#EXTINF:-1,Not A Video\n" +
+		"https://example.com/page.html\n" +
+		"#EXTINF:30,Embedded\n" +
+		"https://www.youtube.com/embed/9bZkp7q19f0?autoplay=1\n"
+	w := doRawRequest(t, ctx, "POST", "/apis/channel/"+channelID+"/import/file", cookie, "audio/x-mpegurl", []byte(m3u))
+
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, float64(0), resp["state"])
+	data := responseData(t, resp)
+	assert.Equal(t, "m3u", data["format"])
+	assert.Equal(t, float64(2), data["added"])
+	assert.Equal(t, float64(1), data["skipped"])
+
+	results := data["results"].([]interface{})
+	require.Len(t, results, 3)
+	skipped := results[1].(map[string]interface{})
+	assert.Equal(t, "skipped", skipped["status"])
+	assert.Equal(t, "unsupported_url", skipped["reason"])
+	assert.Equal(t, "https://example.com/page.html", skipped["url"])
+
+	// 確認節目已依序新增
+	resp = doJSONRequest(t, ctx, "GET", "/apis/getchannel/"+channelID, "", nil)
+	channel := responseData(t, resp)["channel"].(map[string]interface{})
+	contents := channel["contents"].([]interface{})
+	require.Len(t, contents, 2)
+	assert.Equal(t, "Never Gonna Give You Up", contents[0].(map[string]interface{})["name"])
+	assert.Equal(t, float64(212), contents[0].(map[string]interface{})["duration"])
+	assert.Equal(t, "9bZkp7q19f0", contents[1].(map[string]interface{})["youtube_id"])
+}
+
+// TestImportChannelFile_XSPFMultipart 測試以 multipart 上傳 XSPF
+func TestImportChannelFile_XSPFMultipart(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+
+	cookie := getAuthCookie(t, ctx, "listuser", "list@example.com", "testpass123")
+	channelID := createChannel(t, ctx, cookie, "Import Channel")
+
+	xspf := `<?xml version="1.0" encoding="UTF-8"?>
+<playlist version="1" xmlns="http://xspf.org/ns/0/">
+  <trackList>
+    <track><location>https://www.youtube.com/watch?v=dQw4w9WgXcQ&amp;t=10</location><title>Track</title><duration>61000</duration></track>
+    <track><title>No location</title></track>
+  </trackList>
+</playlist>`
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", "list.xspf")
+	require.NoError(t, err)
+	_, _ = part.Write([]byte(xspf))
+	require.NoError(t, writer.Close())
+
+	w := doRawRequest(t, ctx, "POST", "/apis/channel/"+channelID+"/import/file", cookie, writer.FormDataContentType(), body.Bytes())
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, float64(0), resp["state"])
+	data := responseData(t, resp)
+	assert.Equal(t, "xspf", data["format"])
+	assert.Equal(t, float64(1), data["added"])
+
+	results := data["results"].([]interface{})
+	require.Len(t, results, 2)
+	assert.Equal(t, "missing_url", results[1].(map[string]interface{})["reason"])
+}
+
+// TestImportChannelFile_AccessDenied 測試非管理員無法匯入
+func TestImportChannelFile_AccessDenied(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+
+	owner := getAuthCookie(t, ctx, "listuser", "list@example.com", "testpass123")
+	other := getAuthCookie(t, ctx, "otheruser", "other@example.com", "testpass123")
+	channelID := createChannel(t, ctx, owner, "Import Channel")
+
+	w := doRawRequest(t, ctx, "POST", "/apis/channel/"+channelID+"/import/file?format=json", other, "application/json",
+		[]byte(`{"title":"x","entries":[{"url":"https://youtu.be/dQw4w9WgXcQ"}]}`))
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, float64(1), resp["state"])
+	assert.Equal(t, float64(2), resp["code"])
+}
@@ -0,0 +1,133 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAddProgramWithProviderURL 測試以各來源的 URL 新增節目，並確認類型與影片 ID 有保存
+func TestAddProgramWithProviderURL(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+
+	cookie := getAuthCookie(t, ctx, "provideruser", "provider@example.com", "testpass123")
+	channelID := createChannel(t, ctx, cookie, "Provider Channel")
+
+	cases := []struct {
+		url       string
+		wantType  string
+		wantID    string
+		youtubeID string
+	}{
+		{"https://www.youtube.com/watch?v=dQw4w9WgXcQ", "youtube", "dQw4w9WgXcQ", "dQw4w9WgXcQ"},
+		{"https://vimeo.com/76979871", "vimeo", "76979871", ""},
+		{"https://www.dailymotion.com/video/x8abc12_some-title", "dailymotion", "x8abc12", ""},
+		{"https://cdn.example.com/videos/clip.mp4", "mp4", "https://cdn.example.com/videos/clip.mp4", ""},
+		{"https://cdn.example.com/live/stream.m3u8", "hls", "https://cdn.example.com/live/stream.m3u8", ""},
+	}
+	for _, tc := range cases {
+		resp := doJSONRequest(t, ctx, "POST", "/apis/addprog", cookie, map[string]interface{}{
+			"ch":       channelID,
+			"name":     "Program " + tc.wantType,
+			"url":      tc.url,
+			"duration": 60,
+		})
+		require.Equal(t, float64(0), resp["state"], tc.url)
+	}
+
+	resp := doJSONRequest(t, ctx, "GET", "/apis/getchannel/"+channelID, "", nil)
+	channel := responseData(t, resp)["channel"].(map[string]interface{})
+	contents := channel["contents"].([]interface{})
+	require.Len(t, contents, len(cases))
+	for i, tc := range cases {
+		program := contents[i].(map[string]interface{})
+		assert.Equal(t, tc.wantType, program["type"], tc.url)
+		assert.Equal(t, tc.wantID, program["video_id"], tc.url)
+		assert.Equal(t, tc.youtubeID, program["youtube_id"], tc.url)
+	}
+
+	// 不支援的 URL
+	resp = doJSONRequest(t, ctx, "POST", "/apis/addprog", cookie, map[string]interface{}{
+		"ch":   channelID,
+		"name": "Unsupported",
+		"url":  "https://example.com/page.html",
+	})
+	assert.Equal(t, float64(1), resp["state"])
+	assert.Equal(t, float64(0), resp["code"])
+
+	// 影片 ID 與類型不符
+	resp = doJSONRequest(t, ctx, "POST", "/apis/addprog", cookie, map[string]interface{}{
+		"ch":       channelID,
+		"name":     "Bad ID",
+		"type":     "vimeo",
+		"video_id": "not-a-number",
+	})
+	assert.Equal(t, float64(1), resp["state"])
+	assert.Equal(t, float64(0), resp["code"])
+}
+
+// TestProviderUpdateCover 測試依來源更新頻道封面
+func TestProviderUpdateCover(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+
+	cookie := getAuthCookie(t, ctx, "coveruser", "cover@example.com", "testpass123")
+	channelID := createChannel(t, ctx, cookie, "Cover Channel")
+
+	resp := doJSONRequest(t, ctx, "POST", "/apis/addprog", cookie, map[string]interface{}{
+		"ch":          channelID,
+		"name":        "Dailymotion",
+		"type":        "dailymotion",
+		"video_id":    "x8abc12",
+		"updateCover": true,
+	})
+	require.Equal(t, float64(0), resp["state"])
+	programID := int(responseData(t, resp)["program"].(map[string]interface{})["_id"].(float64))
+
+	resp = doJSONRequest(t, ctx, "GET", "/apis/getchannel/"+channelID, "", nil)
+	channel := responseData(t, resp)["channel"].(map[string]interface{})
+	cover := channel["cover"].(map[string]interface{})
+	assert.Equal(t, "https://www.dailymotion.com/thumbnail/video/x8abc12", cover["default"])
+
+	// 改為 MP4 後沒有縮圖，封面維持不變
+	resp = doJSONRequest(t, ctx, "POST", "/apis/saveprog", cookie, map[string]interface{}{
+		"ch":          channelID,
+		"prog_id":     programID,
+		"name":        "Direct File",
+		"url":         "https://cdn.example.com/videos/clip.mp4",
+		"updateCover": true,
+	})
+	require.Equal(t, float64(0), resp["state"])
+	program := responseData(t, resp)["program"].(map[string]interface{})
+	assert.Equal(t, "mp4", program["type"])
+	assert.Equal(t, "https://cdn.example.com/videos/clip.mp4", program["video_id"])
+	assert.Equal(t, "", program["youtube_id"])
+
+	resp = doJSONRequest(t, ctx, "GET", "/apis/getchannel/"+channelID, "", nil)
+	channel = responseData(t, resp)["channel"].(map[string]interface{})
+	cover = channel["cover"].(map[string]interface{})
+	assert.Equal(t, "https://www.dailymotion.com/thumbnail/video/x8abc12", cover["default"])
+}
+
+// TestPickProgramWithVimeoURL 測試 PickProgram 使用非 YouTube 的 url
+func TestPickProgramWithVimeoURL(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+
+	cookie := getAuthCookie(t, ctx, "pickvimeo", "pickvimeo@example.com", "testpass123")
+
+	encodedURL := url.QueryEscape("https://player.vimeo.com/video/76979871")
+	req, _ := http.NewRequest("GET", "/apis/pickprog?callback=testCallback&name=Vimeo+Video&url="+encodedURL, nil)
+	req.Header.Set("Cookie", cookie)
+	w := httptest.NewRecorder()
+	ctx.Router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"type":"vimeo"`)
+	assert.Contains(t, w.Body.String(), `"video_id":"76979871"`)
+}
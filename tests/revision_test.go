@@ -0,0 +1,184 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// channelETag 輔助函數：取得頻道目前的 ETag
+func channelETag(t *testing.T, ctx *TestDBContext, cookie, channelID string) string {
+	w := doRawRequest(t, ctx, "GET", "/apis/getchannel/"+channelID, cookie, "", nil)
+	etag := w.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	return etag
+}
+
+// doIfMatchRequest 輔助函數：帶 If-Match 標頭發送 JSON 請求
+func doIfMatchRequest(t *testing.T, ctx *TestDBContext, path, cookie, ifMatch string, payload interface{}) (map[string]interface{}, string) {
+	body, err := json.Marshal(payload)
+	require.NoError(t, err)
+	req, _ := http.NewRequest("POST", path, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Cookie", cookie)
+	req.Header.Set("If-Match", ifMatch)
+	w := httptest.NewRecorder()
+	ctx.Router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp, w.Header().Get("ETag")
+}
+
+// TestChannelRevisionConflict 測試同時編輯頻道時以 If-Match 偵測衝突
+func TestChannelRevisionConflict(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+
+	cookie := getAuthCookie(t, ctx, "reviser", "reviser@example.com", "testpass123")
+	channelID := createChannel(t, ctx, cookie, "Revised")
+	etag := channelETag(t, ctx, cookie, channelID)
+
+	// 第一位編輯者成功，取得新的 ETag
+	resp, newETag := doIfMatchRequest(t, ctx, "/apis/savechannel", cookie, etag, map[string]interface{}{
+		"id": channelID, "name": "First Edit",
+	})
+	require.Equal(t, float64(0), resp["state"])
+	assert.NotEmpty(t, newETag)
+	assert.NotEqual(t, etag, newETag)
+	assert.Equal(t, newETag, channelETag(t, ctx, cookie, channelID))
+
+	// 第二位編輯者使用舊的 ETag，不會覆蓋第一位的修改
+	resp, _ = doIfMatchRequest(t, ctx, "/apis/savechannel", cookie, etag, map[string]interface{}{
+		"id": channelID, "name": "Second Edit",
+	})
+	assert.Equal(t, float64(3), resp["code"])
+	resp = doJSONRequest(t, ctx, "GET", "/apis/getchannel/"+channelID, cookie, nil)
+	require.Equal(t, float64(0), resp["state"])
+	channel := responseData(t, resp)["channel"].(map[string]interface{})
+	assert.Equal(t, "First Edit", channel["name"])
+
+	// 也可以用 revision 欄位指定版本
+	resp = doJSONRequest(t, ctx, "POST", "/apis/savechannel", cookie, map[string]interface{}{
+		"id": channelID, "name": "Third Edit", "revision": channel["revision"],
+	})
+	require.Equal(t, float64(0), resp["state"])
+	resp = doJSONRequest(t, ctx, "POST", "/apis/savechannel", cookie, map[string]interface{}{
+		"id": channelID, "name": "Fourth Edit", "revision": channel["revision"],
+	})
+	assert.Equal(t, float64(3), resp["code"])
+
+	// 未指定版本時照常更新
+	resp = doJSONRequest(t, ctx, "POST", "/apis/savechannel", cookie, map[string]interface{}{
+		"id": channelID, "name": "Unconditional",
+	})
+	require.Equal(t, float64(0), resp["state"])
+
+	resp, _ = doIfMatchRequest(t, ctx, "/apis/savechannel", cookie, "not-a-revision", map[string]interface{}{
+		"id": channelID, "name": "Malformed",
+	})
+	assert.Equal(t, float64(0), resp["code"])
+}
+
+// TestProgramRevisionConflict 測試節目修改會遞增頻道修訂版本並檢查 If-Match
+func TestProgramRevisionConflict(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+
+	cookie := getAuthCookie(t, ctx, "progreviser", "progreviser@example.com", "testpass123")
+	channelID := createChannel(t, ctx, cookie, "Programs")
+	etag := channelETag(t, ctx, cookie, channelID)
+
+	first := addProgram(t, ctx, cookie, channelID, "First", "dQw4w9WgXcQ", 60)
+	second := addProgram(t, ctx, cookie, channelID, "Second", "9bZkp7q19f0", 60)
+	assert.NotEqual(t, etag, channelETag(t, ctx, cookie, channelID))
+
+	resp, _ := doIfMatchRequest(t, ctx, "/apis/prog/saveorder", cookie, etag, map[string]interface{}{
+		"ch": channelID, "order": []int{second, first},
+	})
+	assert.Equal(t, float64(3), resp["code"])
+
+	resp, _ = doIfMatchRequest(t, ctx, "/apis/saveprog", cookie, etag, map[string]interface{}{
+		"ch": channelID, "prog_id": first, "name": "Renamed",
+	})
+	assert.Equal(t, float64(3), resp["code"])
+
+	etag = channelETag(t, ctx, cookie, channelID)
+	resp, etag = doIfMatchRequest(t, ctx, "/apis/prog/saveorder", cookie, etag, map[string]interface{}{
+		"ch": channelID, "order": []int{second, first},
+	})
+	require.Equal(t, float64(0), resp["state"])
+
+	resp, _ = doIfMatchRequest(t, ctx, "/apis/delprog", cookie, etag, map[string]interface{}{
+		"ch": channelID, "ids": []int{first},
+	})
+	require.Equal(t, float64(0), resp["state"])
+	resp, _ = doIfMatchRequest(t, ctx, "/apis/delprog", cookie, etag, map[string]interface{}{
+		"ch": channelID, "ids": []int{second},
+	})
+	assert.Equal(t, float64(3), resp["code"])
+}
+
+// channelRevision 輔助函數：取得頻道目前的修訂版本與節目數
+func channelRevision(t *testing.T, ctx *TestDBContext, cookie, channelID string) (float64, int) {
+	resp := doJSONRequest(t, ctx, "GET", "/apis/getchannel/"+channelID, cookie, nil)
+	require.Equal(t, float64(0), resp["state"])
+	channel := responseData(t, resp)["channel"].(map[string]interface{})
+	contents, _ := channel["contents"].([]interface{})
+	return channel["revision"].(float64), len(contents)
+}
+
+// TestRevisionClaimedWithMutation 測試指定版本的修改只遞增一次修訂版本，修改失敗時不遞增
+func TestRevisionClaimedWithMutation(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+
+	cookie := getAuthCookie(t, ctx, "claimer", "claimer@example.com", "testpass123")
+	channelID := createChannel(t, ctx, cookie, "Claimed")
+	revision, _ := channelRevision(t, ctx, cookie, channelID)
+
+	resp := doJSONRequest(t, ctx, "POST", "/apis/savechannel", cookie, map[string]interface{}{
+		"id": channelID, "name": "Edited", "revision": revision,
+	})
+	require.Equal(t, float64(0), resp["state"])
+	edited, _ := channelRevision(t, ctx, cookie, channelID)
+	assert.Equal(t, revision+1, edited)
+
+	// 修改失敗時不遞增修訂版本
+	program := addProgram(t, ctx, cookie, channelID, "Program", "dQw4w9WgXcQ", 60)
+	revision, _ = channelRevision(t, ctx, cookie, channelID)
+	resp = doJSONRequest(t, ctx, "POST", "/apis/saveprog", cookie, map[string]interface{}{
+		"ch": channelID, "prog_id": program, "name": "Renamed", "type": "unknown", "video_id": "abc", "revision": revision,
+	})
+	assert.Equal(t, float64(1), resp["state"])
+	unchanged, _ := channelRevision(t, ctx, cookie, channelID)
+	assert.Equal(t, revision, unchanged)
+
+	// 移動節目時也檢查目標頻道的修訂版本
+	targetID := createChannel(t, ctx, cookie, "Target")
+	targetRevision, _ := channelRevision(t, ctx, cookie, targetID)
+	addProgram(t, ctx, cookie, targetID, "Existing", "9bZkp7q19f0", 60)
+	resp = doJSONRequest(t, ctx, "POST", "/apis/progmoveto", cookie, map[string]interface{}{
+		"ch": channelID, "target": targetID, "ids": []int{program}, "revision": revision, "target_revision": targetRevision,
+	})
+	assert.Equal(t, float64(3), resp["code"])
+	_, sourcePrograms := channelRevision(t, ctx, cookie, channelID)
+	assert.Equal(t, 1, sourcePrograms)
+	unchanged, _ = channelRevision(t, ctx, cookie, channelID)
+	assert.Equal(t, revision, unchanged)
+
+	targetRevision, _ = channelRevision(t, ctx, cookie, targetID)
+	resp = doJSONRequest(t, ctx, "POST", "/apis/progmoveto", cookie, map[string]interface{}{
+		"ch": channelID, "target": targetID, "ids": []int{program}, "revision": revision, "target_revision": targetRevision,
+	})
+	require.Equal(t, float64(0), resp["state"])
+	moved, targetPrograms := channelRevision(t, ctx, cookie, targetID)
+	assert.Equal(t, 2, targetPrograms)
+	assert.Equal(t, targetRevision+1, moved)
+}
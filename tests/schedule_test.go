@@ -0,0 +1,154 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupScheduleChannel 建立含三個節目（60s、0s、120s）的頻道，並將播出起點設為 epoch
+func setupScheduleChannel(t *testing.T, ctx *TestDBContext, cookie string, epoch time.Time) (string, []int) {
+	channelID := createChannel(t, ctx, cookie, "Schedule Channel")
+	ids := []int{
+		addProgram(t, ctx, cookie, channelID, "One", "dQw4w9WgXcQ", 60),
+		addProgram(t, ctx, cookie, channelID, "Zero", "9bZkp7q19f0", 0),
+		addProgram(t, ctx, cookie, channelID, "Two", "kJQP7kiw5Fk", 120),
+	}
+
+	resp := doJSONRequest(t, ctx, "POST", "/apis/prog/saveorder", cookie, map[string]interface{}{
+		"ch":    channelID,
+		"order": ids,
+	})
+	require.Equal(t, float64(0), resp["state"])
+
+	resp = doJSONRequest(t, ctx, "POST", "/apis/channel/"+channelID+"/schedule", cookie, map[string]interface{}{
+		"epoch": epoch.Format(time.RFC3339),
+		"loop":  "repeat",
+	})
+	require.Equal(t, float64(0), resp["state"])
+	return channelID, ids
+}
+
+// TestChannelNow 測試取得頻道目前播出的節目
+func TestChannelNow(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+
+	cookie := getAuthCookie(t, ctx, "scheduser", "sched@example.com", "testpass123")
+	epoch := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	channelID, ids := setupScheduleChannel(t, ctx, cookie, epoch)
+
+	// 第 70 秒：第二個可播出的節目（長度 0 的節目被略過）
+	at := epoch.Add(70 * time.Second).Unix()
+	resp := doJSONRequest(t, ctx, "GET", fmt.Sprintf("/apis/channel/%s/now?at=%d", channelID, at), "", nil)
+	require.Equal(t, float64(0), resp["state"])
+	data := responseData(t, resp)
+
+	airing := data["airing"].(map[string]interface{})
+	program := airing["program"].(map[string]interface{})
+	assert.Equal(t, float64(ids[2]), program["_id"])
+	assert.Equal(t, float64(10), airing["offset"])
+	assert.Equal(t, float64(110), airing["remaining"])
+	next := airing["next"].(map[string]interface{})
+	assert.Equal(t, float64(ids[0]), next["program"].(map[string]interface{})["_id"])
+
+	info := data["schedule"].(map[string]interface{})
+	assert.Equal(t, float64(180), info["cycle"])
+	assert.Equal(t, []interface{}{float64(ids[1])}, info["skipped"])
+
+	// 開播前沒有節目
+	before := epoch.Add(-time.Minute).Unix()
+	resp = doJSONRequest(t, ctx, "GET", fmt.Sprintf("/apis/channel/%s/now?at=%d", channelID, before), "", nil)
+	require.Equal(t, float64(0), resp["state"])
+	assert.Nil(t, responseData(t, resp)["airing"])
+
+	// 頻道不存在
+	resp = doJSONRequest(t, ctx, "GET", "/apis/channel/not-exist/now", "", nil)
+	assert.Equal(t, float64(1), resp["state"])
+	assert.Equal(t, float64(2), resp["code"])
+}
+
+// TestChannelSchedule 測試取得頻道節目表
+func TestChannelSchedule(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+
+	cookie := getAuthCookie(t, ctx, "scheduser", "sched@example.com", "testpass123")
+	epoch := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	channelID, ids := setupScheduleChannel(t, ctx, cookie, epoch)
+
+	from := epoch.Add(30 * time.Second).Format(time.RFC3339)
+	to := epoch.Add(400 * time.Second).Format(time.RFC3339)
+	resp := doJSONRequest(t, ctx, "GET", fmt.Sprintf("/apis/channel/%s/schedule?from=%s&to=%s", channelID, from, to), "", nil)
+	require.Equal(t, float64(0), resp["state"])
+
+	slots := responseData(t, resp)["slots"].([]interface{})
+	require.Len(t, slots, 5)
+	expected := []int{ids[0], ids[2], ids[0], ids[2], ids[0]}
+	for i, s := range slots {
+		slot := s.(map[string]interface{})
+		assert.Equal(t, float64(expected[i]), slot["program"].(map[string]interface{})["_id"])
+	}
+	assert.Equal(t, epoch.Format(time.RFC3339), slots[0].(map[string]interface{})["start"])
+
+	// 參數錯誤
+	resp = doJSONRequest(t, ctx, "GET", fmt.Sprintf("/apis/channel/%s/schedule?from=bad", channelID), "", nil)
+	assert.Equal(t, float64(1), resp["state"])
+	assert.Equal(t, float64(0), resp["code"])
+}
+
+// TestSaveChannelSchedule_AccessDenied 測試非管理員無法設定播出
+func TestSaveChannelSchedule_AccessDenied(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+
+	owner := getAuthCookie(t, ctx, "scheduser", "sched@example.com", "testpass123")
+	other := getAuthCookie(t, ctx, "otheruser", "other@example.com", "testpass123")
+	channelID := createChannel(t, ctx, owner, "Schedule Channel")
+
+	resp := doJSONRequest(t, ctx, "POST", "/apis/channel/"+channelID+"/schedule", other, map[string]interface{}{
+		"loop": "once",
+	})
+	assert.Equal(t, float64(1), resp["state"])
+	assert.Equal(t, float64(2), resp["code"])
+
+	resp = doJSONRequest(t, ctx, "POST", "/apis/channel/"+channelID+"/schedule", owner, map[string]interface{}{
+		"loop": "sometimes",
+	})
+	assert.Equal(t, float64(1), resp["state"])
+	assert.Equal(t, float64(0), resp["code"])
+}
+
+// TestSaveProgramOrder_KeepsAiringProgram 測試重新排序後目前播出的節目不變
+func TestSaveProgramOrder_KeepsAiringProgram(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+
+	cookie := getAuthCookie(t, ctx, "scheduser", "sched@example.com", "testpass123")
+	// 起點設在 70 秒前，目前正播出第三個節目的第 10 秒左右
+	epoch := time.Now().Add(-70 * time.Second).Truncate(time.Second)
+	channelID, ids := setupScheduleChannel(t, ctx, cookie, epoch)
+
+	nowPlaying := func() (float64, float64) {
+		resp := doJSONRequest(t, ctx, "GET", "/apis/channel/"+channelID+"/now", "", nil)
+		require.Equal(t, float64(0), resp["state"])
+		airing := responseData(t, resp)["airing"].(map[string]interface{})
+		return airing["program"].(map[string]interface{})["_id"].(float64), airing["offset"].(float64)
+	}
+
+	beforeID, beforeOffset := nowPlaying()
+	require.Equal(t, float64(ids[2]), beforeID)
+
+	resp := doJSONRequest(t, ctx, "POST", "/apis/prog/saveorder", cookie, map[string]interface{}{
+		"ch":    channelID,
+		"order": []int{ids[2], ids[1], ids[0]},
+	})
+	require.Equal(t, float64(0), resp["state"])
+
+	afterID, afterOffset := nowPlaying()
+	assert.Equal(t, beforeID, afterID)
+	assert.InDelta(t, beforeOffset, afterOffset, 2)
+}
@@ -0,0 +1,117 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// search 輔助函數：全文搜尋並回傳頻道與節目結果
+func search(t *testing.T, ctx *TestDBContext, cookie, query string) ([]interface{}, []interface{}) {
+	resp := doJSONRequest(t, ctx, "GET", "/apis/search?"+query, cookie, nil)
+	require.Equal(t, float64(0), resp["state"])
+	data := responseData(t, resp)
+	return data["channels"].([]interface{}), data["programs"].([]interface{})
+}
+
+// resultNames 輔助函數：取得搜尋結果的名稱列表
+func resultNames(results []interface{}, field string) []string {
+	names := make([]string, 0, len(results))
+	for _, result := range results {
+		item := result.(map[string]interface{})
+		if field != "" {
+			item = item[field].(map[string]interface{})
+		}
+		names = append(names, item["name"].(string))
+	}
+	return names
+}
+
+// TestSearch 測試全文搜尋頻道與節目、排序與分頁
+func TestSearch(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+
+	cookie := getAuthCookie(t, ctx, "searcher", "searcher@example.com", "testpass123")
+	createChannel(t, ctx, cookie, "Midnight Jazz Lounge")
+	mixID := createChannel(t, ctx, cookie, "Evening Mix")
+	resp := doJSONRequest(t, ctx, "POST", "/apis/savechannel", cookie, map[string]interface{}{
+		"id": mixID, "name": "Evening Mix", "desc": "Soft jazz and soul",
+	})
+	require.Equal(t, float64(0), resp["state"])
+	cookingID := createChannel(t, ctx, cookie, "Cooking Show")
+	addProgram(t, ctx, cookie, cookingID, "Smooth Jazz Piano", "dQw4w9WgXcQ", 60)
+	addProgram(t, ctx, cookie, cookingID, "Pasta Basics", "9bZkp7q19f0", 60)
+
+	// 名稱符合的頻道排在描述符合的頻道之前
+	channels, programs := search(t, ctx, "", "q=jazz")
+	assert.Equal(t, []string{"Midnight Jazz Lounge", "Evening Mix"}, resultNames(channels, ""))
+	require.Len(t, programs, 1)
+	program := programs[0].(map[string]interface{})
+	assert.Equal(t, "Smooth Jazz Piano", program["program"].(map[string]interface{})["name"])
+	assert.Equal(t, cookingID, program["channel_id"])
+	assert.Equal(t, "Cooking Show", program["channel_name"])
+
+	// 多個搜尋詞需全部符合（不分大小寫）
+	channels, _ = search(t, ctx, "", "q=JAZZ+lounge")
+	assert.Equal(t, []string{"Midnight Jazz Lounge"}, resultNames(channels, ""))
+
+	// 分頁與搜尋類型
+	channels, programs = search(t, ctx, "", "q=jazz&type=channels&start=1&limit=1")
+	assert.Equal(t, []string{"Evening Mix"}, resultNames(channels, ""))
+	assert.Empty(t, programs)
+	channels, programs = search(t, ctx, "", "q=pasta&type=programs")
+	assert.Empty(t, channels)
+	assert.Equal(t, []string{"Pasta Basics"}, resultNames(programs, "program"))
+
+	// 修改名稱後可以搜尋到新名稱
+	resp = doJSONRequest(t, ctx, "POST", "/apis/savechannel", cookie, map[string]interface{}{
+		"id": cookingID, "name": "Kitchen Stories",
+	})
+	require.Equal(t, float64(0), resp["state"])
+	channels, _ = search(t, ctx, "", "q=kitchen")
+	assert.Equal(t, []string{"Kitchen Stories"}, resultNames(channels, ""))
+	channels, _ = search(t, ctx, "", "q=cooking")
+	assert.Empty(t, channels)
+
+	// 缺少搜尋字串或搜尋類型錯誤
+	resp = doJSONRequest(t, ctx, "GET", "/apis/search?q=+", "", nil)
+	assert.Equal(t, float64(0), resp["code"])
+	resp = doJSONRequest(t, ctx, "GET", "/apis/search?q=jazz&type=users", "", nil)
+	assert.Equal(t, float64(0), resp["code"])
+}
+
+// TestSearchVisibility 測試搜尋結果只包含可讀取的頻道
+func TestSearchVisibility(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+
+	owner := getAuthCookie(t, ctx, "keeper", "keeper@example.com", "testpass123")
+	other := getAuthCookie(t, ctx, "stranger", "stranger@example.com", "testpass123")
+	createChannel(t, ctx, owner, "Public Vault")
+	privateID := createChannel(t, ctx, owner, "Private Vault")
+	addProgram(t, ctx, owner, privateID, "Vault Secrets", "dQw4w9WgXcQ", 60)
+	unlistedID := createChannel(t, ctx, owner, "Unlisted Vault")
+	for id, channel := range map[string][]string{privateID: {"Private Vault", "private"}, unlistedID: {"Unlisted Vault", "unlisted"}} {
+		resp := doJSONRequest(t, ctx, "POST", "/apis/savechannel", owner, map[string]interface{}{
+			"id": id, "name": channel[0], "visibility": channel[1],
+		})
+		require.Equal(t, float64(0), resp["state"])
+	}
+	trashedID := createChannel(t, ctx, owner, "Trashed Vault")
+	resp := doJSONRequest(t, ctx, "POST", "/apis/channel/"+trashedID+"/delete", owner, nil)
+	require.Equal(t, float64(0), resp["state"])
+
+	// 未登入與其他使用者只能搜尋到公開頻道
+	for _, cookie := range []string{"", other} {
+		channels, programs := search(t, ctx, cookie, "q=vault")
+		assert.Equal(t, []string{"Public Vault"}, resultNames(channels, ""))
+		assert.Empty(t, programs)
+	}
+
+	// 擁有者可以搜尋到自己的不公開與私人頻道（不含垃圾桶中的頻道）
+	channels, programs := search(t, ctx, owner, "q=vault")
+	assert.ElementsMatch(t, []string{"Public Vault", "Private Vault", "Unlisted Vault"}, resultNames(channels, ""))
+	assert.Equal(t, []string{"Vault Secrets"}, resultNames(programs, "program"))
+}
@@ -0,0 +1,119 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/higgstv/higgstv-go/internal/repository"
+	"github.com/higgstv/higgstv-go/internal/service"
+)
+
+// signIn 輔助函數：登入並回傳 Cookie（模擬另一個裝置）
+func signIn(t *testing.T, ctx *TestDBContext, username, password string) string {
+	body, _ := json.Marshal(map[string]interface{}{"username": username, "password": password})
+	w := doRawRequest(t, ctx, "POST", "/apis/signin", "", "application/json", body)
+	cookie := w.Header().Get("Set-Cookie")
+	require.NotEmpty(t, cookie)
+	return cookie
+}
+
+// assertLoggedIn 確認 Cookie 是否仍為有效的登入狀態
+func assertLoggedIn(t *testing.T, ctx *TestDBContext, cookie string, expected bool) {
+	resp := doJSONRequest(t, ctx, "GET", "/apis/sessions", cookie, nil)
+	if expected {
+		assert.Equal(t, float64(0), resp["state"])
+	} else {
+		assert.Equal(t, float64(1), resp["state"])
+		assert.Equal(t, float64(1), resp["code"])
+	}
+}
+
+// listSessions 輔助函數：列出目前使用者的 Session
+func listSessions(t *testing.T, ctx *TestDBContext, cookie string) []interface{} {
+	resp := doJSONRequest(t, ctx, "GET", "/apis/sessions", cookie, nil)
+	require.Equal(t, float64(0), resp["state"])
+	return responseData(t, resp)["sessions"].([]interface{})
+}
+
+// TestSessionListAndRevoke 測試列出與撤銷登入裝置
+func TestSessionListAndRevoke(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+
+	first := getAuthCookie(t, ctx, "sessionuser", "session@example.com", "testpass123")
+	second := signIn(t, ctx, "sessionuser", "testpass123")
+	other := getAuthCookie(t, ctx, "sessionother", "sessionother@example.com", "testpass123")
+
+	sessions := listSessions(t, ctx, first)
+	require.Len(t, sessions, 2)
+	var secondID string
+	currentCount := 0
+	for _, item := range sessions {
+		s := item.(map[string]interface{})
+		if s["current"] == true {
+			currentCount++
+		} else {
+			secondID = s["id"].(string)
+		}
+	}
+	assert.Equal(t, 1, currentCount)
+	require.NotEmpty(t, secondID)
+
+	// 其他使用者不能撤銷
+	resp := doJSONRequest(t, ctx, "POST", "/apis/sessions/revoke", other, map[string]interface{}{"id": secondID})
+	assert.Equal(t, float64(1), resp["state"])
+	assert.Equal(t, float64(0), resp["code"])
+	assertLoggedIn(t, ctx, second, true)
+
+	resp = doJSONRequest(t, ctx, "POST", "/apis/sessions/revoke", first, map[string]interface{}{"id": secondID})
+	require.Equal(t, float64(0), resp["state"])
+	assertLoggedIn(t, ctx, second, false)
+	assertLoggedIn(t, ctx, first, true)
+
+	// 撤銷其他所有裝置
+	third := signIn(t, ctx, "sessionuser", "testpass123")
+	fourth := signIn(t, ctx, "sessionuser", "testpass123")
+	resp = doJSONRequest(t, ctx, "POST", "/apis/sessions/revoke", third, map[string]interface{}{"all": true})
+	require.Equal(t, float64(0), resp["state"])
+	assertLoggedIn(t, ctx, first, false)
+	assertLoggedIn(t, ctx, fourth, false)
+	assertLoggedIn(t, ctx, third, true)
+	assert.Len(t, listSessions(t, ctx, third), 1)
+
+	// 登出後原本的 Cookie 不再有效
+	w := doRawRequest(t, ctx, "GET", "/apis/signout", third, "", nil)
+	require.Equal(t, 200, w.Code)
+	assertLoggedIn(t, ctx, third, false)
+	assertLoggedIn(t, ctx, other, true)
+}
+
+// TestPasswordChangeRevokesSessions 測試變更與重設密碼時撤銷其他 Session
+func TestPasswordChangeRevokesSessions(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+
+	current := getAuthCookie(t, ctx, "pwuser", "pw@example.com", "testpass123")
+	other := signIn(t, ctx, "pwuser", "testpass123")
+
+	resp := doJSONRequest(t, ctx, "POST", "/apis/change_password", current, map[string]interface{}{
+		"password":     "testpass123",
+		"new_password": "newpass456",
+	})
+	require.Equal(t, true, resp["ret"])
+	assertLoggedIn(t, ctx, other, false)
+	assertLoggedIn(t, ctx, current, true)
+
+	accessKey, err := service.NewAuthService(repository.NewUserRepository(ctx.DB)).GenerateAccessKey(context.Background(), "pw@example.com")
+	require.NoError(t, err)
+	resp = doJSONRequest(t, ctx, "POST", "/apis/reset_password", "", map[string]interface{}{
+		"email":      "pw@example.com",
+		"access_key": accessKey,
+		"password":   "resetpass789",
+	})
+	require.Equal(t, true, resp["ret"])
+	assertLoggedIn(t, ctx, current, false)
+}
@@ -0,0 +1,129 @@
+package tests
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createTag 輔助函數：以網站管理員建立標籤並回傳標籤 ID
+func createTag(t *testing.T, ctx *TestDBContext, cookie string, payload map[string]interface{}) int {
+	resp := doJSONRequest(t, ctx, "POST", "/apis/admin/tags", cookie, payload)
+	require.Equal(t, float64(0), resp["state"], "create tag failed: %v", resp)
+	return int(responseData(t, resp)["tag"].(map[string]interface{})["_id"].(float64))
+}
+
+// TestTagRegistry 測試標籤的建立、列出、更新與刪除
+func TestTagRegistry(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+	ctx.Config.Admin.Users = []string{"tagadmin"}
+
+	adminCookie := getAuthCookie(t, ctx, "tagadmin", "tagadmin@example.com", "testpass123")
+	userCookie := getAuthCookie(t, ctx, "taguser", "taguser@example.com", "testpass123")
+
+	// 可指定既有客戶端使用的 ID，未指定時自動產生
+	music := createTag(t, ctx, adminCookie, map[string]interface{}{
+		"id": 3, "slug": "music", "labels": map[string]string{"zh-TW": "音樂", "en": "Music"},
+	})
+	assert.Equal(t, 3, music)
+	jazz := createTag(t, ctx, adminCookie, map[string]interface{}{"slug": "jazz", "parent": music})
+	assert.Equal(t, 4, jazz)
+
+	// 重複、格式錯誤與不存在的上層標籤
+	for _, payload := range []map[string]interface{}{
+		{"slug": "music"},
+		{"id": 3, "slug": "other"},
+		{"slug": "Not A Slug"},
+		{"slug": "orphan", "parent": 99},
+	} {
+		resp := doJSONRequest(t, ctx, "POST", "/apis/admin/tags", adminCookie, payload)
+		assert.Equal(t, float64(0), resp["code"], "payload %v", payload)
+	}
+
+	// 一般使用者不能管理標籤
+	resp := doJSONRequest(t, ctx, "POST", "/apis/admin/tags", userCookie, map[string]interface{}{"slug": "sneaky"})
+	assert.Equal(t, float64(2), resp["code"])
+
+	resp = doJSONRequest(t, ctx, "GET", "/apis/tags", "", nil)
+	require.Equal(t, float64(0), resp["state"])
+	tags := responseData(t, resp)["tags"].([]interface{})
+	require.Len(t, tags, 2)
+	first := tags[0].(map[string]interface{})
+	assert.Equal(t, "music", first["slug"])
+	assert.Equal(t, "音樂", first["labels"].(map[string]interface{})["zh-TW"])
+	assert.Equal(t, float64(music), tags[1].(map[string]interface{})["parent"])
+
+	// 不能形成循環，仍有下層標籤時不能刪除
+	resp = doJSONRequest(t, ctx, "POST", "/apis/admin/tags/"+strconv.Itoa(music), adminCookie, map[string]interface{}{"parent": jazz})
+	assert.Equal(t, float64(0), resp["code"])
+	resp = doJSONRequest(t, ctx, "POST", "/apis/admin/tags/"+strconv.Itoa(music)+"/delete", adminCookie, nil)
+	assert.Equal(t, float64(0), resp["code"])
+
+	resp = doJSONRequest(t, ctx, "POST", "/apis/admin/tags/"+strconv.Itoa(jazz), adminCookie, map[string]interface{}{
+		"slug": "smooth-jazz", "parent": 0,
+	})
+	require.Equal(t, float64(0), resp["state"])
+	updated := responseData(t, resp)["tag"].(map[string]interface{})
+	assert.Equal(t, "smooth-jazz", updated["slug"])
+	assert.Nil(t, updated["parent"])
+
+	resp = doJSONRequest(t, ctx, "POST", "/apis/admin/tags/"+strconv.Itoa(music)+"/delete", adminCookie, nil)
+	require.Equal(t, float64(0), resp["state"])
+	resp = doJSONRequest(t, ctx, "GET", "/apis/tags", "", nil)
+	assert.Len(t, responseData(t, resp)["tags"], 1)
+}
+
+// TestTagValidationAndFilter 測試新增頻道與節目時檢查標籤，以及以標籤 slug 過濾頻道
+func TestTagValidationAndFilter(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+	ctx.Config.Admin.Users = []string{"tagfilteradmin"}
+
+	cookie := getAuthCookie(t, ctx, "tagfilteradmin", "tagfilteradmin@example.com", "testpass123")
+	music := createTag(t, ctx, cookie, map[string]interface{}{"slug": "music"})
+	jazz := createTag(t, ctx, cookie, map[string]interface{}{"slug": "jazz", "parent": music})
+	news := createTag(t, ctx, cookie, map[string]interface{}{"slug": "news"})
+
+	// 未登錄的標籤
+	resp := doJSONRequest(t, ctx, "POST", "/apis/addchannel", cookie, map[string]interface{}{
+		"name": "Unknown", "tags": []int{music, 99},
+	})
+	assert.Equal(t, float64(0), resp["code"])
+
+	resp = doJSONRequest(t, ctx, "POST", "/apis/addchannel", cookie, map[string]interface{}{
+		"name": "Jazz Channel", "tags": []int{jazz},
+	})
+	require.Equal(t, float64(0), resp["state"])
+	jazzChannel := responseData(t, resp)["channel"].(map[string]interface{})["_id"].(string)
+	resp = doJSONRequest(t, ctx, "POST", "/apis/addchannel", cookie, map[string]interface{}{
+		"name": "News Channel", "tags": []int{news},
+	})
+	require.Equal(t, float64(0), resp["state"])
+
+	resp = doJSONRequest(t, ctx, "POST", "/apis/addprog", cookie, map[string]interface{}{
+		"ch": jazzChannel, "name": "Tagged", "youtube_id": "dQw4w9WgXcQ", "duration": 60, "tags": []int{99},
+	})
+	assert.Equal(t, float64(0), resp["code"])
+	resp = doJSONRequest(t, ctx, "POST", "/apis/addprog", cookie, map[string]interface{}{
+		"ch": jazzChannel, "name": "Tagged", "youtube_id": "dQw4w9WgXcQ", "duration": 60, "tags": []int{jazz},
+	})
+	require.Equal(t, float64(0), resp["state"])
+
+	// 上層標籤包含下層標籤的頻道
+	channelNames := func(slug string) []interface{} {
+		resp := doJSONRequest(t, ctx, "GET", "/apis/getchannels?tag="+slug, "", nil)
+		require.Equal(t, float64(0), resp["state"])
+		names := []interface{}{}
+		for _, item := range responseData(t, resp)["channels"].([]interface{}) {
+			names = append(names, item.(map[string]interface{})["name"])
+		}
+		return names
+	}
+	assert.Equal(t, []interface{}{"Jazz Channel"}, channelNames("music"))
+	assert.Equal(t, []interface{}{"Jazz Channel"}, channelNames("jazz"))
+	assert.Equal(t, []interface{}{"News Channel"}, channelNames("news"))
+	assert.Empty(t, channelNames("missing"))
+}
@@ -20,7 +20,11 @@ import (
 	"github.com/higgstv/higgstv-go/internal/config"
 	"github.com/higgstv/higgstv-go/internal/database"
 	"github.com/higgstv/higgstv-go/internal/migration"
+	"github.com/higgstv/higgstv-go/internal/playlist"
+	"github.com/higgstv/higgstv-go/internal/repository"
+	"github.com/higgstv/higgstv-go/internal/service"
 	"github.com/higgstv/higgstv-go/pkg/logger"
+	"github.com/higgstv/higgstv-go/pkg/metadata"
 	"github.com/higgstv/higgstv-go/pkg/session"
 )
 
@@ -28,6 +32,7 @@ import (
 type TestDBContext struct {
 	DB     database.Database
 	Router *gin.Engine
+	Config *config.Config
 }
 
 // sanitizeTestName 清理測試名稱（移除不適合作為資料庫名稱的字元）
@@ -92,6 +97,8 @@ func SetupTestDB(t *testing.T) *TestDBContext {
 	}
 
 	session.Init(cfg.Session.Secret)
+	session.SetStore(repository.NewSessionStore(db))
+	session.SetTokenAuthenticator(service.NewAPITokenService(repository.NewAPITokenRepository(db), repository.NewUserRepository(db)))
 
 	// 初始化 Logger（與 main.go 保持一致）
 	if err := logger.Init(cfg.Server.Env); err != nil {
@@ -99,18 +106,23 @@ func SetupTestDB(t *testing.T) *TestDBContext {
 		// 不中斷測試，但記錄警告
 	}
 
+	// 影片資訊查詢（預設停用，避免測試連到外部服務）
+	metadata.Default = nil
+
+	// 還原預設的播放清單解析器（個別測試可改用本機服務）
+	playlist.InitResolver(playlist.ResolverConfig{})
+
+	// 執行資料庫遷移（與設定 database.auto_migrate 的 main.go 保持一致）
+	if err := migration.RunMigrationsWithTimeout(db); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+
 	// 確保資料庫索引已建立（與 main.go 保持一致）
 	if err := database.EnsureIndexesWithTimeout(db); err != nil {
 		t.Logf("Warning: Failed to ensure database indexes: %v", err)
 		// 不中斷測試，但記錄警告
 	}
 
-	// 執行資料庫遷移（與 main.go 保持一致）
-	if err := migration.RunMigrationsWithTimeout(db); err != nil {
-		t.Logf("Warning: Failed to run migrations: %v", err)
-		// 不中斷測試，但記錄警告
-	}
-
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
 
@@ -124,6 +136,7 @@ func SetupTestDB(t *testing.T) *TestDBContext {
 	return &TestDBContext{
 		DB:     db,
 		Router: router,
+		Config: cfg,
 	}
 }
 
@@ -147,6 +160,8 @@ func cleanupDatabase(ctx context.Context, db database.Database) error {
 		return cleanupMongoDB(ctx, db)
 	case database.DatabaseTypeSQLite:
 		return cleanupSQLite(ctx, db)
+	case database.DatabaseTypePostgres:
+		return cleanupPostgres(ctx, db)
 	default:
 		return nil
 	}
@@ -194,7 +209,7 @@ func cleanupSQLite(ctx context.Context, db database.Database) error {
 	tables := []string{
 		"users", "channels", "programs", "counters", "migrations",
 		"user_channels", "channel_tags", "channel_owners", "channel_permissions",
-		"program_tags", "channel_program_order",
+		"program_tags", "channel_program_order", "invitations", "sessions", "api_tokens", "channel_shares", "channel_revisions", "tags",
 	}
 	
 	for _, table := range tables {
@@ -207,6 +222,15 @@ func cleanupSQLite(ctx context.Context, db database.Database) error {
 	return nil
 }
 
+// cleanupPostgres 清理 PostgreSQL 資料庫（每個測試使用獨立的 schema，只清除資料）
+func cleanupPostgres(ctx context.Context, db database.Database) error {
+	sqlDB := db.(*database.PostgresDatabase).GetDB()
+	_, err := sqlDB.ExecContext(ctx, `TRUNCATE users, channels, programs, counters, migrations,
+		user_channels, channel_tags, channel_owners, channel_permissions,
+		program_tags, channel_program_order, invitations, sessions, api_tokens, channel_shares, channel_revisions, tags CASCADE`)
+	return err
+}
+
 // getAuthCookie 輔助函數：註冊並登入，返回 Cookie（使用 TestDBContext）
 func getAuthCookie(t *testing.T, ctx *TestDBContext, username, email, password string) string {
 	// 註冊
@@ -228,3 +252,59 @@ func getAuthCookie(t *testing.T, ctx *TestDBContext, username, email, password s
 	require.NotEmpty(t, cookies)
 	return cookies
 }
+
+// doJSONRequest 輔助函數：送出 JSON 請求並解析回應
+func doJSONRequest(t *testing.T, ctx *TestDBContext, method, path, cookie string, payload interface{}) map[string]interface{} {
+	var body *bytes.Buffer
+	if payload != nil {
+		jsonData, err := json.Marshal(payload)
+		require.NoError(t, err)
+		body = bytes.NewBuffer(jsonData)
+	} else {
+		body = bytes.NewBuffer(nil)
+	}
+	req, _ := http.NewRequest(method, path, body)
+	req.Header.Set("Content-Type", "application/json")
+	if cookie != "" {
+		req.Header.Set("Cookie", cookie)
+	}
+	w := httptest.NewRecorder()
+	ctx.Router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp
+}
+
+// responseData 輔助函數：取得回應的 Data 欄位
+func responseData(t *testing.T, resp map[string]interface{}) map[string]interface{} {
+	data, ok := resp["Data"].(map[string]interface{})
+	require.True(t, ok, "response should have 'Data' field: %v", resp)
+	return data
+}
+
+// createChannel 輔助函數：建立頻道並回傳頻道 ID
+func createChannel(t *testing.T, ctx *TestDBContext, cookie, name string) string {
+	resp := doJSONRequest(t, ctx, "POST", "/apis/addchannel", cookie, map[string]interface{}{
+		"name": name,
+		"tags": []int{},
+	})
+	require.Equal(t, float64(0), resp["state"])
+	channel := responseData(t, resp)["channel"].(map[string]interface{})
+	return channel["_id"].(string)
+}
+
+// addProgram 輔助函數：新增節目並回傳節目 ID
+func addProgram(t *testing.T, ctx *TestDBContext, cookie, channelID, name, youtubeID string, duration int) int {
+	resp := doJSONRequest(t, ctx, "POST", "/apis/addprog", cookie, map[string]interface{}{
+		"ch":         channelID,
+		"name":       name,
+		"youtube_id": youtubeID,
+		"duration":   duration,
+		"tags":       []int{},
+	})
+	require.Equal(t, float64(0), resp["state"])
+	program := responseData(t, resp)["program"].(map[string]interface{})
+	return int(program["_id"].(float64))
+}
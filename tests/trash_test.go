@@ -0,0 +1,149 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/higgstv/higgstv-go/internal/database"
+	"github.com/higgstv/higgstv-go/internal/repository"
+	"github.com/higgstv/higgstv-go/internal/service"
+)
+
+// ownChannelIDs 輔助函數：取得自己的頻道 ID 列表
+func ownChannelIDs(t *testing.T, ctx *TestDBContext, cookie string) []string {
+	resp := doJSONRequest(t, ctx, "GET", "/apis/getownchannels", cookie, nil)
+	require.Equal(t, float64(0), resp["state"])
+	ids := []string{}
+	for _, item := range responseData(t, resp)["channels"].([]interface{}) {
+		ids = append(ids, item.(map[string]interface{})["_id"].(string))
+	}
+	return ids
+}
+
+// TestChannelTrashAndRestore 測試刪除頻道移到垃圾桶並還原
+func TestChannelTrashAndRestore(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+
+	owner := getAuthCookie(t, ctx, "trashowner", "trashowner@example.com", "testpass123")
+	other := getAuthCookie(t, ctx, "trashother", "trashother@example.com", "testpass123")
+	channelID := createChannel(t, ctx, owner, "Old Channel")
+	addProgram(t, ctx, owner, channelID, "Kept", "dQw4w9WgXcQ", 60)
+
+	// 其他使用者不能刪除
+	resp := doJSONRequest(t, ctx, "POST", "/apis/channel/"+channelID+"/delete", other, nil)
+	assert.Equal(t, float64(2), resp["code"])
+
+	resp = doJSONRequest(t, ctx, "POST", "/apis/channel/"+channelID+"/delete", owner, nil)
+	require.Equal(t, float64(0), resp["state"])
+
+	// 刪除後不會出現在頻道列表，也無法讀取或編輯
+	assert.NotContains(t, ownChannelIDs(t, ctx, owner), channelID)
+	resp = doJSONRequest(t, ctx, "GET", "/apis/getchannel/"+channelID, owner, nil)
+	assert.Equal(t, float64(2), resp["code"])
+	resp = doJSONRequest(t, ctx, "POST", "/apis/addprog", owner, map[string]interface{}{
+		"ch": channelID, "name": "Deleted", "youtube_id": "dQw4w9WgXcQ",
+	})
+	assert.Equal(t, float64(2), resp["code"])
+	resp = doJSONRequest(t, ctx, "POST", "/apis/channel/"+channelID+"/delete", owner, nil)
+	assert.Equal(t, float64(2), resp["code"])
+
+	resp = doJSONRequest(t, ctx, "GET", "/apis/trash", owner, nil)
+	require.Equal(t, float64(0), resp["state"])
+	trash := responseData(t, resp)["channels"].([]interface{})
+	require.Len(t, trash, 1)
+	trashed := trash[0].(map[string]interface{})
+	assert.Equal(t, channelID, trashed["_id"])
+	assert.NotEmpty(t, trashed["deleted_at"])
+	assert.NotEmpty(t, trashed["purge_at"])
+
+	resp = doJSONRequest(t, ctx, "GET", "/apis/trash", other, nil)
+	require.Equal(t, float64(0), resp["state"])
+	assert.Empty(t, responseData(t, resp)["channels"])
+
+	// 其他使用者不能還原
+	resp = doJSONRequest(t, ctx, "POST", "/apis/channel/"+channelID+"/restore", other, nil)
+	assert.Equal(t, float64(2), resp["code"])
+
+	resp = doJSONRequest(t, ctx, "POST", "/apis/channel/"+channelID+"/restore", owner, nil)
+	require.Equal(t, float64(0), resp["state"])
+	assert.Contains(t, ownChannelIDs(t, ctx, owner), channelID)
+	resp = doJSONRequest(t, ctx, "GET", "/apis/getchannel/"+channelID, owner, nil)
+	require.Equal(t, float64(0), resp["state"])
+	channel := responseData(t, resp)["channel"].(map[string]interface{})
+	assert.Len(t, channel["contents"], 1)
+	assert.NotContains(t, channel, "deleted_at")
+
+	resp = doJSONRequest(t, ctx, "POST", "/apis/channel/"+channelID+"/restore", owner, nil)
+	assert.Equal(t, float64(2), resp["code"])
+}
+
+// TestUnclassifiedChannelCannotBeDeleted 測試未分類頻道不能刪除
+func TestUnclassifiedChannelCannotBeDeleted(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+
+	cookie := getAuthCookie(t, ctx, "trashunclassified", "trashunclassified@example.com", "testpass123")
+	resp := doJSONRequest(t, ctx, "GET", "/apis/getownchannels", cookie, nil)
+	require.Equal(t, float64(0), resp["state"])
+	unclassifiedID := ""
+	for _, item := range responseData(t, resp)["channels"].([]interface{}) {
+		if item.(map[string]interface{})["type"] == "unclassified" {
+			unclassifiedID = item.(map[string]interface{})["_id"].(string)
+		}
+	}
+	require.NotEmpty(t, unclassifiedID)
+
+	resp = doJSONRequest(t, ctx, "POST", "/apis/channel/"+unclassifiedID+"/delete", cookie, nil)
+	assert.Equal(t, float64(0), resp["code"])
+	assert.Contains(t, ownChannelIDs(t, ctx, cookie), unclassifiedID)
+}
+
+// TestTrashPurge 測試過期的頻道永久刪除（包含節目與擁有者關聯）
+func TestTrashPurge(t *testing.T) {
+	ctx := SetupTestDB(t)
+	defer CleanupTestDB(t, ctx)
+
+	cookie := getAuthCookie(t, ctx, "trashpurge", "trashpurge@example.com", "testpass123")
+	expiredID := createChannel(t, ctx, cookie, "Expired")
+	addProgram(t, ctx, cookie, expiredID, "Gone", "dQw4w9WgXcQ", 60)
+	recentID := createChannel(t, ctx, cookie, "Recent")
+
+	channelRepo := repository.NewChannelRepository(ctx.DB)
+	userRepo := repository.NewUserRepository(ctx.DB)
+	background := context.Background()
+	require.NoError(t, channelRepo.SoftDelete(background, expiredID, time.Now().Add(-48*time.Hour)))
+	resp := doJSONRequest(t, ctx, "POST", "/apis/channel/"+recentID+"/delete", cookie, nil)
+	require.Equal(t, float64(0), resp["state"])
+
+	channelService := service.NewChannelService(channelRepo, userRepo)
+	purged, err := channelService.PurgeExpired(background, time.Now().Add(-24*time.Hour), 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+
+	resp = doJSONRequest(t, ctx, "GET", "/apis/trash", cookie, nil)
+	require.Equal(t, float64(0), resp["state"])
+	trash := responseData(t, resp)["channels"].([]interface{})
+	require.Len(t, trash, 1)
+	assert.Equal(t, recentID, trash[0].(map[string]interface{})["_id"])
+
+	resp = doJSONRequest(t, ctx, "POST", "/apis/channel/"+expiredID+"/restore", cookie, nil)
+	assert.Equal(t, float64(2), resp["code"])
+
+	user, err := userRepo.FindByUsername(background, "trashpurge")
+	require.NoError(t, err)
+	assert.NotContains(t, user.OwnChannels, expiredID)
+	assert.Contains(t, user.OwnChannels, recentID)
+
+	if sqliteDB, ok := ctx.DB.(*database.SQLiteDatabase); ok {
+		for _, table := range []string{"programs", "channel_program_order", "channel_owners", "user_channels"} {
+			var count int
+			require.NoError(t, sqliteDB.GetDB().QueryRow("SELECT COUNT(*) FROM "+table+" WHERE channel_id = ?", expiredID).Scan(&count))
+			assert.Equal(t, 0, count, table)
+		}
+	}
+}